@@ -17,11 +17,13 @@ CERTDIR_TRUSTEDJWTCERTS=$CERTS_PATH/trustedjwt
 CERTDIR_TRUSTEDCAS=$CERTS_PATH/trustedca
 KEYS_PATH=$PRODUCT_HOME/keys
 KEYS_TRANSFER_POLICY_PATH=$PRODUCT_HOME/keys-transfer-policy
+KEY_TRANSFER_EVENTS_PATH=$PRODUCT_HOME/key-transfer-events
+KEY_STORE_PATH=$CONFIG_PATH/key-store
 SAML_CERTS_PATH=$CERTS_PATH/saml
 TPM_IDENTITY_CERTS_PATH=$CERTS_PATH/tpm-identity
 
 if [ ! -f $CONFIG_PATH/.setup_done ]; then
-  for directory in $PRODUCT_HOME $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $KEYS_PATH $KEYS_TRANSFER_POLICY_PATH $SAML_CERTS_PATH $TPM_IDENTITY_CERTS_PATH; do
+  for directory in $PRODUCT_HOME $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDJWTCERTS $CERTDIR_TRUSTEDCAS $KEYS_PATH $KEYS_TRANSFER_POLICY_PATH $KEY_TRANSFER_EVENTS_PATH $KEY_STORE_PATH $SAML_CERTS_PATH $TPM_IDENTITY_CERTS_PATH; do
     mkdir -p $directory
     if [ $? -ne 0 ]; then
       echo "Cannot create directory: $directory"
@@ -35,6 +37,19 @@ if [ ! -f $CONFIG_PATH/.setup_done ]; then
     exit 1
   fi
   touch $CONFIG_PATH/.setup_done
+else
+  # the directories added after the first setup are created on upgrade
+  for directory in $KEY_TRANSFER_EVENTS_PATH $KEY_STORE_PATH; do
+    if [ ! -d $directory ]; then
+      mkdir -p $directory
+      if [ $? -ne 0 ]; then
+        echo "Cannot create directory: $directory"
+        exit 1
+      fi
+      chown -R $USER_ID:$USER_ID $directory
+      chmod 700 $directory
+    fi
+  done
 fi
 
 if [ ! -z $SETUP_TASK ]; then
@@ -70,10 +70,11 @@ CERTDIR_TRUSTEDJWTCERTS=$CERTS_PATH/trustedjwt
 CERTDIR_TRUSTEDCAS=$CERTS_PATH/trustedca
 KEYS_PATH=$PRODUCT_HOME/keys
 KEYS_TRANSFER_POLICY_PATH=$PRODUCT_HOME/keys-transfer-policy
+KEY_TRANSFER_EVENTS_PATH=$PRODUCT_HOME/key-transfer-events
 SAML_CERTS_PATH=$CERTS_PATH/saml/
 TPM_IDENTITY_CERTS_PATH=$CERTS_PATH/tpm-identity/
 
-for directory in $BIN_PATH $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDCAS $CERTDIR_TRUSTEDJWTCERTS $KEYS_PATH $KEYS_TRANSFER_POLICY_PATH $SAML_CERTS_PATH $TPM_IDENTITY_CERTS_PATH; do
+for directory in $BIN_PATH $LOG_PATH $CONFIG_PATH $CERTS_PATH $CERTDIR_TRUSTEDCAS $CERTDIR_TRUSTEDJWTCERTS $KEYS_PATH $KEYS_TRANSFER_POLICY_PATH $KEY_TRANSFER_EVENTS_PATH $SAML_CERTS_PATH $TPM_IDENTITY_CERTS_PATH; do
     mkdir -p $directory
     if [ $? -ne 0 ]; then
         echo "Cannot create directory: $directory"
@@ -0,0 +1,130 @@
+/*
+ *  Copyright (C) 2022 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+package aas
+
+import "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+
+// LDAPGroupMappingCreateInfo request payload
+// swagger:parameters LDAPGroupMappingCreateInfo
+type LDAPGroupMappingCreateInfo struct {
+	// in:body
+	Body aas.LDAPGroupMappingCreate
+}
+
+// LDAPGroupMappingResponse response payload
+// swagger:parameters LDAPGroupMappingResponse
+type LDAPGroupMappingResponse struct {
+	// in:body
+	Body aas.LDAPGroupMappingInfo
+}
+
+// LDAPGroupMappingsResponse response payload
+// swagger:parameters LDAPGroupMappingsResponse
+type LDAPGroupMappingsResponse struct {
+	// in:body
+	Body aas.LDAPGroupMappingInfos
+}
+
+// swagger:operation POST /ldap-group-mappings LDAPGroupMappings createLDAPGroupMapping
+// ---
+//
+// description: |
+//   Maps an LDAP group to a role. The users authenticated against the LDAP directory are granted the roles
+//   mapped to the groups they are a member of when they request a token. The group is identified by its DN
+//   and is matched case insensitively. The role must exist in the Authservice database.
+//   A valid bearer token with the user_roles:create permission should be provided to authorize this REST call.
+//
+// security:
+//  - bearerAuth: []
+// consumes:
+//  - application/json
+// produces:
+//  - application/json
+// parameters:
+// - name: request body
+//   in: body
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/LDAPGroupMappingCreate"
+// responses:
+//   '201':
+//     description: Successfully mapped the LDAP group to the role.
+//     schema:
+//       "$ref": "#/definitions/LDAPGroupMappingInfo"
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/ldap-group-mappings
+// x-sample-call-input: |
+//    {
+//       "group": "cn=kbs-admins,ou=groups,dc=example,dc=com",
+//       "service": "KBS",
+//       "name": "KeyCRUD"
+//    }
+// x-sample-call-output: |
+//    {
+//       "mapping_id": "b2f3fa41-2f6e-4d5b-9a85-0c8b3b1f0a7e",
+//       "group": "cn=kbs-admins,ou=groups,dc=example,dc=com",
+//       "role_id": "75fa8fe0-f2e6-4c5b-a3b5-5c3e3b9e5f1a",
+//       "service": "KBS",
+//       "name": "KeyCRUD"
+//    }
+// ---
+
+// swagger:operation GET /ldap-group-mappings LDAPGroupMappings queryLDAPGroupMappings
+// ---
+// description: |
+//   Retrieves the mappings of LDAP groups to roles. A valid bearer token with the user_roles:search
+//   permission should be provided to authorize this REST call.
+//
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: group
+//   description: DN of the LDAP group.
+//   in: query
+//   type: string
+// responses:
+//   '200':
+//     description: Successfully retrieved the LDAP group mappings.
+//     schema:
+//       "$ref": "#/definitions/LDAPGroupMappingInfos"
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/ldap-group-mappings?group=cn%3Dkbs-admins%2Cou%3Dgroups%2Cdc%3Dexample%2Cdc%3Dcom
+// x-sample-call-output: |
+//    [
+//       {
+//          "mapping_id": "b2f3fa41-2f6e-4d5b-9a85-0c8b3b1f0a7e",
+//          "group": "cn=kbs-admins,ou=groups,dc=example,dc=com",
+//          "role_id": "75fa8fe0-f2e6-4c5b-a3b5-5c3e3b9e5f1a",
+//          "service": "KBS",
+//          "name": "KeyCRUD"
+//       }
+//    ]
+// ---
+
+// swagger:operation DELETE /ldap-group-mappings/{mapping_id} LDAPGroupMappings deleteLDAPGroupMapping
+// ---
+// description: |
+//   Deletes the mapping of an LDAP group to a role. The role is removed from the LDAP users who are
+//   granted the role only through the group the next time they request a token. A valid bearer token
+//   with the user_roles:delete permission should be provided to authorize this REST call.
+//
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: mapping_id
+//   description: Unique ID of the LDAP group mapping.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// responses:
+//   '204':
+//     description: Successfully deleted the LDAP group mapping.
+//
+// x-sample-call-endpoint: |
+//    https://authservice.com:8444/aas/v1/ldap-group-mappings/b2f3fa41-2f6e-4d5b-9a85-0c8b3b1f0a7e
+// ---
@@ -4,7 +4,10 @@
  */
 package aas
 
-import "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+import (
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+)
 
 // UserCredInfo request payload
 // swagger:parameters UserCredInfo
@@ -57,6 +60,10 @@ type CustomClaims struct {
 //         g-GxCZQNbo5I6zr5E-_GgzsBfbIWvN_sxFXq7pN3CN7wvCfnEGXsW4coThT2PS6V
 //         roDctDvds396GUcr1Ra077t8q_ETPStLcuKyAvH994uzyVIIXKZnyb9mjDdYU168
 //         4G0f6M2HpZoo9DZxeQlGf4RmZVqODSW2FH78f0x0a3UTsLsV02Si0KU1GaI2
+//   '401':
+//     description: Invalid username or password, or the account of the user is locked after too many failed logins.
+//   '403':
+//     description: The password of the user has expired and must be changed with the /users/changepassword API.
 //
 // x-sample-call-endpoint: https://authservice.com:8444/aas/v1/token
 // x-sample-call-input: |
@@ -159,3 +166,267 @@ type CustomClaims struct {
 //         PLXFXTIa55e53dYRPt3mf3LllNtiMsMBTOaX075MQ77TCqmgT-0cAlsB-VlqfiYP
 //         t8F6Qsn2ELaG3Yeb7Y5mN-5Ecq4dxf9WtJFaPQhtslO
 // ---
+
+// swagger:operation POST /custom-claims-token/renew Token renewCustomClaimsJwtToken
+// ---
+// description: |
+//   Renews the custom claims token of the Authorization header: a new token is issued with the same subject,
+//   claims and validity. The token must hold the custom_claims:renew permission of AAS, the user tokens are not
+//   renewed. The trust agent renews its API token with this API before it expires.
+//
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/jwt
+// responses:
+//   '200':
+//     description: Successfully renewed the custom claims token.
+//     schema:
+//       type: string
+//   '401':
+//     description: The token is not valid or does not hold the custom_claims:renew permission.
+//   '403':
+//     description: The token is a user token.
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/custom-claims-token/renew
+// x-sample-call-output: |
+//         eyJhbGciOiJSUzM4NCIsImtpZCI6ImZiNzE2YmE0MjkwODg2NGJlZWQ1ZmNmODZi...
+// ---
+
+// RefreshTokenRequestInfo request payload
+// swagger:parameters RefreshTokenRequestInfo
+type RefreshTokenRequest struct {
+	// in:body
+	Body aas.RefreshTokenRequest
+}
+
+// TokenRevokeRequestInfo request payload
+// swagger:parameters TokenRevokeRequestInfo
+type TokenRevokeRequest struct {
+	// in:body
+	Body aas.TokenRevokeRequest
+}
+
+// OIDCTokenRequestInfo request payload
+// swagger:parameters OIDCTokenRequestInfo
+type OIDCTokenRequest struct {
+	// in:body
+	Body aas.OIDCTokenRequest
+}
+
+// TokenResponseInfo response payload
+// swagger:response TokenResponseInfo
+type TokenResponse struct {
+	// in:body
+	Body aas.TokenResponse
+}
+
+// TokenRevocationsInfo response payload
+// swagger:response TokenRevocationsInfo
+type TokenRevocations struct {
+	// in:body
+	Body jwtauth.TokenRevocations
+}
+
+// swagger:operation POST /token Token getJwtTokenWithRefreshToken
+// ---
+// description: |
+//   Creates a new bearer token along with a refresh token when the request accepts application/json.
+//   The refresh token can be exchanged once for a new bearer token and refresh token with the
+//   /token/refresh API.
+//
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     "$ref": "#/definitions/UserCred"
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully created the bearer token and refresh token.
+//     schema:
+//       "$ref": "#/definitions/TokenResponse"
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/token
+// x-sample-call-input: |
+//    {
+//       "username" : "admin@aas",
+//       "password" : "aasAdminPass"
+//    }
+// x-sample-call-output: |
+//    {
+//       "access_token": "eyJhbGciOiJSUzM4NCIsImtpZCI6ImYwY2UyNzhhMGM0OGI5NjE3YzQxNzViYmMz...",
+//       "refresh_token": "3kz0FQ6zkH2Yd0cOq5ZGZ2TKOb7xg6eD9Zq2aM1mYbA",
+//       "token_type": "Bearer",
+//       "expires_in": 7200
+//    }
+// ---
+
+// swagger:operation POST /token/oidc Token getOIDCJwtToken
+// ---
+// description: |
+//   Exchanges an ID token issued to AAS by the configured OpenID Connect provider for a bearer token.
+//   The ID token signature is verified with the keys of the provider JWKS, and its issuer, audience and
+//   validity are checked. The user is granted the roles mapped to the claims of the ID token in the oidc
+//   claim_mappings of the AAS configuration. A bearer token along with a refresh token is returned when the
+//   request accepts application/json. This API is only available when OIDC is enabled in the configuration.
+//
+// consumes:
+// - application/json
+// produces:
+// - application/jwt
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     "$ref": "#/definitions/OIDCTokenRequest"
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/jwt
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully created the bearer token.
+//     schema:
+//       "$ref": "#/definitions/TokenResponse"
+//   '401':
+//     description: The ID token is invalid or is not mapped to any role.
+//   '503':
+//     description: The keys of the OpenID Connect provider could not be retrieved.
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/token/oidc
+// x-sample-call-input: |
+//    {
+//       "id_token": "eyJhbGciOiJSUzI1NiIsImtpZCI6IjFlOWdkazcifQ.eyJpc3MiOiJodHRwczovL2lkcC5leGFtcGxlLmNvbSIs..."
+//    }
+// x-sample-call-output: |
+//    {
+//       "access_token": "eyJhbGciOiJSUzM4NCIsImtpZCI6ImYwY2UyNzhhMGM0OGI5NjE3YzQxNzViYmMz...",
+//       "refresh_token": "3kz0FQ6zkH2Yd0cOq5ZGZ2TKOb7xg6eD9Zq2aM1mYbA",
+//       "token_type": "Bearer",
+//       "expires_in": 7200
+//    }
+// ---
+
+// swagger:operation POST /token/refresh Token refreshJwtToken
+// ---
+// description: |
+//   Exchanges a refresh token for a new bearer token and refresh token. The refresh token
+//   can only be used once.
+//
+// consumes:
+// - application/json
+// produces:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     "$ref": "#/definitions/RefreshTokenRequest"
+// responses:
+//   '200':
+//     description: Successfully refreshed the bearer token.
+//     schema:
+//       "$ref": "#/definitions/TokenResponse"
+//   '401':
+//     description: The refresh token is invalid, expired or already used.
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/token/refresh
+// x-sample-call-input: |
+//    {
+//       "refresh_token": "3kz0FQ6zkH2Yd0cOq5ZGZ2TKOb7xg6eD9Zq2aM1mYbA"
+//    }
+// x-sample-call-output: |
+//    {
+//       "access_token": "eyJhbGciOiJSUzM4NCIsImtpZCI6ImYwY2UyNzhhMGM0OGI5NjE3YzQxNzViYmMz...",
+//       "refresh_token": "Q8mXlVw1oJ3cR2n5dT0pZsYvK7eB4hFgA9uL6iNxWqE",
+//       "token_type": "Bearer",
+//       "expires_in": 7200
+//    }
+// ---
+
+// swagger:operation POST /token/revoke Token revokeToken
+// ---
+// description: |
+//   Revokes a bearer token or a refresh token. A revoked bearer token is added to the token
+//   revocation list, so the services stop accepting it before it expires. The API succeeds
+//   for unknown refresh tokens so that it cannot be used to probe them.
+//
+// consumes:
+// - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//     "$ref": "#/definitions/TokenRevokeRequest"
+// responses:
+//   '204':
+//     description: Successfully revoked the token.
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/token/revoke
+// x-sample-call-input: |
+//    {
+//       "token": "3kz0FQ6zkH2Yd0cOq5ZGZ2TKOb7xg6eD9Zq2aM1mYbA"
+//    }
+// ---
+
+// swagger:operation GET /token/revocations Token getTokenRevocations
+// ---
+// description: |
+//   Retrieves the token revocation list. The list has the ids (jti) of the revoked bearer
+//   tokens and the users whose tokens issued before revoked_at are revoked. Only the entries
+//   added after the since parameter are returned, the updated_at value of the response can be
+//   passed as since to retrieve the next revocations.
+//
+// produces:
+// - application/json
+// parameters:
+// - name: since
+//   description: Time of the last retrieved revocation list in RFC3339 format.
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
+// responses:
+//   '200':
+//     description: Successfully retrieved the token revocation list.
+//     schema:
+//       "$ref": "#/definitions/TokenRevocations"
+//
+// x-sample-call-endpoint: https://authservice.com:8444/aas/v1/token/revocations?since=2022-05-10T10:12:04.512Z
+// x-sample-call-output: |
+//    {
+//       "revoked_tokens": [
+//          {
+//             "jti": "bc2a6dbb-9a4c-4d5e-8d4d-1d9e7f0a26a3",
+//             "revoked_at": "2022-05-10T10:15:21.104Z",
+//             "expires_at": "2022-05-10T12:15:21.104Z"
+//          },
+//          {
+//             "sub": "admin@aas",
+//             "revoked_at": "2022-05-10T10:16:02Z",
+//             "expires_at": "2022-05-10T12:16:02.317Z"
+//          }
+//       ],
+//       "updated_at": "2022-05-10T10:16:02.318Z"
+//    }
+// ---
@@ -281,7 +281,9 @@ type SwaggUserRolesResponse struct {
 // swagger:operation PATCH /users/changepassword Users changePassword
 // ---
 // description: |
-//   Updates the password for the specified user in the Authservice database.
+//   Updates the password for the specified user in the Authservice database. The new password must match
+//   the password policy and must not be one of the recent passwords of the user. The users whose password
+//   has expired can change it with this API.
 //
 // consumes:
 //  - application/json
@@ -294,6 +296,8 @@ type SwaggUserRolesResponse struct {
 // responses:
 //   '200':
 //     description: Successfully updated the user password.
+//   '400':
+//     description: The new password does not match the password policy or was used recently.
 //
 // x-sample-call-endpoint: https://authservice.com:8444/aas/v1/users/changepassword
 // x-sample-call-input: |
@@ -308,6 +312,29 @@ type SwaggUserRolesResponse struct {
 
 // ---
 
+// swagger:operation POST /users/{user_id}/unlock Users unlockUser
+// ---
+// description: |
+//   Unlocks the account of the user locked after too many consecutive failed logins. A valid bearer
+//   token with the users:store permission should be provided to authorize this REST call.
+//
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: user_id
+//   description: Unique ID of the user.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// responses:
+//   '204':
+//     description: Successfully unlocked the user.
+//
+// x-sample-call-endpoint: |
+//    https://authservice.com:8444/aas/v1/users/1fdb39de-7bf4-440e-ad05-286eca933f78/unlock
+// ---
+
 // swagger:operation POST /users/{user_id}/roles UserRoles addUserRoles
 // ---
 // description: |
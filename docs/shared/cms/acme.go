@@ -0,0 +1,90 @@
+/*
+ *  Copyright (C) 2022 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cms
+
+import "github.com/intel-secl/intel-secl/v5/pkg/model/cms"
+
+// AcmeExternalAccountKeyInfo response payload
+// swagger:response AcmeExternalAccountKeyInfo
+type AcmeExternalAccountKeyInfo struct {
+	// in:body
+	Body cms.AcmeExternalAccountKey
+}
+
+// AcmeDirectoryInfo response payload
+// swagger:response AcmeDirectoryInfo
+type AcmeDirectoryInfo struct {
+	// in:body
+	Body cms.AcmeDirectory
+}
+
+// swagger:operation POST /acme/eab-keys ACME CreateEabKey
+// ---
+// description: |
+//   Creates an external account key for the ACME clients. The ACME account created with the key is bound to the
+//   CMS CertApprover roles of the bearer token of this request, and the certificates are issued to the account
+//   only while the user of the token still holds these roles in AAS. The key can be used once, to create one
+//   account, until it expires. A valid bearer token with CMS CertApprover roles should be provided to authorize
+//   this REST call.
+//
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// responses:
+//   "201":
+//     description: Successfully created the external account key.
+//     schema:
+//       "$ref": "#/definitions/AcmeExternalAccountKey"
+//   "401":
+//     description: The bearer token has no CMS CertApprover role.
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/acme/eab-keys
+// x-sample-call-output: |
+//    {
+//       "key_id": "ZePWXU4eVHTSwZzrOdaO9A",
+//       "hmac_key": "b2n7kOQ4Xcw9kdLwFvWv6RMxmAPz7eKbw0dF3fF3v0A",
+//       "directory": "https://cms.com:8445/cms/v1/acme/directory"
+//    }
+// ---
+
+// swagger:operation GET /acme/directory ACME GetAcmeDirectory
+// ---
+// description: |
+//   Retrieves the directory of the ACME (RFC 8555) endpoints of CMS, to be configured in the ACME clients such
+//   as cert-manager and lego along with an external account key. The accounts must be bound to an external
+//   account key, the identifiers of the orders are authorized by the CertApprover roles bound to the account
+//   and no challenge has to be completed. The certificates are issued with the certificate profile configured
+//   as acme.cert-profile, TLS by default. The identifiers of an order must all be allowed by the SAN list of
+//   one CertApprover role, which the user who created the external account key must still hold in AAS when the
+//   order is finalized, and CMS must then be configured with its service user. The SAN list of the CSR must be
+//   the identifiers of the order, the Common Name of the CSR being optional and one of the identifiers when
+//   set. The authorizations of the wildcard DNS names are for their base domain names. The other ACME
+//   endpoints are new-nonce, new-account, account/{id}, account/{id}/orders, new-order, order/{id},
+//   order/{id}/finalize, authz/{id}, cert/{id} and revoke-cert, as described in RFC 8555. They are
+//   authenticated by the JWS signature of the requests and the errors are reported as RFC 7807 problem
+//   documents.
+//
+// produces:
+// - application/json
+// responses:
+//   "200":
+//     description: Successfully retrieved the ACME directory.
+//     schema:
+//       "$ref": "#/definitions/AcmeDirectory"
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/acme/directory
+// x-sample-call-output: |
+//    {
+//       "newNonce": "https://cms.com:8445/cms/v1/acme/new-nonce",
+//       "newAccount": "https://cms.com:8445/cms/v1/acme/new-account",
+//       "newOrder": "https://cms.com:8445/cms/v1/acme/new-order",
+//       "revokeCert": "https://cms.com:8445/cms/v1/acme/revoke-cert",
+//       "meta": {
+//          "externalAccountRequired": true
+//       }
+//    }
+// ---
@@ -94,8 +94,12 @@ package cms
 // description: |
 //   Retrieves the certificate signed by CMS. A valid certificate type
 //   should be provided as a query parameter for this API Call to distinguish the
-//   type of certificate requested. A valid bearer token is required to authorize
-//   this REST call.
+//   type of certificate requested. The certificate type selects the certificate profile
+//   configured in CMS, which defines the issuing CA, the validity, the allowed key types,
+//   the allowed SANs and the key usages of the certificate. A valid bearer token is required
+//   to authorize this REST call, with a CertApprover role whose context has the CN of the CSR
+//   and a CERTTYPE selecting the same profile. CSRs violating the profile are rejected with
+//   the reason of the rejection.
 //
 // security:
 //  - bearerAuth: []
@@ -129,7 +133,9 @@ package cms
 //         1va55WHMBZlmi2T0XC8QKuYMw7FnnWU+whPaBUOgvtFRwoeLKBBR
 //         -----END CERTIFICATE REQUEST-------
 // - name: certType
-//   description: Certificate type such as TLS, Flavor-Signing, JWT-Signing, Signing and TLS-Client.
+//   description: |
+//     Certificate type such as TLS, Flavor-Signing, JWT-Signing, Signing and TLS-Client, or the name
+//     of a certificate profile configured in CMS.
 //   in: query
 //   type: string
 //   required: true
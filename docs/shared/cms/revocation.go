@@ -0,0 +1,100 @@
+/*
+ *  Copyright (C) 2022 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package cms
+
+import "github.com/intel-secl/intel-secl/v5/pkg/model/cms"
+
+// RevokeCertificateInfo request payload
+// swagger:parameters RevokeCertificateInfo
+type RevokeCertificateInfo struct {
+	// in:body
+	Body cms.RevokeCertificate
+}
+
+// swagger:operation POST /certificates/{serial}/revoke Certificate RevokeCertificate
+// ---
+// description: |
+//   Revokes a certificate issued by CMS. The certificate is listed in the CRL of its issuing CA and is
+//   reported as revoked by the OCSP responder. The optional reason is one of unspecified, keyCompromise,
+//   affiliationChanged, superseded and cessationOfOperation. A valid bearer token with the CMS CertRevoker
+//   role should be provided to authorize this REST call.
+//
+// security:
+//  - bearerAuth: []
+// consumes:
+// - application/json
+// parameters:
+// - name: serial
+//   description: Serial number of the certificate in hexadecimal.
+//   in: path
+//   required: true
+//   type: string
+// - name: request body
+//   in: body
+//   required: false
+//   schema:
+//     "$ref": "#/definitions/RevokeCertificate"
+// responses:
+//   "204":
+//     description: Successfully revoked the certificate.
+//   "400":
+//     description: Invalid revocation reason provided or certificate already revoked.
+//   "404":
+//     description: No certificate with the serial number was issued by CMS.
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/certificates/1a/revoke
+// x-sample-call-input: |
+//    {
+//       "reason": "cessationOfOperation"
+//    }
+// ---
+
+// swagger:operation GET /crl/{issuingCa} Revocation GetCrl
+// ---
+// description: |
+//   Retrieves the DER encoded CRL of an intermediate CA, listing the unexpired certificates revoked for the CA.
+//   The URL of the CRL is embedded as CRL distribution point in the certificates issued by the CA, from the
+//   revocation base URL set by the CMS setup. HVS, KBS and WLS check the CRLs of the server certificates of their
+//   TLS connections, such as the TLS certificates of the trust agents, and KBS checks the CRLs of the client
+//   certificates of the key transfers authenticated by mutual TLS, the other services not authenticating clients
+//   by certificate. The CRLs are cached until their next update, and a certificate is rejected when its CRL
+//   cannot be retrieved. The certificates issued without distribution point, before the revocation base URL
+//   was set, are not checked.
+//
+// produces:
+// - application/pkix-crl
+// parameters:
+// - name: issuingCa
+//   description: Intermediate CA such as TLS, TLS-Client and Signing.
+//   in: path
+//   required: true
+//   type: string
+// responses:
+//   "200":
+//     description: Successfully retrieved the CRL.
+//   "404":
+//     description: Invalid issuing CA provided.
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/crl/TLS-Client
+// ---
+
+// swagger:operation POST /ocsp Revocation GetOcspResponse
+// ---
+// description: |
+//   OCSP responder of the intermediate CAs as defined in RFC 6960. The DER encoded OCSP request is provided
+//   as request body, the request can also be sent base64 encoded with GET /ocsp/{request}. The response is
+//   signed by the intermediate CA that issued the certificate.
+//
+// consumes:
+// - application/ocsp-request
+// produces:
+// - application/ocsp-response
+// responses:
+//   "200":
+//     description: Successfully created the OCSP response.
+//
+// x-sample-call-endpoint: https://cms.com:8445/cms/v1/ocsp
+// ---
@@ -0,0 +1,172 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+)
+
+// AttestationPolicy request/response payload
+// swagger:parameters AttestationPolicy
+type AttestationPolicy struct {
+	// in:body
+	Body hvs.AttestationPolicy
+}
+
+// ---
+//
+// swagger:operation PUT /flavorgroups/{flavorgroup_id}/attestation-policy Flavorgroups Update-AttestationPolicy
+// ---
+//
+// description: |
+//   Creates or replaces the attestation policy of a flavorgroup. The policy schedules the attestation of the hosts of
+//   the flavorgroup, in addition to the attestation of hosts whose reports have expired, and sets the validity of
+//   their SAML reports.
+//
+//    | Attribute              | Description|
+//    |------------------------|------------|
+//    | refresh_period_seconds | Period at which the hosts of the flavorgroup are attested. At least 60 seconds. (Optional) |
+//    | jitter_seconds         | Maximum random delay added to each scheduled attestation. Must be less than the refresh period, defaults to a tenth of it. (Optional) |
+//    | saml_validity_seconds  | Validity of the SAML reports of the hosts of the flavorgroup. At least 60 seconds. (Optional) |
+//
+//   At least one of refresh_period_seconds and saml_validity_seconds must be provided. When a host belongs to several
+//   flavorgroups, its SAML reports have the shortest validity set by their policies. Changes to the schedule are taken
+//   into account within the HRRS schedule poll period, one minute by default.
+//
+// x-permissions: flavorgroups:create
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// consumes:
+//  - application/json
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/AttestationPolicy"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully saved the attestation policy.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/AttestationPolicy"
+//   '400':
+//     description: Invalid request body provided
+//   '404':
+//     description: Flavorgroup record not found
+//   '415':
+//     description: Invalid Content-Type Header
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/826501bd-3c75-4839-a08f-db5f744f8498/attestation-policy
+// x-sample-call-input: |
+//      {
+//         "refresh_period_seconds": 300,
+//         "jitter_seconds": 30,
+//         "saml_validity_seconds": 900
+//      }
+// x-sample-call-output: |
+//      {
+//         "flavorgroup_id": "826501bd-3c75-4839-a08f-db5f744f8498",
+//         "refresh_period_seconds": 300,
+//         "jitter_seconds": 30,
+//         "saml_validity_seconds": 900,
+//         "updated": "2021-06-10T08:16:42.391926Z"
+//      }
+// ---
+
+// swagger:operation GET /flavorgroups/{flavorgroup_id}/attestation-policy Flavorgroups Retrieve-AttestationPolicy
+// ---
+//
+// description: |
+//   Retrieves the attestation policy of a flavorgroup.
+// x-permissions: flavorgroups:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the attestation policy.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/AttestationPolicy"
+//   '404':
+//     description: Attestation policy not found
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/826501bd-3c75-4839-a08f-db5f744f8498/attestation-policy
+// x-sample-call-output: |
+//      {
+//         "flavorgroup_id": "826501bd-3c75-4839-a08f-db5f744f8498",
+//         "refresh_period_seconds": 86400,
+//         "updated": "2021-06-10T08:16:42.391926Z"
+//      }
+// ---
+
+// swagger:operation DELETE /flavorgroups/{flavorgroup_id}/attestation-policy Flavorgroups Delete-AttestationPolicy
+// ---
+//
+// description: |
+//   Deletes the attestation policy of a flavorgroup. Its hosts are then attested when their reports expire and their
+//   SAML reports have the configured validity.
+// x-permissions: flavorgroups:delete
+// security:
+//  - bearerAuth: []
+// parameters:
+// - name: flavorgroup_id
+//   description: Unique ID of the flavorgroup.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// responses:
+//   '204':
+//     description: Successfully deleted the attestation policy.
+//   '404':
+//     description: Attestation policy not found
+//   '500':
+//     description: Internal server error
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavorgroups/826501bd-3c75-4839-a08f-db5f744f8498/attestation-policy
+// ---
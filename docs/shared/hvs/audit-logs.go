@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+
+// AuditLogEntryCollection response payload
+// swagger:parameters AuditLogEntryCollection
+type AuditLogEntryCollection struct {
+	// in:body
+	Body hvs.AuditLogEntryCollection
+}
+
+//  ---
+//
+//  swagger:operation GET /audit-logs AuditLogs SearchAuditLogs
+//  ---
+//  description: |
+//      Searches for audit log entries, including the entries that have been rotated into the
+//      audit_log_entry_N partitions.
+//      Returns - The serialized AuditLogEntryCollection Go struct object that was retrieved, which is a collection of serialized AuditLogEntry Go struct objects.
+//
+//  x-permissions: audit_logs:search
+//  security:
+//    - bearerAuth: []
+//  produces:
+//    - application/json
+//  parameters:
+//    - name: entityId
+//      description: Identifier of the audited entity
+//      in: query
+//      type: string
+//      format: uuid
+//      required: false
+//    - name: entityType
+//      description: Type of the audited entity
+//      in: query
+//      type: string
+//      enum:
+//        - host_status
+//        - report
+//      required: false
+//    - name: action
+//      description: Audited action
+//      in: query
+//      type: string
+//      enum:
+//        - create
+//        - update
+//        - delete
+//      required: false
+//    - name: fromDate
+//      description: |
+//        Filters audit log entries created after this date.
+//         date                                   Ex: fromDate=2006-01-02
+//         date+time                              Ex: fromDate=2006-01-02 15:04:05
+//         date+time(with milli seconds)          Ex: fromDate=2006-01-02T15:04:05.000Z
+//         date+time(with micro seconds)          Ex: fromDate=2006-01-02T15:04:05.000000Z
+//      in: query
+//      type: string
+//      format: date-time
+//      required: false
+//    - name: toDate
+//      description: |
+//        Filters audit log entries created before this date.
+//         date                                   Ex: toDate=2006-01-02
+//         date+time                              Ex: toDate=2006-01-02 15:04:05
+//         date+time(with milli seconds)          Ex: toDate=2006-01-02T15:04:05.000Z
+//         date+time(with micro seconds)          Ex: toDate=2006-01-02T15:04:05.000000Z
+//      in: query
+//      type: string
+//      format: date-time
+//      required: false
+//    - name: numberOfDays
+//      description: Returns audit log entries created since the past 'n' days. For an exact range, use `fromDate` and `toDate` instead.
+//      in: query
+//      type: integer
+//      minimum: 1
+//      required: false
+//    - name: limit
+//      description: Limit of the number of items in a page.
+//      in: query
+//      type: integer
+//      required: false
+//      default: 1000
+//    - name: afterId
+//      description: Next row id after which db must be queried
+//      in: query
+//      type: integer
+//      required: false
+//    - name: Accept
+//      description: Accept header
+//      in: header
+//      type: string
+//      required: true
+//      enum:
+//        - application/json
+//  responses:
+//    '200':
+//      description: Successfully retrieved the audit log entries. Also returned when no results are found.
+//      content: application/json
+//      schema:
+//        $ref: "#/definitions/AuditLogEntryCollection"
+//    '400':
+//      description: Invalid values for search criteria
+//    '415':
+//      description: Invalid Accept Header in Request
+//    '500':
+//      description: Internal server error
+//
+//  x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/audit-logs?entityType=report&entityId=6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001&limit=2
+//  x-sample-call-output: |
+//    {
+//      "audit_logs": [
+//          {
+//              "id": "8d3b8a66-5c4a-4e32-8b2a-d0c6e0f8a001",
+//              "entity_id": "6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001",
+//              "entity_type": "report",
+//              "action": "update",
+//              "created": "2020-07-17T04:47:33.842636Z",
+//              "data": [
+//                  {
+//                      "name": "id",
+//                      "value": "6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001",
+//                      "is_updated": false
+//                  },
+//                  {
+//                      "name": "host_id",
+//                      "value": "47a3b602-f321-4e03-b3b2-8f3ca3cde128",
+//                      "is_updated": false
+//                  }
+//              ]
+//          }
+//      ],
+//      "next": "limit=2&afterId=1"
+//    }
+// ---
@@ -0,0 +1,193 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+)
+
+// FlavorBundle request/response payload
+// swagger:parameters FlavorBundle
+type FlavorBundle struct {
+	// in:body
+	Body hvs.FlavorBundle
+}
+
+// FlavorBundleImportResult response payload
+// swagger:parameters FlavorBundleImportResult
+type FlavorBundleImportResult struct {
+	// in:body
+	Body hvs.FlavorBundleImportResult
+}
+
+// ---
+//
+// swagger:operation GET /flavors/export Flavors Export-Flavors
+// ---
+//
+// description: |
+//   Exports the flavors matching the search criteria as a single bundle that can be imported in another HVS instance.
+//   The bundle contains the signed flavors, the flavorgroups they belong to with their match policies and the flavor
+//   templates of these flavorgroups. The flavorgroup and flavor template associations are listed in the flavorIds and
+//   flavorTemplateIds of each flavorgroup. The query parameters are the same as for the flavor search.
+//
+// x-permissions: flavors:export
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: id
+//   description: Flavor ID
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: key
+//   description: The key can be any “key” field from the meta description section of a flavor. Both key and value query parameters need to be specified.
+//   in: query
+//   type: string
+//   required: false
+// - name: value
+//   description: The value of the key attribute in flavor description. When provided, key must be provided in query as well.
+//   in: query
+//   type: string
+//   required: false
+// - name: flavorgroupId
+//   description: The flavor group ID. Exports all the flavors associated with the flavor group ID.
+//   in: query
+//   type: string
+//   required: false
+// - name: flavorParts
+//   description: An array of flavor parts, exports all the flavors associated with the flavor parts
+//   in: query
+//   type: string
+//   required: false
+// - name: limit
+//   description: Limit of the number of items in a page.
+//   in: query
+//   type: integer
+//   required: false
+//   default: 1000
+// - name: afterId
+//   description: Next row id after which db must be queried
+//   in: query
+//   type: integer
+//   required: false
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully exported the flavors.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/FlavorBundle"
+//   '400':
+//     description: Invalid search criteria provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/export?flavorgroupId=5f8c2a4e-2c39-4d5c-8d41-0d1a1e8b7d0e
+// x-sample-call-output: |
+//     {
+//        "signed_flavors": [
+//            {
+//                "flavor": {
+//                    "meta": {
+//                        "id": "f66ac31d-124d-418e-8200-2abf414a9adf",
+//                        "description": {
+//                            "flavor_part": "PLATFORM",
+//                            "label": "INTEL_IntelCorporation_SE5C620.86B.00.01.0014.070920180847_TXT_TPM_06-16-2020"
+//                        }
+//                    },
+//                    ...
+//                },
+//                "signature": "EyuFK0QbhO0CebbSuc+f..."
+//            }
+//        ],
+//        "flavorgroups": [
+//            {
+//                "id": "5f8c2a4e-2c39-4d5c-8d41-0d1a1e8b7d0e",
+//                "name": "automatic",
+//                "flavorIds": ["f66ac31d-124d-418e-8200-2abf414a9adf"],
+//                "flavorTemplateIds": ["426912bd-39b0-4daa-ad21-0c6933230b50"],
+//                "flavor_match_policies": [ ... ]
+//            }
+//        ],
+//        "flavor_templates": [ ... ]
+//     }
+// ---
+
+// swagger:operation POST /flavors/import Flavors Import-Flavors
+// ---
+//
+// description: |
+//   Imports a flavor bundle exported from another HVS instance. The signature of each flavor is verified with the
+//   flavor signing certificates in the trusted flavor signing certificates directory
+//   (/etc/hvs/certs/trustedca/flavor-import/ by default) before anything is stored, and the imported flavors are
+//   signed again with the flavor signing key of this instance.
+//
+//   Flavorgroups are matched by name and created if they do not exist. Flavor templates that do not exist are created.
+//   The import is idempotent on the flavor ID: flavors that already exist are left unchanged and reported in
+//   existing_flavor_ids, so the same bundle can be imported again.
+//
+// x-permissions: flavors:import
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// consumes:
+//  - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/FlavorBundle"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully imported the flavor bundle.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/FlavorBundleImportResult"
+//   '400':
+//     description: Invalid request body provided or flavor signature not trusted
+//   '415':
+//     description: Invalid Content-Type Header
+//   '500':
+//     description: Internal server error or no trusted flavor signing certificate configured
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/flavors/import
+// x-sample-call-input: |
+//     <output of GET /flavors/export>
+// x-sample-call-output: |
+//     {
+//        "imported_flavor_ids": ["f66ac31d-124d-418e-8200-2abf414a9adf"],
+//        "existing_flavor_ids": []
+//     }
+// ---
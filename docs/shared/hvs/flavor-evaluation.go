@@ -0,0 +1,120 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+)
+
+// FlavorEvaluationRequest API request payload
+// swagger:parameters FlavorEvaluationRequest
+type FlavorEvaluationRequest struct {
+	// in:body
+	Body hvs.FlavorEvaluationRequest
+}
+
+// TrustReport response payload
+// swagger:parameters TrustReport
+type TrustReport struct {
+	// in:body
+	Body hvs.TrustReport
+}
+
+// ---
+//
+// swagger:operation POST /rpc/evaluate-flavors Evaluate-Flavors Evaluate-Flavors
+// ---
+//
+// description: |
+//   Verifies a host against a flavorgroup and/or candidate flavors and returns the resulting trust report. This is a
+//   dry run: the report is not stored, the host status and trust caches are not updated and no events are published.
+//   It can be used to check the effect of new flavors on a host before they are created.
+//
+//    | Attribute         | Description|
+//    |-------------------|------------|
+//    | host_id           | ID of a registered host. Its latest stored host manifest is evaluated. |
+//    | host_manifest     | Host manifest to evaluate, instead of host_id. |
+//    | flavorgroup_name  | Name of the flavorgroup whose flavors and match policies are used. (Optional) |
+//    | flavor_collection | Candidate flavors. With a flavorgroup, they are evaluated as if they had been added to it; otherwise each of them is verified against the host. (Optional) |
+//
+//   Exactly one of host_id and host_manifest must be provided, along with flavorgroup_name, flavor_collection or both.
+//   The candidate flavors are signed with the flavor signing key of HVS before they are verified.
+//
+// x-permissions: flavors:evaluate
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// consumes:
+//  - application/json
+// parameters:
+// - name: request body
+//   required: true
+//   in: body
+//   schema:
+//    "$ref": "#/definitions/FlavorEvaluationRequest"
+// - name: Content-Type
+//   description: Content-Type header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully evaluated the flavors.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/TrustReport"
+//   '400':
+//     description: Invalid request body provided, unknown host or flavorgroup, or no host manifest available for the host
+//   '415':
+//     description: Invalid Content-Type Header
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/rpc/evaluate-flavors
+// x-sample-call-input: |
+//      {
+//         "host_id": "d9d43923-05ae-4c8a-a64f-eba02473010d",
+//         "flavorgroup_name": "automatic",
+//         "flavor_collection": {
+//             "flavors": [
+//                 {
+//                     "flavor": {
+//                         "meta": {
+//                             "description": {
+//                                 "flavor_part": "PLATFORM",
+//                                 "label": "platform_bios_update"
+//                             }
+//                         },
+//                         "pcrs": [
+//                             {
+//                                 "pcr": {"index": 0, "bank": "SHA256"},
+//                                 "measurement": "3f95ecbb0bb8e66e54d3f9e4dbae8fe57fed96f0",
+//                                 "pcr_matches": true
+//                             }
+//                         ]
+//                     }
+//                 }
+//             ]
+//         }
+//      }
+// x-sample-call-output: |
+//      {
+//         "policy_name": "",
+//         "results": [ ... ],
+//         "trusted": true,
+//         "host_manifest": { ... }
+//      }
+// ---
@@ -21,6 +21,13 @@ type ReportCollection struct {
 	Body hvs.ReportCollection
 }
 
+// ReportDiff response payload
+// swagger:parameters ReportDiff
+type ReportDiff struct {
+	// in:body
+	Body hvs.ReportDiff
+}
+
 // Report request payload
 // swagger:parameters ReportCreateRequest
 type ReportCreateRequest struct {
@@ -431,3 +438,121 @@ type ReportCreateRequest struct {
 //       "expiration": "2018-07-23T17:39:52-0700"
 //     }
 //   }
+
+// ---
+
+// swagger:operation GET /reports/{report_id}/diff Reports Diff-Reports
+// ---
+//
+// description: |
+//   Compares a report with another report of the same host, typically the last trusted report of a host that
+//   became untrusted. Returns the rules whose outcome changed, the PCR values that changed and the event log
+//   and IMA log entries that were added to or removed from the host manifest. Entries in "added" are only
+//   present in the report given by report_id, entries in "removed" are only present in the report given by
+//   the against query parameter. A rule that was only evaluated in one of the reports has a null trusted or
+//   against_trusted value.
+// x-permissions: reports:retrieve
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: report_id
+//   description: Unique ID of the Report.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: against
+//   description: Unique ID of the Report to compare with. It must belong to the same host.
+//   in: query
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully compared the Reports.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/ReportDiff"
+//   '400':
+//     description: Invalid against query parameter or the Reports belong to different hosts.
+//   '404':
+//     description: No relevant report record found.
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error.
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/reports/8a545a4f-d282-4d91-8ec5-bcbe439dcfbc/diff?against=2b5d1f0c-3e5c-4c4c-9f0e-7d0f6c9e1a32
+// x-sample-call-output: |
+//   {
+//     "report_id": "8a545a4f-d282-4d91-8ec5-bcbe439dcfbc",
+//     "against_report_id": "2b5d1f0c-3e5c-4c4c-9f0e-7d0f6c9e1a32",
+//     "host_id": "94824cb6-d6c8-4faf-83b0-125996ceebe2",
+//     "trusted": false,
+//     "against_trusted": true,
+//     "rules": [
+//       {
+//         "rule": {
+//           "rule_name": "com.intel.mtwilson.core.verifier.policy.rule.PcrMatchesConstant",
+//           "markers": ["PLATFORM"],
+//           "expected_pcr": {
+//             "pcr": {
+//               "index": 0,
+//               "bank": "SHA256"
+//             },
+//             "measurement": "8b4d4b2ad2a9b2b8c1b9d7f0d8f1a9a6c5e0d6f6c2d8c7e5a1b2c3d4e5f6a7b8",
+//             "pcr_matches": true
+//           }
+//         },
+//         "trusted": false,
+//         "against_trusted": true,
+//         "faults": [
+//           {
+//             "fault_name": "com.intel.mtwilson.core.verifier.policy.fault.PcrValueMismatchSHA256",
+//             "description": "Host PCR 0 with value '1f2e3d4c5b6a79880f1e2d3c4b5a69780f1e2d3c4b5a69788796a5b4c3d2e1f0' does not match expected value '8b4d4b2ad2a9b2b8c1b9d7f0d8f1a9a6c5e0d6f6c2d8c7e5a1b2c3d4e5f6a7b8'",
+//             "pcr_index": "pcr_0",
+//             "pcr_bank": "SHA256"
+//           }
+//         ]
+//       }
+//     ],
+//     "pcrs": [
+//       {
+//         "index": "pcr_0",
+//         "pcr_bank": "SHA256",
+//         "value": "1f2e3d4c5b6a79880f1e2d3c4b5a69780f1e2d3c4b5a69788796a5b4c3d2e1f0",
+//         "against_value": "8b4d4b2ad2a9b2b8c1b9d7f0d8f1a9a6c5e0d6f6c2d8c7e5a1b2c3d4e5f6a7b8"
+//       }
+//     ],
+//     "event_logs": [
+//       {
+//         "pcr": {
+//           "index": 0,
+//           "bank": "SHA256"
+//         },
+//         "added": [
+//           {
+//             "type_id": "0x80000008",
+//             "type_name": "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+//             "measurement": "4a3e9d8c7b6a5f4e3d2c1b0a99887766554433221100ffeeddccbbaa99887766"
+//           }
+//         ],
+//         "removed": [
+//           {
+//             "type_id": "0x80000008",
+//             "type_name": "EV_EFI_PLATFORM_FIRMWARE_BLOB",
+//             "measurement": "b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6"
+//           }
+//         ]
+//       }
+//     ]
+//   }
@@ -0,0 +1,324 @@
+/*
+ *  Copyright (C) 2021 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import "github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+
+// Subscription response payload
+// swagger:parameters Subscription
+type Subscription struct {
+	// in:body
+	Body hvs.Subscription
+}
+
+// SubscriptionCollection response payload
+// swagger:parameters SubscriptionCollection
+type SubscriptionCollection struct {
+	// in:body
+	Body hvs.SubscriptionCollection
+}
+
+// EventDeadLetterCollection response payload
+// swagger:parameters EventDeadLetterCollection
+type EventDeadLetterCollection struct {
+	// in:body
+	Body hvs.EventDeadLetterCollection
+}
+
+// ---
+
+// swagger:operation POST /subscriptions Subscriptions Create-Subscription
+// ---
+// description: |
+//   Registers a URL that HVS posts events to. Events are posted as JSON with the following headers:
+//
+//    | Header           | Description|
+//    |------------------|------------|
+//    | X-Hvs-Signature  | "sha256=" followed by the hex encoded HMAC-SHA256 of the request body, keyed with the subscription secret |
+//    | X-Hvs-Event-Type | Type of the event |
+//    | X-Hvs-Event-Id   | Unique ID of the event, identical across retries |
+//
+//   Events of type host.trusted, host.untrusted and report.created include a "report" attribute that summarizes
+//   the host's trust report. When HVS is configured with NATS servers these summaries are also published on
+//   the subjects hvs.report.created and hvs.host.<host-id>.trust-changed.
+//
+//   Any 2xx response acknowledges the event. Other responses and connection errors are retried with an
+//   increasing delay; events that are still undelivered after the configured number of retries are
+//   recorded as dead letters for the subscription.
+//
+//    | Attribute   | Description|
+//    |-------------|------------|
+//    | url         | The http or https URL events are posted to |
+//    | event_types | One or more of host.trusted, host.untrusted, host.connection-failure, flavor.created, flavor.deleted, report.created |
+//    | secret      | Key used to sign the events, at least 16 characters. Generated when not provided. (Optional) |
+//
+//   The secret is only returned in the response of this request.
+//
+// x-permissions: subscriptions:create
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// consumes:
+//   - application/json
+// parameters:
+//   - name: request body
+//     required: true
+//     in: body
+//     schema:
+//       "$ref": "#/definitions/Subscription"
+//   - name: Content-Type
+//     description: Content-Type header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   '201':
+//     description: Successfully created the subscription.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/Subscription"
+//   '400':
+//     description: Invalid request body provided
+//   '415':
+//     description: Invalid Content-Type/Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions
+// x-sample-call-input: |
+//   {
+//       "url": "https://soc.example.com/hvs-events",
+//       "event_types": ["host.untrusted", "host.connection-failure"]
+//   }
+// x-sample-call-output: |
+//   {
+//       "id": "1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11",
+//       "url": "https://soc.example.com/hvs-events",
+//       "event_types": ["host.untrusted", "host.connection-failure"],
+//       "secret": "5b0d1e3f4c8a9e7d6b2a1c0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d",
+//       "created": "2021-03-04T10:15:30.123456Z"
+//   }
+
+// ---
+
+// swagger:operation GET /subscriptions Subscriptions Search-Subscriptions
+// ---
+// description: |
+//   Searches for subscriptions. Secrets are not returned.
+//
+// x-permissions: subscriptions:search
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: eventType
+//     description: Returns the subscriptions that receive this event type
+//     in: query
+//     type: string
+//     enum:
+//       - host.trusted
+//       - host.untrusted
+//       - host.connection-failure
+//       - flavor.created
+//       - flavor.deleted
+//       - report.created
+//     required: false
+//   - name: url
+//     description: Returns the subscriptions registered for this URL
+//     in: query
+//     type: string
+//     required: false
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully searched the subscriptions.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/SubscriptionCollection"
+//   '400':
+//     description: Invalid search criteria provided
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions?eventType=host.untrusted
+// x-sample-call-output: |
+//   {
+//       "subscriptions": [
+//           {
+//               "id": "1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11",
+//               "url": "https://soc.example.com/hvs-events",
+//               "event_types": ["host.untrusted", "host.connection-failure"],
+//               "created": "2021-03-04T10:15:30.123456Z"
+//           }
+//       ]
+//   }
+
+// ---
+
+// swagger:operation GET /subscriptions/{subscription_id} Subscriptions Retrieve-Subscription
+// ---
+// description: |
+//   Retrieves a subscription. The secret is not returned.
+//
+// x-permissions: subscriptions:retrieve
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: subscription_id
+//     description: Unique ID of the subscription.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully retrieved the subscription.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/Subscription"
+//   '404':
+//     description: No subscription with the given ID
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions/1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11
+// x-sample-call-output: |
+//   {
+//       "id": "1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11",
+//       "url": "https://soc.example.com/hvs-events",
+//       "event_types": ["host.untrusted", "host.connection-failure"],
+//       "created": "2021-03-04T10:15:30.123456Z"
+//   }
+
+// ---
+
+// swagger:operation DELETE /subscriptions/{subscription_id} Subscriptions Delete-Subscription
+// ---
+// description: |
+//   Deletes a subscription and its dead letters.
+//
+// x-permissions: subscriptions:delete
+// security:
+//   - bearerAuth: []
+// parameters:
+//   - name: subscription_id
+//     description: Unique ID of the subscription.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+// responses:
+//   '204':
+//     description: Successfully deleted the subscription.
+//   '404':
+//     description: No subscription with the given ID
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions/1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11
+
+// ---
+
+// swagger:operation GET /subscriptions/{subscription_id}/dead-letters Subscriptions Search-Subscription-DeadLetters
+// ---
+// description: |
+//   Returns the events that could not be delivered to the subscription, most recent first.
+//
+// x-permissions: subscriptions:retrieve
+// security:
+//   - bearerAuth: []
+// produces:
+//   - application/json
+// parameters:
+//   - name: subscription_id
+//     description: Unique ID of the subscription.
+//     in: path
+//     required: true
+//     type: string
+//     format: uuid
+//   - name: limit
+//     description: Maximum number of dead letters returned.
+//     in: query
+//     type: integer
+//     required: false
+//     default: 1000
+//   - name: Accept
+//     description: Accept header
+//     in: header
+//     type: string
+//     required: true
+//     enum:
+//       - application/json
+// responses:
+//   "200":
+//     description: Successfully retrieved the dead letters.
+//     content: application/json
+//     schema:
+//       $ref: "#/definitions/EventDeadLetterCollection"
+//   '400':
+//     description: Invalid limit provided
+//   '404':
+//     description: No subscription with the given ID
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://hvs.com:8443/hvs/v2/subscriptions/1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11/dead-letters
+// x-sample-call-output: |
+//   {
+//       "dead_letters": [
+//           {
+//               "id": "0b1a7f7e-5c0e-4d3c-8d5e-3f2a9c1b7e64",
+//               "subscription_id": "1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11",
+//               "event": {
+//                   "id": "a3c9d8e2-4f5b-4a6c-9d7e-8f0a1b2c3d4e",
+//                   "type": "host.untrusted",
+//                   "created": "2021-03-04T10:20:00.000000Z",
+//                   "entity_id": "ee37c360-7eae-4250-a677-6ee12adce8e2",
+//                   "entity_type": "host",
+//                   "data": {
+//                       "report_id": "5c7a1f4e-2b7d-4c84-9d6a-0a6e5e2b9c22",
+//                       "trusted": false
+//                   }
+//               },
+//               "attempts": 6,
+//               "last_error": "subscriber responded with status 503",
+//               "created": "2021-03-04T10:35:00.000000Z"
+//           }
+//       ]
+//   }
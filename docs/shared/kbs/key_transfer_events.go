@@ -0,0 +1,184 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import "github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+
+type KeyTransferEvents []kbs.KeyTransferEvent
+
+// KeyTransferEventCollection response payload
+// swagger:parameters KeyTransferEventCollection
+type KeyTransferEventCollection struct {
+	// in:body
+	Body KeyTransferEvents
+}
+
+// ---
+
+// swagger:operation GET /transfer-events KeyTransferEvents SearchKeyTransferEvents
+// ---
+//
+// description: |
+//   Searches for key transfer events. A key transfer event is recorded for each decision taken on a key transfer
+//   request made with a SAML report or by an SKC client.
+//
+//    | Attribute     | Description |
+//    |---------------|-------------|
+//    | key_id        | Unique identifier of the requested key. |
+//    | requester     | Hardware UUID of the host for SAML transfers, common name of the client certificate for SKC transfers. |
+//    | transfer_type | saml or skc. |
+//    | decision      | granted, denied, challenged or failed. A challenge is issued to SKC clients without an established session, a failed transfer could not be completed by KBS. |
+//    | failed_clause | Clause of the key usage policy which denied the transfer. |
+//    | reason        | Reason of the denial or of the failure. |
+//    | created_at    | Time at which the decision was taken. |
+//
+//   Returns - The collection of serialized KeyTransferEvent Go struct objects, oldest first.
+// x-permissions: key_transfer_events:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: keyId
+//   description: Unique identifier of the key.
+//   in: query
+//   type: string
+//   format: uuid
+//   required: false
+// - name: requester
+//   description: Hardware UUID of the host or common name of the client certificate.
+//   in: query
+//   type: string
+//   required: false
+// - name: decision
+//   description: Decision taken on the key transfer request.
+//   in: query
+//   type: string
+//   required: false
+//   enum: [granted, denied, challenged, failed]
+// - name: fromDate
+//   description: Earliest time of the key transfer events in RFC3339 format.
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
+// - name: toDate
+//   description: Latest time of the key transfer events in RFC3339 format.
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the key transfer events.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyTransferEvents"
+//   '400':
+//     description: Invalid values for request params
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/transfer-events?decision=denied
+// x-sample-call-output: |
+//  [
+//    {
+//      "id": "6a1b7a43-e1b5-4e7b-9f0d-3b7e8c4b3c52",
+//      "key_id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//      "requester": "7a569dad-2d82-49e4-9156-069b0065b262",
+//      "transfer_type": "saml",
+//      "decision": "denied",
+//      "failed_clause": "required_trust[TRUST_OS]",
+//      "reason": "trust attribute of the host is false",
+//      "created_at": "2022-03-14T09:21:45.571398453Z"
+//    }
+//  ]
+
+// ---
+
+// swagger:operation GET /keys/{id}/transfers Keys RetrieveKeyTransferHistory
+// ---
+//
+// description: |
+//   Retrieves the transfer history of a key. The history of a key is kept after the key is deleted.
+//   Returns - The collection of serialized KeyTransferEvent Go struct objects of the key, oldest first.
+// x-permissions: key_transfer_events:search
+// security:
+//  - bearerAuth: []
+// produces:
+//  - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: requester
+//   description: Hardware UUID of the host or common name of the client certificate.
+//   in: query
+//   type: string
+//   required: false
+// - name: decision
+//   description: Decision taken on the key transfer request.
+//   in: query
+//   type: string
+//   required: false
+//   enum: [granted, denied, challenged, failed]
+// - name: fromDate
+//   description: Earliest time of the key transfer events in RFC3339 format.
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
+// - name: toDate
+//   description: Latest time of the key transfer events in RFC3339 format.
+//   in: query
+//   type: string
+//   format: date-time
+//   required: false
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully retrieved the transfer history of the key.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyTransferEvents"
+//   '400':
+//     description: Invalid values for request params
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfers
+// x-sample-call-output: |
+//  [
+//    {
+//      "id": "0b4bd1b4-1e1e-4a4f-b43b-e7bb4a0a3bd1",
+//      "key_id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//      "requester": "00ecd3ab-9af4-e711-906e-001560a04062",
+//      "transfer_type": "saml",
+//      "decision": "granted",
+//      "created_at": "2022-03-14T08:02:11.130215722Z"
+//    }
+//  ]
@@ -52,6 +52,17 @@ type KeyTransferResponse struct {
 //    | transfer_policy_id | Unique identifier of the transfer policy to apply to this key. |
 //    | label              | String to attach optionally a text description to the key, e.g. "US Nginx key". |
 //    | usage              | String to attach optionally a usage criteria for the key, e.g. "Country:US,State:CA". |
+//    | usage_policy       | A json object having the requirements on the SAML report of a host for the key to be transferred. Takes precedence over usage. |
+//
+//   The serialized KeyUsagePolicy Go struct object represents the content of the usage_policy field. All the attributes that are set must be satisfied.
+//
+//    | Attribute                  | Description |
+//    |----------------------------|-------------|
+//    | asset_tags                 | A json object having all_of and any_of lists of asset tags, e.g. {"key": "Country", "value": "US"}. All the asset tags of all_of and at least one of any_of must be deployed on the host. |
+//    | required_trust             | List of trust attributes of the SAML report that must be true, e.g. TRUST_PLATFORM, TRUST_OS. |
+//    | max_saml_age_seconds       | Maximum time in seconds elapsed since the SAML report was issued. |
+//    | allowed_aik_issuers        | List of common names of the CAs allowed to issue the AIK certificate of the host. |
+//    | required_hardware_features | List of hardware features of the SAML report that must be enabled, e.g. TXT, SecureBootEnabled. |
 //
 //   The serialized KeyInformation Go struct object represents the content of the key_information field.
 //
@@ -59,7 +70,7 @@ type KeyTransferResponse struct {
 //    |-------------|-------------|
 //    | algorithm   | Encryption algorithm used to create or register key. Supported algorithms are AES, RSA and EC. |
 //    | key_length  | Key length used to create key. Supported key lengths are 128,192,256 bits for AES and 2048,3072,4096,7680 bits for RSA. |
-//    | curve_type  | Elliptic curve used to create key. Supported curves are secp256r1, secp384r1 and secp521r1. |
+//    | curve_type  | Elliptic curve used to create or register EC key. Supported curves are secp256r1 (prime256v1), secp384r1 and secp521r1. Only secp256r1 and secp384r1 are supported if key is created on KMIP server. |
 //    | key_string  | Base64 encoded private key to be registered. Supported only if key is created locally. |
 //    | kmip_key_id | Unique KMIP identifier of key to be registered. Supported only if key is created on KMIP server. |
 //
@@ -185,6 +196,7 @@ type KeyTransferResponse struct {
 //
 // description: |
 //   Transfers a key.
+//   The wrapped key is raw bytes for AES keys, PKCS#1 DER for RSA keys and SEC 1 DER without the public key for EC keys.
 //   Returns - The serialized KeyTransferResponse Go struct object that was retrieved.
 // x-permissions: keys:transfer
 // security:
@@ -200,6 +212,11 @@ type KeyTransferResponse struct {
 //   required: true
 //   type: string
 //   format: uuid
+// - name: version
+//   description: Version of the key material to transfer. Defaults to the current version of the key.
+//   in: query
+//   required: false
+//   type: integer
 // - name: Content-Type
 //   description: Content-Type header
 //   in: header
@@ -221,8 +238,10 @@ type KeyTransferResponse struct {
 //       application/json
 //     schema:
 //       $ref: "#/definitions/KeyTransferResponse"
+//   '400':
+//     description: Invalid key version
 //   '404':
-//     description: Key record not found
+//     description: Key record or version not found
 //   '415':
 //     description: Invalid Content-Type/Accept Header in Request
 //   '500':
@@ -249,6 +268,64 @@ type KeyTransferResponse struct {
 
 // ---
 
+// swagger:operation POST /keys/{id}/rotate Keys RotateKey
+// ---
+//
+// description: |
+//   Rotates a key. New key material is created behind the same key id and becomes the current version of the key,
+//   which is transferred by default. The previous versions are kept and can still be transferred with the version
+//   query parameter, so that data encrypted with them can be decrypted.
+//   Returns - The serialized KeyResponse Go struct object of the rotated key.
+// x-permissions: keys:rotate
+// security:
+//  - bearerAuth: []
+// produces:
+// - application/json
+// parameters:
+// - name: id
+//   description: Unique ID of the key.
+//   in: path
+//   required: true
+//   type: string
+//   format: uuid
+// - name: Accept
+//   description: Accept header
+//   in: header
+//   type: string
+//   required: true
+//   enum:
+//     - application/json
+// responses:
+//   '200':
+//     description: Successfully rotated the key.
+//     content:
+//       application/json
+//     schema:
+//       $ref: "#/definitions/KeyResponse"
+//   '404':
+//     description: Key record not found
+//   '415':
+//     description: Invalid Accept Header in Request
+//   '500':
+//     description: Internal server error
+//
+// x-sample-call-endpoint: https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/rotate
+// x-sample-call-output: |
+//    {
+//        "key_information": {
+//            "id": "fc0cc779-22b6-4741-b0d9-e2e69635ad1e",
+//            "algorithm": "AES",
+//            "key_length": 256
+//        },
+//        "transfer_policy_id": "3ce27bbd-3c5f-4b15-8c0a-44310f0f83d9",
+//        "transfer_link": "https://kbs.com:9443/kbs/v1/keys/fc0cc779-22b6-4741-b0d9-e2e69635ad1e/transfer",
+//        "created_at": "2020-09-23T11:16:26.738467277Z",
+//        "version": 2,
+//        "rotated_at": "2020-12-23T10:05:12.164329853Z"
+//    }
+
+// ---
+
 // swagger:operation DELETE /keys/{id} Keys DeleteKey
 // ---
 //
@@ -11,19 +11,33 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/defender"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/ldap"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/oidc"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
 	"github.com/nats-io/jwt/v2"
 	"github.com/nats-io/nkeys"
+	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"time"
+	"unicode"
 )
 
 var defaultLog = log.GetDefaultLogger()
+var secLog = log.GetSecurityLogger()
 
 var defend *defender.Defender
 
+var passwordPolicy config.PasswordPolicy
+var accountLockout config.AccountLockout
+
+// ErrPasswordExpired is returned by HttpHandleUserAuth when the password of the user is correct but older than the
+// maximum password age. The user can only change the password.
+var ErrPasswordExpired = errors.New("password has expired, it must be changed with the change password API")
+
 func InitDefender(maxAttempts, intervalMins, lockoutDurationMins int) {
 	defend = defender.New(maxAttempts,
 		time.Duration(intervalMins)*time.Minute,
@@ -34,23 +48,19 @@ func InitDefender(maxAttempts, intervalMins, lockoutDurationMins int) {
 
 }
 
+// InitPasswordPolicy sets the password policy and the account lockout enforced for the local users
+func InitPasswordPolicy(policy config.PasswordPolicy, lockout config.AccountLockout) {
+	passwordPolicy = policy
+	accountLockout = lockout
+}
+
+// HttpHandleUserAuth authenticates the local user. ErrPasswordExpired is returned with http.StatusForbidden when the
+// password is correct but has expired.
 func HttpHandleUserAuth(u domain.UserStore, username, password string) (int, error) {
 	// first let us make sure that this is not a user that is banned
-
-	foundInDefendList := false
-	// check if we have an entry for the client in the defend map.
-	// There are several scenarios in this case
-	if client, ok := defend.Client(username); ok {
-		foundInDefendList = true
-		if client.Banned() {
-			// case 1. Client is banned - however, the ban expired but cleanup is not done.
-			// just delete the client from the map
-			if client.BanExpired() {
-				defend.RemoveClient(client.Key())
-			} else {
-				return http.StatusTooManyRequests, fmt.Errorf("Maximum login attempts exceeded for user : %s. Banned !", username)
-			}
-		}
+	foundInDefendList, httpStatus, err := checkDefendList(username)
+	if err != nil {
+		return httpStatus, err
 	}
 
 	// fetch by user
@@ -58,7 +68,12 @@ func HttpHandleUserAuth(u domain.UserStore, username, password string) (int, err
 	if err != nil {
 		return http.StatusUnauthorized, fmt.Errorf("BasicAuth failure: could not retrieve user: %s error: %s", username, err)
 	}
+	// the password of a locked user is not checked, so that it cannot be guessed while the account is locked
+	if userLocked(user) {
+		return http.StatusUnauthorized, fmt.Errorf("Authentication failure - account of user %s is locked", username)
+	}
 	if err := user.CheckPassword([]byte(password)); err != nil {
+		recordFailedLogin(u, user)
 		if defend.Inc(username) {
 			return http.StatusTooManyRequests, fmt.Errorf("Authentication failure - maximum login attempts exceeded for user : %s. Banned !", username)
 		}
@@ -66,13 +81,287 @@ func HttpHandleUserAuth(u domain.UserStore, username, password string) (int, err
 	}
 	// If we found the user earlier in the defend list, we should now remove as user is authorized
 	if foundInDefendList {
-		if client, ok := defend.Client(username); ok {
-			defend.RemoveClient(client.Key())
+		removeFromDefendList(username)
+	}
+	if user.FailedLoginAttempts != 0 || user.LockedAt != nil {
+		user.FailedLoginAttempts = 0
+		user.LockedAt = nil
+		if err := u.UpdateLockout(*user); err != nil {
+			defaultLog.WithError(err).Errorf("common/common:HttpHandleUserAuth() Could not reset failed logins of user %s", username)
 		}
 	}
+	if passwordExpired(user) {
+		return http.StatusForbidden, ErrPasswordExpired
+	}
 	return 0, nil
 }
 
+// HttpHandleUserStatus checks that the user authenticated earlier, like with a refresh token, is not locked and that
+// the password of the user has not expired since
+func HttpHandleUserStatus(user *types.User) (int, error) {
+	if userLocked(user) {
+		return http.StatusUnauthorized, fmt.Errorf("account of user %s is locked", user.Name)
+	}
+	if passwordExpired(user) {
+		return http.StatusForbidden, ErrPasswordExpired
+	}
+	return 0, nil
+}
+
+// UnlockUser clears the failed logins and the lockout of the user, and removes the user from the defend list
+func UnlockUser(u domain.UserStore, user types.User) error {
+	user.FailedLoginAttempts = 0
+	user.LockedAt = nil
+	if err := u.UpdateLockout(user); err != nil {
+		return fmt.Errorf("could not unlock user %s: %v", user.Name, err)
+	}
+	removeFromDefendList(user.Name)
+	return nil
+}
+
+// SetUserPassword validates the password against the password policy and the password history of the user, and sets
+// it as the password of the user. The replaced password is added to the password history, the user must be saved by
+// the caller.
+func SetUserPassword(db domain.AASDatabase, user *types.User, password string) (int, error) {
+	if err := validatePasswordPolicy(password); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// the history depth includes the current password of the user
+	keepHistory := passwordPolicy.HistoryDepth - 1
+	if passwordPolicy.HistoryDepth > 0 && len(user.PasswordHash) != 0 {
+		if user.CheckPassword([]byte(password)) == nil {
+			return http.StatusBadRequest, fmt.Errorf("password was used recently and cannot be reused")
+		}
+		if keepHistory > 0 {
+			history, err := db.PasswordHistoryStore().RetrieveAll(user.ID, keepHistory)
+			if err != nil {
+				defaultLog.WithError(err).Error("common/common:SetUserPassword() Could not retrieve password history")
+				return http.StatusInternalServerError, fmt.Errorf("cannot complete request")
+			}
+			for _, previous := range history {
+				if bcrypt.CompareHashAndPassword(previous.PasswordHash, []byte(password)) == nil {
+					return http.StatusBadRequest, fmt.Errorf("password was used recently and cannot be reused")
+				}
+			}
+		}
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		defaultLog.WithError(err).Error("common/common:SetUserPassword() Could not generate password hash")
+		return http.StatusInternalServerError, fmt.Errorf("cannot complete request")
+	}
+
+	if keepHistory > 0 && len(user.PasswordHash) != 0 {
+		_, err = db.PasswordHistoryStore().Create(types.PasswordHistory{UserID: user.ID, PasswordHash: user.PasswordHash})
+		if err != nil {
+			defaultLog.WithError(err).Error("common/common:SetUserPassword() Could not add password to password history")
+			return http.StatusInternalServerError, fmt.Errorf("cannot complete request")
+		}
+		if err = db.PasswordHistoryStore().DeleteOldest(user.ID, keepHistory); err != nil {
+			defaultLog.WithError(err).Warn("common/common:SetUserPassword() Could not delete oldest passwords from password history")
+		}
+	}
+
+	now := time.Now()
+	user.PasswordHash = passwordHash
+	user.PasswordCost = bcrypt.DefaultCost
+	user.PasswordChangedAt = &now
+	return 0, nil
+}
+
+func validatePasswordPolicy(password string) error {
+	if len(password) < passwordPolicy.MinLength {
+		return fmt.Errorf("password must have at least %d characters", passwordPolicy.MinLength)
+	}
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		default:
+			hasSpecial = true
+		}
+	}
+	if passwordPolicy.RequireUppercase && !hasUpper {
+		return fmt.Errorf("password must have an uppercase letter")
+	}
+	if passwordPolicy.RequireLowercase && !hasLower {
+		return fmt.Errorf("password must have a lowercase letter")
+	}
+	if passwordPolicy.RequireDigit && !hasDigit {
+		return fmt.Errorf("password must have a digit")
+	}
+	if passwordPolicy.RequireSpecial && !hasSpecial {
+		return fmt.Errorf("password must have a special character")
+	}
+	return nil
+}
+
+// userLocked checks if the account of the user is locked, the lockout ends after the lockout duration
+func userLocked(user *types.User) bool {
+	if accountLockout.MaxAttempts <= 0 || user.ServiceAccount || user.LockedAt == nil {
+		return false
+	}
+	return accountLockout.DurationMins <= 0 ||
+		time.Since(*user.LockedAt) < time.Duration(accountLockout.DurationMins)*time.Minute
+}
+
+// recordFailedLogin increments the failed logins of the user in the database, so that concurrent failed logins are
+// all counted, and locks the account when they reach the maximum login attempts
+func recordFailedLogin(u domain.UserStore, user *types.User) {
+	if accountLockout.MaxAttempts <= 0 || user.ServiceAccount {
+		return
+	}
+	// the lockouts started before lockoutEnd have ended, a lockout without duration never ends
+	var lockoutEnd time.Time
+	if accountLockout.DurationMins > 0 {
+		lockoutEnd = time.Now().Add(-time.Duration(accountLockout.DurationMins) * time.Minute)
+	}
+	lockout, err := u.RecordFailedLogin(*user, accountLockout.MaxAttempts, lockoutEnd)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("common/common:recordFailedLogin() Could not record failed login of user %s", user.Name)
+		return
+	}
+	user.FailedLoginAttempts = lockout.FailedLoginAttempts
+	user.LockedAt = lockout.LockedAt
+	if user.FailedLoginAttempts == accountLockout.MaxAttempts {
+		secLog.Warningf("%s: Account of user %s locked after %d failed logins", commLogMsg.UnauthorizedAccess,
+			user.Name, user.FailedLoginAttempts)
+	}
+}
+
+func passwordExpired(user *types.User) bool {
+	if passwordPolicy.MaxAgeDays <= 0 || user.ServiceAccount || user.PasswordChangedAt == nil {
+		return false
+	}
+	return time.Since(*user.PasswordChangedAt) > time.Duration(passwordPolicy.MaxAgeDays)*24*time.Hour
+}
+
+// HttpHandleLDAPUserAuth authenticates the user against the LDAP directory, with the same lockout of the users
+// exceeding the maximum login attempts as the local users
+func HttpHandleLDAPUserAuth(authenticator domain.LDAPAuthenticator, username, password string) (*types.LDAPUser, int, error) {
+	foundInDefendList, httpStatus, err := checkDefendList(username)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	ldapUser, err := authenticator.Authenticate(username, password)
+	if errors.Is(err, ldap.ErrInvalidCredentials) {
+		if defend.Inc(username) {
+			return nil, http.StatusTooManyRequests, fmt.Errorf("Authentication failure - maximum login attempts exceeded for user : %s. Banned !", username)
+		}
+		return nil, http.StatusUnauthorized, fmt.Errorf("invalid username or password provided")
+	}
+	if err != nil {
+		defaultLog.WithError(err).Error("common/common:HttpHandleLDAPUserAuth() Could not authenticate user against LDAP directory")
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("could not authenticate user against LDAP directory")
+	}
+	if foundInDefendList {
+		removeFromDefendList(username)
+	}
+	return ldapUser, 0, nil
+}
+
+// HttpHandleOIDCUserAuth verifies the ID token of the OpenID Connect provider and returns the user identified by it
+func HttpHandleOIDCUserAuth(verifier domain.OIDCVerifier, idToken string) (*types.OIDCIdentity, int, error) {
+	identity, err := verifier.Verify(idToken)
+	if errors.Is(err, oidc.ErrInvalidToken) {
+		return nil, http.StatusUnauthorized, fmt.Errorf("invalid ID token provided")
+	}
+	if err != nil {
+		defaultLog.WithError(err).Error("common/common:HttpHandleOIDCUserAuth() Could not verify ID token with OIDC provider")
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("could not verify ID token with OIDC provider")
+	}
+	return identity, 0, nil
+}
+
+// checkDefendList checks if we have an entry for the client in the defend map, and fails if the client is banned
+func checkDefendList(username string) (bool, int, error) {
+	client, ok := defend.Client(username)
+	if !ok {
+		return false, 0, nil
+	}
+	// There are several scenarios in this case
+	if client.Banned() {
+		// case 1. Client is banned - however, the ban expired but cleanup is not done.
+		// just delete the client from the map
+		if client.BanExpired() {
+			defend.RemoveClient(client.Key())
+		} else {
+			return true, http.StatusTooManyRequests, fmt.Errorf("Maximum login attempts exceeded for user : %s. Banned !", username)
+		}
+	}
+	return true, 0, nil
+}
+
+func removeFromDefendList(username string) {
+	if client, ok := defend.Client(username); ok {
+		defend.RemoveClient(client.Key())
+	}
+}
+
+// RevokeUserTokens revokes the bearer tokens issued to the user so far and deletes the refresh tokens of the user.
+// It is called when the user is deleted or when the credentials or the roles of the user are changed.
+func RevokeUserTokens(db domain.AASDatabase, user types.User, tokenValidity time.Duration) error {
+	defaultLog.Trace("common/common:RevokeUserTokens() Entering")
+	defer defaultLog.Trace("common/common:RevokeUserTokens() Leaving")
+
+	// the issued at time of the tokens is in seconds, so the tokens issued within the second of the revocation
+	// are not revoked to keep the tokens requested right after the change valid
+	now := time.Now()
+	_, err := db.RevokedTokenStore().Create(types.RevokedToken{
+		Subject:   user.Name,
+		RevokedAt: now.Truncate(time.Second),
+		ExpiresAt: now.Add(tokenValidity),
+	})
+	if err != nil {
+		return fmt.Errorf("could not revoke tokens of user %s: %v", user.Name, err)
+	}
+	if err = db.RefreshTokenStore().DeleteByUser(user.ID); err != nil {
+		return fmt.Errorf("could not delete refresh tokens of user %s: %v", user.Name, err)
+	}
+	if err = db.RevokedTokenStore().DeleteExpired(now); err != nil {
+		defaultLog.WithError(err).Warn("could not delete expired entries of token revocation list")
+	}
+	return nil
+}
+
+// RetrieveTokenRevocations retrieves the tokens revoked after since, all of them for the zero time. The revocations
+// created up to constants.TokenRevocationsOverlap before since are retrieved again, so that the revocations committed
+// after a later one are not missed, the clients dedupe them by jti (token id) and subject.
+func RetrieveTokenRevocations(db domain.AASDatabase, since time.Time) (*jwtauth.TokenRevocations, error) {
+	defaultLog.Trace("common/common:RetrieveTokenRevocations() Entering")
+	defer defaultLog.Trace("common/common:RetrieveTokenRevocations() Leaving")
+
+	createdSince := since
+	if !since.IsZero() {
+		createdSince = since.Add(-constants.TokenRevocationsOverlap)
+	}
+	revokedTokens, err := db.RevokedTokenStore().RetrieveAll(createdSince)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve token revocation list: %v", err)
+	}
+	revocations := &jwtauth.TokenRevocations{RevokedTokens: []jwtauth.RevokedToken{}, UpdatedAt: since}
+	for _, revokedToken := range revokedTokens {
+		revocations.RevokedTokens = append(revocations.RevokedTokens, jwtauth.RevokedToken{
+			Jti:       revokedToken.Jti,
+			Subject:   revokedToken.Subject,
+			RevokedAt: revokedToken.RevokedAt,
+			ExpiresAt: revokedToken.ExpiresAt,
+		})
+		if revokedToken.CreatedAt.After(revocations.UpdatedAt) {
+			revocations.UpdatedAt = revokedToken.CreatedAt
+		}
+	}
+	return revocations, nil
+}
+
 //Generates JWT token from key pair
 func CreateJWTToken(keyPair nkeys.KeyPair, issuerKeyPair nkeys.KeyPair, creatorType, clientType string, entityInfo config.NatsEntityInfo) (string, error) {
 	defaultLog.Trace("common/common:CreateJWTToken() Entering")
@@ -103,7 +392,7 @@ func CreateJWTToken(keyPair nkeys.KeyPair, issuerKeyPair nkeys.KeyPair, creatorT
 		claims = jwt.NewUserClaims(pk)
 		claims.(*jwt.UserClaims).Name = entityInfo.Name
 		if clientType == constants.ComponentTypeHvs {
-			claims.(*jwt.UserClaims).Pub.Allow = []string{"trust-agent.>"}
+			claims.(*jwt.UserClaims).Pub.Allow = []string{"trust-agent.>", "hvs.>"}
 			claims.(*jwt.UserClaims).Sub.Allow = []string{"_INBOX.>"}
 		} else if clientType == constants.ComponentTypeTa {
 			claims.(*jwt.UserClaims).Pub.Deny = []string{">"}
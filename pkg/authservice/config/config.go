@@ -7,6 +7,7 @@ package config
 import (
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
 	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -21,16 +22,53 @@ const (
 	AasServiceUsername = "aas.service-username"
 	AasServicePassword = "aas.service-password"
 
-	JwtIncludeKid        = "jwt.include-kid"
-	JwtCertCommonName    = "jwt.cert-common-name"
-	JwtTokenDurationMins = "jwt.token-duration-mins"
+	JwtIncludeKid                  = "jwt.include-kid"
+	JwtCertCommonName              = "jwt.cert-common-name"
+	JwtTokenDurationMins           = "jwt.token-duration-mins"
+	JwtRefreshTokenDurationMins    = "jwt.refresh-token-duration-mins"
+	JwtCustomClaimsMaxDurationMins = "jwt.custom-claims-token-max-duration-mins"
 
 	AuthDefenderMaxAttempts         = "auth-defender.max-attempts"
 	AuthDefenderIntervalMins        = "auth-defender.interval-mins"
 	AuthDefenderLockoutDurationMins = "auth-defender.lockout-duration-mins"
 
+	PasswordPolicyMinLength        = "password-policy.min-length"
+	PasswordPolicyRequireUppercase = "password-policy.require-uppercase"
+	PasswordPolicyRequireLowercase = "password-policy.require-lowercase"
+	PasswordPolicyRequireDigit     = "password-policy.require-digit"
+	PasswordPolicyRequireSpecial   = "password-policy.require-special"
+	PasswordPolicyHistoryDepth     = "password-policy.history-depth"
+	PasswordPolicyMaxAgeDays       = "password-policy.max-age-days"
+
+	AccountLockoutMaxAttempts  = "account-lockout.max-attempts"
+	AccountLockoutDurationMins = "account-lockout.duration-mins"
+
 	CreateCredentials = "create-credentials"
 
+	LdapEnabled            = "ldap.enabled"
+	LdapURL                = "ldap.url"
+	LdapStartTLS           = "ldap.start-tls"
+	LdapCACertFile         = "ldap.ca-cert-file"
+	LdapBindDN             = "ldap.bind-dn"
+	LdapBindPassword       = "ldap.bind-password"
+	LdapUserSearchBase     = "ldap.user-search-base"
+	LdapUserFilter         = "ldap.user-filter"
+	LdapGroupAttribute     = "ldap.group-attribute"
+	LdapGroupSearchBase    = "ldap.group-search-base"
+	LdapGroupFilter        = "ldap.group-filter"
+	LdapAutoProvisionUsers = "ldap.auto-provision-users"
+	LdapTimeout            = "ldap.timeout"
+
+	OidcEnabled            = "oidc.enabled"
+	OidcIssuerURL          = "oidc.issuer-url"
+	OidcClientID           = "oidc.client-id"
+	OidcJWKSURL            = "oidc.jwks-url"
+	OidcCACertFile         = "oidc.ca-cert-file"
+	OidcUsernameClaim      = "oidc.username-claim"
+	OidcClaimMappings      = "oidc.claim-mappings"
+	OidcAutoProvisionUsers = "oidc.auto-provision-users"
+	OidcTimeout            = "oidc.timeout"
+
 	NatsOperatorName               = "nats.operator.name"
 	NatsOperatorCredentialValidity = "nats.operator.credential-validity"
 	NatsAccountName                = "nats.account.name"
@@ -47,10 +85,14 @@ type Configuration struct {
 	DB               commConfig.DBConfig      `yaml:"db"`
 	Log              commConfig.LogConfig     `yaml:"log"`
 	AuthDefender     AuthDefender             `yaml:"auth-defender"`
+	PasswordPolicy   PasswordPolicy           `yaml:"password-policy"`
+	AccountLockout   AccountLockout           `yaml:"account-lockout"`
 	JWT              JWT                      `yaml:"jwt"`
 	TLS              commConfig.TLSCertConfig `yaml:"tls"`
 	Server           commConfig.ServerConfig  `yaml:"server"`
 	Nats             NatsConfig               `yaml:"nats"`
+	LDAP             LDAPConfig               `yaml:"ldap"`
+	OIDC             OIDCConfig               `yaml:"oidc"`
 }
 
 type AASConfig struct {
@@ -59,9 +101,12 @@ type AASConfig struct {
 }
 
 type JWT struct {
-	IncludeKid        bool   `yaml:"include-kid" mapstructure:"include-kid"`
-	TokenDurationMins int    `yaml:"token-duration-mins" mapstructure:"token-duration-mins"`
-	CertCommonName    string `yaml:"cert-common-name" mapstructure:"cert-common-name"`
+	IncludeKid               bool   `yaml:"include-kid" mapstructure:"include-kid"`
+	TokenDurationMins        int    `yaml:"token-duration-mins" mapstructure:"token-duration-mins"`
+	CertCommonName           string `yaml:"cert-common-name" mapstructure:"cert-common-name"`
+	RefreshTokenDurationMins int    `yaml:"refresh-token-duration-mins" mapstructure:"refresh-token-duration-mins"`
+	// CustomClaimsMaxDurationMins is the longest validity allowed for the custom claims tokens
+	CustomClaimsMaxDurationMins int `yaml:"custom-claims-token-max-duration-mins" mapstructure:"custom-claims-token-max-duration-mins"`
 }
 
 type AuthDefender struct {
@@ -70,6 +115,79 @@ type AuthDefender struct {
 	LockoutDurationMins int `yaml:"lockout-duration-mins" mapstructure:"lockout-duration-mins"`
 }
 
+// PasswordPolicy is the policy of the passwords of the local users, enforced when a password is set
+type PasswordPolicy struct {
+	MinLength        int  `yaml:"min-length" mapstructure:"min-length"`
+	RequireUppercase bool `yaml:"require-uppercase" mapstructure:"require-uppercase"`
+	RequireLowercase bool `yaml:"require-lowercase" mapstructure:"require-lowercase"`
+	RequireDigit     bool `yaml:"require-digit" mapstructure:"require-digit"`
+	RequireSpecial   bool `yaml:"require-special" mapstructure:"require-special"`
+	// HistoryDepth is the number of passwords of the user, including the current one, which cannot be reused
+	HistoryDepth int `yaml:"history-depth" mapstructure:"history-depth"`
+	// MaxAgeDays is the number of days after which the password must be changed, passwords do not expire when 0
+	MaxAgeDays int `yaml:"max-age-days" mapstructure:"max-age-days"`
+}
+
+// AccountLockout is the lockout of the local users after consecutive failed logins. Unlike the auth defender, the
+// lockout is stored in the database and is kept when AAS is restarted.
+type AccountLockout struct {
+	// MaxAttempts is the number of consecutive failed logins locking the account, the lockout is disabled when 0
+	MaxAttempts int `yaml:"max-attempts" mapstructure:"max-attempts"`
+	// DurationMins is the duration of the lockout, the account stays locked until unlocked by an admin when 0
+	DurationMins int `yaml:"duration-mins" mapstructure:"duration-mins"`
+}
+
+// LDAPConfig is the configuration of the LDAP directory the users requesting a token can be authenticated against
+type LDAPConfig struct {
+	Enabled  bool   `yaml:"enabled" mapstructure:"enabled"`
+	URL      string `yaml:"url" mapstructure:"url"`
+	StartTLS bool   `yaml:"start-tls" mapstructure:"start-tls"`
+	// CACertFile is the CA certificate bundle verifying the certificate of the directory, the system certificate
+	// pool is used when it is not set
+	CACertFile string `yaml:"ca-cert-file" mapstructure:"ca-cert-file"`
+	// BindDN and BindPassword are the credentials used to search the users, the search is anonymous without them
+	BindDN         string `yaml:"bind-dn" mapstructure:"bind-dn"`
+	BindPassword   string `yaml:"bind-password" mapstructure:"bind-password"`
+	UserSearchBase string `yaml:"user-search-base" mapstructure:"user-search-base"`
+	// UserFilter is the search filter of the user entry, %s is replaced with the escaped username
+	UserFilter string `yaml:"user-filter" mapstructure:"user-filter"`
+	// GroupAttribute is the attribute of the user entry listing the groups of the user, like memberOf in AD
+	GroupAttribute string `yaml:"group-attribute" mapstructure:"group-attribute"`
+	// GroupSearchBase and GroupFilter are used to search the groups of the user when the directory has no
+	// group attribute in the user entry, %s in the filter is replaced with the escaped DN of the user
+	GroupSearchBase    string        `yaml:"group-search-base" mapstructure:"group-search-base"`
+	GroupFilter        string        `yaml:"group-filter" mapstructure:"group-filter"`
+	AutoProvisionUsers bool          `yaml:"auto-provision-users" mapstructure:"auto-provision-users"`
+	Timeout            time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// OIDCConfig is the configuration of the OpenID Connect provider whose ID tokens can be exchanged for AAS tokens
+type OIDCConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// IssuerURL must be the iss claim of the ID tokens, the provider metadata is discovered from it
+	IssuerURL string `yaml:"issuer-url" mapstructure:"issuer-url"`
+	// ClientID must be in the aud claim of the ID tokens
+	ClientID string `yaml:"client-id" mapstructure:"client-id"`
+	// JWKSURL overrides the jwks_uri of the provider metadata
+	JWKSURL string `yaml:"jwks-url" mapstructure:"jwks-url"`
+	// CACertFile is the CA certificate bundle verifying the certificate of the provider, the system certificate
+	// pool is used when it is not set
+	CACertFile string `yaml:"ca-cert-file" mapstructure:"ca-cert-file"`
+	// UsernameClaim is the claim of the ID token used as the AAS username
+	UsernameClaim      string             `yaml:"username-claim" mapstructure:"username-claim"`
+	ClaimMappings      []OIDCClaimMapping `yaml:"claim-mappings" mapstructure:"claim-mappings"`
+	AutoProvisionUsers bool               `yaml:"auto-provision-users" mapstructure:"auto-provision-users"`
+	Timeout            time.Duration      `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// OIDCClaimMapping grants the roles to the users whose ID token has the value in the claim. The claim can be a
+// string or a list of strings, like the groups claim.
+type OIDCClaimMapping struct {
+	Claim string         `yaml:"claim" mapstructure:"claim" json:"claim"`
+	Value string         `yaml:"value" mapstructure:"value" json:"value"`
+	Roles []aas.RoleInfo `yaml:"roles" mapstructure:"roles" json:"roles"`
+}
+
 type NatsConfig struct {
 	Operator               NatsEntityInfo `yaml:"operator" mapstructure:"operator"`
 	Account                NatsEntityInfo `yaml:"account" mapstructure:"account"`
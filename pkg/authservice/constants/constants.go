@@ -26,6 +26,11 @@ const (
 	TokenSignKeysAndCertDir = ConfigDir + "certs/tokensign/"
 	TokenSignKeyFile        = TokenSignKeysAndCertDir + "jwt.key"
 	TokenSignCertFile       = TokenSignKeysAndCertDir + "jwtsigncert.pem"
+	// next signing key published in the JWKS before tokens are signed with it, and previous signing certificate
+	// published until the tokens signed with it expire
+	TokenSignNextKeyFile      = TokenSignKeysAndCertDir + "jwt-next.key"
+	TokenSignNextCertFile     = TokenSignKeysAndCertDir + "jwtsigncert-next.pem"
+	TokenSignPreviousCertFile = TokenSignKeysAndCertDir + "jwtsigncert-previous.pem"
 
 	OperatorSeedFile         = NatsNkeyDirPath + "operator-seed.txt"
 	AccountSeedFile          = NatsNkeyDirPath + "account-seed.txt"
@@ -52,6 +57,17 @@ const (
 	DefaultLogLevel                = "info"
 )
 
+const (
+	DefaultRefreshTokenDurationMins = 1440
+	// DefaultCustomClaimsMaxDurationMins allows the custom claims tokens valid for one year requested by the trust agent
+	DefaultCustomClaimsMaxDurationMins = 525600
+	// DefaultTokenRevocationRefreshSecs is how often AAS refreshes the token revocation list from the database
+	DefaultTokenRevocationRefreshSecs = 10
+	// TokenRevocationsOverlap is how long before since the token revocations are retrieved again: the creation time
+	// of a revocation is set before its transaction commits, so it may be committed after a later revocation
+	TokenRevocationsOverlap = time.Minute
+)
+
 const (
 	DefaultDBVendor            = "postgres"
 	DefaultDBName              = "aas_db"
@@ -82,6 +98,31 @@ const (
 	DefaultAuthDefendLockoutMins  = 15
 )
 
+const (
+	DefaultPasswordMinLength          = 8
+	DefaultAccountLockoutMaxAttempts  = 10
+	DefaultAccountLockoutDurationMins = 30
+)
+
+const (
+	DefaultLdapUserFilter     = "(uid=%s)"
+	DefaultLdapGroupAttribute = "memberOf"
+	DefaultLdapGroupFilter    = "(member=%s)"
+	DefaultLdapTimeout        = 10 * time.Second
+)
+
+const (
+	DefaultOidcUsernameClaim = "preferred_username"
+	DefaultOidcTimeout       = 10 * time.Second
+)
+
+// sources of the users authenticated by an external identity provider
+const (
+	UserSourceLdap = "ldap"
+	// UserSourceOidcPrefix is followed by the issuer URL of the OIDC provider
+	UserSourceOidcPrefix = "oidc:"
+)
+
 const (
 	DefaultReadTimeout       = 30 * time.Second
 	DefaultReadHeaderTimeout = 10 * time.Second
@@ -100,7 +141,8 @@ const (
 	HvsUserName         = "ISecL-HVS"
 )
 
-var DefaultRoles = [5]string{Administrator, RoleManager, UserManager, UserRoleManager, CustomClaimsCreator}
+var DefaultRoles = [7]string{Administrator, RoleManager, UserManager, UserRoleManager, CustomClaimsCreator, TokenRevoker,
+	TokenRevocationReader}
 
 const (
 	Administrator       = "Administrator"
@@ -108,6 +150,10 @@ const (
 	UserManager         = "UserManager"
 	UserRoleManager     = "UserRoleManager"
 	CustomClaimsCreator = "CustomClaimsCreator"
+	// TokenRevoker revokes the tokens issued by AAS
+	TokenRevoker = "TokenRevoker"
+	// TokenRevocationReader is the role of the services retrieving the token revocations
+	TokenRevocationReader = "TokenRevocationReader"
 )
 
 func GetDefaultAdministratorRoles() []ct.RoleCreate {
@@ -163,5 +209,25 @@ func GetDefaultAdministratorRoles() []ct.RoleCreate {
 				CustomClaimsCreate,
 			},
 		},
+		{
+			RoleInfo: ct.RoleInfo{
+				Service: ServiceName,
+				Name:    TokenRevoker,
+				Context: "",
+			},
+			Permissions: []string{
+				TokenRevoke + ":*",
+			},
+		},
+		{
+			RoleInfo: ct.RoleInfo{
+				Service: ServiceName,
+				Name:    TokenRevocationReader,
+				Context: "",
+			},
+			Permissions: []string{
+				TokenRevocationRetrieve + ":*",
+			},
+		},
 	}
 }
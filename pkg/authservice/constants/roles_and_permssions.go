@@ -23,6 +23,11 @@ const (
 	UserRoleDelete   = "user_roles:delete"
 
 	CustomClaimsCreate = "custom_claims:create"
+	// CustomClaimsRenew is granted in the custom claims tokens that can be renewed with themselves
+	CustomClaimsRenew = "custom_claims:renew"
+
+	TokenRevoke             = "tokens:revoke"
+	TokenRevocationRetrieve = "token_revocations:retrieve"
 
 	CredentialCreate = "credential:create"
 
@@ -293,6 +293,206 @@ func getMockUserStore() mock.MockUserStore {
 	return mockUserStore
 }
 
+func getMockRevokedTokenStore() mock.MockRevokedTokenStore {
+	var revokedTokens types.RevokedTokens
+	mockRevokedTokenStore := mock.MockRevokedTokenStore{}
+	mockRevokedTokenStore.CreateFunc = func(rt types.RevokedToken) (*types.RevokedToken, error) {
+		rt.ID = uuid.NewString()
+		rt.CreatedAt = time.Now()
+		revokedTokens = append(revokedTokens, rt)
+		return &rt, nil
+	}
+	mockRevokedTokenStore.RetrieveAllFunc = func(createdSince time.Time) (types.RevokedTokens, error) {
+		var result types.RevokedTokens
+		for _, rt := range revokedTokens {
+			if rt.CreatedAt.After(createdSince) {
+				result = append(result, rt)
+			}
+		}
+		return result, nil
+	}
+	return mockRevokedTokenStore
+}
+
+func getMockRefreshTokenStore() mock.MockRefreshTokenStore {
+	refreshTokens := map[string]types.RefreshToken{}
+	mockRefreshTokenStore := mock.MockRefreshTokenStore{}
+	mockRefreshTokenStore.CreateFunc = func(rt types.RefreshToken) (*types.RefreshToken, error) {
+		rt.ID = uuid.NewString()
+		refreshTokens[string(rt.TokenHash)] = rt
+		return &rt, nil
+	}
+	mockRefreshTokenStore.RetrieveFunc = func(tokenHash []byte) (*types.RefreshToken, error) {
+		if rt, ok := refreshTokens[string(tokenHash)]; ok {
+			return &rt, nil
+		}
+		return nil, errors.New("record not found")
+	}
+	mockRefreshTokenStore.DeleteFunc = func(rt types.RefreshToken) error {
+		for tokenHash, storedToken := range refreshTokens {
+			if storedToken.ID == rt.ID {
+				delete(refreshTokens, tokenHash)
+				return nil
+			}
+		}
+		return errors.New("record not found")
+	}
+	return mockRefreshTokenStore
+}
+
+func getMockLDAPGroupMappingStore() mock.MockLDAPGroupMappingStore {
+	var mappings types.LDAPGroupMappings
+	mockLDAPGroupMappingStore := mock.MockLDAPGroupMappingStore{}
+	mockLDAPGroupMappingStore.CreateFunc = func(m types.LDAPGroupMapping) (*types.LDAPGroupMapping, error) {
+		m.ID = uuid.NewString()
+		mappings = append(mappings, m)
+		return &m, nil
+	}
+	mockLDAPGroupMappingStore.RetrieveFunc = func(id string) (*types.LDAPGroupMapping, error) {
+		for _, m := range mappings {
+			if m.ID == id {
+				return &m, nil
+			}
+		}
+		return nil, errors.New("record not found")
+	}
+	mockLDAPGroupMappingStore.RetrieveAllFunc = func(groups []string) (types.LDAPGroupMappings, error) {
+		var result types.LDAPGroupMappings
+		for _, m := range mappings {
+			if len(groups) == 0 {
+				result = append(result, m)
+			}
+			for _, group := range groups {
+				if strings.EqualFold(m.GroupDN, group) {
+					result = append(result, m)
+				}
+			}
+		}
+		return result, nil
+	}
+	mockLDAPGroupMappingStore.DeleteFunc = func(m types.LDAPGroupMapping) error {
+		for index, mapping := range mappings {
+			if mapping.ID == m.ID {
+				mappings = append(mappings[:index], mappings[index+1:]...)
+				return nil
+			}
+		}
+		return errors.New("record not found")
+	}
+	return mockLDAPGroupMappingStore
+}
+
+// getMockLDAPRoleStore returns a role store with the roles the LDAP groups are mapped to in the tests
+func getMockLDAPRoleStore(ldapRoles types.Roles) mock.MockRoleStore {
+	mockRoleStore := mock.MockRoleStore{}
+	mockRoleStore.RetrieveFunc = func(rs *types.RoleSearch) (*types.Role, error) {
+		for _, role := range ldapRoles {
+			if role.RoleInfo == rs.RoleInfo {
+				return &role, nil
+			}
+			for _, id := range rs.IDFilter {
+				if role.ID == id {
+					return &role, nil
+				}
+			}
+		}
+		return nil, errors.New("record not found")
+	}
+	mockRoleStore.RetrieveAllFunc = func(rs *types.RoleSearch) (types.Roles, error) {
+		var resultRoles types.Roles
+		for _, role := range ldapRoles {
+			for _, id := range rs.IDFilter {
+				if role.ID != id {
+					continue
+				}
+				if len(rs.ServiceFilter) == 0 {
+					resultRoles = append(resultRoles, role)
+				}
+				for _, service := range rs.ServiceFilter {
+					if role.Service == service {
+						resultRoles = append(resultRoles, role)
+					}
+				}
+			}
+		}
+		return resultRoles, nil
+	}
+	return mockRoleStore
+}
+
+// getMockUsersDatabase returns a database with in-memory user and password history stores, the roles can be granted
+// to the users
+func getMockUsersDatabase(roles types.Roles) *mock.MockDatabase {
+	mockDatabase := &mock.MockDatabase{
+		MockRoleStore:             getMockLDAPRoleStore(roles),
+		MockLDAPGroupMappingStore: getMockLDAPGroupMappingStore(),
+		MockRevokedTokenStore:     getMockRevokedTokenStore(),
+		MockRefreshTokenStore:     getMockRefreshTokenStore(),
+		MockPasswordHistoryStore:  getMockPasswordHistoryStore(),
+	}
+	mockDatabase.MockUserStore.RoleStore = roles
+	mockDatabase.MockUserStore.CreateFunc = func(u types.User) (*types.User, error) {
+		u.ID = uuid.NewString()
+		mockDatabase.MockUserStore.UserStore = append(mockDatabase.MockUserStore.UserStore, u)
+		return &u, nil
+	}
+	mockDatabase.MockUserStore.RetrieveFunc = func(u types.User) (*types.User, error) {
+		for _, user := range mockDatabase.MockUserStore.UserStore {
+			if u.Subject != "" {
+				if u.Source == user.Source && u.Subject == user.Subject {
+					return &user, nil
+				}
+			} else if u.Name == user.Name || (u.ID != "" && u.ID == user.ID) {
+				return &user, nil
+			}
+		}
+		return nil, errors.New("record not found")
+	}
+	mockDatabase.MockUserStore.UpdateFunc = func(u types.User) error {
+		for index, user := range mockDatabase.MockUserStore.UserStore {
+			if u.ID == user.ID {
+				mockDatabase.MockUserStore.UserStore[index] = u
+				return nil
+			}
+		}
+		return errors.New("record not found")
+	}
+	return mockDatabase
+}
+
+func getMockPasswordHistoryStore() mock.MockPasswordHistoryStore {
+	var histories types.PasswordHistories
+	mockPasswordHistoryStore := mock.MockPasswordHistoryStore{}
+	mockPasswordHistoryStore.CreateFunc = func(h types.PasswordHistory) (*types.PasswordHistory, error) {
+		h.ID = uuid.NewString()
+		histories = append(types.PasswordHistories{h}, histories...)
+		return &h, nil
+	}
+	mockPasswordHistoryStore.RetrieveAllFunc = func(userID string, limit int) (types.PasswordHistories, error) {
+		var userHistories types.PasswordHistories
+		for _, h := range histories {
+			if h.UserID == userID && len(userHistories) < limit {
+				userHistories = append(userHistories, h)
+			}
+		}
+		return userHistories, nil
+	}
+	mockPasswordHistoryStore.DeleteOldestFunc = func(userID string, keep int) error {
+		var kept types.PasswordHistories
+		for _, h := range histories {
+			if h.UserID != userID {
+				kept = append(kept, h)
+			} else if keep > 0 {
+				kept = append(kept, h)
+				keep--
+			}
+		}
+		histories = kept
+		return nil
+	}
+	return mockPasswordHistoryStore
+}
+
 func getMockRoleStore() mock.MockRoleStore {
 	mockRoleStore := mock.MockRoleStore{}
 
@@ -337,6 +537,13 @@ func getMockRoleStore() mock.MockRoleStore {
 		return errors.New("record not found")
 	}
 
+	mockRoleStore.GetUsersFunc = func(r types.Role) (types.Users, error) {
+		if r.Name == "GetRoleTest" {
+			return types.Users{{ID: "0d5ae8e3-cda1-4d5c-a3da-4f08fbab1b5f", Name: "roleholder"}}, nil
+		}
+		return nil, nil
+	}
+
 	mockRoleStore.RetrieveAllFunc = func(rs *types.RoleSearch) (types.Roles, error) {
 		var resultRoles []types.Role
 		if rs == nil {
@@ -5,11 +5,16 @@
 package controllers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"regexp"
+	"time"
 
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
 	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
 
 	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
@@ -17,6 +22,9 @@ import (
 
 type JwtCertificateController struct {
 	TokenSignCertFile string
+	// PublishedCertFiles lists the signing certificates published in the JWKS, so that tokens signed with the next
+	// or the previous signing key can also be verified. Missing files are skipped.
+	PublishedCertFiles []string
 }
 
 var (
@@ -42,3 +50,48 @@ func (controller JwtCertificateController) GetJwtCertificate(w http.ResponseWrit
 	secLog.Info(commLogMsg.AuthorizedAccess, r.RemoteAddr)
 	return string(tokenCertificate), http.StatusOK, nil
 }
+
+func (controller JwtCertificateController) GetJwks(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to getJwks")
+	defer defaultLog.Trace("getJwks return")
+
+	jwks := jwtauth.JSONWebKeySet{Keys: []jwtauth.JSONWebKey{}}
+	kids := make(map[string]bool)
+	for _, certFile := range append([]string{controller.TokenSignCertFile}, controller.PublishedCertFiles...) {
+		certPem, err := ioutil.ReadFile(certFile)
+		if err != nil {
+			if os.IsNotExist(err) && certFile != controller.TokenSignCertFile {
+				continue
+			}
+			defaultLog.WithError(err).Errorf("controllers/jwt_certificate_controller:GetJwks() Failed to read %s", certFile)
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to read jwt certificate"}
+		}
+		cert, err := crypt.GetCertFromPem(certPem)
+		if err != nil {
+			secLog.Errorf(commLogMsg.UnauthorizedAccess, err.Error())
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Invalid jwt certificate"}
+		}
+		if time.Now().After(cert.NotAfter) {
+			defaultLog.Warnf("controllers/jwt_certificate_controller:GetJwks() Certificate %s is expired", certFile)
+			continue
+		}
+		jwk, err := jwtauth.NewJSONWebKey(certPem)
+		if err != nil {
+			secLog.Errorf(commLogMsg.UnauthorizedAccess, err.Error())
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Invalid jwt certificate"}
+		}
+		if !kids[jwk.Kid] {
+			kids[jwk.Kid] = true
+			jwks.Keys = append(jwks.Keys, *jwk)
+		}
+	}
+
+	jwksBytes, err := json.Marshal(jwks)
+	if err != nil {
+		defaultLog.WithError(err).Error("failed to marshal json response")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "failed to marshal json response"}
+	}
+	secLog.Info(commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return string(jwksBytes), http.StatusOK, nil
+}
@@ -5,6 +5,7 @@
 package controllers_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/controllers"
 	aasRoutes "github.com/intel-secl/intel-secl/v5/pkg/authservice/router"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -62,4 +64,36 @@ var _ = Describe("JwtCertificateController", func() {
 			})
 		})
 	})
+
+	Describe("GetJwks", func() {
+		Context("Validate Get Jwks", func() {
+			It("Should return StatusOK - Keys of the published certificates provided", func() {
+				jwtCertificateController.PublishedCertFiles = []string{"../../../test/aas/jwtsigncert-next.pem", tokenSignCertFile}
+				router.Handle("/jwks", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtCertificateController.GetJwks, "application/json"))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/jwks", nil)
+
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var jwks jwtauth.JSONWebKeySet
+				Expect(json.Unmarshal(w.Body.Bytes(), &jwks)).To(Succeed())
+				Expect(jwks.Keys).To(HaveLen(1))
+				Expect(jwks.Keys[0].Kty).To(Equal("RSA"))
+			})
+
+			It("Should return InternalServerError - Invalid certificate location provided", func() {
+				router.Handle("/jwks", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtCertificateControllerTest.GetJwks, "application/json"))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/jwks", nil)
+
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
 })
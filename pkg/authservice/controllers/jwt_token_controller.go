@@ -5,10 +5,16 @@
 package controllers
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
+	jwtgo "github.com/Waterdrips/jwt-go"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
 	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
 	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
@@ -27,9 +33,30 @@ type roleClaims struct {
 	Permissions []aasModel.PermissionInfo `json:"permissions,omitempty"`
 }
 
+// refreshTokenLength is the number of random bytes of a refresh token
+const refreshTokenLength = 32
+
 type JwtTokenController struct {
 	Database     domain.AASDatabase
 	TokenFactory *jwtauth.JwtFactory
+	// TokenValidity and RefreshTokenValidity are the validity of the issued bearer tokens and refresh tokens
+	TokenValidity        time.Duration
+	RefreshTokenValidity time.Duration
+	// CustomClaimsMaxValidity is the longest validity that can be requested for a custom claims token
+	CustomClaimsMaxValidity time.Duration
+	// MaxTokenValidity is the longest validity of the tokens issued by AAS, the tokens of a changed user are revoked
+	// for this duration
+	MaxTokenValidity time.Duration
+	// TokenVerifier returns the verifier of the tokens to revoke
+	TokenVerifier func() (jwtauth.Verifier, error)
+	// LDAPAuthenticator authenticates the users that are not local users against the LDAP directory when it is set
+	LDAPAuthenticator domain.LDAPAuthenticator
+	// LDAPAutoProvisionUsers creates the LDAP users when they request a token for the first time
+	LDAPAutoProvisionUsers bool
+	// OIDCVerifier verifies the ID tokens of the OpenID Connect provider exchanged for AAS tokens
+	OIDCVerifier domain.OIDCVerifier
+	// OIDCAutoProvisionUsers creates the OpenID Connect users when they request a token for the first time
+	OIDCAutoProvisionUsers bool
 }
 
 func (controller JwtTokenController) CreateJwtToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -37,6 +64,242 @@ func (controller JwtTokenController) CreateJwtToken(w http.ResponseWriter, r *ht
 	defaultLog.Trace("call to createJwtToken")
 	defer defaultLog.Trace("createJwtToken return")
 
+	user, httpStatus, err := controller.authenticateUser(r)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	jwt, httpStatus, err := controller.createUserJwt(*user)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	secLog.Infof("%s: Return JWT token of user [%s] to: %s", commLogMsg.TokenIssued, user.Name, r.RemoteAddr)
+	return jwt, http.StatusOK, nil
+}
+
+// CreateJwtTokenWithRefreshToken authenticates the user like CreateJwtToken and returns the bearer token together
+// with a refresh token, which can be exchanged for a new bearer token once the bearer token has expired
+func (controller JwtTokenController) CreateJwtTokenWithRefreshToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to createJwtTokenWithRefreshToken")
+	defer defaultLog.Trace("createJwtTokenWithRefreshToken return")
+
+	user, httpStatus, err := controller.authenticateUser(r)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	tokenResponse, httpStatus, err := controller.createTokenResponse(*user)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	secLog.Infof("%s: Return JWT token and refresh token of user [%s] to: %s", commLogMsg.TokenIssued, user.Name, r.RemoteAddr)
+	return tokenResponse, http.StatusOK, nil
+}
+
+// CreateOIDCJwtToken exchanges the ID token of the OpenID Connect provider for a bearer token of the user identified
+// by the ID token
+func (controller JwtTokenController) CreateOIDCJwtToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to createOIDCJwtToken")
+	defer defaultLog.Trace("createOIDCJwtToken return")
+
+	user, httpStatus, err := controller.authenticateOIDCUser(r)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	jwt, httpStatus, err := controller.createUserJwt(*user)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	secLog.Infof("%s: Return JWT token of OIDC user [%s] to: %s", commLogMsg.TokenIssued, user.Name, r.RemoteAddr)
+	return jwt, http.StatusOK, nil
+}
+
+// CreateOIDCJwtTokenWithRefreshToken exchanges the ID token like CreateOIDCJwtToken and returns the bearer token
+// together with a refresh token
+func (controller JwtTokenController) CreateOIDCJwtTokenWithRefreshToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to createOIDCJwtTokenWithRefreshToken")
+	defer defaultLog.Trace("createOIDCJwtTokenWithRefreshToken return")
+
+	user, httpStatus, err := controller.authenticateOIDCUser(r)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	tokenResponse, httpStatus, err := controller.createTokenResponse(*user)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	secLog.Infof("%s: Return JWT token and refresh token of OIDC user [%s] to: %s", commLogMsg.TokenIssued, user.Name, r.RemoteAddr)
+	return tokenResponse, http.StatusOK, nil
+}
+
+// RefreshJwtToken exchanges a refresh token for a new bearer token and a new refresh token. The refresh token can
+// only be used once.
+func (controller JwtTokenController) RefreshJwtToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to refreshJwtToken")
+	defer defaultLog.Trace("refreshJwtToken return")
+
+	if r.ContentLength == 0 {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var rtr aasModel.RefreshTokenRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(&rtr)
+	if err != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if rtr.RefreshToken == "" {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "refresh_token is required"}
+	}
+
+	refreshToken, err := controller.retrieveRefreshToken(rtr.RefreshToken)
+	if err != nil {
+		secLog.Warningf("%s: Invalid refresh token, requested from %s: ", commLogMsg.AuthenticationFailed, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "invalid refresh token"}
+	}
+	// deleting the refresh token fails if it was used concurrently
+	if err = controller.Database.RefreshTokenStore().Delete(*refreshToken); err != nil {
+		defaultLog.WithError(err).Error("could not delete used refresh token")
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "invalid refresh token"}
+	}
+	if time.Now().After(refreshToken.ExpiresAt) {
+		secLog.Warningf("%s: Expired refresh token, requested from %s: ", commLogMsg.AuthenticationFailed, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "refresh token is expired"}
+	}
+
+	user, err := controller.Database.UserStore().Retrieve(types.User{ID: refreshToken.UserID})
+	if err != nil {
+		defaultLog.WithError(err).Error("could not retrieve user of refresh token")
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "invalid refresh token"}
+	}
+	// the locked users and the users whose password has expired cannot keep refreshing their tokens
+	if httpStatus, err := authcommon.HttpHandleUserStatus(user); err != nil {
+		secLog.Warningf("%s: Refresh token of user [%s] rejected, requested from %s: %s", commLogMsg.AuthenticationFailed,
+			user.Name, r.RemoteAddr, err.Error())
+		return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	tokenResponse, httpStatus, err := controller.createTokenResponse(*user)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	secLog.Infof("%s: Return refreshed JWT token of user [%s] to: %s", commLogMsg.TokenIssued, user.Name, r.RemoteAddr)
+	return tokenResponse, http.StatusOK, nil
+}
+
+// RevokeToken revokes a bearer token or a refresh token. Like in RFC 7009, the token is not disclosed to be invalid
+// or already revoked.
+func (controller JwtTokenController) RevokeToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to revokeToken")
+	defer defaultLog.Trace("revokeToken return")
+
+	if r.ContentLength == 0 {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var trr aasModel.TokenRevokeRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(&trr)
+	if err != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if trr.Token == "" {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "token is required"}
+	}
+
+	// bearer tokens are JWTs with three parts, refresh tokens are opaque
+	if strings.Count(trr.Token, ".") != 2 {
+		refreshToken, err := controller.retrieveRefreshToken(trr.Token)
+		if err != nil {
+			defaultLog.WithError(err).Info("refresh token to revoke not found")
+			return nil, http.StatusNoContent, nil
+		}
+		if err = controller.Database.RefreshTokenStore().Delete(*refreshToken); err != nil {
+			defaultLog.WithError(err).Info("could not delete revoked refresh token")
+			return nil, http.StatusNoContent, nil
+		}
+		secLog.Infof("%s: Refresh token of user %s revoked by: %s", commLogMsg.PrivilegeModified, refreshToken.UserID, r.RemoteAddr)
+		return nil, http.StatusNoContent, nil
+	}
+
+	verifier, err := controller.TokenVerifier()
+	if err != nil {
+		defaultLog.WithError(err).Error("could not initialize jwt verifier")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
+	}
+	token, err := verifier.ValidateTokenAndGetClaims(trr.Token, &map[string]interface{}{})
+	if err != nil {
+		defaultLog.WithError(err).Info("token to revoke is not valid")
+		return nil, http.StatusNoContent, nil
+	}
+	claims := token.GetStandardClaims().(*jwtgo.StandardClaims)
+	if claims.Id == "" {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "token without jti (token id) can not be revoked"}
+	}
+
+	now := time.Now()
+	_, err = controller.Database.RevokedTokenStore().Create(types.RevokedToken{
+		Jti:       claims.Id,
+		RevokedAt: now,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("could not add token to revocation list")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
+	}
+	if err = controller.Database.RevokedTokenStore().DeleteExpired(now); err != nil {
+		defaultLog.WithError(err).Warn("could not delete expired entries of token revocation list")
+	}
+
+	secLog.Infof("%s: Token %s of %s revoked by: %s", commLogMsg.PrivilegeModified, claims.Id, claims.Subject, r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+// GetTokenRevocations returns the revocation list of the tokens which are not expired yet. Services pass the
+// updated_at time of the list they have as since to retrieve only the tokens revoked after it.
+func (controller JwtTokenController) GetTokenRevocations(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to getTokenRevocations")
+	defer defaultLog.Trace("getTokenRevocations return")
+
+	var since time.Time
+	if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339Nano, sinceParam)
+		if err != nil {
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid since query parameter provided"}
+		}
+	}
+
+	revocations, err := authcommon.RetrieveTokenRevocations(controller.Database, since)
+	if err != nil {
+		defaultLog.WithError(err).Error("could not retrieve token revocations")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve token revocations"}
+	}
+
+	revocationsBytes, err := json.Marshal(revocations)
+	if err != nil {
+		defaultLog.WithError(err).Error("failed to marshal json response")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "failed to marshal json response"}
+	}
+	return string(revocationsBytes), http.StatusOK, nil
+}
+
+func (controller JwtTokenController) authenticateUser(r *http.Request) (*types.User, int, error) {
 	if r.ContentLength == 0 {
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
 	}
@@ -61,28 +324,355 @@ func (controller JwtTokenController) CreateJwtToken(w http.ResponseWriter, r *ht
 
 	u := controller.Database.UserStore()
 
+	// local users, which have a password hash, are always authenticated with their local password
+	if controller.LDAPAuthenticator != nil {
+		if localUser, err := u.Retrieve(types.User{Name: uc.UserName}); err != nil || len(localUser.PasswordHash) == 0 {
+			return controller.authenticateLDAPUser(r, uc)
+		}
+	}
+
 	if httpStatus, err := authcommon.HttpHandleUserAuth(u, uc.UserName, uc.Password); err != nil {
 		secLog.Warningf("%s: User [%s] authentication failed, requested from %s: ", commLogMsg.AuthenticationFailed, uc.UserName, r.RemoteAddr)
 		return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
 	}
 	secLog.Infof("%s: User [%s] authenticated, requested from %s: ", commLogMsg.AuthenticationSuccess, uc.UserName, r.RemoteAddr)
 
-	roles, err := u.GetRoles(types.User{Name: uc.UserName}, nil, false)
+	user, err := u.Retrieve(types.User{Name: uc.UserName})
+	if err != nil || user == nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve user"}
+	}
+	return user, 0, nil
+}
+
+// authenticateLDAPUser authenticates the user against the LDAP directory and grants the user the roles mapped to the
+// LDAP groups of the user. The roles granted earlier through groups the user is not a member of anymore are removed,
+// the roles no LDAP group is mapped to are left to the administrators.
+func (controller JwtTokenController) authenticateLDAPUser(r *http.Request, uc aasModel.UserCred) (*types.User, int, error) {
+	ldapUser, httpStatus, err := authcommon.HttpHandleLDAPUserAuth(controller.LDAPAuthenticator, uc.UserName, uc.Password)
 	if err != nil {
+		secLog.Warningf("%s: LDAP user [%s] authentication failed, requested from %s: ", commLogMsg.AuthenticationFailed, uc.UserName, r.RemoteAddr)
+		return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
+	}
+	secLog.Infof("%s: LDAP user [%s] with DN [%s] authenticated, requested from %s: ", commLogMsg.AuthenticationSuccess, uc.UserName, ldapUser.DN, r.RemoteAddr)
+
+	mappedRoles, err := controller.ldapGroupsRoles(ldapUser.Groups)
+	if err != nil {
+		defaultLog.WithError(err).Error("could not retrieve roles mapped to LDAP groups")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve roles"}
 	}
-	perms, err := u.GetPermissions(types.User{Name: uc.UserName}, nil)
+	if len(mappedRoles) == 0 {
+		secLog.Warningf("%s: LDAP user [%s] is not a member of any group mapped to a role, requested from %s: ", commLogMsg.UnauthorizedAccess, uc.UserName, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "user is not a member of any LDAP group mapped to a role"}
+	}
+
+	ownedRoles, err := controller.ldapMappingsRoles()
 	if err != nil {
-		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve permissions"}
+		defaultLog.WithError(err).Error("could not retrieve roles of LDAP group mappings")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve roles"}
 	}
 
-	jwt, err := controller.TokenFactory.Create(&roleClaims{Roles: roles, Permissions: perms}, uc.UserName, 0)
+	return controller.syncFederatedUser(r, constants.UserSourceLdap, "", uc.UserName, mappedRoles, ownedRoles,
+		controller.LDAPAutoProvisionUsers)
+}
+
+// authenticateOIDCUser verifies the ID token of the request and grants the user identified by the token the roles
+// mapped to the claims of the token. The roles the claims do not grant anymore are removed, the roles no claim is
+// mapped to are left to the administrators.
+func (controller JwtTokenController) authenticateOIDCUser(r *http.Request) (*types.User, int, error) {
+	if r.ContentLength == 0 {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var tr aasModel.OIDCTokenRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(&tr)
 	if err != nil {
-		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate token"}
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+	if tr.IDToken == "" {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "id_token is required"}
 	}
 
-	secLog.Infof("%s: Return JWT token of user [%s] to: %s", commLogMsg.TokenIssued, uc.UserName, r.RemoteAddr)
-	return jwt, http.StatusOK, nil
+	identity, httpStatus, err := authcommon.HttpHandleOIDCUserAuth(controller.OIDCVerifier, tr.IDToken)
+	if err != nil {
+		secLog.Warningf("%s: OIDC user authentication failed, requested from %s: ", commLogMsg.AuthenticationFailed, r.RemoteAddr)
+		return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
+	}
+	if validationErr := validation.ValidateUserNameString(identity.Username); validationErr != nil {
+		secLog.Warningf("%s: OIDC user [%s] has an invalid username, requested from %s: ", commLogMsg.AuthenticationFailed, identity.Subject, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: validationErr.Error()}
+	}
+	secLog.Infof("%s: OIDC user [%s] with subject [%s] authenticated, requested from %s: ", commLogMsg.AuthenticationSuccess, identity.Username, identity.Subject, r.RemoteAddr)
+
+	mappedRoles, err := controller.oidcClaimsRoles(identity.Roles)
+	if err != nil {
+		defaultLog.WithError(err).Error("could not retrieve roles mapped to OIDC claims")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve roles"}
+	}
+	if len(mappedRoles) == 0 {
+		secLog.Warningf("%s: OIDC user [%s] has no claim mapped to a role, requested from %s: ", commLogMsg.UnauthorizedAccess, identity.Username, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "user has no OIDC claim mapped to a role"}
+	}
+
+	ownedRoles, err := controller.oidcClaimsRoles(identity.MappingRoles)
+	if err != nil {
+		defaultLog.WithError(err).Error("could not retrieve roles of OIDC claim mappings")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve roles"}
+	}
+
+	return controller.syncFederatedUser(r, constants.UserSourceOidcPrefix+identity.Issuer, identity.Subject,
+		identity.Username, mappedRoles, ownedRoles, controller.OIDCAutoProvisionUsers)
+}
+
+// oidcClaimsRoles retrieves the roles mapped to the claims of the ID token, the roles that do not exist are skipped
+func (controller JwtTokenController) oidcClaimsRoles(roleInfos []aasModel.RoleInfo) (types.Roles, error) {
+	var roles types.Roles
+	for _, roleInfo := range roleInfos {
+		role, err := controller.Database.RoleStore().Retrieve(&types.RoleSearch{RoleInfo: roleInfo, AllContexts: false})
+		if err != nil {
+			if strings.Contains(err.Error(), commErr.RecordNotFound) {
+				defaultLog.Warnf("role %s:%s of OIDC claim mapping not found", roleInfo.Service, roleInfo.Name)
+				continue
+			}
+			return nil, err
+		}
+		if role != nil {
+			roles = append(roles, *role)
+		}
+	}
+	return roles, nil
+}
+
+// ldapGroupsRoles retrieves the roles mapped to the LDAP groups
+func (controller JwtTokenController) ldapGroupsRoles(groups []string) (types.Roles, error) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	mappings, err := controller.Database.LDAPGroupMappingStore().RetrieveAll(groups)
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	var roleIDs []string
+	for _, mapping := range mappings {
+		roleIDs = append(roleIDs, mapping.RoleID)
+	}
+	return controller.Database.RoleStore().RetrieveAll(&types.RoleSearch{
+		IDFilter:    roleIDs,
+		AllContexts: true,
+	})
+}
+
+// ldapMappingsRoles retrieves the roles of all the LDAP group mappings, the roles granted and removed through the
+// LDAP groups
+func (controller JwtTokenController) ldapMappingsRoles() (types.Roles, error) {
+	mappings, err := controller.Database.LDAPGroupMappingStore().RetrieveAll(nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	var roleIDs []string
+	for _, mapping := range mappings {
+		roleIDs = append(roleIDs, mapping.RoleID)
+	}
+	return controller.Database.RoleStore().RetrieveAll(&types.RoleSearch{
+		IDFilter:    roleIDs,
+		AllContexts: true,
+	})
+}
+
+// syncFederatedUser provisions the user authenticated by an external identity provider and synchronizes the roles
+// the mappings of the identity provider own: the mapped roles are granted and the owned roles that are not mapped
+// anymore are removed, the other roles granted by the administrators are kept. The users with a subject are
+// identified by their source and subject, their username is used only to provision them, the other users by their
+// source and username. The tokens of the user are revoked when a role is removed.
+func (controller JwtTokenController) syncFederatedUser(r *http.Request, source, subject, username string,
+	mappedRoles, ownedRoles types.Roles, autoProvision bool) (*types.User, int, error) {
+	u := controller.Database.UserStore()
+	filter := types.User{Name: username}
+	if subject != "" {
+		filter = types.User{Source: source, Subject: subject}
+	}
+	user, err := u.Retrieve(filter)
+	if err != nil {
+		if !strings.Contains(err.Error(), commErr.RecordNotFound) {
+			defaultLog.WithError(err).Error("could not retrieve federated user")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve user"}
+		}
+		if !autoProvision {
+			secLog.Warningf("%s: Federated user [%s] is not provisioned, requested from %s: ", commLogMsg.UnauthorizedAccess, username, r.RemoteAddr)
+			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "user is not provisioned"}
+		}
+		if subject != "" {
+			// the username is the subject of the AAS tokens, it must not be the name of another user
+			_, err = u.Retrieve(types.User{Name: username})
+			if err == nil {
+				secLog.Warningf("%s: Federated user [%s] with subject [%s] has the username of another user, requested from %s: ", commLogMsg.UnauthorizedAccess, username, subject, r.RemoteAddr)
+				return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "username is used by another user"}
+			}
+			if !strings.Contains(err.Error(), commErr.RecordNotFound) {
+				defaultLog.WithError(err).Error("could not retrieve user")
+				return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve user"}
+			}
+		}
+		user, err = u.Create(types.User{Name: username, Source: source, Subject: subject})
+		if err != nil {
+			defaultLog.WithError(err).Error("could not provision federated user")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to create user"}
+		}
+		secLog.WithField("user", user.Name).Infof("%s: Federated user provisioned, requested from: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	} else if len(user.PasswordHash) != 0 {
+		// the local users authenticate with their password, the identity provider cannot grant their roles
+		secLog.Warningf("%s: Federated user [%s] is a local user, requested from %s: ", commLogMsg.UnauthorizedAccess, username, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "user is a local user"}
+	} else if user.Source != source {
+		if user.Source != "" || !controller.singleIdentityProvider() {
+			secLog.Warningf("%s: Federated user [%s] of source [%s] belongs to another identity provider, requested from %s: ", commLogMsg.UnauthorizedAccess, username, source, r.RemoteAddr)
+			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "user belongs to another identity provider"}
+		}
+		// the federated users provisioned before the sources were recorded belong to the only identity provider
+		user.Source = source
+		if err = u.Update(*user); err != nil {
+			defaultLog.WithError(err).Error("could not update source of federated user")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to update user"}
+		}
+		secLog.WithField("user", user.Name).Infof("%s: Source of federated user set to [%s], requested from: %s", commLogMsg.PrivilegeModified, source, r.RemoteAddr)
+	}
+
+	userRoles, err := u.GetRoles(types.User{Name: user.Name}, nil, true)
+	if err != nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve roles"}
+	}
+	var rolesToAdd types.Roles
+	for _, role := range mappedRoles {
+		if !containsRole(userRoles, role.ID) {
+			rolesToAdd = append(rolesToAdd, role)
+		}
+	}
+	rolesRemoved := false
+	for _, role := range userRoles {
+		if containsRole(mappedRoles, role.ID) || !containsRole(ownedRoles, role.ID) {
+			continue
+		}
+		if err = u.DeleteRole(*user, role.ID, nil); err != nil {
+			defaultLog.WithError(err).Error("could not delete role of federated user")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to update user roles"}
+		}
+		rolesRemoved = true
+	}
+	if len(rolesToAdd) > 0 {
+		if err = u.AddRoles(*user, rolesToAdd, true); err != nil {
+			defaultLog.WithError(err).Error("could not add roles to federated user")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to update user roles"}
+		}
+	}
+	if rolesRemoved {
+		// the tokens issued earlier grant the roles the identity provider does not grant anymore
+		if err = authcommon.RevokeUserTokens(controller.Database, *user, controller.MaxTokenValidity); err != nil {
+			defaultLog.WithError(err).Error("could not revoke tokens of federated user")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "user roles changed but tokens could not be revoked"}
+		}
+	}
+	if rolesRemoved || len(rolesToAdd) > 0 {
+		secLog.WithField("user", user.Name).Infof("%s: Roles of federated user updated from identity provider", commLogMsg.PrivilegeModified)
+	}
+	return user, 0, nil
+}
+
+// singleIdentityProvider returns true when the users are authenticated by one external identity provider only
+func (controller JwtTokenController) singleIdentityProvider() bool {
+	return (controller.LDAPAuthenticator != nil) != (controller.OIDCVerifier != nil)
+}
+
+func containsRole(roles []types.Role, roleID string) bool {
+	for _, role := range roles {
+		if role.ID == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+func (controller JwtTokenController) createUserJwt(user types.User) (string, int, error) {
+	u := controller.Database.UserStore()
+
+	roles, err := u.GetRoles(types.User{Name: user.Name}, nil, false)
+	if err != nil {
+		return "", http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve roles"}
+	}
+	perms, err := u.GetPermissions(types.User{Name: user.Name}, nil)
+	if err != nil {
+		return "", http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve permissions"}
+	}
+
+	jwt, err := controller.TokenFactory.Create(&roleClaims{Roles: roles, Permissions: perms}, user.Name, 0)
+	if err != nil {
+		return "", http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate token"}
+	}
+	return jwt, 0, nil
+}
+
+func (controller JwtTokenController) createTokenResponse(user types.User) (interface{}, int, error) {
+	jwt, httpStatus, err := controller.createUserJwt(user)
+	if err != nil {
+		return nil, httpStatus, err
+	}
+
+	refreshTokenBytes, err := crypt.GetRandomBytes(refreshTokenLength)
+	if err != nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate refresh token"}
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(refreshTokenBytes)
+	tokenHash, err := crypt.GetHashData([]byte(refreshToken), constants.HashingAlgorithm)
+	if err != nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate refresh token"}
+	}
+
+	now := time.Now()
+	_, err = controller.Database.RefreshTokenStore().Create(types.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(controller.RefreshTokenValidity),
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("could not store refresh token")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to store refresh token"}
+	}
+	if err = controller.Database.RefreshTokenStore().DeleteExpired(now); err != nil {
+		defaultLog.WithError(err).Warn("could not delete expired refresh tokens")
+	}
+
+	tokenResponseBytes, err := json.Marshal(aasModel.TokenResponse{
+		AccessToken:  jwt,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(controller.TokenValidity.Seconds()),
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("failed to marshal json response")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "failed to marshal json response"}
+	}
+	return string(tokenResponseBytes), 0, nil
+}
+
+func (controller JwtTokenController) retrieveRefreshToken(refreshToken string) (*types.RefreshToken, error) {
+	tokenHash, err := crypt.GetHashData([]byte(refreshToken), constants.HashingAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	storedToken, err := controller.Database.RefreshTokenStore().Retrieve(tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if storedToken == nil {
+		return nil, fmt.Errorf("refresh token not found")
+	}
+	return storedToken, nil
 }
 
 func (controller JwtTokenController) CreateCustomClaimsJwtToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -107,6 +697,10 @@ func (controller JwtTokenController) CreateCustomClaimsJwtToken(w http.ResponseW
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid subject provided"}
 	}
 
+	if cc.ValiditySecs < 0 || time.Duration(cc.ValiditySecs)*time.Second > controller.CustomClaimsMaxValidity {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid token validity provided"}
+	}
+
 	jwt, err := controller.TokenFactory.Create(&cc.Claims, cc.Subject, time.Duration(cc.ValiditySecs)*time.Second)
 	if err != nil {
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate token"}
@@ -115,3 +709,57 @@ func (controller JwtTokenController) CreateCustomClaimsJwtToken(w http.ResponseW
 	secLog.Infof("%s: Created custom claims for user/subject %s with token valid for %d seconds", commLogMsg.TokenIssued, cc.Subject, cc.ValiditySecs)
 	return jwt, http.StatusOK, nil
 }
+
+// RenewCustomClaimsJwtToken issues a new token with the claims and the validity of the custom claims token of the
+// request, so that the services bootstrapped with a custom claims token, such as the trust agent, renew it before it
+// expires. The user tokens are not renewed, the users authenticate again.
+func (controller JwtTokenController) RenewCustomClaimsJwtToken(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to renewCustomClaimsJwtToken")
+	defer defaultLog.Trace("renewCustomClaimsJwtToken return")
+
+	splitAuthHeader := strings.Split(r.Header.Get("Authorization"), "Bearer ")
+	if len(splitAuthHeader) <= 1 {
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "bearer token is required"}
+	}
+	verifier, err := controller.TokenVerifier()
+	if err != nil {
+		defaultLog.WithError(err).Error("could not initialize jwt verifier")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
+	}
+	claims := map[string]interface{}{}
+	token, err := verifier.ValidateTokenAndGetClaims(strings.TrimSpace(splitAuthHeader[1]), &claims)
+	if err != nil {
+		defaultLog.WithError(err).Info("token to renew is not valid")
+		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "invalid token"}
+	}
+	standardClaims := token.GetStandardClaims().(*jwtgo.StandardClaims)
+
+	_, err = controller.Database.UserStore().Retrieve(types.User{Name: standardClaims.Subject})
+	if err == nil {
+		secLog.Warningf("%s: Token renewal of user %s rejected, requested from %s", commLogMsg.UnauthorizedAccess, standardClaims.Subject, r.RemoteAddr)
+		return nil, http.StatusForbidden, &commErr.ResourceError{Message: "user tokens can not be renewed"}
+	}
+	if !strings.Contains(err.Error(), commErr.RecordNotFound) {
+		defaultLog.WithError(err).Error("could not retrieve user")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Database error: unable to retrieve user"}
+	}
+
+	// the issued at time of the tokens is set back by the clock skew grace period of less than a minute
+	validity := time.Unix(standardClaims.ExpiresAt, 0).Sub(time.Unix(standardClaims.IssuedAt, 0)).Truncate(time.Minute)
+	if validity <= 0 || validity > controller.CustomClaimsMaxValidity {
+		validity = controller.CustomClaimsMaxValidity
+	}
+	// the standard claims are set again by the token factory
+	for _, claim := range []string{"aud", "exp", "iat", "iss", "jti", "nbf", "sub"} {
+		delete(claims, claim)
+	}
+
+	jwt, err := controller.TokenFactory.Create(&claims, standardClaims.Subject, validity)
+	if err != nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "could not generate token"}
+	}
+
+	secLog.Infof("%s: Renewed custom claims token %s for subject %s with token valid for %d seconds", commLogMsg.TokenIssued, standardClaims.Id, standardClaims.Subject, int64(validity.Seconds()))
+	return jwt, http.StatusOK, nil
+}
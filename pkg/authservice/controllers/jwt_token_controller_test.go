@@ -5,21 +5,41 @@
 package controllers_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"log"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/Waterdrips/jwt-go"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	comm "github.com/intel-secl/intel-secl/v5/pkg/authservice/common"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/ldap"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/oidc"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/postgres/mock"
 	aasRoutes "github.com/intel-secl/intel-secl/v5/pkg/authservice/router"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/bcrypt"
 )
 
 var _ = Describe("JwtTokenController", func() {
@@ -29,20 +49,316 @@ var _ = Describe("JwtTokenController", func() {
 	var jwtController controllers.JwtTokenController
 	// mock database
 	mockDatabase := &mock.MockDatabase{
-		MockUserStore:       getMockUserStore(),
-		MockRoleStore:       getMockRoleStore(),
-		MockPermissionStore: getPermissionStore(),
+		MockUserStore:         getMockUserStore(),
+		MockRoleStore:         getMockRoleStore(),
+		MockPermissionStore:   getPermissionStore(),
+		MockRevokedTokenStore: getMockRevokedTokenStore(),
+		MockRefreshTokenStore: getMockRefreshTokenStore(),
 	}
 	comm.InitDefender(5, 5, 15)
+	revokeTokenFactory, revokeTokenVerifier := getRevokeTokenFactoryAndVerifier()
 
 	BeforeEach(func() {
 		router = mux.NewRouter()
 		jwtController = controllers.JwtTokenController{
-			Database:     mockDatabase,
-			TokenFactory: tokenFactory,
+			Database:                mockDatabase,
+			TokenFactory:            tokenFactory,
+			TokenValidity:           10 * time.Minute,
+			RefreshTokenValidity:    time.Hour,
+			CustomClaimsMaxValidity: 365 * 24 * time.Hour,
+			TokenVerifier:           revokeTokenVerifier,
 		}
 	})
 
+	// requestTokenResponse requests a bearer token and a refresh token for the test user
+	requestTokenResponse := func() aas.TokenResponse {
+		router.Handle("/token", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.CreateJwtTokenWithRefreshToken,
+			"application/json"))).Methods(http.MethodPost)
+		usercred := `{
+			"username":"testusername",
+			"password":"testAdminPassword"
+			}`
+		req, err := http.NewRequest(http.MethodPost, "/token", strings.NewReader(usercred))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusOK))
+
+		var tokenResponse aas.TokenResponse
+		Expect(json.Unmarshal(w.Body.Bytes(), &tokenResponse)).To(Succeed())
+		return tokenResponse
+	}
+
+	Describe("CreateJwtTokenWithRefreshToken", func() {
+		Context("Validate CreateJwtTokenWithRefreshToken with valid username and password", func() {
+			It("Should return StatusOK - Valid request should create JwtToken and refresh token", func() {
+				tokenResponse := requestTokenResponse()
+				Expect(tokenResponse.AccessToken).NotTo(BeEmpty())
+				Expect(tokenResponse.RefreshToken).NotTo(BeEmpty())
+				Expect(tokenResponse.TokenType).To(Equal("Bearer"))
+				Expect(tokenResponse.ExpiresIn).To(Equal(600))
+			})
+		})
+		Context("Validate CreateJwtTokenWithRefreshToken with invalid username and password", func() {
+			It("Should return StatusUnauthorized - Invalid username and password provided", func() {
+				router.Handle("/token", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.CreateJwtTokenWithRefreshToken,
+					"application/json"))).Methods(http.MethodPost)
+				usercred := `{
+					"username":"testusername",
+					"password":"testpassword"
+					}`
+				req, err := http.NewRequest(http.MethodPost, "/token", strings.NewReader(usercred))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("RefreshJwtToken", func() {
+		refreshToken := func(refreshToken string) *httptest.ResponseRecorder {
+			router.Handle("/token/refresh", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.RefreshJwtToken,
+				"application/json"))).Methods(http.MethodPost)
+			req, err := http.NewRequest(http.MethodPost, "/token/refresh",
+				strings.NewReader(`{"refresh_token":"`+refreshToken+`"}`))
+			Expect(err).NotTo(HaveOccurred())
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("Validate RefreshJwtToken with valid refresh token", func() {
+			It("Should return StatusOK - Refresh token should be exchanged only once", func() {
+				tokenResponse := requestTokenResponse()
+
+				w = refreshToken(tokenResponse.RefreshToken)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var refreshedResponse aas.TokenResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &refreshedResponse)).To(Succeed())
+				Expect(refreshedResponse.AccessToken).NotTo(BeEmpty())
+				Expect(refreshedResponse.RefreshToken).NotTo(Equal(tokenResponse.RefreshToken))
+
+				w = refreshToken(tokenResponse.RefreshToken)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+		Context("Validate RefreshJwtToken with unknown refresh token", func() {
+			It("Should return StatusUnauthorized - Unknown refresh token provided", func() {
+				w = refreshToken("unknown")
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+		Context("Validate RefreshJwtToken with empty request", func() {
+			It("Should return StatusBadRequest - Empty request body provided", func() {
+				router.Handle("/token/refresh", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.RefreshJwtToken,
+					"application/json"))).Methods(http.MethodPost)
+				req, err := http.NewRequest(http.MethodPost, "/token/refresh", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("RevokeToken", func() {
+		revokeToken := func(token string) *httptest.ResponseRecorder {
+			router.Handle("/token/revoke", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(aasRoutes.ResponseHandler(
+				jwtController.RevokeToken, ""), []string{constants.TokenRevoke}))).Methods(http.MethodPost)
+			req, err := http.NewRequest(http.MethodPost, "/token/revoke", strings.NewReader(`{"token":"`+token+`"}`))
+			Expect(err).NotTo(HaveOccurred())
+			req = context.SetUserPermissions(req, []aas.PermissionInfo{{
+				Service: constants.ServiceName,
+				Rules:   []string{constants.TokenRevoke + ":*"},
+			}})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("Validate RevokeToken with refresh token", func() {
+			It("Should return StatusNoContent - Revoked refresh token should not be exchanged", func() {
+				tokenResponse := requestTokenResponse()
+				w = revokeToken(tokenResponse.RefreshToken)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				router.Handle("/token/refresh", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.RefreshJwtToken,
+					"application/json"))).Methods(http.MethodPost)
+				req, err := http.NewRequest(http.MethodPost, "/token/refresh",
+					strings.NewReader(`{"refresh_token":"`+tokenResponse.RefreshToken+`"}`))
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+		Context("Validate RevokeToken with bearer token", func() {
+			It("Should return StatusNoContent - Bearer token should be added to the revocation list", func() {
+				token, err := revokeTokenFactory.Create(map[string]string{"name": "testusername"}, "testusername", 0)
+				Expect(err).NotTo(HaveOccurred())
+				w = revokeToken(token)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				revocations, err := comm.RetrieveTokenRevocations(mockDatabase, time.Time{})
+				Expect(err).NotTo(HaveOccurred())
+				verifier, err := revokeTokenVerifier()
+				Expect(err).NotTo(HaveOccurred())
+				parsedToken, err := verifier.ValidateTokenAndGetClaims(token, &map[string]interface{}{})
+				Expect(err).NotTo(HaveOccurred())
+				jti := parsedToken.GetStandardClaims().(*jwt.StandardClaims).Id
+				Expect(revocations.RevokedTokens).To(ContainElement(HaveField("Jti", jti)))
+			})
+		})
+		Context("Validate RetrieveTokenRevocations with a revocation committed after a later one", func() {
+			It("Should retrieve the revocation created before since within the overlap", func() {
+				revokedToken, err := mockDatabase.MockRevokedTokenStore.Create(types.RevokedToken{Jti: "late-jti",
+					RevokedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)})
+				Expect(err).NotTo(HaveOccurred())
+
+				since := revokedToken.CreatedAt.Add(constants.TokenRevocationsOverlap / 2)
+				revocations, err := comm.RetrieveTokenRevocations(mockDatabase, since)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(revocations.RevokedTokens).To(ContainElement(HaveField("Jti", "late-jti")))
+				Expect(revocations.UpdatedAt).To(Equal(since))
+			})
+		})
+		Context("Validate RevokeToken with unknown token", func() {
+			It("Should return StatusNoContent - Unknown token is not disclosed", func() {
+				w = revokeToken("unknown")
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+		Context("Validate RevokeToken with empty token", func() {
+			It("Should return StatusBadRequest - Token is required", func() {
+				w = revokeToken("")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Validate RevokeToken without the revoke permission", func() {
+			It("Should return StatusUnauthorized - Insufficient privileges", func() {
+				router.Handle("/token/revoke", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(aasRoutes.ResponseHandler(
+					jwtController.RevokeToken, ""), []string{constants.TokenRevoke}))).Methods(http.MethodPost)
+				req, err := http.NewRequest(http.MethodPost, "/token/revoke", strings.NewReader(`{"token":"unknown"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.CustomClaimsCreate},
+				}})
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("RenewCustomClaimsJwtToken", func() {
+		customClaimsRenewer := aas.PermissionInfo{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.CustomClaimsRenew + ":*"},
+		}
+		renewToken := func(token string) *httptest.ResponseRecorder {
+			router.Handle("/custom-claims-token/renew", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(aasRoutes.ResponseHandler(
+				jwtController.RenewCustomClaimsJwtToken, "application/jwt"), []string{constants.CustomClaimsRenew}))).Methods(http.MethodPost)
+			req, err := http.NewRequest(http.MethodPost, "/custom-claims-token/renew", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", "Bearer "+token)
+			req = context.SetUserPermissions(req, []aas.PermissionInfo{customClaimsRenewer})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		Context("Validate RenewCustomClaimsJwtToken with custom claims token", func() {
+			It("Should return StatusOK - Token with the same subject, claims and validity should be issued", func() {
+				claims := map[string]interface{}{"permissions": []aas.PermissionInfo{customClaimsRenewer}}
+				token, err := revokeTokenFactory.Create(claims, "00000000-8887-0f15-0106-1024a5a5a5a5", 24*time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				// the renewed token is verified with the verifier of the token factory
+				jwtController.TokenFactory = revokeTokenFactory
+				w = renewToken(token)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				verifier, err := revokeTokenVerifier()
+				Expect(err).NotTo(HaveOccurred())
+				var renewedClaims struct {
+					Permissions []aas.PermissionInfo `json:"permissions"`
+				}
+				renewedToken, err := verifier.ValidateTokenAndGetClaims(w.Body.String(), &renewedClaims)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(renewedClaims.Permissions).To(Equal([]aas.PermissionInfo{customClaimsRenewer}))
+				standardClaims := renewedToken.GetStandardClaims().(*jwt.StandardClaims)
+				Expect(standardClaims.Subject).To(Equal("00000000-8887-0f15-0106-1024a5a5a5a5"))
+				Expect(time.Unix(standardClaims.ExpiresAt, 0)).To(BeTemporally("~", time.Now().Add(24*time.Hour), time.Minute))
+			})
+		})
+		Context("Validate RenewCustomClaimsJwtToken with user token", func() {
+			It("Should return StatusForbidden - User tokens should not be renewed", func() {
+				token, err := revokeTokenFactory.Create(map[string]string{"name": "testusername"}, "testusername", 0)
+				Expect(err).NotTo(HaveOccurred())
+				w = renewToken(token)
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+		Context("Validate RenewCustomClaimsJwtToken without bearer token", func() {
+			It("Should return StatusUnauthorized - Token to renew is required", func() {
+				w = renewToken("")
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GetTokenRevocations", func() {
+		tokenRevocationReader := aas.PermissionInfo{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.TokenRevocationRetrieve + ":*"},
+		}
+
+		Context("Validate GetTokenRevocations with valid request", func() {
+			It("Should return StatusOK - Token revocation list should be returned", func() {
+				router.Handle("/token/revocations", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(aasRoutes.ResponseHandler(
+					jwtController.GetTokenRevocations, "application/json"), []string{constants.TokenRevocationRetrieve}))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/token/revocations?since="+
+					url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339Nano)), nil)
+				Expect(err).NotTo(HaveOccurred())
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{tokenRevocationReader})
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var revocations jwtauth.TokenRevocations
+				Expect(json.Unmarshal(w.Body.Bytes(), &revocations)).To(Succeed())
+				Expect(revocations.RevokedTokens).NotTo(BeNil())
+			})
+		})
+		Context("Validate GetTokenRevocations without the retrieve permission", func() {
+			It("Should return StatusUnauthorized - Insufficient privileges", func() {
+				router.Handle("/token/revocations", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(aasRoutes.ResponseHandler(
+					jwtController.GetTokenRevocations, "application/json"), []string{constants.TokenRevocationRetrieve}))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/token/revocations", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{})
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+		Context("Validate GetTokenRevocations with invalid since", func() {
+			It("Should return StatusBadRequest - Invalid since provided", func() {
+				router.Handle("/token/revocations", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(aasRoutes.ResponseHandler(
+					jwtController.GetTokenRevocations, "application/json"), []string{constants.TokenRevocationRetrieve}))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/token/revocations?since=yesterday", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{tokenRevocationReader})
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
 	Describe("CreateJwtToken", func() {
 		// Empty username and password
 		Context("Validate CreateJwtToken with empty request", func() {
@@ -271,6 +587,33 @@ var _ = Describe("JwtTokenController", func() {
 			})
 		})
 
+		Context("Validate CreateCustomClaimsJwtToken with a validity above the maximum", func() {
+			It("Should return StatusBadRequest - Invalid token validity provided", func() {
+				router.Handle("/custom-claims-token", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(
+					aasRoutes.ResponseHandler(jwtController.CreateCustomClaimsJwtToken, "application/jwt"), []string{constants.CustomClaimsCreate}))).Methods(http.MethodPost)
+
+				usercred := `{
+								"subject": "test_user",
+								"validity_seconds": 31536001,
+								"claims": {}
+							}`
+
+				req, err := http.NewRequest(http.MethodPost, "/custom-claims-token", strings.NewReader(usercred))
+
+				permissions := aas.PermissionInfo{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.CustomClaimsCreate},
+				}
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{permissions})
+
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
 		Context("Validate CreateCustomClaimsJwtToken with invalid request", func() {
 			It("Should return StatusBadRequest - Invalid subject provided", func() {
 				router.Handle("/custom-claims-token", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(
@@ -306,3 +649,571 @@ var _ = Describe("JwtTokenController", func() {
 		})
 	})
 })
+
+// stubLDAPAuthenticator authenticates the users of an in-memory directory
+type stubLDAPAuthenticator struct {
+	passwords map[string]string
+	groups    map[string][]string
+	err       error
+	calls     int
+}
+
+func (a *stubLDAPAuthenticator) Authenticate(username, password string) (*types.LDAPUser, error) {
+	a.calls++
+	if a.err != nil {
+		return nil, a.err
+	}
+	if storedPassword, ok := a.passwords[username]; !ok || storedPassword != password {
+		return nil, ldap.ErrInvalidCredentials
+	}
+	return &types.LDAPUser{
+		DN:     "uid=" + username + ",ou=people,dc=example,dc=com",
+		Groups: a.groups[username],
+	}, nil
+}
+
+var _ = Describe("JwtTokenController with LDAP users", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var mockDatabase *mock.MockDatabase
+	var authenticator *stubLDAPAuthenticator
+	var jwtController controllers.JwtTokenController
+
+	const ldapPassword = "ldapUserPassword"
+	ldapRoles := types.Roles{
+		{ID: "7e1f0a52-6c1e-4a55-9f0e-2b1d7b3c4d01", RoleInfo: aas.RoleInfo{Service: "KBS", Name: "KeyCRUD"}},
+		{ID: "7e1f0a52-6c1e-4a55-9f0e-2b1d7b3c4d02", RoleInfo: aas.RoleInfo{Service: "HVS", Name: "ReportSearcher"}},
+	}
+	comm.InitDefender(5, 5, 15)
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		mockDatabase = getMockUsersDatabase(ldapRoles)
+		_, err := mockDatabase.MockLDAPGroupMappingStore.Create(types.LDAPGroupMapping{
+			GroupDN: "cn=kbs-admins,ou=groups,dc=example,dc=com",
+			RoleID:  ldapRoles[0].ID,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		authenticator = &stubLDAPAuthenticator{
+			passwords: map[string]string{"ldapuser": ldapPassword},
+			groups:    map[string][]string{"ldapuser": {"CN=kbs-admins,OU=groups,DC=example,DC=com"}},
+		}
+		jwtController = controllers.JwtTokenController{
+			Database:               mockDatabase,
+			TokenFactory:           tokenFactory,
+			TokenValidity:          10 * time.Minute,
+			LDAPAuthenticator:      authenticator,
+			LDAPAutoProvisionUsers: true,
+		}
+		router.Handle("/token", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+			return jwtController.CreateJwtToken(w, r)
+		}, "application/jwt"))).Methods(http.MethodPost)
+	})
+
+	requestToken := func(username, password string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/token",
+			strings.NewReader(`{"username":"`+username+`","password":"`+password+`"}`))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Context("Validate CreateJwtToken with LDAP user member of mapped group", func() {
+		It("Should return StatusOK - User should be provisioned with the mapped roles", func() {
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).NotTo(BeEmpty())
+
+			Expect(mockDatabase.MockUserStore.UserStore).To(HaveLen(1))
+			user := mockDatabase.MockUserStore.UserStore[0]
+			Expect(user.Name).To(Equal("ldapuser"))
+			Expect(user.Source).To(Equal("ldap"))
+			Expect(user.PasswordHash).To(BeEmpty())
+			Expect(user.Roles).To(HaveLen(1))
+			Expect(user.Roles[0].ID).To(Equal(ldapRoles[0].ID))
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP user not provisioned", func() {
+		It("Should return StatusUnauthorized - User should not be provisioned without auto provisioning", func() {
+			jwtController.LDAPAutoProvisionUsers = false
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP user and invalid password", func() {
+		It("Should return StatusUnauthorized - Invalid password provided", func() {
+			w = requestToken("ldapuser", "wrongPassword")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP user not member of mapped group", func() {
+		It("Should return StatusUnauthorized - User without mapped roles should not get a token", func() {
+			authenticator.groups["ldapuser"] = []string{"cn=hvs-admins,ou=groups,dc=example,dc=com"}
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP directory not available", func() {
+		It("Should return StatusServiceUnavailable - Directory errors are not authentication failures", func() {
+			authenticator.err = errors.New("Could not connect to the LDAP directory")
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+	})
+	Context("Validate CreateJwtToken with local user", func() {
+		It("Should return StatusOK - Local users should not be authenticated against the directory", func() {
+			localPasswordHash, err := bcrypt.GenerateFromPassword([]byte(ldapPassword), bcrypt.MinCost)
+			Expect(err).NotTo(HaveOccurred())
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:           uuid.NewString(),
+				Name:         "ldapuser",
+				PasswordHash: localPasswordHash,
+				PasswordCost: bcrypt.MinCost,
+				Roles:        ldapRoles[1:],
+			}}
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(authenticator.calls).To(Equal(0))
+			Expect(mockDatabase.MockUserStore.UserStore[0].Roles[0].ID).To(Equal(ldapRoles[1].ID))
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP user removed from group", func() {
+		It("Should return StatusOK - Role of the group should be removed and tokens revoked", func() {
+			_, err := mockDatabase.MockLDAPGroupMappingStore.Create(types.LDAPGroupMapping{
+				GroupDN: "cn=hvs-admins,ou=groups,dc=example,dc=com",
+				RoleID:  ldapRoles[1].ID,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:     uuid.NewString(),
+				Name:   "ldapuser",
+				Source: "ldap",
+				Roles:  ldapRoles,
+			}}
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			revokedTokens, err := mockDatabase.MockRevokedTokenStore.RetrieveAll(time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revokedTokens).To(HaveLen(1))
+			Expect(revokedTokens[0].Subject).To(Equal("ldapuser"))
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP user granted role not mapped to any group", func() {
+		It("Should return StatusOK - Role granted by the administrators should be kept", func() {
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:     uuid.NewString(),
+				Name:   "ldapuser",
+				Source: "ldap",
+				Roles:  ldapRoles,
+			}}
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			revokedTokens, err := mockDatabase.MockRevokedTokenStore.RetrieveAll(time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revokedTokens).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP user provisioned by other identity provider", func() {
+		It("Should return StatusUnauthorized - User of another identity provider should not be granted roles", func() {
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:     uuid.NewString(),
+				Name:   "ldapuser",
+				Source: "oidc:https://idp.example.com/realms/isecl",
+			}}
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore[0].Roles).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateJwtToken with LDAP user provisioned without source", func() {
+		It("Should return StatusOK - User should be assigned to the only identity provider", func() {
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:   uuid.NewString(),
+				Name: "ldapuser",
+			}}
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockDatabase.MockUserStore.UserStore[0].Source).To(Equal("ldap"))
+		})
+		It("Should return StatusUnauthorized - User should not be assigned with several identity providers", func() {
+			jwtController.OIDCVerifier = &stubOIDCVerifier{}
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:   uuid.NewString(),
+				Name: "ldapuser",
+			}}
+			w = requestToken("ldapuser", ldapPassword)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore[0].Source).To(BeEmpty())
+		})
+	})
+})
+
+// getRevokeTokenFactoryAndVerifier creates a token factory with a matching verifier for the tokens to revoke
+func getRevokeTokenFactoryAndVerifier() (*jwtauth.JwtFactory, func() (jwtauth.Verifier, error)) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		log.Fatalf("Failed to generate KeyPair %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "AAS JWT Signing Certificate"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	certDer, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		log.Fatalf("Failed to CreateCertificate %v", err)
+	}
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDer})
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		log.Fatalf("Failed to marshal PKCS8 KeyPair %v", err)
+	}
+
+	factory, err := jwtauth.NewTokenFactory(keyDer, true, certPem, "AAS JWT Issuer", time.Minute)
+	if err != nil {
+		log.Fatalf("Failed to create JWTTokenFactory %v", err)
+	}
+	return factory, func() (jwtauth.Verifier, error) {
+		return jwtauth.NewVerifier(certPem, nil, time.Minute)
+	}
+}
+
+// stubOIDCVerifier verifies the ID tokens of an in-memory provider
+type stubOIDCVerifier struct {
+	identities map[string]types.OIDCIdentity
+	err        error
+}
+
+func (v *stubOIDCVerifier) Verify(idToken string) (*types.OIDCIdentity, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	identity, ok := v.identities[idToken]
+	if !ok {
+		return nil, oidc.ErrInvalidToken
+	}
+	return &identity, nil
+}
+
+var _ = Describe("JwtTokenController with OIDC users", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var mockDatabase *mock.MockDatabase
+	var verifier *stubOIDCVerifier
+	var jwtController controllers.JwtTokenController
+
+	oidcRoles := types.Roles{
+		{ID: "9a4c2b1e-0d6f-4f3a-8c7b-5e2d1a0f9b01", RoleInfo: aas.RoleInfo{Service: "KBS", Name: "KeyCRUD"}},
+		{ID: "9a4c2b1e-0d6f-4f3a-8c7b-5e2d1a0f9b02", RoleInfo: aas.RoleInfo{Service: "HVS", Name: "ReportSearcher"}},
+	}
+	const oidcIssuer = "https://idp.example.com/realms/isecl"
+	oidcMappingRoles := []aas.RoleInfo{oidcRoles[0].RoleInfo, {Service: "WLS", Name: "UnknownRole"}}
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		mockDatabase = getMockUsersDatabase(oidcRoles)
+		verifier = &stubOIDCVerifier{identities: map[string]types.OIDCIdentity{
+			"admin-id-token": {Subject: "248289761001", Username: "jane.doe", Issuer: oidcIssuer,
+				Roles: []aas.RoleInfo{oidcRoles[0].RoleInfo}, MappingRoles: oidcMappingRoles},
+			"unmapped-id-token": {Subject: "248289761002", Username: "john.doe", Issuer: oidcIssuer,
+				Roles: []aas.RoleInfo{{Service: "WLS", Name: "UnknownRole"}}, MappingRoles: oidcMappingRoles},
+			"renamed-id-token": {Subject: "248289761001", Username: "jane.smith", Issuer: oidcIssuer,
+				Roles: []aas.RoleInfo{oidcRoles[0].RoleInfo}, MappingRoles: oidcMappingRoles},
+			"impersonating-id-token": {Subject: "248289761003", Username: "jane.doe", Issuer: oidcIssuer,
+				Roles: []aas.RoleInfo{oidcRoles[0].RoleInfo}, MappingRoles: oidcMappingRoles},
+		}}
+		jwtController = controllers.JwtTokenController{
+			Database:               mockDatabase,
+			TokenFactory:           tokenFactory,
+			TokenValidity:          10 * time.Minute,
+			RefreshTokenValidity:   time.Hour,
+			OIDCVerifier:           verifier,
+			OIDCAutoProvisionUsers: true,
+		}
+		router.Handle("/token/oidc", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+			return jwtController.CreateOIDCJwtTokenWithRefreshToken(w, r)
+		}, "application/json"))).Methods(http.MethodPost).Headers("Accept", "application/json")
+		router.Handle("/token/oidc", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(func(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+			return jwtController.CreateOIDCJwtToken(w, r)
+		}, "application/jwt"))).Methods(http.MethodPost)
+	})
+
+	requestToken := func(body, accept string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/token/oidc", strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Context("Validate CreateOIDCJwtToken with ID token mapped to role", func() {
+		It("Should return StatusOK - User should be provisioned with the mapped roles", func() {
+			w = requestToken(`{"id_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).NotTo(BeEmpty())
+
+			Expect(mockDatabase.MockUserStore.UserStore).To(HaveLen(1))
+			user := mockDatabase.MockUserStore.UserStore[0]
+			Expect(user.Name).To(Equal("jane.doe"))
+			Expect(user.Source).To(Equal("oidc:https://idp.example.com/realms/isecl"))
+			Expect(user.Subject).To(Equal("248289761001"))
+			Expect(user.PasswordHash).To(BeEmpty())
+			Expect(user.Roles).To(HaveLen(1))
+			Expect(user.Roles[0].ID).To(Equal(oidcRoles[0].ID))
+		})
+	})
+	Context("Validate CreateOIDCJwtTokenWithRefreshToken with ID token mapped to role", func() {
+		It("Should return StatusOK - Bearer token and refresh token should be returned", func() {
+			w = requestToken(`{"id_token":"admin-id-token"}`, consts.HTTPMediaTypeJson)
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var tokenResponse aas.TokenResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &tokenResponse)).To(Succeed())
+			Expect(tokenResponse.AccessToken).NotTo(BeEmpty())
+			Expect(tokenResponse.RefreshToken).NotTo(BeEmpty())
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with user not provisioned", func() {
+		It("Should return StatusUnauthorized - User should not be provisioned without auto provisioning", func() {
+			jwtController.OIDCAutoProvisionUsers = false
+			w = requestToken(`{"id_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with invalid ID token", func() {
+		It("Should return StatusUnauthorized - Invalid ID token provided", func() {
+			w = requestToken(`{"id_token":"forged-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with ID token mapped to unknown role", func() {
+		It("Should return StatusUnauthorized - User without existing mapped roles should not get a token", func() {
+			w = requestToken(`{"id_token":"unmapped-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with ID token of local user", func() {
+		It("Should return StatusUnauthorized - Local users should not be granted roles by the provider", func() {
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:           uuid.NewString(),
+				Name:         "jane.doe",
+				PasswordHash: []byte("localPasswordHash"),
+			}}
+			w = requestToken(`{"id_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with ID token of user of other provider", func() {
+		It("Should return StatusUnauthorized - Users of another identity provider should not be granted roles", func() {
+			mockDatabase.MockUserStore.UserStore = []types.User{{
+				ID:      uuid.NewString(),
+				Name:    "jane.doe",
+				Source:  "oidc:https://other-idp.example.com",
+				Subject: "248289761001",
+			}}
+			w = requestToken(`{"id_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore[0].Roles).To(BeEmpty())
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with ID tokens sharing a username", func() {
+		It("Should return StatusUnauthorized - User with another subject should not get the roles of the user", func() {
+			w = requestToken(`{"id_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			w = requestToken(`{"id_token":"impersonating-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore).To(HaveLen(1))
+			Expect(mockDatabase.MockUserStore.UserStore[0].Subject).To(Equal("248289761001"))
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with ID token of user with changed username", func() {
+		It("Should return StatusOK - User should be identified by the subject", func() {
+			w = requestToken(`{"id_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			w = requestToken(`{"id_token":"renamed-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockDatabase.MockUserStore.UserStore).To(HaveLen(1))
+			Expect(mockDatabase.MockUserStore.UserStore[0].Name).To(Equal("jane.doe"))
+		})
+	})
+	Context("Validate CreateOIDCJwtToken with provider not available", func() {
+		It("Should return StatusServiceUnavailable - Provider errors are not authentication failures", func() {
+			verifier.err = errors.New("Could not retrieve the OIDC provider JWKS")
+			w = requestToken(`{"id_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+		})
+	})
+	Context("Validate CreateOIDCJwtToken without ID token", func() {
+		It("Should return StatusBadRequest - ID token is required", func() {
+			w = requestToken(`{"id_token":""}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			w = requestToken(`{"access_token":"admin-id-token"}`, "application/jwt")
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
+
+var _ = Describe("JwtTokenController with account lockout and password expiry", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var mockDatabase *mock.MockDatabase
+	var jwtController controllers.JwtTokenController
+
+	const localPassword = "localUserPassword"
+	localRoles := types.Roles{
+		{ID: "3b6d9e2f-8a41-4c7e-b0d5-6f1a2c3e4d01", RoleInfo: aas.RoleInfo{Service: "KBS", Name: "KeyCRUD"}},
+	}
+	localPasswordHash, _ := bcrypt.GenerateFromPassword([]byte(localPassword), bcrypt.MinCost)
+
+	BeforeEach(func() {
+		comm.InitDefender(5, 5, 15)
+		comm.InitPasswordPolicy(config.PasswordPolicy{MaxAgeDays: 90},
+			config.AccountLockout{MaxAttempts: 3, DurationMins: 30})
+
+		router = mux.NewRouter()
+		mockDatabase = getMockUsersDatabase(localRoles)
+		passwordChangedAt := time.Now()
+		mockDatabase.MockUserStore.UserStore = []types.User{{
+			ID:                uuid.NewString(),
+			Name:              "localuser",
+			PasswordHash:      localPasswordHash,
+			PasswordCost:      bcrypt.MinCost,
+			PasswordChangedAt: &passwordChangedAt,
+			Roles:             localRoles,
+		}}
+		jwtController = controllers.JwtTokenController{
+			Database:             mockDatabase,
+			TokenFactory:         tokenFactory,
+			TokenValidity:        10 * time.Minute,
+			RefreshTokenValidity: time.Hour,
+		}
+		router.Handle("/token", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.CreateJwtTokenWithRefreshToken,
+			"application/json"))).Methods(http.MethodPost).Headers("Accept", "application/json")
+		router.Handle("/token", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.CreateJwtToken,
+			"application/jwt"))).Methods(http.MethodPost)
+		router.Handle("/token/refresh", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(jwtController.RefreshJwtToken,
+			"application/json"))).Methods(http.MethodPost)
+	})
+
+	AfterEach(func() {
+		comm.InitPasswordPolicy(config.PasswordPolicy{}, config.AccountLockout{})
+	})
+
+	request := func(url, body, accept string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	requestToken := func(password string) *httptest.ResponseRecorder {
+		return request("/token", `{"username":"localuser","password":"`+password+`"}`, "application/jwt")
+	}
+
+	Context("Validate CreateJwtToken with consecutive failed logins", func() {
+		It("Should return StatusUnauthorized - Account should be locked even for correct password", func() {
+			for i := 0; i < 3; i++ {
+				w = requestToken("wrongPassword")
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			}
+			user := mockDatabase.MockUserStore.UserStore[0]
+			Expect(user.FailedLoginAttempts).To(Equal(3))
+			Expect(user.LockedAt).NotTo(BeNil())
+
+			w = requestToken(localPassword)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Body.String()).To(ContainSubstring("locked"))
+		})
+	})
+	Context("Validate CreateJwtToken with failed logins below maximum attempts", func() {
+		It("Should return StatusOK - Failed logins should be reset by successful login", func() {
+			w = requestToken("wrongPassword")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(mockDatabase.MockUserStore.UserStore[0].FailedLoginAttempts).To(Equal(1))
+
+			w = requestToken(localPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockDatabase.MockUserStore.UserStore[0].FailedLoginAttempts).To(BeZero())
+		})
+	})
+	Context("Validate CreateJwtToken with lockout ended", func() {
+		It("Should return StatusOK - User should be unlocked after the lockout duration", func() {
+			lockedAt := time.Now().Add(-31 * time.Minute)
+			mockDatabase.MockUserStore.UserStore[0].FailedLoginAttempts = 3
+			mockDatabase.MockUserStore.UserStore[0].LockedAt = &lockedAt
+
+			w = requestToken(localPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			user := mockDatabase.MockUserStore.UserStore[0]
+			Expect(user.FailedLoginAttempts).To(BeZero())
+			Expect(user.LockedAt).To(BeNil())
+		})
+	})
+	Context("Validate CreateJwtToken with lockout without duration", func() {
+		It("Should return StatusUnauthorized - User should stay locked until unlocked", func() {
+			comm.InitPasswordPolicy(config.PasswordPolicy{}, config.AccountLockout{MaxAttempts: 3})
+			lockedAt := time.Now().Add(-24 * time.Hour)
+			mockDatabase.MockUserStore.UserStore[0].FailedLoginAttempts = 3
+			mockDatabase.MockUserStore.UserStore[0].LockedAt = &lockedAt
+
+			w = requestToken(localPassword)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+	Context("Validate CreateJwtToken with expired password", func() {
+		It("Should return StatusForbidden - Password expired error should be returned", func() {
+			passwordChangedAt := time.Now().Add(-91 * 24 * time.Hour)
+			mockDatabase.MockUserStore.UserStore[0].PasswordChangedAt = &passwordChangedAt
+
+			w = requestToken(localPassword)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Body.String()).To(ContainSubstring("password has expired"))
+		})
+	})
+	Context("Validate CreateJwtToken for service account with failed logins and expired password", func() {
+		It("Should return StatusOK - Service account should be exempt from lockout and password expiry", func() {
+			passwordChangedAt := time.Now().Add(-91 * 24 * time.Hour)
+			mockDatabase.MockUserStore.UserStore[0].PasswordChangedAt = &passwordChangedAt
+			mockDatabase.MockUserStore.UserStore[0].ServiceAccount = true
+			for i := 0; i < 3; i++ {
+				w = requestToken("wrongPassword")
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			}
+			Expect(mockDatabase.MockUserStore.UserStore[0].LockedAt).To(BeNil())
+
+			w = requestToken(localPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+	Context("Validate RefreshJwtToken with password expired after token was issued", func() {
+		It("Should return StatusForbidden - Refresh token should not extend the expired password", func() {
+			w = request("/token", `{"username":"localuser","password":"`+localPassword+`"}`, consts.HTTPMediaTypeJson)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var tokenResponse aas.TokenResponse
+			Expect(json.Unmarshal(w.Body.Bytes(), &tokenResponse)).To(Succeed())
+
+			passwordChangedAt := time.Now().Add(-91 * 24 * time.Hour)
+			mockDatabase.MockUserStore.UserStore[0].PasswordChangedAt = &passwordChangedAt
+			w = request("/token/refresh", `{"refresh_token":"`+tokenResponse.RefreshToken+`"}`, consts.HTTPMediaTypeJson)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+})
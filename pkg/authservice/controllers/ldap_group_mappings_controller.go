@@ -0,0 +1,233 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	consts "github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
+	aasModel "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+
+	"github.com/gorilla/mux"
+
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+)
+
+// maxLDAPGroupLength is the maximum length of the DN of a mapped LDAP group
+const maxLDAPGroupLength = 1024
+
+// LDAPGroupMappingsController manages the mapping table of LDAP groups to AAS roles. Mapping a group to a role
+// grants the role to the members of the group, so the same permissions as adding roles to users are required.
+type LDAPGroupMappingsController struct {
+	Database domain.AASDatabase
+}
+
+func (controller LDAPGroupMappingsController) CreateLDAPGroupMapping(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to createLDAPGroupMapping")
+	defer defaultLog.Trace("createLDAPGroupMapping return")
+
+	// authorize rest api endpoint based on token
+	ctxMap, err := authorizeEndpoint(r, []string{consts.UserRoleCreate}, true)
+	if err != nil {
+		secLog.Warningf("%s: Unauthorized create ldap group mapping attempt from: %s", commLogMsg.UnauthorizedAccess, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, err
+	}
+
+	if r.ContentLength == 0 {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body was not provided"}
+	}
+
+	var mc aasModel.LDAPGroupMappingCreate
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&mc)
+	if err != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	mc.Group = strings.TrimSpace(mc.Group)
+	if mc.Group == "" || len(mc.Group) > maxLDAPGroupLength {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid LDAP group provided"}
+	}
+	if validationErr := ValidateServiceString(mc.Service); validationErr != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: validationErr.Error()}
+	}
+	if validationErr := ValidateRoleString(mc.Name); validationErr != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: validationErr.Error()}
+	}
+	if validationErr := ValidateContextString(mc.Context); validationErr != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: validationErr.Error()}
+	}
+
+	// we have the role now. If ctxMap is not nil, we need to make sure that the right privilege is
+	// available to grant a role of the requested service
+	if ctxMap != nil {
+		if _, ok := (*ctxMap)[mc.Service]; !ok {
+			errMsg := fmt.Sprintf("%s: not allowed to map ldap group to role of service: %s", commLogMsg.UnauthorizedAccess, mc.Service)
+			secLog.Error(errMsg)
+			return nil, http.StatusForbidden, &commErr.PrivilegeError{Message: errMsg}
+		}
+	}
+
+	role, err := controller.Database.RoleStore().Retrieve(&types.RoleSearch{
+		RoleInfo:    aasModel.RoleInfo{Service: mc.Service, Name: mc.Name, Context: mc.Context},
+		AllContexts: false,
+	})
+	if err != nil || role == nil {
+		defaultLog.WithError(err).Info("failed to retrieve role to map ldap group to")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Role to map LDAP group to not found"}
+	}
+
+	existingMappings, err := controller.Database.LDAPGroupMappingStore().RetrieveAll([]string{mc.Group})
+	if err != nil {
+		defaultLog.WithError(err).Error("failed to retrieve ldap group mappings")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve LDAP group mappings"}
+	}
+	for _, existingMapping := range existingMappings {
+		if existingMapping.RoleID == role.ID {
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "same LDAP group mapping exists"}
+		}
+	}
+
+	created, err := controller.Database.LDAPGroupMappingStore().Create(types.LDAPGroupMapping{
+		GroupDN: mc.Group,
+		RoleID:  role.ID,
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("failed to create ldap group mapping")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create LDAP group mapping"}
+	}
+	secLog.WithField("group", created.GroupDN).WithField("role", role.RoleInfo).Infof("%s: LDAP group mapping created by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+
+	mappingBytes, err := json.Marshal(aasModel.LDAPGroupMappingInfo{
+		ID:       created.ID,
+		Group:    created.GroupDN,
+		RoleID:   role.ID,
+		RoleInfo: role.RoleInfo,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: err.Error()}
+	}
+	return string(mappingBytes), http.StatusCreated, nil
+}
+
+func (controller LDAPGroupMappingsController) QueryLDAPGroupMappings(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to queryLDAPGroupMappings")
+	defer defaultLog.Trace("queryLDAPGroupMappings return")
+
+	// authorize rest api endpoint based on token
+	svcFltr, err := authorizeEndPointAndGetServiceFilter(r, []string{consts.UserRoleSearch})
+	if err != nil {
+		secLog.Warningf("%s: Unauthorized query ldap group mappings attempt from: %s", commLogMsg.UnauthorizedAccess, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, err
+	}
+
+	var groups []string
+	if group := r.URL.Query().Get("group"); group != "" {
+		if len(group) > maxLDAPGroupLength {
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid LDAP group provided"}
+		}
+		groups = []string{group}
+	}
+
+	mappings, err := controller.Database.LDAPGroupMappingStore().RetrieveAll(groups)
+	if err != nil {
+		defaultLog.WithError(err).Error("failed to retrieve ldap group mappings")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve LDAP group mappings"}
+	}
+
+	mappingInfos := aasModel.LDAPGroupMappingInfos{}
+	if len(mappings) > 0 {
+		var roleIDs []string
+		for _, mapping := range mappings {
+			roleIDs = append(roleIDs, mapping.RoleID)
+		}
+		roles, err := controller.Database.RoleStore().RetrieveAll(&types.RoleSearch{
+			IDFilter:      roleIDs,
+			ServiceFilter: svcFltr,
+			AllContexts:   true,
+		})
+		if err != nil {
+			defaultLog.WithError(err).Error("failed to retrieve roles of ldap group mappings")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve LDAP group mappings"}
+		}
+		// the mappings to roles the user is not allowed to search are left out
+		for _, mapping := range mappings {
+			for _, role := range roles {
+				if role.ID == mapping.RoleID {
+					mappingInfos = append(mappingInfos, aasModel.LDAPGroupMappingInfo{
+						ID:       mapping.ID,
+						Group:    mapping.GroupDN,
+						RoleID:   role.ID,
+						RoleInfo: role.RoleInfo,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	mappingsBytes, err := json.Marshal(mappingInfos)
+	if err != nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: err.Error()}
+	}
+	secLog.Infof("%s: Return ldap group mappings query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return string(mappingsBytes), http.StatusOK, nil
+}
+
+func (controller LDAPGroupMappingsController) DeleteLDAPGroupMapping(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to deleteLDAPGroupMapping")
+	defer defaultLog.Trace("deleteLDAPGroupMapping return")
+
+	// authorize rest api endpoint based on token
+	ctxMap, err := authorizeEndpoint(r, []string{consts.UserRoleDelete}, true)
+	if err != nil {
+		secLog.Warningf("%s: Unauthorized delete ldap group mapping attempt from: %s", commLogMsg.UnauthorizedAccess, r.RemoteAddr)
+		return nil, http.StatusUnauthorized, err
+	}
+
+	id := mux.Vars(r)["id"]
+
+	validationErr := validation.ValidateUUIDv4(id)
+	if validationErr != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: validationErr.Error()}
+	}
+
+	mapping, err := controller.Database.LDAPGroupMappingStore().Retrieve(id)
+	if err != nil || mapping == nil {
+		defaultLog.WithError(err).WithField("id", id).Info("attempt to delete invalid ldap group mapping")
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "LDAP group mapping not found"}
+	}
+
+	// the mapping of a deleted role can be deleted by any user allowed to delete mappings
+	if ctxMap != nil {
+		role, err := controller.Database.RoleStore().Retrieve(&types.RoleSearch{AllContexts: true, IDFilter: []string{mapping.RoleID}})
+		if err == nil && role != nil {
+			if _, ok := (*ctxMap)[role.Service]; !ok {
+				errMsg := fmt.Sprintf("%s: not allowed to delete ldap group mapping to role of service: %s", commLogMsg.UnauthorizedAccess, role.Service)
+				secLog.Error(errMsg)
+				return nil, http.StatusForbidden, &commErr.PrivilegeError{Message: errMsg}
+			}
+		}
+	}
+
+	if err = controller.Database.LDAPGroupMappingStore().Delete(*mapping); err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("failed to delete ldap group mapping")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete LDAP group mapping"}
+	}
+	secLog.WithField("group", mapping.GroupDN).Infof("%s: LDAP group mapping deleted by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+
+	return nil, http.StatusNoContent, nil
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/postgres/mock"
+	aasRoutes "github.com/intel-secl/intel-secl/v5/pkg/authservice/router"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LDAPGroupMappingsController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var mockDatabase *mock.MockDatabase
+	var mappingsController controllers.LDAPGroupMappingsController
+
+	ldapRoles := types.Roles{
+		{ID: "5c3b2c58-3b51-4bd1-8e42-0e0a4f1d3a01", RoleInfo: aas.RoleInfo{Service: "KBS", Name: "KeyCRUD"}},
+		{ID: "5c3b2c58-3b51-4bd1-8e42-0e0a4f1d3a02", RoleInfo: aas.RoleInfo{Service: "HVS", Name: "ReportSearcher"}},
+	}
+	const kbsAdminsGroup = "cn=kbs-admins,ou=groups,dc=example,dc=com"
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		mockDatabase = &mock.MockDatabase{
+			MockRoleStore:             getMockLDAPRoleStore(ldapRoles),
+			MockLDAPGroupMappingStore: getMockLDAPGroupMappingStore(),
+		}
+		mappingsController = controllers.LDAPGroupMappingsController{Database: mockDatabase}
+		router.Handle("/ldap-group-mappings", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(mappingsController.CreateLDAPGroupMapping,
+			"application/json"))).Methods(http.MethodPost)
+		router.Handle("/ldap-group-mappings", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(mappingsController.QueryLDAPGroupMappings,
+			"application/json"))).Methods(http.MethodGet)
+		router.Handle("/ldap-group-mappings/{id}", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(mappingsController.DeleteLDAPGroupMapping,
+			""))).Methods(http.MethodDelete)
+	})
+
+	request := func(method, url, body string, permissions aas.PermissionInfo) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req = context.SetUserPermissions(req, []aas.PermissionInfo{permissions})
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	createMapping := func(body string) *httptest.ResponseRecorder {
+		return request(http.MethodPost, "/ldap-group-mappings", body, aas.PermissionInfo{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.UserRoleCreate},
+		})
+	}
+
+	Describe("CreateLDAPGroupMapping", func() {
+		Context("Validate CreateLDAPGroupMapping with valid group and role", func() {
+			It("Should return StatusCreated - Valid request should create the mapping", func() {
+				w = createMapping(`{"group":"` + kbsAdminsGroup + `","service":"KBS","name":"KeyCRUD"}`)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var mapping aas.LDAPGroupMappingInfo
+				Expect(json.Unmarshal(w.Body.Bytes(), &mapping)).To(Succeed())
+				Expect(mapping.ID).NotTo(BeEmpty())
+				Expect(mapping.Group).To(Equal(kbsAdminsGroup))
+				Expect(mapping.RoleID).To(Equal(ldapRoles[0].ID))
+				Expect(mapping.Service).To(Equal("KBS"))
+			})
+		})
+		Context("Validate CreateLDAPGroupMapping with existing mapping", func() {
+			It("Should return StatusBadRequest - Same mapping should not be created twice", func() {
+				w = createMapping(`{"group":"` + kbsAdminsGroup + `","service":"KBS","name":"KeyCRUD"}`)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				w = createMapping(`{"group":"` + strings.ToUpper(kbsAdminsGroup) + `","service":"KBS","name":"KeyCRUD"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Validate CreateLDAPGroupMapping with unknown role", func() {
+			It("Should return StatusBadRequest - Role to map the group to should exist", func() {
+				w = createMapping(`{"group":"` + kbsAdminsGroup + `","service":"KBS","name":"UnknownRole"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Validate CreateLDAPGroupMapping without group", func() {
+			It("Should return StatusBadRequest - Group is required", func() {
+				w = createMapping(`{"group":" ","service":"KBS","name":"KeyCRUD"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Validate CreateLDAPGroupMapping with unknown field", func() {
+			It("Should return StatusBadRequest - Unknown fields are not allowed", func() {
+				w = createMapping(`{"group":"` + kbsAdminsGroup + `","service":"KBS","name":"KeyCRUD","role_id":"1"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Validate CreateLDAPGroupMapping with permission limited to other service", func() {
+			It("Should return StatusForbidden - Role of the service cannot be granted", func() {
+				w = request(http.MethodPost, "/ldap-group-mappings",
+					`{"group":"`+kbsAdminsGroup+`","service":"KBS","name":"KeyCRUD"}`, aas.PermissionInfo{
+						Service: constants.ServiceName,
+						Rules:   []string{constants.UserRoleCreate},
+						Context: "HVS",
+					})
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+		Context("Validate CreateLDAPGroupMapping without permission", func() {
+			It("Should return StatusUnauthorized - User role create permission is required", func() {
+				w = request(http.MethodPost, "/ldap-group-mappings",
+					`{"group":"`+kbsAdminsGroup+`","service":"KBS","name":"KeyCRUD"}`, aas.PermissionInfo{
+						Service: constants.ServiceName,
+						Rules:   []string{constants.RoleCreate},
+					})
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("QueryLDAPGroupMappings", func() {
+		queryMappings := func(url string, permissions aas.PermissionInfo) aas.LDAPGroupMappingInfos {
+			w = request(http.MethodGet, url, "", permissions)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var mappings aas.LDAPGroupMappingInfos
+			Expect(json.Unmarshal(w.Body.Bytes(), &mappings)).To(Succeed())
+			return mappings
+		}
+		searchPermissions := aas.PermissionInfo{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.UserRoleSearch},
+		}
+
+		BeforeEach(func() {
+			Expect(createMapping(`{"group":"` + kbsAdminsGroup + `","service":"KBS","name":"KeyCRUD"}`).Code).
+				To(Equal(http.StatusCreated))
+			Expect(createMapping(`{"group":"cn=hvs-admins,ou=groups,dc=example,dc=com","service":"HVS","name":"ReportSearcher"}`).Code).
+				To(Equal(http.StatusCreated))
+		})
+
+		Context("Validate QueryLDAPGroupMappings without filter", func() {
+			It("Should return StatusOK - All the mappings should be returned", func() {
+				mappings := queryMappings("/ldap-group-mappings", searchPermissions)
+				Expect(mappings).To(HaveLen(2))
+			})
+		})
+		Context("Validate QueryLDAPGroupMappings with group filter", func() {
+			It("Should return StatusOK - Mappings of the group should be returned", func() {
+				mappings := queryMappings("/ldap-group-mappings?group=CN%3Dkbs-admins%2Cou%3Dgroups%2Cdc%3Dexample%2Cdc%3Dcom",
+					searchPermissions)
+				Expect(mappings).To(HaveLen(1))
+				Expect(mappings[0].Name).To(Equal("KeyCRUD"))
+			})
+		})
+		Context("Validate QueryLDAPGroupMappings with permission limited to a service", func() {
+			It("Should return StatusOK - Only the mappings to roles of the service should be returned", func() {
+				mappings := queryMappings("/ldap-group-mappings", aas.PermissionInfo{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.UserRoleSearch},
+					Context: "HVS",
+				})
+				Expect(mappings).To(HaveLen(1))
+				Expect(mappings[0].Service).To(Equal("HVS"))
+			})
+		})
+	})
+
+	Describe("DeleteLDAPGroupMapping", func() {
+		deletePermissions := aas.PermissionInfo{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.UserRoleDelete},
+		}
+
+		Context("Validate DeleteLDAPGroupMapping with existing mapping", func() {
+			It("Should return StatusNoContent - Mapping should be deleted", func() {
+				w = createMapping(`{"group":"` + kbsAdminsGroup + `","service":"KBS","name":"KeyCRUD"}`)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				var mapping aas.LDAPGroupMappingInfo
+				Expect(json.Unmarshal(w.Body.Bytes(), &mapping)).To(Succeed())
+
+				w = request(http.MethodDelete, "/ldap-group-mappings/"+mapping.ID, "", deletePermissions)
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+				w = request(http.MethodDelete, "/ldap-group-mappings/"+mapping.ID, "", deletePermissions)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+		Context("Validate DeleteLDAPGroupMapping with permission limited to other service", func() {
+			It("Should return StatusForbidden - Mapping to role of the service cannot be deleted", func() {
+				w = createMapping(`{"group":"` + kbsAdminsGroup + `","service":"KBS","name":"KeyCRUD"}`)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+				var mapping aas.LDAPGroupMappingInfo
+				Expect(json.Unmarshal(w.Body.Bytes(), &mapping)).To(Succeed())
+
+				w = request(http.MethodDelete, "/ldap-group-mappings/"+mapping.ID, "", aas.PermissionInfo{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.UserRoleDelete},
+					Context: "HVS",
+				})
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			})
+		})
+		Context("Validate DeleteLDAPGroupMapping with invalid id", func() {
+			It("Should return StatusBadRequest - Mapping id should be a valid uuid", func() {
+				w = request(http.MethodDelete, "/ldap-group-mappings/invalid-id", "", deletePermissions)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
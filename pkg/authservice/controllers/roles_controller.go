@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	authcommon "github.com/intel-secl/intel-secl/v5/pkg/authservice/common"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
@@ -24,6 +26,9 @@ import (
 
 type RolesController struct {
 	Database domain.AASDatabase
+	// MaxTokenValidity is the longest validity of the tokens issued by AAS, the tokens of the users holding a deleted
+	// role are revoked for this duration
+	MaxTokenValidity time.Duration
 }
 
 func (controller RolesController) CreateRole(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -220,10 +225,22 @@ func (controller RolesController) DeleteRole(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	// the holders are retrieved before the deletion clears the role of the users
+	holders, err := controller.Database.RoleStore().GetUsers(*delRl)
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Info("failed to retrieve users of role")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete role"}
+	}
 	if err := controller.Database.RoleStore().Delete(*delRl); err != nil {
 		defaultLog.WithError(err).WithField("id", id).Info("failed to delete role")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete role"}
 	}
+	for _, holder := range holders {
+		if err = authcommon.RevokeUserTokens(controller.Database, holder, controller.MaxTokenValidity); err != nil {
+			defaultLog.WithError(err).WithField("id", id).Error("could not revoke tokens of users of deleted role")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "role deleted but tokens could not be revoked"}
+		}
+	}
 	secLog.WithField("role", delRl).Infof("%s: Role deleted by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
 
 	return nil, http.StatusNoContent, nil
@@ -307,7 +324,7 @@ func (controller RolesController) UpdateRole(w http.ResponseWriter, r *http.Requ
 	return nil, http.StatusNotImplemented, &commErr.ResourceError{Message: ""}
 }
 
-func contains(strArr [5]string, str string) bool {
+func contains(strArr [len(consts.DefaultRoles)]string, str string) bool {
 	for _, s := range strArr {
 		if s == str {
 			return true
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	comm "github.com/intel-secl/intel-secl/v5/pkg/authservice/common"
@@ -29,16 +30,19 @@ var _ = Describe("RolesController", func() {
 	var rolesController controllers.RolesController
 	// mock database
 	mockDatabase := &mock.MockDatabase{
-		MockUserStore:       getMockUserStore(),
-		MockRoleStore:       getMockRoleStore(),
-		MockPermissionStore: getPermissionStore(),
+		MockUserStore:         getMockUserStore(),
+		MockRoleStore:         getMockRoleStore(),
+		MockPermissionStore:   getPermissionStore(),
+		MockRevokedTokenStore: getMockRevokedTokenStore(),
+		MockRefreshTokenStore: getMockRefreshTokenStore(),
 	}
 	comm.InitDefender(5, 5, 15)
 
 	BeforeEach(func() {
 		router = mux.NewRouter()
 		rolesController = controllers.RolesController{
-			Database: mockDatabase,
+			Database:         mockDatabase,
+			MaxTokenValidity: time.Hour,
 		}
 	})
 
@@ -477,6 +481,11 @@ var _ = Describe("RolesController", func() {
 				w = httptest.NewRecorder()
 				router.ServeHTTP(w, req)
 				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				// the tokens of the users holding the deleted role are revoked
+				revokedTokens, err := mockDatabase.MockRevokedTokenStore.RetrieveAll(time.Time{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(revokedTokens).To(ContainElement(HaveField("Subject", "roleholder")))
 			})
 			// To validate with insufficent privilege
 			It("Should return StatusUnauthorized - Insufficent privilege", func() {
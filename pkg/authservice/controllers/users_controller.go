@@ -6,10 +6,12 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	authcommon "github.com/intel-secl/intel-secl/v5/pkg/authservice/common"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
@@ -20,7 +22,6 @@ import (
 	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
 	aasModel "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/gorilla/mux"
 
@@ -29,6 +30,9 @@ import (
 
 type UsersController struct {
 	Database domain.AASDatabase
+	// MaxTokenValidity is the longest validity of the tokens issued by AAS, the tokens of a changed user are revoked
+	// for this duration
+	MaxTokenValidity time.Duration
 }
 
 func (controller UsersController) CreateUser(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
@@ -65,12 +69,12 @@ func (controller UsersController) CreateUser(w http.ResponseWriter, r *http.Requ
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "same user exists"}
 	}
 
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(uc.Password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: err.Error()}
+	newUser := types.User{Name: uc.Name, ServiceAccount: uc.ServiceAccount != nil && *uc.ServiceAccount}
+	if httpStatus, err := authcommon.SetUserPassword(controller.Database, &newUser, uc.Password); err != nil {
+		return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
 	}
 
-	created, err := controller.Database.UserStore().Create(types.User{Name: uc.Name, PasswordHash: passwordHash, PasswordCost: bcrypt.DefaultCost})
+	created, err := controller.Database.UserStore().Create(newUser)
 	if err != nil {
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: err.Error()}
 	}
@@ -135,12 +139,12 @@ func (controller UsersController) UpdateUser(w http.ResponseWriter, r *http.Requ
 	if err != nil {
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
 	}
-	if uc.Name == "" && uc.Password == "" {
+	if uc.Name == "" && uc.Password == "" && uc.ServiceAccount == nil {
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "No data to change"}
 	}
 
-	// create a structure for the updated user
-	updatedUser := types.User{ID: id}
+	// the updated user keeps the password change time and the lockout of the user
+	updatedUser := *u
 
 	// validate user fields and set the attributes for the user that we want to change
 	if uc.Name != "" {
@@ -154,8 +158,6 @@ func (controller UsersController) UpdateUser(w http.ResponseWriter, r *http.Requ
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "supplied username belongs to another user"}
 		}
 		updatedUser.Name = uc.Name
-	} else {
-		updatedUser.Name = u.Name
 	}
 
 	if uc.Password != "" {
@@ -163,15 +165,14 @@ func (controller UsersController) UpdateUser(w http.ResponseWriter, r *http.Requ
 		if validationErr != nil {
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: validationErr.Error()}
 		}
-		updatedUser.PasswordHash, err = bcrypt.GenerateFromPassword([]byte(uc.Password), bcrypt.DefaultCost)
-		if err != nil {
-			defaultLog.WithError(err).Error("could not generate password when attempting to update user : ", id)
-			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Cannot complete request"}
+		if httpStatus, err := authcommon.SetUserPassword(controller.Database, &updatedUser, uc.Password); err != nil {
+			defaultLog.WithError(err).Error("could not set password when attempting to update user : ", id)
+			return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
 		}
-		updatedUser.PasswordCost = bcrypt.DefaultCost
-	} else {
-		updatedUser.PasswordHash = u.PasswordHash
-		updatedUser.PasswordCost = u.PasswordCost
+	}
+
+	if uc.ServiceAccount != nil {
+		updatedUser.ServiceAccount = *uc.ServiceAccount
 	}
 
 	err = controller.Database.UserStore().Update(updatedUser)
@@ -179,6 +180,10 @@ func (controller UsersController) UpdateUser(w http.ResponseWriter, r *http.Requ
 		defaultLog.WithError(err).Error("database error while attempting to change user:", id)
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
 	}
+	if err = authcommon.RevokeUserTokens(controller.Database, *u, controller.MaxTokenValidity); err != nil {
+		defaultLog.WithError(err).Error("could not revoke tokens of changed user:", id)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "user changed but tokens could not be revoked"}
+	}
 	secLog.Infof("%s: User %s changed by: %s", commLogMsg.PrivilegeModified, id, r.RemoteAddr)
 
 	return nil, http.StatusOK, nil
@@ -206,11 +211,43 @@ func (controller UsersController) DeleteUser(w http.ResponseWriter, r *http.Requ
 	if err := controller.Database.UserStore().Delete(*delUsr); err != nil {
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: err.Error()}
 	}
+	if err := authcommon.RevokeUserTokens(controller.Database, *delUsr, controller.MaxTokenValidity); err != nil {
+		defaultLog.WithError(err).Error("could not revoke tokens of deleted user:", id)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "user deleted but tokens could not be revoked"}
+	}
 	secLog.WithField("user", delUsr).Infof("%s: User deleted by: %s", commLogMsg.UserDeleted, r.RemoteAddr)
 
 	return nil, http.StatusNoContent, nil
 }
 
+// UnlockUser unlocks the account of the user locked after too many failed logins
+func (controller UsersController) UnlockUser(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+
+	defaultLog.Trace("call to unlockUser")
+	defer defaultLog.Trace("unlockUser return")
+
+	id := mux.Vars(r)["id"]
+
+	validationErr := validation.ValidateUUIDv4(id)
+	if validationErr != nil {
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: validationErr.Error()}
+	}
+
+	u, err := controller.Database.UserStore().Retrieve(types.User{ID: id})
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", id).Info("failed to retrieve user")
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: "User not found"}
+	}
+
+	if err = authcommon.UnlockUser(controller.Database.UserStore(), *u); err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("could not unlock user")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
+	}
+	secLog.WithField("user", u.ID).Infof("%s: User %s unlocked by: %s", commLogMsg.PrivilegeModified, u.ID, r.RemoteAddr)
+
+	return nil, http.StatusNoContent, nil
+}
+
 func (controller UsersController) QueryUsers(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 
 	defaultLog.Trace("call to queryUsers")
@@ -561,6 +598,10 @@ func (controller UsersController) DeleteUserRole(w http.ResponseWriter, r *http.
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "failed to delete role from user"}
 		}
 	}
+	if err = authcommon.RevokeUserTokens(controller.Database, *u, controller.MaxTokenValidity); err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("could not revoke tokens of user")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "role deleted from user but tokens could not be revoked"}
+	}
 	secLog.WithField("user", *u).Infof("%s: User roles deleted by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
 	return nil, http.StatusNoContent, nil
 }
@@ -603,7 +644,9 @@ func (controller UsersController) ChangePassword(w http.ResponseWriter, r *http.
 
 	u := controller.Database.UserStore()
 
-	if httpStatus, err := authcommon.HttpHandleUserAuth(u, pc.UserName, pc.OldPassword); err != nil {
+	// the users whose password has expired authenticate with it to change it
+	if httpStatus, err := authcommon.HttpHandleUserAuth(u, pc.UserName, pc.OldPassword); err != nil &&
+		!errors.Is(err, authcommon.ErrPasswordExpired) {
 		secLog.Warningf("%s: User [%s] auth failed, requested from %s: ", commLogMsg.UnauthorizedAccess, pc.UserName, r.RemoteAddr)
 		return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
 	}
@@ -614,18 +657,18 @@ func (controller UsersController) ChangePassword(w http.ResponseWriter, r *http.
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
 	}
 
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(pc.NewPassword), bcrypt.DefaultCost)
-	if err != nil {
-		defaultLog.WithError(err).Error("could not generate password when attempting to change password")
-		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
+	if httpStatus, err := authcommon.SetUserPassword(controller.Database, existingUser, pc.NewPassword); err != nil {
+		return nil, httpStatus, &commErr.ResourceError{Message: err.Error()}
 	}
-	existingUser.PasswordHash = passwordHash
-	existingUser.PasswordCost = bcrypt.DefaultCost
 	err = controller.Database.UserStore().Update(*existingUser)
 	if err != nil {
 		defaultLog.WithError(err).Error("database error while attempting to change password")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "cannot complete request"}
 	}
+	if err = authcommon.RevokeUserTokens(controller.Database, *existingUser, controller.MaxTokenValidity); err != nil {
+		defaultLog.WithError(err).Error("could not revoke tokens after password change")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "password changed but tokens could not be revoked"}
+	}
 	secLog.WithField("user", existingUser.ID).Infof("%s: User %s password changed by: %s", commLogMsg.PrivilegeModified, existingUser.ID, r.RemoteAddr)
 
 	return nil, http.StatusOK, nil
@@ -8,13 +8,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	comm "github.com/intel-secl/intel-secl/v5/pkg/authservice/common"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/controllers"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/postgres/mock"
 	aasRoutes "github.com/intel-secl/intel-secl/v5/pkg/authservice/router"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
 	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
@@ -1583,3 +1587,150 @@ var _ = Describe("UsersController", func() {
 		})
 	})
 })
+
+var _ = Describe("UsersController with password policy", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var mockDatabase *mock.MockDatabase
+	var userController controllers.UsersController
+	var localUser types.User
+
+	const currentPassword = "Current-Passw0rd"
+
+	BeforeEach(func() {
+		comm.InitDefender(5, 5, 15)
+		comm.InitPasswordPolicy(config.PasswordPolicy{
+			MinLength:        12,
+			RequireUppercase: true,
+			RequireLowercase: true,
+			RequireDigit:     true,
+			RequireSpecial:   true,
+			HistoryDepth:     3,
+			MaxAgeDays:       90,
+		}, config.AccountLockout{MaxAttempts: 3})
+
+		router = mux.NewRouter()
+		mockDatabase = getMockUsersDatabase(nil)
+		localUser = types.User{ID: uuid.NewString(), Name: "localuser"}
+		_, err := comm.SetUserPassword(mockDatabase, &localUser, currentPassword)
+		Expect(err).NotTo(HaveOccurred())
+		mockDatabase.MockUserStore.UserStore = []types.User{localUser}
+
+		userController = controllers.UsersController{Database: mockDatabase}
+		router.Handle("/users", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(userController.CreateUser,
+			"application/json"))).Methods(http.MethodPost)
+		router.Handle("/users/changepassword", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(userController.ChangePassword,
+			""))).Methods(http.MethodPatch)
+		router.Handle("/users/{id}", aasRoutes.ErrorHandler(aasRoutes.ResponseHandler(userController.UpdateUser,
+			"application/json"))).Methods(http.MethodPatch)
+		router.Handle("/users/{id}/unlock", aasRoutes.ErrorHandler(aasRoutes.PermissionsHandler(
+			aasRoutes.ResponseHandler(userController.UnlockUser, ""), []string{constants.UserStore}))).Methods(http.MethodPost)
+	})
+
+	AfterEach(func() {
+		comm.InitPasswordPolicy(config.PasswordPolicy{}, config.AccountLockout{})
+	})
+
+	request := func(method, url, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req = context.SetUserPermissions(req, []aas.PermissionInfo{{
+			Service: constants.ServiceName,
+			Rules:   []string{constants.UserStore},
+		}})
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	changePassword := func(oldPassword, newPassword string) *httptest.ResponseRecorder {
+		return request(http.MethodPatch, "/users/changepassword", `{"username":"localuser","old_password":"`+
+			oldPassword+`","new_password":"`+newPassword+`","password_confirm":"`+newPassword+`"}`)
+	}
+
+	Context("Validate CreateUser with password not matching the policy", func() {
+		It("Should return StatusBadRequest - Password should be rejected by the policy", func() {
+			for _, password := range []string{"Sh0rt-pwd", "no-uppercase-passw0rd", "NO-LOWERCASE-PASSW0RD",
+				"No-Digit-Password", "NoSpecialPassw0rd"} {
+				w = request(http.MethodPost, "/users", `{"username":"newuser","password":"`+password+`"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest), password)
+			}
+			w = request(http.MethodPost, "/users", `{"username":"newuser","password":"Valid-Passw0rd"}`)
+			Expect(w.Code).To(Equal(http.StatusCreated))
+			Expect(mockDatabase.MockUserStore.UserStore[1].PasswordChangedAt).NotTo(BeNil())
+		})
+	})
+	Context("Validate ChangePassword with passwords in the password history", func() {
+		It("Should return StatusBadRequest - Recent passwords should not be reused", func() {
+			w = changePassword(currentPassword, currentPassword)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+			w = changePassword(currentPassword, "Second-Passw0rd")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			w = changePassword("Second-Passw0rd", "Third-Passw0rd")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			w = changePassword("Third-Passw0rd", currentPassword)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+			// the first password is out of the history after the third change
+			w = changePassword("Third-Passw0rd", "Fourth-Passw0rd")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			w = changePassword("Fourth-Passw0rd", currentPassword)
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+	Context("Validate ChangePassword with expired password", func() {
+		It("Should return StatusOK - Expired password should be changed", func() {
+			passwordChangedAt := time.Now().Add(-91 * 24 * time.Hour)
+			mockDatabase.MockUserStore.UserStore[0].PasswordChangedAt = &passwordChangedAt
+
+			w = changePassword(currentPassword, "Renewed-Passw0rd")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(mockDatabase.MockUserStore.UserStore[0].PasswordChangedAt.After(passwordChangedAt)).To(BeTrue())
+		})
+	})
+	Context("Validate UpdateUser with password not matching the policy", func() {
+		It("Should return StatusBadRequest - Password set by admin should match the policy", func() {
+			w = request(http.MethodPatch, "/users/"+localUser.ID, `{"password":"weakpassword"}`)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+	Context("Validate UpdateUser with new username", func() {
+		It("Should return StatusOK - Password change time and lockout should be kept", func() {
+			lockedAt := time.Now()
+			mockDatabase.MockUserStore.UserStore[0].LockedAt = &lockedAt
+
+			w = request(http.MethodPatch, "/users/"+localUser.ID, `{"username":"renameduser"}`)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			user := mockDatabase.MockUserStore.UserStore[0]
+			Expect(user.Name).To(Equal("renameduser"))
+			Expect(user.PasswordChangedAt).To(Equal(localUser.PasswordChangedAt))
+			Expect(user.LockedAt).NotTo(BeNil())
+		})
+	})
+	Context("Validate UnlockUser with locked user", func() {
+		It("Should return StatusNoContent - User should be able to log in again", func() {
+			for i := 0; i < 3; i++ {
+				w = changePassword("wrongPassword", "Unlocked-Passw0rd")
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			}
+			w = changePassword(currentPassword, "Unlocked-Passw0rd")
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+
+			w = request(http.MethodPost, "/users/"+localUser.ID+"/unlock", "")
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(mockDatabase.MockUserStore.UserStore[0].LockedAt).To(BeNil())
+
+			w = changePassword(currentPassword, "Unlocked-Passw0rd")
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+	Context("Validate UnlockUser with unknown user", func() {
+		It("Should return StatusNotFound - User should exist", func() {
+			w = request(http.MethodPost, "/users/"+uuid.NewString()+"/unlock", "")
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			w = request(http.MethodPost, "/users/invalid-id/unlock", "")
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
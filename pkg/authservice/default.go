@@ -48,11 +48,35 @@ func init() {
 	viper.SetDefault(config.JwtIncludeKid, true)
 	viper.SetDefault(config.JwtCertCommonName, constants.DefaultAasJwtCn)
 	viper.SetDefault(config.JwtTokenDurationMins, constants.DefaultAasJwtDurationMins)
+	viper.SetDefault(config.JwtRefreshTokenDurationMins, constants.DefaultRefreshTokenDurationMins)
+	viper.SetDefault(config.JwtCustomClaimsMaxDurationMins, constants.DefaultCustomClaimsMaxDurationMins)
 
 	viper.SetDefault(config.AuthDefenderMaxAttempts, constants.DefaultAuthDefendMaxAttempts)
 	viper.SetDefault(config.AuthDefenderIntervalMins, constants.DefaultAuthDefendIntervalMins)
 	viper.SetDefault(config.AuthDefenderLockoutDurationMins, constants.DefaultAuthDefendLockoutMins)
 
+	viper.SetDefault(config.PasswordPolicyMinLength, constants.DefaultPasswordMinLength)
+	viper.SetDefault(config.PasswordPolicyRequireUppercase, false)
+	viper.SetDefault(config.PasswordPolicyRequireLowercase, false)
+	viper.SetDefault(config.PasswordPolicyRequireDigit, false)
+	viper.SetDefault(config.PasswordPolicyRequireSpecial, false)
+	viper.SetDefault(config.PasswordPolicyHistoryDepth, 0)
+	viper.SetDefault(config.PasswordPolicyMaxAgeDays, 0)
+	viper.SetDefault(config.AccountLockoutMaxAttempts, constants.DefaultAccountLockoutMaxAttempts)
+	viper.SetDefault(config.AccountLockoutDurationMins, constants.DefaultAccountLockoutDurationMins)
+
+	viper.SetDefault(config.LdapEnabled, false)
+	viper.SetDefault(config.LdapUserFilter, constants.DefaultLdapUserFilter)
+	viper.SetDefault(config.LdapGroupAttribute, constants.DefaultLdapGroupAttribute)
+	viper.SetDefault(config.LdapGroupFilter, constants.DefaultLdapGroupFilter)
+	viper.SetDefault(config.LdapAutoProvisionUsers, false)
+	viper.SetDefault(config.LdapTimeout, constants.DefaultLdapTimeout)
+
+	viper.SetDefault(config.OidcEnabled, false)
+	viper.SetDefault(config.OidcUsernameClaim, constants.DefaultOidcUsernameClaim)
+	viper.SetDefault(config.OidcAutoProvisionUsers, false)
+	viper.SetDefault(config.OidcTimeout, constants.DefaultOidcTimeout)
+
 	viper.SetDefault(config.CreateCredentials, false)
 	viper.SetDefault(config.NatsOperatorName, constants.DefaultOperatorName)
 	viper.SetDefault(config.NatsAccountName, constants.DefaultAccountName)
@@ -84,29 +108,31 @@ func defaultConfig() *config.Configuration {
 
 func loadAlias() {
 	alias := map[string]string{
-		commConfig.DbHost:                  "AAS_DB_HOSTNAME",
-		commConfig.DbVendor:                "AAS_DB_VENDOR",
-		commConfig.DbPort:                  "AAS_DB_PORT",
-		commConfig.DbName:                  "AAS_DB_NAME",
-		commConfig.DbUsername:              "AAS_DB_USERNAME",
-		commConfig.DbPassword:              "AAS_DB_PASSWORD",
-		commConfig.DbSslCert:               "AAS_DB_SSLCERT",
-		commConfig.DbSslCertSource:         "AAS_DB_SSLCERTSRC",
-		commConfig.DbSslMode:               "AAS_DB_SSL_MODE",
-		commConfig.TlsCommonName:           "AAS_TLS_CERT_CN",
-		commConfig.TlsSanList:              "SAN_LIST",
-		commConfig.ServerReadTimeout:       "AAS_SERVER_READ_TIMEOUT",
-		commConfig.ServerReadHeaderTimeout: "AAS_SERVER_READ_HEADER_TIMEOUT",
-		commConfig.ServerWriteTimeout:      "AAS_SERVER_WRITE_TIMEOUT",
-		commConfig.ServerIdleTimeout:       "AAS_SERVER_IDLE_TIMEOUT",
-		commConfig.ServerMaxHeaderBytes:    "AAS_SERVER_MAX_HEADER_BYTES",
-		config.AasServiceUsername:          "AAS_ADMIN_USERNAME",
-		config.AasServicePassword:          "AAS_ADMIN_PASSWORD",
-		config.JwtTokenDurationMins:        "AAS_JWT_TOKEN_DURATION_MINS",
-		config.JwtIncludeKid:               "AAS_JWT_INCLUDE_KEYID",
-		config.JwtCertCommonName:           "AAS_JWT_CERT_CN",
-		commConfig.TlsCertFile:             "CERT_PATH",
-		commConfig.TlsKeyFile:              "KEY_PATH",
+		commConfig.DbHost:                     "AAS_DB_HOSTNAME",
+		commConfig.DbVendor:                   "AAS_DB_VENDOR",
+		commConfig.DbPort:                     "AAS_DB_PORT",
+		commConfig.DbName:                     "AAS_DB_NAME",
+		commConfig.DbUsername:                 "AAS_DB_USERNAME",
+		commConfig.DbPassword:                 "AAS_DB_PASSWORD",
+		commConfig.DbSslCert:                  "AAS_DB_SSLCERT",
+		commConfig.DbSslCertSource:            "AAS_DB_SSLCERTSRC",
+		commConfig.DbSslMode:                  "AAS_DB_SSL_MODE",
+		commConfig.TlsCommonName:              "AAS_TLS_CERT_CN",
+		commConfig.TlsSanList:                 "SAN_LIST",
+		commConfig.ServerReadTimeout:          "AAS_SERVER_READ_TIMEOUT",
+		commConfig.ServerReadHeaderTimeout:    "AAS_SERVER_READ_HEADER_TIMEOUT",
+		commConfig.ServerWriteTimeout:         "AAS_SERVER_WRITE_TIMEOUT",
+		commConfig.ServerIdleTimeout:          "AAS_SERVER_IDLE_TIMEOUT",
+		commConfig.ServerMaxHeaderBytes:       "AAS_SERVER_MAX_HEADER_BYTES",
+		config.AasServiceUsername:             "AAS_ADMIN_USERNAME",
+		config.AasServicePassword:             "AAS_ADMIN_PASSWORD",
+		config.JwtTokenDurationMins:           "AAS_JWT_TOKEN_DURATION_MINS",
+		config.JwtRefreshTokenDurationMins:    "AAS_JWT_REFRESH_TOKEN_DURATION_MINS",
+		config.JwtCustomClaimsMaxDurationMins: "AAS_JWT_CUSTOM_CLAIMS_TOKEN_MAX_DURATION_MINS",
+		config.JwtIncludeKid:                  "AAS_JWT_INCLUDE_KEYID",
+		config.JwtCertCommonName:              "AAS_JWT_CERT_CN",
+		commConfig.TlsCertFile:                "CERT_PATH",
+		commConfig.TlsKeyFile:                 "KEY_PATH",
 	}
 	for k, v := range alias {
 		if env := os.Getenv(v); env != "" {
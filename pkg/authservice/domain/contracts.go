@@ -6,6 +6,8 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 )
@@ -16,6 +18,10 @@ type (
 		UserStore() UserStore
 		RoleStore() RoleStore
 		PermissionStore() PermissionStore
+		RevokedTokenStore() RevokedTokenStore
+		RefreshTokenStore() RefreshTokenStore
+		LDAPGroupMappingStore() LDAPGroupMappingStore
+		PasswordHistoryStore() PasswordHistoryStore
 		Close()
 	}
 
@@ -33,6 +39,8 @@ type (
 		RetrieveAll(*types.RoleSearch) (types.Roles, error)
 		Update(types.Role) error
 		Delete(types.Role) error
+		// GetUsers retrieves the users holding the role
+		GetUsers(types.Role) (types.Users, error)
 	}
 
 	UserStore interface {
@@ -46,5 +54,50 @@ type (
 		AddRoles(types.User, types.Roles, bool) error
 		GetUserRoleByID(types.User, string) (types.Role, error)
 		DeleteRole(types.User, string, []string) error
+		// UpdateLockout updates only the failed logins and the lockout of the user
+		UpdateLockout(types.User) error
+		// RecordFailedLogin increments the failed logins of the user, counted again from zero when the lockout
+		// started before lockoutEnd, and locks the account when they reach maxAttempts. It returns the user with the
+		// updated failed logins and lockout.
+		RecordFailedLogin(user types.User, maxAttempts int, lockoutEnd time.Time) (*types.User, error)
+	}
+
+	PasswordHistoryStore interface {
+		Create(types.PasswordHistory) (*types.PasswordHistory, error)
+		// RetrieveAll retrieves the most recent previous passwords of the user, up to limit
+		RetrieveAll(userID string, limit int) (types.PasswordHistories, error)
+		// DeleteOldest deletes the previous passwords of the user except the keep most recent ones
+		DeleteOldest(userID string, keep int) error
+	}
+
+	RevokedTokenStore interface {
+		Create(types.RevokedToken) (*types.RevokedToken, error)
+		RetrieveAll(createdSince time.Time) (types.RevokedTokens, error)
+		DeleteExpired(time.Time) error
+	}
+
+	RefreshTokenStore interface {
+		Create(types.RefreshToken) (*types.RefreshToken, error)
+		Retrieve(tokenHash []byte) (*types.RefreshToken, error)
+		Delete(types.RefreshToken) error
+		DeleteByUser(userID string) error
+		DeleteExpired(time.Time) error
+	}
+
+	LDAPGroupMappingStore interface {
+		Create(types.LDAPGroupMapping) (*types.LDAPGroupMapping, error)
+		Retrieve(id string) (*types.LDAPGroupMapping, error)
+		RetrieveAll(groups []string) (types.LDAPGroupMappings, error)
+		Delete(types.LDAPGroupMapping) error
+	}
+
+	// LDAPAuthenticator authenticates the users against an LDAP directory
+	LDAPAuthenticator interface {
+		Authenticate(username, password string) (*types.LDAPUser, error)
+	}
+
+	// OIDCVerifier verifies the ID tokens of an OpenID Connect provider
+	OIDCVerifier interface {
+		Verify(idToken string) (*types.OIDCIdentity, error)
 	}
 )
@@ -36,6 +36,8 @@ Usage of authservice setup:
 		admin                    Add authservice admin username and password to database and assign respective 
 		                         roles to the user
 		jwt                      Create jwt signing key and jwt certificate signed by CMS
+		rotate-jwt-signing-key   Publish the next jwt signing key and certificate signed by CMS in the JWKS when run
+		                         the first time, make it the active jwt signing key when run again
 		create-credentials       Generates credentials to support third party authentication and authorization
 		update-service-config    Sets or Updates the Service configuration `
 
@@ -0,0 +1,184 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+// ErrInvalidCredentials is returned when the user is not found in the directory or the password is wrong
+var ErrInvalidCredentials = errors.New("invalid username or password provided")
+
+// noAttributes is the attribute list of a search returning only the DN of the entries
+var noAttributes = []string{"1.1"}
+
+// Authenticator authenticates the users by binding to the LDAP directory with their credentials, after searching
+// the DN of the user entry.
+type Authenticator struct {
+	cfg       config.LDAPConfig
+	url       string
+	tlsConfig *tls.Config
+}
+
+// NewAuthenticator creates an authenticator for the LDAP directory. The connection to the directory must be secured
+// with TLS, either with a ldaps:// URL or with StartTLS. The certificate of the directory is verified with rootCAs.
+func NewAuthenticator(cfg config.LDAPConfig, rootCAs *x509.CertPool) (*Authenticator, error) {
+	defaultLog.Trace("ldap/ldap:NewAuthenticator() Entering")
+	defer defaultLog.Trace("ldap/ldap:NewAuthenticator() Leaving")
+
+	ldapURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid LDAP URL")
+	}
+	switch ldapURL.Scheme {
+	case "ldaps":
+	case "ldap":
+		if !cfg.StartTLS {
+			return nil, errors.New("LDAP URL with ldap:// scheme requires start-tls to be enabled")
+		}
+	default:
+		return nil, errors.Errorf("Unsupported LDAP URL scheme %s", ldapURL.Scheme)
+	}
+	if cfg.UserSearchBase == "" {
+		return nil, errors.New("LDAP user search base is required")
+	}
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = constants.DefaultLdapUserFilter
+	}
+	if !strings.Contains(cfg.UserFilter, "%s") {
+		return nil, errors.New("LDAP user filter must contain %s to be replaced with the username")
+	}
+	if cfg.GroupSearchBase == "" && cfg.GroupAttribute == "" {
+		cfg.GroupAttribute = constants.DefaultLdapGroupAttribute
+	}
+	if cfg.GroupFilter == "" {
+		cfg.GroupFilter = constants.DefaultLdapGroupFilter
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = constants.DefaultLdapTimeout
+	}
+
+	return &Authenticator{
+		cfg: cfg,
+		url: cfg.URL,
+		tlsConfig: &tls.Config{
+			RootCAs:    rootCAs,
+			ServerName: ldapURL.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		},
+	}, nil
+}
+
+// Authenticate binds to the directory as the user and returns the DN and the groups of the user
+func (a *Authenticator) Authenticate(username, password string) (*types.LDAPUser, error) {
+	defaultLog.Trace("ldap/ldap:Authenticate() Entering")
+	defer defaultLog.Trace("ldap/ldap:Authenticate() Leaving")
+
+	// an empty password would be an unauthenticated bind, which succeeds on most directories
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := a.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = a.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+
+	attributes := noAttributes
+	if a.cfg.GroupSearchBase == "" {
+		attributes = []string{a.cfg.GroupAttribute}
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(a.cfg.UserSearchBase, ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases, 2, int(a.cfg.Timeout.Seconds()), false,
+		strings.ReplaceAll(a.cfg.UserFilter, "%s", ldap.EscapeFilter(username)), attributes, nil))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not search the user in the LDAP directory")
+	}
+	if len(result.Entries) == 0 {
+		defaultLog.Debugf("ldap/ldap:Authenticate() User %s not found in the LDAP directory", username)
+		return nil, ErrInvalidCredentials
+	}
+	if len(result.Entries) > 1 {
+		return nil, errors.Errorf("Multiple entries found in the LDAP directory for user %s", username)
+	}
+	userEntry := result.Entries[0]
+
+	if err = conn.Bind(userEntry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, errors.Wrap(err, "Could not bind to the LDAP directory as the user")
+	}
+
+	user := &types.LDAPUser{DN: userEntry.DN}
+	if a.cfg.GroupSearchBase == "" {
+		user.Groups = userEntry.GetAttributeValues(a.cfg.GroupAttribute)
+		return user, nil
+	}
+
+	// the groups are searched with the service account, the user may not be allowed to search them
+	if err = a.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+	result, err = conn.Search(ldap.NewSearchRequest(a.cfg.GroupSearchBase, ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases, 0, int(a.cfg.Timeout.Seconds()), false,
+		strings.ReplaceAll(a.cfg.GroupFilter, "%s", ldap.EscapeFilter(userEntry.DN)), noAttributes, nil))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not search the groups of the user in the LDAP directory")
+	}
+	for _, groupEntry := range result.Entries {
+		user.Groups = append(user.Groups, groupEntry.DN)
+	}
+	return user, nil
+}
+
+func (a *Authenticator) connect() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(a.url, ldap.DialWithTLSConfig(a.tlsConfig),
+		ldap.DialWithDialer(&net.Dialer{Timeout: a.cfg.Timeout}))
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not connect to the LDAP directory")
+	}
+	conn.SetTimeout(a.cfg.Timeout)
+
+	if a.cfg.StartTLS && strings.HasPrefix(a.url, "ldap://") {
+		if err = conn.StartTLS(a.tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "Could not start TLS on the connection to the LDAP directory")
+		}
+	}
+	return conn, nil
+}
+
+// bindServiceAccount binds with the credentials of the service account, the connection is left anonymous without them
+func (a *Authenticator) bindServiceAccount(conn *ldap.Conn) error {
+	if a.cfg.BindDN == "" {
+		if err := conn.UnauthenticatedBind(""); err != nil {
+			return errors.Wrap(err, "Could not bind anonymously to the LDAP directory")
+		}
+		return nil
+	}
+	if err := conn.Bind(a.cfg.BindDN, a.cfg.BindPassword); err != nil {
+		return errors.Wrap(err, "Could not bind to the LDAP directory with the service account")
+	}
+	return nil
+}
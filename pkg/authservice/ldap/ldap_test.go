@@ -0,0 +1,449 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package ldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
+)
+
+const (
+	ldapBindRequest     = 0
+	ldapBindResponse    = 1
+	ldapUnbindRequest   = 2
+	ldapSearchRequest   = 3
+	ldapSearchEntry     = 4
+	ldapSearchDone      = 5
+	ldapSuccess         = 0
+	ldapNoSuchObject    = 32
+	ldapInvalidCreds    = 49
+	ldapInsufficient    = 50
+	ldapProtocolError   = 2
+	filterAnd           = 0
+	filterOr            = 1
+	filterEqualityMatch = 3
+	filterPresent       = 7
+)
+
+type stubEntry struct {
+	password   string
+	attributes map[string][]string
+}
+
+// stubDirectory is an in-process LDAP server supporting the simple bind and the search operations used by the
+// authenticator, with equality, presence, and and or filters.
+type stubDirectory struct {
+	entries map[string]stubEntry
+	// anonymousSearch allows searching without binding first
+	anonymousSearch bool
+	listener        net.Listener
+}
+
+func newStubDirectory(t *testing.T, entries map[string]stubEntry, anonymousSearch bool) (*stubDirectory, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	certDer, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(certDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(cert)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{certDer}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lowerEntries := make(map[string]stubEntry)
+	for dn, entry := range entries {
+		lowerEntries[strings.ToLower(dn)] = entry
+	}
+	directory := &stubDirectory{entries: lowerEntries, anonymousSearch: anonymousSearch, listener: listener}
+	go directory.serve()
+	t.Cleanup(func() { listener.Close() })
+	return directory, rootCAs
+}
+
+func (d *stubDirectory) url() string {
+	return "ldaps://localhost:" + strings.Split(d.listener.Addr().String(), ":")[1]
+}
+
+func (d *stubDirectory) serve() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		go d.handle(conn)
+	}
+}
+
+func (d *stubDirectory) handle(conn net.Conn) {
+	defer conn.Close()
+	bound := false
+	for {
+		request, err := ber.ReadPacket(conn)
+		if err != nil || len(request.Children) < 2 {
+			return
+		}
+		messageID := request.Children[0].Value.(int64)
+		operation := request.Children[1]
+		switch operation.Tag {
+		case ldapBindRequest:
+			name := operation.Children[1].Value.(string)
+			password := operation.Children[2].Data.String()
+			resultCode := ldapInvalidCreds
+			if entry, ok := d.entries[strings.ToLower(name)]; (ok && entry.password == password) ||
+				(name == "" && password == "") {
+				resultCode = ldapSuccess
+			}
+			bound = resultCode == ldapSuccess && name != ""
+			conn.Write(ldapResult(messageID, ldapBindResponse, resultCode).Bytes())
+		case ldapSearchRequest:
+			if !bound && !d.anonymousSearch {
+				conn.Write(ldapResult(messageID, ldapSearchDone, ldapInsufficient).Bytes())
+				continue
+			}
+			d.search(conn, messageID, operation)
+		case ldapUnbindRequest:
+			return
+		default:
+			conn.Write(ldapResult(messageID, ldapSearchDone, ldapProtocolError).Bytes())
+		}
+	}
+}
+
+func (d *stubDirectory) search(conn net.Conn, messageID int64, operation *ber.Packet) {
+	baseDN := strings.ToLower(operation.Children[0].Value.(string))
+	filter := operation.Children[6]
+	var attributes []string
+	for _, attribute := range operation.Children[7].Children {
+		attributes = append(attributes, attribute.Value.(string))
+	}
+
+	if _, ok := d.entries[baseDN]; !ok {
+		conn.Write(ldapResult(messageID, ldapSearchDone, ldapNoSuchObject).Bytes())
+		return
+	}
+	for dn, entry := range d.entries {
+		if !strings.HasSuffix(dn, ","+baseDN) || !matchFilter(filter, entry) {
+			continue
+		}
+		response := ldapMessage(messageID)
+		searchEntry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapSearchEntry, nil, "Search Result Entry")
+		searchEntry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "Object Name"))
+		partialAttributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for _, attribute := range attributes {
+			values, ok := entry.attributes[attribute]
+			if !ok {
+				continue
+			}
+			partialAttribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			partialAttribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute, "Type"))
+			valueSet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				valueSet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			partialAttribute.AppendChild(valueSet)
+			partialAttributes.AppendChild(partialAttribute)
+		}
+		searchEntry.AppendChild(partialAttributes)
+		response.AppendChild(searchEntry)
+		conn.Write(response.Bytes())
+	}
+	conn.Write(ldapResult(messageID, ldapSearchDone, ldapSuccess).Bytes())
+}
+
+func matchFilter(filter *ber.Packet, entry stubEntry) bool {
+	switch filter.Tag {
+	case filterAnd:
+		for _, child := range filter.Children {
+			if !matchFilter(child, entry) {
+				return false
+			}
+		}
+		return true
+	case filterOr:
+		for _, child := range filter.Children {
+			if matchFilter(child, entry) {
+				return true
+			}
+		}
+		return false
+	case filterEqualityMatch:
+		attribute := filter.Children[0].Value.(string)
+		assertion := filter.Children[1].Value.(string)
+		for _, value := range entry.attributes[attribute] {
+			if strings.EqualFold(value, assertion) {
+				return true
+			}
+		}
+		return false
+	case filterPresent:
+		_, ok := entry.attributes[filter.Data.String()]
+		return ok
+	}
+	return false
+}
+
+func ldapMessage(messageID int64) *ber.Packet {
+	message := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	return message
+}
+
+func ldapResult(messageID int64, tag ber.Tag, resultCode int) *ber.Packet {
+	message := ldapMessage(messageID)
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	message.AppendChild(result)
+	return message
+}
+
+var testEntries = map[string]stubEntry{
+	"dc=example,dc=com":           {},
+	"ou=users,dc=example,dc=com":  {},
+	"ou=groups,dc=example,dc=com": {},
+	"cn=aas,ou=services,dc=example,dc=com": {
+		password: "servicePassword",
+	},
+	"uid=alice,ou=users,dc=example,dc=com": {
+		password: "alicePassword",
+		attributes: map[string][]string{
+			"uid":      {"alice"},
+			"memberOf": {"cn=kbs-admins,ou=groups,dc=example,dc=com", "cn=hvs-admins,ou=groups,dc=example,dc=com"},
+		},
+	},
+	"uid=bob,ou=users,dc=example,dc=com": {
+		password: "bobPassword",
+		attributes: map[string][]string{
+			"uid": {"bob"},
+		},
+	},
+	"cn=kbs-admins,ou=groups,dc=example,dc=com": {
+		attributes: map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"member":      {"uid=alice,ou=users,dc=example,dc=com"},
+		},
+	},
+	"cn=hvs-admins,ou=groups,dc=example,dc=com": {
+		attributes: map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"member":      {"uid=alice,ou=users,dc=example,dc=com", "uid=bob,ou=users,dc=example,dc=com"},
+		},
+	},
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	directory, rootCAs := newStubDirectory(t, testEntries, false)
+	anonymousDirectory, anonymousRootCAs := newStubDirectory(t, testEntries, true)
+
+	serviceAccountConfig := config.LDAPConfig{
+		URL:            directory.url(),
+		BindDN:         "cn=aas,ou=services,dc=example,dc=com",
+		BindPassword:   "servicePassword",
+		UserSearchBase: "ou=users,dc=example,dc=com",
+		UserFilter:     "(uid=%s)",
+	}
+	groupSearchConfig := serviceAccountConfig
+	groupSearchConfig.GroupSearchBase = "ou=groups,dc=example,dc=com"
+	groupSearchConfig.GroupFilter = "(&(objectClass=groupOfNames)(member=%s))"
+	anonymousConfig := config.LDAPConfig{
+		URL:            anonymousDirectory.url(),
+		UserSearchBase: "ou=users,dc=example,dc=com",
+	}
+	wrongServicePasswordConfig := serviceAccountConfig
+	wrongServicePasswordConfig.BindPassword = "wrongPassword"
+
+	tests := []struct {
+		name       string
+		cfg        config.LDAPConfig
+		rootCAs    *x509.CertPool
+		username   string
+		password   string
+		wantDN     string
+		wantGroups []string
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "Validate Authenticate with groups from memberOf",
+			cfg:        serviceAccountConfig,
+			rootCAs:    rootCAs,
+			username:   "alice",
+			password:   "alicePassword",
+			wantDN:     "uid=alice,ou=users,dc=example,dc=com",
+			wantGroups: []string{"cn=kbs-admins,ou=groups,dc=example,dc=com", "cn=hvs-admins,ou=groups,dc=example,dc=com"},
+		},
+		{
+			name:       "Validate Authenticate with groups from group search",
+			cfg:        groupSearchConfig,
+			rootCAs:    rootCAs,
+			username:   "bob",
+			password:   "bobPassword",
+			wantDN:     "uid=bob,ou=users,dc=example,dc=com",
+			wantGroups: []string{"cn=hvs-admins,ou=groups,dc=example,dc=com"},
+		},
+		{
+			name:     "Validate Authenticate with anonymous search",
+			cfg:      anonymousConfig,
+			rootCAs:  anonymousRootCAs,
+			username: "bob",
+			password: "bobPassword",
+			wantDN:   "uid=bob,ou=users,dc=example,dc=com",
+		},
+		{
+			name:     "Validate Authenticate with wrong password",
+			cfg:      serviceAccountConfig,
+			rootCAs:  rootCAs,
+			username: "alice",
+			password: "bobPassword",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "Validate Authenticate with unknown user",
+			cfg:      serviceAccountConfig,
+			rootCAs:  rootCAs,
+			username: "carol",
+			password: "carolPassword",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "Validate Authenticate with empty password",
+			cfg:      serviceAccountConfig,
+			rootCAs:  rootCAs,
+			username: "alice",
+			password: "",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "Validate Authenticate with filter injection in username",
+			cfg:      serviceAccountConfig,
+			rootCAs:  rootCAs,
+			username: "*",
+			password: "alicePassword",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:       "Validate Authenticate with wrong service account password",
+			cfg:        wrongServicePasswordConfig,
+			rootCAs:    rootCAs,
+			username:   "alice",
+			password:   "alicePassword",
+			wantAnyErr: true,
+		},
+		{
+			name:       "Validate Authenticate with untrusted directory certificate",
+			cfg:        serviceAccountConfig,
+			rootCAs:    anonymousRootCAs,
+			username:   "alice",
+			password:   "alicePassword",
+			wantAnyErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := NewAuthenticator(tt.cfg, tt.rootCAs)
+			if err != nil {
+				t.Fatalf("NewAuthenticator() error = %v", err)
+			}
+			user, err := authenticator.Authenticate(tt.username, tt.password)
+			if tt.wantErr != nil || tt.wantAnyErr {
+				if err == nil || (tt.wantErr != nil && err != tt.wantErr) {
+					t.Errorf("Authenticator.Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticator.Authenticate() error = %v", err)
+			}
+			if !strings.EqualFold(user.DN, tt.wantDN) {
+				t.Errorf("Authenticator.Authenticate() DN = %s, want %s", user.DN, tt.wantDN)
+			}
+			if len(user.Groups) != 0 || len(tt.wantGroups) != 0 {
+				if !reflect.DeepEqual(user.Groups, tt.wantGroups) {
+					t.Errorf("Authenticator.Authenticate() groups = %v, want %v", user.Groups, tt.wantGroups)
+				}
+			}
+		})
+	}
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.LDAPConfig
+		wantErr bool
+	}{
+		{
+			name:    "Validate NewAuthenticator with ldaps URL",
+			cfg:     config.LDAPConfig{URL: "ldaps://ad.example.com:636", UserSearchBase: "dc=example,dc=com"},
+			wantErr: false,
+		},
+		{
+			name: "Validate NewAuthenticator with ldap URL and StartTLS",
+			cfg: config.LDAPConfig{URL: "ldap://ad.example.com:389", StartTLS: true,
+				UserSearchBase: "dc=example,dc=com"},
+			wantErr: false,
+		},
+		{
+			name:    "Validate NewAuthenticator with ldap URL without StartTLS",
+			cfg:     config.LDAPConfig{URL: "ldap://ad.example.com:389", UserSearchBase: "dc=example,dc=com"},
+			wantErr: true,
+		},
+		{
+			name:    "Validate NewAuthenticator without user search base",
+			cfg:     config.LDAPConfig{URL: "ldaps://ad.example.com:636"},
+			wantErr: true,
+		},
+		{
+			name: "Validate NewAuthenticator with user filter without username placeholder",
+			cfg: config.LDAPConfig{URL: "ldaps://ad.example.com:636", UserSearchBase: "dc=example,dc=com",
+				UserFilter: "(uid=admin)"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(tt.cfg, nil); (err != nil) != tt.wantErr {
+				t.Errorf("NewAuthenticator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
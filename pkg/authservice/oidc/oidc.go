@@ -0,0 +1,320 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package oidc
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwtgo "github.com/Waterdrips/jwt-go"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
+	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+// ErrInvalidToken is returned when the ID token is not a valid token of the provider for AAS
+var ErrInvalidToken = errors.New("invalid ID token provided")
+
+const (
+	// discoveryPath is the path of the provider metadata relative to the issuer URL
+	discoveryPath = "/.well-known/openid-configuration"
+	// maxResponseSize is the maximum size of the provider metadata and of the JWKS
+	maxResponseSize = 1 << 20
+	// jwksMinRefreshInterval limits the refreshes of the JWKS triggered by tokens signed with unknown keys
+	jwksMinRefreshInterval = time.Minute
+	// jwksMaxAge is the time after which the cached JWKS is refreshed, to stop trusting the keys removed by the provider
+	jwksMaxAge = time.Hour
+	// clockSkew is the clock difference with the provider tolerated when validating the token times
+	clockSkew = time.Minute
+)
+
+// validMethods are the signing algorithms of the ID tokens accepted, the symmetric algorithms and none are rejected
+var validMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Provider verifies the ID tokens issued by an OpenID Connect provider to AAS, with the keys of the provider JWKS
+type Provider struct {
+	cfg    config.OIDCConfig
+	client *http.Client
+
+	lock          sync.Mutex
+	jwksURL       string
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// fetchError is an error retrieving the metadata or the keys of the provider, as opposed to an invalid token
+type fetchError struct {
+	error
+}
+
+// NewProvider creates the verifier of the ID tokens of the provider. The provider metadata and keys are retrieved
+// when the first token is verified, so that AAS starts when the provider is not available.
+func NewProvider(cfg config.OIDCConfig, rootCAs *x509.CertPool) (*Provider, error) {
+	defaultLog.Trace("oidc/oidc:NewProvider() Entering")
+	defer defaultLog.Trace("oidc/oidc:NewProvider() Leaving")
+
+	issuerURL, err := url.Parse(cfg.IssuerURL)
+	if err != nil || issuerURL.Scheme != "https" || issuerURL.Host == "" {
+		return nil, errors.New("OIDC issuer URL must be a https:// URL")
+	}
+	if cfg.JWKSURL != "" {
+		jwksURL, err := url.Parse(cfg.JWKSURL)
+		if err != nil || jwksURL.Scheme != "https" || jwksURL.Host == "" {
+			return nil, errors.New("OIDC JWKS URL must be a https:// URL")
+		}
+	}
+	if cfg.ClientID == "" {
+		return nil, errors.New("OIDC client ID is required")
+	}
+	for _, mapping := range cfg.ClaimMappings {
+		if mapping.Claim == "" || mapping.Value == "" || len(mapping.Roles) == 0 {
+			return nil, errors.Errorf("OIDC claim mapping %s=%s must have a claim, a value and roles", mapping.Claim, mapping.Value)
+		}
+		for _, role := range mapping.Roles {
+			if role.Service == "" || role.Name == "" {
+				return nil, errors.Errorf("OIDC claim mapping %s=%s has a role without service or name", mapping.Claim, mapping.Value)
+			}
+		}
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = constants.DefaultOidcUsernameClaim
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = constants.DefaultOidcTimeout
+	}
+
+	return &Provider{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					RootCAs:    rootCAs,
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+		},
+		jwksURL: cfg.JWKSURL,
+	}, nil
+}
+
+// Verify validates the signature, the issuer, the audience and the validity of the ID token and returns the user
+// identified by the token, with the roles mapped to the claims of the token
+func (p *Provider) Verify(idToken string) (*types.OIDCIdentity, error) {
+	defaultLog.Trace("oidc/oidc:Verify() Entering")
+	defer defaultLog.Trace("oidc/oidc:Verify() Leaving")
+
+	claims := jwtgo.MapClaims{}
+	parser := jwtgo.Parser{ValidMethods: validMethods, SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(idToken, claims, p.keyFunc)
+	if err != nil {
+		if validationErr, ok := err.(*jwtgo.ValidationError); ok {
+			if fetchErr, ok := validationErr.Inner.(fetchError); ok {
+				return nil, fetchErr.error
+			}
+		}
+		defaultLog.WithError(err).Debug("oidc/oidc:Verify() Could not verify ID token")
+		return nil, ErrInvalidToken
+	}
+
+	if err = p.validateClaims(claims); err != nil {
+		defaultLog.WithError(err).Debug("oidc/oidc:Verify() Invalid ID token claims")
+		return nil, ErrInvalidToken
+	}
+
+	subject, _ := claims["sub"].(string)
+	username, _ := claims[p.cfg.UsernameClaim].(string)
+	if subject == "" || username == "" {
+		defaultLog.Debugf("oidc/oidc:Verify() ID token has no sub or %s claim", p.cfg.UsernameClaim)
+		return nil, ErrInvalidToken
+	}
+
+	identity := &types.OIDCIdentity{Subject: subject, Username: username, Issuer: p.cfg.IssuerURL}
+	for _, mapping := range p.cfg.ClaimMappings {
+		identity.MappingRoles = appendRoles(identity.MappingRoles, mapping.Roles)
+		if containsString(claimValues(claims[mapping.Claim]), mapping.Value) {
+			identity.Roles = appendRoles(identity.Roles, mapping.Roles)
+		}
+	}
+	return identity, nil
+}
+
+func (p *Provider) validateClaims(claims jwtgo.MapClaims) error {
+	now := time.Now()
+	if !claims.VerifyIssuer(p.cfg.IssuerURL, true) {
+		return errors.New("issuer of the token is not the provider")
+	}
+
+	audience := claimValues(claims["aud"])
+	if !containsString(audience, p.cfg.ClientID) {
+		return errors.New("audience of the token is not AAS")
+	}
+	// the authorized party must be AAS when the token is issued for multiple audiences
+	if azp, ok := claims["azp"].(string); ok && len(audience) > 1 && azp != p.cfg.ClientID {
+		return errors.New("authorized party of the token is not AAS")
+	}
+
+	if _, ok := claims["exp"]; !ok || !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), true) {
+		return errors.New("token is expired")
+	}
+	if !claims.VerifyNotBefore(now.Add(clockSkew).Unix(), false) || !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), false) {
+		return errors.New("token is not valid yet")
+	}
+	return nil
+}
+
+// keyFunc returns the key of the provider JWKS the token is signed with
+func (p *Provider) keyFunc(token *jwtgo.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.keys == nil || time.Since(p.keysFetchedAt) > jwksMaxAge {
+		if err := p.refreshKeys(); err != nil {
+			return nil, fetchError{err}
+		}
+	}
+	key, ok := p.lookupKey(kid)
+	// the provider may have rotated its keys
+	if !ok && time.Since(p.keysFetchedAt) > jwksMinRefreshInterval {
+		if err := p.refreshKeys(); err != nil {
+			return nil, fetchError{err}
+		}
+		key, ok = p.lookupKey(kid)
+	}
+	if !ok {
+		return nil, errors.Errorf("no key %s in the JWKS of the provider", kid)
+	}
+	return key, nil
+}
+
+// lookupKey returns the key with the key id, or the only key of the JWKS for the tokens without key id
+func (p *Provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) refreshKeys() error {
+	if p.jwksURL == "" {
+		var metadata struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := p.getJson(strings.TrimSuffix(p.cfg.IssuerURL, "/")+discoveryPath, &metadata); err != nil {
+			return errors.Wrap(err, "Could not retrieve the OIDC provider metadata")
+		}
+		if metadata.Issuer != p.cfg.IssuerURL {
+			return errors.Errorf("OIDC provider metadata issuer %s does not match the issuer URL", metadata.Issuer)
+		}
+		jwksURL, err := url.Parse(metadata.JWKSURI)
+		if err != nil || jwksURL.Scheme != "https" {
+			return errors.New("OIDC provider metadata has no https:// jwks_uri")
+		}
+		p.jwksURL = metadata.JWKSURI
+	}
+
+	var jwks jwtauth.JSONWebKeySet
+	if err := p.getJson(p.jwksURL, &jwks); err != nil {
+		return errors.Wrap(err, "Could not retrieve the OIDC provider JWKS")
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			defaultLog.WithError(err).Warnf("oidc/oidc:refreshKeys() Skipping key %s of the OIDC provider JWKS", jwk.Kid)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	return nil
+}
+
+func (p *Provider) getJson(url string, v interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			defaultLog.WithError(err).Error("Error closing response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// claimValues returns the values of a string or string list claim
+func claimValues(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var values []string
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+func appendRoles(roles []aas.RoleInfo, newRoles []aas.RoleInfo) []aas.RoleInfo {
+	for _, newRole := range newRoles {
+		found := false
+		for _, role := range roles {
+			if role == newRole {
+				found = true
+				break
+			}
+		}
+		if !found {
+			roles = append(roles, newRole)
+		}
+	}
+	return roles
+}
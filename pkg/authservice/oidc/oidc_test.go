@@ -0,0 +1,328 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	jwtgo "github.com/Waterdrips/jwt-go"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+)
+
+const testClientID = "aas"
+
+// stubProvider is an in-process OpenID Connect provider serving the provider metadata and the JWKS
+type stubProvider struct {
+	server      *httptest.Server
+	keys        jwtauth.JSONWebKeySet
+	jwksFetches int
+	unavailable bool
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	provider := &stubProvider{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		if provider.unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   provider.server.URL,
+			"jwks_uri": provider.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		provider.jwksFetches++
+		_ = json.NewEncoder(w).Encode(provider.keys)
+	})
+	provider.server = httptest.NewTLSServer(mux)
+	t.Cleanup(provider.server.Close)
+	return provider
+}
+
+func (s *stubProvider) rootCAs() *x509.CertPool {
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(s.server.Certificate())
+	return rootCAs
+}
+
+func (s *stubProvider) addRSAKey(t *testing.T, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.keys.Keys = append(s.keys.Keys, jwtauth.JSONWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	})
+	return key
+}
+
+func (s *stubProvider) addECKey(t *testing.T, kid string) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.keys.Keys = append(s.keys.Keys, jwtauth.JSONWebKey{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	})
+	return key
+}
+
+func signToken(t *testing.T, method jwtgo.SigningMethod, kid string, key interface{}, claims jwtgo.MapClaims) string {
+	token := jwtgo.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestProvider_Verify(t *testing.T) {
+	stub := newStubProvider(t)
+	rsaKey := stub.addRSAKey(t, "rsa-key")
+	ecKey := stub.addECKey(t, "ec-key")
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminRoles := []aas.RoleInfo{{Service: "AAS", Name: "Administrator"}}
+	keyRoles := []aas.RoleInfo{{Service: "KBS", Name: "KeyCRUD"}, {Service: "AAS", Name: "Administrator"}}
+	provider, err := NewProvider(config.OIDCConfig{
+		IssuerURL: stub.server.URL,
+		ClientID:  testClientID,
+		ClaimMappings: []config.OIDCClaimMapping{
+			{Claim: "groups", Value: "isecl-admins", Roles: adminRoles},
+			{Claim: "department", Value: "security", Roles: keyRoles},
+		},
+	}, stub.rootCAs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validClaims := func() jwtgo.MapClaims {
+		return jwtgo.MapClaims{
+			"iss":                stub.server.URL,
+			"sub":                "248289761001",
+			"aud":                testClientID,
+			"exp":                time.Now().Add(5 * time.Minute).Unix(),
+			"iat":                time.Now().Unix(),
+			"preferred_username": "jane.doe",
+			"groups":             []string{"users", "isecl-admins"},
+		}
+	}
+	withClaim := func(name string, value interface{}) jwtgo.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	multipleAudienceClaims := withClaim("aud", []string{testClientID, "admin-ui"})
+	multipleAudienceClaims["azp"] = "admin-ui"
+
+	tests := []struct {
+		name      string
+		token     string
+		wantRoles []aas.RoleInfo
+		wantErr   error
+	}{
+		{
+			name:      "Validate Verify with valid RSA signed token",
+			token:     signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, validClaims()),
+			wantRoles: adminRoles,
+		},
+		{
+			name:      "Validate Verify with valid EC signed token and multiple mapped claims",
+			token:     signToken(t, jwtgo.SigningMethodES256, "ec-key", ecKey, withClaim("department", "security")),
+			wantRoles: []aas.RoleInfo{adminRoles[0], keyRoles[0]},
+		},
+		{
+			name:  "Validate Verify with token without mapped claims",
+			token: signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, withClaim("groups", nil)),
+		},
+		{
+			name:    "Validate Verify with token signed with other key",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "rsa-key", otherKey, validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with token signed with unknown key",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "other-key", otherKey, validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with HMAC signed token",
+			token:   signToken(t, jwtgo.SigningMethodHS256, "rsa-key", []byte("secret"), validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with token of other issuer",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, withClaim("iss", "https://idp.example.com")),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with token for other audience",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, withClaim("aud", "admin-ui")),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with token authorized for other party",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, multipleAudienceClaims),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with expired token",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, withClaim("exp", time.Now().Add(-5*time.Minute).Unix())),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with token without expiry",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, withClaim("exp", nil)),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with token without username",
+			token:   signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, withClaim("preferred_username", nil)),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Validate Verify with malformed token",
+			token:   "not.a.token",
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := provider.Verify(tt.token)
+			if err != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if identity.Username != "jane.doe" || identity.Subject != "248289761001" || identity.Issuer != stub.server.URL {
+				t.Errorf("Verify() identity = %+v", identity)
+			}
+			if !reflect.DeepEqual(identity.Roles, tt.wantRoles) {
+				t.Errorf("Verify() roles = %v, want %v", identity.Roles, tt.wantRoles)
+			}
+			if wantMappingRoles := []aas.RoleInfo{adminRoles[0], keyRoles[0]}; !reflect.DeepEqual(identity.MappingRoles, wantMappingRoles) {
+				t.Errorf("Verify() mapping roles = %v, want %v", identity.MappingRoles, wantMappingRoles)
+			}
+		})
+	}
+
+	// the JWKS is fetched once, the refresh on unknown keys is rate limited
+	if stub.jwksFetches != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", stub.jwksFetches)
+	}
+}
+
+func TestProvider_VerifyProviderUnavailable(t *testing.T) {
+	stub := newStubProvider(t)
+	rsaKey := stub.addRSAKey(t, "rsa-key")
+	stub.unavailable = true
+
+	provider, err := NewProvider(config.OIDCConfig{IssuerURL: stub.server.URL, ClientID: testClientID}, stub.rootCAs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := signToken(t, jwtgo.SigningMethodRS256, "rsa-key", rsaKey, jwtgo.MapClaims{
+		"iss":                stub.server.URL,
+		"sub":                "248289761001",
+		"aud":                testClientID,
+		"exp":                time.Now().Add(5 * time.Minute).Unix(),
+		"preferred_username": "jane.doe",
+	})
+
+	// a provider that is not available is not reported as an invalid token
+	if _, err = provider.Verify(token); err == nil || err == ErrInvalidToken {
+		t.Fatalf("Verify() error = %v, want provider error", err)
+	}
+
+	stub.unavailable = false
+	if _, err = provider.Verify(token); err != nil {
+		t.Fatalf("Verify() error = %v once the provider is available", err)
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.OIDCConfig
+		wantErr bool
+	}{
+		{
+			name:    "Validate NewProvider with issuer URL and client ID",
+			cfg:     config.OIDCConfig{IssuerURL: "https://idp.example.com/realms/isecl", ClientID: testClientID},
+			wantErr: false,
+		},
+		{
+			name:    "Validate NewProvider with http issuer URL",
+			cfg:     config.OIDCConfig{IssuerURL: "http://idp.example.com/realms/isecl", ClientID: testClientID},
+			wantErr: true,
+		},
+		{
+			name: "Validate NewProvider with http JWKS URL",
+			cfg: config.OIDCConfig{IssuerURL: "https://idp.example.com/realms/isecl", ClientID: testClientID,
+				JWKSURL: "http://idp.example.com/jwks"},
+			wantErr: true,
+		},
+		{
+			name:    "Validate NewProvider without client ID",
+			cfg:     config.OIDCConfig{IssuerURL: "https://idp.example.com/realms/isecl"},
+			wantErr: true,
+		},
+		{
+			name: "Validate NewProvider with claim mapping without roles",
+			cfg: config.OIDCConfig{IssuerURL: "https://idp.example.com/realms/isecl", ClientID: testClientID,
+				ClaimMappings: []config.OIDCClaimMapping{{Claim: "groups", Value: "isecl-admins"}}},
+			wantErr: true,
+		},
+		{
+			name: "Validate NewProvider with claim mapping to role without name",
+			cfg: config.OIDCConfig{IssuerURL: "https://idp.example.com/realms/isecl", ClientID: testClientID,
+				ClaimMappings: []config.OIDCClaimMapping{{Claim: "groups", Value: "isecl-admins",
+					Roles: []aas.RoleInfo{{Service: "AAS"}}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewProvider(tt.cfg, nil); (err != nil) != tt.wantErr {
+				t.Errorf("NewProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -9,9 +9,14 @@ import (
 )
 
 type MockDatabase struct {
-	MockUserStore       MockUserStore
-	MockRoleStore       MockRoleStore
-	MockPermissionStore MockPermissionStore
+	MockUserStore         MockUserStore
+	MockRoleStore         MockRoleStore
+	MockPermissionStore   MockPermissionStore
+	MockRevokedTokenStore MockRevokedTokenStore
+	MockRefreshTokenStore MockRefreshTokenStore
+
+	MockLDAPGroupMappingStore MockLDAPGroupMappingStore
+	MockPasswordHistoryStore  MockPasswordHistoryStore
 }
 
 func (m *MockDatabase) Migrate() error {
@@ -30,6 +35,22 @@ func (m *MockDatabase) PermissionStore() domain.PermissionStore {
 	return &m.MockPermissionStore
 }
 
+func (m *MockDatabase) RevokedTokenStore() domain.RevokedTokenStore {
+	return &m.MockRevokedTokenStore
+}
+
+func (m *MockDatabase) RefreshTokenStore() domain.RefreshTokenStore {
+	return &m.MockRefreshTokenStore
+}
+
+func (m *MockDatabase) LDAPGroupMappingStore() domain.LDAPGroupMappingStore {
+	return &m.MockLDAPGroupMappingStore
+}
+
+func (m *MockDatabase) PasswordHistoryStore() domain.PasswordHistoryStore {
+	return &m.MockPasswordHistoryStore
+}
+
 func (m *MockDatabase) Close() {
 
 }
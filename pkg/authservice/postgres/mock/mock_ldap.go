@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mock
+
+import (
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+)
+
+type MockLDAPGroupMappingStore struct {
+	CreateFunc      func(types.LDAPGroupMapping) (*types.LDAPGroupMapping, error)
+	RetrieveFunc    func(string) (*types.LDAPGroupMapping, error)
+	RetrieveAllFunc func([]string) (types.LDAPGroupMappings, error)
+	DeleteFunc      func(types.LDAPGroupMapping) error
+}
+
+func (m *MockLDAPGroupMappingStore) Create(mapping types.LDAPGroupMapping) (*types.LDAPGroupMapping, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(mapping)
+	}
+	return nil, nil
+}
+
+func (m *MockLDAPGroupMappingStore) Retrieve(id string) (*types.LDAPGroupMapping, error) {
+	if m.RetrieveFunc != nil {
+		return m.RetrieveFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockLDAPGroupMappingStore) RetrieveAll(groups []string) (types.LDAPGroupMappings, error) {
+	if m.RetrieveAllFunc != nil {
+		return m.RetrieveAllFunc(groups)
+	}
+	return nil, nil
+}
+
+func (m *MockLDAPGroupMappingStore) Delete(mapping types.LDAPGroupMapping) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(mapping)
+	}
+	return nil
+}
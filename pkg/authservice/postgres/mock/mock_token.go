@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mock
+
+import (
+	"time"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+)
+
+type MockRevokedTokenStore struct {
+	CreateFunc        func(types.RevokedToken) (*types.RevokedToken, error)
+	RetrieveAllFunc   func(time.Time) (types.RevokedTokens, error)
+	DeleteExpiredFunc func(time.Time) error
+}
+
+func (m *MockRevokedTokenStore) Create(revokedToken types.RevokedToken) (*types.RevokedToken, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(revokedToken)
+	}
+	return nil, nil
+}
+
+func (m *MockRevokedTokenStore) RetrieveAll(createdSince time.Time) (types.RevokedTokens, error) {
+	if m.RetrieveAllFunc != nil {
+		return m.RetrieveAllFunc(createdSince)
+	}
+	return nil, nil
+}
+
+func (m *MockRevokedTokenStore) DeleteExpired(expiredAt time.Time) error {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(expiredAt)
+	}
+	return nil
+}
+
+type MockRefreshTokenStore struct {
+	CreateFunc        func(types.RefreshToken) (*types.RefreshToken, error)
+	RetrieveFunc      func([]byte) (*types.RefreshToken, error)
+	DeleteFunc        func(types.RefreshToken) error
+	DeleteByUserFunc  func(string) error
+	DeleteExpiredFunc func(time.Time) error
+}
+
+func (m *MockRefreshTokenStore) Create(refreshToken types.RefreshToken) (*types.RefreshToken, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(refreshToken)
+	}
+	return nil, nil
+}
+
+func (m *MockRefreshTokenStore) Retrieve(tokenHash []byte) (*types.RefreshToken, error) {
+	if m.RetrieveFunc != nil {
+		return m.RetrieveFunc(tokenHash)
+	}
+	return nil, nil
+}
+
+func (m *MockRefreshTokenStore) Delete(refreshToken types.RefreshToken) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(refreshToken)
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenStore) DeleteByUser(userID string) error {
+	if m.DeleteByUserFunc != nil {
+		return m.DeleteByUserFunc(userID)
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenStore) DeleteExpired(expiredAt time.Time) error {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(expiredAt)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package mock
 
 import (
 	"errors"
+	"time"
 
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
@@ -17,9 +18,11 @@ type MockUserStore struct {
 	RetrieveAllFunc func(types.User) (types.Users, error)
 	UpdateFunc      func(types.User) error
 	DeleteFunc      func(types.User) error
-	UserStore       []types.User
-	RoleStore       []types.Role
-	PermissionStore []types.Permission
+	// UpdateLockoutFunc defaults to updating the lockout of the user in UserStore
+	UpdateLockoutFunc func(types.User) error
+	UserStore         []types.User
+	RoleStore         []types.Role
+	PermissionStore   []types.Permission
 }
 
 func (m *MockUserStore) Create(user types.User) (*types.User, error) {
@@ -57,6 +60,41 @@ func (m *MockUserStore) Delete(user types.User) error {
 	return nil
 }
 
+func (m *MockUserStore) UpdateLockout(user types.User) error {
+	if m.UpdateLockoutFunc != nil {
+		return m.UpdateLockoutFunc(user)
+	}
+	for index, u := range m.UserStore {
+		if u.ID == user.ID {
+			m.UserStore[index].FailedLoginAttempts = user.FailedLoginAttempts
+			m.UserStore[index].LockedAt = user.LockedAt
+		}
+	}
+	return nil
+}
+
+func (m *MockUserStore) RecordFailedLogin(user types.User, maxAttempts int, lockoutEnd time.Time) (*types.User, error) {
+	for index, u := range m.UserStore {
+		if u.ID != user.ID {
+			continue
+		}
+		if u.LockedAt != nil && u.LockedAt.Before(lockoutEnd) {
+			u.FailedLoginAttempts = 0
+			u.LockedAt = nil
+		}
+		u.FailedLoginAttempts++
+		if u.FailedLoginAttempts < maxAttempts {
+			u.LockedAt = nil
+		} else if u.LockedAt == nil {
+			now := time.Now()
+			u.LockedAt = &now
+		}
+		m.UserStore[index] = u
+		return &u, nil
+	}
+	return nil, errors.New("record not found")
+}
+
 func (m *MockUserStore) GetUserRoleByID(u types.User, roleID string) (types.Role, error) {
 	for _, user := range m.UserStore {
 		if u.ID == user.ID && user.Roles == nil {
@@ -122,3 +160,30 @@ func (m *MockUserStore) DeleteRole(u types.User, roleID string, svcFltr []string
 	}
 	return nil
 }
+
+type MockPasswordHistoryStore struct {
+	CreateFunc       func(types.PasswordHistory) (*types.PasswordHistory, error)
+	RetrieveAllFunc  func(string, int) (types.PasswordHistories, error)
+	DeleteOldestFunc func(string, int) error
+}
+
+func (m *MockPasswordHistoryStore) Create(h types.PasswordHistory) (*types.PasswordHistory, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(h)
+	}
+	return nil, nil
+}
+
+func (m *MockPasswordHistoryStore) RetrieveAll(userID string, limit int) (types.PasswordHistories, error) {
+	if m.RetrieveAllFunc != nil {
+		return m.RetrieveAllFunc(userID, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockPasswordHistoryStore) DeleteOldest(userID string, keep int) error {
+	if m.DeleteOldestFunc != nil {
+		return m.DeleteOldestFunc(userID, keep)
+	}
+	return nil
+}
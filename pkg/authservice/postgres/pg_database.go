@@ -175,7 +175,16 @@ func (pd *PostgresDatabase) Migrate() error {
 	defaultLog.Trace("Migrate")
 	defer defaultLog.Trace("Migrate done")
 
-	pd.Db.AutoMigrate(types.User{}, types.Role{}, types.Permission{})
+	pd.Db.AutoMigrate(types.User{}, types.Role{}, types.Permission{}, types.RevokedToken{}, types.RefreshToken{},
+		types.LDAPGroupMapping{}, types.PasswordHistory{})
+
+	// the users created before the password change time was recorded have last changed their password at the latest
+	// when they were last updated
+	err := pd.Db.Model(&types.User{}).Where("password_changed_at IS NULL").
+		UpdateColumn("password_changed_at", gorm.Expr("updated_at")).Error
+	if err != nil {
+		return errors.Wrap(err, "Failed to set password change time of existing users")
+	}
 	return nil
 }
 
@@ -191,6 +200,14 @@ func (pd *PostgresDatabase) PermissionStore() domain.PermissionStore {
 	return &PostgresPermissionStore{db: pd.Db}
 }
 
+func (pd *PostgresDatabase) RevokedTokenStore() domain.RevokedTokenStore {
+	return &PostgresRevokedTokenStore{db: pd.Db}
+}
+
+func (pd *PostgresDatabase) RefreshTokenStore() domain.RefreshTokenStore {
+	return &PostgresRefreshTokenStore{db: pd.Db}
+}
+
 func (pd *PostgresDatabase) Close() {
 	if pd.Db != nil {
 		err := pd.Db.Close()
@@ -235,3 +252,11 @@ func Open(host string, port int, dbname, user, password, sslMode, sslCert string
 	}
 	return &PostgresDatabase{Db: db}, nil
 }
+
+func (pd *PostgresDatabase) LDAPGroupMappingStore() domain.LDAPGroupMappingStore {
+	return &PostgresLDAPGroupMappingStore{db: pd.Db}
+}
+
+func (pd *PostgresDatabase) PasswordHistoryStore() domain.PasswordHistoryStore {
+	return &PostgresPasswordHistoryStore{db: pd.Db}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type PostgresLDAPGroupMappingStore struct {
+	db *gorm.DB
+}
+
+func (r *PostgresLDAPGroupMappingStore) Create(mapping types.LDAPGroupMapping) (*types.LDAPGroupMapping, error) {
+	defaultLog.Trace("ldap group mapping Create")
+	defer defaultLog.Trace("ldap group mapping Create done")
+
+	uuid, err := UUID()
+	if err == nil {
+		mapping.ID = uuid
+	} else {
+		return &mapping, errors.Wrap(err, "ldap group mapping create: failed to get UUID")
+	}
+	if err := r.db.Create(&mapping).Error; err != nil {
+		return &mapping, errors.Wrap(err, "ldap group mapping create: failed")
+	}
+	return &mapping, nil
+}
+
+func (r *PostgresLDAPGroupMappingStore) Retrieve(id string) (*types.LDAPGroupMapping, error) {
+	defaultLog.Trace("ldap group mapping Retrieve")
+	defer defaultLog.Trace("ldap group mapping Retrieve done")
+
+	mapping := &types.LDAPGroupMapping{}
+	if err := r.db.Where("id = ?", id).First(mapping).Error; err != nil {
+		return nil, errors.Wrap(err, "ldap group mapping retrieve: failed")
+	}
+	return mapping, nil
+}
+
+// RetrieveAll retrieves the mappings of the groups, all the mappings when groups is empty. The group DNs are compared
+// case insensitively.
+func (r *PostgresLDAPGroupMappingStore) RetrieveAll(groups []string) (types.LDAPGroupMappings, error) {
+	defaultLog.Trace("ldap group mapping RetrieveAll")
+	defer defaultLog.Trace("ldap group mapping RetrieveAll done")
+
+	var mappings types.LDAPGroupMappings
+	tx := r.db
+	if len(groups) > 0 {
+		lowerGroups := make([]string, len(groups))
+		for i, group := range groups {
+			lowerGroups[i] = strings.ToLower(group)
+		}
+		tx = tx.Where("lower(group_dn) IN (?)", lowerGroups)
+	}
+	if err := tx.Order("created_at").Find(&mappings).Error; err != nil {
+		return mappings, errors.Wrap(err, "ldap group mapping retrieve all: failed")
+	}
+	return mappings, nil
+}
+
+func (r *PostgresLDAPGroupMappingStore) Delete(mapping types.LDAPGroupMapping) error {
+	defaultLog.Trace("ldap group mapping Delete")
+	defer defaultLog.Trace("ldap group mapping Delete done")
+
+	if err := r.db.Delete(&mapping).Error; err != nil {
+		return errors.Wrap(err, "ldap group mapping delete: failed")
+	}
+	return nil
+}
@@ -126,12 +126,27 @@ func (r *PostgresRoleStore) Delete(role types.Role) error {
 		return errors.Wrap(err, "Repository role delete: failed to clear user-role mapping")
 	}
 
+	if err := r.db.Where("role_id = ?", role.ID).Delete(&types.LDAPGroupMapping{}).Error; err != nil {
+		return errors.Wrap(err, "Repository role delete: failed to delete ldap group mappings")
+	}
+
 	if err := r.db.Delete(&role).Error; err != nil {
 		return errors.Wrap(err, "role delete: failed")
 	}
 	return nil
 }
 
+func (r *PostgresRoleStore) GetUsers(role types.Role) (types.Users, error) {
+	defaultLog.Trace("Repository role GetUsers")
+	defer defaultLog.Trace("Repository role GetUsers done")
+
+	var users types.Users
+	if err := r.db.Model(&role).Association("Users").Find(&users).Error; err != nil {
+		return nil, errors.Wrap(err, "Repository role get users: failed")
+	}
+	return users, nil
+}
+
 func (r *PostgresPermissionStore) AddPermissions(role types.Role, permissions types.Permissions, mustAddAllPermissions bool) error {
 	defaultLog.Trace("role AddPermisisons")
 	defer defaultLog.Trace("role AddPermissions done")
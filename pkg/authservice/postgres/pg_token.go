@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"time"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+type PostgresRevokedTokenStore struct {
+	db *gorm.DB
+}
+
+func (r *PostgresRevokedTokenStore) Create(revokedToken types.RevokedToken) (*types.RevokedToken, error) {
+	defaultLog.Trace("revoked token Create")
+	defer defaultLog.Trace("revoked token Create done")
+
+	uuid, err := UUID()
+	if err == nil {
+		revokedToken.ID = uuid
+	} else {
+		return &revokedToken, errors.Wrap(err, "revoked token create: failed to get UUID")
+	}
+	if err := r.db.Create(&revokedToken).Error; err != nil {
+		return &revokedToken, errors.Wrap(err, "revoked token create: failed")
+	}
+	return &revokedToken, nil
+}
+
+// RetrieveAll retrieves the revoked tokens added to the revocation list after createdSince which are not expired yet
+func (r *PostgresRevokedTokenStore) RetrieveAll(createdSince time.Time) (types.RevokedTokens, error) {
+	defaultLog.Trace("revoked token RetrieveAll")
+	defer defaultLog.Trace("revoked token RetrieveAll done")
+
+	var revokedTokens types.RevokedTokens
+	tx := r.db.Where("expires_at > ?", time.Now())
+	if !createdSince.IsZero() {
+		tx = tx.Where("created_at > ?", createdSince)
+	}
+	if err := tx.Order("created_at").Find(&revokedTokens).Error; err != nil {
+		return revokedTokens, errors.Wrap(err, "revoked token retrieve all: failed")
+	}
+	return revokedTokens, nil
+}
+
+func (r *PostgresRevokedTokenStore) DeleteExpired(expiredAt time.Time) error {
+	if err := r.db.Where("expires_at <= ?", expiredAt).Delete(&types.RevokedToken{}).Error; err != nil {
+		return errors.Wrap(err, "revoked token delete expired: failed")
+	}
+	return nil
+}
+
+type PostgresRefreshTokenStore struct {
+	db *gorm.DB
+}
+
+func (r *PostgresRefreshTokenStore) Create(refreshToken types.RefreshToken) (*types.RefreshToken, error) {
+	defaultLog.Trace("refresh token Create")
+	defer defaultLog.Trace("refresh token Create done")
+
+	uuid, err := UUID()
+	if err == nil {
+		refreshToken.ID = uuid
+	} else {
+		return &refreshToken, errors.Wrap(err, "refresh token create: failed to get UUID")
+	}
+	if err := r.db.Create(&refreshToken).Error; err != nil {
+		return &refreshToken, errors.Wrap(err, "refresh token create: failed")
+	}
+	return &refreshToken, nil
+}
+
+func (r *PostgresRefreshTokenStore) Retrieve(tokenHash []byte) (*types.RefreshToken, error) {
+	defaultLog.Trace("refresh token Retrieve")
+	defer defaultLog.Trace("refresh token Retrieve done")
+
+	refreshToken := &types.RefreshToken{}
+	if err := r.db.Where("token_hash = ?", tokenHash).First(refreshToken).Error; err != nil {
+		return nil, errors.Wrap(err, "refresh token retrieve: failed")
+	}
+	return refreshToken, nil
+}
+
+// Delete deletes the refresh token. It fails if the refresh token does not exist anymore, so that a refresh token
+// used concurrently is only exchanged once.
+func (r *PostgresRefreshTokenStore) Delete(refreshToken types.RefreshToken) error {
+	tx := r.db.Where("id = ?", refreshToken.ID).Delete(&types.RefreshToken{})
+	if tx.Error != nil {
+		return errors.Wrap(tx.Error, "refresh token delete: failed")
+	}
+	if tx.RowsAffected == 0 {
+		return errors.Wrap(gorm.ErrRecordNotFound, "refresh token delete: failed")
+	}
+	return nil
+}
+
+func (r *PostgresRefreshTokenStore) DeleteByUser(userID string) error {
+	if err := r.db.Where("user_id = ?", userID).Delete(&types.RefreshToken{}).Error; err != nil {
+		return errors.Wrap(err, "refresh token delete by user: failed")
+	}
+	return nil
+}
+
+func (r *PostgresRefreshTokenStore) DeleteExpired(expiredAt time.Time) error {
+	if err := r.db.Where("expires_at <= ?", expiredAt).Delete(&types.RefreshToken{}).Error; err != nil {
+		return errors.Wrap(err, "refresh token delete expired: failed")
+	}
+	return nil
+}
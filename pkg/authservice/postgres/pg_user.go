@@ -5,6 +5,8 @@
 package postgres
 
 import (
+	"time"
+
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 
@@ -65,12 +67,57 @@ func (r *PostgresUserStore) Update(u types.User) error {
 	return nil
 }
 
+func (r *PostgresUserStore) UpdateLockout(u types.User) error {
+	defaultLog.Trace("user UpdateLockout")
+	defer defaultLog.Trace("user UpdateLockout done")
+
+	err := r.db.Model(&types.User{ID: u.ID}).UpdateColumns(map[string]interface{}{
+		"failed_login_attempts": u.FailedLoginAttempts,
+		"locked_at":             u.LockedAt,
+	}).Error
+	if err != nil {
+		return errors.Wrap(err, "user update lockout: failed")
+	}
+	return nil
+}
+
+// RecordFailedLogin increments the failed logins in a single update, so that the concurrent failed logins of the user
+// are all counted
+func (r *PostgresUserStore) RecordFailedLogin(u types.User, maxAttempts int, lockoutEnd time.Time) (*types.User, error) {
+	defaultLog.Trace("user RecordFailedLogin")
+	defer defaultLog.Trace("user RecordFailedLogin done")
+
+	// the expressions of the update are evaluated on the row before the update
+	var lockout struct {
+		FailedLoginAttempts int
+		LockedAt            *time.Time
+	}
+	err := r.db.Raw(`UPDATE users SET
+		failed_login_attempts = CASE WHEN locked_at < ? THEN 1 ELSE failed_login_attempts + 1 END,
+		locked_at = CASE
+			WHEN (CASE WHEN locked_at < ? THEN 1 ELSE failed_login_attempts + 1 END) < ? THEN NULL
+			WHEN locked_at IS NULL OR locked_at < ? THEN ?
+			ELSE locked_at END
+		WHERE id = ? AND deleted_at IS NULL
+		RETURNING failed_login_attempts, locked_at`,
+		lockoutEnd, lockoutEnd, maxAttempts, lockoutEnd, time.Now(), u.ID).Scan(&lockout).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "user record failed login: failed")
+	}
+	u.FailedLoginAttempts = lockout.FailedLoginAttempts
+	u.LockedAt = lockout.LockedAt
+	return &u, nil
+}
+
 func (r *PostgresUserStore) Delete(u types.User) error {
 	defaultLog.Trace("user Delete")
 	defer defaultLog.Trace("user Delete done")
 	if err := r.db.Model(&u).Association("Roles").Clear().Error; err != nil {
 		return errors.Wrap(err, "user delete: failed to clear user-role mapping")
 	}
+	if err := r.db.Where("user_id = ?", u.ID).Delete(&types.PasswordHistory{}).Error; err != nil {
+		return errors.Wrap(err, "user delete: failed to delete password history")
+	}
 	if err := r.db.Delete(&u).Error; err != nil {
 		return errors.Wrap(err, "user delete: failed to clear user-role mapping")
 	}
@@ -197,3 +244,48 @@ func (r *PostgresUserStore) DeleteRole(u types.User, roleID string, svcFltr []st
 	}
 	return nil
 }
+
+type PostgresPasswordHistoryStore struct {
+	db *gorm.DB
+}
+
+func (r *PostgresPasswordHistoryStore) Create(h types.PasswordHistory) (*types.PasswordHistory, error) {
+	defaultLog.Trace("password history Create")
+	defer defaultLog.Trace("password history Create done")
+
+	uuid, err := UUID()
+	if err == nil {
+		h.ID = uuid
+	} else {
+		return &h, errors.Wrap(err, "password history create: failed to get UUID")
+	}
+	if err := r.db.Create(&h).Error; err != nil {
+		return &h, errors.Wrap(err, "password history create: failed")
+	}
+	return &h, nil
+}
+
+func (r *PostgresPasswordHistoryStore) RetrieveAll(userID string, limit int) (types.PasswordHistories, error) {
+	defaultLog.Trace("password history RetrieveAll")
+	defer defaultLog.Trace("password history RetrieveAll done")
+
+	var histories types.PasswordHistories
+	err := r.db.Where("user_id = ?", userID).Order("created_at desc").Limit(limit).Find(&histories).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "password history retrieve all: failed")
+	}
+	return histories, nil
+}
+
+func (r *PostgresPasswordHistoryStore) DeleteOldest(userID string, keep int) error {
+	defaultLog.Trace("password history DeleteOldest")
+	defer defaultLog.Trace("password history DeleteOldest done")
+
+	recent := r.db.Model(&types.PasswordHistory{}).Select("id").Where("user_id = ?", userID).
+		Order("created_at desc").Limit(keep).SubQuery()
+	err := r.db.Where("user_id = ? AND id NOT IN ?", userID, recent).Delete(&types.PasswordHistory{}).Error
+	if err != nil {
+		return errors.Wrap(err, "password history delete oldest: failed")
+	}
+	return nil
+}
@@ -17,7 +17,11 @@ func SetJwtCertificateRoutes(r *mux.Router) *mux.Router {
 	defaultLog.Trace("router/jwt_certificate:SetJwtCertificateRoutes() Entering")
 	defer defaultLog.Trace("router/jwt_certificate:SetJwtCertificateRoutes() Leaving")
 
-	controller := controllers.JwtCertificateController{TokenSignCertFile: consts.TokenSignCertFile}
+	controller := controllers.JwtCertificateController{
+		TokenSignCertFile:  consts.TokenSignCertFile,
+		PublishedCertFiles: []string{consts.TokenSignNextCertFile, consts.TokenSignPreviousCertFile},
+	}
 	r.Handle("/jwt-certificates", ErrorHandler(ResponseHandler(controller.GetJwtCertificate, "application/x-pem-file"))).Methods(http.MethodGet)
+	r.Handle("/jwks", ErrorHandler(ResponseHandler(controller.GetJwks, "application/json"))).Methods(http.MethodGet)
 	return r
 }
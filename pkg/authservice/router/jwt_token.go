@@ -7,6 +7,7 @@ package router
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
@@ -15,28 +16,56 @@ import (
 	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 )
 
-func SetJwtTokenRoutes(r *mux.Router, db domain.AASDatabase, tokFactory *jwtauth.JwtFactory) *mux.Router {
+func SetJwtTokenRoutes(r *mux.Router, db domain.AASDatabase, tokFactory *jwtauth.JwtFactory, tokenValidity,
+	refreshTokenValidity, maxTokenValidity time.Duration,
+	ldapAuthenticator domain.LDAPAuthenticator, ldapAutoProvisionUsers bool,
+	oidcVerifier domain.OIDCVerifier, oidcAutoProvisionUsers bool) *mux.Router {
 	defaultLog.Trace("router/jwt_certificate:SetJwtTokenRoutes() Entering")
 	defer defaultLog.Trace("router/jwt_certificate:SetJwtTokenRoutes() Leaving")
 
 	controller := controllers.JwtTokenController{
-		Database:     db,
-		TokenFactory: tokFactory,
+		Database:             db,
+		TokenFactory:         tokFactory,
+		TokenValidity:        tokenValidity,
+		RefreshTokenValidity: refreshTokenValidity,
+		MaxTokenValidity:     maxTokenValidity,
+
+		LDAPAuthenticator:      ldapAuthenticator,
+		LDAPAutoProvisionUsers: ldapAutoProvisionUsers,
+		OIDCVerifier:           oidcVerifier,
+		OIDCAutoProvisionUsers: oidcAutoProvisionUsers,
 	}
+	r.Handle("/token", ErrorHandler(ResponseHandler(controller.CreateJwtTokenWithRefreshToken,
+		"application/json"))).Methods(http.MethodPost).Headers("Accept", "application/json")
 	r.Handle("/token", ErrorHandler(ResponseHandler(controller.CreateJwtToken, "application/jwt"))).Methods(http.MethodPost)
+	if oidcVerifier != nil {
+		r.Handle("/token/oidc", ErrorHandler(ResponseHandler(controller.CreateOIDCJwtTokenWithRefreshToken,
+			"application/json"))).Methods(http.MethodPost).Headers("Accept", "application/json")
+		r.Handle("/token/oidc", ErrorHandler(ResponseHandler(controller.CreateOIDCJwtToken, "application/jwt"))).Methods(http.MethodPost)
+	}
+	r.Handle("/token/refresh", ErrorHandler(ResponseHandler(controller.RefreshJwtToken, "application/json"))).Methods(http.MethodPost)
 	return r
 }
 
-func SetAuthJwtTokenRoutes(r *mux.Router, db domain.AASDatabase, tokFactory *jwtauth.JwtFactory) *mux.Router {
+func SetAuthJwtTokenRoutes(r *mux.Router, db domain.AASDatabase, tokFactory *jwtauth.JwtFactory,
+	customClaimsMaxValidity time.Duration, tokenVerifier func() (jwtauth.Verifier, error)) *mux.Router {
 	defaultLog.Trace("router/jwt_certificate:SetAuthJwtTokenRoutes() Entering")
 	defer defaultLog.Trace("router/jwt_certificate:SetAuthJwtTokenRoutes() Leaving")
 
 	controller := controllers.JwtTokenController{
-		Database:     db,
-		TokenFactory: tokFactory,
+		Database:                db,
+		TokenFactory:            tokFactory,
+		CustomClaimsMaxValidity: customClaimsMaxValidity,
+		TokenVerifier:           tokenVerifier,
 	}
 	r.Handle("/custom-claims-token", ErrorHandler(PermissionsHandler(ResponseHandler(controller.CreateCustomClaimsJwtToken,
 		"application/jwt"), []string{consts.CustomClaimsCreate}))).Methods(http.MethodPost)
+	r.Handle("/custom-claims-token/renew", ErrorHandler(PermissionsHandler(ResponseHandler(controller.RenewCustomClaimsJwtToken,
+		"application/jwt"), []string{consts.CustomClaimsRenew}))).Methods(http.MethodPost)
+	r.Handle("/token/revoke", ErrorHandler(PermissionsHandler(ResponseHandler(controller.RevokeToken,
+		""), []string{consts.TokenRevoke}))).Methods(http.MethodPost)
+	r.Handle("/token/revocations", ErrorHandler(PermissionsHandler(ResponseHandler(controller.GetTokenRevocations,
+		"application/json"), []string{consts.TokenRevocationRetrieve}))).Methods(http.MethodGet)
 
 	return r
 }
@@ -0,0 +1,29 @@
+/*
+ *  Copyright (C) 2022 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
+)
+
+func SetLDAPGroupMappingsRoutes(r *mux.Router, db domain.AASDatabase) *mux.Router {
+	defaultLog.Trace("router/ldap_group_mappings:SetLDAPGroupMappingsRoutes() Entering")
+	defer defaultLog.Trace("router/ldap_group_mappings:SetLDAPGroupMappingsRoutes() Leaving")
+
+	controller := controllers.LDAPGroupMappingsController{Database: db}
+
+	r.Handle("/ldap-group-mappings", ErrorHandler(ResponseHandler(controller.CreateLDAPGroupMapping,
+		"application/json"))).Methods(http.MethodPost)
+	r.Handle("/ldap-group-mappings", ErrorHandler(ResponseHandler(controller.QueryLDAPGroupMappings,
+		"application/json"))).Methods(http.MethodGet)
+	r.Handle("/ldap-group-mappings/{id}", ErrorHandler(ResponseHandler(controller.DeleteLDAPGroupMapping,
+		""))).Methods(http.MethodDelete)
+	return r
+}
@@ -10,13 +10,14 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/controllers"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
 	"net/http"
+	"time"
 )
 
-func SetRolesRoutes(r *mux.Router, db domain.AASDatabase) *mux.Router {
+func SetRolesRoutes(r *mux.Router, db domain.AASDatabase, maxTokenValidity time.Duration) *mux.Router {
 	defaultLog.Trace("router/roles:SetRolesRoutes() Entering")
 	defer defaultLog.Trace("router/roles:SetRolesRoutes() Leaving")
 
-	controller := controllers.RolesController{Database: db}
+	controller := controllers.RolesController{Database: db, MaxTokenValidity: maxTokenValidity}
 
 	r.Handle("/roles", ErrorHandler(ResponseHandler(controller.CreateRole, "application/json"))).Methods(http.MethodPost)
 	r.Handle("/roles", ErrorHandler(ResponseHandler(controller.QueryRoles, "application/json"))).Methods(http.MethodGet)
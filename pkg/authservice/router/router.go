@@ -10,11 +10,14 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	authcommon "github.com/intel-secl/intel-secl/v5/pkg/authservice/common"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/postgres"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	cmw "github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
+	cos "github.com/intel-secl/intel-secl/v5/pkg/lib/common/os"
 )
 
 var defaultLog = log.GetDefaultLogger()
@@ -26,7 +29,7 @@ type Router struct {
 
 // InitRoutes registers all routes for the application.
 func InitRoutes(cfg *config.Configuration, dataStore *postgres.PostgresDatabase,
-	tokenFactory *jwtauth.JwtFactory) *mux.Router {
+	tokenFactory *jwtauth.JwtFactory, ldapAuthenticator domain.LDAPAuthenticator, oidcVerifier domain.OIDCVerifier) *mux.Router {
 	defaultLog.Trace("router/router:InitRoutes() Entering")
 	defer defaultLog.Trace("router/router:InitRoutes() Leaving")
 
@@ -35,30 +38,51 @@ func InitRoutes(cfg *config.Configuration, dataStore *postgres.PostgresDatabase,
 
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
-	defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, tokenFactory)
+	defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, tokenFactory, ldapAuthenticator, oidcVerifier)
 	return router
 }
 
 func defineSubRoutes(router *mux.Router, service string, cfg *config.Configuration, dataStore *postgres.PostgresDatabase,
-	tokenFactory *jwtauth.JwtFactory) {
+	tokenFactory *jwtauth.JwtFactory, ldapAuthenticator domain.LDAPAuthenticator, oidcVerifier domain.OIDCVerifier) {
 	defaultLog.Trace("router/router:defineSubRoutes() Entering")
 	defer defaultLog.Trace("router/router:defineSubRoutes() Leaving")
 
+	tokenValidity := time.Duration(cfg.JWT.TokenDurationMins) * time.Minute
+	refreshTokenValidity := time.Duration(cfg.JWT.RefreshTokenDurationMins) * time.Minute
+	if refreshTokenValidity <= 0 {
+		refreshTokenValidity = constants.DefaultRefreshTokenDurationMins * time.Minute
+	}
+	customClaimsMaxValidity := time.Duration(cfg.JWT.CustomClaimsMaxDurationMins) * time.Minute
+	if customClaimsMaxValidity <= 0 {
+		customClaimsMaxValidity = constants.DefaultCustomClaimsMaxDurationMins * time.Minute
+	}
+	// the tokens of a changed user are revoked until the longest lived token AAS can issue has expired
+	maxTokenValidity := tokenValidity
+	if customClaimsMaxValidity > maxTokenValidity {
+		maxTokenValidity = customClaimsMaxValidity
+	}
+	cfgRouter := Router{cfg: cfg}
+
 	serviceApi := "/" + service + "/" + constants.ApiVersion
 	subRouter := router.PathPrefix(serviceApi).Subrouter()
 	subRouter = SetVersionRoutes(subRouter)
 	subRouter = SetJwtCertificateRoutes(subRouter)
-	subRouter = SetJwtTokenRoutes(subRouter, dataStore, tokenFactory)
-	subRouter = SetUsersNoAuthRoutes(subRouter, dataStore)
+	subRouter = SetJwtTokenRoutes(subRouter, dataStore, tokenFactory, tokenValidity, refreshTokenValidity, maxTokenValidity,
+		ldapAuthenticator, cfg.LDAP.AutoProvisionUsers, oidcVerifier, cfg.OIDC.AutoProvisionUsers)
+	subRouter = SetUsersNoAuthRoutes(subRouter, dataStore, maxTokenValidity)
 
 	subRouter = router.PathPrefix(serviceApi).Subrouter()
-	cfgRouter := Router{cfg: cfg}
-	subRouter.Use(cmw.NewTokenAuth(constants.TokenSignKeysAndCertDir,
+	revocationList := jwtauth.NewRevocationList(func(since time.Time) (*jwtauth.TokenRevocations, error) {
+		return authcommon.RetrieveTokenRevocations(dataStore, since)
+	}, time.Second*constants.DefaultTokenRevocationRefreshSecs)
+	subRouter.Use(cmw.NewTokenAuthWithRevocationList(constants.TokenSignKeysAndCertDir,
 		constants.TrustedCAsStoreDir, cfgRouter.retrieveJWTSigningCerts,
-		time.Minute*constants.DefaultJwtValidateCacheKeyMins))
-	subRouter = SetRolesRoutes(subRouter, dataStore)
-	subRouter = SetUsersRoutes(subRouter, dataStore)
-	subRouter = SetAuthJwtTokenRoutes(subRouter, dataStore, tokenFactory)
+		time.Minute*constants.DefaultJwtValidateCacheKeyMins, revocationList))
+	subRouter = SetRolesRoutes(subRouter, dataStore, maxTokenValidity)
+	subRouter = SetUsersRoutes(subRouter, dataStore, maxTokenValidity)
+	subRouter = SetLDAPGroupMappingsRoutes(subRouter, dataStore)
+	subRouter = SetAuthJwtTokenRoutes(subRouter, dataStore, tokenFactory, customClaimsMaxValidity,
+		cfgRouter.jwtVerifier)
 	subRouter = SetCredentialsRoutes(subRouter, cfg.Nats.UserCredentialValidity)
 
 }
@@ -68,3 +92,16 @@ func (router Router) retrieveJWTSigningCerts() error {
 	defaultLog.Debug("Callback function to get JWT certs called")
 	return nil
 }
+
+// jwtVerifier creates a verifier of the tokens signed by AAS, used to verify the tokens to revoke
+func (router Router) jwtVerifier() (jwtauth.Verifier, error) {
+	certPems, err := cos.GetDirFileContents(constants.TokenSignKeysAndCertDir, "*.pem")
+	if err != nil {
+		return nil, err
+	}
+	rootPems, err := cos.GetDirFileContents(constants.TrustedCAsStoreDir, "*.pem")
+	if err != nil {
+		return nil, err
+	}
+	return jwtauth.NewVerifier(certPems, rootPems, time.Minute*constants.DefaultJwtValidateCacheKeyMins)
+}
@@ -7,6 +7,7 @@ package router
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
@@ -14,11 +15,11 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
 )
 
-func SetUsersRoutes(r *mux.Router, db domain.AASDatabase) *mux.Router {
+func SetUsersRoutes(r *mux.Router, db domain.AASDatabase, maxTokenValidity time.Duration) *mux.Router {
 	defaultLog.Trace("router/users:SetUsersRoutes() Entering")
 	defer defaultLog.Trace("router/users:SetUsersRoutes() Leaving")
 
-	controller := controllers.UsersController{Database: db}
+	controller := controllers.UsersController{Database: db, MaxTokenValidity: maxTokenValidity}
 
 	r.Handle("/users", ErrorHandler(PermissionsHandler(ResponseHandler(controller.CreateUser,
 		"application/json"), []string{consts.UserCreate}))).Methods(http.MethodPost)
@@ -30,6 +31,8 @@ func SetUsersRoutes(r *mux.Router, db domain.AASDatabase) *mux.Router {
 		"application/json"), []string{consts.UserRetrieve}))).Methods(http.MethodGet)
 	r.Handle("/users/{id}", ErrorHandler(PermissionsHandler(ResponseHandler(controller.UpdateUser,
 		"application/json"), []string{consts.UserStore}))).Methods("PATCH")
+	r.Handle("/users/{id}/unlock", ErrorHandler(PermissionsHandler(ResponseHandler(controller.UnlockUser,
+		""), []string{consts.UserStore}))).Methods(http.MethodPost)
 	r.Handle("/users/{id}/roles", ErrorHandler(ResponseHandler(controller.AddUserRoles,
 		"application/json"))).Methods(http.MethodPost)
 	r.Handle("/users/{id}/roles", ErrorHandler(ResponseHandler(controller.QueryUserRoles,
@@ -44,11 +47,11 @@ func SetUsersRoutes(r *mux.Router, db domain.AASDatabase) *mux.Router {
 	return r
 }
 
-func SetUsersNoAuthRoutes(r *mux.Router, db domain.AASDatabase) *mux.Router {
+func SetUsersNoAuthRoutes(r *mux.Router, db domain.AASDatabase, maxTokenValidity time.Duration) *mux.Router {
 	defaultLog.Trace("router/users:SetUsersNoAuthRoutes() Entering")
 	defer defaultLog.Trace("router/users:SetUsersNoAuthRoutes() Leaving")
 
-	controller := controllers.UsersController{Database: db}
+	controller := controllers.UsersController{Database: db, MaxTokenValidity: maxTokenValidity}
 	r.Handle("/users/changepassword", ErrorHandler(ResponseHandler(controller.ChangePassword,
 		""))).Methods("PATCH")
 
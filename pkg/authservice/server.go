@@ -8,17 +8,25 @@ package authservice
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"github.com/gorilla/handlers"
 	comm "github.com/intel-secl/intel-secl/v5/pkg/authservice/common"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/ldap"
+	"github.com/intel-secl/intel-secl/v5/pkg/authservice/oidc"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/postgres"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/router"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/certrenewer"
+	commConstants "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
 	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 	"github.com/pkg/errors"
 	"io/ioutil"
 	stdlog "log"
@@ -59,6 +67,39 @@ func (a *App) initJwtTokenFactory() (*jwtauth.JwtFactory, error) {
 		time.Duration(cfg.JWT.TokenDurationMins)*time.Minute)
 }
 
+// loadCACertPool reads the CA certificate bundle of an external identity provider, the system certificate pool is
+// used when the file is not set
+func loadCACertPool(caCertFile string) (*x509.CertPool, error) {
+	if caCertFile == "" {
+		return nil, nil
+	}
+	caCertPem, err := ioutil.ReadFile(caCertFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read CA certificate file")
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caCertPem) {
+		return nil, errors.Errorf("No certificates found in CA certificate file %s", caCertFile)
+	}
+	return rootCAs, nil
+}
+
+func initLDAPAuthenticator(cfg config.LDAPConfig) (*ldap.Authenticator, error) {
+	rootCAs, err := loadCACertPool(cfg.CACertFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load LDAP CA certificates")
+	}
+	return ldap.NewAuthenticator(cfg, rootCAs)
+}
+
+func initOIDCProvider(cfg config.OIDCConfig) (*oidc.Provider, error) {
+	rootCAs, err := loadCACertPool(cfg.CACertFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load OIDC CA certificates")
+	}
+	return oidc.NewProvider(cfg, rootCAs)
+}
+
 func (a *App) startServer() error {
 	c := a.configuration()
 	if c == nil {
@@ -67,6 +108,7 @@ func (a *App) startServer() error {
 
 	// initialize defender
 	comm.InitDefender(c.AuthDefender.MaxAttempts, c.AuthDefender.IntervalMins, c.AuthDefender.LockoutDurationMins)
+	comm.InitPasswordPolicy(c.PasswordPolicy, c.AccountLockout)
 
 	// initialize log
 	if err := a.configureLogs(c.Log.EnableStdout, true); err != nil {
@@ -87,8 +129,28 @@ func (a *App) startServer() error {
 		return err
 	}
 
+	var ldapAuthenticator domain.LDAPAuthenticator
+	if c.LDAP.Enabled {
+		authenticator, err := initLDAPAuthenticator(c.LDAP)
+		if err != nil {
+			defaultLog.WithError(err).Error("Failed to initialize LDAP authenticator")
+			return err
+		}
+		ldapAuthenticator = authenticator
+	}
+
+	var oidcVerifier domain.OIDCVerifier
+	if c.OIDC.Enabled {
+		provider, err := initOIDCProvider(c.OIDC)
+		if err != nil {
+			defaultLog.WithError(err).Error("Failed to initialize OIDC provider")
+			return err
+		}
+		oidcVerifier = provider
+	}
+
 	// Initialize routes
-	routes := router.InitRoutes(c, dataStore, jwtFactory)
+	routes := router.InitRoutes(c, dataStore, jwtFactory, ldapAuthenticator, oidcVerifier)
 	loggerMiddleware := middleware.LogWriterMiddleware{a.logWriter()}
 	routes.Use(loggerMiddleware.WriteDurationLog())
 	// ISECL-8715 - Prevent potential open redirects to external URLs
@@ -101,6 +163,11 @@ func (a *App) startServer() error {
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
 			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
 	}
+	certRenewer, err := startCertRenewer(c, jwtFactory, tlsconfig)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while starting certificate renewer")
+	}
+	defer certRenewer.Stop()
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -117,11 +184,9 @@ func (a *App) startServer() error {
 		MaxHeaderBytes:    c.Server.MaxHeaderBytes,
 	}
 
-	// dispatch web server go routine
+	// dispatch web server go routine, the TLS certificate is provided by the certificate renewer
 	go func() {
-		tlsCert := c.TLS.CertFile
-		tlsKey := c.TLS.KeyFile
-		if err := h.ListenAndServeTLS(tlsCert, tlsKey); err != nil {
+		if err := h.ListenAndServeTLS("", ""); err != nil {
 			if err != http.ErrServerClosed {
 				defaultLog.WithError(err).Fatal("Failed to start HTTPS server")
 			}
@@ -141,3 +206,32 @@ func (a *App) startServer() error {
 	secLog.Info(commLogMsg.ServiceStop)
 	return nil
 }
+
+// startCertRenewer starts the renewal of the TLS certificate issued by CMS. AAS has no service user of its own in CMS,
+// it signs the tokens of the requests to CMS itself with the CertApprover role of its TLS certificate, as CMS trusts
+// the tokens signed by AAS.
+func startCertRenewer(c *config.Configuration, jwtFactory *jwtauth.JwtFactory, tlsConfig *tls.Config) (*certrenewer.Renewer, error) {
+	defaultLog.Trace("server:startCertRenewer() Entering")
+	defer defaultLog.Trace("server:startCertRenewer() Leaving")
+
+	tokenProvider := func() (string, error) {
+		claims := ct.AuthClaims{Roles: []ct.RoleInfo{{
+			Service: "CMS",
+			Name:    "CertApprover",
+			Context: "CN=" + c.TLS.CommonName + ";SAN=" + c.TLS.SANList + ";CERTTYPE=TLS",
+		}}}
+		return jwtFactory.Create(&claims, constants.ServiceName, 0)
+	}
+	renewer := certrenewer.NewRenewer(c.CMSBaseURL, constants.TrustedCAsStoreDir, tokenProvider)
+	tlsCertificate, err := renewer.Watch(certrenewer.Certificate{
+		CertFile: c.TLS.CertFile,
+		KeyFile:  c.TLS.KeyFile,
+		CertType: commConstants.CertTypeTls,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.GetCertificate = tlsCertificate.GetCertificate
+	renewer.Start()
+	return renewer, nil
+}
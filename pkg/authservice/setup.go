@@ -49,11 +49,11 @@ func (a *App) setup(args []string) error {
 			return errors.Wrap(err, "Failed to read answer file")
 		}
 	}
-	runner, err := a.setupTaskRunner()
+	cmd := args[1]
+	runner, err := a.setupTaskRunner(cmd)
 	if err != nil {
 		return err
 	}
-	cmd := args[1]
 	// print help and return if applicable
 	if len(args) > 2 && args[2] == "--help" {
 		if cmd == "all" {
@@ -106,7 +106,7 @@ func (a *App) setup(args []string) error {
 }
 
 // a helper function for setting up the task runner
-func (a *App) setupTaskRunner() (*setup.Runner, error) {
+func (a *App) setupTaskRunner(cmd string) (*setup.Runner, error) {
 
 	loadAlias()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
@@ -199,6 +199,31 @@ func (a *App) setupTaskRunner() (*setup.Runner, error) {
 		CmsBaseURL:    viper.GetString(commConfig.CmsBaseUrl),
 		BearerToken:   viper.GetString(commConfig.BearerToken),
 	})
+	// the signing key is only rotated on request, rotate-jwt-signing-key is not part of setup all
+	if cmd == "rotate-jwt-signing-key" {
+		runner.AddTask("rotate-jwt-signing-key", "", &tasks.RotateJwtSigningKey{
+			DownloadNextCert: &setup.DownloadCert{
+				KeyFile:      constants.TokenSignNextKeyFile,
+				CertFile:     constants.TokenSignNextCertFile,
+				KeyAlgorithm: constants.DefaultKeyAlgorithm,
+				KeyLength:    constants.DefaultKeyLength,
+				Subject: pkix.Name{
+					CommonName: viper.GetString(config.JwtCertCommonName),
+				},
+				CertType:      "JWT-Signing",
+				CaCertDirPath: constants.TrustedCAsStoreDir,
+				ConsoleWriter: a.consoleWriter(),
+				CmsBaseURL:    viper.GetString(commConfig.CmsBaseUrl),
+				BearerToken:   viper.GetString(commConfig.BearerToken),
+			},
+			KeyFile:          constants.TokenSignKeyFile,
+			CertFile:         constants.TokenSignCertFile,
+			NextKeyFile:      constants.TokenSignNextKeyFile,
+			NextCertFile:     constants.TokenSignNextCertFile,
+			PreviousCertFile: constants.TokenSignPreviousCertFile,
+			ConsoleWriter:    a.consoleWriter(),
+		})
+	}
 
 	runner.AddTask("create-credentials", "", &tasks.CreateCredentials{
 		CreateCredentials: viper.GetBool(config.CreateCredentials),
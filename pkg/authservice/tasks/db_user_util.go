@@ -13,6 +13,7 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
 	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 	"golang.org/x/crypto/bcrypt"
+	"time"
 )
 
 func createPermission(db domain.AASDatabase, rule string) (*types.Permission, error) {
@@ -97,7 +98,11 @@ func addDBUser(db domain.AASDatabase, username string, password string, roles []
 	} else {
 		uuid, _ = postgres.UUID()
 	}
-	err = db.UserStore().Update(types.User{ID: uuid, Name: username, PasswordHash: hash, PasswordCost: bcrypt.DefaultCost, Roles: roles})
+	// the password of the user is kept in the configuration of the service, it is exempt from the password expiry
+	// and the account lockout
+	passwordChangedAt := time.Now()
+	err = db.UserStore().Update(types.User{ID: uuid, Name: username, PasswordHash: hash, PasswordCost: bcrypt.DefaultCost,
+		PasswordChangedAt: &passwordChangedAt, ServiceAccount: true, Roles: roles})
 	if err != nil {
 		defaultLog.WithError(err).Error("failed to create or update register host user in db")
 		return err
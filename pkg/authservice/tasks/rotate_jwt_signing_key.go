@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package tasks
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+// RotateJwtSigningKey rotates the JWT signing key in two stages. The first run downloads the next signing key and
+// certificate from CMS, which are published in the JWKS right away so that services can cache the next certificate
+// before any token is signed with it. The second run makes the next key the active one and keeps the certificate of
+// the previous key published until the tokens signed with it expire.
+type RotateJwtSigningKey struct {
+	// DownloadNextCert downloads the next signing key and certificate to NextKeyFile and NextCertFile
+	DownloadNextCert setup.Task
+	KeyFile          string
+	CertFile         string
+	NextKeyFile      string
+	NextCertFile     string
+	PreviousCertFile string
+	ConsoleWriter    io.Writer
+
+	activated bool
+	keyId     string
+}
+
+const rotateJwtSigningKeyHelpPrompt = "Following environment variables are required for rotate-jwt-signing-key setup:"
+
+var rotateJwtSigningKeyEnvHelp = map[string]string{
+	"CMS_BASE_URL": "CMS base URL in the format https://{{cms}}:{{cms_port}}/cms/v1/",
+	"BEARER_TOKEN": "Bearer token for accessing CMS api",
+}
+
+func (r *RotateJwtSigningKey) Run() error {
+	defaultLog.Trace("tasks/rotate_jwt_signing_key:Run() Entering")
+	defer defaultLog.Trace("tasks/rotate_jwt_signing_key:Run() Leaving")
+
+	if _, err := os.Stat(r.NextKeyFile); os.IsNotExist(err) {
+		if err = r.DownloadNextCert.Run(); err != nil {
+			return errors.Wrap(err, "Failed to download next JWT signing key and certificate")
+		}
+		r.keyId, err = keyIdOfSigningKey(r.NextKeyFile, r.NextCertFile)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(r.ConsoleWriter, "Next JWT signing key with kid "+r.keyId+" is published in the JWKS. Run "+
+			"rotate-jwt-signing-key again to sign the tokens with it once the services have fetched its certificate")
+		return nil
+	}
+
+	keyId, err := keyIdOfSigningKey(r.NextKeyFile, r.NextCertFile)
+	if err != nil {
+		return err
+	}
+	certPem, err := ioutil.ReadFile(r.CertFile)
+	if err == nil {
+		if err = ioutil.WriteFile(r.PreviousCertFile, certPem, 0640); err != nil {
+			return errors.Wrap(err, "Failed to save previous JWT signing certificate")
+		}
+	} else if !os.IsNotExist(err) {
+		return errors.Wrap(err, "Failed to read JWT signing certificate")
+	}
+	if err = os.Rename(r.NextKeyFile, r.KeyFile); err != nil {
+		return errors.Wrap(err, "Failed to activate next JWT signing key")
+	}
+	if err = os.Rename(r.NextCertFile, r.CertFile); err != nil {
+		return errors.Wrap(err, "Failed to activate next JWT signing certificate")
+	}
+	r.activated = true
+	r.keyId = keyId
+	fmt.Fprintln(r.ConsoleWriter, "JWT signing key with kid "+r.keyId+" is activated. Restart authservice to sign "+
+		"the tokens with it")
+	return nil
+}
+
+func (r *RotateJwtSigningKey) Validate() error {
+	defaultLog.Trace("tasks/rotate_jwt_signing_key:Validate() Entering")
+	defer defaultLog.Trace("tasks/rotate_jwt_signing_key:Validate() Leaving")
+
+	// the signing key is rotated each time the task is run
+	if r.keyId == "" {
+		return errors.New("JWT signing key is not rotated")
+	}
+
+	keyFile, certFile := r.NextKeyFile, r.NextCertFile
+	if r.activated {
+		keyFile, certFile = r.KeyFile, r.CertFile
+	}
+	keyId, err := keyIdOfSigningKey(keyFile, certFile)
+	if err != nil {
+		return err
+	}
+	if keyId != r.keyId {
+		return errors.Errorf("JWT signing certificate %s does not have kid %s", certFile, r.keyId)
+	}
+	return nil
+}
+
+func (r *RotateJwtSigningKey) PrintHelp(w io.Writer) {
+	setup.PrintEnvHelp(w, rotateJwtSigningKeyHelpPrompt, "", rotateJwtSigningKeyEnvHelp)
+	fmt.Fprintln(w, "")
+}
+
+func (r *RotateJwtSigningKey) SetName(n, e string) {
+	r.DownloadNextCert.SetName(n, e)
+}
+
+// keyIdOfSigningKey checks that the certificate matches the signing key and returns its kid (key id)
+func keyIdOfSigningKey(keyFile, certFile string) (string, error) {
+	key, err := crypt.GetPrivateKeyFromPKCS8File(keyFile)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to read JWT signing key")
+	}
+	cert, err := crypt.GetCertFromPemFile(certFile)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to read JWT signing certificate")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return "", errors.New("JWT signing key is not supported")
+	}
+	publicKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !publicKey.Equal(cert.PublicKey) {
+		return "", errors.Errorf("JWT signing certificate %s does not match signing key %s", certFile, keyFile)
+	}
+	return jwtauth.KeyId(cert)
+}
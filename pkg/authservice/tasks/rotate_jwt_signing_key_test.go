@@ -0,0 +1,127 @@
+/*
+ *  Copyright (C) 2022 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package tasks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+)
+
+// selfSignedJwtCert saves a self signed JWT signing key and certificate instead of downloading them from CMS
+type selfSignedJwtCert struct {
+	keyFile  string
+	certFile string
+}
+
+func (s *selfSignedJwtCert) Run() error {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "AAS JWT Signing Certificate"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	certDer, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err = crypt.SavePrivateKeyAsPKCS8(keyDer, s.keyFile); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDer}), 0600)
+}
+
+func (s *selfSignedJwtCert) Validate() error {
+	return nil
+}
+
+func (s *selfSignedJwtCert) SetName(string, string) {
+}
+
+func (s *selfSignedJwtCert) PrintHelp(io.Writer) {
+}
+
+func TestRotateJwtSigningKey_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tokensign")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	active := &selfSignedJwtCert{keyFile: filepath.Join(dir, "jwt.key"), certFile: filepath.Join(dir, "jwtsigncert.pem")}
+	if err = active.Run(); err != nil {
+		t.Fatal(err)
+	}
+	activeCertPem, _ := ioutil.ReadFile(active.certFile)
+
+	newTask := func() *RotateJwtSigningKey {
+		return &RotateJwtSigningKey{
+			DownloadNextCert: &selfSignedJwtCert{keyFile: filepath.Join(dir, "jwt-next.key"),
+				certFile: filepath.Join(dir, "jwtsigncert-next.pem")},
+			KeyFile:          active.keyFile,
+			CertFile:         active.certFile,
+			NextKeyFile:      filepath.Join(dir, "jwt-next.key"),
+			NextCertFile:     filepath.Join(dir, "jwtsigncert-next.pem"),
+			PreviousCertFile: filepath.Join(dir, "jwtsigncert-previous.pem"),
+			ConsoleWriter:    os.Stdout,
+		}
+	}
+
+	// first run publishes the next signing key without changing the active one
+	task := newTask()
+	if err = task.Validate(); err == nil {
+		t.Error("RotateJwtSigningKey.Validate() should fail before the task is run")
+	}
+	if err = task.Run(); err != nil {
+		t.Fatalf("RotateJwtSigningKey.Run() error = %v", err)
+	}
+	if err = task.Validate(); err != nil {
+		t.Errorf("RotateJwtSigningKey.Validate() error = %v", err)
+	}
+	nextKeyId := task.keyId
+	if certPem, _ := ioutil.ReadFile(active.certFile); string(certPem) != string(activeCertPem) {
+		t.Error("RotateJwtSigningKey.Run() should not change the active signing certificate on first run")
+	}
+
+	// second run activates the next signing key and keeps the previous certificate
+	task = newTask()
+	if err = task.Run(); err != nil {
+		t.Fatalf("RotateJwtSigningKey.Run() error = %v", err)
+	}
+	if err = task.Validate(); err != nil {
+		t.Errorf("RotateJwtSigningKey.Validate() error = %v", err)
+	}
+	if task.keyId != nextKeyId {
+		t.Errorf("RotateJwtSigningKey.Run() activated kid %s, want %s", task.keyId, nextKeyId)
+	}
+	if certPem, _ := ioutil.ReadFile(task.PreviousCertFile); string(certPem) != string(activeCertPem) {
+		t.Error("RotateJwtSigningKey.Run() should save the previous signing certificate")
+	}
+	if _, err = os.Stat(task.NextKeyFile); !os.IsNotExist(err) {
+		t.Error("RotateJwtSigningKey.Run() should move the next signing key")
+	}
+}
@@ -6,6 +6,7 @@
 package tasks
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/intel-secl/intel-secl/v5/pkg/authservice/config"
 	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
@@ -25,26 +26,59 @@ type UpdateServiceConfig struct {
 const envHelpPrompt = "Following environment variables are required for update-service-config setup:"
 
 var envHelp = map[string]string{
-	"LOG_LEVEL":                           "Log level",
-	"LOG_MAX_LENGTH":                      "Max length of log statement",
-	"LOG_ENABLE_STDOUT":                   "Enable console log",
-	"JWT_INCLUDE_KID":                     "Includes JWT Key Id for token validation",
-	"JWT_TOKEN_DURATION_MINS":             "Validity of token duration",
-	"JWT_CERT_COMMON_NAME":                "Common Name for JWT Certificate",
-	"AUTH_DEFENDER_MAX_ATTEMPTS":          "Auth defender maximum attempts",
-	"AUTH_DEFENDER_INTERVAL_MINS":         "Auth defender interval in minutes",
-	"AUTH_DEFENDER_LOCKOUT_DURATION_MINS": "Auth defender lockout duration in minutes",
-	"SERVER_PORT":                         "The Port on which Server Listens to",
-	"SERVER_READ_TIMEOUT":                 "Request Read Timeout Duration in Seconds",
-	"SERVER_READ_HEADER_TIMEOUT":          "Request Read Header Timeout Duration in Seconds",
-	"SERVER_WRITE_TIMEOUT":                "Request Write Timeout Duration in Seconds",
-	"SERVER_IDLE_TIMEOUT":                 "Request Idle Timeout in Seconds",
-	"SERVER_MAX_HEADER_BYTES":             "Max Length Of Request Header in Bytes",
-	"NATS_OPERATOR_NAME":                  "Set the NATS operator name, default is \"ISecL-operator\"",
-	"NATS_OPERATOR_CREDENTIAL_VALIDITY":   "Set the NATS operator credential validity, default is 5 years",
-	"NATS_ACCOUNT_NAME":                   "Set the NATS account name, default is \"ISecL-account\"",
-	"NATS_ACCOUNT_CREDENTIAL_VALIDITY":    "Set the NATS account credential validity, default is 5 years",
-	"NATS_USER_CREDENTIAL_VALIDITY":       "Set the NATS user credential validity, default is 1 year",
+	"LOG_LEVEL":                                 "Log level",
+	"LOG_MAX_LENGTH":                            "Max length of log statement",
+	"LOG_ENABLE_STDOUT":                         "Enable console log",
+	"JWT_INCLUDE_KID":                           "Includes JWT Key Id for token validation",
+	"JWT_TOKEN_DURATION_MINS":                   "Validity of token duration",
+	"JWT_REFRESH_TOKEN_DURATION_MINS":           "Validity of refresh token duration",
+	"JWT_CUSTOM_CLAIMS_TOKEN_MAX_DURATION_MINS": "Maximum validity of the custom claims tokens, default is one year",
+	"JWT_CERT_COMMON_NAME":                      "Common Name for JWT Certificate",
+	"AUTH_DEFENDER_MAX_ATTEMPTS":                "Auth defender maximum attempts",
+	"AUTH_DEFENDER_INTERVAL_MINS":               "Auth defender interval in minutes",
+	"AUTH_DEFENDER_LOCKOUT_DURATION_MINS":       "Auth defender lockout duration in minutes",
+	"PASSWORD_POLICY_MIN_LENGTH":                "Minimum length of the passwords of the users, default is 8",
+	"PASSWORD_POLICY_REQUIRE_UPPERCASE":         "Require an uppercase letter in the passwords of the users",
+	"PASSWORD_POLICY_REQUIRE_LOWERCASE":         "Require a lowercase letter in the passwords of the users",
+	"PASSWORD_POLICY_REQUIRE_DIGIT":             "Require a digit in the passwords of the users",
+	"PASSWORD_POLICY_REQUIRE_SPECIAL":           "Require a special character in the passwords of the users",
+	"PASSWORD_POLICY_HISTORY_DEPTH":             "Number of passwords of a user, including the current one, which cannot be reused",
+	"PASSWORD_POLICY_MAX_AGE_DAYS":              "Number of days after which the passwords of the users expire, passwords do not expire when 0 nor for the service accounts",
+	"ACCOUNT_LOCKOUT_MAX_ATTEMPTS":              "Consecutive failed logins locking the account of a user other than a service account, default is 10, lockout is disabled when 0",
+	"ACCOUNT_LOCKOUT_DURATION_MINS":             "Account lockout duration in minutes, default is 30, accounts stay locked until unlocked when 0",
+	"SERVER_PORT":                               "The Port on which Server Listens to",
+	"SERVER_READ_TIMEOUT":                       "Request Read Timeout Duration in Seconds",
+	"SERVER_READ_HEADER_TIMEOUT":                "Request Read Header Timeout Duration in Seconds",
+	"SERVER_WRITE_TIMEOUT":                      "Request Write Timeout Duration in Seconds",
+	"SERVER_IDLE_TIMEOUT":                       "Request Idle Timeout in Seconds",
+	"SERVER_MAX_HEADER_BYTES":                   "Max Length Of Request Header in Bytes",
+	"LDAP_ENABLED":                              "Authenticate the users requesting a token against an LDAP directory",
+	"LDAP_URL":                                  "URL of the LDAP directory, ldaps://host:636 or ldap://host:389",
+	"LDAP_START_TLS":                            "Use StartTLS on the ldap:// connection to the LDAP directory",
+	"LDAP_CA_CERT_FILE":                         "CA certificate bundle verifying the LDAP directory certificate, default is the system certificate pool",
+	"LDAP_BIND_DN":                              "DN of the account searching the users in the LDAP directory",
+	"LDAP_BIND_PASSWORD":                        "Password of the account searching the users in the LDAP directory",
+	"LDAP_USER_SEARCH_BASE":                     "Base DN of the user search in the LDAP directory",
+	"LDAP_USER_FILTER":                          "Filter of the user search, %s is replaced with the username, default is \"(uid=%s)\"",
+	"LDAP_GROUP_ATTRIBUTE":                      "Attribute of the user entry listing the groups of the user, default is \"memberOf\"",
+	"LDAP_GROUP_SEARCH_BASE":                    "Base DN of the group search, the groups are searched instead of read from the group attribute when set",
+	"LDAP_GROUP_FILTER":                         "Filter of the group search, %s is replaced with the user DN, default is \"(member=%s)\"",
+	"LDAP_AUTO_PROVISION_USERS":                 "Create the LDAP users in AAS when they request a token for the first time",
+	"LDAP_TIMEOUT":                              "Timeout of the requests to the LDAP directory, default is 10s",
+	"OIDC_ENABLED":                              "Exchange the ID tokens of an OpenID Connect provider for AAS tokens",
+	"OIDC_ISSUER_URL":                           "Issuer URL of the OpenID Connect provider, https://host/realm",
+	"OIDC_CLIENT_ID":                            "Client ID of AAS at the OpenID Connect provider, required in the audience of the ID tokens",
+	"OIDC_JWKS_URL":                             "URL of the JWKS of the OpenID Connect provider, default is the jwks_uri of the provider metadata",
+	"OIDC_CA_CERT_FILE":                         "CA certificate bundle verifying the OpenID Connect provider certificate, default is the system certificate pool",
+	"OIDC_USERNAME_CLAIM":                       "Claim of the ID token used as the username, default is \"preferred_username\"",
+	"OIDC_CLAIM_MAPPINGS":                       "JSON list of claim values mapped to roles, [{\"claim\":\"groups\",\"value\":\"admins\",\"roles\":[{\"service\":\"AAS\",\"name\":\"Administrator\"}]}]",
+	"OIDC_AUTO_PROVISION_USERS":                 "Create the OpenID Connect users in AAS when they request a token for the first time",
+	"OIDC_TIMEOUT":                              "Timeout of the requests to the OpenID Connect provider, default is 10s",
+	"NATS_OPERATOR_NAME":                        "Set the NATS operator name, default is \"ISecL-operator\"",
+	"NATS_OPERATOR_CREDENTIAL_VALIDITY":         "Set the NATS operator credential validity, default is 5 years",
+	"NATS_ACCOUNT_NAME":                         "Set the NATS account name, default is \"ISecL-account\"",
+	"NATS_ACCOUNT_CREDENTIAL_VALIDITY":          "Set the NATS account credential validity, default is 5 years",
+	"NATS_USER_CREDENTIAL_VALIDITY":             "Set the NATS user credential validity, default is 1 year",
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -57,9 +91,11 @@ func (uc UpdateServiceConfig) Run() error {
 	}
 
 	(*uc.AppConfig).JWT = config.JWT{
-		IncludeKid:        viper.GetBool(config.JwtIncludeKid),
-		TokenDurationMins: viper.GetInt(config.JwtTokenDurationMins),
-		CertCommonName:    viper.GetString(config.JwtCertCommonName),
+		IncludeKid:                  viper.GetBool(config.JwtIncludeKid),
+		TokenDurationMins:           viper.GetInt(config.JwtTokenDurationMins),
+		CertCommonName:              viper.GetString(config.JwtCertCommonName),
+		RefreshTokenDurationMins:    viper.GetInt(config.JwtRefreshTokenDurationMins),
+		CustomClaimsMaxDurationMins: viper.GetInt(config.JwtCustomClaimsMaxDurationMins),
 	}
 
 	(*uc.AppConfig).AuthDefender = config.AuthDefender{
@@ -68,6 +104,61 @@ func (uc UpdateServiceConfig) Run() error {
 		LockoutDurationMins: viper.GetInt(config.AuthDefenderLockoutDurationMins),
 	}
 
+	(*uc.AppConfig).PasswordPolicy = config.PasswordPolicy{
+		MinLength:        viper.GetInt(config.PasswordPolicyMinLength),
+		RequireUppercase: viper.GetBool(config.PasswordPolicyRequireUppercase),
+		RequireLowercase: viper.GetBool(config.PasswordPolicyRequireLowercase),
+		RequireDigit:     viper.GetBool(config.PasswordPolicyRequireDigit),
+		RequireSpecial:   viper.GetBool(config.PasswordPolicyRequireSpecial),
+		HistoryDepth:     viper.GetInt(config.PasswordPolicyHistoryDepth),
+		MaxAgeDays:       viper.GetInt(config.PasswordPolicyMaxAgeDays),
+	}
+	if (*uc.AppConfig).PasswordPolicy.MinLength < 0 || (*uc.AppConfig).PasswordPolicy.HistoryDepth < 0 ||
+		(*uc.AppConfig).PasswordPolicy.MaxAgeDays < 0 {
+		return errors.New("Password policy min length, history depth and max age days cannot be negative")
+	}
+
+	(*uc.AppConfig).AccountLockout = config.AccountLockout{
+		MaxAttempts:  viper.GetInt(config.AccountLockoutMaxAttempts),
+		DurationMins: viper.GetInt(config.AccountLockoutDurationMins),
+	}
+
+	(*uc.AppConfig).LDAP = config.LDAPConfig{
+		Enabled:            viper.GetBool(config.LdapEnabled),
+		URL:                viper.GetString(config.LdapURL),
+		StartTLS:           viper.GetBool(config.LdapStartTLS),
+		CACertFile:         viper.GetString(config.LdapCACertFile),
+		BindDN:             viper.GetString(config.LdapBindDN),
+		BindPassword:       viper.GetString(config.LdapBindPassword),
+		UserSearchBase:     viper.GetString(config.LdapUserSearchBase),
+		UserFilter:         viper.GetString(config.LdapUserFilter),
+		GroupAttribute:     viper.GetString(config.LdapGroupAttribute),
+		GroupSearchBase:    viper.GetString(config.LdapGroupSearchBase),
+		GroupFilter:        viper.GetString(config.LdapGroupFilter),
+		AutoProvisionUsers: viper.GetBool(config.LdapAutoProvisionUsers),
+		Timeout:            viper.GetDuration(config.LdapTimeout),
+	}
+
+	// the claim mappings are kept from the configuration file unless they are provided in the environment
+	claimMappings := (*uc.AppConfig).OIDC.ClaimMappings
+	if claimMappingsJson := viper.GetString(config.OidcClaimMappings); claimMappingsJson != "" {
+		claimMappings = nil
+		if err := json.Unmarshal([]byte(claimMappingsJson), &claimMappings); err != nil {
+			return errors.Wrap(err, "Invalid OIDC claim mappings provided")
+		}
+	}
+	(*uc.AppConfig).OIDC = config.OIDCConfig{
+		Enabled:            viper.GetBool(config.OidcEnabled),
+		IssuerURL:          viper.GetString(config.OidcIssuerURL),
+		ClientID:           viper.GetString(config.OidcClientID),
+		JWKSURL:            viper.GetString(config.OidcJWKSURL),
+		CACertFile:         viper.GetString(config.OidcCACertFile),
+		UsernameClaim:      viper.GetString(config.OidcUsernameClaim),
+		ClaimMappings:      claimMappings,
+		AutoProvisionUsers: viper.GetBool(config.OidcAutoProvisionUsers),
+		Timeout:            viper.GetDuration(config.OidcTimeout),
+	}
+
 	(*uc.AppConfig).Nats = config.NatsConfig{
 		Operator: config.NatsEntityInfo{
 			Name:               viper.GetString(config.NatsOperatorName),
@@ -0,0 +1,26 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package types
+
+import (
+	"time"
+)
+
+// LDAPGroupMapping struct is the database schema of the mapping table of LDAP groups to AAS roles. The LDAP users
+// that are members of the group are granted the role.
+type LDAPGroupMapping struct {
+	ID        string `gorm:"primary_key;type:uuid"`
+	CreatedAt time.Time
+	GroupDN   string `gorm:"not null;index"`
+	RoleID    string `gorm:"type:uuid;not null;index"`
+}
+
+type LDAPGroupMappings []LDAPGroupMapping
+
+// LDAPUser is a user authenticated against the LDAP directory
+type LDAPUser struct {
+	DN     string
+	Groups []string
+}
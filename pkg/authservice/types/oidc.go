@@ -0,0 +1,21 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package types
+
+import (
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+)
+
+// OIDCIdentity is a user authenticated with the ID token of an OpenID Connect provider
+type OIDCIdentity struct {
+	Subject  string
+	Username string
+	// Issuer is the issuer URL of the provider which issued the ID token
+	Issuer string
+	// Roles are the roles mapped to the claims of the ID token
+	Roles []aas.RoleInfo
+	// MappingRoles are the roles of all the claim mappings of the provider, the roles the provider grants and removes
+	MappingRoles []aas.RoleInfo
+}
@@ -0,0 +1,32 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package types
+
+import (
+	"time"
+)
+
+// RevokedToken struct is the database schema of the token revocation list. Either the jti (token id) of a single
+// revoked token or the subject whose tokens issued before RevokedAt are revoked is set.
+type RevokedToken struct {
+	ID        string    `gorm:"primary_key;type:uuid"`
+	CreatedAt time.Time `gorm:"index"`
+	Jti       string    `gorm:"index"`
+	Subject   string    `gorm:"index"`
+	RevokedAt time.Time
+	ExpiresAt time.Time
+}
+
+type RevokedTokens []RevokedToken
+
+// RefreshToken struct is the database schema of the refresh tokens issued to the users. Only the hash of the
+// refresh token is stored.
+type RefreshToken struct {
+	ID        string `gorm:"primary_key;type:uuid"`
+	UserID    string `gorm:"type:uuid;index"`
+	TokenHash []byte `gorm:"unique_index"`
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -26,10 +26,36 @@ type User struct {
 	PasswordSalt []byte     `json:"-"`
 	PasswordCost int        `json:"-"`
 	Roles        []Role     `json:"roles,omitempty" gorm:"many2many:user_roles"`
+	// PasswordChangedAt is when the password was last set, the password expires after the maximum password age
+	PasswordChangedAt *time.Time `json:"-"`
+	// FailedLoginAttempts is the number of consecutive failed logins, the account is locked at LockedAt when it
+	// reaches the maximum login attempts of the account lockout
+	FailedLoginAttempts int        `json:"-"`
+	LockedAt            *time.Time `json:"locked_at,omitempty"`
+	// ServiceAccount is set for the users the services authenticate as with the password of their configuration,
+	// which has no rotation path: their password does not expire and their account is not locked
+	ServiceAccount bool `json:"service_account,omitempty"`
+	// Source is the identity provider authenticating the federated users, "ldap" or "oidc:" followed by the issuer
+	// URL, it is empty for the local users. The LDAP users are identified by their source and username.
+	Source string `json:"source,omitempty"`
+	// Subject is the sub claim identifying the OIDC users at their provider with their source, the username claim
+	// being editable by the users at some providers. The username is only the name the user was provisioned with.
+	Subject string `json:"subject,omitempty"`
 }
 
 type Users []User
 
+// PasswordHistory struct is the database schema of the previous passwords of the users, kept to prevent the users
+// from reusing them
+type PasswordHistory struct {
+	ID           string `gorm:"primary_key;type:uuid"`
+	UserID       string `gorm:"type:uuid;index"`
+	PasswordHash []byte
+	CreatedAt    time.Time
+}
+
+type PasswordHistories []PasswordHistory
+
 func (u *User) CheckPassword(password []byte) error {
 	return bcrypt.CompareHashAndPassword(u.PasswordHash, password)
 }
@@ -10,10 +10,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	aasTypes "github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	"github.com/intel-secl/intel-secl/v5/pkg/clients"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	types "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 
 	"github.com/pkg/errors"
@@ -33,7 +35,12 @@ type AASClient interface {
 	AddRoleToUser(userID string, r types.RoleIDs) error
 	GetCredentials(createCredentailsReq types.CreateCredentialsReq) ([]byte, error)
 	GetCustomClaimsToken(customClaimsTokenReq types.CustomClaims) ([]byte, error)
+	RenewCustomClaimsToken() ([]byte, error)
 	GetJwtSigningCertificate() ([]byte, error)
+	GetJwks() (*jwtauth.JSONWebKeySet, error)
+	GetJwtSigningCertificates() ([][]byte, error)
+	GetTokenRevocations(since time.Time) (*jwtauth.TokenRevocations, error)
+	RevokeToken(token string) error
 }
 
 func NewAASClient(aasURL string, token []byte, client HttpClient) AASClient {
@@ -72,6 +79,18 @@ var (
 	ErrHTTPGetRoles = &clients.HTTPClientErr{
 		ErrMessage: "Failed to get roles",
 	}
+	ErrHTTPGetJwks = &clients.HTTPClientErr{
+		ErrMessage: "Failed to get jwks",
+	}
+	ErrHTTPGetTokenRevocations = &clients.HTTPClientErr{
+		ErrMessage: "Failed to get token revocations",
+	}
+	ErrHTTPRevokeToken = &clients.HTTPClientErr{
+		ErrMessage: "Failed to revoke token",
+	}
+	ErrHTTPRenewCustomClaimsToken = &clients.HTTPClientErr{
+		ErrMessage: "Failed to renew custom claims token",
+	}
 	ErrHTTPGetPermissionsForUser = &clients.HTTPClientErr{
 		ErrMessage: "Failed to get permissions for user",
 	}
@@ -454,6 +473,37 @@ func (c *Client) GetCustomClaimsToken(customClaimsTokenReq types.CustomClaims) (
 	return creds, nil
 }
 
+// RenewCustomClaimsToken renews the custom claims token of the client with the same claims and validity
+func (c *Client) RenewCustomClaimsToken() ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, clients.ResolvePath(c.BaseURL, "custom-claims-token/renew"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:RenewCustomClaimsToken() Error initializing custom claims token renew request")
+	}
+	c.PrepReqHeader(req)
+	req.Header.Set("Accept", "application/jwt")
+
+	if c.HTTPClient == nil {
+		return nil, errors.New("aas/client:RenewCustomClaimsToken() HTTPClient should not be null")
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:RenewCustomClaimsToken() Could not renew custom claims token")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		ErrHTTPRenewCustomClaimsToken.RetCode = res.StatusCode
+		return nil, ErrHTTPRenewCustomClaimsToken
+	}
+
+	token, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:RenewCustomClaimsToken() Error reading response")
+	}
+	return token, nil
+}
+
 func (c *Client) GetJwtSigningCertificate() ([]byte, error) {
 	jwtUrl := clients.ResolvePath(c.BaseURL, "jwt-certificates")
 	req, err := http.NewRequest("GET", jwtUrl, nil)
@@ -476,3 +526,120 @@ func (c *Client) GetJwtSigningCertificate() ([]byte, error) {
 	}
 	return body, nil
 }
+
+// GetJwks retrieves the JWKS listing the keys that AAS signs the tokens with, including the next and the previous
+// signing keys when the signing key is being rotated
+func (c *Client) GetJwks() (*jwtauth.JSONWebKeySet, error) {
+	jwksUrl := clients.ResolvePath(c.BaseURL, "jwks")
+	req, err := http.NewRequest("GET", jwksUrl, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:GetJwks() Error initializing get jwks request")
+	}
+
+	// Set the request header
+	req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:GetJwks() Could not retrieve jwks")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		ErrHTTPGetJwks.RetCode = res.StatusCode
+		return nil, ErrHTTPGetJwks
+	}
+
+	var jwks jwtauth.JSONWebKeySet
+	if err = json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+		return nil, errors.Wrap(err, "aas/client:GetJwks() Failed to decode jwks")
+	}
+	return &jwks, nil
+}
+
+// GetJwtSigningCertificates retrieves the PEM encoded certificate chains of the keys listed in the JWKS. It falls back
+// to the single signing certificate when AAS does not serve the JWKS.
+func (c *Client) GetJwtSigningCertificates() ([][]byte, error) {
+	jwks, err := c.GetJwks()
+	if err != nil {
+		log.WithError(err).Warn("aas/client:GetJwtSigningCertificates() Could not retrieve jwks, retrieving jwt signing certificate")
+		jwtCert, err := c.GetJwtSigningCertificate()
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{jwtCert}, nil
+	}
+
+	var jwtCerts [][]byte
+	for _, jwk := range jwks.Keys {
+		jwtCert, err := jwk.CertificateChainPem()
+		if err != nil {
+			return nil, errors.Wrap(err, "aas/client:GetJwtSigningCertificates() Invalid key in jwks")
+		}
+		jwtCerts = append(jwtCerts, jwtCert)
+	}
+	return jwtCerts, nil
+}
+
+// GetTokenRevocations retrieves the tokens revoked after since, all of the tokens revoked and not expired yet for the
+// zero time. The updated_at time of the returned list is passed as since to retrieve the revocations after it.
+func (c *Client) GetTokenRevocations(since time.Time) (*jwtauth.TokenRevocations, error) {
+	revocationsUrl, err := url.Parse(clients.ResolvePath(c.BaseURL, "token/revocations"))
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:GetTokenRevocations() Error parsing token revocations url")
+	}
+	if !since.IsZero() {
+		q := revocationsUrl.Query()
+		q.Set("since", since.Format(time.RFC3339Nano))
+		revocationsUrl.RawQuery = q.Encode()
+	}
+	req, err := http.NewRequest("GET", revocationsUrl.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:GetTokenRevocations() Error initializing get token revocations request")
+	}
+
+	c.PrepReqHeader(req)
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "aas/client:GetTokenRevocations() Could not retrieve token revocations")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		ErrHTTPGetTokenRevocations.RetCode = res.StatusCode
+		return nil, ErrHTTPGetTokenRevocations
+	}
+
+	var revocations jwtauth.TokenRevocations
+	if err = json.NewDecoder(res.Body).Decode(&revocations); err != nil {
+		return nil, errors.Wrap(err, "aas/client:GetTokenRevocations() Failed to decode token revocations")
+	}
+	return &revocations, nil
+}
+
+// RevokeToken revokes a bearer token or a refresh token issued by AAS
+func (c *Client) RevokeToken(token string) error {
+	payload, err := json.Marshal(&types.TokenRevokeRequest{Token: token})
+	if err != nil {
+		return errors.Wrap(err, "aas/client:RevokeToken() Error marshalling token revoke request")
+	}
+	req, err := http.NewRequest("POST", clients.ResolvePath(c.BaseURL, "token/revoke"), bytes.NewBuffer(payload))
+	if err != nil {
+		return errors.Wrap(err, "aas/client:RevokeToken() Error initializing token revoke request")
+	}
+
+	c.PrepReqHeader(req)
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "aas/client:RevokeToken() Could not revoke token")
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusNoContent {
+		ErrHTTPRevokeToken.RetCode = res.StatusCode
+		return ErrHTTPRevokeToken
+	}
+	return nil
+}
@@ -6,13 +6,16 @@ package aas
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	aasTypes "github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
 	types "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	"github.com/pkg/errors"
 )
 
 var BaseURL = "https://localhost:8771/"
@@ -138,6 +141,15 @@ func mockServer(t *testing.T) *httptest.Server {
         }`))
 	}).Methods(http.MethodPost)
 
+	r.HandleFunc("/aas/v1/jwks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+		w.Write([]byte(`{"keys":[
+			{"kty":"RSA","kid":"3ba8e1c1f4e47d5c6e2bd7a1d0c9b0e9d3d1b7a5","use":"sig","alg":"RS384","x5c":["Y2VydGlmaWNhdGU="]},
+			{"kty":"EC","kid":"6c3e9d2f8a8b0b2e4bd4f5a7d1c2b3a4e5f60718","use":"sig","alg":"ES384","x5c":["bmV4dA==","Y2E="]}
+		]}`))
+	}).Methods(http.MethodGet)
+
 	r.HandleFunc("/aas/v1/jwt-certificates", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Add("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
@@ -187,6 +199,44 @@ func mockServer(t *testing.T) *httptest.Server {
         4G0f6M2HpZoo9DZxeQlGf4RmZVqODSW2FH78f0x0a3UTsLsV02Si0KU1GaI2`))
 	}).Methods(http.MethodPost)
 
+	r.HandleFunc("/aas/v1/token/revocations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if since := r.URL.Query().Get("since"); since != "" {
+			if _, err := time.Parse(time.RFC3339Nano, since); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		w.Write([]byte(`{"revoked_tokens":[
+			{"jti":"3f0a5b1e-3d4c-4f5e-9a8b-7c6d5e4f3a2b","revoked_at":"2022-06-01T10:00:00Z","expires_at":"2022-06-01T12:00:00Z"},
+			{"sub":"admin","revoked_at":"2022-06-01T10:05:00Z","expires_at":"2022-06-01T12:05:00Z"}
+		],"updated_at":"2022-06-01T10:05:00.123456Z"}`))
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc("/aas/v1/token/revoke", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+		var trr types.TokenRevokeRequest
+		if err := json.NewDecoder(r.Body).Decode(&trr); err != nil || trr.Token == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods(http.MethodPost)
+
+	r.HandleFunc("/aas/v1/custom-claims-token/renew", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("renewed-token"))
+	}).Methods(http.MethodPost)
+
 	return httptest.NewServer(r)
 
 }
@@ -1231,3 +1281,269 @@ func TestClient_GetJwtSigningCertificate(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetJwtSigningCertificates(t *testing.T) {
+	server := mockServer(t)
+	defer server.Close()
+
+	// AAS versions that do not serve the JWKS only publish the signing certificate
+	r := mux.NewRouter()
+	r.HandleFunc("/aas/v1/jwt-certificates", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n"))
+	}).Methods(http.MethodGet)
+	pemServer := httptest.NewServer(r)
+	defer pemServer.Close()
+
+	tests := []struct {
+		name      string
+		baseURL   string
+		wantCerts int
+		wantErr   bool
+	}{
+		{
+			name:      "Validate GetJwtSigningCertificates with valid inputs",
+			baseURL:   server.URL + "/aas/v1",
+			wantCerts: 2,
+		},
+		{
+			name:      "Validate GetJwtSigningCertificates falls back to jwt-certificates without jwks",
+			baseURL:   pemServer.URL + "/aas/v1",
+			wantCerts: 1,
+		},
+		{
+			name:    "Validate GetJwtSigningCertificates with Empty BaseURL",
+			baseURL: "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				BaseURL:    tt.baseURL,
+				HTTPClient: &http.Client{},
+			}
+			got, err := c.GetJwtSigningCertificates()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.GetJwtSigningCertificates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantCerts {
+				t.Errorf("Client.GetJwtSigningCertificates() returned %d certificates, want %d", len(got), tt.wantCerts)
+			}
+		})
+	}
+}
+
+func TestClient_GetTokenRevocations(t *testing.T) {
+	server := mockServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		baseURL     string
+		token       string
+		since       time.Time
+		wantRevoked int
+		wantErr     bool
+	}{
+		{
+			name:        "Validate GetTokenRevocations with valid inputs",
+			baseURL:     server.URL + "/aas/v1",
+			token:       token,
+			wantRevoked: 2,
+		},
+		{
+			name:        "Validate GetTokenRevocations with since",
+			baseURL:     server.URL + "/aas/v1",
+			token:       token,
+			since:       time.Now().Add(-time.Hour),
+			wantRevoked: 2,
+		},
+		{
+			name:    "Validate GetTokenRevocations without token",
+			baseURL: server.URL + "/aas/v1",
+			wantErr: true,
+		},
+		{
+			name:    "Validate GetTokenRevocations with Empty BaseURL",
+			baseURL: "",
+			token:   token,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				BaseURL:    tt.baseURL,
+				JWTToken:   []byte(tt.token),
+				HTTPClient: &http.Client{},
+			}
+			got, err := c.GetTokenRevocations(tt.since)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.GetTokenRevocations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && len(got.RevokedTokens) != tt.wantRevoked {
+				t.Errorf("Client.GetTokenRevocations() returned %d revoked tokens, want %d", len(got.RevokedTokens), tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestNewTokenRevocationsFetcher(t *testing.T) {
+	server := mockServer(t)
+	defer server.Close()
+
+	// the first token is rejected as an expired token would be
+	tokens := [][]byte{[]byte("expired"), []byte(token)}
+	var fetched int
+	c := &Client{
+		BaseURL:    server.URL + "/aas/v1",
+		HTTPClient: &http.Client{},
+	}
+	fetchRevocations := NewTokenRevocationsFetcher(c, func() ([]byte, error) {
+		if fetched == len(tokens) {
+			return nil, errors.New("no token")
+		}
+		fetched++
+		return tokens[fetched-1], nil
+	})
+
+	got, err := fetchRevocations(time.Time{})
+	if err != nil {
+		t.Fatalf("NewTokenRevocationsFetcher() error = %v", err)
+	}
+	if len(got.RevokedTokens) != 2 {
+		t.Errorf("NewTokenRevocationsFetcher() returned %d revoked tokens, want 2", len(got.RevokedTokens))
+	}
+	if fetched != 2 {
+		t.Errorf("NewTokenRevocationsFetcher() fetched %d tokens, want 2", fetched)
+	}
+
+	// the token is kept for the next retrievals
+	if _, err = fetchRevocations(time.Now()); err != nil || fetched != 2 {
+		t.Errorf("NewTokenRevocationsFetcher() error = %v, fetched %d tokens, want 2", err, fetched)
+	}
+}
+
+func TestNewUserRolesFetcher(t *testing.T) {
+	server := mockServer(t)
+	defer server.Close()
+
+	var fetched int
+	c := &Client{
+		BaseURL:    server.URL + "/aas/v1",
+		HTTPClient: &http.Client{},
+	}
+	fetchUserRoles := NewUserRolesFetcher(c, func() ([]byte, error) {
+		fetched++
+		return []byte(token), nil
+	})
+
+	got, err := fetchUserRoles("superadmin")
+	if err != nil {
+		t.Fatalf("NewUserRolesFetcher() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "CertApprover" {
+		t.Errorf("NewUserRolesFetcher() returned roles %v, want CertApprover", got)
+	}
+
+	// a user unknown to AAS has no roles
+	got, err = fetchUserRoles("unknown")
+	if err != nil || len(got) != 0 {
+		t.Errorf("NewUserRolesFetcher() error = %v, returned roles %v, want none", err, got)
+	}
+	if fetched != 1 {
+		t.Errorf("NewUserRolesFetcher() fetched %d tokens, want 1", fetched)
+	}
+}
+
+func TestClient_RevokeToken(t *testing.T) {
+	server := mockServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:    "Validate RevokeToken with valid inputs",
+			baseURL: server.URL + "/aas/v1",
+			token:   token,
+		},
+		{
+			name:    "Validate RevokeToken with empty token",
+			baseURL: server.URL + "/aas/v1",
+			token:   "",
+			wantErr: true,
+		},
+		{
+			name:    "Validate RevokeToken with Empty BaseURL",
+			baseURL: "",
+			token:   token,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				BaseURL:    tt.baseURL,
+				HTTPClient: &http.Client{},
+			}
+			if err := c.RevokeToken(tt.token); (err != nil) != tt.wantErr {
+				t.Errorf("Client.RevokeToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_RenewCustomClaimsToken(t *testing.T) {
+	server := mockServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Validate RenewCustomClaimsToken with valid inputs",
+			baseURL: server.URL + "/aas/v1",
+			token:   token,
+			want:    "renewed-token",
+		},
+		{
+			name:    "Validate RenewCustomClaimsToken with invalid token",
+			baseURL: server.URL + "/aas/v1",
+			token:   "invalid-token",
+			wantErr: true,
+		},
+		{
+			name:    "Validate RenewCustomClaimsToken with Empty BaseURL",
+			baseURL: "",
+			token:   token,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				BaseURL:    tt.baseURL,
+				JWTToken:   []byte(tt.token),
+				HTTPClient: &http.Client{},
+			}
+			got, err := c.RenewCustomClaimsToken()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.RenewCustomClaimsToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Client.RenewCustomClaimsToken() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
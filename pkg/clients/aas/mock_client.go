@@ -7,8 +7,10 @@ package aas
 
 import (
 	"net/http"
+	"time"
 
 	aasTypes "github.com/intel-secl/intel-secl/v5/pkg/authservice/types"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	types "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 	"github.com/stretchr/testify/mock"
 )
@@ -80,7 +82,32 @@ func (c *MockAasClient) GetCustomClaimsToken(customClaimsTokenReq types.CustomCl
 	return args.Get(0).([]byte), args.Error(1)
 }
 
+func (c *MockAasClient) RenewCustomClaimsToken() ([]byte, error) {
+	args := c.Called()
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 func (c *MockAasClient) GetJwtSigningCertificate() ([]byte, error) {
 	args := c.Called()
 	return args.Get(0).([]byte), args.Error(1)
 }
+
+func (c *MockAasClient) GetJwks() (*jwtauth.JSONWebKeySet, error) {
+	args := c.Called()
+	return args.Get(0).(*jwtauth.JSONWebKeySet), args.Error(1)
+}
+
+func (c *MockAasClient) GetJwtSigningCertificates() ([][]byte, error) {
+	args := c.Called()
+	return args.Get(0).([][]byte), args.Error(1)
+}
+
+func (c *MockAasClient) GetTokenRevocations(since time.Time) (*jwtauth.TokenRevocations, error) {
+	args := c.Called(since)
+	return args.Get(0).(*jwtauth.TokenRevocations), args.Error(1)
+}
+
+func (c *MockAasClient) RevokeToken(token string) error {
+	args := c.Called(token)
+	return args.Error(0)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package aas
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/clients"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
+	"github.com/pkg/errors"
+)
+
+// TokenFetcher returns a bearer token of a service, holding the permissions of the requests of the service to AAS
+type TokenFetcher func() ([]byte, error)
+
+// NewServiceUserTokenFetcher returns a TokenFetcher fetching a token from AAS for the service user
+func NewServiceUserTokenFetcher(aasBaseUrl string, client HttpClient, username, password string) TokenFetcher {
+	jwtClient := NewJWTClient(aasBaseUrl)
+	jwtClient.HTTPClient = client
+	jwtClient.AddUser(username, password)
+	return func() ([]byte, error) {
+		return jwtClient.FetchTokenForUser(username)
+	}
+}
+
+// NewTokenRevocationsFetcher returns the function retrieving the token revocations from AAS for the revocation list of
+// a service. The token authorizing the retrieval is fetched at the first retrieval and fetched again once when AAS
+// rejects it.
+func NewTokenRevocationsFetcher(client *Client, fetchToken TokenFetcher) func(since time.Time) (*jwtauth.TokenRevocations, error) {
+	var lock sync.Mutex
+	return func(since time.Time) (*jwtauth.TokenRevocations, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if len(client.JWTToken) == 0 {
+			if err := refreshToken(client, fetchToken); err != nil {
+				return nil, err
+			}
+		}
+		revocations, err := client.GetTokenRevocations(since)
+		if httpErr, ok := err.(*clients.HTTPClientErr); ok && httpErr.RetCode == http.StatusUnauthorized {
+			if err = refreshToken(client, fetchToken); err != nil {
+				return nil, err
+			}
+			revocations, err = client.GetTokenRevocations(since)
+		}
+		return revocations, err
+	}
+}
+
+func refreshToken(client *Client, fetchToken TokenFetcher) error {
+	token, err := fetchToken()
+	if err != nil {
+		return errors.Wrap(err, "aas/token_revocations:refreshToken() Could not fetch token of service")
+	}
+	client.JWTToken = token
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package aas
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/clients"
+	types "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+)
+
+// NewUserRolesFetcher returns the function retrieving the current roles of a user from AAS, for the services checking
+// that the user still holds the roles granted earlier. A user unknown to AAS has no roles. The token authorizing the
+// retrieval is fetched at the first retrieval and fetched again once when AAS rejects it.
+func NewUserRolesFetcher(client *Client, fetchToken TokenFetcher) func(username string) ([]types.RoleInfo, error) {
+	var lock sync.Mutex
+	return func(username string) ([]types.RoleInfo, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if len(client.JWTToken) == 0 {
+			if err := refreshToken(client, fetchToken); err != nil {
+				return nil, err
+			}
+		}
+		roles, err := getUserRoles(client, username)
+		if httpErr, ok := err.(*clients.HTTPClientErr); ok && httpErr.RetCode == http.StatusUnauthorized {
+			if err = refreshToken(client, fetchToken); err != nil {
+				return nil, err
+			}
+			roles, err = getUserRoles(client, username)
+		}
+		return roles, err
+	}
+}
+
+func getUserRoles(client *Client, username string) ([]types.RoleInfo, error) {
+	users, err := client.GetUsers(username)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user.Name == username {
+			return client.GetRolesForUser(user.ID)
+		}
+	}
+	return nil, nil
+}
@@ -8,10 +8,15 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// crlRequestTimeout limits the time of the TLS handshakes spent retrieving the CRLs
+const crlRequestTimeout = 10 * time.Second
+
 type HTTPClientErr struct {
 	ErrMessage string
 	RetCode    int
@@ -38,16 +43,31 @@ func HTTPClientTLSNoVerify() *http.Client {
 	}
 }
 
+// HTTPClientWithCA creates an HTTP client trusting the CA certificates, the certificates of the servers being checked
+// against the CRLs of their distribution points
 func HTTPClientWithCA(caCertificates []x509.Certificate) (*http.Client, error) {
 	config := &tls.Config{
 		MinVersion:         tls.VersionTLS13,
 		InsecureSkipVerify: false,
 		RootCAs:            GetCertPool(caCertificates),
 	}
+	config.VerifyPeerCertificate = NewCrlChecker(caCertificates).VerifyPeerCertificate
 	tr := &http.Transport{TLSClientConfig: config, Proxy: http.ProxyFromEnvironment}
 	return &http.Client{Transport: tr}, nil
 }
 
+// NewCrlChecker creates a CRL checker retrieving the CRLs from the servers trusted by the CA certificates, such as
+// CMS. The CRL of the certificate of these servers is not checked to retrieve the CRLs.
+func NewCrlChecker(caCertificates []x509.Certificate) *crypt.CrlChecker {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		InsecureSkipVerify: false,
+		RootCAs:            GetCertPool(caCertificates),
+	}
+	tr := &http.Transport{TLSClientConfig: config, Proxy: http.ProxyFromEnvironment}
+	return crypt.NewCrlChecker(&http.Client{Transport: tr, Timeout: crlRequestTimeout})
+}
+
 func ResolvePath(baseURL, path string) string {
 	if baseURL == "" ||
 		path == "" {
@@ -0,0 +1,181 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package config
+
+import (
+	"crypto/x509"
+	"strings"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
+	"github.com/pkg/errors"
+)
+
+// CertProfile defines the certificates issued by CMS for a certificate type. The certificate type requested is the
+// name of the profile or one of its aliases, and the CERTTYPE of the CertApprover role context of the requester must
+// select the same profile.
+type CertProfile struct {
+	Name string `yaml:"name" mapstructure:"name"`
+	// Aliases are the other certificate types served by the profile
+	Aliases      []string      `yaml:"aliases,omitempty" mapstructure:"aliases"`
+	IssuingCa    string        `yaml:"issuing-ca" mapstructure:"issuing-ca"`
+	ValidityDays int           `yaml:"validity-days" mapstructure:"validity-days"`
+	KeyTypes     []CertKeyType `yaml:"key-types" mapstructure:"key-types"`
+	// SanPatterns are the wildcard patterns the SANs of the CSRs must match, SANs are not allowed when it is empty
+	SanPatterns  []string `yaml:"san-patterns,omitempty" mapstructure:"san-patterns"`
+	KeyUsages    []string `yaml:"key-usages" mapstructure:"key-usages"`
+	ExtKeyUsages []string `yaml:"ext-key-usages,omitempty" mapstructure:"ext-key-usages"`
+}
+
+// CertKeyType is a type of public key allowed in the CSRs, with its minimum size in bits
+type CertKeyType struct {
+	Type    string `yaml:"type" mapstructure:"type"`
+	MinSize int    `yaml:"min-size" mapstructure:"min-size"`
+}
+
+var keyUsages = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+}
+
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+}
+
+// DefaultCertProfiles returns the profiles used when none is configured, they issue the certificates that CMS has
+// always issued for the TLS, TLS-Client and Signing certificate types
+func DefaultCertProfiles() []CertProfile {
+	rsaKey := []CertKeyType{{Type: constants.DefaultKeyAlgorithm, MinSize: constants.DefaultKeyAlgorithmLength}}
+	return []CertProfile{
+		{
+			Name:         constants.Tls,
+			IssuingCa:    constants.Tls,
+			ValidityDays: constants.DefaultCertValidityDays,
+			KeyTypes:     rsaKey,
+			SanPatterns:  []string{"*"},
+			KeyUsages:    []string{"digitalSignature", "contentCommitment"},
+			ExtKeyUsages: []string{"clientAuth", "serverAuth"},
+		},
+		{
+			Name:         constants.TlsClient,
+			IssuingCa:    constants.TlsClient,
+			ValidityDays: constants.DefaultCertValidityDays,
+			KeyTypes:     rsaKey,
+			KeyUsages:    []string{"digitalSignature", "contentCommitment"},
+			ExtKeyUsages: []string{"clientAuth"},
+		},
+		{
+			Name:         constants.Signing,
+			Aliases:      []string{"Flavor-Signing", "JWT-Signing"},
+			IssuingCa:    constants.Signing,
+			ValidityDays: constants.DefaultCertValidityDays,
+			KeyTypes:     rsaKey,
+			KeyUsages:    []string{"digitalSignature", "contentCommitment"},
+		},
+	}
+}
+
+// GetCertProfile returns the profile of the certificate type, from the configured profiles or from the default
+// profiles when none is configured, or nil when no profile serves the certificate type
+func (c *Configuration) GetCertProfile(certType string) *CertProfile {
+	profiles := DefaultCertProfiles()
+	if c != nil && len(c.CertProfiles) > 0 {
+		profiles = c.CertProfiles
+	}
+	for i, profile := range profiles {
+		if profile.Serves(certType) {
+			return &profiles[i]
+		}
+	}
+	return nil
+}
+
+// Serves returns true when the certificate type is the name or one of the aliases of the profile
+func (p CertProfile) Serves(certType string) bool {
+	if strings.EqualFold(p.Name, certType) {
+		return true
+	}
+	for _, alias := range p.Aliases {
+		if strings.EqualFold(alias, certType) {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyUsage returns the key usage of the certificates issued for the profile
+func (p CertProfile) KeyUsage() (x509.KeyUsage, error) {
+	var keyUsage x509.KeyUsage
+	for _, name := range p.KeyUsages {
+		usage, ok := keyUsages[name]
+		if !ok {
+			return 0, errors.Errorf("unknown key usage %s in certificate profile %s", name, p.Name)
+		}
+		keyUsage |= usage
+	}
+	return keyUsage, nil
+}
+
+// ExtKeyUsage returns the extended key usages of the certificates issued for the profile
+func (p CertProfile) ExtKeyUsage() ([]x509.ExtKeyUsage, error) {
+	var extKeyUsage []x509.ExtKeyUsage
+	for _, name := range p.ExtKeyUsages {
+		usage, ok := extKeyUsages[name]
+		if !ok {
+			return nil, errors.Errorf("unknown extended key usage %s in certificate profile %s", name, p.Name)
+		}
+		extKeyUsage = append(extKeyUsage, usage)
+	}
+	return extKeyUsage, nil
+}
+
+// ValidateCertProfiles checks that the certificate profiles are complete and that no certificate type is served by
+// several profiles
+func ValidateCertProfiles(profiles []CertProfile) error {
+	certTypes := map[string]string{}
+	for _, profile := range profiles {
+		if profile.Name == "" {
+			return errors.New("certificate profile without name")
+		}
+		for _, certType := range append([]string{profile.Name}, profile.Aliases...) {
+			if other, found := certTypes[strings.ToLower(certType)]; found {
+				return errors.Errorf("certificate type %s is served by certificate profiles %s and %s", certType, other, profile.Name)
+			}
+			certTypes[strings.ToLower(certType)] = profile.Name
+		}
+
+		validIssuingCa := false
+		for _, issuingCa := range constants.GetIntermediateCAs() {
+			validIssuingCa = validIssuingCa || profile.IssuingCa == issuingCa
+		}
+		if !validIssuingCa {
+			return errors.Errorf("certificate profile %s has invalid issuing CA %s", profile.Name, profile.IssuingCa)
+		}
+		if profile.ValidityDays <= 0 {
+			return errors.Errorf("certificate profile %s has invalid validity %d", profile.Name, profile.ValidityDays)
+		}
+		if len(profile.KeyTypes) == 0 {
+			return errors.Errorf("certificate profile %s allows no key type", profile.Name)
+		}
+		for _, keyType := range profile.KeyTypes {
+			if keyType.Type != "rsa" && keyType.Type != "ecdsa" {
+				return errors.Errorf("certificate profile %s has unknown key type %s", profile.Name, keyType.Type)
+			}
+		}
+		if _, err := profile.KeyUsage(); err != nil {
+			return err
+		}
+		if _, err := profile.ExtKeyUsage(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -29,6 +29,15 @@ const (
 	AasJwtCn  = "aas-jwt-cn"
 	AasTlsCn  = "aas-tls-cn"
 	AasTlsSan = "aas-tls-san"
+
+	RevocationBaseUrl          = "revocation.base-url"
+	RevocationCrlValidityHours = "revocation.crl-validity-hours"
+
+	CmsServiceUsername = "cms.service-username"
+	CmsServicePassword = "cms.service-password"
+
+	AcmeCertProfile         = "acme.cert-profile"
+	AcmeEabKeyValidityHours = "acme.eab-key-validity-hours"
 )
 
 // Configuration is the global configuration struct that is marshalled/unmarshalled to a persisted yaml file
@@ -43,6 +52,12 @@ type Configuration struct {
 	AasJwtCn          string                  `yaml:"aas-jwt-cn" mapstructure:"aas-jwt-cn"`
 	AasTlsCn          string                  `yaml:"aas-tls-cn" mapstructure:"aas-tls-cn"`
 	AasTlsSan         string                  `yaml:"aas-tls-san" mapstructure:"aas-tls-san"`
+	Revocation        RevocationConfig        `yaml:"revocation" mapstructure:"revocation"`
+	CertProfiles      []CertProfile           `yaml:"cert-profiles" mapstructure:"cert-profiles"`
+	Acme              AcmeConfig              `yaml:"acme" mapstructure:"acme"`
+	// CMS is the service user retrieving the token revocations from AAS, the revoked tokens are accepted until they
+	// expire when it is not set
+	CMS commConfig.ServiceConfig `yaml:"cms" mapstructure:"cms"`
 }
 
 type CACertConfig struct {
@@ -53,6 +68,22 @@ type CACertConfig struct {
 	Country      string `yaml:"country" mapstructure:"country"`
 }
 
+// RevocationConfig is the configuration of the CRLs and of the OCSP responder of the intermediate CAs
+type RevocationConfig struct {
+	// BaseUrl is the CMS URL embedded in the issued certificates to locate the CRL distribution points and the OCSP
+	// responder, no revocation information is embedded when it is empty
+	BaseUrl          string `yaml:"base-url" mapstructure:"base-url"`
+	CrlValidityHours int    `yaml:"crl-validity-hours" mapstructure:"crl-validity-hours"`
+}
+
+// AcmeConfig is the configuration of the ACME server of CMS
+type AcmeConfig struct {
+	// CertProfile is the certificate profile of the certificates issued to the ACME clients
+	CertProfile string `yaml:"cert-profile" mapstructure:"cert-profile"`
+	// EabKeyValidityHours is the time during which an external account key can be used to create an ACME account
+	EabKeyValidityHours int `yaml:"eab-key-validity-hours" mapstructure:"eab-key-validity-hours"`
+}
+
 // this function sets the configuration file name and type
 func init() {
 	viper.SetConfigName(constants.ConfigFile)
@@ -36,6 +36,8 @@ const (
 	TLSCertFile                    = "tls-cert.pem"
 	TLSKeyFile                     = "tls.key"
 	SerialNumberPath               = ConfigDir + "serial-number"
+	IssuedCertsDirPath             = ConfigDir + "issued-certificates/"
+	AcmeDirPath                    = ConfigDir + "acme/"
 	TlsCaCertFile                  = "tls-ca.pem"
 	TlsCaKeyFile                   = "tls-ca.key"
 	TlsClientCaCertFile            = "tls-client-ca.pem"
@@ -53,9 +55,11 @@ const (
 	DefaultProvince                = "SF"
 	DefaultLocality                = "SC"
 	DefaultCACertValidity          = 5
+	DefaultCertValidityDays        = 365
 	DefaultKeyAlgorithm            = "rsa"
 	DefaultKeyAlgorithmLength      = 3072
 	CertApproverGroupName          = "CertApprover"
+	CertRevokerGroupName           = "CertRevoker"
 	DefaultAasJwtCn                = "AAS JWT Signing Certificate"
 	DefaultAasTlsCn                = "AAS TLS Certificate"
 	DefaultTlsSan                  = "127.0.0.1,localhost"
@@ -67,8 +71,23 @@ const (
 	DefaultIdleTimeout             = 10 * time.Second
 	DefaultMaxHeaderBytes          = 1 << 20
 	DefaultLogEntryMaxlength       = 300
+	DefaultCrlValidityHours        = 24
+	HTTPMediaTypePkixCrl           = "application/pkix-crl"
+	HTTPMediaTypeOcspRequest       = "application/ocsp-request"
+	HTTPMediaTypeOcspResponse      = "application/ocsp-response"
+	HTTPMediaTypeJose              = "application/jose+json"
+	HTTPMediaTypeProblemJson       = "application/problem+json"
+	HTTPMediaTypePemCertChain      = "application/pem-certificate-chain"
+	DefaultAcmeEabKeyValidityHours = 24
+	AcmeOrderValidity              = 24 * time.Hour
+	AcmeNonceValidity              = time.Hour
+	AcmeMaxNonces                  = 10000
+	AcmeMaxRequestSize             = 1 << 16
 )
 
+// DefaultTokenRevocationsRefreshMins is how often the token revocation list is retrieved from AAS
+const DefaultTokenRevocationsRefreshMins = 1
+
 type CaAttrib struct {
 	CommonName string
 	CertPath   string
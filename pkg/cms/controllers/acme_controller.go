@@ -0,0 +1,1107 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/validation"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/auth"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	cm "github.com/intel-secl/intel-secl/v5/pkg/model/cms"
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// maxAcmeIdentifiers is the maximum number of identifiers of an ACME order
+const maxAcmeIdentifiers = 100
+
+// acmeSignatureAlgorithms are the algorithms allowed for the signatures of the ACME requests
+var acmeSignatureAlgorithms = map[string]bool{
+	string(jose.RS256): true,
+	string(jose.RS384): true,
+	string(jose.RS512): true,
+	string(jose.ES256): true,
+	string(jose.ES384): true,
+	string(jose.ES512): true,
+}
+
+// acmeEabAlgorithms are the MAC algorithms allowed for the external account bindings
+var acmeEabAlgorithms = map[string]bool{
+	string(jose.HS256): true,
+	string(jose.HS384): true,
+	string(jose.HS512): true,
+}
+
+var errAcmeOrderNotReady = errors.New("order is not ready")
+
+// AcmeController implements the subset of the ACME protocol (RFC 8555) needed by the ACME clients to obtain
+// certificates from CMS. The accounts must be bound to an external account key created with an AAS token, and the
+// identifiers of the orders are authorized by the CertApprover roles of that token rather than by challenges. The
+// orders and the CSRs finalizing them are validated with the same rule, the roles being checked again in AAS when the
+// orders are finalized.
+type AcmeController struct {
+	CertificatesController
+	AcmeDir string
+	// BasePath is the path of the ACME endpoints, used to build the URLs of the ACME objects
+	BasePath string
+	Nonces   *utils.AcmeNonces
+	// RetrieveUserRoles retrieves the current roles of a user from AAS, the orders cannot be finalized without it
+	RetrieveUserRoles func(username string) ([]ct.RoleInfo, error)
+}
+
+// acmeRequest is an ACME request whose JWS signature has been verified
+type acmeRequest struct {
+	payload []byte
+	jwk     *jose.JSONWebKey
+	// account is the account of the key identifier of the request, nil when the request is signed with a JWK
+	account *utils.AcmeAccount
+}
+
+func acmeProblem(errType string, status int, detail string) *cm.AcmeProblem {
+	return &cm.AcmeProblem{Type: "urn:ietf:params:acme:error:" + errType, Detail: detail, Status: status}
+}
+
+// url returns the URL of the ACME resource, as seen by the client
+func (controller AcmeController) url(httpRequest *http.Request, resource ...string) string {
+	return "https://" + httpRequest.Host + controller.BasePath + strings.Join(resource, "/")
+}
+
+func (controller AcmeController) accountUrl(httpRequest *http.Request, accountID string) string {
+	return controller.url(httpRequest, "account", accountID)
+}
+
+func (controller AcmeController) orderUrl(httpRequest *http.Request, orderID string) string {
+	return controller.url(httpRequest, "order", orderID)
+}
+
+// certProfile returns the profile of the certificates issued to the ACME clients
+func (controller AcmeController) certProfile() *config.CertProfile {
+	certType := constants.Tls
+	if controller.Config != nil && controller.Config.Acme.CertProfile != "" {
+		certType = controller.Config.Acme.CertProfile
+	}
+	return controller.Config.GetCertProfile(certType)
+}
+
+// setHeaders sets the headers of all the ACME responses, each response providing a fresh nonce to the client
+func (controller AcmeController) setHeaders(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	nonce, err := controller.Nonces.New()
+	if err != nil {
+		log.WithError(err).Error("resource/acme:setHeaders() Could not create nonce")
+	} else {
+		httpWriter.Header().Set("Replay-Nonce", nonce)
+	}
+	httpWriter.Header().Set("Cache-Control", "no-store")
+	httpWriter.Header().Add("Link", "<"+controller.url(httpRequest, "directory")+">;rel=\"index\"")
+}
+
+func (controller AcmeController) writeJson(httpWriter http.ResponseWriter, httpRequest *http.Request, status int,
+	location string, body interface{}) {
+	controller.setHeaders(httpWriter, httpRequest)
+	if location != "" {
+		httpWriter.Header().Set("Location", location)
+	}
+	httpWriter.Header().Set("Content-Type", consts.HTTPMediaTypeJson)
+	httpWriter.WriteHeader(status)
+	if body != nil {
+		if err := json.NewEncoder(httpWriter).Encode(body); err != nil {
+			log.WithError(err).Errorf("resource/acme:writeJson() Failed to write response")
+		}
+	}
+}
+
+func (controller AcmeController) writeProblem(httpWriter http.ResponseWriter, httpRequest *http.Request,
+	problem *cm.AcmeProblem) {
+	controller.setHeaders(httpWriter, httpRequest)
+	httpWriter.Header().Set("Content-Type", constants.HTTPMediaTypeProblemJson)
+	httpWriter.WriteHeader(problem.Status)
+	if err := json.NewEncoder(httpWriter).Encode(problem); err != nil {
+		log.WithError(err).Errorf("resource/acme:writeProblem() Failed to write response")
+	}
+}
+
+// verifyRequest verifies the JWS of the ACME request: its nonce, its URL and its signature by the JWK of the request
+// or by the key of the account of the key identifier of the request
+func (controller AcmeController) verifyRequest(httpRequest *http.Request, allowJwk, allowKid bool) (*acmeRequest, *cm.AcmeProblem) {
+	if httpRequest.Header.Get("Content-Type") != constants.HTTPMediaTypeJose {
+		return nil, acmeProblem("malformed", http.StatusUnsupportedMediaType, "Content type not supported")
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(httpRequest.Body, constants.AcmeMaxRequestSize))
+	if err != nil {
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "Cannot read http request body")
+	}
+	jws, err := jose.ParseSigned(string(body))
+	if err != nil || len(jws.Signatures) != 1 {
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "Invalid JWS")
+	}
+	header := jws.Signatures[0].Protected
+	if !acmeSignatureAlgorithms[header.Algorithm] {
+		return nil, acmeProblem("badSignatureAlgorithm", http.StatusBadRequest, "Signature algorithm not supported")
+	}
+	if !controller.Nonces.Use(header.Nonce) {
+		return nil, acmeProblem("badNonce", http.StatusBadRequest, "Invalid nonce")
+	}
+	// the URL protects the requests from being replayed to other endpoints
+	if url, _ := header.ExtraHeaders[jose.HeaderKey("url")].(string); url != "https://"+httpRequest.Host+httpRequest.URL.Path {
+		return nil, acmeProblem("unauthorized", http.StatusUnauthorized, "URL of the JWS does not match the request URL")
+	}
+
+	request := &acmeRequest{}
+	switch {
+	case header.JSONWebKey != nil && header.KeyID == "" && allowJwk:
+		if !header.JSONWebKey.Valid() || !header.JSONWebKey.IsPublic() {
+			return nil, acmeProblem("badPublicKey", http.StatusBadRequest, "Invalid JWK")
+		}
+		request.jwk = header.JSONWebKey
+	case header.JSONWebKey == nil && header.KeyID != "" && allowKid:
+		if !strings.HasPrefix(header.KeyID, controller.accountUrl(httpRequest, "")) {
+			return nil, acmeProblem("accountDoesNotExist", http.StatusBadRequest, "Unknown account")
+		}
+		account, err := utils.RetrieveAcmeAccount(controller.AcmeDir,
+			strings.TrimPrefix(header.KeyID, controller.accountUrl(httpRequest, "")))
+		if err == utils.ErrAcmeObjectNotFound {
+			return nil, acmeProblem("accountDoesNotExist", http.StatusBadRequest, "Unknown account")
+		} else if err != nil {
+			log.WithError(err).Error("resource/acme:verifyRequest() Could not retrieve account")
+			return nil, acmeProblem("serverInternal", http.StatusInternalServerError, "Could not retrieve account")
+		}
+		if account.Status != cm.AcmeStatusValid {
+			return nil, acmeProblem("unauthorized", http.StatusUnauthorized, "Account is "+account.Status)
+		}
+		var key jose.JSONWebKey
+		if err = json.Unmarshal(account.Key, &key); err != nil {
+			log.WithError(err).Error("resource/acme:verifyRequest() Could not decode account key")
+			return nil, acmeProblem("serverInternal", http.StatusInternalServerError, "Could not retrieve account")
+		}
+		request.jwk = &key
+		request.account = account
+	default:
+		if allowKid && !allowJwk {
+			return nil, acmeProblem("malformed", http.StatusBadRequest, "The request must be signed by the key of an account")
+		}
+		if allowJwk && !allowKid {
+			return nil, acmeProblem("malformed", http.StatusBadRequest, "The request must be signed by the JWK of the request")
+		}
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "The request must have either a JWK or a key identifier")
+	}
+
+	request.payload, err = jws.Verify(request.jwk)
+	if err != nil {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "Invalid JWS signature")
+	}
+	return request, nil
+}
+
+// decodePayload decodes the JSON payload of the request, the empty payloads of the POST-as-GET requests are left as
+// the zero value
+func decodePayload(payload []byte, v interface{}) *cm.AcmeProblem {
+	if len(payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return acmeProblem("malformed", http.StatusBadRequest, "Invalid request payload")
+	}
+	return nil
+}
+
+// keyThumbprint returns the base64url encoded RFC 7638 thumbprint of the key, which identifies the accounts
+func keyThumbprint(jwk *jose.JSONWebKey) (string, error) {
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not compute key thumbprint")
+	}
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
+// roleContexts returns the contexts of the CertApprover roles, as provided to the validation of the CSRs posted to the
+// certificates endpoint
+func roleContexts(roles []ct.RoleInfo) *map[string]ct.RoleInfo {
+	ctxMap, _ := auth.ValidatePermissionAndGetRoleContext(roles,
+		[]ct.RoleInfo{{Service: constants.ServiceName, Name: constants.CertApproverGroupName}},
+		false)
+	return ctxMap
+}
+
+// currentRoles returns the CertApprover roles bound to the account which the user who created its external account
+// key still holds in AAS
+func (controller AcmeController) currentRoles(account *utils.AcmeAccount) ([]ct.RoleInfo, *cm.AcmeProblem) {
+	if controller.RetrieveUserRoles == nil {
+		log.Error("resource/acme:currentRoles() CMS service user is not configured, the roles of the ACME accounts cannot be checked in AAS")
+		return nil, acmeProblem("serverInternal", http.StatusInternalServerError, "Roles of the account cannot be checked")
+	}
+	if account.Username == "" {
+		return nil, acmeProblem("unauthorized", http.StatusForbidden,
+			"The account must be created again with a new external account key")
+	}
+	userRoles, err := controller.RetrieveUserRoles(account.Username)
+	if err != nil {
+		log.WithError(err).Errorf("resource/acme:currentRoles() Could not retrieve roles of user %s", account.Username)
+		return nil, acmeProblem("serverInternal", http.StatusInternalServerError, "Roles of the account cannot be checked")
+	}
+	var roles []ct.RoleInfo
+	for _, role := range account.Roles {
+		for _, userRole := range userRoles {
+			if userRole.Service == role.Service && userRole.Name == role.Name && userRole.Context == role.Context {
+				roles = append(roles, role)
+				break
+			}
+		}
+	}
+	if len(roles) == 0 {
+		slog.Warningf("%s: Roles of ACME account %s are not held by user %s anymore", commLogMsg.UnauthorizedAccess,
+			account.ID, account.Username)
+		return nil, acmeProblem("unauthorized", http.StatusForbidden, "The roles of the account have been revoked")
+	}
+	return roles, nil
+}
+
+func (controller AcmeController) accountObject(httpRequest *http.Request, account *utils.AcmeAccount) cm.AcmeAccount {
+	return cm.AcmeAccount{
+		Status:  account.Status,
+		Contact: account.Contact,
+		Orders:  controller.url(httpRequest, "account", account.ID, "orders"),
+	}
+}
+
+func (controller AcmeController) orderObject(httpRequest *http.Request, order *utils.AcmeOrder) cm.AcmeOrder {
+	expires := order.Expires
+	orderObject := cm.AcmeOrder{
+		Status:      orderStatus(order),
+		Expires:     &expires,
+		Identifiers: order.Identifiers,
+		Error:       order.Error,
+		Finalize:    controller.orderUrl(httpRequest, order.ID) + "/finalize",
+	}
+	for _, authzID := range order.AuthorizationIDs {
+		orderObject.Authorizations = append(orderObject.Authorizations, controller.url(httpRequest, "authz", authzID))
+	}
+	if order.Status == cm.AcmeStatusValid {
+		orderObject.Certificate = controller.url(httpRequest, "cert", order.ID)
+	}
+	return orderObject
+}
+
+// orderStatus returns the status of the order, the orders not completed before they expire being invalid
+func orderStatus(order *utils.AcmeOrder) string {
+	if order.Status != cm.AcmeStatusValid && order.Status != cm.AcmeStatusProcessing && time.Now().After(order.Expires) {
+		return cm.AcmeStatusInvalid
+	}
+	return order.Status
+}
+
+// checkAccountPath checks that the account of the path is the account of the request, the accounts which do not exist
+// being not found
+func (controller AcmeController) checkAccountPath(httpRequest *http.Request, request *acmeRequest) *cm.AcmeProblem {
+	id := mux.Vars(httpRequest)["id"]
+	if id == request.account.ID {
+		return nil
+	}
+	_, err := utils.RetrieveAcmeAccount(controller.AcmeDir, id)
+	if err == utils.ErrAcmeObjectNotFound {
+		return acmeProblem("malformed", http.StatusNotFound, "Account not found")
+	} else if err != nil {
+		log.WithError(err).Error("resource/acme:checkAccountPath() Could not retrieve account")
+		return acmeProblem("serverInternal", http.StatusInternalServerError, "Could not retrieve account")
+	}
+	slog.Warning(commLogMsg.UnauthorizedAccess)
+	return acmeProblem("unauthorized", http.StatusUnauthorized, "The request is not signed by the key of the account")
+}
+
+// retrieveOrder returns the order of the path when it belongs to the account of the request
+func (controller AcmeController) retrieveOrder(httpRequest *http.Request, request *acmeRequest) (*utils.AcmeOrder, *cm.AcmeProblem) {
+	order, err := utils.RetrieveAcmeOrder(controller.AcmeDir, mux.Vars(httpRequest)["id"])
+	if err == utils.ErrAcmeObjectNotFound || (err == nil && order.AccountID != request.account.ID) {
+		return nil, acmeProblem("malformed", http.StatusNotFound, "Order not found")
+	} else if err != nil {
+		log.WithError(err).Error("resource/acme:retrieveOrder() Could not retrieve order")
+		return nil, acmeProblem("serverInternal", http.StatusInternalServerError, "Could not retrieve order")
+	}
+	return order, nil
+}
+
+// GetDirectory is used to get the directory of the ACME endpoints
+func (controller AcmeController) GetDirectory(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetDirectory() Entering")
+	defer log.Trace("resource/acme:GetDirectory() Leaving")
+
+	controller.writeJson(httpWriter, httpRequest, http.StatusOK, "", cm.AcmeDirectory{
+		NewNonce:   controller.url(httpRequest, "new-nonce"),
+		NewAccount: controller.url(httpRequest, "new-account"),
+		NewOrder:   controller.url(httpRequest, "new-order"),
+		RevokeCert: controller.url(httpRequest, "revoke-cert"),
+		Meta:       cm.AcmeDirectoryMeta{ExternalAccountRequired: true},
+	})
+}
+
+// NewNonce is used to get a nonce for the first ACME request of the clients
+func (controller AcmeController) NewNonce(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:NewNonce() Entering")
+	defer log.Trace("resource/acme:NewNonce() Leaving")
+
+	controller.setHeaders(httpWriter, httpRequest)
+	if httpRequest.Method == http.MethodHead {
+		httpWriter.WriteHeader(http.StatusOK)
+	} else {
+		httpWriter.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// NewAccount is used to create an ACME account bound to an external account key, or to find the account of the key
+// of the request
+func (controller AcmeController) NewAccount(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:NewAccount() Entering")
+	defer log.Trace("resource/acme:NewAccount() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, true, false)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	var accountRequest cm.AcmeAccount
+	if problem = decodePayload(request.payload, &accountRequest); problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+
+	accountID, err := keyThumbprint(request.jwk)
+	if err != nil {
+		log.WithError(err).Error("resource/acme:NewAccount() Could not identify account")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("badPublicKey", http.StatusBadRequest, "Invalid JWK"))
+		return
+	}
+	account, err := utils.RetrieveAcmeAccount(controller.AcmeDir, accountID)
+	if err == nil {
+		controller.writeJson(httpWriter, httpRequest, http.StatusOK, controller.accountUrl(httpRequest, accountID),
+			controller.accountObject(httpRequest, account))
+		return
+	} else if err != utils.ErrAcmeObjectNotFound {
+		log.WithError(err).Error("resource/acme:NewAccount() Could not retrieve account")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not retrieve account"))
+		return
+	}
+	if accountRequest.OnlyReturnExisting {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("accountDoesNotExist", http.StatusBadRequest,
+			"No account exists with the key"))
+		return
+	}
+	if len(accountRequest.ExternalAccountBinding) == 0 {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("externalAccountRequired", http.StatusBadRequest,
+			"The account must be bound to an external account key obtained with an AAS token"))
+		return
+	}
+
+	eabKey, problem := controller.verifyExternalAccountBinding(httpRequest, accountRequest.ExternalAccountBinding, request.jwk)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	eabKey, err = utils.BindAcmeEabKey(controller.AcmeDir, eabKey.KeyID, accountID)
+	if err != nil {
+		if err == utils.ErrAcmeEabKeyUnusable {
+			slog.Warning(commLogMsg.UnauthorizedAccess)
+			controller.writeProblem(httpWriter, httpRequest, acmeProblem("unauthorized", http.StatusUnauthorized,
+				"External account key is expired or already bound to an account"))
+			return
+		}
+		log.WithError(err).Error("resource/acme:NewAccount() Could not bind external account key")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not create account"))
+		return
+	}
+
+	key, err := request.jwk.MarshalJSON()
+	if err == nil {
+		account = &utils.AcmeAccount{
+			ID:        accountID,
+			Status:    cm.AcmeStatusValid,
+			Contact:   accountRequest.Contact,
+			Key:       key,
+			EabKeyID:  eabKey.KeyID,
+			Roles:     eabKey.Roles,
+			Username:  eabKey.Username,
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+		}
+		err = utils.StoreAcmeAccount(controller.AcmeDir, account)
+	}
+	if err != nil {
+		log.WithError(err).Error("resource/acme:NewAccount() Could not store account")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not create account"))
+		return
+	}
+	slog.Infof("resource/acme:NewAccount() Created ACME account %s bound to external account key %s", accountID, eabKey.KeyID)
+	controller.writeJson(httpWriter, httpRequest, http.StatusCreated, controller.accountUrl(httpRequest, accountID),
+		controller.accountObject(httpRequest, account))
+}
+
+// verifyExternalAccountBinding verifies that the external account binding is the JWK of the account signed with the
+// HMAC key of an external account key, and returns the external account key
+func (controller AcmeController) verifyExternalAccountBinding(httpRequest *http.Request, eab json.RawMessage,
+	jwk *jose.JSONWebKey) (*utils.AcmeEabKey, *cm.AcmeProblem) {
+	jws, err := jose.ParseSigned(string(eab))
+	if err != nil || len(jws.Signatures) != 1 {
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "Invalid external account binding")
+	}
+	header := jws.Signatures[0].Protected
+	if !acmeEabAlgorithms[header.Algorithm] || header.Nonce != "" {
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "Invalid external account binding")
+	}
+	if url, _ := header.ExtraHeaders[jose.HeaderKey("url")].(string); url != controller.url(httpRequest, "new-account") {
+		return nil, acmeProblem("unauthorized", http.StatusUnauthorized, "URL of the external account binding does not match the request URL")
+	}
+
+	eabKey, err := utils.RetrieveAcmeEabKey(controller.AcmeDir, header.KeyID)
+	if err != nil {
+		if err != utils.ErrAcmeObjectNotFound {
+			log.WithError(err).Error("resource/acme:verifyExternalAccountBinding() Could not retrieve external account key")
+			return nil, acmeProblem("serverInternal", http.StatusInternalServerError, "Could not retrieve external account key")
+		}
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		return nil, acmeProblem("unauthorized", http.StatusUnauthorized, "Unknown external account key")
+	}
+	payload, err := jws.Verify(eabKey.HmacKey)
+	if err != nil {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		return nil, acmeProblem("unauthorized", http.StatusUnauthorized, "Invalid external account binding signature")
+	}
+
+	var boundKey jose.JSONWebKey
+	if err = json.Unmarshal(payload, &boundKey); err != nil {
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "Invalid external account binding")
+	}
+	boundThumbprint, err := keyThumbprint(&boundKey)
+	if err != nil {
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "Invalid external account binding")
+	}
+	thumbprint, err := keyThumbprint(jwk)
+	if err != nil || thumbprint != boundThumbprint {
+		return nil, acmeProblem("malformed", http.StatusBadRequest, "External account binding is not for the key of the account")
+	}
+	return eabKey, nil
+}
+
+// UpdateAccount is used to get, update the contacts of, or deactivate the ACME account
+func (controller AcmeController) UpdateAccount(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:UpdateAccount() Entering")
+	defer log.Trace("resource/acme:UpdateAccount() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, false, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	account := request.account
+	if problem = controller.checkAccountPath(httpRequest, request); problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	var update cm.AcmeAccount
+	if problem = decodePayload(request.payload, &update); problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+
+	if update.Status == "" && update.Contact == nil {
+		controller.writeJson(httpWriter, httpRequest, http.StatusOK, "", controller.accountObject(httpRequest, account))
+		return
+	}
+	if update.Status != "" && update.Status != cm.AcmeStatusDeactivated {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusBadRequest,
+			"The account can only be deactivated"))
+		return
+	}
+	if update.Status == cm.AcmeStatusDeactivated {
+		account.Status = cm.AcmeStatusDeactivated
+	}
+	if update.Contact != nil {
+		account.Contact = update.Contact
+	}
+	if err := utils.StoreAcmeAccount(controller.AcmeDir, account); err != nil {
+		log.WithError(err).Error("resource/acme:UpdateAccount() Could not store account")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not update account"))
+		return
+	}
+	slog.Infof("resource/acme:UpdateAccount() Updated ACME account %s with status %s", account.ID, account.Status)
+	controller.writeJson(httpWriter, httpRequest, http.StatusOK, "", controller.accountObject(httpRequest, account))
+}
+
+// GetAccountOrders is used to get the URLs of the orders of the ACME account
+func (controller AcmeController) GetAccountOrders(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetAccountOrders() Entering")
+	defer log.Trace("resource/acme:GetAccountOrders() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, false, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	if problem = controller.checkAccountPath(httpRequest, request); problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	orders, err := utils.RetrieveAcmeOrders(controller.AcmeDir, request.account.ID)
+	if err != nil {
+		log.WithError(err).Error("resource/acme:GetAccountOrders() Could not retrieve orders")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not retrieve orders"))
+		return
+	}
+	orderList := cm.AcmeOrderList{Orders: []string{}}
+	for i := range orders {
+		orderList.Orders = append(orderList.Orders, controller.orderUrl(httpRequest, orders[i].ID))
+	}
+	controller.writeJson(httpWriter, httpRequest, http.StatusOK, "", orderList)
+}
+
+// NewOrder is used to order a certificate for DNS names and IP addresses, the identifiers are authorized at once when
+// the CertApprover roles bound to the account allow them
+func (controller AcmeController) NewOrder(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:NewOrder() Entering")
+	defer log.Trace("resource/acme:NewOrder() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, false, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	var orderRequest cm.AcmeOrder
+	if problem = decodePayload(request.payload, &orderRequest); problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	if orderRequest.NotBefore != "" || orderRequest.NotAfter != "" {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusBadRequest,
+			"The validity of the certificates is set by the certificate profile, notBefore and notAfter are not supported"))
+		return
+	}
+	if len(orderRequest.Identifiers) == 0 || len(orderRequest.Identifiers) > maxAcmeIdentifiers {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusBadRequest,
+			"Invalid number of identifiers"))
+		return
+	}
+	profile := controller.certProfile()
+	if profile == nil {
+		log.Error("resource/acme:NewOrder() No certificate profile for ACME certificates")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Invalid certificate profile"))
+		return
+	}
+
+	expires := time.Now().UTC().Truncate(time.Second).Add(constants.AcmeOrderValidity)
+	var identifiers []cm.AcmeIdentifier
+	var values []string
+	for _, identifier := range orderRequest.Identifiers {
+		switch identifier.Type {
+		case cm.AcmeIdentifierDns:
+			identifier.Value = strings.ToLower(identifier.Value)
+			// only the leftmost label of a DNS name can be a wildcard
+			baseName := strings.TrimPrefix(identifier.Value, "*.")
+			if baseName == "" || strings.Contains(baseName, "*") || net.ParseIP(baseName) != nil {
+				controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusBadRequest,
+					"Invalid DNS identifier "+identifier.Value))
+				return
+			}
+		case cm.AcmeIdentifierIp:
+			ip := net.ParseIP(identifier.Value)
+			if ip == nil {
+				controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusBadRequest,
+					"Invalid IP identifier "+identifier.Value))
+				return
+			}
+			identifier.Value = ip.String()
+		default:
+			controller.writeProblem(httpWriter, httpRequest, acmeProblem("unsupportedIdentifier", http.StatusBadRequest,
+				"Identifier type "+identifier.Type+" is not supported"))
+			return
+		}
+		identifiers = append(identifiers, identifier)
+		values = append(values, identifier.Value)
+	}
+	if err := validation.ValidateAcmeIdentifiers(profile, values, roleContexts(request.account.Roles)); err != nil {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		log.WithError(err).Errorf("resource/acme:NewOrder() Identifiers rejected for account %s", request.account.ID)
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("rejectedIdentifier", http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	order := &utils.AcmeOrder{
+		AccountID:   request.account.ID,
+		Status:      cm.AcmeStatusReady,
+		Identifiers: identifiers,
+		Expires:     expires,
+	}
+	err := func() error {
+		var err error
+		for _, identifier := range identifiers {
+			// the authorization of a wildcard DNS name is for its base domain name
+			authz := &utils.AcmeAuthorization{
+				AccountID:  request.account.ID,
+				Status:     cm.AcmeStatusValid,
+				Identifier: identifier,
+				Wildcard:   strings.HasPrefix(identifier.Value, "*."),
+				Expires:    expires,
+			}
+			authz.Identifier.Value = strings.TrimPrefix(identifier.Value, "*.")
+			if authz.ID, err = utils.NewAcmeID(); err != nil {
+				return err
+			}
+			if err = utils.StoreAcmeAuthorization(controller.AcmeDir, authz); err != nil {
+				return err
+			}
+			order.AuthorizationIDs = append(order.AuthorizationIDs, authz.ID)
+		}
+		if order.ID, err = utils.NewAcmeID(); err != nil {
+			return err
+		}
+		return utils.StoreAcmeOrder(controller.AcmeDir, order)
+	}()
+	if err != nil {
+		log.WithError(err).Error("resource/acme:NewOrder() Could not store order")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not create order"))
+		return
+	}
+	log.Infof("resource/acme:NewOrder() Created ACME order %s for account %s", order.ID, request.account.ID)
+	controller.writeJson(httpWriter, httpRequest, http.StatusCreated, controller.orderUrl(httpRequest, order.ID),
+		controller.orderObject(httpRequest, order))
+}
+
+// GetOrder is used to get the ACME order
+func (controller AcmeController) GetOrder(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetOrder() Entering")
+	defer log.Trace("resource/acme:GetOrder() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, false, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	order, problem := controller.retrieveOrder(httpRequest, request)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	controller.writeJson(httpWriter, httpRequest, http.StatusOK, "", controller.orderObject(httpRequest, order))
+}
+
+// GetAuthorization is used to get the authorization of an identifier of an ACME order
+func (controller AcmeController) GetAuthorization(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetAuthorization() Entering")
+	defer log.Trace("resource/acme:GetAuthorization() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, false, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	authz, err := utils.RetrieveAcmeAuthorization(controller.AcmeDir, mux.Vars(httpRequest)["id"])
+	if err == utils.ErrAcmeObjectNotFound || (err == nil && authz.AccountID != request.account.ID) {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusNotFound, "Authorization not found"))
+		return
+	} else if err != nil {
+		log.WithError(err).Error("resource/acme:GetAuthorization() Could not retrieve authorization")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not retrieve authorization"))
+		return
+	}
+	status := authz.Status
+	if time.Now().After(authz.Expires) {
+		status = cm.AcmeStatusExpired
+	}
+	expires := authz.Expires
+	controller.writeJson(httpWriter, httpRequest, http.StatusOK, "", cm.AcmeAuthorization{
+		Identifier: authz.Identifier,
+		Status:     status,
+		Expires:    &expires,
+		// the identifier is authorized by the CertApprover roles bound to the account
+		Challenges: []cm.AcmeChallenge{},
+		Wildcard:   authz.Wildcard,
+	})
+}
+
+// FinalizeOrder is used to issue the certificate of a ready ACME order for the CSR of the request
+func (controller AcmeController) FinalizeOrder(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:FinalizeOrder() Entering")
+	defer log.Trace("resource/acme:FinalizeOrder() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, false, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	order, problem := controller.retrieveOrder(httpRequest, request)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	if orderStatus(order) != cm.AcmeStatusReady {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("orderNotReady", http.StatusForbidden,
+			"Order is "+orderStatus(order)))
+		return
+	}
+	var finalizeRequest cm.AcmeFinalize
+	if problem = decodePayload(request.payload, &finalizeRequest); problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+
+	csrBytes, err := base64.RawURLEncoding.DecodeString(finalizeRequest.Csr)
+	var csr *x509.CertificateRequest
+	if err == nil {
+		csr, err = x509.ParseCertificateRequest(csrBytes)
+	}
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+	if err != nil {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.WithError(err).Error("resource/acme:FinalizeOrder() Invalid CSR provided")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("badCSR", http.StatusBadRequest, "Invalid CSR provided"))
+		return
+	}
+	if !csrMatchesOrder(csr, order) {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("badCSR", http.StatusBadRequest,
+			"The SAN list of the CSR does not match the identifiers of the order"))
+		return
+	}
+	profile := controller.certProfile()
+	if profile == nil {
+		log.Error("resource/acme:FinalizeOrder() No certificate profile for ACME certificates")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Invalid certificate profile"))
+		return
+	}
+	roles, problem := controller.currentRoles(request.account)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	if err = validation.ValidateAcmeCertificateRequest(profile, csr, roleContexts(roles)); err != nil {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.WithError(err).Error("resource/acme:FinalizeOrder() Invalid CSR provided")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("badCSR", http.StatusBadRequest,
+			"Invalid CSR provided: "+err.Error()))
+		return
+	}
+
+	// the order is processed once, whatever the concurrent finalize requests
+	_, err = utils.UpdateAcmeOrder(controller.AcmeDir, order.ID, func(order *utils.AcmeOrder) error {
+		if orderStatus(order) != cm.AcmeStatusReady {
+			return errAcmeOrderNotReady
+		}
+		order.Status = cm.AcmeStatusProcessing
+		return nil
+	})
+	if err != nil {
+		if err == errAcmeOrderNotReady {
+			controller.writeProblem(httpWriter, httpRequest, acmeProblem("orderNotReady", http.StatusForbidden,
+				"Order is not ready"))
+			return
+		}
+		log.WithError(err).Error("resource/acme:FinalizeOrder() Could not update order")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not update order"))
+		return
+	}
+
+	certificate, _, issueErr := controller.issueCertificate(profile, csr)
+	order, err = utils.UpdateAcmeOrder(controller.AcmeDir, order.ID, func(order *utils.AcmeOrder) error {
+		if issueErr != nil {
+			order.Status = cm.AcmeStatusInvalid
+			order.Error = acmeProblem("serverInternal", http.StatusInternalServerError, "Cannot issue certificate")
+			return nil
+		}
+		issuedCert, err := x509.ParseCertificate(certificate)
+		if err != nil {
+			return err
+		}
+		order.Status = cm.AcmeStatusValid
+		order.CertificateSerial = utils.SerialNumberToString(issuedCert.SerialNumber)
+		return nil
+	})
+	if issueErr == nil {
+		issueErr = err
+	}
+	if issueErr != nil {
+		log.WithError(issueErr).Error("resource/acme:FinalizeOrder() Cannot issue certificate")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Cannot issue certificate"))
+		return
+	}
+	log.Infof("resource/acme:FinalizeOrder() Issued certificate with serial number %s for ACME order %s",
+		order.CertificateSerial, order.ID)
+	controller.writeJson(httpWriter, httpRequest, http.StatusOK, controller.orderUrl(httpRequest, order.ID),
+		controller.orderObject(httpRequest, order))
+}
+
+// csrMatchesOrder checks that the SAN list of the CSR holds exactly the identifiers of the order
+func csrMatchesOrder(csr *x509.CertificateRequest, order *utils.AcmeOrder) bool {
+	var csrIdentifiers, orderIdentifiers []string
+	for _, dnsName := range csr.DNSNames {
+		csrIdentifiers = append(csrIdentifiers, cm.AcmeIdentifierDns+":"+strings.ToLower(dnsName))
+	}
+	for _, ip := range csr.IPAddresses {
+		csrIdentifiers = append(csrIdentifiers, cm.AcmeIdentifierIp+":"+ip.String())
+	}
+	for _, identifier := range order.Identifiers {
+		orderIdentifiers = append(orderIdentifiers, identifier.Type+":"+identifier.Value)
+	}
+	sort.Strings(csrIdentifiers)
+	sort.Strings(orderIdentifiers)
+	return strings.Join(csrIdentifiers, ",") == strings.Join(orderIdentifiers, ",")
+}
+
+// GetCertificate is used to download the certificate chain issued for the ACME order
+func (controller AcmeController) GetCertificate(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:GetCertificate() Entering")
+	defer log.Trace("resource/acme:GetCertificate() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, false, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	order, problem := controller.retrieveOrder(httpRequest, request)
+	if problem == nil && order.Status != cm.AcmeStatusValid {
+		problem = acmeProblem("malformed", http.StatusNotFound, "Certificate not found")
+	}
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+
+	var chain bytes.Buffer
+	serialNumber, err := utils.SerialNumberFromString(order.CertificateSerial)
+	var issuedCert *utils.IssuedCertificate
+	if err == nil {
+		issuedCert, err = utils.RetrieveIssuedCertificate(controller.IssuedCertsDir, serialNumber)
+	}
+	var caCert *x509.Certificate
+	if err == nil {
+		caCert, err = crypt.GetCertFromPemFile(constants.GetCaAttribs(issuedCert.IssuingCa, controller.CaAttribs).CertPath)
+	}
+	if err == nil {
+		// include the issuing CA as well since clients would need the entire chain minus the root.
+		err = pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: issuedCert.Certificate})
+	}
+	if err == nil {
+		err = pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
+	}
+	if err != nil {
+		log.WithError(err).Error("resource/acme:GetCertificate() Could not retrieve certificate")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not retrieve certificate"))
+		return
+	}
+	controller.setHeaders(httpWriter, httpRequest)
+	httpWriter.Header().Set("Content-Type", constants.HTTPMediaTypePemCertChain)
+	httpWriter.WriteHeader(http.StatusOK)
+	if _, err = httpWriter.Write(chain.Bytes()); err != nil {
+		log.WithError(err).Errorf("resource/acme:GetCertificate() Failed to write response")
+	}
+}
+
+// RevokeCert is used to revoke a certificate issued for an order of the ACME account, or a certificate whose key signed
+// the request
+func (controller AcmeController) RevokeCert(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:RevokeCert() Entering")
+	defer log.Trace("resource/acme:RevokeCert() Leaving")
+
+	request, problem := controller.verifyRequest(httpRequest, true, true)
+	if problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	var revokeRequest cm.AcmeRevokeCert
+	if problem = decodePayload(request.payload, &revokeRequest); problem != nil {
+		controller.writeProblem(httpWriter, httpRequest, problem)
+		return
+	}
+	certBytes, err := base64.RawURLEncoding.DecodeString(revokeRequest.Certificate)
+	var cert *x509.Certificate
+	if err == nil {
+		cert, err = x509.ParseCertificate(certBytes)
+	}
+	if err != nil {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusBadRequest,
+			"Invalid certificate provided"))
+		return
+	}
+	reason := 0
+	if revokeRequest.Reason != nil {
+		reason = *revokeRequest.Reason
+	}
+	validReason := false
+	for _, code := range revocationReasons {
+		validReason = validReason || code == reason
+	}
+	if !validReason {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("badRevocationReason", http.StatusBadRequest,
+			"Invalid revocation reason provided"))
+		return
+	}
+
+	issuedCert, err := utils.RetrieveIssuedCertificate(controller.IssuedCertsDir, cert.SerialNumber)
+	if err == utils.ErrCertificateNotFound || (err == nil && !bytes.Equal(issuedCert.Certificate, cert.Raw)) {
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("malformed", http.StatusNotFound,
+			"Certificate was not issued by CMS"))
+		return
+	} else if err != nil {
+		log.WithError(err).Error("resource/acme:RevokeCert() Could not retrieve certificate")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not revoke certificate"))
+		return
+	}
+
+	authorized, err := controller.canRevoke(request, cert)
+	if err != nil {
+		log.WithError(err).Error("resource/acme:RevokeCert() Could not check the orders of the account")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not revoke certificate"))
+		return
+	}
+	if !authorized {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("unauthorized", http.StatusForbidden,
+			"The certificate was not issued to the account and the request is not signed by the key of the certificate"))
+		return
+	}
+
+	_, err = utils.RevokeIssuedCertificate(controller.IssuedCertsDir, cert.SerialNumber, reason)
+	if err != nil {
+		if errors.Cause(err) == utils.ErrCertificateRevoked {
+			controller.writeProblem(httpWriter, httpRequest, acmeProblem("alreadyRevoked", http.StatusBadRequest,
+				"Certificate is already revoked"))
+			return
+		}
+		log.WithError(err).Error("resource/acme:RevokeCert() Could not revoke certificate")
+		controller.writeProblem(httpWriter, httpRequest, acmeProblem("serverInternal", http.StatusInternalServerError,
+			"Could not revoke certificate"))
+		return
+	}
+	slog.Infof("resource/acme:RevokeCert() Revoked certificate with serial number %s and subject %s",
+		issuedCert.SerialNumber, issuedCert.Subject)
+	controller.setHeaders(httpWriter, httpRequest)
+	httpWriter.WriteHeader(http.StatusOK)
+}
+
+// canRevoke returns true when the certificate was issued for an order of the account of the request, or when the
+// request is signed by the key of the certificate
+func (controller AcmeController) canRevoke(request *acmeRequest, cert *x509.Certificate) (bool, error) {
+	if request.account == nil {
+		certThumbprint, err := keyThumbprint(&jose.JSONWebKey{Key: cert.PublicKey})
+		if err != nil {
+			return false, nil
+		}
+		thumbprint, err := keyThumbprint(request.jwk)
+		return err == nil && thumbprint == certThumbprint, nil
+	}
+	orders, err := utils.RetrieveAcmeOrders(controller.AcmeDir, request.account.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, order := range orders {
+		if order.CertificateSerial == utils.SerialNumberToString(cert.SerialNumber) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateEabKey is used to create an external account key binding the ACME account created with it to the CertApprover
+// roles of the AAS token of the request
+func (controller AcmeController) CreateEabKey(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/acme:CreateEabKey() Entering")
+	defer log.Trace("resource/acme:CreateEabKey() Leaving")
+
+	privileges, err := context.GetUserRoles(httpRequest)
+	if err != nil {
+		slog.WithError(err).Warn("resource/acme:CreateEabKey() Failed to read roles and permissions")
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		_, err = httpWriter.Write([]byte("Could not get user roles from http context"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/acme:CreateEabKey() Failed to write response")
+		}
+		return
+	}
+
+	// the roles of the user of the token are checked again in AAS when the certificates are issued
+	username, err := context.GetTokenSubject(httpRequest)
+	if err != nil || username == "" {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		httpWriter.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	ctxMap, foundRole := auth.ValidatePermissionAndGetRoleContext(privileges,
+		[]ct.RoleInfo{{Service: constants.ServiceName, Name: constants.CertApproverGroupName}},
+		false)
+	if !foundRole || len(*ctxMap) == 0 {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		httpWriter.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var roles []ct.RoleInfo
+	for _, role := range *ctxMap {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Context < roles[j].Context
+	})
+
+	validityHours := constants.DefaultAcmeEabKeyValidityHours
+	if controller.Config != nil && controller.Config.Acme.EabKeyValidityHours > 0 {
+		validityHours = controller.Config.Acme.EabKeyValidityHours
+	}
+	eabKey, err := utils.CreateAcmeEabKey(controller.AcmeDir, username, roles, time.Duration(validityHours)*time.Hour)
+	if err != nil {
+		log.WithError(err).Error("resource/acme:CreateEabKey() Could not create external account key")
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		_, err = httpWriter.Write([]byte("Could not create external account key"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/acme:CreateEabKey() Failed to write response")
+		}
+		return
+	}
+	slog.Infof("resource/acme:CreateEabKey() Created external account key %s for %d CertApprover roles",
+		eabKey.KeyID, len(roles))
+
+	httpWriter.Header().Set("Content-Type", consts.HTTPMediaTypeJson)
+	httpWriter.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(httpWriter).Encode(cm.AcmeExternalAccountKey{
+		KeyID:     eabKey.KeyID,
+		HmacKey:   base64.RawURLEncoding.EncodeToString(eabKey.HmacKey),
+		Directory: controller.url(httpRequest, "directory"),
+	})
+	if err != nil {
+		log.WithError(err).Errorf("resource/acme:CreateEabKey() Failed to write response")
+	}
+}
@@ -0,0 +1,489 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	cm "github.com/intel-secl/intel-secl/v5/pkg/model/cms"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const mockAcmeUrl = "https://cms.example.com/acme/"
+
+var acmeRoles = []ct.RoleInfo{
+	{Service: constants.ServiceName, Name: constants.CertApproverGroupName,
+		Context: "CN=kbs.example.com;SAN=kbs.example.com,10.1.1.1;certType=TLS"},
+}
+
+// userRoles are the roles of the users in AAS when the orders are finalized
+var userRoles []ct.RoleInfo
+
+var acmeIdentifiers = []cm.AcmeIdentifier{
+	{Type: cm.AcmeIdentifierDns, Value: "kbs.example.com"},
+	{Type: cm.AcmeIdentifierIp, Value: "10.1.1.1"},
+}
+
+func setupAcme(t *testing.T) func() {
+	teardown := setupRevocation(t)
+	userRoles = acmeRoles
+	acmeController := AcmeController{
+		CertificatesController: certificatesController,
+		AcmeDir:                mockPath + "acme/",
+		BasePath:               "/acme/",
+		Nonces:                 utils.NewAcmeNonces(time.Minute, 100),
+		RetrieveUserRoles: func(username string) ([]ct.RoleInfo, error) {
+			if username != "kbs" {
+				return nil, nil
+			}
+			return userRoles, nil
+		},
+	}
+	router.HandleFunc("/acme/directory", acmeController.GetDirectory).Methods(http.MethodGet)
+	router.HandleFunc("/acme/new-nonce", acmeController.NewNonce).Methods(http.MethodHead, http.MethodGet)
+	router.HandleFunc("/acme/new-account", acmeController.NewAccount).Methods(http.MethodPost)
+	router.HandleFunc("/acme/account/{id}", acmeController.UpdateAccount).Methods(http.MethodPost)
+	router.HandleFunc("/acme/account/{id}/orders", acmeController.GetAccountOrders).Methods(http.MethodPost)
+	router.HandleFunc("/acme/new-order", acmeController.NewOrder).Methods(http.MethodPost)
+	router.HandleFunc("/acme/order/{id}", acmeController.GetOrder).Methods(http.MethodPost)
+	router.HandleFunc("/acme/order/{id}/finalize", acmeController.FinalizeOrder).Methods(http.MethodPost)
+	router.HandleFunc("/acme/authz/{id}", acmeController.GetAuthorization).Methods(http.MethodPost)
+	router.HandleFunc("/acme/cert/{id}", acmeController.GetCertificate).Methods(http.MethodPost)
+	router.HandleFunc("/acme/revoke-cert", acmeController.RevokeCert).Methods(http.MethodPost)
+	router.HandleFunc("/acme/eab-keys", acmeController.CreateEabKey).Methods(http.MethodPost)
+	return teardown
+}
+
+// acmeClient signs the ACME requests as the ACME clients do
+type acmeClient struct {
+	t          *testing.T
+	key        *ecdsa.PrivateKey
+	accountUrl string
+	nonce      string
+}
+
+func newAcmeClient(t *testing.T) *acmeClient {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	return &acmeClient{t: t, key: key}
+}
+
+func (client *acmeClient) post(url string, payload interface{}, embedJwk bool) *httptest.ResponseRecorder {
+	if client.nonce == "" {
+		req := httptest.NewRequest(http.MethodHead, mockAcmeUrl+"new-nonce", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(client.t, http.StatusOK, w.Code)
+		client.nonce = w.Header().Get("Replay-Nonce")
+	}
+	options := (&jose.SignerOptions{}).WithHeader("url", url).WithHeader("nonce", client.nonce)
+	signingKey := jose.SigningKey{Algorithm: jose.ES256, Key: client.key}
+	if embedJwk {
+		options.EmbedJWK = true
+	} else {
+		signingKey.Key = jose.JSONWebKey{Key: client.key, KeyID: client.accountUrl}
+	}
+	signer, err := jose.NewSigner(signingKey, options)
+	assert.NoError(client.t, err)
+	var payloadBytes []byte
+	if payload != nil {
+		payloadBytes, err = json.Marshal(payload)
+		assert.NoError(client.t, err)
+	}
+	jws, err := signer.Sign(payloadBytes)
+	assert.NoError(client.t, err)
+
+	req := httptest.NewRequest(http.MethodPost, url, bytes.NewBufferString(serializeJws(client.t, jws)))
+	req.Header.Set("Content-Type", constants.HTTPMediaTypeJose)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	client.nonce = w.Header().Get("Replay-Nonce")
+	return w
+}
+
+// serializeJws returns the flattened JSON serialization of the JWS, with the empty payload of the POST-as-GET requests
+// that go-jose omits
+func serializeJws(t *testing.T, jws *jose.JSONWebSignature) string {
+	var flattened map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(jws.FullSerialize()), &flattened))
+	if _, found := flattened["payload"]; !found {
+		flattened["payload"] = ""
+	}
+	serialized, err := json.Marshal(flattened)
+	assert.NoError(t, err)
+	return string(serialized)
+}
+
+// eabBinding returns the external account binding of the account key signed with the HMAC key
+func (client *acmeClient) eabBinding(keyID string, hmacKey []byte) json.RawMessage {
+	options := (&jose.SignerOptions{}).WithHeader("kid", keyID).WithHeader("url", mockAcmeUrl+"new-account")
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: hmacKey}, options)
+	assert.NoError(client.t, err)
+	payload, err := json.Marshal(jose.JSONWebKey{Key: client.key.Public()})
+	assert.NoError(client.t, err)
+	jws, err := signer.Sign(payload)
+	assert.NoError(client.t, err)
+	return json.RawMessage(jws.FullSerialize())
+}
+
+func createEabKey(t *testing.T, roles []ct.RoleInfo) (*httptest.ResponseRecorder, cm.AcmeExternalAccountKey) {
+	req := httptest.NewRequest(http.MethodPost, mockAcmeUrl+"eab-keys", nil)
+	req = context.SetUserRoles(req, roles)
+	req = context.SetTokenSubject(req, "kbs")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var eabKey cm.AcmeExternalAccountKey
+	if w.Code == http.StatusCreated {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &eabKey))
+	}
+	return w, eabKey
+}
+
+// newAccount creates an account bound to a new external account key of the roles
+func (client *acmeClient) newAccount(roles []ct.RoleInfo) {
+	_, eabKey := createEabKey(client.t, roles)
+	hmacKey, err := base64.RawURLEncoding.DecodeString(eabKey.HmacKey)
+	assert.NoError(client.t, err)
+	w := client.post(mockAcmeUrl+"new-account", cm.AcmeAccount{TermsOfServiceAgreed: true,
+		ExternalAccountBinding: client.eabBinding(eabKey.KeyID, hmacKey)}, true)
+	assert.Equal(client.t, http.StatusCreated, w.Code)
+	client.accountUrl = w.Header().Get("Location")
+}
+
+func (client *acmeClient) newOrder(identifiers []cm.AcmeIdentifier) (*httptest.ResponseRecorder, cm.AcmeOrder) {
+	w := client.post(mockAcmeUrl+"new-order", cm.AcmeOrder{Identifiers: identifiers}, false)
+	var order cm.AcmeOrder
+	_ = json.Unmarshal(w.Body.Bytes(), &order)
+	return w, order
+}
+
+func acmeCsr(t *testing.T, cn string, dnsNames []string, ips []net.IP) (string, crypto.Signer) {
+	key, err := rsa.GenerateKey(rand.Reader, constants.DefaultKeyAlgorithmLength)
+	assert.NoError(t, err)
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		SignatureAlgorithm: x509.SHA384WithRSA,
+		Subject:            pkix.Name{CommonName: cn},
+		DNSNames:           dnsNames,
+		IPAddresses:        ips,
+	}, key)
+	assert.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(csrBytes), key
+}
+
+func assertAcmeProblem(t *testing.T, w *httptest.ResponseRecorder, status int, errType string) {
+	assert.Equal(t, status, w.Code)
+	assert.Equal(t, constants.HTTPMediaTypeProblemJson, w.Header().Get("Content-Type"))
+	var problem cm.AcmeProblem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "urn:ietf:params:acme:error:"+errType, problem.Type)
+	// the clients retry the requests rejected for a bad nonce with the nonce of the response
+	assert.NotEmpty(t, w.Header().Get("Replay-Nonce"))
+}
+
+func TestAcmeDirectory(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	req := httptest.NewRequest(http.MethodGet, mockAcmeUrl+"directory", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var directory cm.AcmeDirectory
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &directory))
+	assert.Equal(t, mockAcmeUrl+"new-account", directory.NewAccount)
+	assert.Equal(t, mockAcmeUrl+"new-nonce", directory.NewNonce)
+	assert.True(t, directory.Meta.ExternalAccountRequired)
+}
+
+func TestAcmeIssueCertificate(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+
+	// the account of the key is returned when it already exists
+	w := client.post(mockAcmeUrl+"new-account", cm.AcmeAccount{OnlyReturnExisting: true}, true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, client.accountUrl, w.Header().Get("Location"))
+
+	w, order := client.newOrder(acmeIdentifiers)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	orderUrl := w.Header().Get("Location")
+	assert.Equal(t, cm.AcmeStatusReady, order.Status)
+	if !assert.Len(t, order.Authorizations, 2) {
+		t.FailNow()
+	}
+	w = client.post(order.Authorizations[0], nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var authz cm.AcmeAuthorization
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &authz))
+	assert.Equal(t, cm.AcmeStatusValid, authz.Status)
+	assert.Equal(t, acmeIdentifiers[0], authz.Identifier)
+
+	csr, key := acmeCsr(t, "kbs.example.com", []string{"kbs.example.com"}, []net.IP{net.ParseIP("10.1.1.1")})
+	w = client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, cm.AcmeStatusValid, order.Status)
+	assert.NotEmpty(t, order.Certificate)
+
+	w = client.post(orderUrl, nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = client.post(order.Certificate, nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, constants.HTTPMediaTypePemCertChain, w.Header().Get("Content-Type"))
+	block, rest := pem.Decode(w.Body.Bytes())
+	if !assert.NotNil(t, block) {
+		t.FailNow()
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "kbs.example.com", cert.Subject.CommonName)
+	assert.Equal(t, []string{"kbs.example.com"}, cert.DNSNames)
+	assert.Equal(t, key.Public(), cert.PublicKey)
+	block, _ = pem.Decode(rest)
+	caCert, err := crypt.GetCertFromPemFile(constants.GetCaAttribs(constants.Tls, mockPathCert).CertPath)
+	assert.NoError(t, err)
+	assert.Equal(t, caCert.Raw, block.Bytes)
+
+	w = client.post(client.accountUrl+"/orders", nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var orders cm.AcmeOrderList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &orders))
+	assert.Equal(t, []string{orderUrl}, orders.Orders)
+
+	// the certificate of the order can be revoked by the account
+	revoke := cm.AcmeRevokeCert{Certificate: base64.RawURLEncoding.EncodeToString(cert.Raw)}
+	w = client.post(mockAcmeUrl+"revoke-cert", revoke, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	issuedCert, err := utils.RetrieveIssuedCertificate(mockPath+MockIssuedCertsDir, cert.SerialNumber)
+	assert.NoError(t, err)
+	assert.True(t, issuedCert.Revoked())
+	assertAcmeProblem(t, client.post(mockAcmeUrl+"revoke-cert", revoke, false), http.StatusBadRequest, "alreadyRevoked")
+
+	// the order cannot be finalized again
+	assertAcmeProblem(t, client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false), http.StatusForbidden, "orderNotReady")
+}
+
+func TestAcmeNewAccountExternalAccountBinding(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+
+	w := client.post(mockAcmeUrl+"new-account", cm.AcmeAccount{TermsOfServiceAgreed: true}, true)
+	assertAcmeProblem(t, w, http.StatusBadRequest, "externalAccountRequired")
+	w = client.post(mockAcmeUrl+"new-account", cm.AcmeAccount{OnlyReturnExisting: true}, true)
+	assertAcmeProblem(t, w, http.StatusBadRequest, "accountDoesNotExist")
+
+	_, eabKey := createEabKey(t, acmeRoles)
+	w = client.post(mockAcmeUrl+"new-account", cm.AcmeAccount{
+		ExternalAccountBinding: client.eabBinding(eabKey.KeyID, []byte("not the HMAC key of the external account"))}, true)
+	assertAcmeProblem(t, w, http.StatusUnauthorized, "unauthorized")
+
+	hmacKey, err := base64.RawURLEncoding.DecodeString(eabKey.HmacKey)
+	assert.NoError(t, err)
+	w = client.post(mockAcmeUrl+"new-account", cm.AcmeAccount{ExternalAccountBinding: client.eabBinding(eabKey.KeyID, hmacKey)}, true)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// the external account key is bound to a single account
+	otherClient := newAcmeClient(t)
+	w = otherClient.post(mockAcmeUrl+"new-account", cm.AcmeAccount{
+		ExternalAccountBinding: otherClient.eabBinding(eabKey.KeyID, hmacKey)}, true)
+	assertAcmeProblem(t, w, http.StatusUnauthorized, "unauthorized")
+
+	// the binding must be for the key of the account
+	_, otherEabKey := createEabKey(t, acmeRoles)
+	otherHmacKey, err := base64.RawURLEncoding.DecodeString(otherEabKey.HmacKey)
+	assert.NoError(t, err)
+	w = otherClient.post(mockAcmeUrl+"new-account", cm.AcmeAccount{
+		ExternalAccountBinding: client.eabBinding(otherEabKey.KeyID, otherHmacKey)}, true)
+	assertAcmeProblem(t, w, http.StatusBadRequest, "malformed")
+}
+
+func TestCreateEabKeyWithoutCertApproverRole(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	w, _ := createEabKey(t, revokerRoles)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAcmeBadNonce(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+	usedNonce := client.nonce
+	w := client.post(client.accountUrl, nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	client.nonce = usedNonce
+	assertAcmeProblem(t, client.post(client.accountUrl, nil, false), http.StatusBadRequest, "badNonce")
+	w = client.post(client.accountUrl, nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAcmeRejectedIdentifier(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+
+	w, _ := client.newOrder([]cm.AcmeIdentifier{{Type: cm.AcmeIdentifierDns, Value: "hvs.example.com"}})
+	assertAcmeProblem(t, w, http.StatusBadRequest, "rejectedIdentifier")
+	w, _ = client.newOrder([]cm.AcmeIdentifier{{Type: "email", Value: "admin@example.com"}})
+	assertAcmeProblem(t, w, http.StatusBadRequest, "unsupportedIdentifier")
+}
+
+func TestAcmeFinalizeBadCsr(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+	_, order := client.newOrder(acmeIdentifiers)
+
+	// the SAN list of the CSR must be the identifiers of the order
+	csr, _ := acmeCsr(t, "kbs.example.com", []string{"kbs.example.com"}, nil)
+	assertAcmeProblem(t, client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false), http.StatusBadRequest, "badCSR")
+	// the CN of the CSR must be one of the identifiers of the order
+	csr, _ = acmeCsr(t, "hvs.example.com", []string{"kbs.example.com"}, []net.IP{net.ParseIP("10.1.1.1")})
+	assertAcmeProblem(t, client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false), http.StatusBadRequest, "badCSR")
+
+	// the order is still ready after the bad CSRs, the CN being optional
+	csr, _ = acmeCsr(t, "", []string{"kbs.example.com"}, []net.IP{net.ParseIP("10.1.1.1")})
+	w := client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAcmeWildcardIdentifier(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	wildcardRoles := []ct.RoleInfo{{Service: constants.ServiceName, Name: constants.CertApproverGroupName,
+		Context: "CN=kbs.example.com;SAN=*.example.com;certType=TLS"}}
+	userRoles = wildcardRoles
+	client.newAccount(wildcardRoles)
+
+	w, order := client.newOrder([]cm.AcmeIdentifier{{Type: cm.AcmeIdentifierDns, Value: "*.example.com"}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	if !assert.Len(t, order.Authorizations, 1) {
+		t.FailNow()
+	}
+	w = client.post(order.Authorizations[0], nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var authz cm.AcmeAuthorization
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &authz))
+	assert.Equal(t, "example.com", authz.Identifier.Value)
+	assert.True(t, authz.Wildcard)
+
+	csr, _ := acmeCsr(t, "", []string{"*.example.com"}, nil)
+	w = client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w, _ = client.newOrder([]cm.AcmeIdentifier{{Type: cm.AcmeIdentifierDns, Value: "kbs.*.example.com"}})
+	assertAcmeProblem(t, w, http.StatusBadRequest, "malformed")
+}
+
+func TestAcmeFinalizeRevokedRoles(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+	_, order := client.newOrder(acmeIdentifiers)
+
+	// the roles removed from the user in AAS cannot be used by the account anymore
+	userRoles = nil
+	csr, _ := acmeCsr(t, "kbs.example.com", []string{"kbs.example.com"}, []net.IP{net.ParseIP("10.1.1.1")})
+	assertAcmeProblem(t, client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false), http.StatusForbidden, "unauthorized")
+
+	userRoles = acmeRoles
+	w := client.post(order.Finalize, cm.AcmeFinalize{Csr: csr}, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAcmeOrderOfOtherAccount(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+	w, order := client.newOrder(acmeIdentifiers)
+	orderUrl := w.Header().Get("Location")
+
+	otherClient := newAcmeClient(t)
+	otherClient.newAccount(acmeRoles)
+	assertAcmeProblem(t, otherClient.post(orderUrl, nil, false), http.StatusNotFound, "malformed")
+	assertAcmeProblem(t, otherClient.post(order.Authorizations[0], nil, false), http.StatusNotFound, "malformed")
+	assertAcmeProblem(t, otherClient.post(client.accountUrl, nil, false), http.StatusUnauthorized, "unauthorized")
+
+	// the malformed IDs are not found
+	assertAcmeProblem(t, otherClient.post(mockAcmeUrl+"order/not.an.id", nil, false), http.StatusNotFound, "malformed")
+	assertAcmeProblem(t, otherClient.post(mockAcmeUrl+"authz/not.an.id", nil, false), http.StatusNotFound, "malformed")
+	assertAcmeProblem(t, otherClient.post(mockAcmeUrl+"account/not.an.id", nil, false), http.StatusNotFound, "malformed")
+}
+
+func TestAcmeDeactivateAccount(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+
+	w := client.post(client.accountUrl, cm.AcmeAccount{Status: cm.AcmeStatusDeactivated}, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var account cm.AcmeAccount
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &account))
+	assert.Equal(t, cm.AcmeStatusDeactivated, account.Status)
+
+	w, _ = client.newOrder(acmeIdentifiers)
+	assertAcmeProblem(t, w, http.StatusUnauthorized, "unauthorized")
+}
+
+func TestAcmeCertProfile(t *testing.T) {
+	controller := AcmeController{}
+	assert.Equal(t, constants.Tls, controller.certProfile().Name)
+	controller.Config = &config.Configuration{Acme: config.AcmeConfig{CertProfile: constants.TlsClient}}
+	assert.Equal(t, constants.TlsClient, controller.certProfile().Name)
+}
+
+func TestAcmeRequestUrlMismatch(t *testing.T) {
+	teardown := setupAcme(t)
+	defer teardown()
+	client := newAcmeClient(t)
+	client.newAccount(acmeRoles)
+
+	// a request signed for an endpoint cannot be replayed to another one
+	_, order := client.newOrder(acmeIdentifiers)
+	w := client.post(order.Authorizations[0], nil, false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	options := (&jose.SignerOptions{}).WithHeader("url", order.Authorizations[0]).WithHeader("nonce", client.nonce)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256,
+		Key: jose.JSONWebKey{Key: client.key, KeyID: client.accountUrl}}, options)
+	assert.NoError(t, err)
+	jws, err := signer.Sign(nil)
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, order.Authorizations[1], bytes.NewBufferString(serializeJws(t, jws)))
+	req.Header.Set("Content-Type", constants.HTTPMediaTypeJose)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assertAcmeProblem(t, w, http.StatusUnauthorized, "unauthorized")
+}
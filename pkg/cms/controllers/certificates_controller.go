@@ -8,7 +8,9 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"encoding/pem"
+	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/utils"
@@ -20,6 +22,9 @@ import (
 	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
 	v "github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
 	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	cm "github.com/intel-secl/intel-secl/v5/pkg/model/cms"
+	"github.com/pkg/errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -27,9 +32,10 @@ import (
 )
 
 type CertificatesController struct {
-	Config    *config.Configuration
-	CaAttribs map[string]constants.CaAttrib
-	SerialNo  string
+	Config         *config.Configuration
+	CaAttribs      map[string]constants.CaAttrib
+	SerialNo       string
+	IssuedCertsDir string
 }
 
 //GetCertificates is used to get the JWT Signing/TLS certificate upon JWT validation
@@ -99,6 +105,18 @@ func (controller CertificatesController) GetCertificates(httpWriter http.Respons
 		}
 		return
 	}
+	profile := controller.Config.GetCertProfile(certType)
+	if profile == nil {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.Errorf("resource/certificates:GetCertificates() No certificate profile for certType %s", certType)
+		httpWriter.WriteHeader(http.StatusBadRequest)
+		_, err = httpWriter.Write([]byte("Invalid certType provided"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+		}
+		return
+	}
+	log.Debugf("resource/certificates:GetCertificates() Processing CSR with cert type %v and profile %v", certType, profile.Name)
 
 	requestBodyBytes, err := ioutil.ReadAll(httpRequest.Body)
 	if err != nil {
@@ -147,12 +165,12 @@ func (controller CertificatesController) GetCertificates(httpWriter http.Respons
 		return
 	}
 
-	err = validation.ValidateCertificateRequest(controller.Config, clientCSR, certType, ctxMap)
+	err = validation.ValidateCertificateRequest(profile, clientCSR, ctxMap)
 	if err != nil {
 		slog.Warning(commLogMsg.InvalidInputBadParam)
 		log.WithError(err).Error("resource/certificates:GetCertificates() Invalid CSR provided")
 		httpWriter.WriteHeader(http.StatusBadRequest)
-		_, err = httpWriter.Write([]byte("Invalid CSR provided"))
+		_, err = httpWriter.Write([]byte("Invalid CSR provided: " + err.Error()))
 		if err != nil {
 			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
 		}
@@ -160,20 +178,80 @@ func (controller CertificatesController) GetCertificates(httpWriter http.Respons
 	}
 	log.Debug("resource/certificates:GetCertificates() Received valid CSR")
 
-	serialNumber, err := utils.GetNextSerialNumber(controller.SerialNo)
+	certificate, caCert, err := controller.issueCertificate(profile, clientCSR)
+	if err != nil {
+		log.WithError(err).Error("resource/certificates:GetCertificates() Cannot issue certificate")
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		_, err = httpWriter.Write([]byte("Cannot issue certificate"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+		}
+		return
+	}
+
+	httpWriter.Header().Add("Content-Type", consts.HTTPMediaTypePemFile)
+	httpWriter.WriteHeader(http.StatusOK)
+	// encode the certificate first
+	err = pem.Encode(httpWriter, &pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+	if err != nil {
+		log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to encode certificate")
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		_, err = httpWriter.Write([]byte("Cannot encode issued certificate"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+		}
+		return
+	}
+	// include the issuing CA as well since clients would need the entire chain minus the root.
+	err = pem.Encode(httpWriter, &pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
 	if err != nil {
-		log.WithError(err).Error("resource/certificates:GetCertificates() Failed to read next Serial Number")
+		log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to encode certificate")
 		httpWriter.WriteHeader(http.StatusInternalServerError)
-		_, err = httpWriter.Write([]byte("Failed to read next Serial Number"))
+		_, err = httpWriter.Write([]byte("Cannot encode Issuing CA"))
 		if err != nil {
 			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
 		}
 		return
 	}
+	return
+}
+
+// issueCertificate issues the certificate for the CSR validated against the profile, records the certificate in the
+// inventory of the issued certificates and returns the DER certificate with the issuing CA
+func (controller CertificatesController) issueCertificate(profile *config.CertProfile,
+	clientCSR *x509.CertificateRequest) ([]byte, *x509.Certificate, error) {
+	log.Trace("resource/certificates:issueCertificate() Entering")
+	defer log.Trace("resource/certificates:issueCertificate() Leaving")
 
+	serialNumber, err := utils.GetNextSerialNumber(controller.SerialNo)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to read next Serial Number")
+	}
+
+	caAttr := constants.GetCaAttribs(profile.IssuingCa, controller.CaAttribs)
+	caCert, caPrivKey, err := crypt.LoadX509CertAndPrivateKey(caAttr.CertPath, caAttr.KeyPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Could not load Issuing CA")
+	}
+
+	keyUsage, err := profile.KeyUsage()
+	var extKeyUsage []x509.ExtKeyUsage
+	if err == nil {
+		extKeyUsage, err = profile.ExtKeyUsage()
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Invalid certificate profile")
+	}
+
+	notBefore := time.Now()
+	notAfter := notBefore.AddDate(0, 0, profile.ValidityDays)
+	// the certificate cannot outlive its issuing CA
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
 	clientCRTTemplate := x509.Certificate{
-		Signature:          clientCSR.Signature,
-		SignatureAlgorithm: clientCSR.SignatureAlgorithm,
+		// the intermediate CAs have RSA keys, whatever the key type of the CSR
+		SignatureAlgorithm: x509.SHA384WithRSA,
 
 		PublicKeyAlgorithm: clientCSR.PublicKeyAlgorithm,
 		PublicKey:          clientCSR.PublicKey,
@@ -182,87 +260,135 @@ func (controller CertificatesController) GetCertificates(httpWriter http.Respons
 		Subject: pkix.Name{
 			CommonName: clientCSR.Subject.CommonName,
 		},
-		NotBefore: time.Now(),
-		NotAfter:  time.Now().AddDate(1, 0, 0),
-	}
-
-	// TODO: is the certificate requested is not a TLS certificate, we need to make sure that there is no SAN list
-	// in the CSR and that the the CN is not in the form of a domain name/ IP address
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
 
-	var issuingCa string
-	log.Debugf("resource/certificates:GetCertificates() Processing CSR with cert type - %v", certType)
-	if strings.EqualFold(certType, "TLS") {
-		issuingCa = constants.Tls
-		clientCRTTemplate.DNSNames = clientCSR.DNSNames
+		KeyUsage:    keyUsage,
+		ExtKeyUsage: extKeyUsage,
+	}
+	// the SANs of the CSR have been validated against the profile, the other profiles never have a SAN list
+	if len(profile.SanPatterns) > 0 {
+		clientCRTTemplate.DNSNames = validation.RequestedDNSNames(clientCSR)
 		clientCRTTemplate.IPAddresses = clientCSR.IPAddresses
+	}
+	if controller.Config != nil && controller.Config.Revocation.BaseUrl != "" {
+		clientCRTTemplate.CRLDistributionPoints = []string{crlUrl(controller.Config.Revocation.BaseUrl, profile.IssuingCa)}
+		clientCRTTemplate.OCSPServer = []string{ocspUrl(controller.Config.Revocation.BaseUrl)}
+	}
 
-		clientCRTTemplate.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment
-		clientCRTTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth}
-	} else if strings.EqualFold(certType, "Flavor-Signing") || strings.EqualFold(certType, "JWT-Signing") || strings.EqualFold(certType, "Signing") {
-		issuingCa = constants.Signing
-		clientCRTTemplate.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment
-	} else if strings.EqualFold(certType, "TLS-Client") {
-		issuingCa = constants.TlsClient
-		clientCRTTemplate.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment
-		clientCRTTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	certificate, err := x509.CreateCertificate(rand.Reader, &clientCRTTemplate, caCert, clientCSR.PublicKey, caPrivKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Cannot create certificate from CSR")
+	}
 
-	} else {
-		log.Errorf("Invalid certType provided")
-		httpWriter.WriteHeader(http.StatusBadRequest)
-		_, err = httpWriter.Write([]byte("Invalid certType provided"))
-		if err != nil {
-			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
-		}
-		return
+	// the certificate is recorded before being returned so that it can always be revoked
+	issuedCert, err := x509.ParseCertificate(certificate)
+	if err == nil {
+		err = utils.StoreIssuedCertificate(controller.IssuedCertsDir, issuedCert, profile.IssuingCa)
 	}
-	caAttr := constants.GetCaAttribs(issuingCa, controller.CaAttribs)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Cannot record issued certificate")
+	}
+	log.Infof("resource/certificates:issueCertificate() Issued certificate with serial number %s for requested CSR with CN - %v",
+		utils.SerialNumberToString(serialNumber), clientCSR.Subject.String())
+	return certificate, caCert, nil
+}
 
-	caCert, caPrivKey, err := crypt.LoadX509CertAndPrivateKey(caAttr.CertPath, caAttr.KeyPath)
+//RevokeCertificate is used to revoke a certificate issued by CMS, the certificate is then listed in the CRL of its
+//issuing CA and reported as revoked by the OCSP responder
+func (controller CertificatesController) RevokeCertificate(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/certificates:RevokeCertificate() Entering")
+	defer log.Trace("resource/certificates:RevokeCertificate() Leaving")
+
+	privileges, err := context.GetUserRoles(httpRequest)
 	if err != nil {
-		log.WithError(err).Error("resource/certificates:GetCertificates() Could not load Issuing CA")
+		slog.WithError(err).Warn("resource/certificates:RevokeCertificate() Failed to read roles and permissions")
 		httpWriter.WriteHeader(http.StatusInternalServerError)
-		_, err = httpWriter.Write([]byte("Cannot load Issuing CA"))
+		_, err = httpWriter.Write([]byte("Could not get user roles from http context"))
 		if err != nil {
-			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+			log.WithError(err).Errorf("resource/certificates:RevokeCertificate() Failed to write response")
 		}
 		return
 	}
 
-	certificate, err := x509.CreateCertificate(rand.Reader, &clientCRTTemplate, caCert, clientCSR.PublicKey, caPrivKey)
+	_, foundRole := auth.ValidatePermissionAndGetRoleContext(privileges,
+		[]ct.RoleInfo{{Service: constants.ServiceName, Name: constants.CertRevokerGroupName}},
+		false)
+	if !foundRole {
+		slog.Warning(commLogMsg.UnauthorizedAccess)
+		httpWriter.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	serialNumber, err := utils.SerialNumberFromString(mux.Vars(httpRequest)["serial"])
 	if err != nil {
-		log.WithError(err).Error("resource/certificates:GetCertificates() Cannot create certificate from CSR")
-		httpWriter.WriteHeader(http.StatusInternalServerError)
-		_, err = httpWriter.Write([]byte("Cannot create certificate"))
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.WithError(err).Error("resource/certificates:RevokeCertificate() Invalid serial number provided")
+		httpWriter.WriteHeader(http.StatusBadRequest)
+		_, err = httpWriter.Write([]byte("Invalid serial number provided"))
 		if err != nil {
-			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+			log.WithError(err).Errorf("resource/certificates:RevokeCertificate() Failed to write response")
 		}
 		return
 	}
 
-	httpWriter.Header().Add("Content-Type", consts.HTTPMediaTypePemFile)
-	httpWriter.WriteHeader(http.StatusOK)
-	// encode the certificate first
-	err = pem.Encode(httpWriter, &pem.Block{Type: "CERTIFICATE", Bytes: certificate})
-	if err != nil {
-		log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to encode certificate")
-		httpWriter.WriteHeader(http.StatusInternalServerError)
-		_, err = httpWriter.Write([]byte("Cannot encode issued certificate"))
+	var revokeRequest cm.RevokeCertificate
+	if httpRequest.ContentLength != 0 {
+		if httpRequest.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+			httpWriter.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err = httpWriter.Write([]byte("Content type not supported"))
+			if err != nil {
+				log.WithError(err).Errorf("resource/certificates:RevokeCertificate() Failed to write response")
+			}
+			return
+		}
+		dec := json.NewDecoder(httpRequest.Body)
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&revokeRequest)
+		if err != nil && err != io.EOF {
+			slog.Warning(commLogMsg.InvalidInputBadParam)
+			log.WithError(err).Error("resource/certificates:RevokeCertificate() Invalid request body provided")
+			httpWriter.WriteHeader(http.StatusBadRequest)
+			_, err = httpWriter.Write([]byte("Invalid request body provided"))
+			if err != nil {
+				log.WithError(err).Errorf("resource/certificates:RevokeCertificate() Failed to write response")
+			}
+			return
+		}
+	}
+	reason, ok := revocationReasons[revokeRequest.Reason]
+	if !ok {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.Errorf("resource/certificates:RevokeCertificate() Invalid revocation reason %s provided", revokeRequest.Reason)
+		httpWriter.WriteHeader(http.StatusBadRequest)
+		_, err = httpWriter.Write([]byte("Invalid revocation reason provided"))
 		if err != nil {
-			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+			log.WithError(err).Errorf("resource/certificates:RevokeCertificate() Failed to write response")
 		}
 		return
 	}
-	// include the issuing CA as well since clients would need the entire chain minus the root.
-	err = pem.Encode(httpWriter, &pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
+
+	revokedCert, err := utils.RevokeIssuedCertificate(controller.IssuedCertsDir, serialNumber, reason)
 	if err != nil {
-		log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to encode certificate")
-		httpWriter.WriteHeader(http.StatusInternalServerError)
-		_, err = httpWriter.Write([]byte("Cannot encode Issuing CA"))
+		var status int
+		var message string
+		switch errors.Cause(err) {
+		case utils.ErrCertificateNotFound:
+			status, message = http.StatusNotFound, "Certificate with the serial number was not issued by CMS"
+		case utils.ErrCertificateRevoked:
+			status, message = http.StatusBadRequest, "Certificate is already revoked"
+		default:
+			log.WithError(err).Error("resource/certificates:RevokeCertificate() Could not revoke certificate")
+			status, message = http.StatusInternalServerError, "Could not revoke certificate"
+		}
+		httpWriter.WriteHeader(status)
+		_, err = httpWriter.Write([]byte(message))
 		if err != nil {
-			log.WithError(err).Errorf("resource/certificates:GetCertificates() Failed to write response")
+			log.WithError(err).Errorf("resource/certificates:RevokeCertificate() Failed to write response")
 		}
 		return
 	}
-	log.Infof("resource/certificates:GetCertificates() Issued certificate for requested CSR with CN - %v", clientCSR.Subject.String())
-	return
+	slog.Infof("resource/certificates:RevokeCertificate() Revoked certificate with serial number %s and subject %s",
+		revokedCert.SerialNumber, revokedCert.Subject)
+	httpWriter.WriteHeader(http.StatusNoContent)
 }
@@ -6,7 +6,10 @@ package controllers
 
 import (
 	"bytes"
+	"crypto/x509"
+	"encoding/pem"
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
@@ -14,7 +17,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 var router *mux.Router
@@ -22,9 +27,12 @@ var w *httptest.ResponseRecorder
 var certificatesController CertificatesController
 
 var payload = mockCertificate("CERTIFICATE REQUEST", false, false)
+var signingPayload = mockCsr("AAS JWT Signing Certificate", nil)
+var tlsClientPayload = mockCsr("TA TLS Client Certificate", nil)
 var role1 = ct.RoleInfo{"CMS", "CertApprover", "CN=AAS JWT Signing Certificate;CERTTYPE=JWT-Signing"}
 var role2 = ct.RoleInfo{"CMS", "CertApprover", "CN=AAS TLS Certificate;SAN=10.10.10.10,10.10.10.10;CERTTYPE=TLS"}
-var roles = []ct.RoleInfo{role1, role2}
+var role3 = ct.RoleInfo{"CMS", "CertApprover", "CN=TA TLS Client Certificate;certType=TLS-Client"}
+var roles = []ct.RoleInfo{role1, role2, role3}
 var claims = ct.AuthClaims{
 	Roles:       roles,
 	Permissions: []ct.PermissionInfo{},
@@ -35,7 +43,8 @@ var mockPathCert map[string]constants.CaAttrib
 func setup(t *testing.T) func() {
 	mockPath, mockPathCert = CreateTestFilePath()
 	CreateIntermediateCa(mockPath, mockPathCert)
-	certificatesController = CertificatesController{CaAttribs: mockPathCert, SerialNo: mockPath + MockSerialNo}
+	certificatesController = CertificatesController{CaAttribs: mockPathCert, SerialNo: mockPath + MockSerialNo,
+		IssuedCertsDir: mockPath + MockIssuedCertsDir}
 	router = mux.NewRouter()
 	w = httptest.NewRecorder()
 	return func() {
@@ -236,7 +245,7 @@ func TestUnabletoGetIssuingCertFromFile(t *testing.T) {
 	os.Remove(constants.GetCaAttribs("Signing", mockPathCert).CertPath)
 	os.Remove(constants.GetCaAttribs("Signing", mockPathCert).KeyPath)
 	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
-	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing", bytes.NewBuffer([]byte(payload)))
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing", bytes.NewBuffer([]byte(signingPayload)))
 	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
 	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
 	req = context.SetUserRoles(req, claims.Roles)
@@ -255,7 +264,7 @@ func TestIncorrectSigningCertProvided(t *testing.T) {
 	fcert.WriteString("test")
 	fcert.Close()
 	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
-	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing", bytes.NewBuffer([]byte(payload)))
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing", bytes.NewBuffer([]byte(signingPayload)))
 	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
 	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
 	req = context.SetUserRoles(req, claims.Roles)
@@ -270,7 +279,7 @@ func TestGetIssuingCertSigning(t *testing.T) {
 	teardown := setup(t)
 	defer teardown()
 	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
-	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing", bytes.NewBuffer([]byte(payload)))
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing", bytes.NewBuffer([]byte(signingPayload)))
 	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
 	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
 	req = context.SetUserRoles(req, claims.Roles)
@@ -300,7 +309,7 @@ func TestGetIssuingCertTLSClient(t *testing.T) {
 	teardown := setup(t)
 	defer teardown()
 	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
-	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=TLS-Client", bytes.NewBuffer([]byte(payload)))
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=TLS-Client", bytes.NewBuffer([]byte(tlsClientPayload)))
 	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
 	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
 	req = context.SetUserRoles(req, claims.Roles)
@@ -310,3 +319,60 @@ func TestGetIssuingCertTLSClient(t *testing.T) {
 		t.Error("Certificate with type tls-client should be created")
 	}
 }
+
+func TestGetCertificatesProfileViolation(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing",
+		bytes.NewBuffer([]byte(mockCsr("AAS JWT Signing Certificate", []string{"aas.example.com"}))))
+	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
+	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
+	req = context.SetUserRoles(req, claims.Roles)
+	router.ServeHTTP(w, req)
+	if !(w.Code == http.StatusBadRequest) || !strings.Contains(w.Body.String(), "does not allow SAN list") {
+		t.Error("Signing certificate with SAN list should be rejected with the profile violation")
+	}
+}
+
+func TestGetCertificatesProfileValidity(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+	profiles := config.DefaultCertProfiles()
+	profiles[2].ValidityDays = 30
+	certificatesController.Config = &config.Configuration{CertProfiles: profiles}
+	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=JWT-Signing", bytes.NewBuffer([]byte(signingPayload)))
+	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
+	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
+	req = context.SetUserRoles(req, claims.Roles)
+	router.ServeHTTP(w, req)
+	if !(w.Code == http.StatusOK) {
+		t.Fatal("Certificate with type signing should be created")
+	}
+	block, _ := pem.Decode(w.Body.Bytes())
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || cert.NotAfter.After(time.Now().AddDate(0, 0, 30)) || len(cert.ExtKeyUsage) != 0 {
+		t.Error("Certificate should be issued with the validity and usages of the profile")
+	}
+}
+
+func TestGetCertificatesEmptyDNSName(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType=TLS-Client",
+		bytes.NewBuffer([]byte(mockCsr("TA TLS Client Certificate", []string{""}))))
+	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
+	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
+	req = context.SetUserRoles(req, claims.Roles)
+	router.ServeHTTP(w, req)
+	if !(w.Code == http.StatusOK) {
+		t.Fatal("Certificate with type tls-client should be created")
+	}
+	block, _ := pem.Decode(w.Body.Bytes())
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || len(cert.DNSNames) != 0 || len(cert.IPAddresses) != 0 {
+		t.Error("Certificate should be issued without SAN list")
+	}
+}
@@ -26,6 +26,7 @@ var MockMp = map[string]constants.CaAttrib{
 }
 
 var MockSerialNo = "serial-number"
+var MockIssuedCertsDir = "issued-certificates/"
 
 func GenerateRandString() string {
 	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -97,6 +98,21 @@ func mockCertificate(csr string, extraChar bool, differentAlgo bool) string {
 	return csrCert
 }
 
+func mockCsr(cn string, dnsNames []string) string {
+	keyBytes, _ := rsa.GenerateKey(rand.Reader, constants.DefaultKeyAlgorithmLength)
+	csrTemplate := x509.CertificateRequest{
+		SignatureAlgorithm: x509.SHA384WithRSA,
+		DNSNames:           dnsNames,
+		Subject: pkix.Name{
+			CommonName: cn,
+		},
+	}
+	csrBytes, _ := x509.CreateCertificateRequest(rand.Reader, &csrTemplate, keyBytes)
+	buffer := new(bytes.Buffer)
+	pem.Encode(buffer, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrBytes})
+	return buffer.String()
+}
+
 func CreateRootCa(path string, mockmp map[string]constants.CaAttrib) {
 	c := config.Configuration{}
 	rootCa := tasks.RootCa{
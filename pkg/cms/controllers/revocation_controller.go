@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	cm "github.com/intel-secl/intel-secl/v5/pkg/model/cms"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxOcspRequestSize is the maximum size of the OCSP requests, which only hold a few hashes and a serial number
+const maxOcspRequestSize = 1 << 12
+
+// revocationReasons maps the revocation reasons of the revoke requests to the RFC 5280 reason codes
+var revocationReasons = map[string]int{
+	"":                                      ocsp.Unspecified,
+	cm.RevocationReasonUnspecified:          ocsp.Unspecified,
+	cm.RevocationReasonKeyCompromise:        ocsp.KeyCompromise,
+	cm.RevocationReasonAffiliationChanged:   ocsp.AffiliationChanged,
+	cm.RevocationReasonSuperseded:           ocsp.Superseded,
+	cm.RevocationReasonCessationOfOperation: ocsp.CessationOfOperation,
+}
+
+type RevocationController struct {
+	Config         *config.Configuration
+	CaAttribs      map[string]constants.CaAttrib
+	IssuedCertsDir string
+}
+
+// crlUrl returns the URL of the CRL of the intermediate CA embedded as distribution point in the issued certificates
+func crlUrl(baseUrl, issuingCa string) string {
+	return strings.TrimSuffix(baseUrl, "/") + "/crl/" + issuingCa
+}
+
+// ocspUrl returns the URL of the OCSP responder embedded in the issued certificates
+func ocspUrl(baseUrl string) string {
+	return strings.TrimSuffix(baseUrl, "/") + "/ocsp"
+}
+
+func (controller RevocationController) validity() time.Duration {
+	if controller.Config == nil || controller.Config.Revocation.CrlValidityHours <= 0 {
+		return constants.DefaultCrlValidityHours * time.Hour
+	}
+	return time.Duration(controller.Config.Revocation.CrlValidityHours) * time.Hour
+}
+
+// GetCrl is used to get the CRL of an intermediate CA, listing the unexpired certificates revoked for the CA
+func (controller RevocationController) GetCrl(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/revocation:GetCrl() Entering")
+	defer log.Trace("resource/revocation:GetCrl() Leaving")
+
+	issuingCa := mux.Vars(httpRequest)["issuingCa"]
+	if !isIntermediateCa(issuingCa) {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.Errorf("resource/revocation:GetCrl() Invalid issuing CA %s provided", issuingCa)
+		httpWriter.WriteHeader(http.StatusNotFound)
+		_, err := httpWriter.Write([]byte("Invalid issuing CA provided"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/revocation:GetCrl() Failed to write response")
+		}
+		return
+	}
+
+	crl, err := controller.createCrl(issuingCa)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:GetCrl() Could not create CRL of issuing CA %s", issuingCa)
+		httpWriter.WriteHeader(http.StatusInternalServerError)
+		_, err = httpWriter.Write([]byte("Cannot create CRL"))
+		if err != nil {
+			log.WithError(err).Errorf("resource/revocation:GetCrl() Failed to write response")
+		}
+		return
+	}
+	httpWriter.Header().Set("Content-Type", constants.HTTPMediaTypePkixCrl)
+	httpWriter.WriteHeader(http.StatusOK)
+	_, err = httpWriter.Write(crl)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:GetCrl() Failed to write response")
+	}
+}
+
+func (controller RevocationController) createCrl(issuingCa string) ([]byte, error) {
+	caAttr := constants.GetCaAttribs(issuingCa, controller.CaAttribs)
+	caCert, caPrivKey, err := crypt.LoadX509CertAndPrivateKey(caAttr.CertPath, caAttr.KeyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load issuing CA")
+	}
+	signer, ok := caPrivKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("Issuing CA private key cannot sign")
+	}
+	revokedCerts, err := utils.RetrieveRevokedCertificates(controller.IssuedCertsDir, issuingCa)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := x509.RevocationList{
+		// the CRL number must increase with each new CRL, the CRLs are created on request so the time is used
+		Number:     big.NewInt(now.UnixNano()),
+		ThisUpdate: now,
+		NextUpdate: now.Add(controller.validity()),
+	}
+	for _, revokedCert := range revokedCerts {
+		serialNumber, err := utils.SerialNumberFromString(revokedCert.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		template.RevokedCertificateEntries = append(template.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   serialNumber,
+			RevocationTime: *revokedCert.RevokedAt,
+			ReasonCode:     revokedCert.RevocationReason,
+		})
+	}
+	return x509.CreateRevocationList(rand.Reader, &template, caCert, signer)
+}
+
+// GetOcspResponse is the OCSP responder of the intermediate CAs, it reports the status of the certificates issued by
+// CMS. The requests are received as the body of a POST or base64 encoded in the path of a GET as defined in RFC 6960
+func (controller RevocationController) GetOcspResponse(httpWriter http.ResponseWriter, httpRequest *http.Request) {
+	log.Trace("resource/revocation:GetOcspResponse() Entering")
+	defer log.Trace("resource/revocation:GetOcspResponse() Leaving")
+
+	var requestBytes []byte
+	var err error
+	if httpRequest.Method == http.MethodGet {
+		requestBytes, err = base64.StdEncoding.DecodeString(mux.Vars(httpRequest)["request"])
+	} else {
+		if httpRequest.Header.Get("Content-Type") != constants.HTTPMediaTypeOcspRequest {
+			httpWriter.WriteHeader(http.StatusUnsupportedMediaType)
+			_, err = httpWriter.Write([]byte("Content type not supported"))
+			if err != nil {
+				log.WithError(err).Errorf("resource/revocation:GetOcspResponse() Failed to write response")
+			}
+			return
+		}
+		requestBytes, err = io.ReadAll(io.LimitReader(httpRequest.Body, maxOcspRequestSize))
+	}
+	var ocspRequest *ocsp.Request
+	if err == nil {
+		ocspRequest, err = ocsp.ParseRequest(requestBytes)
+	}
+	if err != nil {
+		slog.Warning(commLogMsg.InvalidInputBadParam)
+		log.WithError(err).Error("resource/revocation:GetOcspResponse() Invalid OCSP request provided")
+		writeOcspResponse(httpWriter, ocsp.MalformedRequestErrorResponse)
+		return
+	}
+
+	response, err := controller.createOcspResponse(ocspRequest)
+	if err != nil {
+		log.WithError(err).Error("resource/revocation:GetOcspResponse() Could not create OCSP response")
+		writeOcspResponse(httpWriter, ocsp.InternalErrorErrorResponse)
+		return
+	}
+	writeOcspResponse(httpWriter, response)
+}
+
+func (controller RevocationController) createOcspResponse(ocspRequest *ocsp.Request) ([]byte, error) {
+	issuingCa, caCert, caPrivKey, err := controller.findIssuingCa(ocspRequest)
+	if err != nil {
+		return nil, err
+	}
+	if caCert == nil {
+		// the certificate was not issued by CMS, so CMS is not authorized to respond for it
+		return ocsp.UnauthorizedErrorResponse, nil
+	}
+	signer, ok := caPrivKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("Issuing CA private key cannot sign")
+	}
+
+	now := time.Now()
+	template := ocsp.Response{
+		Status:       ocsp.Unknown,
+		SerialNumber: ocspRequest.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(controller.validity()),
+		IssuerHash:   ocspRequest.HashAlgorithm,
+	}
+	issuedCert, err := utils.RetrieveIssuedCertificate(controller.IssuedCertsDir, ocspRequest.SerialNumber)
+	if err != nil && errors.Cause(err) != utils.ErrCertificateNotFound {
+		return nil, err
+	}
+	if issuedCert != nil && issuedCert.IssuingCa == issuingCa {
+		if issuedCert.Revoked() {
+			template.Status = ocsp.Revoked
+			template.RevokedAt = *issuedCert.RevokedAt
+			template.RevocationReason = issuedCert.RevocationReason
+		} else {
+			template.Status = ocsp.Good
+		}
+	}
+	// the intermediate CA signs the response itself, so no responder certificate is needed
+	return ocsp.CreateResponse(caCert, caCert, template, signer)
+}
+
+// findIssuingCa returns the intermediate CA identified by the name and key hashes of the OCSP request, or no
+// certificate when the request is not for one of the intermediate CAs
+func (controller RevocationController) findIssuingCa(ocspRequest *ocsp.Request) (string, *x509.Certificate, interface{}, error) {
+	if !ocspRequest.HashAlgorithm.Available() {
+		return "", nil, nil, nil
+	}
+	for _, issuingCa := range constants.GetIntermediateCAs() {
+		caAttr := constants.GetCaAttribs(issuingCa, controller.CaAttribs)
+		caCert, caPrivKey, err := crypt.LoadX509CertAndPrivateKey(caAttr.CertPath, caAttr.KeyPath)
+		if err != nil {
+			return "", nil, nil, errors.Wrapf(err, "Could not load issuing CA %s", issuingCa)
+		}
+		var publicKeyInfo struct {
+			Algorithm pkix.AlgorithmIdentifier
+			PublicKey asn1.BitString
+		}
+		if _, err = asn1.Unmarshal(caCert.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+			return "", nil, nil, errors.Wrapf(err, "Could not parse public key of issuing CA %s", issuingCa)
+		}
+		nameHash := ocspRequest.HashAlgorithm.New()
+		nameHash.Write(caCert.RawSubject)
+		keyHash := ocspRequest.HashAlgorithm.New()
+		keyHash.Write(publicKeyInfo.PublicKey.RightAlign())
+		if bytes.Equal(nameHash.Sum(nil), ocspRequest.IssuerNameHash) && bytes.Equal(keyHash.Sum(nil), ocspRequest.IssuerKeyHash) {
+			return issuingCa, caCert, caPrivKey, nil
+		}
+	}
+	return "", nil, nil, nil
+}
+
+func writeOcspResponse(httpWriter http.ResponseWriter, response []byte) {
+	// as required by RFC 6960 the OCSP errors are reported in the response status, with a successful HTTP status
+	httpWriter.Header().Set("Content-Type", constants.HTTPMediaTypeOcspResponse)
+	httpWriter.WriteHeader(http.StatusOK)
+	_, err := httpWriter.Write(response)
+	if err != nil {
+		log.WithError(err).Errorf("resource/revocation:GetOcspResponse() Failed to write response")
+	}
+}
+
+func isIntermediateCa(issuingCa string) bool {
+	for _, intermediateCa := range constants.GetIntermediateCAs() {
+		if intermediateCa == issuingCa {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/tasks"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/utils"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+const mockRevocationBaseUrl = "https://cms.example.com:8445/cms/v1/"
+
+var revokerRoles = []ct.RoleInfo{{Service: constants.ServiceName, Name: constants.CertRevokerGroupName}}
+
+func setupRevocation(t *testing.T) func() {
+	mockPath, mockPathCert = CreateTestFilePath()
+	CreateRootCa(mockPath, mockPathCert)
+	// the intermediate CAs must be distinct for the OCSP responder to identify the issuing CA of the requests
+	intermediateCa := tasks.IntermediateCa{
+		ConsoleWriter: os.Stdout,
+		Config: &config.CACertConfig{
+			Validity:     constants.DefaultCACertValidity,
+			Organization: constants.DefaultOrganization,
+			Locality:     constants.DefaultLocality,
+			Province:     constants.DefaultProvince,
+			Country:      constants.DefaultCountry,
+		},
+		SerialNumberPath: mockPath + MockSerialNo,
+		CaAttribs:        mockPathCert,
+	}
+	assert.NoError(t, intermediateCa.Run())
+
+	cfg := &config.Configuration{Revocation: config.RevocationConfig{BaseUrl: mockRevocationBaseUrl, CrlValidityHours: 1}}
+	certificatesController = CertificatesController{Config: cfg, CaAttribs: mockPathCert, SerialNo: mockPath + MockSerialNo,
+		IssuedCertsDir: mockPath + MockIssuedCertsDir}
+	revocationController := RevocationController{Config: cfg, CaAttribs: mockPathCert, IssuedCertsDir: mockPath + MockIssuedCertsDir}
+	router = mux.NewRouter()
+	router.HandleFunc("/certificates", certificatesController.GetCertificates).Methods(http.MethodPost)
+	router.HandleFunc("/certificates/{serial:[0-9a-fA-F]+}/revoke", certificatesController.RevokeCertificate).Methods(http.MethodPost)
+	router.HandleFunc("/crl/{issuingCa}", revocationController.GetCrl).Methods(http.MethodGet)
+	router.HandleFunc("/ocsp", revocationController.GetOcspResponse).Methods(http.MethodPost)
+	router.HandleFunc("/ocsp/{request:.+}", revocationController.GetOcspResponse).Methods(http.MethodGet)
+	return func() {
+		DeleteTestFilePath(mockPath)
+		router = nil
+	}
+}
+
+func issueCertificate(t *testing.T, certType string) *x509.Certificate {
+	csr := payload
+	if certType == constants.TlsClient {
+		csr = tlsClientPayload
+	}
+	req, _ := http.NewRequest(http.MethodPost, "/certificates?certType="+certType, bytes.NewBuffer([]byte(csr)))
+	req.Header.Set("Accept", consts.HTTPMediaTypePemFile)
+	req.Header.Set("Content-Type", consts.HTTPMediaTypePemFile)
+	req = context.SetUserRoles(req, claims.Roles)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	block, _ := pem.Decode(w.Body.Bytes())
+	if !assert.NotNil(t, block) {
+		t.FailNow()
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	return cert
+}
+
+func revokeCertificate(serialNumber, body string, roles []ct.RoleInfo) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, "/certificates/"+serialNumber+"/revoke", strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+	}
+	req = context.SetUserRoles(req, roles)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func getCrl(t *testing.T, issuingCa string) *x509.RevocationList {
+	req, _ := http.NewRequest(http.MethodGet, "/crl/"+issuingCa, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, constants.HTTPMediaTypePkixCrl, w.Header().Get("Content-Type"))
+	crl, err := x509.ParseRevocationList(w.Body.Bytes())
+	assert.NoError(t, err)
+	caCert, err := crypt.GetCertFromPemFile(constants.GetCaAttribs(issuingCa, mockPathCert).CertPath)
+	assert.NoError(t, err)
+	assert.NoError(t, crl.CheckSignatureFrom(caCert))
+	return crl
+}
+
+func getOcspResponse(t *testing.T, cert *x509.Certificate, issuingCa string, usePost bool) *ocsp.Response {
+	caCert, err := crypt.GetCertFromPemFile(constants.GetCaAttribs(issuingCa, mockPathCert).CertPath)
+	assert.NoError(t, err)
+	ocspRequest, err := ocsp.CreateRequest(cert, caCert, &ocsp.RequestOptions{Hash: crypto.SHA256})
+	assert.NoError(t, err)
+
+	var req *http.Request
+	if usePost {
+		req, _ = http.NewRequest(http.MethodPost, "/ocsp", bytes.NewReader(ocspRequest))
+		req.Header.Set("Content-Type", constants.HTTPMediaTypeOcspRequest)
+	} else {
+		req, _ = http.NewRequest(http.MethodGet, "/ocsp/"+base64.StdEncoding.EncodeToString(ocspRequest), nil)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, constants.HTTPMediaTypeOcspResponse, w.Header().Get("Content-Type"))
+	response, err := ocsp.ParseResponseForCert(w.Body.Bytes(), cert, caCert)
+	assert.NoError(t, err)
+	return response
+}
+
+func TestIssuedCertificateHasRevocationInfo(t *testing.T) {
+	teardown := setupRevocation(t)
+	defer teardown()
+	cert := issueCertificate(t, "TLS-Client")
+	assert.Equal(t, []string{"https://cms.example.com:8445/cms/v1/crl/TLS-Client"}, cert.CRLDistributionPoints)
+	assert.Equal(t, []string{"https://cms.example.com:8445/cms/v1/ocsp"}, cert.OCSPServer)
+
+	issuedCert, err := utils.RetrieveIssuedCertificate(mockPath+MockIssuedCertsDir, cert.SerialNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.TlsClient, issuedCert.IssuingCa)
+	assert.False(t, issuedCert.Revoked())
+}
+
+func TestRevokeCertificate(t *testing.T) {
+	teardown := setupRevocation(t)
+	defer teardown()
+	cert := issueCertificate(t, "TLS-Client")
+	otherCert := issueCertificate(t, "TLS-Client")
+	serialNumber := utils.SerialNumberToString(cert.SerialNumber)
+
+	assert.Empty(t, getCrl(t, constants.TlsClient).RevokedCertificateEntries)
+	assert.Equal(t, ocsp.Good, getOcspResponse(t, cert, constants.TlsClient, true).Status)
+
+	w := revokeCertificate(serialNumber, `{"reason":"cessationOfOperation"}`, revokerRoles)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = revokeCertificate(serialNumber, "", revokerRoles)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	crl := getCrl(t, constants.TlsClient)
+	if assert.Len(t, crl.RevokedCertificateEntries, 1) {
+		assert.Equal(t, cert.SerialNumber, crl.RevokedCertificateEntries[0].SerialNumber)
+		assert.Equal(t, ocsp.CessationOfOperation, crl.RevokedCertificateEntries[0].ReasonCode)
+	}
+	assert.Empty(t, getCrl(t, constants.Tls).RevokedCertificateEntries)
+
+	response := getOcspResponse(t, cert, constants.TlsClient, false)
+	assert.Equal(t, ocsp.Revoked, response.Status)
+	assert.Equal(t, ocsp.CessationOfOperation, response.RevocationReason)
+	assert.Equal(t, ocsp.Good, getOcspResponse(t, otherCert, constants.TlsClient, true).Status)
+}
+
+func TestRevokeCertificateWithoutRevokerRole(t *testing.T) {
+	teardown := setupRevocation(t)
+	defer teardown()
+	cert := issueCertificate(t, "TLS")
+	w := revokeCertificate(utils.SerialNumberToString(cert.SerialNumber), "", claims.Roles)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRevokeUnknownCertificate(t *testing.T) {
+	teardown := setupRevocation(t)
+	defer teardown()
+	w := revokeCertificate("ff", "", revokerRoles)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeCertificateInvalidReason(t *testing.T) {
+	teardown := setupRevocation(t)
+	defer teardown()
+	cert := issueCertificate(t, "TLS")
+	w := revokeCertificate(utils.SerialNumberToString(cert.SerialNumber), `{"reason":"certificateHold"}`, revokerRoles)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = revokeCertificate(utils.SerialNumberToString(cert.SerialNumber), `{"serial":"1"}`, revokerRoles)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCrlInvalidIssuingCa(t *testing.T) {
+	teardown := setupRevocation(t)
+	defer teardown()
+	req, _ := http.NewRequest(http.MethodGet, "/crl/"+constants.Root, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOcspMalformedRequest(t *testing.T) {
+	teardown := setupRevocation(t)
+	defer teardown()
+	req, _ := http.NewRequest(http.MethodPost, "/ocsp", bytes.NewReader([]byte("test")))
+	req.Header.Set("Content-Type", constants.HTTPMediaTypeOcspRequest)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ocsp.MalformedRequestErrorResponse, w.Body.Bytes())
+}
@@ -42,6 +42,11 @@ func init() {
 	viper.SetDefault(config.AasTlsSan, constants.DefaultTlsSan)
 
 	viper.SetDefault(config.TokenDurationMins, constants.DefaultTokenDurationMins)
+
+	viper.SetDefault(config.RevocationCrlValidityHours, constants.DefaultCrlValidityHours)
+
+	viper.SetDefault(config.AcmeCertProfile, constants.Tls)
+	viper.SetDefault(config.AcmeEabKeyValidityHours, constants.DefaultAcmeEabKeyValidityHours)
 }
 
 func defaultConfig() *config.Configuration {
@@ -0,0 +1,66 @@
+/*
+ *  Copyright (C) 2022 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/utils"
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+// acmeBasePath is the path of the ACME endpoints
+var acmeBasePath = "/" + strings.ToLower(constants.ServiceName) + constants.ApiVersion + "/acme/"
+
+func newAcmeController(config *config.Configuration,
+	retrieveUserRoles func(username string) ([]ct.RoleInfo, error)) controllers.AcmeController {
+	return controllers.AcmeController{
+		CertificatesController: controllers.CertificatesController{Config: config, CaAttribs: constants.CertStoreMap,
+			SerialNo: constants.SerialNumberPath, IssuedCertsDir: constants.IssuedCertsDirPath},
+		AcmeDir:  constants.AcmeDirPath,
+		BasePath: acmeBasePath,
+		Nonces:   utils.NewAcmeNonces(constants.AcmeNonceValidity, constants.AcmeMaxNonces),
+
+		RetrieveUserRoles: retrieveUserRoles,
+	}
+}
+
+// SetAcmeRoutes is used to set the ACME endpoints, which are public since the ACME requests are authenticated by the
+// JWS signature of the accounts. The orders are finalized only while the users who created the external account keys
+// of the accounts still hold the roles retrieved by retrieveUserRoles.
+func SetAcmeRoutes(router *mux.Router, config *config.Configuration,
+	retrieveUserRoles func(username string) ([]ct.RoleInfo, error)) *mux.Router {
+	log.Trace("router/acme:SetAcmeRoutes() Entering")
+	defer log.Trace("router/acme:SetAcmeRoutes() Leaving")
+	acmeController := newAcmeController(config, retrieveUserRoles)
+	router.HandleFunc("/acme/directory", acmeController.GetDirectory).Methods(http.MethodGet)
+	router.HandleFunc("/acme/new-nonce", acmeController.NewNonce).Methods(http.MethodHead, http.MethodGet)
+	router.HandleFunc("/acme/new-account", acmeController.NewAccount).Methods(http.MethodPost)
+	router.HandleFunc("/acme/account/{id:[A-Za-z0-9_-]+}", acmeController.UpdateAccount).Methods(http.MethodPost)
+	router.HandleFunc("/acme/account/{id:[A-Za-z0-9_-]+}/orders", acmeController.GetAccountOrders).Methods(http.MethodPost)
+	router.HandleFunc("/acme/new-order", acmeController.NewOrder).Methods(http.MethodPost)
+	router.HandleFunc("/acme/order/{id:[A-Za-z0-9_-]+}", acmeController.GetOrder).Methods(http.MethodPost)
+	router.HandleFunc("/acme/order/{id:[A-Za-z0-9_-]+}/finalize", acmeController.FinalizeOrder).Methods(http.MethodPost)
+	router.HandleFunc("/acme/authz/{id:[A-Za-z0-9_-]+}", acmeController.GetAuthorization).Methods(http.MethodPost)
+	router.HandleFunc("/acme/cert/{id:[A-Za-z0-9_-]+}", acmeController.GetCertificate).Methods(http.MethodPost)
+	router.HandleFunc("/acme/revoke-cert", acmeController.RevokeCert).Methods(http.MethodPost)
+	return router
+}
+
+// SetAcmeEabKeyRoutes is used to set the endpoint creating the external account keys of the ACME accounts, which
+// requires an AAS token with the CertApprover roles bound to the accounts
+func SetAcmeEabKeyRoutes(router *mux.Router, config *config.Configuration) *mux.Router {
+	log.Trace("router/acme:SetAcmeEabKeyRoutes() Entering")
+	defer log.Trace("router/acme:SetAcmeEabKeyRoutes() Leaving")
+	acmeController := newAcmeController(config, nil)
+	router.HandleFunc("/acme/eab-keys", acmeController.CreateEabKey).Methods(http.MethodPost)
+	return router
+}
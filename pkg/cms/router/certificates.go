@@ -18,7 +18,9 @@ import (
 func SetCertificatesRoutes(router *mux.Router, config *config.Configuration) *mux.Router {
 	log.Trace("router/certificates:SetCertificatesRoutes() Entering")
 	defer log.Trace("router/certificates:SetCertificatesRoutes() Leaving")
-	certController := controllers.CertificatesController{Config: config, CaAttribs: constants.CertStoreMap, SerialNo: constants.SerialNumberPath}
+	certController := controllers.CertificatesController{Config: config, CaAttribs: constants.CertStoreMap, SerialNo: constants.SerialNumberPath,
+		IssuedCertsDir: constants.IssuedCertsDirPath}
 	router.HandleFunc("/certificates", certController.GetCertificates).Methods(http.MethodPost)
+	router.HandleFunc("/certificates/{serial:[0-9a-fA-F]+}/revoke", certController.RevokeCertificate).Methods(http.MethodPost)
 	return router
 }
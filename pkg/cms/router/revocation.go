@@ -0,0 +1,28 @@
+/*
+ *  Copyright (C) 2022 Intel Corporation
+ *  SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/controllers"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+)
+
+// SetRevocationRoutes is used to set the endpoints of the CRLs and of the OCSP responder, which are public so that
+// the relying parties can check the status of the certificates
+func SetRevocationRoutes(router *mux.Router, config *config.Configuration) *mux.Router {
+	log.Trace("router/revocation:SetRevocationRoutes() Entering")
+	defer log.Trace("router/revocation:SetRevocationRoutes() Leaving")
+	revocationController := controllers.RevocationController{Config: config, CaAttribs: constants.CertStoreMap,
+		IssuedCertsDir: constants.IssuedCertsDirPath}
+	router.HandleFunc("/crl/{issuingCa}", revocationController.GetCrl).Methods(http.MethodGet)
+	router.HandleFunc("/ocsp", revocationController.GetOcspResponse).Methods(http.MethodPost)
+	router.HandleFunc("/ocsp/{request:.+}", revocationController.GetOcspResponse).Methods(http.MethodGet)
+	return router
+}
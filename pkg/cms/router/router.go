@@ -8,23 +8,30 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/clients/aas"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v5/pkg/lib/common/os"
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 	"github.com/pkg/errors"
-	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
 var defaultLog = log.GetDefaultLogger()
 
 type Router struct {
-	cfg *config.Configuration
+	cfg                   *config.Configuration
+	fetchTokenRevocations func(since time.Time) (*jwtauth.TokenRevocations, error)
+	// fetchUserRoles is created at the first ACME order finalized, the ACME requests being concurrent
+	fetchUserRoles      func(username string) ([]ct.RoleInfo, error)
+	fetchUserRolesMutex sync.Mutex
 }
 
 // InitRoutes registers all routes for the application.
@@ -48,12 +55,28 @@ func defineSubRoutes(router *mux.Router, service string, cfg *config.Configurati
 	subRouter := router.PathPrefix(serviceApi).Subrouter()
 	subRouter = SetVersionRoutes(subRouter)
 	subRouter = SetCACertificatesRoutes(subRouter)
+	subRouter = SetRevocationRoutes(subRouter, cfg)
+	cfgRouter := &Router{cfg: cfg}
+	if cfg.CMS.Username != "" && cfg.CMS.Password != "" {
+		subRouter = SetAcmeRoutes(subRouter, cfg, cfgRouter.fnGetUserRoles)
+	} else {
+		defaultLog.Warn("router/router:defineSubRoutes() CMS service user is not configured, the ACME orders cannot be finalized")
+		subRouter = SetAcmeRoutes(subRouter, cfg, nil)
+	}
 
 	subRouter = router.PathPrefix(serviceApi).Subrouter()
-	cfgRouter := Router{cfg: cfg}
-	subRouter.Use(middleware.NewTokenAuth(constants.TrustedJWTSigningCertsDir, constants.ConfigDir, cfgRouter.fnGetJwtCerts,
-		time.Minute*constants.DefaultJwtValidateCacheKeyMins))
+	if cfg.CMS.Username != "" && cfg.CMS.Password != "" {
+		revocationList := jwtauth.NewRevocationList(cfgRouter.fnGetTokenRevocations,
+			time.Minute*constants.DefaultTokenRevocationsRefreshMins)
+		subRouter.Use(middleware.NewTokenAuthWithRevocationList(constants.TrustedJWTSigningCertsDir, constants.ConfigDir,
+			cfgRouter.fnGetJwtCerts, time.Minute*constants.DefaultJwtValidateCacheKeyMins, revocationList))
+	} else {
+		defaultLog.Warn("router/router:defineSubRoutes() CMS service user is not configured, the revoked tokens are accepted until they expire")
+		subRouter.Use(middleware.NewTokenAuth(constants.TrustedJWTSigningCertsDir, constants.ConfigDir, cfgRouter.fnGetJwtCerts,
+			time.Minute*constants.DefaultJwtValidateCacheKeyMins))
+	}
 	subRouter = SetCertificatesRoutes(subRouter, cfg)
+	subRouter = SetAcmeEabKeyRoutes(subRouter, cfg)
 }
 
 // Fetch JWT certificate from AAS
@@ -66,28 +89,43 @@ func (r *Router) fnGetJwtCerts() error {
 	if !strings.HasSuffix(cfg.AASApiUrl, "/") {
 		cfg.AASApiUrl = cfg.AASApiUrl + "/"
 	}
-	url := cfg.AASApiUrl + "jwt-certificates"
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	httpClient, err := r.aasHttpClient()
+	if err != nil {
+		return err
+	}
+
+	aasClient := aas.Client{BaseURL: cfg.AASApiUrl, HTTPClient: httpClient}
+	jwtCerts, err := aasClient.GetJwtSigningCertificates()
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not create http request")
+		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not retrieve jwt certificates")
 	}
-	req.Header.Add("accept", "application/x-pem-file")
+	for _, jwtCert := range jwtCerts {
+		err = crypt.SavePemCertWithShortSha1FileName(jwtCert, constants.TrustedJWTSigningCertsDir)
+		if err != nil {
+			return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+		}
+	}
+	return nil
+}
+
+// aasHttpClient creates the HTTP client of the requests to AAS
+func (r *Router) aasHttpClient() (*http.Client, error) {
 	rootCaCertPems, err := cos.GetDirFileContents(constants.RootCADirPath, "*.pem")
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not read root CA certificate")
+		return nil, errors.Wrap(err, "router/router:aasHttpClient() Could not read root CA certificate")
 	}
 
 	// Get the SystemCertPool, continue with an empty pool on error
 	rootCAs, err := x509.SystemCertPool()
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not initiate certificate pool")
+		return nil, errors.Wrap(err, "router/router:aasHttpClient() Could not initiate certificate pool")
 	}
 	if rootCAs == nil {
 		rootCAs = x509.NewCertPool()
 	}
 	for _, rootCACert := range rootCaCertPems {
 		if ok := rootCAs.AppendCertsFromPEM(rootCACert); !ok {
-			return err
+			return nil, err
 		}
 	}
 	httpClient := &http.Client{
@@ -99,25 +137,52 @@ func (r *Router) fnGetJwtCerts() error {
 			},
 		},
 	}
+	return httpClient, nil
+}
 
-	res, err := httpClient.Do(req)
+// Fetch the tokens revoked in AAS with the token of the CMS service user
+func (r *Router) fnGetTokenRevocations(since time.Time) (*jwtauth.TokenRevocations, error) {
+	defaultLog.Trace("router/router:fnGetTokenRevocations() Entering")
+	defer defaultLog.Trace("router/router:fnGetTokenRevocations() Leaving")
+
+	if r.fetchTokenRevocations == nil {
+		httpClient, err := r.aasHttpClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "router/router:fnGetTokenRevocations() Could not create AAS client")
+		}
+		aasClient := &aas.Client{BaseURL: r.cfg.AASApiUrl, HTTPClient: httpClient}
+		r.fetchTokenRevocations = aas.NewTokenRevocationsFetcher(aasClient, aas.NewServiceUserTokenFetcher(r.cfg.AASApiUrl,
+			httpClient, r.cfg.CMS.Username, r.cfg.CMS.Password))
+	}
+	revocations, err := r.fetchTokenRevocations(since)
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not retrieve jwt certificate")
+		return nil, errors.Wrap(err, "router/router:fnGetTokenRevocations() Error retrieving token revocations from AAS")
 	}
-	defer func() {
-		derr := res.Body.Close()
-		if derr != nil {
-			defaultLog.WithError(derr).Error("Error closing response body")
-		}
-	}()
+	return revocations, nil
+}
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not read response body")
+// Fetch the current roles of a user from AAS
+func (r *Router) fnGetUserRoles(username string) ([]ct.RoleInfo, error) {
+	defaultLog.Trace("router/router:fnGetUserRoles() Entering")
+	defer defaultLog.Trace("router/router:fnGetUserRoles() Leaving")
+
+	r.fetchUserRolesMutex.Lock()
+	if r.fetchUserRoles == nil {
+		httpClient, err := r.aasHttpClient()
+		if err != nil {
+			r.fetchUserRolesMutex.Unlock()
+			return nil, errors.Wrap(err, "router/router:fnGetUserRoles() Could not create AAS client")
+		}
+		aasClient := &aas.Client{BaseURL: r.cfg.AASApiUrl, HTTPClient: httpClient}
+		r.fetchUserRoles = aas.NewUserRolesFetcher(aasClient, aas.NewServiceUserTokenFetcher(r.cfg.AASApiUrl,
+			httpClient, r.cfg.CMS.Username, r.cfg.CMS.Password))
 	}
-	err = crypt.SavePemCertWithShortSha1FileName(body, constants.TrustedJWTSigningCertsDir)
+	fetchUserRoles := r.fetchUserRoles
+	r.fetchUserRolesMutex.Unlock()
+
+	roles, err := fetchUserRoles(username)
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+		return nil, errors.Wrap(err, "router/router:fnGetUserRoles() Error retrieving user roles from AAS")
 	}
-	return nil
+	return roles, nil
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/router"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
@@ -43,6 +44,12 @@ func (a *App) startServer() error {
 		return err
 	}
 
+	if len(c.CertProfiles) > 0 {
+		if err := config.ValidateCertProfiles(c.CertProfiles); err != nil {
+			return errors.Wrap(err, "Invalid certificate profiles in configuration")
+		}
+	}
+
 	// Initialize routes
 	routes := router.InitRoutes(c)
 	loggerMiddleware := middleware.LogWriterMiddleware{a.logWriter()}
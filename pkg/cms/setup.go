@@ -91,6 +91,7 @@ func (a *App) setup(args []string) error {
 			return errors.New("Failed to run setup task " + cmd)
 		}
 	}
+	a.setRevocationConfig()
 	err = a.Config.Save(constants.DefaultConfigFilePath)
 	if err != nil {
 		return errors.Wrap(err, "Error saving config")
@@ -103,6 +104,24 @@ func (a *App) setup(args []string) error {
 	return cos.ChownDirForUser(constants.ServiceUserName, a.configDir())
 }
 
+// setRevocationConfig sets the revocation configuration left empty by the setup tasks run, so that the revocation
+// information is embedded in the issued certificates whichever setup tasks have been run
+func (a *App) setRevocationConfig() {
+	if a.Config.Revocation.BaseUrl == "" {
+		a.Config.Revocation.BaseUrl = viper.GetString(config.RevocationBaseUrl)
+	}
+	if a.Config.Revocation.BaseUrl == "" {
+		port := a.Config.Server.Port
+		if port == 0 {
+			port = viper.GetInt(commConfig.ServerPort)
+		}
+		a.Config.Revocation.BaseUrl = tasks.DefaultRevocationBaseUrl(a.Config.TlsSanList, port)
+	}
+	if a.Config.Revocation.CrlValidityHours <= 0 {
+		a.Config.Revocation.CrlValidityHours = viper.GetInt(config.RevocationCrlValidityHours)
+	}
+}
+
 // a helper function for setting up the task runner
 func (a *App) setupTaskRunner() (*setup.Runner, error) {
 
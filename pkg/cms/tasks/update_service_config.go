@@ -8,11 +8,16 @@ package tasks
 import (
 	"fmt"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
 	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/setup"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 type UpdateServiceConfig struct {
@@ -26,17 +31,23 @@ type UpdateServiceConfig struct {
 const envHelpPrompt = "Following environment variables are required for update-service-config setup:"
 
 var envHelp = map[string]string{
-	"LOG_LEVEL":                  "Log level",
-	"LOG_MAX_LENGTH":             "Max length of log statement",
-	"LOG_ENABLE_STDOUT":          "Enable console log",
-	"AAS_BASE_URL":               "AAS Base URL",
-	"TOKEN_DURATION_MINS":        "Validity of token duration",
-	"SERVER_PORT":                "The Port on which Server Listens to",
-	"SERVER_READ_TIMEOUT":        "Request Read Timeout Duration in Seconds",
-	"SERVER_READ_HEADER_TIMEOUT": "Request Read Header Timeout Duration in Seconds",
-	"SERVER_WRITE_TIMEOUT":       "Request Write Timeout Duration in Seconds",
-	"SERVER_IDLE_TIMEOUT":        "Request Idle Timeout in Seconds",
-	"SERVER_MAX_HEADER_BYTES":    "Max Length Of Request Header in Bytes",
+	"LOG_LEVEL":                     "Log level",
+	"LOG_MAX_LENGTH":                "Max length of log statement",
+	"LOG_ENABLE_STDOUT":             "Enable console log",
+	"AAS_BASE_URL":                  "AAS Base URL",
+	"TOKEN_DURATION_MINS":           "Validity of token duration",
+	"SERVER_PORT":                   "The Port on which Server Listens to",
+	"SERVER_READ_TIMEOUT":           "Request Read Timeout Duration in Seconds",
+	"SERVER_READ_HEADER_TIMEOUT":    "Request Read Header Timeout Duration in Seconds",
+	"SERVER_WRITE_TIMEOUT":          "Request Write Timeout Duration in Seconds",
+	"SERVER_IDLE_TIMEOUT":           "Request Idle Timeout in Seconds",
+	"SERVER_MAX_HEADER_BYTES":       "Max Length Of Request Header in Bytes",
+	"REVOCATION_BASE_URL":           "CMS URL embedded in the issued certificates to locate the CRLs and the OCSP responder, defaults to the first SAN of the CMS TLS certificate",
+	"REVOCATION_CRL_VALIDITY_HOURS": "Validity of the CRLs and of the OCSP responses in hours",
+	"ACME_CERT_PROFILE":             "Certificate profile of the certificates issued to the ACME clients, defaults to TLS",
+	"ACME_EAB_KEY_VALIDITY_HOURS":   "Validity of the ACME external account keys in hours",
+	"CMS_SERVICE_USERNAME":          "CMS service username in AAS retrieving the token revocations and the roles of the ACME accounts, the revoked tokens are accepted until they expire and the ACME orders cannot be finalized when it is not set",
+	"CMS_SERVICE_PASSWORD":          "CMS service password in AAS",
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -50,6 +61,10 @@ func (uc UpdateServiceConfig) Run() error {
 	}
 
 	(*uc.AppConfig).AASApiUrl = viper.GetString(commConfig.AasBaseUrl)
+	(*uc.AppConfig).CMS = commConfig.ServiceConfig{
+		Username: viper.GetString(config.CmsServiceUsername),
+		Password: viper.GetString(config.CmsServicePassword),
+	}
 
 	(*uc.AppConfig).TokenDurationMins = viper.GetInt(config.TokenDurationMins)
 	if uc.ServerConfig.Port < 1024 ||
@@ -57,14 +72,63 @@ func (uc UpdateServiceConfig) Run() error {
 		uc.ServerConfig.Port = uc.DefaultPort
 	}
 	(*uc.AppConfig).Server = uc.ServerConfig
+
+	(*uc.AppConfig).Revocation.BaseUrl = viper.GetString(config.RevocationBaseUrl)
+	if (*uc.AppConfig).Revocation.BaseUrl == "" {
+		(*uc.AppConfig).Revocation.BaseUrl = DefaultRevocationBaseUrl((*uc.AppConfig).TlsSanList, uc.ServerConfig.Port)
+	}
+	(*uc.AppConfig).Revocation.CrlValidityHours = viper.GetInt(config.RevocationCrlValidityHours)
+	if (*uc.AppConfig).Revocation.CrlValidityHours <= 0 {
+		(*uc.AppConfig).Revocation.CrlValidityHours = constants.DefaultCrlValidityHours
+	}
+	// the default profiles are written to the configuration so that they can be customized
+	if len((*uc.AppConfig).CertProfiles) == 0 {
+		(*uc.AppConfig).CertProfiles = config.DefaultCertProfiles()
+	}
+	(*uc.AppConfig).Acme.CertProfile = viper.GetString(config.AcmeCertProfile)
+	if (*uc.AppConfig).Acme.CertProfile == "" {
+		(*uc.AppConfig).Acme.CertProfile = constants.Tls
+	}
+	(*uc.AppConfig).Acme.EabKeyValidityHours = viper.GetInt(config.AcmeEabKeyValidityHours)
+	if (*uc.AppConfig).Acme.EabKeyValidityHours <= 0 {
+		(*uc.AppConfig).Acme.EabKeyValidityHours = constants.DefaultAcmeEabKeyValidityHours
+	}
 	return nil
 }
 
+// DefaultRevocationBaseUrl returns the CMS URL embedded in the issued certificates when it is not configured, built
+// from the first SAN of the CMS TLS certificate
+func DefaultRevocationBaseUrl(tlsSanList string, port int) string {
+	san := strings.TrimSpace(strings.Split(tlsSanList, ",")[0])
+	if san == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/cms%s/", net.JoinHostPort(san, strconv.Itoa(port)), constants.ApiVersion)
+}
+
 func (uc UpdateServiceConfig) Validate() error {
 	if (*uc.AppConfig).Server.Port < 1024 ||
 		(*uc.AppConfig).Server.Port > 65535 {
 		return errors.New("Configured port is not valid")
 	}
+	if (*uc.AppConfig).Revocation.BaseUrl != "" {
+		baseUrl, err := url.Parse((*uc.AppConfig).Revocation.BaseUrl)
+		if err != nil || (baseUrl.Scheme != "http" && baseUrl.Scheme != "https") || baseUrl.Host == "" {
+			return errors.New("Configured revocation base URL is not valid")
+		}
+	}
+	if (*uc.AppConfig).Revocation.CrlValidityHours <= 0 {
+		return errors.New("Configured CRL validity is not valid")
+	}
+	if err := config.ValidateCertProfiles((*uc.AppConfig).CertProfiles); err != nil {
+		return errors.Wrap(err, "Configured certificate profiles are not valid")
+	}
+	if (*uc.AppConfig).Acme.CertProfile != "" && (*uc.AppConfig).GetCertProfile((*uc.AppConfig).Acme.CertProfile) == nil {
+		return errors.New("Configured ACME certificate profile is not valid")
+	}
+	if (*uc.AppConfig).Acme.EabKeyValidityHours < 0 {
+		return errors.New("Configured ACME external account key validity is not valid")
+	}
 	return nil
 }
 
@@ -65,3 +65,9 @@ func TestInvalidPort(t *testing.T) {
 	err := ca.Validate()
 	assert.Error(t, err)
 }
+
+func TestDefaultRevocationBaseUrl(t *testing.T) {
+	assert.Equal(t, "https://cms.example.com:8445/cms/v1/", DefaultRevocationBaseUrl(" cms.example.com, 10.1.1.1", 8445))
+	assert.Equal(t, "https://[::1]:8445/cms/v1/", DefaultRevocationBaseUrl("::1", 8445))
+	assert.Empty(t, DefaultRevocationBaseUrl("", 8445))
+}
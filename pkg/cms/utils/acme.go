@@ -0,0 +1,351 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
+	cm "github.com/intel-secl/intel-secl/v5/pkg/model/cms"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrAcmeObjectNotFound is returned when no ACME object has the identifier
+	ErrAcmeObjectNotFound = errors.New("ACME object not found")
+	// ErrAcmeEabKeyUnusable is returned when binding an account to an external account key that is expired or
+	// already bound to another account
+	ErrAcmeEabKeyUnusable = errors.New("external account key is expired or already bound")
+)
+
+// directories of the ACME objects in the ACME directory
+const (
+	acmeEabKeys        = "eab-keys"
+	acmeAccounts       = "accounts"
+	acmeOrders         = "orders"
+	acmeAuthorizations = "authorizations"
+)
+
+// acmeIdPattern matches the identifiers of the ACME objects, which are base64url encoded so that they can be used as
+// file names
+var acmeIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// acmeLock serializes the updates of the ACME objects
+var acmeLock sync.Mutex
+
+// AcmeEabKey is an external account key, binding the ACME account created with it to the CertApprover roles of the
+// AAS token used to create the key. The user of the token is recorded so that the certificates are issued only while
+// the user still holds the roles in AAS.
+type AcmeEabKey struct {
+	KeyID     string        `json:"key_id"`
+	HmacKey   []byte        `json:"hmac_key"`
+	Roles     []ct.RoleInfo `json:"roles"`
+	Username  string        `json:"username"`
+	CreatedAt time.Time     `json:"created_at"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	AccountID string        `json:"account_id,omitempty"`
+}
+
+// AcmeAccount is the record of an ACME account, identified by the thumbprint of its key
+type AcmeAccount struct {
+	ID        string          `json:"id"`
+	Status    string          `json:"status"`
+	Contact   []string        `json:"contact,omitempty"`
+	Key       json.RawMessage `json:"key"`
+	EabKeyID  string          `json:"eab_key_id"`
+	Roles     []ct.RoleInfo   `json:"roles"`
+	Username  string          `json:"username"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AcmeOrder is the record of an ACME order
+type AcmeOrder struct {
+	ID               string              `json:"id"`
+	AccountID        string              `json:"account_id"`
+	Status           string              `json:"status"`
+	Identifiers      []cm.AcmeIdentifier `json:"identifiers"`
+	AuthorizationIDs []string            `json:"authorization_ids"`
+	Expires          time.Time           `json:"expires"`
+	Error            *cm.AcmeProblem     `json:"error,omitempty"`
+	// CertificateSerial is the serial number of the certificate issued for the order, in the inventory of the
+	// issued certificates
+	CertificateSerial string `json:"certificate_serial,omitempty"`
+}
+
+// AcmeAuthorization is the record of the authorization of an identifier of an ACME order, the identifier of a wildcard
+// DNS name being its base domain name
+type AcmeAuthorization struct {
+	ID         string            `json:"id"`
+	AccountID  string            `json:"account_id"`
+	Status     string            `json:"status"`
+	Identifier cm.AcmeIdentifier `json:"identifier"`
+	Wildcard   bool              `json:"wildcard,omitempty"`
+	Expires    time.Time         `json:"expires"`
+}
+
+// NewAcmeID returns a random identifier for the ACME objects
+func NewAcmeID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", errors.Wrap(err, "utils/acme:NewAcmeID() Could not generate identifier")
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+// CreateAcmeEabKey creates and records a new external account key for the CertApprover roles of the user
+func CreateAcmeEabKey(acmeDir, username string, roles []ct.RoleInfo, validity time.Duration) (*AcmeEabKey, error) {
+	keyID, err := NewAcmeID()
+	if err != nil {
+		return nil, err
+	}
+	hmacKey := make([]byte, 32)
+	if _, err = rand.Read(hmacKey); err != nil {
+		return nil, errors.Wrap(err, "utils/acme:CreateAcmeEabKey() Could not generate HMAC key")
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	eabKey := &AcmeEabKey{
+		KeyID:     keyID,
+		HmacKey:   hmacKey,
+		Roles:     roles,
+		Username:  username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(validity),
+	}
+	if err = storeAcmeObject(acmeDir, acmeEabKeys, keyID, eabKey); err != nil {
+		return nil, err
+	}
+	return eabKey, nil
+}
+
+// RetrieveAcmeEabKey returns the external account key with the key identifier, or ErrAcmeObjectNotFound
+func RetrieveAcmeEabKey(acmeDir, keyID string) (*AcmeEabKey, error) {
+	var eabKey AcmeEabKey
+	if err := retrieveAcmeObject(acmeDir, acmeEabKeys, keyID, &eabKey); err != nil {
+		return nil, err
+	}
+	return &eabKey, nil
+}
+
+// BindAcmeEabKey binds the unexpired external account key to the account, a key can only be bound to one account
+func BindAcmeEabKey(acmeDir, keyID, accountID string) (*AcmeEabKey, error) {
+	acmeLock.Lock()
+	defer acmeLock.Unlock()
+
+	eabKey, err := RetrieveAcmeEabKey(acmeDir, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if eabKey.AccountID != "" || time.Now().After(eabKey.ExpiresAt) {
+		return nil, ErrAcmeEabKeyUnusable
+	}
+	eabKey.AccountID = accountID
+	if err = storeAcmeObject(acmeDir, acmeEabKeys, keyID, eabKey); err != nil {
+		return nil, err
+	}
+	return eabKey, nil
+}
+
+// StoreAcmeAccount records the ACME account
+func StoreAcmeAccount(acmeDir string, account *AcmeAccount) error {
+	acmeLock.Lock()
+	defer acmeLock.Unlock()
+	return storeAcmeObject(acmeDir, acmeAccounts, account.ID, account)
+}
+
+// RetrieveAcmeAccount returns the ACME account with the identifier, or ErrAcmeObjectNotFound
+func RetrieveAcmeAccount(acmeDir, id string) (*AcmeAccount, error) {
+	var account AcmeAccount
+	if err := retrieveAcmeObject(acmeDir, acmeAccounts, id, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// StoreAcmeAuthorization records the authorization of an identifier of an ACME order
+func StoreAcmeAuthorization(acmeDir string, authz *AcmeAuthorization) error {
+	acmeLock.Lock()
+	defer acmeLock.Unlock()
+	return storeAcmeObject(acmeDir, acmeAuthorizations, authz.ID, authz)
+}
+
+// RetrieveAcmeAuthorization returns the authorization with the identifier, or ErrAcmeObjectNotFound
+func RetrieveAcmeAuthorization(acmeDir, id string) (*AcmeAuthorization, error) {
+	var authz AcmeAuthorization
+	if err := retrieveAcmeObject(acmeDir, acmeAuthorizations, id, &authz); err != nil {
+		return nil, err
+	}
+	return &authz, nil
+}
+
+// StoreAcmeOrder records the ACME order
+func StoreAcmeOrder(acmeDir string, order *AcmeOrder) error {
+	acmeLock.Lock()
+	defer acmeLock.Unlock()
+	return storeAcmeObject(acmeDir, acmeOrders, order.ID, order)
+}
+
+// RetrieveAcmeOrder returns the ACME order with the identifier, or ErrAcmeObjectNotFound
+func RetrieveAcmeOrder(acmeDir, id string) (*AcmeOrder, error) {
+	var order AcmeOrder
+	if err := retrieveAcmeObject(acmeDir, acmeOrders, id, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// UpdateAcmeOrder applies the update to the ACME order and records the order when the update succeeds, the updates
+// being serialized so that the status transitions of the order are atomic
+func UpdateAcmeOrder(acmeDir, id string, update func(order *AcmeOrder) error) (*AcmeOrder, error) {
+	acmeLock.Lock()
+	defer acmeLock.Unlock()
+
+	order, err := RetrieveAcmeOrder(acmeDir, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = update(order); err != nil {
+		return order, err
+	}
+	if err = storeAcmeObject(acmeDir, acmeOrders, id, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// RetrieveAcmeOrders returns the orders of the ACME account
+func RetrieveAcmeOrders(acmeDir, accountID string) ([]AcmeOrder, error) {
+	files, err := filepath.Glob(filepath.Join(acmeDir, acmeOrders, "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "utils/acme:RetrieveAcmeOrders() Could not list orders")
+	}
+	var orders []AcmeOrder
+	for _, file := range files {
+		var order AcmeOrder
+		if err = readAcmeObject(file, &order); err != nil {
+			return nil, err
+		}
+		if order.AccountID == accountID {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func acmeObjectPath(acmeDir, kind, id string) (string, error) {
+	if !acmeIdPattern.MatchString(id) {
+		return "", ErrAcmeObjectNotFound
+	}
+	return filepath.Join(acmeDir, kind, id+".json"), nil
+}
+
+func retrieveAcmeObject(acmeDir, kind, id string, object interface{}) error {
+	path, err := acmeObjectPath(acmeDir, kind, id)
+	if err != nil {
+		return err
+	}
+	return readAcmeObject(path, object)
+}
+
+func readAcmeObject(path string, object interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrAcmeObjectNotFound
+		}
+		return errors.Wrap(err, "utils/acme:readAcmeObject() Could not read ACME object")
+	}
+	if err = json.Unmarshal(data, object); err != nil {
+		return errors.Wrapf(err, "utils/acme:readAcmeObject() Could not decode ACME object %s", path)
+	}
+	return nil
+}
+
+func storeAcmeObject(acmeDir, kind, id string, object interface{}) error {
+	path, err := acmeObjectPath(acmeDir, kind, id)
+	if err != nil {
+		return errors.Errorf("utils/acme:storeAcmeObject() Invalid identifier %s", id)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return errors.Wrap(err, "utils/acme:storeAcmeObject() Could not create ACME directory")
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return errors.Wrap(err, "utils/acme:storeAcmeObject() Could not encode ACME object")
+	}
+	// write to a temporary file first so that an object is never left partially written
+	err = ioutil.WriteFile(path+".tmp", data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "utils/acme:storeAcmeObject() Could not write ACME object")
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return errors.Wrap(err, "utils/acme:storeAcmeObject() Could not save ACME object")
+	}
+	return nil
+}
+
+// AcmeNonces are the anti-replay nonces of the ACME requests, each nonce is accepted once until it expires
+type AcmeNonces struct {
+	Validity time.Duration
+	MaxSize  int
+
+	lock   sync.Mutex
+	nonces map[string]time.Time
+}
+
+// NewAcmeNonces creates an empty set of nonces with the validity and the maximum number of unused nonces
+func NewAcmeNonces(validity time.Duration, maxSize int) *AcmeNonces {
+	return &AcmeNonces{Validity: validity, MaxSize: maxSize, nonces: map[string]time.Time{}}
+}
+
+// New returns a new nonce
+func (n *AcmeNonces) New() (string, error) {
+	nonce, err := NewAcmeID()
+	if err != nil {
+		return "", err
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	now := time.Now()
+	if len(n.nonces) >= n.MaxSize {
+		for unused, expires := range n.nonces {
+			if now.After(expires) {
+				delete(n.nonces, unused)
+			}
+		}
+	}
+	// the nonces are not persisted, the clients retry the requests rejected for a forgotten nonce with a new nonce
+	for unused := range n.nonces {
+		if len(n.nonces) < n.MaxSize {
+			break
+		}
+		delete(n.nonces, unused)
+	}
+	n.nonces[nonce] = now.Add(n.Validity)
+	return nonce, nil
+}
+
+// Use returns true when the nonce was issued, has not expired and has not been used before
+func (n *AcmeNonces) Use(nonce string) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	expires, found := n.nonces[nonce]
+	if !found {
+		return false
+	}
+	delete(n.nonces, nonce)
+	return time.Now().Before(expires)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package utils
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrCertificateNotFound is returned when the serial number is not the one of a certificate issued by CMS
+	ErrCertificateNotFound = errors.New("certificate not found")
+	// ErrCertificateRevoked is returned when revoking a certificate that is already revoked
+	ErrCertificateRevoked = errors.New("certificate is already revoked")
+)
+
+// inventoryLock serializes the updates of the issued certificate records
+var inventoryLock sync.Mutex
+
+// IssuedCertificate is the record of a certificate issued by an intermediate CA of CMS
+type IssuedCertificate struct {
+	SerialNumber     string     `json:"serial_number"`
+	IssuingCa        string     `json:"issuing_ca"`
+	Subject          string     `json:"subject"`
+	NotBefore        time.Time  `json:"not_before"`
+	NotAfter         time.Time  `json:"not_after"`
+	Certificate      []byte     `json:"certificate"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason int        `json:"revocation_reason,omitempty"`
+}
+
+// Revoked returns true when the certificate has been revoked
+func (ic IssuedCertificate) Revoked() bool {
+	return ic.RevokedAt != nil
+}
+
+// SerialNumberToString returns the hexadecimal representation of the serial number used to identify the certificates
+func SerialNumberToString(serialNumber *big.Int) string {
+	return serialNumber.Text(16)
+}
+
+// SerialNumberFromString parses the hexadecimal representation of a serial number
+func SerialNumberFromString(serialNumber string) (*big.Int, error) {
+	sn, ok := new(big.Int).SetString(serialNumber, 16)
+	if !ok || sn.Sign() < 0 {
+		return nil, errors.Errorf("utils/inventory:SerialNumberFromString() Invalid serial number %s", serialNumber)
+	}
+	return sn, nil
+}
+
+// StoreIssuedCertificate records the certificate issued by the intermediate CA in the inventory directory
+func StoreIssuedCertificate(inventoryDir string, cert *x509.Certificate, issuingCa string) error {
+	inventoryLock.Lock()
+	defer inventoryLock.Unlock()
+
+	err := os.MkdirAll(inventoryDir, 0700)
+	if err != nil {
+		return errors.Wrap(err, "utils/inventory:StoreIssuedCertificate() Could not create inventory directory")
+	}
+	return writeIssuedCertificate(inventoryDir, &IssuedCertificate{
+		SerialNumber: SerialNumberToString(cert.SerialNumber),
+		IssuingCa:    issuingCa,
+		Subject:      cert.Subject.String(),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		Certificate:  cert.Raw,
+	})
+}
+
+// RetrieveIssuedCertificate returns the record of the certificate with the serial number, or ErrCertificateNotFound
+func RetrieveIssuedCertificate(inventoryDir string, serialNumber *big.Int) (*IssuedCertificate, error) {
+	return readIssuedCertificate(issuedCertificatePath(inventoryDir, SerialNumberToString(serialNumber)))
+}
+
+// RevokeIssuedCertificate marks the certificate with the serial number as revoked for the RFC 5280 reason code
+func RevokeIssuedCertificate(inventoryDir string, serialNumber *big.Int, reason int) (*IssuedCertificate, error) {
+	inventoryLock.Lock()
+	defer inventoryLock.Unlock()
+
+	issuedCert, err := RetrieveIssuedCertificate(inventoryDir, serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	if issuedCert.Revoked() {
+		return issuedCert, ErrCertificateRevoked
+	}
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+	issuedCert.RevokedAt = &revokedAt
+	issuedCert.RevocationReason = reason
+	if err = writeIssuedCertificate(inventoryDir, issuedCert); err != nil {
+		return nil, err
+	}
+	return issuedCert, nil
+}
+
+// RetrieveRevokedCertificates returns the records of the unexpired certificates revoked for the intermediate CA
+func RetrieveRevokedCertificates(inventoryDir string, issuingCa string) ([]IssuedCertificate, error) {
+	files, err := filepath.Glob(filepath.Join(inventoryDir, "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "utils/inventory:RetrieveRevokedCertificates() Could not list issued certificates")
+	}
+	now := time.Now()
+	var revokedCerts []IssuedCertificate
+	for _, file := range files {
+		issuedCert, err := readIssuedCertificate(file)
+		if err != nil {
+			return nil, err
+		}
+		// the expired certificates are not valid anymore and are not listed in the CRL
+		if issuedCert.IssuingCa == issuingCa && issuedCert.Revoked() && issuedCert.NotAfter.After(now) {
+			revokedCerts = append(revokedCerts, *issuedCert)
+		}
+	}
+	return revokedCerts, nil
+}
+
+func issuedCertificatePath(inventoryDir, serialNumber string) string {
+	return filepath.Join(inventoryDir, serialNumber+".json")
+}
+
+func readIssuedCertificate(path string) (*IssuedCertificate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrCertificateNotFound
+		}
+		return nil, errors.Wrap(err, "utils/inventory:readIssuedCertificate() Could not read issued certificate")
+	}
+	var issuedCert IssuedCertificate
+	if err = json.Unmarshal(data, &issuedCert); err != nil {
+		return nil, errors.Wrapf(err, "utils/inventory:readIssuedCertificate() Could not decode issued certificate %s", path)
+	}
+	return &issuedCert, nil
+}
+
+func writeIssuedCertificate(inventoryDir string, issuedCert *IssuedCertificate) error {
+	data, err := json.Marshal(issuedCert)
+	if err != nil {
+		return errors.Wrap(err, "utils/inventory:writeIssuedCertificate() Could not encode issued certificate")
+	}
+	// write to a temporary file first so that a record is never left partially written
+	path := issuedCertificatePath(inventoryDir, issuedCert.SerialNumber)
+	err = ioutil.WriteFile(path+".tmp", data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "utils/inventory:writeIssuedCertificate() Could not write issued certificate")
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return errors.Wrap(err, "utils/inventory:writeIssuedCertificate() Could not save issued certificate")
+	}
+	return nil
+}
@@ -6,9 +6,14 @@
 package utils
 
 import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"os"
 	"testing"
+	"time"
 )
 
 var MockSerialNo = "serial-number"
@@ -42,3 +47,80 @@ func TestMessage(t *testing.T) {
 		t.Errorf("error")
 	}
 }
+
+func TestIssuedCertificateInventory(t *testing.T) {
+	os.MkdirAll(path, os.ModePerm)
+	defer os.RemoveAll(path)
+	inventoryDir := path + "issued-certificates/"
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(26),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		Raw:          []byte("test"),
+	}
+	assert.NoError(t, StoreIssuedCertificate(inventoryDir, cert, "TLS"))
+
+	issuedCert, err := RetrieveIssuedCertificate(inventoryDir, big.NewInt(26))
+	assert.NoError(t, err)
+	assert.Equal(t, "1a", issuedCert.SerialNumber)
+	assert.False(t, issuedCert.Revoked())
+	_, err = RetrieveIssuedCertificate(inventoryDir, big.NewInt(27))
+	assert.Equal(t, ErrCertificateNotFound, err)
+
+	_, err = RevokeIssuedCertificate(inventoryDir, big.NewInt(26), 5)
+	assert.NoError(t, err)
+	_, err = RevokeIssuedCertificate(inventoryDir, big.NewInt(26), 5)
+	assert.Equal(t, ErrCertificateRevoked, err)
+
+	revokedCerts, err := RetrieveRevokedCertificates(inventoryDir, "TLS")
+	assert.NoError(t, err)
+	assert.Len(t, revokedCerts, 1)
+	revokedCerts, err = RetrieveRevokedCertificates(inventoryDir, "Signing")
+	assert.NoError(t, err)
+	assert.Empty(t, revokedCerts)
+}
+
+func TestAcmeEabKey(t *testing.T) {
+	os.MkdirAll(path, os.ModePerm)
+	defer os.RemoveAll(path)
+	acmeDir := path + "acme/"
+	roles := []ct.RoleInfo{{Service: "CMS", Name: "CertApprover", Context: "CN=test;SAN=test.example.com;certType=TLS"}}
+	eabKey, err := CreateAcmeEabKey(acmeDir, "kbs", roles, time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, eabKey.HmacKey, 32)
+
+	boundKey, err := BindAcmeEabKey(acmeDir, eabKey.KeyID, "account")
+	assert.NoError(t, err)
+	assert.Equal(t, roles, boundKey.Roles)
+	assert.Equal(t, "kbs", boundKey.Username)
+	_, err = BindAcmeEabKey(acmeDir, eabKey.KeyID, "other-account")
+	assert.Equal(t, ErrAcmeEabKeyUnusable, err)
+
+	expiredKey, err := CreateAcmeEabKey(acmeDir, "kbs", roles, -time.Hour)
+	assert.NoError(t, err)
+	_, err = BindAcmeEabKey(acmeDir, expiredKey.KeyID, "account")
+	assert.Equal(t, ErrAcmeEabKeyUnusable, err)
+	_, err = BindAcmeEabKey(acmeDir, "../../eab-key", "account")
+	assert.Equal(t, ErrAcmeObjectNotFound, err)
+}
+
+func TestAcmeNonces(t *testing.T) {
+	nonces := NewAcmeNonces(time.Hour, 2)
+	nonce, err := nonces.New()
+	assert.NoError(t, err)
+	assert.True(t, nonces.Use(nonce))
+	assert.False(t, nonces.Use(nonce))
+	assert.False(t, nonces.Use("unknown"))
+
+	// the oldest nonces are forgotten beyond the maximum number of nonces
+	for i := 0; i < 5; i++ {
+		_, err = nonces.New()
+		assert.NoError(t, err)
+	}
+	assert.Len(t, nonces.nonces, 2)
+
+	expiredNonces := NewAcmeNonces(-time.Second, 2)
+	nonce, err = expiredNonces.New()
+	assert.NoError(t, err)
+	assert.False(t, expiredNonces.Use(nonce))
+}
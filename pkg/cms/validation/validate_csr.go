@@ -5,10 +5,10 @@
 package validation
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/x509"
-	"fmt"
 	"github.com/intel-secl/intel-secl/v5/pkg/cms/config"
-	"github.com/intel-secl/intel-secl/v5/pkg/cms/constants"
 	clog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/search"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
@@ -22,87 +22,246 @@ import (
 var log = clog.GetDefaultLogger()
 var slog = clog.GetSecurityLogger()
 
-//ValidateCertificateRequest is used to validate the Certificate Signing Request
-func ValidateCertificateRequest(conf *config.Configuration, csr *x509.CertificateRequest, certType string,
+// signatureAlgorithms are the signature algorithms of the CSRs allowed for each key type
+var signatureAlgorithms = map[string][]x509.SignatureAlgorithm{
+	"rsa":   {x509.SHA384WithRSA, x509.SHA512WithRSA},
+	"ecdsa": {x509.ECDSAWithSHA384, x509.ECDSAWithSHA512},
+}
+
+//ValidateCertificateRequest is used to validate the Certificate Signing Request against the certificate profile
+//and the CertApprover roles of the requester. The errors describe the reason why the CSR is rejected.
+func ValidateCertificateRequest(profile *config.CertProfile, csr *x509.CertificateRequest,
 	ctxMap *map[string]types.RoleInfo) error {
 	log.Trace("validation/validate_CSR:ValidateCertificateRequest() Entering")
 	defer log.Trace("validation/validate_CSR:ValidateCertificateRequest() Leaving")
 
-	// TODO: We should be able to support other signature algorithms... such as ECDSA with SHA384
-	if csr.SignatureAlgorithm != x509.SHA384WithRSA {
-		return fmt.Errorf("validation/validate_CSR:ValidateCertificateRequest() Incorrect Signature Algorithm used (should be SHA 384 with RSA): %v", csr.SignatureAlgorithm)
+	if len(csr.Subject.Names) != 1 || csr.Subject.CommonName == "" {
+		return errors.New("Only Common Name is supported in Subject")
+	}
+	err := validatePublicKey(profile, csr)
+	if err != nil {
+		return err
+	}
+
+	err = validateDNSNames(csr.DNSNames)
+	if err != nil {
+		return err
 	}
-	if len(csr.Subject.Names) != 1 {
-		return errors.New("validation/validate_CSR:ValidateCertificateRequest() Only Common Name is supported in Subject")
+	if len(csr.EmailAddresses) > 0 || len(csr.URIs) > 0 {
+		return errors.New("Only DNS names and IP addresses are supported in SAN list")
+	}
+	csrSans := RequestedDNSNames(csr)
+	for _, ip := range csr.IPAddresses {
+		csrSans = append(csrSans, ip.String())
+	}
+	log.Debugf("validation/validate_CSR:ValidateCertificateRequest() San list requested in CSR - %v ", csrSans)
+	if len(csrSans) > 0 && len(profile.SanPatterns) == 0 {
+		return errors.Errorf("Certificate profile %s does not allow SAN list", profile.Name)
+	}
+	for _, san := range csrSans {
+		if !sanMatched(san, profile.SanPatterns) {
+			return errors.Errorf("SAN %s is not allowed by certificate profile %s", san, profile.Name)
+		}
 	}
-	subjectFromCsr := strings.TrimSpace(csr.Subject.String())
+
 	// Validate CN
-	sanListsFromToken := []string{}
+	commonName := csr.Subject.CommonName
 	isCnPresentInToken := false
-
+	var sanListsFromToken [][]string
+	isProfileInToken := false
 	for k := range *ctxMap {
-		params := strings.Split(k, ";")
+		roleCtx := parseRoleContext(k)
 		// Check if Subject matches with CN
-		if len(params) > 0 && (strings.EqualFold(params[0], subjectFromCsr) || search.WildcardMatched(params[0], subjectFromCsr)) {
-			isCnPresentInToken = true
-			log.Debugf("validation/validate_CSR:ValidateCertificateRequest() Token contains required Common Name : %v ", subjectFromCsr)
-			if len(params) > 2 {
-				if strings.EqualFold(params[2], "CERTTYPE="+certType) { // Check if cert type matches
-					sanListsFromToken = append(sanListsFromToken, params[1])
-				}
+		if !strings.EqualFold(roleCtx["CN"], commonName) && !search.WildcardMatched(commonName, roleCtx["CN"]) {
+			continue
+		}
+		isCnPresentInToken = true
+		log.Debugf("validation/validate_CSR:ValidateCertificateRequest() Token contains required Common Name : %v ", commonName)
+		// Check if the cert type of the role selects the profile
+		if roleCtx["CERTTYPE"] != "" && profile.Serves(roleCtx["CERTTYPE"]) {
+			isProfileInToken = true
+			if roleCtx["SAN"] != "" {
+				sanListsFromToken = append(sanListsFromToken, strings.Split(roleCtx["SAN"], ","))
 			}
 		}
 	}
-
 	if !isCnPresentInToken {
-		return errors.New("validation/validate_CSR:ValidateCertificateRequest() No role associated with provided Common Name in CSR -" + subjectFromCsr)
-	}
-	log.Info("validation/validate_CSR:ValidateCertificateRequest() Got valid Common Name in CSR : " + subjectFromCsr)
-
-	// Validate SAN only for TLS
-	if strings.EqualFold(constants.Tls, certType) {
-		log.Debugf("validation/validate_CSR:ValidateCertificateRequest() San list(IP) requested in CSR - %v ", csr.IPAddresses)
-		log.Debugf("validation/validate_CSR:ValidateCertificateRequest() San list(DNS) requested in CSR - %v ", csr.DNSNames)
-		err := validateDNSNames(csr.DNSNames)
-		if err != nil {
-			return errors.Wrap(err, "validation/validate_CSR:ValidateCertificateRequest() Unsupported content in SAN list")
-		}
-
-		for _, sanlistFromToken := range sanListsFromToken {
-			if sanlistFromToken != "" {
-				log.Debugf("validation/validate_CSR:ValidateCertificateRequest() San list requested in token - %v ", sanlistFromToken)
-				sans := strings.Split(sanlistFromToken, "=")
-				if len(sans) > 1 {
-					tokenSanList := strings.Split(sans[1], ",")
-					isSanPresentInToken := true
-					for _, san := range tokenSanList {
-						if !ipInSlice(san, csr.IPAddresses) && !stringInSlice(san, csr.DNSNames) {
-							isSanPresentInToken = false
-							break
-						}
-					}
-					if isSanPresentInToken {
-						log.Debugf("validation/validate_CSR:ValidateCertificateRequest() San list requested in CSR is part of Token is valid")
-						return nil
-					}
-				}
+		return errors.New("No role associated with provided Common Name in CSR - " + commonName)
+	}
+	if !isProfileInToken {
+		return errors.Errorf("No role associated with Common Name %s for certificate profile %s", commonName, profile.Name)
+	}
+	log.Info("validation/validate_CSR:ValidateCertificateRequest() Got valid Common Name in CSR : " + commonName)
+
+	// Validate SAN only for the profiles allowing SANs
+	if len(profile.SanPatterns) > 0 {
+		for _, tokenSanList := range sanListsFromToken {
+			log.Debugf("validation/validate_CSR:ValidateCertificateRequest() San list requested in token - %v ", tokenSanList)
+			if sanListMatched(csr, csrSans, tokenSanList) {
+				log.Debugf("validation/validate_CSR:ValidateCertificateRequest() San list requested in CSR is part of Token is valid")
+				return nil
 			}
 		}
-		return errors.New("validation/validate_CSR:ValidateCertificateRequest() No role associated with provided SAN list in CSR")
+		return errors.New("No role associated with provided SAN list in CSR")
 	}
 	log.Info("validation/validate_CSR:ValidateCertificateRequest() Certificate Signing Request is valid")
 	return nil
 }
 
+//ValidateAcmeIdentifiers is used to check that the DNS names and IP addresses of an ACME order can be requested in
+//the SAN list of the certificates of the certificate profile: they must all be allowed by the SAN list of one
+//CertApprover role of the requester for the profile. The orders and the CSRs finalizing them are checked by this same
+//rule, so that an accepted order can be finalized.
+func ValidateAcmeIdentifiers(profile *config.CertProfile, identifiers []string, ctxMap *map[string]types.RoleInfo) error {
+	log.Trace("validation/validate_CSR:ValidateAcmeIdentifiers() Entering")
+	defer log.Trace("validation/validate_CSR:ValidateAcmeIdentifiers() Leaving")
+
+	if len(identifiers) == 0 {
+		return errors.New("No DNS name or IP address requested")
+	}
+	for _, identifier := range identifiers {
+		if !sanMatched(identifier, profile.SanPatterns) {
+			return errors.Errorf("SAN %s is not allowed by certificate profile %s", identifier, profile.Name)
+		}
+	}
+	for k := range *ctxMap {
+		roleCtx := parseRoleContext(k)
+		if roleCtx["CERTTYPE"] == "" || !profile.Serves(roleCtx["CERTTYPE"]) || roleCtx["SAN"] == "" {
+			continue
+		}
+		roleSans := strings.Split(roleCtx["SAN"], ",")
+		allowed := true
+		for _, identifier := range identifiers {
+			allowed = allowed && sanMatched(identifier, roleSans)
+		}
+		if allowed {
+			return nil
+		}
+	}
+	return errors.Errorf("No role associated with SAN list %s for certificate profile %s",
+		strings.Join(identifiers, ","), profile.Name)
+}
+
+//ValidateAcmeCertificateRequest is used to validate the CSR finalizing an ACME order. The ACME clients request the
+//certificates for the identifiers of the order, the Common Name is optional and must be one of them when present.
+func ValidateAcmeCertificateRequest(profile *config.CertProfile, csr *x509.CertificateRequest,
+	ctxMap *map[string]types.RoleInfo) error {
+	log.Trace("validation/validate_CSR:ValidateAcmeCertificateRequest() Entering")
+	defer log.Trace("validation/validate_CSR:ValidateAcmeCertificateRequest() Leaving")
+
+	if len(csr.Subject.Names) > 1 || (len(csr.Subject.Names) == 1 && csr.Subject.CommonName == "") {
+		return errors.New("Only Common Name is supported in Subject")
+	}
+	if err := validatePublicKey(profile, csr); err != nil {
+		return err
+	}
+	if err := validateDNSNames(csr.DNSNames); err != nil {
+		return err
+	}
+	if len(csr.EmailAddresses) > 0 || len(csr.URIs) > 0 {
+		return errors.New("Only DNS names and IP addresses are supported in SAN list")
+	}
+	csrSans := RequestedDNSNames(csr)
+	for _, ip := range csr.IPAddresses {
+		csrSans = append(csrSans, ip.String())
+	}
+	cnInSans := csr.Subject.CommonName == ""
+	for _, san := range csrSans {
+		cnInSans = cnInSans || strings.EqualFold(san, csr.Subject.CommonName)
+	}
+	if !cnInSans {
+		return errors.New("Common Name is not part of the SAN list in CSR - " + csr.Subject.CommonName)
+	}
+	return ValidateAcmeIdentifiers(profile, csrSans, ctxMap)
+}
+
+//RequestedDNSNames returns the DNS names of the SAN list of the CSR. The CSRs created by the setup tasks for the
+//certificates without SAN list hold an empty DNS name, which is left out.
+func RequestedDNSNames(csr *x509.CertificateRequest) []string {
+	var dnsNames []string
+	for _, dnsName := range csr.DNSNames {
+		if dnsName != "" {
+			dnsNames = append(dnsNames, dnsName)
+		}
+	}
+	return dnsNames
+}
+
+// validatePublicKey checks that the public key of the CSR and the signature algorithm of the CSR are allowed by the
+// profile
+func validatePublicKey(profile *config.CertProfile, csr *x509.CertificateRequest) error {
+	var keyType string
+	var keySize int
+	switch key := csr.PublicKey.(type) {
+	case *rsa.PublicKey:
+		keyType, keySize = "rsa", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		keyType, keySize = "ecdsa", key.Curve.Params().BitSize
+	default:
+		return errors.Errorf("Public key algorithm %v is not supported", csr.PublicKeyAlgorithm)
+	}
+
+	validSignatureAlgorithm := false
+	for _, algorithm := range signatureAlgorithms[keyType] {
+		validSignatureAlgorithm = validSignatureAlgorithm || csr.SignatureAlgorithm == algorithm
+	}
+	if !validSignatureAlgorithm {
+		return errors.Errorf("Incorrect Signature Algorithm used for %s key: %v", keyType, csr.SignatureAlgorithm)
+	}
+
+	for _, allowedKeyType := range profile.KeyTypes {
+		if allowedKeyType.Type == keyType && keySize >= allowedKeyType.MinSize {
+			return nil
+		}
+	}
+	return errors.Errorf("Public key %s of %d bits is not allowed by certificate profile %s", keyType, keySize, profile.Name)
+}
+
+// parseRoleContext returns the parameters of a CertApprover role context in the form CN=<cn>;SAN=<san list>;CERTTYPE=<type>
+func parseRoleContext(ctx string) map[string]string {
+	params := map[string]string{}
+	for _, param := range strings.Split(ctx, ";") {
+		keyValue := strings.SplitN(param, "=", 2)
+		if len(keyValue) == 2 {
+			params[strings.ToUpper(strings.TrimSpace(keyValue[0]))] = strings.TrimSpace(keyValue[1])
+		}
+	}
+	return params
+}
+
+// sanListMatched checks that the CSR has all the SANs of the role and only SANs of the role
+func sanListMatched(csr *x509.CertificateRequest, csrSans []string, tokenSanList []string) bool {
+	for _, san := range tokenSanList {
+		if !ipInSlice(san, csr.IPAddresses) && !stringInSlice(san, csr.DNSNames) {
+			return false
+		}
+	}
+	for _, san := range csrSans {
+		if !sanMatched(san, tokenSanList) {
+			return false
+		}
+	}
+	return true
+}
+
+func sanMatched(san string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.EqualFold(san, pattern) || search.WildcardMatched(strings.ToLower(san), strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateDNSNames(list []string) error {
 	log.Trace("validation/validate_CSR:validateDNSNames() Entering")
 	defer log.Trace("validation/validate_CSR:validateDNSNames() Leaving")
 	for _, v := range list {
 		if _, err := url.Parse(v); err != nil {
-			return errors.New("validation/validate_CSR:ValidateCertificateRequest() URL is not supported under SAN list in CSR")
+			return errors.New("URL is not supported under SAN list in CSR")
 		}
 		if err := validation.ValidateEmailString(v); err == nil {
-			return errors.New("validation/validate_CSR:ValidateCertificateRequest() Email is not supported under SAN list in CSR")
+			return errors.New("Email is not supported under SAN list in CSR")
 		}
 	}
 	return nil
@@ -6,6 +6,8 @@ package validation
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -53,7 +55,7 @@ func TestValidateCertificateRequest(t *testing.T) {
 	var role2 = ct.RoleInfo{"TLS", "CertApprover", "CN=AAS TLS Certificate;SAN=10.10.10.10,10.10.10.10;CERTTYPE=TLS"}
 	var roles = map[string]ct.RoleInfo{role1.Context: role1, role2.Context: role2}
 	conf, _ := config.Load()
-	err := ValidateCertificateRequest(conf, clientCSR, typeCert, &roles)
+	err := ValidateCertificateRequest(conf.GetCertProfile(typeCert), clientCSR, &roles)
 	assert.NoError(t, err)
 }
 
@@ -64,7 +66,7 @@ func TestValidateCertificateRequestInvalidRoles(t *testing.T) {
 	var role2 = ct.RoleInfo{"TLS", "CertApprover", "CN=AAS TLS;SAN=10.10.10.10,10.10.10.10;CERTTYPE=TLS"}
 	var roles = map[string]ct.RoleInfo{role1.Context: role1, role2.Context: role2}
 	conf, _ := config.Load()
-	err := ValidateCertificateRequest(conf, clientCSR, typeCert, &roles)
+	err := ValidateCertificateRequest(conf.GetCertProfile(typeCert), clientCSR, &roles)
 	assert.Error(t, err)
 }
 
@@ -75,7 +77,7 @@ func TestValidateCertificateRequestMismatchIp(t *testing.T) {
 	var role2 = ct.RoleInfo{"TLS", "CertApprover", "CN=AAS TLS Certificate;SAN=13.34.45.3,45.10.56.10;CERTTYPE=TLS"}
 	var roles = map[string]ct.RoleInfo{role1.Context: role1, role2.Context: role2}
 	conf, _ := config.Load()
-	err := ValidateCertificateRequest(conf, clientCSR, typeCert, &roles)
+	err := ValidateCertificateRequest(conf.GetCertProfile(typeCert), clientCSR, &roles)
 	assert.Error(t, err)
 }
 
@@ -84,3 +86,141 @@ func TestValidateCertificateRequestInvalidIp(t *testing.T) {
 	value := ipInSlice("8.8.8.8", ip)
 	assert.Equal(t, value, true)
 }
+
+func createCsr(key interface{}, algorithm x509.SignatureAlgorithm, cn string, dnsNames []string) *x509.CertificateRequest {
+	csrTemplate := x509.CertificateRequest{
+		SignatureAlgorithm: algorithm,
+		DNSNames:           dnsNames,
+		Subject: pkix.Name{
+			CommonName: cn,
+		},
+	}
+	csrBytes, _ := x509.CreateCertificateRequest(rand.Reader, &csrTemplate, key)
+	csr, _ := x509.ParseCertificateRequest(csrBytes)
+	return csr
+}
+
+func TestValidateCertificateRequestProfiles(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, constants.DefaultKeyAlgorithmLength)
+	smallRsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	var roles = map[string]ct.RoleInfo{}
+	for _, ctx := range []string{
+		"CN=HVS Flavor Signing Certificate;certType=Signing",
+		"CN=TA TLS Client Certificate;certType=TLS-Client",
+		"CN=KBS TLS Certificate;SAN=kbs.example.com,10.1.1.1;certType=TLS",
+		"CN=WLS TLS Certificate;SAN=*.example.com;certType=TLS",
+	} {
+		roles[ctx] = ct.RoleInfo{Service: "CMS", Name: "CertApprover", Context: ctx}
+	}
+	conf := &config.Configuration{CertProfiles: config.DefaultCertProfiles()}
+	ecProfile := *conf.GetCertProfile("TLS-Client")
+	ecProfile.KeyTypes = append(ecProfile.KeyTypes, config.CertKeyType{Type: "ecdsa", MinSize: 384})
+
+	tests := []struct {
+		name     string
+		profile  *config.CertProfile
+		csr      *x509.CertificateRequest
+		errorMsg string
+	}{
+		{"alias of the profile selected by the role", conf.GetCertProfile("Flavor-Signing"),
+			createCsr(rsaKey, x509.SHA384WithRSA, "HVS Flavor Signing Certificate", nil), ""},
+		{"SAN list in signing certificate", conf.GetCertProfile("Signing"),
+			createCsr(rsaKey, x509.SHA384WithRSA, "HVS Flavor Signing Certificate", []string{"hvs.example.com"}),
+			"Certificate profile Signing does not allow SAN list"},
+		{"empty DNS name in signing certificate", conf.GetCertProfile("Signing"),
+			createCsr(rsaKey, x509.SHA384WithRSA, "HVS Flavor Signing Certificate", []string{""}), ""},
+		{"role of other profile", conf.GetCertProfile("TLS"),
+			createCsr(rsaKey, x509.SHA384WithRSA, "TA TLS Client Certificate", nil),
+			"No role associated with Common Name TA TLS Client Certificate for certificate profile TLS"},
+		{"key smaller than allowed by profile", conf.GetCertProfile("TLS-Client"),
+			createCsr(smallRsaKey, x509.SHA384WithRSA, "TA TLS Client Certificate", nil),
+			"Public key rsa of 2048 bits is not allowed by certificate profile TLS-Client"},
+		{"key type not allowed by profile", conf.GetCertProfile("TLS-Client"),
+			createCsr(ecKey, x509.ECDSAWithSHA384, "TA TLS Client Certificate", nil),
+			"Public key ecdsa of 384 bits is not allowed by certificate profile TLS-Client"},
+		{"key type allowed by profile", &ecProfile,
+			createCsr(ecKey, x509.ECDSAWithSHA384, "TA TLS Client Certificate", nil), ""},
+		{"SAN list of the role", conf.GetCertProfile("TLS"),
+			createCsr(rsaKey, x509.SHA384WithRSA, "KBS TLS Certificate", []string{"kbs.example.com", "10.1.1.1"}), ""},
+		{"SAN not in the role", conf.GetCertProfile("TLS"),
+			createCsr(rsaKey, x509.SHA384WithRSA, "KBS TLS Certificate", []string{"kbs.example.com", "10.1.1.1", "evil.com"}),
+			"No role associated with provided SAN list in CSR"},
+		{"SAN matched by wildcard of the role", conf.GetCertProfile("TLS"),
+			createCsr(rsaKey, x509.SHA384WithRSA, "WLS TLS Certificate", []string{"wls.example.com"}), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCertificateRequest(tt.profile, tt.csr, &roles)
+			if tt.errorMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestValidateAcmeIdentifiers(t *testing.T) {
+	var roles = map[string]ct.RoleInfo{}
+	for _, ctx := range []string{
+		"CN=TA TLS Client Certificate;SAN=ta.example.com;certType=TLS-Client",
+		"CN=KBS TLS Certificate;SAN=kbs.example.com,10.1.1.1;certType=TLS",
+		"CN=WLS TLS Certificate;SAN=*.example.com;certType=TLS",
+	} {
+		roles[ctx] = ct.RoleInfo{Service: "CMS", Name: "CertApprover", Context: ctx}
+	}
+	conf := &config.Configuration{CertProfiles: config.DefaultCertProfiles()}
+	tlsProfile := conf.GetCertProfile("TLS")
+	restrictedProfile := *tlsProfile
+	restrictedProfile.SanPatterns = []string{"*.example.com"}
+
+	assert.NoError(t, ValidateAcmeIdentifiers(tlsProfile, []string{"kbs.example.com", "10.1.1.1"}, &roles))
+	assert.NoError(t, ValidateAcmeIdentifiers(tlsProfile, []string{"wls.example.com", "*.example.com"}, &roles))
+	// the identifiers must be allowed by the same role
+	assert.EqualError(t, ValidateAcmeIdentifiers(tlsProfile, []string{"wls.example.com", "10.1.1.1"}, &roles),
+		"No role associated with SAN list wls.example.com,10.1.1.1 for certificate profile TLS")
+	assert.EqualError(t, ValidateAcmeIdentifiers(tlsProfile, []string{"10.1.1.2"}, &roles),
+		"No role associated with SAN list 10.1.1.2 for certificate profile TLS")
+	assert.EqualError(t, ValidateAcmeIdentifiers(conf.GetCertProfile("TLS-Client"), []string{"ta.example.com"}, &roles),
+		"SAN ta.example.com is not allowed by certificate profile TLS-Client")
+	assert.EqualError(t, ValidateAcmeIdentifiers(&restrictedProfile, []string{"10.1.1.1"}, &roles),
+		"SAN 10.1.1.1 is not allowed by certificate profile TLS")
+}
+
+func TestValidateAcmeCertificateRequest(t *testing.T) {
+	var roles = map[string]ct.RoleInfo{}
+	ctx := "CN=KBS TLS Certificate;SAN=kbs.example.com;certType=TLS"
+	roles[ctx] = ct.RoleInfo{Service: "CMS", Name: "CertApprover", Context: ctx}
+	conf := &config.Configuration{CertProfiles: config.DefaultCertProfiles()}
+	tlsProfile := conf.GetCertProfile("TLS")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, constants.DefaultKeyAlgorithmLength)
+
+	// the Common Name of the CSRs of the ACME clients is optional
+	assert.NoError(t, ValidateAcmeCertificateRequest(tlsProfile,
+		createCsr(rsaKey, x509.SHA384WithRSA, "", []string{"kbs.example.com"}), &roles))
+	assert.NoError(t, ValidateAcmeCertificateRequest(tlsProfile,
+		createCsr(rsaKey, x509.SHA384WithRSA, "kbs.example.com", []string{"kbs.example.com"}), &roles))
+	assert.EqualError(t, ValidateAcmeCertificateRequest(tlsProfile,
+		createCsr(rsaKey, x509.SHA384WithRSA, "KBS TLS Certificate", []string{"kbs.example.com"}), &roles),
+		"Common Name is not part of the SAN list in CSR - KBS TLS Certificate")
+	assert.EqualError(t, ValidateAcmeCertificateRequest(tlsProfile,
+		createCsr(rsaKey, x509.SHA384WithRSA, "", []string{"hvs.example.com"}), &roles),
+		"No role associated with SAN list hvs.example.com for certificate profile TLS")
+}
+
+func TestValidateCertProfiles(t *testing.T) {
+	assert.NoError(t, config.ValidateCertProfiles(config.DefaultCertProfiles()))
+
+	profiles := config.DefaultCertProfiles()
+	profiles[0].Aliases = []string{"jwt-signing"}
+	assert.Error(t, config.ValidateCertProfiles(profiles))
+
+	profiles = config.DefaultCertProfiles()
+	profiles[0].IssuingCa = constants.Root
+	assert.Error(t, config.ValidateCertProfiles(profiles))
+
+	profiles = config.DefaultCertProfiles()
+	profiles[0].ExtKeyUsages = []string{"anyUsage"}
+	assert.Error(t, config.ValidateCertProfiles(profiles))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/events"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/hrrs"
 	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
 	"github.com/pkg/errors"
@@ -31,6 +32,8 @@ const (
 	FlavorSigningKeyFile    = "flavor-signing.key-file"
 	FlavorSigningCommonName = "flavor-signing.common-name"
 
+	FlavorImportTrustedCertsDir = "flavor-import.trusted-signing-certs-dir"
+
 	PrivacyCaCertFile      = "privacy-ca.cert-file"
 	PrivacyCaKeyFile       = "privacy-ca.key-file"
 	PrivacyCaCommonName    = "privacy-ca.common-name"
@@ -53,6 +56,11 @@ const (
 	AuditLogNumRotated  = "audit-log.number-rotated"
 	AuditLogBufferSize  = "audit-log.buffer-size"
 
+	EventsBufferSize    = "events.buffer-size"
+	EventsMaxRetries    = "events.max-retries"
+	EventsRetryInterval = "events.retry-interval"
+	EventsTimeout       = "events.timeout"
+
 	AikCertValidity   = "aik-certificate-validity-years"
 	DataEncryptionKey = "data-encryption-key"
 	NatsServers       = "nats.servers"
@@ -73,6 +81,7 @@ type Configuration struct {
 	TLS           commConfig.TLSCertConfig     `yaml:"tls"`
 	SAML          SAMLConfig                   `yaml:"saml"`
 	FlavorSigning commConfig.SigningCertConfig `yaml:"flavor-signing" mapstructure:"flavor-signing"`
+	FlavorImport  FlavorImportConfig           `yaml:"flavor-import" mapstructure:"flavor-import"`
 
 	PrivacyCA     commConfig.SelfSignedCertConfig `yaml:"privacy-ca" mapstructure:"privacy-ca"`
 	EndorsementCA commConfig.SelfSignedCertConfig `yaml:"endorsement-ca" mapstructure:"endorsement-ca"`
@@ -91,6 +100,7 @@ type Configuration struct {
 	FVS                      FVSConfig               `yaml:"fvs"`
 	VCSS                     VCSSConfig              `yaml:"vcss"`
 	NATS                     NatsConfig              `yaml:"nats"`
+	Events                   events.EventsConfig     `yaml:"events"`
 	EnableEkCertRevokeChecks bool                    `yaml:"enable-ekcert-revoke-check" mapstructure:"enable-ekcert-revoke-check"`
 }
 
@@ -113,6 +123,11 @@ type AuditLogConfig struct {
 	BufferSize  int `yaml:"buffer-size" mapstructure:"buffer-size"`
 }
 
+type FlavorImportConfig struct {
+	// TrustedSigningCertsDir holds the flavor signing certificates of the HVS instances flavors are imported from
+	TrustedSigningCertsDir string `yaml:"trusted-signing-certs-dir" mapstructure:"trusted-signing-certs-dir"`
+}
+
 type VCSSConfig struct {
 	// RefreshPeriod determines how frequently the VCSS checks the vCenter cluster for updated hosts
 	RefreshPeriod time.Duration `yaml:"refresh-period" mapstructure:"refresh-period"`
@@ -61,6 +61,9 @@ const (
 	FlavorSigningCertFile = TrustedCaCertsDir + "flavor-signing.pem"
 	FlavorSigningKeyFile  = TrustedKeysDir + "flavor-signing.key"
 
+	// flavor signing certificates of the HVS instances flavors can be imported from
+	TrustedFlavorSigningCertsDir = TrustedCaCertsDir + "flavor-import/"
+
 	// privacy ca key and cert
 	PrivacyCACertFile = TrustedCaCertsDir + "privacy-ca/privacy-ca-cert.pem"
 	PrivacyCAKeyFile  = TrustedKeysDir + "privacy-ca.key"
@@ -151,6 +154,8 @@ const (
 // jwt constants
 const (
 	JWTCertsCacheTime = "1m"
+	// TokenRevocationsRefreshTime is how often the token revocation list is retrieved from AAS
+	TokenRevocationsRefreshTime = "1m"
 )
 
 // FVS constants
@@ -193,6 +198,7 @@ const (
 	FvsSkipFlavorSignatureVerification = "fvs-skip-flavor-signature-verification"
 	FvsHostTrustCacheThreshold         = "fvs-host-trust-cache-threshold"
 	HrrsRefreshPeriod                  = "hrrs-refresh-period"
+	HrrsSchedulePollPeriod             = "hrrs-schedule-poll-period"
 	VcssRefreshPeriod                  = "vcss-refresh-period"
 )
 
@@ -39,6 +39,9 @@ const (
 	FlavorRetrieve = "flavors:retrieve"
 	FlavorSearch   = "flavors:search"
 	FlavorDelete   = "flavors:delete"
+	FlavorEvaluate = "flavors:evaluate"
+	FlavorExport   = "flavors:export"
+	FlavorImport   = "flavors:import"
 
 	TagFlavorCreate        = "tag_flavors:create"
 	HostUniqueFlavorCreate = "host_unique_flavors:create"
@@ -61,6 +64,13 @@ const (
 	ReportRetrieve = "reports:retrieve"
 	ReportSearch   = "reports:search"
 
+	AuditLogSearch = "audit_logs:search"
+
+	SubscriptionCreate   = "subscriptions:create"
+	SubscriptionRetrieve = "subscriptions:retrieve"
+	SubscriptionSearch   = "subscriptions:search"
+	SubscriptionDelete   = "subscriptions:delete"
+
 	// AssetTagAPI
 	TagCertificateCreate = "tag_certificates:create"
 	TagCertificateDelete = "tag_certificates:delete"
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// minRefreshPeriodSeconds limits how often the hosts of a flavorgroup can be attested by schedule
+	minRefreshPeriodSeconds = 60
+	// minSamlValiditySeconds limits how short lived the SAML reports of a flavorgroup can be
+	minSamlValiditySeconds = 60
+)
+
+// AttestationPolicyController manages the attestation policies of flavorgroups, which define how often their hosts
+// are attested and the validity of their SAML reports
+type AttestationPolicyController struct {
+	FGStore domain.FlavorGroupStore
+	APStore domain.AttestationPolicyStore
+}
+
+func NewAttestationPolicyController(fgs domain.FlavorGroupStore, aps domain.AttestationPolicyStore) *AttestationPolicyController {
+	return &AttestationPolicyController{
+		FGStore: fgs,
+		APStore: aps,
+	}
+}
+
+// Update creates the attestation policy of the flavorgroup or replaces the existing one
+func (controller AttestationPolicyController) Update(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/attestation_policy_controller:Update() Entering")
+	defer defaultLog.Trace("controllers/attestation_policy_controller:Update() Leaving")
+
+	if r.Header.Get("Content-Type") != consts.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/attestation_policy_controller:Update() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var policy hvs.AttestationPolicy
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil {
+		secLog.WithError(err).Errorf("controllers/attestation_policy_controller:Update() %s :  Failed to decode request body as AttestationPolicy", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	if policy.FlavorgroupId != uuid.Nil && policy.FlavorgroupId != fgID {
+		secLog.Errorf("controllers/attestation_policy_controller:Update() %s : Flavorgroup ID in request body does not match the URL", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "flavorgroup_id does not match the flavorgroup in the URL"}
+	}
+	policy.FlavorgroupId = fgID
+
+	if err := validateAttestationPolicy(policy); err != nil {
+		secLog.WithError(err).Errorf("controllers/attestation_policy_controller:Update() %s : Invalid attestation policy", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	if status, err := controller.checkFlavorgroupExists(fgID); err != nil {
+		return nil, status, err
+	}
+
+	updatedPolicy, err := controller.APStore.Update(&policy)
+	if err != nil {
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Error("controllers/attestation_policy_controller:Update() Attestation policy save failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while saving attestation policy"}
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: Attestation policy updated by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return updatedPolicy, http.StatusOK, nil
+}
+
+// Retrieve returns the attestation policy of the flavorgroup
+func (controller AttestationPolicyController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/attestation_policy_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/attestation_policy_controller:Retrieve() Leaving")
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	policy, err := controller.APStore.Retrieve(fgID)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/attestation_policy_controller:Retrieve() %s : Attestation policy not found", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Attestation policy does not exist"}
+		}
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Error("controllers/attestation_policy_controller:Retrieve() Attestation policy retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve attestation policy"}
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: Attestation policy retrieved by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return policy, http.StatusOK, nil
+}
+
+// Delete removes the attestation policy of the flavorgroup, whose hosts are then only attested when their reports
+// expire
+func (controller AttestationPolicyController) Delete(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/attestation_policy_controller:Delete() Entering")
+	defer defaultLog.Trace("controllers/attestation_policy_controller:Delete() Leaving")
+
+	fgID := uuid.MustParse(mux.Vars(r)["fgID"])
+	if _, err := controller.APStore.Retrieve(fgID); err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/attestation_policy_controller:Delete() %s : Attestation policy not found", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Attestation policy does not exist"}
+		}
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Error("controllers/attestation_policy_controller:Delete() Attestation policy retrieve failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete attestation policy"}
+	}
+
+	if err := controller.APStore.Delete(fgID); err != nil {
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Error("controllers/attestation_policy_controller:Delete() Attestation policy delete failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete attestation policy"}
+	}
+
+	secLog.WithField("flavorGroup", fgID).Infof("%s: Attestation policy deleted by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+func (controller AttestationPolicyController) checkFlavorgroupExists(fgID uuid.UUID) (int, error) {
+	if _, err := controller.FGStore.Retrieve(fgID); err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("flavorGroup", fgID).Errorf("controllers/attestation_policy_controller:checkFlavorgroupExists() %s : FlavorGroup does not exist", commLogMsg.InvalidInputBadParam)
+			return http.StatusNotFound, &commErr.ResourceError{Message: "FlavorGroup does not exist"}
+		}
+		defaultLog.WithError(err).WithField("flavorGroup", fgID).Error("controllers/attestation_policy_controller:checkFlavorgroupExists() Error retrieving FlavorGroup")
+		return http.StatusInternalServerError, &commErr.ResourceError{Message: "Error while saving attestation policy"}
+	}
+	return http.StatusOK, nil
+}
+
+func validateAttestationPolicy(policy hvs.AttestationPolicy) error {
+	defaultLog.Trace("controllers/attestation_policy_controller:validateAttestationPolicy() Entering")
+	defer defaultLog.Trace("controllers/attestation_policy_controller:validateAttestationPolicy() Leaving")
+
+	if policy.RefreshPeriodSeconds == 0 && policy.SamlValiditySeconds == 0 {
+		return errors.New("Either refresh_period_seconds or saml_validity_seconds must be specified")
+	}
+	if policy.RefreshPeriodSeconds != 0 && policy.RefreshPeriodSeconds < minRefreshPeriodSeconds {
+		return errors.Errorf("refresh_period_seconds must be at least %d", minRefreshPeriodSeconds)
+	}
+	if policy.JitterSeconds < 0 || (policy.JitterSeconds > 0 && policy.JitterSeconds >= policy.RefreshPeriodSeconds) {
+		return errors.New("jitter_seconds must be less than refresh_period_seconds")
+	}
+	if policy.SamlValiditySeconds != 0 && policy.SamlValiditySeconds < minSamlValiditySeconds {
+		return errors.Errorf("saml_validity_seconds must be at least %d", minSamlValiditySeconds)
+	}
+	return nil
+}
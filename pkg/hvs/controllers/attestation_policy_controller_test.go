@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v5/pkg/hvs/router"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttestationPolicyController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var attestationPolicyStore *mocks.MockAttestationPolicyStore
+
+	const policyPath = "/flavorgroups/ee37c360-7eae-4250-a677-6ee12adce8e2/attestation-policy"
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		attestationPolicyStore = mocks.NewMockAttestationPolicyStore()
+		attestationPolicyController := controllers.NewAttestationPolicyController(mocks.NewFakeFlavorgroupStore(), attestationPolicyStore)
+		policyExpr := "/flavorgroups/{fgID}/attestation-policy"
+		router.Handle(policyExpr, hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(attestationPolicyController.Update))).Methods(http.MethodPut)
+		router.Handle(policyExpr, hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(attestationPolicyController.Retrieve))).Methods(http.MethodGet)
+		router.Handle(policyExpr, hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(attestationPolicyController.Delete))).Methods(http.MethodDelete)
+	})
+
+	request := func(method, path, body string) {
+		req, err := http.NewRequest(method, path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	// Specs for HTTP Put to "/flavorgroups/{fgID}/attestation-policy"
+	Describe("Update attestation policy", func() {
+		Context("Provide a valid attestation policy", func() {
+			It("Should save the policy and return 200", func() {
+				request(http.MethodPut, policyPath, `{"refresh_period_seconds": 300, "saml_validity_seconds": 600}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var policy hvs.AttestationPolicy
+				Expect(json.Unmarshal(w.Body.Bytes(), &policy)).NotTo(HaveOccurred())
+				Expect(policy.FlavorgroupId.String()).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+				Expect(policy.RefreshPeriodSeconds).To(Equal(300))
+
+				request(http.MethodPut, policyPath, `{"saml_validity_seconds": 120}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				stored, err := attestationPolicyStore.Retrieve(policy.FlavorgroupId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.RefreshPeriodSeconds).To(Equal(0))
+				Expect(stored.SamlValiditySeconds).To(Equal(120))
+			})
+		})
+
+		Context("Provide a refresh period that is too short", func() {
+			It("Should return 400", func() {
+				request(http.MethodPut, policyPath, `{"refresh_period_seconds": 10}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a jitter longer than the refresh period", func() {
+			It("Should return 400", func() {
+				request(http.MethodPut, policyPath, `{"refresh_period_seconds": 300, "jitter_seconds": 300}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide an empty attestation policy", func() {
+			It("Should return 400", func() {
+				request(http.MethodPut, policyPath, `{}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a flavorgroup that does not exist", func() {
+			It("Should return 404", func() {
+				request(http.MethodPut, "/flavorgroups/73755fda-c910-46be-821f-e8ddeab189e9/attestation-policy", `{"refresh_period_seconds": 300}`)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("Provide an invalid Content-Type", func() {
+			It("Should return 415", func() {
+				req, err := http.NewRequest(http.MethodPut, policyPath, strings.NewReader(`{"refresh_period_seconds": 300}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeXml)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+			})
+		})
+	})
+
+	// Specs for HTTP Get and Delete to "/flavorgroups/{fgID}/attestation-policy"
+	Describe("Retrieve and delete attestation policy", func() {
+		Context("When the flavorgroup has an attestation policy", func() {
+			It("Should return it and delete it", func() {
+				request(http.MethodPut, policyPath, `{"refresh_period_seconds": 86400}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				request(http.MethodGet, policyPath, "")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				request(http.MethodDelete, policyPath, "")
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				request(http.MethodGet, policyPath, "")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("When the flavorgroup has no attestation policy", func() {
+			It("Should return 404 on delete", func() {
+				request(http.MethodDelete, policyPath, "")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
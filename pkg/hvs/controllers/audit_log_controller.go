@@ -0,0 +1,171 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/utils"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// AuditLogController contains logic for handling AuditLog API requests
+type AuditLogController struct {
+	Store domain.AuditLogEntryStore
+}
+
+func NewAuditLogController(store domain.AuditLogEntryStore) *AuditLogController {
+	return &AuditLogController{Store: store}
+}
+
+var auditLogSearchParams = map[string]bool{"entityId": true, "entityType": true, "action": true, "fromDate": true,
+	"toDate": true, "numberOfDays": true, "limit": true, "afterId": true}
+
+// Search returns a collection of audit log entries based on AuditLogFilterCriteria
+func (controller AuditLogController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/audit_log_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/audit_log_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), auditLogSearchParams); err != nil {
+		secLog.Errorf("controllers/audit_log_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter, err := getAuditLogFilterCriteria(r.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Warnf("controllers/audit_log_controller:Search() %s ", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid filter criteria"}
+	}
+
+	entries, err := controller.Store.Search(filter)
+	if err != nil {
+		defaultLog.WithError(err).Warnf("controllers/audit_log_controller:Search() Audit log search operation failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Audit log search operation failed"}
+	}
+
+	auditLogCollection := hvs.AuditLogEntryCollection{AuditLogEntries: []hvs.AuditLogEntry{}}
+	for _, entry := range entries {
+		auditLogCollection.AuditLogEntries = append(auditLogCollection.AuditLogEntries, toAuditLogEntry(entry))
+	}
+
+	if len(entries) > 0 {
+		lastRowId := entries[len(entries)-1].RowId
+		auditLogCollection.Next, auditLogCollection.Previous = GetNextAndPrevValues(filter.Limit, filter.AfterId, lastRowId, len(entries))
+	}
+
+	secLog.Infof("%s: Return Audit Log Search query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return auditLogCollection, http.StatusOK, nil
+}
+
+func toAuditLogEntry(entry models.AuditLogEntry) hvs.AuditLogEntry {
+	columns := make([]hvs.AuditColumnData, 0, len(entry.Data.Columns))
+	for _, c := range entry.Data.Columns {
+		columns = append(columns, hvs.AuditColumnData{
+			Name:      c.Name,
+			Value:     c.Value,
+			IsUpdated: c.IsUpdated,
+		})
+	}
+	return hvs.AuditLogEntry{
+		RowId:      entry.RowId,
+		ID:         entry.ID,
+		EntityID:   entry.EntityID,
+		EntityType: entry.EntityType,
+		Action:     entry.Action,
+		Created:    entry.CreatedAt,
+		Data:       columns,
+	}
+}
+
+// getAuditLogFilterCriteria checks for set filter params in the Search request and returns a valid AuditLogFilterCriteria
+func getAuditLogFilterCriteria(params url.Values) (*models.AuditLogFilterCriteria, error) {
+	defaultLog.Trace("controllers/audit_log_controller:getAuditLogFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/audit_log_controller:getAuditLogFilterCriteria() Leaving")
+
+	afc := models.AuditLogFilterCriteria{}
+
+	// Entity ID
+	if strings.TrimSpace(params.Get("entityId")) != "" {
+		entityId, err := uuid.Parse(strings.TrimSpace(params.Get("entityId")))
+		if err != nil {
+			return nil, errors.New("Invalid UUID format of the Entity Identifier specified")
+		}
+		afc.EntityID = entityId
+	}
+
+	// Entity Type
+	entityType := strings.TrimSpace(params.Get("entityType"))
+	if entityType != "" {
+		if err := validation.ValidateNameString(entityType); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for entityType must be specified")
+		}
+		afc.EntityType = entityType
+	}
+
+	// Action
+	action := strings.TrimSpace(params.Get("action"))
+	if action != "" {
+		if err := validation.ValidateNameString(action); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for action must be specified")
+		}
+		afc.Action = action
+	}
+
+	// fromDate
+	fromDate := strings.TrimSpace(params.Get("fromDate"))
+	if fromDate != "" {
+		pTime, err := utils.ParseDateQueryParam(fromDate)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid fromDate specified")
+		}
+		afc.FromDate = pTime
+	}
+
+	// toDate
+	toDate := strings.TrimSpace(params.Get("toDate"))
+	if toDate != "" {
+		pTime, err := utils.ParseDateQueryParam(toDate)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid toDate specified")
+		}
+		afc.ToDate = pTime
+	}
+
+	// numberOfDays - overrides fromDate and toDate when specified
+	numberOfDays := strings.TrimSpace(params.Get("numberOfDays"))
+	if numberOfDays != "" {
+		numDays, err := strconv.Atoi(numberOfDays)
+		if err != nil || numDays < 1 || numDays > constants.MaxNumDaysSearchLimit {
+			return nil, errors.New("numberOfDays must be an integer between 1 and " + strconv.Itoa(constants.MaxNumDaysSearchLimit))
+		}
+		afc.ToDate = time.Now().UTC()
+		afc.FromDate = afc.ToDate.AddDate(0, 0, -numDays).UTC()
+	}
+
+	if !afc.FromDate.IsZero() && !afc.ToDate.IsZero() && afc.FromDate.After(afc.ToDate) {
+		return nil, errors.New("fromDate must not be after toDate")
+	}
+
+	limit, afterId, err := validation.ValidatePaginationValues(params.Get("limit"), params.Get("afterId"))
+	if err != nil {
+		return nil, err
+	}
+	afc.Limit = limit
+	afc.AfterId = afterId
+
+	return &afc, nil
+}
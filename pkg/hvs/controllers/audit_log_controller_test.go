@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v5/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuditLogController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var auditLogEntryStore *mocks2.MockAuditLogEntryStore
+	var auditLogController *controllers.AuditLogController
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		auditLogEntryStore = mocks2.NewMockAuditLogEntryStore()
+		auditLogController = controllers.NewAuditLogController(auditLogEntryStore)
+		router.Handle("/audit-logs", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(auditLogController.Search))).Methods(http.MethodGet)
+	})
+
+	search := func(query string) *hvs.AuditLogEntryCollection {
+		req, err := http.NewRequest(http.MethodGet, "/audit-logs"+query, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return nil
+		}
+		var collection *hvs.AuditLogEntryCollection
+		Expect(json.Unmarshal(w.Body.Bytes(), &collection)).To(Succeed())
+		return collection
+	}
+
+	// Specs for HTTP Get to "/audit-logs"
+	Describe("Search AuditLogs", func() {
+		Context("When no filter arguments are passed", func() {
+			It("All audit log entries are returned", func() {
+				collection := search("")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(collection.AuditLogEntries).To(HaveLen(3))
+				Expect(collection.Next).To(BeEmpty())
+			})
+		})
+
+		Context("When filtered by entityType and entityId", func() {
+			It("Should get the entries of that entity", func() {
+				collection := search("?entityType=report&entityId=6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(collection.AuditLogEntries).To(HaveLen(2))
+				for _, e := range collection.AuditLogEntries {
+					Expect(e.EntityType).To(Equal("report"))
+				}
+			})
+		})
+
+		Context("When filtered by action", func() {
+			It("Should get only the matching entries", func() {
+				collection := search("?action=delete")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(collection.AuditLogEntries).To(HaveLen(1))
+				Expect(collection.AuditLogEntries[0].Action).To(Equal("delete"))
+			})
+		})
+
+		Context("When filtered by numberOfDays", func() {
+			It("Should get only the recent entries", func() {
+				collection := search("?numberOfDays=2")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(collection.AuditLogEntries).To(HaveLen(2))
+			})
+		})
+
+		Context("When paging with limit and afterId", func() {
+			It("Should return the page with next and prev links", func() {
+				collection := search("?limit=1&afterId=1")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(collection.AuditLogEntries).To(HaveLen(1))
+				Expect(collection.Next).To(Equal("limit=1&afterId=2"))
+				Expect(collection.Previous).To(Equal("limit=1&afterId=0"))
+			})
+		})
+
+		Context("When an invalid entityId is passed", func() {
+			It("Should get a 400 error", func() {
+				search("?entityId=abc")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When fromDate is after toDate", func() {
+			It("Should get a 400 error", func() {
+				search("?fromDate=2021-01-02&toDate=2021-01-01")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When an unknown query parameter is passed", func() {
+			It("Should get a 400 error", func() {
+				search("?badParam=true")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
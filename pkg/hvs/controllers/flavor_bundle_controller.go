@@ -0,0 +1,439 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package controllers
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	dm "github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
+	fu "github.com/intel-secl/intel-secl/v5/pkg/lib/flavor/util"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// FlavorBundleController exports flavors with their flavorgroup and flavor template associations and imports
+// them in another HVS instance
+type FlavorBundleController struct {
+	FlavorController
+	// TrustedSigningCertsDir holds the flavor signing certificates of the HVS instances flavors are imported from
+	TrustedSigningCertsDir string
+}
+
+func NewFlavorBundleController(fc FlavorController, trustedSigningCertsDir string) *FlavorBundleController {
+	return &FlavorBundleController{
+		FlavorController:       fc,
+		TrustedSigningCertsDir: trustedSigningCertsDir,
+	}
+}
+
+// Export returns the flavors matching the same filter criteria as the flavor search, along with the flavorgroups
+// they belong to and the flavor templates of these flavorgroups
+func (controller *FlavorBundleController) Export(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_bundle_controller:Export() Entering")
+	defer defaultLog.Trace("controllers/flavor_bundle_controller:Export() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), flavorSearchParams); err != nil {
+		secLog.Errorf("controllers/flavor_bundle_controller:Export() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filterCriteria, err := validateFlavorFilterCriteria(r.URL.Query().Get("key"), r.URL.Query().Get("value"),
+		r.URL.Query().Get("flavorgroupId"), r.URL.Query()["id"], r.URL.Query()["flavorParts"],
+		r.URL.Query().Get("limit"), r.URL.Query().Get("afterId"))
+	if err != nil {
+		secLog.Errorf("controllers/flavor_bundle_controller:Export() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	signedFlavors, err := controller.FStore.Search(&dm.FlavorVerificationFC{
+		FlavorFC: *filterCriteria,
+	})
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_bundle_controller:Export() Flavor search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Unable to search Flavors"}
+	}
+
+	bundle, err := controller.createFlavorBundle(signedFlavors)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_bundle_controller:Export() Failed to create flavor bundle")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to export flavors"}
+	}
+
+	secLog.Infof("%s: %d flavors exported to: %s", commLogMsg.AuthorizedAccess, len(bundle.SignedFlavors), r.RemoteAddr)
+	return bundle, http.StatusOK, nil
+}
+
+func (controller *FlavorBundleController) createFlavorBundle(signedFlavors []hvs.SignedFlavor) (*hvs.FlavorBundle, error) {
+	defaultLog.Trace("controllers/flavor_bundle_controller:createFlavorBundle() Entering")
+	defer defaultLog.Trace("controllers/flavor_bundle_controller:createFlavorBundle() Leaving")
+
+	bundle := hvs.FlavorBundle{
+		SignedFlavors: []hvs.SignedFlavor{},
+		FlavorGroups:  []hvs.FlavorGroup{},
+	}
+	exportedFlavors := make(map[uuid.UUID]bool, len(signedFlavors))
+	for _, signedFlavor := range signedFlavors {
+		bundle.SignedFlavors = append(bundle.SignedFlavors, signedFlavor)
+		exportedFlavors[signedFlavor.Flavor.Meta.ID] = true
+	}
+	if len(exportedFlavors) == 0 {
+		return &bundle, nil
+	}
+
+	flavorgroups, err := controller.FGStore.Search(&dm.FlavorGroupFilterCriteria{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while searching flavorgroups")
+	}
+
+	var templateIds []uuid.UUID
+	exportedTemplates := make(map[uuid.UUID]bool)
+	for _, fg := range flavorgroups {
+		fgFlavorIds, err := controller.FGStore.SearchFlavors(fg.ID)
+		if err != nil && !strings.Contains(err.Error(), commErr.RowsNotFound) {
+			return nil, errors.Wrapf(err, "Error while retrieving flavors of flavorgroup %s", fg.ID)
+		}
+		var flavorIds []uuid.UUID
+		for _, flavorId := range fgFlavorIds {
+			if exportedFlavors[flavorId] {
+				flavorIds = append(flavorIds, flavorId)
+			}
+		}
+		if len(flavorIds) == 0 {
+			continue
+		}
+
+		fgTemplateIds, err := controller.FGStore.SearchFlavorTemplatesByFlavorGroup(fg.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while retrieving flavor templates of flavorgroup %s", fg.ID)
+		}
+		for _, templateId := range fgTemplateIds {
+			if !exportedTemplates[templateId] {
+				exportedTemplates[templateId] = true
+				templateIds = append(templateIds, templateId)
+			}
+		}
+
+		bundle.FlavorGroups = append(bundle.FlavorGroups, hvs.FlavorGroup{
+			ID:                fg.ID,
+			Name:              fg.Name,
+			FlavorIds:         flavorIds,
+			FlavorTemplateIds: fgTemplateIds,
+			MatchPolicies:     fg.MatchPolicies,
+		})
+	}
+
+	for _, templateId := range templateIds {
+		flavorTemplate, err := controller.FTStore.Retrieve(templateId, false)
+		if err != nil {
+			if _, ok := err.(*commErr.StatusNotFoundError); ok {
+				continue
+			}
+			return nil, errors.Wrapf(err, "Error while retrieving flavor template %s", templateId)
+		}
+		bundle.FlavorTemplates = append(bundle.FlavorTemplates, *flavorTemplate)
+	}
+	return &bundle, nil
+}
+
+// Import creates the flavors of a bundle exported from another HVS instance, after verifying their signature
+// with the trusted flavor signing certificates. Flavors that already exist are left unchanged, so that the same
+// bundle can be imported again.
+func (controller *FlavorBundleController) Import(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_bundle_controller:Import() Entering")
+	defer defaultLog.Trace("controllers/flavor_bundle_controller:Import() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_bundle_controller:Import() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var bundle hvs.FlavorBundle
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&bundle); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_bundle_controller:Import() %s :  Failed to decode request body as FlavorBundle", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	trustedCerts, err := crypt.GetCertsFromDir(controller.TrustedSigningCertsDir)
+	if err != nil || len(trustedCerts) == 0 {
+		defaultLog.WithError(err).Errorf("controllers/flavor_bundle_controller:Import() No trusted flavor signing certificates found in %s", controller.TrustedSigningCertsDir)
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "No trusted flavor signing certificates are configured for import"}
+	}
+
+	if err := validateFlavorBundle(&bundle, trustedCerts); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_bundle_controller:Import() %s : Invalid flavor bundle", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	result, err := controller.importFlavorBundle(&bundle)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_bundle_controller:Import() Failed to import flavor bundle")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to import flavors"}
+	}
+
+	secLog.Infof("%s: %d flavors imported by: %s", commLogMsg.PrivilegeModified, len(result.ImportedFlavorIds), r.RemoteAddr)
+	return result, http.StatusOK, nil
+}
+
+// validateFlavorBundle checks the content of the bundle before anything is stored
+func validateFlavorBundle(bundle *hvs.FlavorBundle, trustedCerts []x509.Certificate) error {
+	defaultLog.Trace("controllers/flavor_bundle_controller:validateFlavorBundle() Entering")
+	defer defaultLog.Trace("controllers/flavor_bundle_controller:validateFlavorBundle() Leaving")
+
+	if len(bundle.SignedFlavors) == 0 {
+		return errors.New("The bundle does not contain any flavor")
+	}
+
+	bundleFlavors := make(map[uuid.UUID]bool, len(bundle.SignedFlavors))
+	for i := range bundle.SignedFlavors {
+		signedFlavor := &bundle.SignedFlavors[i]
+		flavorId := signedFlavor.Flavor.Meta.ID
+		if flavorId == uuid.Nil {
+			return errors.New("Flavor ID must be specified for each flavor")
+		}
+		if bundleFlavors[flavorId] {
+			return errors.Errorf("Flavor %s is included more than once", flavorId)
+		}
+		bundleFlavors[flavorId] = true
+		if err := validateFlavorMetaContent(&signedFlavor.Flavor.Meta); err != nil {
+			return errors.Wrapf(err, "Invalid flavor %s", flavorId)
+		}
+		if err := verifyFlavorSignature(signedFlavor, trustedCerts); err != nil {
+			return err
+		}
+	}
+
+	for _, fg := range bundle.FlavorGroups {
+		if fg.Name == "" {
+			return errors.New("FlavorGroup Name must be specified")
+		}
+		if err := validation.ValidateStrings([]string{fg.Name}); err != nil {
+			return errors.Errorf("Valid FlavorGroup Name must be specified, invalid name %s", fg.Name)
+		}
+		if len(fg.MatchPolicies) == 0 {
+			return errors.Errorf("Flavor Type Match Policy Collection must be specified for flavorgroup %s", fg.Name)
+		}
+		for _, flavorId := range fg.FlavorIds {
+			if !bundleFlavors[flavorId] {
+				return errors.Errorf("Flavor %s of flavorgroup %s is not included in the bundle", flavorId, fg.Name)
+			}
+		}
+	}
+
+	for _, flavorTemplate := range bundle.FlavorTemplates {
+		if flavorTemplate.ID == uuid.Nil {
+			return errors.New("Flavor template ID must be specified for each flavor template")
+		}
+	}
+	return nil
+}
+
+// verifyFlavorSignature checks that the flavor was signed by the key of one of the trusted certificates
+func verifyFlavorSignature(signedFlavor *hvs.SignedFlavor, trustedCerts []x509.Certificate) error {
+	for i := range trustedCerts {
+		publicKey, ok := trustedCerts[i].PublicKey.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		if err := signedFlavor.Verify(publicKey); err == nil {
+			return nil
+		}
+	}
+	return errors.Errorf("Signature of flavor %s is not trusted", signedFlavor.Flavor.Meta.ID)
+}
+
+func (controller *FlavorBundleController) importFlavorBundle(bundle *hvs.FlavorBundle) (*hvs.FlavorBundleImportResult, error) {
+	defaultLog.Trace("controllers/flavor_bundle_controller:importFlavorBundle() Entering")
+	defer defaultLog.Trace("controllers/flavor_bundle_controller:importFlavorBundle() Leaving")
+
+	result := hvs.FlavorBundleImportResult{
+		ImportedFlavorIds: []uuid.UUID{},
+		ExistingFlavorIds: []uuid.UUID{},
+	}
+
+	for i := range bundle.FlavorTemplates {
+		flavorTemplate := bundle.FlavorTemplates[i]
+		if _, err := controller.FTStore.Retrieve(flavorTemplate.ID, false); err != nil {
+			if _, ok := err.(*commErr.StatusNotFoundError); !ok {
+				return nil, errors.Wrapf(err, "Error while retrieving flavor template %s", flavorTemplate.ID)
+			}
+			if _, err := controller.FTStore.Create(&flavorTemplate); err != nil {
+				return nil, errors.Wrapf(err, "Error while creating flavor template %s", flavorTemplate.ID)
+			}
+		}
+	}
+
+	// the flavorgroups are matched by name, their IDs differ between instances
+	localFlavorgroups := make(map[uuid.UUID]hvs.FlavorGroup, len(bundle.FlavorGroups))
+	var flavorgroupsForQueue []hvs.FlavorGroup
+	for _, fg := range bundle.FlavorGroups {
+		localFlavorgroup, err := controller.getOrCreateFlavorgroup(fg)
+		if err != nil {
+			return nil, err
+		}
+		localFlavorgroups[fg.ID] = *localFlavorgroup
+		flavorgroupsForQueue = append(flavorgroupsForQueue, *localFlavorgroup)
+
+		for _, templateId := range fg.FlavorTemplateIds {
+			if err := controller.linkFlavorTemplate(templateId, localFlavorgroup.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// the flavors are signed again with the flavor signing key of this instance, so that the flavor trusted
+	// rule is verified with the local flavor signing certificate
+	flavorSignKey, _, err := controller.CertStore.GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while retrieving flavor signing key")
+	}
+	flavorFlavorPartMap := make(map[hvs.FlavorPartName][]hvs.SignedFlavor)
+	var hostIds []uuid.UUID
+	fetchHostData := false
+	for i := range bundle.SignedFlavors {
+		flavor := bundle.SignedFlavors[i].Flavor
+		flavorId := flavor.Meta.ID
+		if _, err := controller.FStore.Retrieve(flavorId); err == nil {
+			result.ExistingFlavorIds = append(result.ExistingFlavorIds, flavorId)
+			continue
+		} else if !strings.Contains(err.Error(), commErr.RowsNotFound) {
+			return nil, errors.Wrapf(err, "Error while retrieving flavor %s", flavorId)
+		}
+
+		signedFlavor, err := fu.PlatformFlavorUtil{}.GetSignedFlavor(&flavor, flavorSignKey.(*rsa.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while signing flavor %s", flavorId)
+		}
+		if _, err := controller.FStore.Create(signedFlavor); err != nil {
+			return nil, errors.Wrapf(err, "Error while creating flavor %s", flavorId)
+		}
+		result.ImportedFlavorIds = append(result.ImportedFlavorIds, flavorId)
+
+		var flavorPart hvs.FlavorPartName
+		_ = (&flavorPart).Parse(flavor.Meta.Description[hvs.FlavorPartDescription].(string))
+		flavorFlavorPartMap[flavorPart] = append(flavorFlavorPartMap[flavorPart], *signedFlavor)
+		switch flavorPart {
+		case hvs.FlavorPartHostUnique, hvs.FlavorPartAssetTag:
+			linkedHostIds, err := controller.linkHostUniqueFlavor(signedFlavor)
+			if err != nil {
+				return nil, err
+			}
+			hostIds = append(hostIds, linkedHostIds...)
+			fetchHostData = fetchHostData || flavorPart == hvs.FlavorPartAssetTag
+		case hvs.FlavorPartSoftware:
+			fetchHostData = true
+		}
+	}
+
+	flavorgroupFlavorMap := make(map[uuid.UUID][]uuid.UUID)
+	for _, fg := range bundle.FlavorGroups {
+		localFlavorgroupId := localFlavorgroups[fg.ID].ID
+		var flavorIds []uuid.UUID
+		for _, flavorId := range fg.FlavorIds {
+			if _, err := controller.FGStore.RetrieveFlavor(localFlavorgroupId, flavorId); err == nil {
+				continue
+			} else if !strings.Contains(err.Error(), commErr.RowsNotFound) {
+				return nil, errors.Wrapf(err, "Error while retrieving flavor %s of flavorgroup %s", flavorId, fg.Name)
+			}
+			flavorIds = append(flavorIds, flavorId)
+		}
+		if len(flavorIds) == 0 {
+			continue
+		}
+		if _, err := controller.FGStore.AddFlavors(localFlavorgroupId, flavorIds); err != nil {
+			return nil, errors.Wrapf(err, "Error while adding flavors to flavorgroup %s", fg.Name)
+		}
+		flavorgroupFlavorMap[localFlavorgroupId] = flavorIds
+	}
+
+	if err := controller.purgeLatestMatchEntriesFromHTC(flavorgroupFlavorMap, flavorFlavorPartMap, flavorgroupsForQueue); err != nil {
+		defaultLog.WithError(err).Warn("controllers/flavor_bundle_controller:importFlavorBundle() Error clearing latest flavors from host trust cache")
+	}
+
+	if len(result.ImportedFlavorIds) > 0 || len(flavorgroupFlavorMap) > 0 {
+		go controller.addFlavorgroupHostsToFlavorVerifyQueue(flavorgroupsForQueue, hostIds, fetchHostData)
+	}
+	return &result, nil
+}
+
+func (controller *FlavorBundleController) getOrCreateFlavorgroup(fg hvs.FlavorGroup) (*hvs.FlavorGroup, error) {
+	existingFlavorgroups, err := controller.FGStore.Search(&dm.FlavorGroupFilterCriteria{NameEqualTo: fg.Name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error while searching flavorgroup %s", fg.Name)
+	}
+	if len(existingFlavorgroups) > 0 {
+		return &existingFlavorgroups[0], nil
+	}
+
+	createdFlavorgroup, err := controller.FGStore.Create(&hvs.FlavorGroup{
+		Name:          fg.Name,
+		MatchPolicies: fg.MatchPolicies,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error while creating flavorgroup %s", fg.Name)
+	}
+	return createdFlavorgroup, nil
+}
+
+// linkFlavorTemplate associates a flavor template with a flavorgroup, if the template exists in this instance
+func (controller *FlavorBundleController) linkFlavorTemplate(templateId, flavorgroupId uuid.UUID) error {
+	if _, err := controller.FTStore.Retrieve(templateId, false); err != nil {
+		if _, ok := err.(*commErr.StatusNotFoundError); ok {
+			defaultLog.Warnf("controllers/flavor_bundle_controller:linkFlavorTemplate() Flavor template %s does not exist, it is not linked to flavorgroup %s", templateId, flavorgroupId)
+			return nil
+		}
+		return errors.Wrapf(err, "Error while retrieving flavor template %s", templateId)
+	}
+	if _, err := controller.FTStore.RetrieveFlavorgroup(templateId, flavorgroupId); err == nil {
+		return nil
+	} else if !strings.Contains(err.Error(), commErr.RowsNotFound) {
+		return errors.Wrapf(err, "Error while retrieving flavorgroups of flavor template %s", templateId)
+	}
+	if err := controller.FTStore.AddFlavorgroups(templateId, []uuid.UUID{flavorgroupId}); err != nil {
+		return errors.Wrapf(err, "Error while linking flavor template %s to flavorgroup %s", templateId, flavorgroupId)
+	}
+	return nil
+}
+
+// linkHostUniqueFlavor associates a HOST_UNIQUE or ASSET_TAG flavor with the registered hosts of the same hardware UUID
+func (controller *FlavorBundleController) linkHostUniqueFlavor(signedFlavor *hvs.SignedFlavor) ([]uuid.UUID, error) {
+	hardwareUUID, ok := signedFlavor.Flavor.Meta.Description[hvs.HardwareUUID].(string)
+	if !ok {
+		return nil, nil
+	}
+	hostHardwareUUID, err := uuid.Parse(hardwareUUID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid hardware UUID in flavor %s", signedFlavor.Flavor.Meta.ID)
+	}
+	hosts, err := controller.HStore.Search(&dm.HostFilterCriteria{HostHardwareId: hostHardwareUUID}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error while searching hosts with hardware UUID %s", hardwareUUID)
+	}
+	var hostIds []uuid.UUID
+	for _, host := range hosts {
+		if _, err := controller.HStore.AddHostUniqueFlavors(host.Id, []uuid.UUID{signedFlavor.Flavor.Meta.ID}); err != nil {
+			return nil, errors.Wrapf(err, "Error while linking flavor %s to host %s", signedFlavor.Flavor.Meta.ID, host.Id)
+		}
+		hostIds = append(hostIds, host.Id)
+	}
+	return hostIds, nil
+}
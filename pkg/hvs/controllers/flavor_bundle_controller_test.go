@@ -0,0 +1,210 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v5/pkg/hvs/router"
+	smocks "github.com/intel-secl/intel-secl/v5/pkg/hvs/services/hosttrust/mocks"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	fu "github.com/intel-secl/intel-secl/v5/pkg/lib/flavor/util"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FlavorBundleController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var flavorStore *mocks.MockFlavorStore
+	var flavorBundleController *controllers.FlavorBundleController
+	var trustedCertsDir string
+	var signingKey *rsa.PrivateKey
+
+	BeforeEach(func() {
+		var err error
+		router = mux.NewRouter()
+		flavorStore = mocks.NewMockFlavorStore()
+		trustedCertsDir, err = ioutil.TempDir("", "flavor-import")
+		Expect(err).NotTo(HaveOccurred())
+
+		var signingCert string
+		signingKey, signingCert, err = crypt.CreateSelfSignedCertAndRSAPrivKeys(2048)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(trustedCertsDir, "flavor-signing.pem"), []byte(signingCert), 0600)).To(Succeed())
+
+		flavorController := controllers.FlavorController{
+			FStore:    flavorStore,
+			FGStore:   mocks.NewFakeFlavorgroupStore(),
+			HStore:    mocks.NewMockHostStore(),
+			CertStore: mocks.NewFakeCertificatesStore(),
+			TCStore:   mocks.NewMockTagCertificateStore(),
+			HTManager: &smocks.MockHostTrustManager{},
+			FTStore:   mocks.NewFakeFlavorTemplateStore(),
+		}
+		flavorBundleController = controllers.NewFlavorBundleController(flavorController, trustedCertsDir)
+		router.Handle("/flavors/export", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorBundleController.Export))).Methods(http.MethodGet)
+		router.Handle("/flavors/import", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorBundleController.Import))).Methods(http.MethodPost)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(trustedCertsDir)
+	})
+
+	// signedBundle returns a bundle with a platform flavor of the test flavorgroup signed with the given key
+	signedBundle := func(flavorId uuid.UUID, key *rsa.PrivateKey) string {
+		flavor := hvs.Flavor{
+			Meta: hvs.Meta{
+				ID: flavorId,
+				Description: map[string]interface{}{
+					hvs.Label:                 "imported_platform",
+					hvs.FlavorPartDescription: hvs.FlavorPartPlatform.String(),
+				},
+			},
+		}
+		signedFlavor, err := fu.PlatformFlavorUtil{}.GetSignedFlavor(&flavor, key)
+		Expect(err).NotTo(HaveOccurred())
+		bundle := hvs.FlavorBundle{
+			SignedFlavors: []hvs.SignedFlavor{*signedFlavor},
+			FlavorGroups: []hvs.FlavorGroup{{
+				ID:        uuid.New(),
+				Name:      "test",
+				FlavorIds: []uuid.UUID{flavorId},
+				MatchPolicies: []hvs.FlavorMatchPolicy{{
+					FlavorPart:  hvs.FlavorPartPlatform,
+					MatchPolicy: hvs.MatchPolicy{MatchType: hvs.MatchTypeAnyOf, Required: hvs.FlavorRequired},
+				}},
+			}},
+		}
+		bundleJson, err := json.Marshal(bundle)
+		Expect(err).NotTo(HaveOccurred())
+		return string(bundleJson)
+	}
+
+	importBundle := func(body string) {
+		req, err := http.NewRequest(http.MethodPost, "/flavors/import", strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	// Specs for HTTP Get to "/flavors/export"
+	Describe("Export flavors", func() {
+		Context("When a flavor ID is passed", func() {
+			It("Should return a bundle with the flavor", func() {
+				req, err := http.NewRequest(http.MethodGet, "/flavors/export?id=c36b5412-8c02-4e08-8a74-8bfa40425cf3", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var bundle hvs.FlavorBundle
+				Expect(json.Unmarshal(w.Body.Bytes(), &bundle)).NotTo(HaveOccurred())
+				Expect(bundle.SignedFlavors).To(HaveLen(1))
+				Expect(bundle.SignedFlavors[0].Flavor.Meta.ID.String()).To(Equal("c36b5412-8c02-4e08-8a74-8bfa40425cf3"))
+			})
+		})
+
+		Context("When an invalid filter is passed", func() {
+			It("Should return 400", func() {
+				req, err := http.NewRequest(http.MethodGet, "/flavors/export?badparam=value", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Post to "/flavors/import"
+	Describe("Import flavors", func() {
+		Context("Provide a bundle signed with a trusted certificate", func() {
+			It("Should import the flavors once", func() {
+				flavorId := uuid.New()
+				bundle := signedBundle(flavorId, signingKey)
+
+				importBundle(bundle)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				var result hvs.FlavorBundleImportResult
+				Expect(json.Unmarshal(w.Body.Bytes(), &result)).NotTo(HaveOccurred())
+				Expect(result.ImportedFlavorIds).To(Equal([]uuid.UUID{flavorId}))
+				Expect(result.ExistingFlavorIds).To(BeEmpty())
+
+				_, err := flavorStore.Retrieve(flavorId)
+				Expect(err).NotTo(HaveOccurred())
+
+				importBundle(bundle)
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(json.Unmarshal(w.Body.Bytes(), &result)).NotTo(HaveOccurred())
+				Expect(result.ImportedFlavorIds).To(BeEmpty())
+				Expect(result.ExistingFlavorIds).To(Equal([]uuid.UUID{flavorId}))
+			})
+		})
+
+		Context("Provide a bundle signed with an untrusted key", func() {
+			It("Should return 400", func() {
+				untrustedKey, _, err := crypt.CreateSelfSignedCertAndRSAPrivKeys(2048)
+				Expect(err).NotTo(HaveOccurred())
+				importBundle(signedBundle(uuid.New(), untrustedKey))
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a bundle with a flavorgroup referring to a flavor that is not included", func() {
+			It("Should return 400", func() {
+				var bundle hvs.FlavorBundle
+				Expect(json.Unmarshal([]byte(signedBundle(uuid.New(), signingKey)), &bundle)).NotTo(HaveOccurred())
+				bundle.FlavorGroups[0].FlavorIds = append(bundle.FlavorGroups[0].FlavorIds, uuid.New())
+				bundleJson, err := json.Marshal(bundle)
+				Expect(err).NotTo(HaveOccurred())
+				importBundle(string(bundleJson))
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide an empty bundle", func() {
+			It("Should return 400", func() {
+				importBundle(`{"signed_flavors": [], "flavorgroups": []}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When no trusted certificate is configured", func() {
+			It("Should return 500", func() {
+				flavorBundleController.TrustedSigningCertsDir = filepath.Join(trustedCertsDir, "missing")
+				importBundle(signedBundle(uuid.New(), signingKey))
+				Expect(w.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("Provide an invalid Content-Type", func() {
+			It("Should return 415", func() {
+				req, err := http.NewRequest(http.MethodPost, "/flavors/import", strings.NewReader(`{"signed_flavors": []}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeXml)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+			})
+		})
+	})
+})
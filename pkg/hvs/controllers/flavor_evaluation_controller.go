@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	dm "github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
+	fu "github.com/intel-secl/intel-secl/v5/pkg/lib/flavor/util"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// FlavorEvaluationController verifies hosts against flavors without storing reports, so that new flavors can be
+// tried out before they are added to a flavorgroup
+type FlavorEvaluationController struct {
+	HStore    domain.HostStore
+	HSStore   domain.HostStatusStore
+	FGStore   domain.FlavorGroupStore
+	HTManager domain.HostTrustManager
+	CertStore *crypt.CertificatesStore
+}
+
+func NewFlavorEvaluationController(hs domain.HostStore, hss domain.HostStatusStore, fgs domain.FlavorGroupStore, htm domain.HostTrustManager, certStore *crypt.CertificatesStore) *FlavorEvaluationController {
+	return &FlavorEvaluationController{
+		HStore:    hs,
+		HSStore:   hss,
+		FGStore:   fgs,
+		HTManager: htm,
+		CertStore: certStore,
+	}
+}
+
+// Evaluate returns the trust report of the host against the requested flavorgroup and candidate flavors.
+// Nothing is persisted: the report, the host status and the trust caches are left untouched.
+func (controller FlavorEvaluationController) Evaluate(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/flavor_evaluation_controller:Evaluate() Entering")
+	defer defaultLog.Trace("controllers/flavor_evaluation_controller:Evaluate() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/flavor_evaluation_controller:Evaluate() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var evaluationRequest hvs.FlavorEvaluationRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&evaluationRequest); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_evaluation_controller:Evaluate() %s :  Failed to decode request body as FlavorEvaluationRequest", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err := validateFlavorEvaluationRequest(evaluationRequest); err != nil {
+		secLog.WithError(err).Errorf("controllers/flavor_evaluation_controller:Evaluate() %s : Invalid flavor evaluation request", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	hostData := evaluationRequest.HostManifest
+	if evaluationRequest.HostID != uuid.Nil {
+		var status int
+		var err error
+		hostData, status, err = controller.getStoredHostManifest(evaluationRequest.HostID)
+		if err != nil {
+			return nil, status, err
+		}
+	}
+
+	var flavorGroups []hvs.FlavorGroup
+	if evaluationRequest.FlavorgroupName != "" {
+		fgs, err := controller.FGStore.Search(&dm.FlavorGroupFilterCriteria{NameEqualTo: evaluationRequest.FlavorgroupName})
+		if err != nil {
+			defaultLog.WithError(err).Error("controllers/flavor_evaluation_controller:Evaluate() Flavorgroup search operation failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve flavorgroup"}
+		}
+		if len(fgs) == 0 {
+			secLog.Errorf("controllers/flavor_evaluation_controller:Evaluate() %s : Flavorgroup %s does not exist", commLogMsg.InvalidInputBadParam, evaluationRequest.FlavorgroupName)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Flavorgroup with given name does not exist"}
+		}
+		flavorGroups = fgs
+	}
+
+	candidateFlavors, err := controller.signCandidateFlavors(evaluationRequest.FlavorCollection)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_evaluation_controller:Evaluate() Failed to sign candidate flavors")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to evaluate flavors"}
+	}
+
+	trustReport, err := controller.HTManager.EvaluateFlavors(evaluationRequest.HostID, hostData, flavorGroups, candidateFlavors)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/flavor_evaluation_controller:Evaluate() Failed to evaluate flavors")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to evaluate flavors"}
+	}
+
+	secLog.Infof("%s: Flavors evaluated by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return trustReport, http.StatusOK, nil
+}
+
+// getStoredHostManifest returns the host manifest of the latest status of a connected host
+func (controller FlavorEvaluationController) getStoredHostManifest(hostId uuid.UUID) (*hvs.HostManifest, int, error) {
+	if _, err := controller.HStore.Retrieve(hostId, nil); err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", hostId).Errorf("controllers/flavor_evaluation_controller:getStoredHostManifest() %s : Host with given ID does not exist", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Host with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", hostId).Error("controllers/flavor_evaluation_controller:getStoredHostManifest() Failed to retrieve host")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve host"}
+	}
+
+	hostStatuses, err := controller.HSStore.Search(&dm.HostStatusFilterCriteria{
+		HostId:        hostId,
+		LatestPerHost: true,
+		Limit:         1,
+	})
+	if err != nil {
+		defaultLog.WithError(err).WithField("id", hostId).Error("controllers/flavor_evaluation_controller:getStoredHostManifest() Failed to retrieve host status")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve host status"}
+	}
+	if len(hostStatuses) == 0 || hostStatuses[0].HostStatusInformation.HostState != hvs.HostStateConnected {
+		secLog.WithField("id", hostId).Errorf("controllers/flavor_evaluation_controller:getStoredHostManifest() %s : No host manifest stored for host", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "No host manifest is available for the host"}
+	}
+	return &hostStatuses[0].HostManifest, http.StatusOK, nil
+}
+
+// signCandidateFlavors signs the candidate flavors with the flavor signing key, the same as when they are created,
+// so that they are verified exactly like the flavors of the flavorgroup
+func (controller FlavorEvaluationController) signCandidateFlavors(flavorCollection hvs.FlavorCollection) ([]hvs.SignedFlavor, error) {
+	if len(flavorCollection.Flavors) == 0 {
+		return nil, nil
+	}
+	flavorSignKey, _, err := controller.CertStore.GetKeyAndCertificates(dm.CertTypesFlavorSigning.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while retrieving flavor signing key")
+	}
+
+	var platformFlavorUtil fu.PlatformFlavorUtil
+	var signedFlavors []hvs.SignedFlavor
+	for i := range flavorCollection.Flavors {
+		flavor := flavorCollection.Flavors[i].Flavor
+		if flavor.Meta.ID == uuid.Nil {
+			flavor.Meta.ID = uuid.New()
+		}
+		signedFlavor, err := platformFlavorUtil.GetSignedFlavor(&flavor, flavorSignKey.(*rsa.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting signed flavor from flavor library")
+		}
+		signedFlavors = append(signedFlavors, *signedFlavor)
+	}
+	return signedFlavors, nil
+}
+
+func validateFlavorEvaluationRequest(evaluationRequest hvs.FlavorEvaluationRequest) error {
+	defaultLog.Trace("controllers/flavor_evaluation_controller:validateFlavorEvaluationRequest() Entering")
+	defer defaultLog.Trace("controllers/flavor_evaluation_controller:validateFlavorEvaluationRequest() Leaving")
+
+	if (evaluationRequest.HostID == uuid.Nil) == (evaluationRequest.HostManifest == nil) {
+		return errors.New("Either host_id or host_manifest must be specified")
+	}
+	if evaluationRequest.HostManifest != nil && evaluationRequest.HostManifest.PcrManifest.IsEmpty() {
+		return errors.New("host_manifest must contain a PCR manifest")
+	}
+	if evaluationRequest.FlavorgroupName == "" && len(evaluationRequest.FlavorCollection.Flavors) == 0 {
+		return errors.New("Either flavorgroup_name or flavor_collection must be specified")
+	}
+	if evaluationRequest.FlavorgroupName != "" {
+		if err := validation.ValidateTextString(evaluationRequest.FlavorgroupName); err != nil {
+			return errors.New("Valid flavorgroup_name must be specified")
+		}
+	}
+	for i := range evaluationRequest.FlavorCollection.Flavors {
+		if err := validateFlavorMetaContent(&evaluationRequest.FlavorCollection.Flavors[i].Flavor.Meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v5/pkg/hvs/router"
+	smocks "github.com/intel-secl/intel-secl/v5/pkg/hvs/services/hosttrust/mocks"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FlavorEvaluationController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var flavorEvaluationController *controllers.FlavorEvaluationController
+
+	const hostManifest = `{
+		"host_info": {"host_name": "localhost1", "hardware_uuid": "00ecd3ab-9af4-e711-906e-001560a04062"},
+		"pcr_manifest": {"sha2pcrs": [{"index": "pcr_0", "value": "3f95ecbb0bb8e66e54d3f9e4dbae8fe57fed96f0", "pcr_bank": "SHA256"}]}
+	}`
+	const candidateFlavor = `{
+		"flavors": [{
+			"flavor": {
+				"meta": {"description": {"flavor_part": "PLATFORM", "label": "candidate_platform"}},
+				"pcrs": [{"pcr": {"index": 0, "bank": "SHA256"}, "measurement": "3f95ecbb0bb8e66e54d3f9e4dbae8fe57fed96f0", "pcr_matches": true}]
+			}
+		}]
+	}`
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		flavorEvaluationController = controllers.NewFlavorEvaluationController(mocks.NewMockHostStore(),
+			mocks.NewMockHostStatusStore(), mocks.NewFakeFlavorgroupStore(), &smocks.MockHostTrustManager{},
+			mocks.NewFakeCertificatesStore())
+		router.Handle("/rpc/evaluate-flavors", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(flavorEvaluationController.Evaluate))).Methods(http.MethodPost)
+	})
+
+	evaluate := func(body string) {
+		req, err := http.NewRequest(http.MethodPost, "/rpc/evaluate-flavors", strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+		req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	// Specs for HTTP Post to "/rpc/evaluate-flavors"
+	Describe("Evaluate flavors", func() {
+		Context("Provide a host manifest and candidate flavors", func() {
+			It("Should return the trust report of the candidate flavors", func() {
+				evaluate(`{"host_manifest": ` + hostManifest + `, "flavor_collection": ` + candidateFlavor + `}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var trustReport hvs.TrustReport
+				Expect(json.Unmarshal(w.Body.Bytes(), &trustReport)).NotTo(HaveOccurred())
+				Expect(trustReport.Trusted).To(BeTrue())
+				Expect(trustReport.Results).To(HaveLen(1))
+				Expect(trustReport.HostManifest.HostInfo.HostName).To(Equal("localhost1"))
+			})
+		})
+
+		Context("Provide a registered host and an existing flavorgroup", func() {
+			It("Should evaluate the stored host manifest", func() {
+				evaluate(`{"host_id": "ee37c360-7eae-4250-a677-6ee12adce8e2", "flavorgroup_name": "automatic"}`)
+				Expect(w.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		Context("Provide a host that is not registered", func() {
+			It("Should return 400", func() {
+				evaluate(`{"host_id": "73755fda-c910-46be-821f-e8ddeab189e9", "flavorgroup_name": "automatic"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide both a host ID and a host manifest", func() {
+			It("Should return 400", func() {
+				evaluate(`{"host_id": "ee37c360-7eae-4250-a677-6ee12adce8e2", "host_manifest": ` + hostManifest + `, "flavorgroup_name": "automatic"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide neither a flavorgroup nor flavors", func() {
+			It("Should return 400", func() {
+				evaluate(`{"host_manifest": ` + hostManifest + `}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a flavorgroup that does not exist", func() {
+			It("Should return 400", func() {
+				evaluate(`{"host_manifest": ` + hostManifest + `, "flavorgroup_name": "missing"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide a candidate flavor without a flavor part", func() {
+			It("Should return 400", func() {
+				evaluate(`{"host_manifest": ` + hostManifest + `, "flavor_collection": {"flavors": [{"flavor": {"meta": {"description": {"flavor_part": "UNKNOWN", "label": "candidate"}}}}]}}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide an unknown attribute in the request", func() {
+			It("Should return 400", func() {
+				evaluate(`{"host_manifest": ` + hostManifest + `, "flavorgroup_name": "automatic", "store_report": true}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Provide an invalid Content-Type", func() {
+			It("Should return 415", func() {
+				req, err := http.NewRequest(http.MethodPost, "/rpc/evaluate-flavors", strings.NewReader(`{"flavorgroup_name": "automatic"}`))
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Content-Type", constants.HTTPMediaTypeXml)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusUnsupportedMediaType))
+			})
+		})
+	})
+})
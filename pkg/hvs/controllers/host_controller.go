@@ -16,6 +16,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/metrics"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/utils"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
@@ -216,6 +217,7 @@ func (hc *HostController) Delete(w http.ResponseWriter, r *http.Request) (interf
 		defaultLog.WithError(err).WithField("id", id).Error("controllers/host_controller:Delete() Host delete failed")
 		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete Host"}
 	}
+	metrics.RemoveHost(id)
 
 	secLog.WithField("host", host).Infof("Host deleted by: %s", r.RemoteAddr)
 	return nil, http.StatusNoContent, nil
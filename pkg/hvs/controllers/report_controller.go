@@ -6,6 +6,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	consts "github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
@@ -205,6 +206,75 @@ func (controller ReportController) Retrieve(w http.ResponseWriter, r *http.Reque
 	return report, http.StatusOK, nil
 }
 
+var reportDiffParams = map[string]bool{"against": true}
+
+// Diff compares the report with the report given by the against query parameter, such as an earlier report replaced
+// by the newer reports of the host. Both reports must belong to the same host.
+func (controller ReportController) Diff(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/report_controller:Diff() Entering")
+	defer defaultLog.Trace("controllers/report_controller:Diff() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), reportDiffParams); err != nil {
+		secLog.Errorf("controllers/report_controller:Diff() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	againstId, err := uuid.Parse(strings.TrimSpace(r.URL.Query().Get("against")))
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/report_controller:Diff() %s : Invalid against query parameter given", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid against query parameter given"}
+	}
+
+	hvsReport, status, err := controller.retrieveReport(id)
+	if err != nil {
+		return nil, status, err
+	}
+	againstReport, status, err := controller.retrieveReport(againstId)
+	if err != nil {
+		return nil, status, err
+	}
+	if hvsReport.HostID != againstReport.HostID {
+		secLog.Errorf("controllers/report_controller:Diff() %s : Reports %s and %s belong to different hosts", commLogMsg.InvalidInputBadParam, id, againstId)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Reports must belong to the same host"}
+	}
+
+	reportDiff := hvs.DiffTrustReports(&hvsReport.TrustReport, &againstReport.TrustReport)
+	reportDiff.ReportID = hvsReport.ID
+	reportDiff.AgainstReportID = againstReport.ID
+	reportDiff.HostID = hvsReport.HostID
+
+	secLog.WithField("id", id).WithField("against", againstId).Infof("%s: Report diff retrieved by: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return reportDiff, http.StatusOK, nil
+}
+
+// retrieveReport retrieves the report, the reports replaced by a newer report of their host are retrieved from the
+// report history
+func (controller ReportController) retrieveReport(id uuid.UUID) (*models.HVSReport, int, error) {
+	hvsReport, err := controller.ReportStore.Retrieve(id)
+	if err == nil {
+		return hvsReport, http.StatusOK, nil
+	}
+	if !strings.Contains(err.Error(), commErr.RowsNotFound) {
+		secLog.WithError(err).WithField("id", id).Info(
+			"controllers/report_controller:retrieveReport() failed to retrieve Report")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Report"}
+	}
+
+	hvsReports, err := controller.ReportStore.Search(&models.ReportFilterCriteria{ID: id, LatestPerHost: false})
+	if err != nil {
+		secLog.WithError(err).WithField("id", id).Info(
+			"controllers/report_controller:retrieveReport() failed to retrieve Report from history")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve Report"}
+	}
+	if len(hvsReports) == 0 {
+		secLog.WithField("id", id).Info(
+			"controllers/report_controller:retrieveReport() Report with given ID does not exist")
+		return nil, http.StatusNotFound, &commErr.ResourceError{Message: fmt.Sprintf("Report with ID %s does not exist", id)}
+	}
+	return &hvsReports[0], http.StatusOK, nil
+}
+
 func (controller ReportController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/report_controller:Search() Entering")
 	defer defaultLog.Trace("controllers/report_controller:Search() Leaving")
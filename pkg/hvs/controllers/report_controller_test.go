@@ -11,9 +11,11 @@ import (
 	"net/http/httptest"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
 	hvsRoutes "github.com/intel-secl/intel-secl/v5/pkg/hvs/router"
 	smocks "github.com/intel-secl/intel-secl/v5/pkg/hvs/services/hosttrust/mocks"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
@@ -230,6 +232,78 @@ var _ = Describe("ReportController", func() {
 		})
 	})
 
+	// Specs for HTTP Get to "/reports/{rId}/diff"
+	Describe("Diff two Reports", func() {
+		serveDiff := func(path string) {
+			router.Handle("/reports/{id}/diff", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(reportController.Diff))).Methods(http.MethodGet)
+			req, err := http.NewRequest(http.MethodGet, path, nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+		}
+
+		Context("Diff a Report against an earlier Report of the same host", func() {
+			It("Should return the changed rules, PCRs and event log entries", func() {
+				existing, err := reportStore.Retrieve(uuid.MustParse("15701f03-7b1d-49f9-ac62-6b9b0728bdb3"))
+				Expect(err).NotTo(HaveOccurred())
+				// copy the trust report so that the existing report is not modified
+				trustReportBytes, err := json.Marshal(existing.TrustReport)
+				Expect(err).NotTo(HaveOccurred())
+				var trustReport hvs.TrustReport
+				Expect(json.Unmarshal(trustReportBytes, &trustReport)).To(Succeed())
+
+				trustReport.Trusted = false
+				trustReport.Results[0].Trusted = false
+				trustReport.Results[0].Faults = []hvs.Fault{{Name: "PcrEventLogContainsUnexpectedEntries", Description: "Module manifest for PCR 17 contains unexpected entries"}}
+				trustReport.HostManifest.PcrManifest.Sha1Pcrs[0].Value = "0000000000000000000000000000000000000000"
+				eventLogs := &trustReport.HostManifest.PcrManifest.PcrEventLogMap.Sha1EventLogs[0]
+				eventLogs.TpmEvent = append(eventLogs.TpmEvent, hvs.EventLog{TypeName: "LCP_DETAILS_HASH", Measurement: "b91bc2aa1bff6bd9a37d3c36e2e7e7e46e85b3a8"})
+				// the new report of the host replaces the existing report
+				newReport, err := reportStore.Update(&models.HVSReport{HostID: existing.HostID, TrustReport: trustReport})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = reportStore.Retrieve(uuid.MustParse("15701f03-7b1d-49f9-ac62-6b9b0728bdb3"))
+				Expect(err).To(HaveOccurred())
+
+				serveDiff("/reports/" + newReport.ID.String() + "/diff?against=15701f03-7b1d-49f9-ac62-6b9b0728bdb3")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var reportDiff hvs.ReportDiff
+				Expect(json.Unmarshal(w.Body.Bytes(), &reportDiff)).To(Succeed())
+				Expect(reportDiff.Trusted).To(BeFalse())
+				Expect(reportDiff.AgainstTrusted).To(BeTrue())
+				Expect(reportDiff.Rules).To(HaveLen(1))
+				Expect(*reportDiff.Rules[0].AgainstTrusted).To(BeTrue())
+				Expect(reportDiff.Rules[0].Faults).To(HaveLen(1))
+				Expect(reportDiff.Pcrs).To(HaveLen(1))
+				Expect(reportDiff.EventLogs).To(HaveLen(1))
+				Expect(reportDiff.EventLogs[0].Added).To(HaveLen(1))
+				Expect(reportDiff.EventLogs[0].Removed).To(BeEmpty())
+			})
+		})
+
+		Context("Diff Reports of different hosts", func() {
+			It("Should return bad request", func() {
+				serveDiff("/reports/15701f03-7b1d-49f9-ac62-6b9b0728bdb3/diff?against=15701f03-7b1d-49f9-ac62-6b9b0728bdb4")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Diff without the against query parameter", func() {
+			It("Should return bad request", func() {
+				serveDiff("/reports/15701f03-7b1d-49f9-ac62-6b9b0728bdb3/diff")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("Diff against a non-existent Report", func() {
+			It("Should return not found", func() {
+				serveDiff("/reports/15701f03-7b1d-49f9-ac62-6b9b0728bdb3/diff?against=73755fda-c910-46be-821f-e8ddeab189e9")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	// Specs for HTTP Get to "/reports"
 	Describe("Search for all the Reports", func() {
 		Context("Get all the Reports", func() {
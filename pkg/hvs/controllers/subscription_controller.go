@@ -0,0 +1,254 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+const (
+	minSubscriptionSecretLength = 16
+	maxDeadLetterSearchLimit    = 1000
+)
+
+// SubscriptionController contains logic for handling event subscription API requests
+type SubscriptionController struct {
+	Store             domain.SubscriptionStore
+	DeadLetterStore   domain.EventDeadLetterStore
+	DataEncryptionKey []byte
+}
+
+func NewSubscriptionController(store domain.SubscriptionStore, dls domain.EventDeadLetterStore, dek []byte) *SubscriptionController {
+	return &SubscriptionController{
+		Store:             store,
+		DeadLetterStore:   dls,
+		DataEncryptionKey: dek,
+	}
+}
+
+var subscriptionSearchParams = map[string]bool{"eventType": true, "url": true}
+var deadLetterSearchParams = map[string]bool{"limit": true}
+
+// Create registers a subscriber URL for a set of event types. The secret used to sign the events is
+// generated when not provided and is only returned in this response.
+func (controller SubscriptionController) Create(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Create() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Create() Leaving")
+
+	if r.Header.Get("Content-Type") != constants.HTTPMediaTypeJson {
+		return nil, http.StatusUnsupportedMediaType, &commErr.ResourceError{Message: "Invalid Content-Type"}
+	}
+
+	if r.ContentLength == 0 {
+		secLog.Error("controllers/subscription_controller:Create() The request body is not provided")
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "The request body is not provided"}
+	}
+
+	var reqSubscription hvs.Subscription
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&reqSubscription); err != nil {
+		secLog.WithError(err).Errorf("controllers/subscription_controller:Create() %s :  Failed to decode request body as Subscription", commLogMsg.InvalidInputBadEncoding)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Unable to decode JSON request body"}
+	}
+
+	if err := validateSubscription(&reqSubscription); err != nil {
+		secLog.WithError(err).Errorf("controllers/subscription_controller:Create() %s", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	secret := reqSubscription.Secret
+	if secret == "" {
+		secretBytes := make([]byte, 32)
+		if _, err := rand.Read(secretBytes); err != nil {
+			defaultLog.WithError(err).Error("controllers/subscription_controller:Create() Failed to generate subscription secret")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to generate subscription secret"}
+		}
+		secret = hex.EncodeToString(secretBytes)
+	}
+
+	encryptedSecret, err := utils.EncryptString(secret, controller.DataEncryptionKey)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/subscription_controller:Create() Failed to encrypt subscription secret")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create subscription"}
+	}
+	reqSubscription.Secret = encryptedSecret
+
+	newSubscription, err := controller.Store.Create(&reqSubscription)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/subscription_controller:Create() Subscription create failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to create subscription"}
+	}
+	newSubscription.Secret = secret
+
+	secLog.WithField("url", newSubscription.URL).Infof("%s: Subscription created by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return newSubscription, http.StatusCreated, nil
+}
+
+// Retrieve returns the subscription with the given id without its secret
+func (controller SubscriptionController) Retrieve(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Retrieve() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Retrieve() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	subscription, status, err := controller.retrieveSubscription(id)
+	if err != nil {
+		return nil, status, err
+	}
+	subscription.Secret = ""
+
+	secLog.WithField("id", id).Infof("Subscription retrieved by: %s", r.RemoteAddr)
+	return subscription, http.StatusOK, nil
+}
+
+// Search returns the subscriptions matching the eventType and url query parameters
+func (controller SubscriptionController) Search(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Search() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), subscriptionSearchParams); err != nil {
+		secLog.Errorf("controllers/subscription_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter := models.SubscriptionFilterCriteria{
+		EventType: hvs.EventType(strings.TrimSpace(r.URL.Query().Get("eventType"))),
+		URL:       strings.TrimSpace(r.URL.Query().Get("url")),
+	}
+	if filter.EventType != "" && !filter.EventType.IsValid() {
+		secLog.Errorf("controllers/subscription_controller:Search() %s : Invalid eventType query parameter", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid eventType query parameter"}
+	}
+
+	subscriptions, err := controller.Store.Search(&filter)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/subscription_controller:Search() Subscription search operation failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Subscription search operation failed"}
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	secLog.Infof("%s: Return subscription query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hvs.SubscriptionCollection{Subscriptions: subscriptions}, http.StatusOK, nil
+}
+
+// Delete removes the subscription and its dead letter records
+func (controller SubscriptionController) Delete(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:Delete() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:Delete() Leaving")
+
+	id := uuid.MustParse(mux.Vars(r)["id"])
+	if _, status, err := controller.retrieveSubscription(id); err != nil {
+		return nil, status, err
+	}
+
+	if err := controller.Store.Delete(id); err != nil {
+		defaultLog.WithError(err).WithField("id", id).Error("controllers/subscription_controller:Delete() Failed to delete subscription")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to delete subscription"}
+	}
+
+	secLog.WithField("id", id).Infof("%s: Subscription deleted by: %s", commLogMsg.PrivilegeModified, r.RemoteAddr)
+	return nil, http.StatusNoContent, nil
+}
+
+// SearchDeadLetters returns the events that could not be delivered to the subscription, most recent first
+func (controller SubscriptionController) SearchDeadLetters(w http.ResponseWriter, r *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/subscription_controller:SearchDeadLetters() Entering")
+	defer defaultLog.Trace("controllers/subscription_controller:SearchDeadLetters() Leaving")
+
+	if err := utils.ValidateQueryParams(r.URL.Query(), deadLetterSearchParams); err != nil {
+		secLog.Errorf("controllers/subscription_controller:SearchDeadLetters() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	filter := models.EventDeadLetterFilterCriteria{
+		SubscriptionID: uuid.MustParse(mux.Vars(r)["id"]),
+		Limit:          maxDeadLetterSearchLimit,
+	}
+	if limit := strings.TrimSpace(r.URL.Query().Get("limit")); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l <= 0 || l > maxDeadLetterSearchLimit {
+			secLog.Errorf("controllers/subscription_controller:SearchDeadLetters() %s : Invalid limit query parameter", commLogMsg.InvalidInputBadParam)
+			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid limit query parameter"}
+		}
+		filter.Limit = l
+	}
+
+	if _, status, err := controller.retrieveSubscription(filter.SubscriptionID); err != nil {
+		return nil, status, err
+	}
+
+	deadLetters, err := controller.DeadLetterStore.Search(&filter)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/subscription_controller:SearchDeadLetters() Dead letter search operation failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Dead letter search operation failed"}
+	}
+
+	secLog.Infof("%s: Return dead letter query to: %s", commLogMsg.AuthorizedAccess, r.RemoteAddr)
+	return hvs.EventDeadLetterCollection{DeadLetters: deadLetters}, http.StatusOK, nil
+}
+
+func (controller SubscriptionController) retrieveSubscription(id uuid.UUID) (*hvs.Subscription, int, error) {
+	subscription, err := controller.Store.Retrieve(id)
+	if err != nil {
+		if strings.Contains(err.Error(), commErr.RowsNotFound) {
+			secLog.WithError(err).WithField("id", id).Error("controllers/subscription_controller:retrieveSubscription() Subscription with given ID does not exist")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Subscription with given ID does not exist"}
+		}
+		defaultLog.WithError(err).WithField("id", id).Error("controllers/subscription_controller:retrieveSubscription() Failed to retrieve subscription")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve subscription"}
+	}
+	return subscription, http.StatusOK, nil
+}
+
+func validateSubscription(sub *hvs.Subscription) error {
+	if sub.ID != uuid.Nil || !sub.Created.IsZero() {
+		return errors.New("id and created must not be specified")
+	}
+
+	subscriberUrl, err := url.Parse(sub.URL)
+	if err != nil || subscriberUrl.Host == "" || (subscriberUrl.Scheme != "https" && subscriberUrl.Scheme != "http") {
+		return errors.New("url must be a valid http or https URL")
+	}
+
+	if len(sub.EventTypes) == 0 {
+		return errors.New("event_types must be specified")
+	}
+	uniqueEventTypes := make(map[hvs.EventType]bool)
+	var eventTypes []hvs.EventType
+	for _, et := range sub.EventTypes {
+		if !et.IsValid() {
+			return errors.Errorf("Invalid event type %s", et)
+		}
+		if !uniqueEventTypes[et] {
+			uniqueEventTypes[et] = true
+			eventTypes = append(eventTypes, et)
+		}
+	}
+	sub.EventTypes = eventTypes
+
+	if sub.Secret != "" && len(sub.Secret) < minSubscriptionSecretLength {
+		return errors.Errorf("secret must be at least %d characters long", minSubscriptionSecretLength)
+	}
+	return nil
+}
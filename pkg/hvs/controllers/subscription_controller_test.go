@@ -0,0 +1,188 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
+	mocks2 "github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
+	hvsRoutes "github.com/intel-secl/intel-secl/v5/pkg/hvs/router"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SubscriptionController", func() {
+	const existingSubscriptionId = "1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11"
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var subscriptionStore *mocks2.MockSubscriptionStore
+	var subscriptionController *controllers.SubscriptionController
+	dek := []byte("0123456789abcdef0123456789abcdef")
+
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		subscriptionStore = mocks2.NewMockSubscriptionStore()
+		subscriptionController = controllers.NewSubscriptionController(subscriptionStore, mocks2.NewMockEventDeadLetterStore(), dek)
+
+		router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Create))).Methods(http.MethodPost)
+		router.Handle("/subscriptions", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Search))).Methods(http.MethodGet)
+		router.Handle("/subscriptions/{id}", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.Retrieve))).Methods(http.MethodGet)
+		router.Handle("/subscriptions/{id}", hvsRoutes.ErrorHandler(hvsRoutes.ResponseHandler(subscriptionController.Delete))).Methods(http.MethodDelete)
+		router.Handle("/subscriptions/{id}/dead-letters", hvsRoutes.ErrorHandler(hvsRoutes.JsonResponseHandler(subscriptionController.SearchDeadLetters))).Methods(http.MethodGet)
+	})
+
+	serve := func(method, path, body string) {
+		req, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Accept", constants.HTTPMediaTypeJson)
+		if body != "" {
+			req.Header.Set("Content-Type", constants.HTTPMediaTypeJson)
+		}
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+	}
+
+	// Specs for HTTP Post to "/subscriptions"
+	Describe("Create Subscription", func() {
+		Context("When a valid subscription without secret is provided", func() {
+			It("Should create the subscription and return a generated secret", func() {
+				serve(http.MethodPost, "/subscriptions", `{"url": "https://siem.example.com/events", "event_types": ["host.untrusted", "host.untrusted", "flavor.created"]}`)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var sub hvs.Subscription
+				Expect(json.Unmarshal(w.Body.Bytes(), &sub)).To(Succeed())
+				Expect(sub.Secret).To(HaveLen(64))
+				Expect(sub.EventTypes).To(Equal([]hvs.EventType{hvs.EventTypeHostUntrusted, hvs.EventTypeFlavorCreated}))
+
+				stored, err := subscriptionStore.Retrieve(sub.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.Secret).NotTo(Equal(sub.Secret))
+				decrypted, err := utils.DecryptString(stored.Secret, dek)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decrypted).To(Equal(sub.Secret))
+			})
+		})
+
+		Context("When an unknown event type is provided", func() {
+			It("Should return bad request", func() {
+				serve(http.MethodPost, "/subscriptions", `{"url": "https://siem.example.com/events", "event_types": ["host.rebooted"]}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When an invalid url is provided", func() {
+			It("Should return bad request", func() {
+				serve(http.MethodPost, "/subscriptions", `{"url": "ftp://siem.example.com/events", "event_types": ["host.untrusted"]}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("When a short secret is provided", func() {
+			It("Should return bad request", func() {
+				serve(http.MethodPost, "/subscriptions", `{"url": "https://siem.example.com/events", "event_types": ["host.untrusted"], "secret": "short"}`)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/subscriptions"
+	Describe("Search Subscriptions", func() {
+		Context("When filtered by a subscribed event type", func() {
+			It("Should return the subscription without its secret", func() {
+				serve(http.MethodGet, "/subscriptions?eventType=host.connection-failure", "")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var collection hvs.SubscriptionCollection
+				Expect(json.Unmarshal(w.Body.Bytes(), &collection)).To(Succeed())
+				Expect(collection.Subscriptions).To(HaveLen(1))
+				Expect(collection.Subscriptions[0].Secret).To(BeEmpty())
+			})
+		})
+
+		Context("When filtered by an event type without subscribers", func() {
+			It("Should return an empty collection", func() {
+				serve(http.MethodGet, "/subscriptions?eventType=flavor.deleted", "")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(strings.TrimSpace(w.Body.String())).To(Equal(`{"subscriptions":[]}`))
+			})
+		})
+
+		Context("When filtered by an invalid event type", func() {
+			It("Should return bad request", func() {
+				serve(http.MethodGet, "/subscriptions?eventType=unknown", "")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/subscriptions/{id}"
+	Describe("Retrieve Subscription", func() {
+		Context("When the subscription exists", func() {
+			It("Should return the subscription without its secret", func() {
+				serve(http.MethodGet, "/subscriptions/"+existingSubscriptionId, "")
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Body.String()).NotTo(ContainSubstring("secret"))
+			})
+		})
+
+		Context("When the subscription does not exist", func() {
+			It("Should return not found", func() {
+				serve(http.MethodGet, "/subscriptions/73755fda-c910-46be-821f-e8ddeab189e9", "")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Delete to "/subscriptions/{id}"
+	Describe("Delete Subscription", func() {
+		Context("When the subscription exists", func() {
+			It("Should delete the subscription", func() {
+				serve(http.MethodDelete, "/subscriptions/"+existingSubscriptionId, "")
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+
+				serve(http.MethodGet, "/subscriptions/"+existingSubscriptionId, "")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("When the subscription does not exist", func() {
+			It("Should return not found", func() {
+				serve(http.MethodDelete, "/subscriptions/73755fda-c910-46be-821f-e8ddeab189e9", "")
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/subscriptions/{id}/dead-letters"
+	Describe("Search dead letters", func() {
+		Context("When the subscription has undelivered events", func() {
+			It("Should return the dead letters", func() {
+				serve(http.MethodGet, "/subscriptions/"+existingSubscriptionId+"/dead-letters?limit=10", "")
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var collection hvs.EventDeadLetterCollection
+				Expect(json.Unmarshal(w.Body.Bytes(), &collection)).To(Succeed())
+				Expect(collection.DeadLetters).To(HaveLen(1))
+				Expect(collection.DeadLetters[0].Event.Type).To(Equal(hvs.EventTypeHostUntrusted))
+			})
+		})
+
+		Context("When an invalid limit is provided", func() {
+			It("Should return bad request", func() {
+				serve(http.MethodGet, "/subscriptions/"+existingSubscriptionId+"/dead-letters?limit=-1", "")
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/events"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/hrrs"
 	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
 	"github.com/spf13/viper"
@@ -32,6 +33,7 @@ func init() {
 	viper.SetDefault(config.FlavorSigningCertFile, constants.FlavorSigningCertFile)
 	viper.SetDefault(config.FlavorSigningKeyFile, constants.FlavorSigningKeyFile)
 	viper.SetDefault(config.FlavorSigningCommonName, constants.DefaultFlavorSigningCN)
+	viper.SetDefault(config.FlavorImportTrustedCertsDir, constants.TrustedFlavorSigningCertsDir)
 
 	viper.SetDefault(config.PrivacyCaCertFile, constants.PrivacyCACertFile)
 	viper.SetDefault(config.PrivacyCaKeyFile, constants.PrivacyCAKeyFile)
@@ -61,6 +63,12 @@ func init() {
 	viper.SetDefault(config.AuditLogNumRotated, constants.DefaultNumRotated)
 	viper.SetDefault(config.AuditLogBufferSize, constants.DefaultChannelBufferSize)
 
+	// set default for event notifications
+	viper.SetDefault(config.EventsBufferSize, events.DefaultBufferSize)
+	viper.SetDefault(config.EventsMaxRetries, events.DefaultMaxRetries)
+	viper.SetDefault(config.EventsRetryInterval, events.DefaultRetryInterval)
+	viper.SetDefault(config.EventsTimeout, events.DefaultTimeout)
+
 	// set default value for aik
 	viper.SetDefault(config.AikCertValidity, constants.DefaultAikCertificateValidity)
 
@@ -95,6 +103,7 @@ func init() {
 	viper.SetDefault(constants.FvsHostTrustCacheThreshold, constants.DefaultHostTrustCacheThreshold)
 
 	viper.SetDefault(constants.HrrsRefreshPeriod, hrrs.DefaultRefreshPeriod)
+	viper.SetDefault(constants.HrrsSchedulePollPeriod, hrrs.DefaultSchedulePollPeriod)
 
 	viper.SetDefault(constants.VcssRefreshPeriod, constants.DefaultVcssRefreshPeriod)
 }
@@ -115,6 +124,12 @@ func defaultConfig() *config.Configuration {
 			NumRotated:  viper.GetInt(config.AuditLogNumRotated),
 			BufferSize:  viper.GetInt(config.AuditLogBufferSize),
 		},
+		Events: events.EventsConfig{
+			BufferSize:    viper.GetInt(config.EventsBufferSize),
+			MaxRetries:    viper.GetInt(config.EventsMaxRetries),
+			RetryInterval: viper.GetDuration(config.EventsRetryInterval),
+			Timeout:       viper.GetDuration(config.EventsTimeout),
+		},
 		HVS: commConfig.ServiceConfig{
 			Username: viper.GetString(config.HvsServiceUsername),
 			Password: viper.GetString(config.HvsServicePassword),
@@ -139,6 +154,9 @@ func defaultConfig() *config.Configuration {
 			KeyFile:    viper.GetString(config.FlavorSigningKeyFile),
 			CommonName: viper.GetString(config.FlavorSigningCommonName),
 		},
+		FlavorImport: config.FlavorImportConfig{
+			TrustedSigningCertsDir: viper.GetString(config.FlavorImportTrustedCertsDir),
+		},
 		PrivacyCA: commConfig.SelfSignedCertConfig{
 			CertFile:     viper.GetString(config.PrivacyCaCertFile),
 			KeyFile:      viper.GetString(config.PrivacyCaKeyFile),
@@ -166,7 +184,8 @@ func defaultConfig() *config.Configuration {
 			Level:        viper.GetString(commConfig.LogLevel),
 		},
 		HRRS: hrrs.HRRSConfig{
-			RefreshPeriod: viper.GetDuration(constants.HrrsRefreshPeriod),
+			RefreshPeriod:      viper.GetDuration(constants.HrrsRefreshPeriod),
+			SchedulePollPeriod: viper.GetDuration(constants.HrrsSchedulePollPeriod),
 		},
 		VCSS: config.VCSSConfig{
 			RefreshPeriod: viper.GetDuration(constants.VcssRefreshPeriod),
@@ -23,6 +23,7 @@ type HostTrustVerifierConfig struct {
 	SamlIssuerConfig                saml.IssuerConfiguration
 	SkipFlavorSignatureVerification bool
 	HostTrustCache                  *lru.Cache
+	AttestationPolicyStore          AttestationPolicyStore
 }
 
 type HostTrustMgrConfig struct {
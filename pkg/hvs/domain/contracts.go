@@ -152,6 +152,10 @@ type (
 
 		//Process all records stuck in queue post service restart
 		ProcessQueue() error
+
+		// EvaluateFlavors returns the trust report of the host data against the flavorgroups and candidate flavors
+		// without storing a report or updating any of the trust caches
+		EvaluateFlavors(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroups []hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error)
 	}
 
 	HostDataReceiver interface {
@@ -174,6 +178,7 @@ type (
 
 	HostTrustVerifier interface {
 		Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData bool, preferHashMatch bool) (*models.HVSReport, error)
+		Evaluate(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroups []hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error)
 	}
 
 	AuditLogWriter interface {
@@ -187,7 +192,35 @@ type (
 	AuditLogEntryStore interface {
 		Create(*models.AuditLogEntry) (*models.AuditLogEntry, error)
 		Retrieve(*models.AuditLogEntry) ([]models.AuditLogEntry, error)
+		// Search returns audit log entries across the audit_log_entry table and all of its rotated partitions
+		Search(*models.AuditLogFilterCriteria) ([]models.AuditLogEntry, error)
 		Update(*models.AuditLogEntry) (*models.AuditLogEntry, error)
 		Delete(uuid.UUID) error
 	}
+
+	EventPublisher interface {
+		// queues the event for delivery to all subscribers of its type
+		Publish(*hvs.Event)
+		Stop()
+	}
+
+	SubscriptionStore interface {
+		Create(*hvs.Subscription) (*hvs.Subscription, error)
+		Retrieve(uuid.UUID) (*hvs.Subscription, error)
+		Search(*models.SubscriptionFilterCriteria) ([]hvs.Subscription, error)
+		Delete(uuid.UUID) error
+	}
+
+	EventDeadLetterStore interface {
+		Create(*hvs.EventDeadLetter) (*hvs.EventDeadLetter, error)
+		Search(*models.EventDeadLetterFilterCriteria) ([]hvs.EventDeadLetter, error)
+	}
+
+	AttestationPolicyStore interface {
+		// Update creates the attestation policy of the flavorgroup or replaces the existing one
+		Update(*hvs.AttestationPolicy) (*hvs.AttestationPolicy, error)
+		Retrieve(uuid.UUID) (*hvs.AttestationPolicy, error)
+		Search(*models.AttestationPolicyFilterCriteria) ([]hvs.AttestationPolicy, error)
+		Delete(uuid.UUID) error
+	}
 )
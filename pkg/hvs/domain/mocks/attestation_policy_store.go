@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockAttestationPolicyStore provides a mocked implementation of interface domain.AttestationPolicyStore
+type MockAttestationPolicyStore struct {
+	lock     sync.Mutex
+	policies map[uuid.UUID]hvs.AttestationPolicy
+}
+
+// Update creates or replaces an AttestationPolicy
+func (store *MockAttestationPolicyStore) Update(ap *hvs.AttestationPolicy) (*hvs.AttestationPolicy, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	ap.Updated = time.Now()
+	store.policies[ap.FlavorgroupId] = *ap
+	return ap, nil
+}
+
+// Retrieve returns the AttestationPolicy of a flavorgroup
+func (store *MockAttestationPolicyStore) Retrieve(flavorgroupId uuid.UUID) (*hvs.AttestationPolicy, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	if ap, ok := store.policies[flavorgroupId]; ok {
+		return &ap, nil
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
+// Search returns the AttestationPolicies matching the filter criteria
+func (store *MockAttestationPolicyStore) Search(criteria *models.AttestationPolicyFilterCriteria) ([]hvs.AttestationPolicy, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	result := []hvs.AttestationPolicy{}
+	for _, ap := range store.policies {
+		if criteria != nil {
+			if criteria.Scheduled && ap.RefreshPeriodSeconds == 0 {
+				continue
+			}
+			if len(criteria.FlavorgroupIds) > 0 && !containsUUID(criteria.FlavorgroupIds, ap.FlavorgroupId) {
+				continue
+			}
+		}
+		result = append(result, ap)
+	}
+	return result, nil
+}
+
+// Delete deletes the AttestationPolicy of a flavorgroup
+func (store *MockAttestationPolicyStore) Delete(flavorgroupId uuid.UUID) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	delete(store.policies, flavorgroupId)
+	return nil
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// NewMockAttestationPolicyStore initializes the mock attestation policy store with the given policies
+func NewMockAttestationPolicyStore(policies ...hvs.AttestationPolicy) *MockAttestationPolicyStore {
+	store := &MockAttestationPolicyStore{policies: make(map[uuid.UUID]hvs.AttestationPolicy)}
+	for _, ap := range policies {
+		store.policies[ap.FlavorgroupId] = ap
+	}
+	return store
+}
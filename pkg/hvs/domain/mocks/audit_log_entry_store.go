@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/pkg/errors"
+)
+
+// MockAuditLogEntryStore provides a mocked implementation of interface domain.AuditLogEntryStore
+type MockAuditLogEntryStore struct {
+	auditLogEntryStore []models.AuditLogEntry
+}
+
+// Create inserts an AuditLogEntry
+func (store *MockAuditLogEntryStore) Create(entry *models.AuditLogEntry) (*models.AuditLogEntry, error) {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	entry.RowId = len(store.auditLogEntryStore) + 1
+	store.auditLogEntryStore = append(store.auditLogEntryStore, *entry)
+	return entry, nil
+}
+
+// Retrieve returns the AuditLogEntries matching the non-empty fields of the given entry
+func (store *MockAuditLogEntryStore) Retrieve(entry *models.AuditLogEntry) ([]models.AuditLogEntry, error) {
+	var entries []models.AuditLogEntry
+	for _, e := range store.auditLogEntryStore {
+		if (entry.ID == uuid.Nil || entry.ID == e.ID) &&
+			(entry.EntityID == uuid.Nil || entry.EntityID == e.EntityID) &&
+			(entry.EntityType == "" || entry.EntityType == e.EntityType) &&
+			(entry.Action == "" || entry.Action == e.Action) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// Search returns a collection of AuditLogEntries filtered as per AuditLogFilterCriteria
+func (store *MockAuditLogEntryStore) Search(criteria *models.AuditLogFilterCriteria) ([]models.AuditLogEntry, error) {
+	entries := []models.AuditLogEntry{}
+	for _, e := range store.auditLogEntryStore {
+		if criteria.EntityID != uuid.Nil && criteria.EntityID != e.EntityID {
+			continue
+		}
+		if criteria.EntityType != "" && criteria.EntityType != e.EntityType {
+			continue
+		}
+		if criteria.Action != "" && criteria.Action != e.Action {
+			continue
+		}
+		if !criteria.FromDate.IsZero() && e.CreatedAt.Before(criteria.FromDate) {
+			continue
+		}
+		if !criteria.ToDate.IsZero() && e.CreatedAt.After(criteria.ToDate) {
+			continue
+		}
+		if e.RowId <= criteria.AfterId {
+			continue
+		}
+		entries = append(entries, e)
+		if criteria.Limit > 0 && len(entries) == criteria.Limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// Update modifies an AuditLogEntry
+func (store *MockAuditLogEntryStore) Update(entry *models.AuditLogEntry) (*models.AuditLogEntry, error) {
+	for i, e := range store.auditLogEntryStore {
+		if e.ID == entry.ID {
+			store.auditLogEntryStore[i] = *entry
+			return entry, nil
+		}
+	}
+	return nil, errors.New(commErr.RecordNotFound)
+}
+
+// Delete deletes an AuditLogEntry
+func (store *MockAuditLogEntryStore) Delete(id uuid.UUID) error {
+	for i, e := range store.auditLogEntryStore {
+		if e.ID == id {
+			store.auditLogEntryStore = append(store.auditLogEntryStore[:i], store.auditLogEntryStore[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New(commErr.RecordNotFound)
+}
+
+// NewMockAuditLogEntryStore provides two report entries and one host_status entry
+func NewMockAuditLogEntryStore() *MockAuditLogEntryStore {
+	store := &MockAuditLogEntryStore{}
+	now := time.Now().UTC()
+	_, _ = store.Create(&models.AuditLogEntry{
+		ID:         uuid.MustParse("8d3b8a66-5c4a-4e32-8b2a-d0c6e0f8a001"),
+		EntityID:   uuid.MustParse("6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001"),
+		EntityType: "report",
+		CreatedAt:  now.AddDate(0, 0, -3),
+		Action:     "update",
+		Data:       models.AuditTableData{Columns: []models.AuditColumnData{{Name: "id", Value: "6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001"}}},
+	})
+	_, _ = store.Create(&models.AuditLogEntry{
+		ID:         uuid.MustParse("8d3b8a66-5c4a-4e32-8b2a-d0c6e0f8a002"),
+		EntityID:   uuid.MustParse("6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001"),
+		EntityType: "report",
+		CreatedAt:  now.AddDate(0, 0, -1),
+		Action:     "delete",
+		Data:       models.AuditTableData{Columns: []models.AuditColumnData{{Name: "id", Value: "6f7d4b2e-0a3b-4d3f-9c59-5c7d1e26a001"}}},
+	})
+	_, _ = store.Create(&models.AuditLogEntry{
+		ID:         uuid.MustParse("8d3b8a66-5c4a-4e32-8b2a-d0c6e0f8a003"),
+		EntityID:   uuid.MustParse("afed7372-18c3-42af-bd9a-70b7f44c11ad"),
+		EntityType: "host_status",
+		CreatedAt:  now.AddDate(0, 0, -1),
+		Action:     "update",
+		Data:       models.AuditTableData{Columns: []models.AuditColumnData{{Name: "id", Value: "afed7372-18c3-42af-bd9a-70b7f44c11ad"}}},
+	})
+	return store
+}
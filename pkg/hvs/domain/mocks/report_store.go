@@ -22,6 +22,8 @@ import (
 // MockReportStore provides a mocked implementation of interface postgres.ReportStore
 type MockReportStore struct {
 	reportStore map[uuid.UUID]models.HVSReport
+	// reportHistory holds the created reports like the audit log
+	reportHistory map[uuid.UUID]models.HVSReport
 }
 
 // Create inserts a HVSReport
@@ -34,12 +36,19 @@ func (store *MockReportStore) Create(report *models.HVSReport) (*models.HVSRepor
 		report.ID = newUuid
 	}
 	store.reportStore[report.ID] = *report
+	store.reportHistory[report.ID] = *report
 	return report, nil
 }
 
+// Update replaces the report of the host with a new report
 func (store *MockReportStore) Update(report *models.HVSReport) (*models.HVSReport, error) {
-	store.reportStore[report.ID] = *report
-	return report, nil
+	for id, r := range store.reportStore {
+		if r.HostID == report.HostID {
+			delete(store.reportStore, id)
+		}
+	}
+	report.ID = uuid.Nil
+	return store.Create(report)
 }
 
 // Retrieve returns HVSReport
@@ -71,7 +80,11 @@ func (store *MockReportStore) Search(criteria *models.ReportFilterCriteria) ([]m
 	var reports []models.HVSReport
 	var hosts []*hvs.Host
 	var hostStatuses []hvs.HostStatus
-	if criteria.ID != uuid.Nil {
+	if criteria.ID != uuid.Nil && !criteria.LatestPerHost {
+		if r, found := store.reportHistory[criteria.ID]; found {
+			reports = append(reports, r)
+		}
+	} else if criteria.ID != uuid.Nil {
 		r, _ := store.Retrieve(criteria.ID)
 		if r != nil {
 			reports = append(reports, *r)
@@ -146,6 +159,7 @@ func NewMockReportStore() *MockReportStore {
 	//TODO add more data
 	store := &MockReportStore{}
 	store.reportStore = make(map[uuid.UUID]models.HVSReport)
+	store.reportHistory = make(map[uuid.UUID]models.HVSReport)
 	saml1text, _ := ioutil.ReadFile("../domain/mocks/resources/saml_report")
 	trustReportBytes, _ := ioutil.ReadFile("../domain/mocks/resources/trust_report.json")
 	var trustReport hvs.TrustReport
@@ -184,5 +198,6 @@ func NewMockReportStore() *MockReportStore {
 func NewEmptyMockReportStore() domain.ReportStore {
 	store := MockReportStore{}
 	store.reportStore = make(map[uuid.UUID]models.HVSReport)
+	store.reportHistory = make(map[uuid.UUID]models.HVSReport)
 	return &store
 }
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+// MockSubscriptionStore provides a mocked implementation of interface domain.SubscriptionStore
+type MockSubscriptionStore struct {
+	lock          sync.Mutex
+	subscriptions []hvs.Subscription
+}
+
+// Create inserts a Subscription
+func (store *MockSubscriptionStore) Create(sub *hvs.Subscription) (*hvs.Subscription, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	sub.ID = uuid.New()
+	sub.Created = time.Now()
+	sub.RowId = len(store.subscriptions) + 1
+	store.subscriptions = append(store.subscriptions, *sub)
+	return sub, nil
+}
+
+// Retrieve returns a single Subscription record from the store
+func (store *MockSubscriptionStore) Retrieve(id uuid.UUID) (*hvs.Subscription, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, sub := range store.subscriptions {
+		if sub.ID == id {
+			return &sub, nil
+		}
+	}
+	return nil, errors.New(commErr.RowsNotFound)
+}
+
+// Search returns the Subscriptions matching the filter criteria
+func (store *MockSubscriptionStore) Search(criteria *models.SubscriptionFilterCriteria) ([]hvs.Subscription, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	result := []hvs.Subscription{}
+	for _, sub := range store.subscriptions {
+		if criteria != nil {
+			if criteria.URL != "" && sub.URL != criteria.URL {
+				continue
+			}
+			if criteria.EventType != "" && !containsEventType(sub.EventTypes, criteria.EventType) {
+				continue
+			}
+		}
+		result = append(result, sub)
+	}
+	return result, nil
+}
+
+// Delete deletes a Subscription from the store
+func (store *MockSubscriptionStore) Delete(id uuid.UUID) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for i, sub := range store.subscriptions {
+		if sub.ID == id {
+			store.subscriptions = append(store.subscriptions[:i], store.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New(commErr.RowsNotFound)
+}
+
+func containsEventType(eventTypes []hvs.EventType, eventType hvs.EventType) bool {
+	for _, et := range eventTypes {
+		if et == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewMockSubscriptionStore initializes the mock subscription store with one subscription
+func NewMockSubscriptionStore() *MockSubscriptionStore {
+	store := &MockSubscriptionStore{}
+	store.subscriptions = []hvs.Subscription{
+		{
+			ID:         uuid.MustParse("1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11"),
+			URL:        "https://soc.example.com/hvs-events",
+			EventTypes: []hvs.EventType{hvs.EventTypeHostUntrusted, hvs.EventTypeHostConnectionFailure},
+			Secret:     "encrypted-secret",
+			Created:    time.Now().Add(-time.Hour),
+			RowId:      1,
+		},
+	}
+	return store
+}
+
+// MockEventDeadLetterStore provides a mocked implementation of interface domain.EventDeadLetterStore
+type MockEventDeadLetterStore struct {
+	lock        sync.Mutex
+	deadLetters []hvs.EventDeadLetter
+}
+
+// Create inserts an EventDeadLetter
+func (store *MockEventDeadLetterStore) Create(dl *hvs.EventDeadLetter) (*hvs.EventDeadLetter, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	dl.ID = uuid.New()
+	dl.Created = time.Now()
+	store.deadLetters = append(store.deadLetters, *dl)
+	return dl, nil
+}
+
+// Search returns the EventDeadLetters matching the filter criteria
+func (store *MockEventDeadLetterStore) Search(criteria *models.EventDeadLetterFilterCriteria) ([]hvs.EventDeadLetter, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	result := []hvs.EventDeadLetter{}
+	for _, dl := range store.deadLetters {
+		if criteria != nil && criteria.SubscriptionID != uuid.Nil && dl.SubscriptionID != criteria.SubscriptionID {
+			continue
+		}
+		result = append(result, dl)
+		if criteria != nil && criteria.Limit > 0 && len(result) == criteria.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// NewMockEventDeadLetterStore initializes the mock dead letter store with one record
+func NewMockEventDeadLetterStore() *MockEventDeadLetterStore {
+	store := &MockEventDeadLetterStore{}
+	store.deadLetters = []hvs.EventDeadLetter{
+		{
+			ID:             uuid.New(),
+			SubscriptionID: uuid.MustParse("1f4e5c7a-2b7d-4c84-9d6a-0a6e5e2b9c11"),
+			Event: hvs.Event{
+				ID:         uuid.New(),
+				Type:       hvs.EventTypeHostUntrusted,
+				Created:    time.Now().Add(-time.Minute),
+				EntityID:   uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"),
+				EntityType: hvs.EventEntityTypeHost,
+			},
+			Attempts:  6,
+			LastError: "subscriber responded with status 503",
+			Created:   time.Now(),
+		},
+	}
+	return store
+}
@@ -0,0 +1,15 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import "github.com/google/uuid"
+
+// AttestationPolicyFilterCriteria holds the filter criteria for searching the attestation policies of flavorgroups
+type AttestationPolicyFilterCriteria struct {
+	FlavorgroupIds []uuid.UUID
+	// Scheduled only returns the policies with a refresh period
+	Scheduled bool
+}
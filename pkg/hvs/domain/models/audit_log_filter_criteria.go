@@ -0,0 +1,23 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogFilterCriteria holds the filter criteria for the Search AuditLogs API
+type AuditLogFilterCriteria struct {
+	EntityID   uuid.UUID
+	EntityType string
+	Action     string
+	FromDate   time.Time
+	ToDate     time.Time
+	Limit      int
+	AfterId    int
+}
@@ -0,0 +1,23 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+)
+
+// SubscriptionFilterCriteria holds the filter criteria for the Search Subscriptions API
+type SubscriptionFilterCriteria struct {
+	EventType hvs.EventType
+	URL       string
+}
+
+// EventDeadLetterFilterCriteria holds the filter criteria for the Search dead letters API
+type EventDeadLetterFilterCriteria struct {
+	SubscriptionID uuid.UUID
+	Limit          int
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// metrics package defines the Prometheus metrics of the HVS host data fetch and flavor verification pipeline
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/host-connector/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/host-connector/util"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "hvs"
+
+// Names of the work queues whose depth is exposed
+const (
+	// QueueHostDataFetch holds the hosts waiting for a host-fetcher worker
+	QueueHostDataFetch = "host_data_fetch"
+	// QueueFlavorVerify holds the hosts waiting for a verifier to verify their stored host data
+	QueueFlavorVerify = "flavor_verify"
+	// QueueFetchedDataVerify holds the newly fetched host data waiting for a verifier
+	QueueFetchedDataVerify = "fetched_data_verify"
+)
+
+// Trust results of the host verifications
+const (
+	ResultTrusted   = "trusted"
+	ResultUntrusted = "untrusted"
+	ResultError     = "error"
+)
+
+var (
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "work_queue_depth",
+		Help:      "Number of items waiting in the host trust work queues",
+	}, []string{"queue"})
+
+	fetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "host_data_fetch_duration_seconds",
+		Help:      "Time taken to fetch the host manifest, per connector type",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"connector"})
+
+	verifyDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "flavor_verification_duration_seconds",
+		Help:      "Time taken to verify the host manifest against the flavors of the host",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	})
+
+	trustResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "host_trust_results_total",
+		Help:      "Number of host verifications, per trust result",
+	}, []string{"result"})
+
+	hostStates = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "hosts",
+		Help:      "Number of hosts per state, as last determined by the host data fetcher since the service started",
+	}, []string{"state"})
+
+	hostStatesMtx sync.Mutex
+	lastHostState = map[uuid.UUID]hvs.HostState{}
+)
+
+func init() {
+	prometheus.MustRegister(queueDepth, fetchDuration, verifyDuration, trustResults, hostStates)
+}
+
+// QueueAdded records an item submitted to the work queue
+func QueueAdded(queue string) {
+	queueDepth.WithLabelValues(queue).Inc()
+}
+
+// QueueRemoved records an item pulled out of the work queue by a worker
+func QueueRemoved(queue string) {
+	queueDepth.WithLabelValues(queue).Dec()
+}
+
+// ObserveHostDataFetch records the duration of a host manifest fetch from the host with the connection string
+func ObserveHostDataFetch(connectionString string, start time.Time) {
+	fetchDuration.WithLabelValues(connectorType(connectionString)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveFlavorVerification records the duration and the trust result of a host verification. Verifications that did
+// not produce a new report are only recorded in the duration.
+func ObserveFlavorVerification(start time.Time, report *models.HVSReport, err error) {
+	verifyDuration.Observe(time.Since(start).Seconds())
+	switch {
+	case err != nil:
+		trustResults.WithLabelValues(ResultError).Inc()
+	case report == nil:
+	case report.TrustReport.Trusted:
+		trustResults.WithLabelValues(ResultTrusted).Inc()
+	default:
+		trustResults.WithLabelValues(ResultUntrusted).Inc()
+	}
+}
+
+// SetHostState records the state of the host, moving it out of its previous state
+func SetHostState(hostId uuid.UUID, state hvs.HostState) {
+	hostStatesMtx.Lock()
+	defer hostStatesMtx.Unlock()
+
+	if prevState, ok := lastHostState[hostId]; ok {
+		if prevState == state {
+			return
+		}
+		hostStates.WithLabelValues(prevState.String()).Dec()
+	}
+	lastHostState[hostId] = state
+	hostStates.WithLabelValues(state.String()).Inc()
+}
+
+// RemoveHost stops counting the host in its last known state
+func RemoveHost(hostId uuid.UUID) {
+	hostStatesMtx.Lock()
+	defer hostStatesMtx.Unlock()
+
+	if prevState, ok := lastHostState[hostId]; ok {
+		hostStates.WithLabelValues(prevState.String()).Dec()
+		delete(lastHostState, hostId)
+	}
+}
+
+func connectorType(connectionString string) string {
+	vendor := util.GetVendorPrefix(connectionString)
+	if vendor == constants.VendorUnknown {
+		vendor = util.GuessVendorFromURL(connectionString)
+	}
+	return strings.ToLower(vendor.String())
+}
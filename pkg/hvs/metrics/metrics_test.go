@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSetHostState(t *testing.T) {
+	connected := hostStates.WithLabelValues(hvs.HostStateConnected.String())
+	unknown := hostStates.WithLabelValues(hvs.HostStateUnknown.String())
+	baseConnected, baseUnknown := testutil.ToFloat64(connected), testutil.ToFloat64(unknown)
+
+	hostId := uuid.New()
+	SetHostState(hostId, hvs.HostStateConnected)
+	SetHostState(hostId, hvs.HostStateConnected)
+	if got := testutil.ToFloat64(connected) - baseConnected; got != 1 {
+		t.Fatalf("SetHostState() connected hosts = %v, want 1", got)
+	}
+
+	SetHostState(hostId, hvs.HostStateUnknown)
+	if got := testutil.ToFloat64(connected) - baseConnected; got != 0 {
+		t.Errorf("SetHostState() connected hosts = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(unknown) - baseUnknown; got != 1 {
+		t.Errorf("SetHostState() unknown hosts = %v, want 1", got)
+	}
+
+	RemoveHost(hostId)
+	if got := testutil.ToFloat64(unknown) - baseUnknown; got != 0 {
+		t.Errorf("RemoveHost() unknown hosts = %v, want 0", got)
+	}
+}
+
+func TestObserveFlavorVerification(t *testing.T) {
+	trusted := trustResults.WithLabelValues(ResultTrusted)
+	untrusted := trustResults.WithLabelValues(ResultUntrusted)
+	failed := trustResults.WithLabelValues(ResultError)
+	baseTrusted, baseUntrusted, baseFailed := testutil.ToFloat64(trusted), testutil.ToFloat64(untrusted), testutil.ToFloat64(failed)
+
+	start := time.Now()
+	ObserveFlavorVerification(start, &models.HVSReport{TrustReport: hvs.TrustReport{Trusted: true}}, nil)
+	ObserveFlavorVerification(start, &models.HVSReport{TrustReport: hvs.TrustReport{Trusted: false}}, nil)
+	ObserveFlavorVerification(start, nil, errors.New("verification failed"))
+	ObserveFlavorVerification(start, nil, nil)
+
+	for _, c := range []struct {
+		result string
+		got    float64
+	}{
+		{ResultTrusted, testutil.ToFloat64(trusted) - baseTrusted},
+		{ResultUntrusted, testutil.ToFloat64(untrusted) - baseUntrusted},
+		{ResultError, testutil.ToFloat64(failed) - baseFailed},
+	} {
+		if c.got != 1 {
+			t.Errorf("ObserveFlavorVerification() %s results = %v, want 1", c.result, c.got)
+		}
+	}
+}
+
+func TestConnectorType(t *testing.T) {
+	tests := map[string]string{
+		"intel:https://ta.server.com:1443":                                        "intel",
+		"https://ta.server.com:1443":                                              "intel",
+		"vmware:https://vcenter.server.com:443/sdk;h=host.server.com;u=user;p=pw": "vmware",
+		"https://vcenter.server.com:443/sdk;h=host.server.com":                    "vmware",
+		"microsoft:https://ta.server.com:1443":                                    "microsoft",
+	}
+	for connectionString, want := range tests {
+		if got := connectorType(connectionString); got != want {
+			t.Errorf("connectorType(%s) = %s, want %s", connectionString, got, want)
+		}
+	}
+}
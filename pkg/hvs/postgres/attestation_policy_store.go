@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+type AttestationPolicyStore struct {
+	Store *DataStore
+}
+
+func NewAttestationPolicyStore(store *DataStore) *AttestationPolicyStore {
+	return &AttestationPolicyStore{Store: store}
+}
+
+// Update creates the attestation policy of the flavorgroup or replaces the existing one
+func (s *AttestationPolicyStore) Update(ap *hvs.AttestationPolicy) (*hvs.AttestationPolicy, error) {
+	defaultLog.Trace("postgres/attestation_policy_store:Update() Entering")
+	defer defaultLog.Trace("postgres/attestation_policy_store:Update() Leaving")
+
+	ap.Updated = time.Now()
+	dbPolicy := attestationPolicy{
+		FlavorgroupId:       ap.FlavorgroupId,
+		RefreshPeriod:       ap.RefreshPeriodSeconds,
+		Jitter:              ap.JitterSeconds,
+		SamlValiditySeconds: ap.SamlValiditySeconds,
+		UpdatedAt:           ap.Updated,
+	}
+	if err := s.Store.Db.Save(&dbPolicy).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/attestation_policy_store:Update() failed to save attestation policy")
+	}
+	return ap, nil
+}
+
+// Retrieve fetches the attestation policy of the flavorgroup with the given id
+func (s *AttestationPolicyStore) Retrieve(flavorgroupId uuid.UUID) (*hvs.AttestationPolicy, error) {
+	defaultLog.Trace("postgres/attestation_policy_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/attestation_policy_store:Retrieve() Leaving")
+
+	ap := hvs.AttestationPolicy{}
+	row := s.Store.Db.Model(&attestationPolicy{}).Select("flavorgroup_id, refresh_period, jitter, saml_validity_seconds, updated").Where(&attestationPolicy{FlavorgroupId: flavorgroupId}).Row()
+	if err := row.Scan(&ap.FlavorgroupId, &ap.RefreshPeriodSeconds, &ap.JitterSeconds, &ap.SamlValiditySeconds, &ap.Updated); err != nil {
+		return nil, errors.Wrap(err, "postgres/attestation_policy_store:Retrieve() failed to scan record")
+	}
+	return &ap, nil
+}
+
+// Search returns the attestation policies matching the filter criteria
+func (s *AttestationPolicyStore) Search(criteria *models.AttestationPolicyFilterCriteria) ([]hvs.AttestationPolicy, error) {
+	defaultLog.Trace("postgres/attestation_policy_store:Search() Entering")
+	defer defaultLog.Trace("postgres/attestation_policy_store:Search() Leaving")
+
+	tx := s.Store.Db.Model(&attestationPolicy{}).Select("flavorgroup_id, refresh_period, jitter, saml_validity_seconds, updated")
+	if criteria != nil {
+		if len(criteria.FlavorgroupIds) > 0 {
+			tx = tx.Where("flavorgroup_id IN (?)", criteria.FlavorgroupIds)
+		}
+		if criteria.Scheduled {
+			tx = tx.Where("refresh_period > 0")
+		}
+	}
+
+	rows, err := tx.Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/attestation_policy_store:Search() failed to retrieve records from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("postgres/attestation_policy_store:Search() Error closing rows")
+		}
+	}()
+
+	policies := []hvs.AttestationPolicy{}
+	for rows.Next() {
+		ap := hvs.AttestationPolicy{}
+		if err := rows.Scan(&ap.FlavorgroupId, &ap.RefreshPeriodSeconds, &ap.JitterSeconds, &ap.SamlValiditySeconds, &ap.Updated); err != nil {
+			return nil, errors.Wrap(err, "postgres/attestation_policy_store:Search() failed to scan record")
+		}
+		policies = append(policies, ap)
+	}
+	return policies, nil
+}
+
+// Delete removes the attestation policy of the flavorgroup
+func (s *AttestationPolicyStore) Delete(flavorgroupId uuid.UUID) error {
+	defaultLog.Trace("postgres/attestation_policy_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/attestation_policy_store:Delete() Leaving")
+
+	if err := s.Store.Db.Delete(&attestationPolicy{FlavorgroupId: flavorgroupId}).Error; err != nil {
+		return errors.Wrap(err, "postgres/attestation_policy_store:Delete() failed to delete attestation policy")
+	}
+	return nil
+}
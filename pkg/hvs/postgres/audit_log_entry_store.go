@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
 	"github.com/pkg/errors"
@@ -142,3 +143,60 @@ func (as *auditLogEntryStore) FindBetweenTime(from, to time.Time) ([]models.Audi
 	}
 	return ret, nil
 }
+
+// Search returns the audit log entries matching the given filter criteria. The rotated audit_log_entry_N
+// partitions inherit from audit_log_entry, so querying the parent table spans all of them.
+func (as *auditLogEntryStore) Search(criteria *models.AuditLogFilterCriteria) ([]models.AuditLogEntry, error) {
+	defaultLog.Trace("postgres/audit_log_entry_store_store:Search() Entering")
+	defer defaultLog.Trace("postgres/audit_log_entry_store_store:Search() Leaving")
+
+	tx := as.store.Db.Model(&auditLogEntry{})
+	if tx == nil {
+		return nil, errors.New("postgres/audit_log_entry_store_store:Search() Unexpected Error. Could not build" +
+			" a gorm query object in AuditLogEntry Search function.")
+	}
+
+	if criteria != nil {
+		if criteria.EntityID != uuid.Nil {
+			tx = tx.Where("entity_id = ?", criteria.EntityID)
+		}
+		if criteria.EntityType != "" {
+			tx = tx.Where("entity_type = ?", criteria.EntityType)
+		}
+		if criteria.Action != "" {
+			tx = tx.Where("action = ?", criteria.Action)
+		}
+		if !criteria.FromDate.IsZero() {
+			tx = tx.Where("CAST(created AS TIMESTAMP) >= CAST(? AS TIMESTAMP)", criteria.FromDate.Format(constants.ParamDateTimeFormatUTC))
+		}
+		if !criteria.ToDate.IsZero() {
+			tx = tx.Where("CAST(created AS TIMESTAMP) <= CAST(? AS TIMESTAMP)", criteria.ToDate.Format(constants.ParamDateTimeFormatUTC))
+		}
+		if criteria.AfterId > 0 {
+			tx = tx.Where("rowid > ?", criteria.AfterId)
+		}
+		if criteria.Limit > 0 {
+			tx = tx.Limit(criteria.Limit)
+		}
+	}
+	tx = tx.Order("rowid asc")
+
+	var matchEntries []auditLogEntry
+	if err := tx.Find(&matchEntries).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/audit_log_entry_store_store:Search() failed to retrieve records from db")
+	}
+
+	ret := []models.AuditLogEntry{}
+	for _, e := range matchEntries {
+		ret = append(ret, models.AuditLogEntry{
+			RowId:      e.Rowid,
+			ID:         e.ID,
+			EntityID:   e.EntityID,
+			EntityType: e.EntityType,
+			CreatedAt:  e.CreatedAt,
+			Action:     e.Action,
+			Data:       models.AuditTableData(e.Data),
+		})
+	}
+	return ret, nil
+}
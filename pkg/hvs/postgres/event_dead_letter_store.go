@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+type EventDeadLetterStore struct {
+	Store *DataStore
+}
+
+func NewEventDeadLetterStore(store *DataStore) *EventDeadLetterStore {
+	return &EventDeadLetterStore{Store: store}
+}
+
+// Create records an event that could not be delivered to a subscriber
+func (d *EventDeadLetterStore) Create(dl *hvs.EventDeadLetter) (*hvs.EventDeadLetter, error) {
+	defaultLog.Trace("postgres/event_dead_letter_store:Create() Entering")
+	defer defaultLog.Trace("postgres/event_dead_letter_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/event_dead_letter_store:Create() failed to create new UUID")
+	}
+	dl.ID = newUuid
+	dl.Created = time.Now()
+	dbDeadLetter := eventDeadLetter{
+		ID:             dl.ID,
+		SubscriptionID: dl.SubscriptionID,
+		Event:          PGEvent(dl.Event),
+		Attempts:       dl.Attempts,
+		LastError:      dl.LastError,
+		CreatedAt:      dl.Created,
+	}
+	if err := d.Store.Db.Create(&dbDeadLetter).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/event_dead_letter_store:Create() failed to create dead letter")
+	}
+	return dl, nil
+}
+
+// Search returns the dead letters matching the filter criteria, most recent first
+func (d *EventDeadLetterStore) Search(criteria *models.EventDeadLetterFilterCriteria) ([]hvs.EventDeadLetter, error) {
+	defaultLog.Trace("postgres/event_dead_letter_store:Search() Entering")
+	defer defaultLog.Trace("postgres/event_dead_letter_store:Search() Leaving")
+
+	tx := d.Store.Db.Model(&eventDeadLetter{}).Select("id, subscription_id, event, attempts, last_error, created")
+	if criteria != nil {
+		if criteria.SubscriptionID != uuid.Nil {
+			tx = tx.Where("subscription_id = ?", criteria.SubscriptionID)
+		}
+		if criteria.Limit > 0 {
+			tx = tx.Limit(criteria.Limit)
+		}
+	}
+
+	rows, err := tx.Order("rowid desc").Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/event_dead_letter_store:Search() failed to retrieve records from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("postgres/event_dead_letter_store:Search() Error closing rows")
+		}
+	}()
+
+	deadLetters := []hvs.EventDeadLetter{}
+	for rows.Next() {
+		dl := hvs.EventDeadLetter{}
+		if err := rows.Scan(&dl.ID, &dl.SubscriptionID, (*PGEvent)(&dl.Event), &dl.Attempts, &dl.LastError, &dl.Created); err != nil {
+			return nil, errors.Wrap(err, "postgres/event_dead_letter_store:Search() failed to scan record")
+		}
+		deadLetters = append(deadLetters, dl)
+	}
+	return deadLetters, nil
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
 	"github.com/jinzhu/gorm"
@@ -17,11 +18,12 @@ import (
 )
 
 type FlavorStore struct {
-	Store *DataStore
+	Store          *DataStore
+	EventPublisher domain.EventPublisher
 }
 
 func NewFlavorStore(store *DataStore) *FlavorStore {
-	return &FlavorStore{store}
+	return &FlavorStore{Store: store}
 }
 
 // create flavors
@@ -52,6 +54,17 @@ func (f *FlavorStore) Create(signedFlavor *hvs.SignedFlavor) (*hvs.SignedFlavor,
 	if err := f.Store.Db.Create(&dbf).Error; err != nil {
 		return nil, errors.Wrap(err, "postgres/flavor_store:Create() failed to create flavor")
 	}
+	if f.EventPublisher != nil {
+		f.EventPublisher.Publish(&hvs.Event{
+			Type:       hvs.EventTypeFlavorCreated,
+			EntityID:   dbf.ID,
+			EntityType: hvs.EventEntityTypeFlavor,
+			Data: map[string]interface{}{
+				"label":       dbf.Label,
+				"flavor_part": dbf.FlavorPart,
+			},
+		})
+	}
 	return signedFlavor, nil
 }
 
@@ -369,5 +382,12 @@ func (f *FlavorStore) Delete(flavorId uuid.UUID) error {
 	if err := f.Store.Db.Where(&dbFlavor).Delete(&dbFlavor).Error; err != nil {
 		return errors.Wrap(err, "postgres/flavor_store:Delete() failed to delete Flavor")
 	}
+	if f.EventPublisher != nil {
+		f.EventPublisher.Publish(&hvs.Event{
+			Type:       hvs.EventTypeFlavorDeleted,
+			EntityID:   flavorId,
+			EntityType: hvs.EventEntityTypeFlavor,
+		})
+	}
 	return nil
 }
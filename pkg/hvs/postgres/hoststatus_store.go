@@ -23,6 +23,7 @@ import (
 type HostStatusStore struct {
 	Store          *DataStore
 	AuditLogWriter domain.AuditLogWriter
+	EventPublisher domain.EventPublisher
 }
 
 func NewHostStatusStore(store *DataStore) *HostStatusStore {
@@ -166,6 +167,7 @@ func (hss *HostStatusStore) Persist(hs *hvs.HostStatus) error {
 		if err != nil {
 			return errors.Wrap(err, "postgres/hoststatus_store:Persist() - Failed to Create HostStatus record ")
 		} else {
+			hss.publishConnectionFailure(hvs.HostStateInvalid, hs)
 			return nil
 		}
 	}
@@ -194,9 +196,28 @@ func (hss *HostStatusStore) Persist(hs *hvs.HostStatus) error {
 			hss.AuditLogWriter.Log(auditEntry)
 		}
 	}
+	hss.publishConnectionFailure(oldHs.Status.HostState, hs)
 	return nil
 }
 
+// publishConnectionFailure notifies subscribers when a host enters the CONNECTION_FAILURE state
+func (hss *HostStatusStore) publishConnectionFailure(oldState hvs.HostState, hs *hvs.HostStatus) {
+	if hss.EventPublisher == nil ||
+		hs.HostStatusInformation.HostState != hvs.HostStateConnectionFailure ||
+		oldState == hvs.HostStateConnectionFailure {
+		return
+	}
+	hss.EventPublisher.Publish(&hvs.Event{
+		Type:       hvs.EventTypeHostConnectionFailure,
+		EntityID:   hs.HostID,
+		EntityType: hvs.EventEntityTypeHost,
+		Data: map[string]interface{}{
+			"host_state":          hs.HostStatusInformation.HostState.String(),
+			"last_time_connected": hs.HostStatusInformation.LastTimeConnected,
+		},
+	})
+}
+
 func (hss *HostStatusStore) Delete(hostStatusId uuid.UUID) error {
 	defaultLog.Trace("postgres/hoststatus_store:Delete() Entering")
 	defer defaultLog.Trace("postgres/hoststatus_store:Delete() Leaving")
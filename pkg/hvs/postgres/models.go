@@ -25,6 +25,8 @@ type (
 	PGHostStatusInformation hvs.HostStatusInformation
 	PGFlavorContent         hvs.Flavor
 	PGFlavorTemplateContent hvs.FlavorTemplate
+	PGEventTypes            []hvs.EventType
+	PGEvent                 hvs.Event
 
 	flavorGroup struct {
 		ID                    uuid.UUID             `json:"id" gorm:"primary_key;type:uuid"`
@@ -169,6 +171,33 @@ type (
 		NotBefore    time.Time `gorm:"not null; column:notbefore"`
 		NotAfter     time.Time `gorm:"not null; column:notafter"`
 	}
+
+	subscription struct {
+		ID         uuid.UUID    `gorm:"primary_key;type:uuid"`
+		URL        string       `gorm:"column:url;not null"`
+		EventTypes PGEventTypes `gorm:"column:event_types" sql:"type:JSONB NOT NULL"`
+		Secret     string       `gorm:"column:secret;not null"`
+		CreatedAt  time.Time    `gorm:"column:created;not null"`
+		Rowid      int          `gorm:"auto_increment;not null"`
+	}
+
+	eventDeadLetter struct {
+		ID             uuid.UUID `gorm:"primary_key;type:uuid"`
+		SubscriptionID uuid.UUID `gorm:"column:subscription_id;type:uuid REFERENCES subscription(Id) ON UPDATE CASCADE ON DELETE CASCADE;not null;index:idx_event_dead_letter_subscription_id"`
+		Event          PGEvent   `gorm:"column:event" sql:"type:JSONB NOT NULL"`
+		Attempts       int       `gorm:"column:attempts;not null"`
+		LastError      string    `gorm:"column:last_error"`
+		CreatedAt      time.Time `gorm:"column:created;not null"`
+		Rowid          int       `gorm:"auto_increment;not null"`
+	}
+
+	attestationPolicy struct {
+		FlavorgroupId       uuid.UUID `gorm:"column:flavorgroup_id;primary_key;type:uuid REFERENCES flavor_group(Id) ON UPDATE CASCADE ON DELETE CASCADE"`
+		RefreshPeriod       int       `gorm:"column:refresh_period;not null"`
+		Jitter              int       `gorm:"column:jitter;not null"`
+		SamlValiditySeconds int       `gorm:"column:saml_validity_seconds;not null"`
+		UpdatedAt           time.Time `gorm:"column:updated;not null"`
+	}
 )
 
 func (qp PGJsonStrMap) Value() (driver.Value, error) {
@@ -271,3 +300,27 @@ func (fl *PGFlavorTemplateContent) Scan(value interface{}) error {
 	}
 	return json.Unmarshal(b, &fl)
 }
+
+func (et PGEventTypes) Value() (driver.Value, error) {
+	return json.Marshal(et)
+}
+
+func (et *PGEventTypes) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGEventTypes_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &et)
+}
+
+func (e PGEvent) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+func (e *PGEvent) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGEvent_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &e)
+}
@@ -144,7 +144,7 @@ func (ds *DataStore) Migrate() {
 
 	ds.Db.AutoMigrate(flavorGroup{}, host{}, flavor{}, trustCache{}, hostuniqueFlavor{}, flavorgroupFlavor{}, hostStatus{}, esxiCluster{},
 		esxiClusterHost{}, tagCertificate{}, tpmEndorsement{}, report{}, hostCredential{}, hostFlavorgroup{}, auditLogEntry{},
-		queue{}, flavorTemplate{}, flavortemplateFlavorgroup{})
+		queue{}, flavorTemplate{}, flavortemplateFlavorgroup{}, subscription{}, eventDeadLetter{}, attestationPolicy{})
 }
 
 func (ds *DataStore) Close() {
@@ -17,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
@@ -24,6 +25,7 @@ import (
 type ReportStore struct {
 	Store          *DataStore
 	AuditLogWriter domain.AuditLogWriter
+	EventPublisher domain.EventPublisher
 	dbLock         sync.Mutex
 }
 
@@ -82,9 +84,60 @@ func (r *ReportStore) Update(re *models.HVSReport) (*models.HVSReport, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "postgres/report_store:Update() Error while creating report")
 	}
+
+	if r.EventPublisher != nil {
+		summary := newTrustReportSummary(vsReport)
+		r.EventPublisher.Publish(&hvs.Event{
+			Type:       hvs.EventTypeReportCreated,
+			EntityID:   vsReport.ID,
+			EntityType: hvs.EventEntityTypeReport,
+			Report:     summary,
+		})
+		// notify subscribers when the host is first reported or flips between trusted and untrusted
+		if len(hvsReports) == 0 || hvsReports[0].TrustReport.Trusted != vsReport.TrustReport.Trusted {
+			r.EventPublisher.Publish(newTrustEvent(vsReport, summary))
+		}
+	}
 	return vsReport, nil
 }
 
+func newTrustEvent(re *models.HVSReport, summary *hvs.TrustReportSummary) *hvs.Event {
+	eventType := hvs.EventTypeHostUntrusted
+	if re.TrustReport.Trusted {
+		eventType = hvs.EventTypeHostTrusted
+	}
+	return &hvs.Event{
+		Type:       eventType,
+		EntityID:   re.HostID,
+		EntityType: hvs.EventEntityTypeHost,
+		Data: map[string]interface{}{
+			"report_id": re.ID,
+			"trusted":   re.TrustReport.Trusted,
+		},
+		Report: summary,
+	}
+}
+
+func newTrustReportSummary(re *models.HVSReport) *hvs.TrustReportSummary {
+	tr := hvs.NewTrustReport(re.TrustReport)
+	flavorsTrust := make(map[hvs.FlavorPartName]bool)
+	for _, flavorPart := range hvs.GetFlavorTypes() {
+		if len(tr.GetResultsForMarker(flavorPart.String())) > 0 {
+			flavorsTrust[flavorPart] = tr.IsTrustedForMarker(flavorPart.String())
+		}
+	}
+	return &hvs.TrustReportSummary{
+		ReportID:     re.ID,
+		HostID:       re.HostID,
+		HostName:     re.TrustReport.HostManifest.HostInfo.HostName,
+		HardwareUUID: re.TrustReport.HostManifest.HostInfo.HardwareUUID,
+		Trusted:      re.TrustReport.Trusted,
+		FlavorsTrust: flavorsTrust,
+		Created:      re.CreatedAt,
+		Expiration:   re.Expiration,
+	}
+}
+
 // Create method creates a new record in report table
 func (r *ReportStore) Create(re *models.HVSReport) (*models.HVSReport, error) {
 	defaultLog.Trace("postgres/report_store:Create() Entering")
@@ -195,7 +248,7 @@ func (r *ReportStore) Search(criteria *models.ReportFilterCriteria) ([]models.HV
 
 		return reports, nil
 	} else {
-		tx = buildReportSearchQuery(r.Store.Db, hostID, hostHardwareUUID, hostName, hostStatus, fromDate, toDate, latestPerHost, criteria.Limit, criteria.AfterId)
+		tx = buildReportSearchQuery(r.Store.Db, reportID, hostID, hostHardwareUUID, hostName, hostStatus, fromDate, toDate, latestPerHost, criteria.Limit, criteria.AfterId)
 		if tx == nil {
 			return nil, errors.New("postgres/report_store:Search() Unexpected Error. Could not build" +
 				" a gorm query object in HVSReport Search function.")
@@ -342,8 +395,9 @@ func (r *ReportStore) Delete(reportId uuid.UUID) error {
 	return nil
 }
 
-// buildReportSearchQuery is a helper function to build the query object for a report search.
-func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostName, hostState string, fromDate, toDate time.Time, latestPerHost bool, limit int, afterId int) *gorm.DB {
+// buildReportSearchQuery is a helper function to build the query object for a report search. The reports are searched
+// in the audit log, which keeps the reports replaced by the newer reports of their host.
+func buildReportSearchQuery(tx *gorm.DB, reportID, hostHardwareID, hostID uuid.UUID, hostName, hostState string, fromDate, toDate time.Time, latestPerHost bool, limit int, afterId int) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildReportSearchQuery() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildReportSearchQuery() Leaving")
 	if tx == nil {
@@ -352,14 +406,14 @@ func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostN
 	if latestPerHost {
 		entity := "auj"
 		txSubQuery := tx.Table("audit_log_entry auj").Select("data -> 'Columns' -> 1 ->> 'Value' AS host_id, max(auj.created) AS max_date ")
-		txSubQuery = buildReportSearchQueryWithCriteria(txSubQuery, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
+		txSubQuery = buildReportSearchQueryWithCriteria(txSubQuery, reportID, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
 		txSubQuery = txSubQuery.Group("host_id")
 		subQuery := txSubQuery.SubQuery()
 		tx = tx.Table("audit_log_entry au").Select("au.*").Joins("INNER JOIN ? a ON a.host_id = au.data -> 'Columns' -> 1 ->> 'Value' AND a.max_date = au.created", subQuery)
 	} else {
 		entity := "au"
 		tx = tx.Table("audit_log_entry au").Select("au.*")
-		tx = buildReportSearchQueryWithCriteria(tx, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
+		tx = buildReportSearchQueryWithCriteria(tx, reportID, hostHardwareID, hostID, entity, hostName, hostState, fromDate, toDate)
 	}
 
 	if afterId > 0 {
@@ -372,7 +426,7 @@ func buildReportSearchQuery(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, hostN
 	return tx
 }
 
-func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid.UUID, entity, hostName string, hostState string, fromDate, toDate time.Time) *gorm.DB {
+func buildReportSearchQueryWithCriteria(tx *gorm.DB, reportID, hostHardwareID, hostID uuid.UUID, entity, hostName string, hostState string, fromDate, toDate time.Time) *gorm.DB {
 	defaultLog.Trace("postgres/report_store:buildReportSearchQueryWithCriteria() Entering")
 	defer defaultLog.Trace("postgres/report_store:buildReportSearchQueryWithCriteria() Leaving")
 
@@ -387,6 +441,10 @@ func buildReportSearchQueryWithCriteria(tx *gorm.DB, hostHardwareID, hostID uuid
 	//TODO rename after testing
 	tx = tx.Where(entity + ".entity_type = 'report'")
 
+	if reportID != uuid.Nil {
+		tx = tx.Where(entity+".entity_id = ?", reportID)
+	}
+
 	if hostName != "" {
 		tx = tx.Where("h.name = ?", hostName)
 	}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+type SubscriptionStore struct {
+	Store *DataStore
+}
+
+func NewSubscriptionStore(store *DataStore) *SubscriptionStore {
+	return &SubscriptionStore{Store: store}
+}
+
+// Create persists a subscription. The secret is stored as provided by the caller
+func (s *SubscriptionStore) Create(sub *hvs.Subscription) (*hvs.Subscription, error) {
+	defaultLog.Trace("postgres/subscription_store:Create() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/subscription_store:Create() failed to create new UUID")
+	}
+	sub.ID = newUuid
+	sub.Created = time.Now()
+	dbSubscription := subscription{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: PGEventTypes(sub.EventTypes),
+		Secret:     sub.Secret,
+		CreatedAt:  sub.Created,
+	}
+	if err := s.Store.Db.Create(&dbSubscription).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/subscription_store:Create() failed to create subscription")
+	}
+	sub.RowId = dbSubscription.Rowid
+	return sub, nil
+}
+
+// Retrieve fetches the subscription with the given id
+func (s *SubscriptionStore) Retrieve(id uuid.UUID) (*hvs.Subscription, error) {
+	defaultLog.Trace("postgres/subscription_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Retrieve() Leaving")
+
+	sub := hvs.Subscription{}
+	row := s.Store.Db.Model(&subscription{}).Select("id, url, event_types, secret, created, rowid").Where(&subscription{ID: id}).Row()
+	if err := row.Scan(&sub.ID, &sub.URL, (*PGEventTypes)(&sub.EventTypes), &sub.Secret, &sub.Created, &sub.RowId); err != nil {
+		return nil, errors.Wrap(err, "postgres/subscription_store:Retrieve() failed to scan record")
+	}
+	return &sub, nil
+}
+
+// Search returns the subscriptions matching the filter criteria
+func (s *SubscriptionStore) Search(criteria *models.SubscriptionFilterCriteria) ([]hvs.Subscription, error) {
+	defaultLog.Trace("postgres/subscription_store:Search() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Search() Leaving")
+
+	tx := s.Store.Db.Model(&subscription{}).Select("id, url, event_types, secret, created, rowid")
+	if criteria != nil {
+		if criteria.EventType != "" {
+			eventTypes, err := json.Marshal([]hvs.EventType{criteria.EventType})
+			if err != nil {
+				return nil, errors.Wrap(err, "postgres/subscription_store:Search() failed to marshal event type")
+			}
+			tx = tx.Where("event_types @> ?::jsonb", string(eventTypes))
+		}
+		if criteria.URL != "" {
+			tx = tx.Where("url = ?", criteria.URL)
+		}
+	}
+
+	rows, err := tx.Order("rowid asc").Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/subscription_store:Search() failed to retrieve records from db")
+	}
+	defer func() {
+		derr := rows.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("postgres/subscription_store:Search() Error closing rows")
+		}
+	}()
+
+	subscriptions := []hvs.Subscription{}
+	for rows.Next() {
+		sub := hvs.Subscription{}
+		if err := rows.Scan(&sub.ID, &sub.URL, (*PGEventTypes)(&sub.EventTypes), &sub.Secret, &sub.Created, &sub.RowId); err != nil {
+			return nil, errors.Wrap(err, "postgres/subscription_store:Search() failed to scan record")
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+	return subscriptions, nil
+}
+
+// Delete removes the subscription and its dead letter records
+func (s *SubscriptionStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("postgres/subscription_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/subscription_store:Delete() Leaving")
+
+	if err := s.Store.Db.Delete(&subscription{ID: id}).Error; err != nil {
+		return errors.Wrap(err, "postgres/subscription_store:Delete() failed to delete subscription")
+	}
+	return nil
+}
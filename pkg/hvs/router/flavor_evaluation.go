@@ -0,0 +1,32 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+)
+
+// SetFlavorEvaluationRoutes registers routes for evaluating flavors against a host without storing the report
+func SetFlavorEvaluationRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupStore domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager) *mux.Router {
+	defaultLog.Trace("router/flavor_evaluation:SetFlavorEvaluationRoutes() Entering")
+	defer defaultLog.Trace("router/flavor_evaluation:SetFlavorEvaluationRoutes() Leaving")
+
+	hostStore := postgres.NewHostStore(store)
+	hostStatusStore := postgres.NewHostStatusStore(store)
+	flavorEvaluationController := controllers.NewFlavorEvaluationController(hostStore, hostStatusStore, flavorGroupStore, hostTrustManager, certStore)
+
+	router.Handle("/rpc/evaluate-flavors",
+		ErrorHandler(PermissionsHandler(JsonResponseHandler(flavorEvaluationController.Evaluate),
+			[]string{constants.FlavorEvaluate}))).Methods(http.MethodPost)
+
+	return router
+}
@@ -69,5 +69,21 @@ func SetFlavorGroupRoutes(router *mux.Router, store *postgres.DataStore, flavorg
 		ErrorHandler(PermissionsHandler(JsonResponseHandler(flavorgroupController.SearchFlavors),
 			[]string{constants.FlavorGroupSearch}))).Methods(http.MethodGet)
 
+	// routes for the attestation policy of a FlavorGroup
+	attestationPolicyController := controllers.NewAttestationPolicyController(flavorgroupStore, postgres.NewAttestationPolicyStore(store))
+	fgAttestationPolicyExpr := fmt.Sprintf("/flavorgroups/{fgID:%s}/attestation-policy", validation.UUIDReg)
+
+	router.Handle(fgAttestationPolicyExpr,
+		ErrorHandler(PermissionsHandler(JsonResponseHandler(attestationPolicyController.Update),
+			[]string{constants.FlavorGroupCreate}))).Methods(http.MethodPut)
+
+	router.Handle(fgAttestationPolicyExpr,
+		ErrorHandler(PermissionsHandler(JsonResponseHandler(attestationPolicyController.Retrieve),
+			[]string{constants.FlavorGroupRetrieve}))).Methods(http.MethodGet)
+
+	router.Handle(fgAttestationPolicyExpr,
+		ErrorHandler(PermissionsHandler(ResponseHandler(attestationPolicyController.Delete),
+			[]string{constants.FlavorGroupDelete}))).Methods(http.MethodDelete)
+
 	return router
 }
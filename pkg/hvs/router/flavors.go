@@ -19,15 +19,17 @@ import (
 )
 
 // SetFlavorRoutes registers routes for flavors
-func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupStore domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, flavorControllerConfig domain.HostControllerConfig) *mux.Router {
+func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupStore domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, flavorControllerConfig domain.HostControllerConfig, eventPublisher domain.EventPublisher, trustedFlavorSigningCertsDir string) *mux.Router {
 	defaultLog.Trace("router/flavors:SetFlavorRoutes() Entering")
 	defer defaultLog.Trace("router/flavors:SetFlavorRoutes() Leaving")
 
 	hostStore := postgres.NewHostStore(store)
 	flavorStore := postgres.NewFlavorStore(store)
+	flavorStore.EventPublisher = eventPublisher
 	tagCertStore := postgres.NewTagCertificateStore(store)
 	flavorTemplateStore := postgres.NewFlavorTemplateStore(store)
 	flavorController := controllers.NewFlavorController(flavorStore, flavorGroupStore, hostStore, tagCertStore, hostTrustManager, certStore, flavorControllerConfig, flavorTemplateStore)
+	flavorBundleController := controllers.NewFlavorBundleController(*flavorController, trustedFlavorSigningCertsDir)
 
 	flavorIdExpr := fmt.Sprintf("%s%s", "/flavors/", validation.IdReg)
 
@@ -40,6 +42,14 @@ func SetFlavorRoutes(router *mux.Router, store *postgres.DataStore, flavorGroupS
 		ErrorHandler(PermissionsHandler(JsonResponseHandler(flavorController.Search),
 			[]string{constants.FlavorSearch}))).Methods(http.MethodGet)
 
+	router.Handle("/flavors/export",
+		ErrorHandler(PermissionsHandler(JsonResponseHandler(flavorBundleController.Export),
+			[]string{constants.FlavorExport}))).Methods(http.MethodGet)
+
+	router.Handle("/flavors/import",
+		ErrorHandler(PermissionsHandler(JsonResponseHandler(flavorBundleController.Import),
+			[]string{constants.FlavorImport}))).Methods(http.MethodPost)
+
 	router.Handle(flavorIdExpr,
 		ErrorHandler(PermissionsHandler(ResponseHandler(flavorController.Delete),
 			[]string{constants.FlavorDelete}))).Methods(http.MethodDelete)
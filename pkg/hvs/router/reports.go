@@ -45,6 +45,10 @@ func SetReportRoutes(router *mux.Router, store *postgres.DataStore, hostTrustMan
 		ErrorHandler(PermissionsHandler(JsonResponseHandler(reportController.Retrieve),
 			[]string{constants.ReportRetrieve}))).Methods(http.MethodGet)
 
+	router.Handle(reportIdExpr+"/diff",
+		ErrorHandler(PermissionsHandler(JsonResponseHandler(reportController.Diff),
+			[]string{constants.ReportRetrieve}))).Methods(http.MethodGet)
+
 	router.Handle("/reports",
 		ErrorHandler(PermissionsHandler(JsonResponseHandler(reportController.Search),
 			[]string{constants.ReportSearch}))).Methods(http.MethodGet)
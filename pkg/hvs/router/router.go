@@ -7,7 +7,6 @@ package router
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
@@ -15,11 +14,14 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
 
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/clients/aas"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	commMetrics "github.com/intel-secl/intel-secl/v5/pkg/lib/common/metrics"
 	cmw "github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v5/pkg/lib/common/os"
 	"github.com/pkg/errors"
@@ -29,11 +31,12 @@ var defaultLog = log.GetDefaultLogger()
 var secLog = log.GetSecurityLogger()
 
 type Router struct {
-	cfg *config.Configuration
+	cfg                   *config.Configuration
+	fetchTokenRevocations func(since time.Time) (*jwtauth.TokenRevocations, error)
 }
 
 // InitRoutes registers all routes for the application.
-func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig) (*mux.Router, error) {
+func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig, eventPublisher domain.EventPublisher) (*mux.Router, error) {
 	defaultLog.Trace("router/router:InitRoutes() Entering")
 	defer defaultLog.Trace("router/router:InitRoutes() Leaving")
 
@@ -42,19 +45,28 @@ func InitRoutes(cfg *config.Configuration, dataStore *postgres.DataStore, fgs do
 
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
+	router = commMetrics.SetMetricsRoutes(router, cfg.Server)
 
-	err := defineSubRoutes(router, constants.OldServiceName, cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	// the routes of both service names share the token revocation list
+	revocationsRefreshTime, err := time.ParseDuration(constants.TokenRevocationsRefreshTime)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not parse token revocations refresh time")
+	}
+	cfgRouter := &Router{cfg: cfg}
+	revocationList := jwtauth.NewRevocationList(cfgRouter.fnGetTokenRevocations, revocationsRefreshTime)
+
+	err = defineSubRoutes(router, constants.OldServiceName, cfg, revocationList, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, eventPublisher)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not define sub routes")
 	}
-	err = defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	err = defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, revocationList, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, eventPublisher)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not define sub routes")
 	}
 	return router, nil
 }
 
-func defineSubRoutes(router *mux.Router, service string, cfg *config.Configuration, dataStore *postgres.DataStore, fgs domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig) error {
+func defineSubRoutes(router *mux.Router, service string, cfg *config.Configuration, revocationList *jwtauth.RevocationList, dataStore *postgres.DataStore, fgs domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, hostControllerConfig domain.HostControllerConfig, eventPublisher domain.EventPublisher) error {
 	defaultLog.Trace("router/router:defineSubRoutes() Entering")
 	defer defaultLog.Trace("router/router:defineSubRoutes() Leaving")
 
@@ -69,23 +81,26 @@ func defineSubRoutes(router *mux.Router, service string, cfg *config.Configurati
 	if err != nil {
 		return errors.Wrap(err, "Could not parse JWT Certificate cache time")
 	}
-	subRouter.Use(cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
+	subRouter.Use(cmw.NewTokenAuthWithRevocationList(constants.TrustedJWTSigningCertsDir,
 		constants.TrustedRootCACertsDir, cfgRouter.fnGetJwtCerts,
-		cacheTime))
+		cacheTime, revocationList))
 	subRouter = SetFlavorGroupRoutes(subRouter, dataStore, fgs, hostTrustManager)
 	subRouter = SetFlavorTemplateRoutes(subRouter, dataStore, fgs)
-	subRouter = SetFlavorRoutes(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	subRouter = SetFlavorRoutes(subRouter, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, eventPublisher, cfg.FlavorImport.TrustedSigningCertsDir)
 	subRouter = SetTpmEndorsementRoutes(subRouter, dataStore)
 	subRouter = SetCertifyAiksRoutes(subRouter, dataStore, certStore, cfg.AikCertValidity, cfg.EnableEkCertRevokeChecks, cfg.RequireEKCertForHostProvision)
 	subRouter = SetHostStatusRoutes(subRouter, dataStore)
 	subRouter = SetCertifyHostKeysRoutes(subRouter, certStore)
 	subRouter = SetHostRoutes(subRouter, dataStore, hostTrustManager, hostControllerConfig)
 	subRouter = SetReportRoutes(subRouter, dataStore, hostTrustManager)
+	subRouter = SetFlavorEvaluationRoutes(subRouter, dataStore, fgs, certStore, hostTrustManager)
 	subRouter = SetCreateCaCertificatesRoutes(subRouter, certStore)
-	subRouter = SetTagCertificateRoutes(subRouter, cfg, fgs, certStore, hostTrustManager, dataStore)
+	subRouter = SetTagCertificateRoutes(subRouter, cfg, fgs, certStore, hostTrustManager, dataStore, eventPublisher)
 	subRouter = SetESXiClusterRoutes(subRouter, dataStore, hostTrustManager, hostControllerConfig)
 	subRouter = SetDeploySoftwareManifestRoute(subRouter, dataStore, hostTrustManager, hostControllerConfig)
 	subRouter = SetManifestsRoute(subRouter, dataStore)
+	subRouter = SetAuditLogRoutes(subRouter, dataStore)
+	subRouter = SetSubscriptionRoutes(subRouter, dataStore, hostControllerConfig.DataEncryptionKey)
 	return nil
 }
 
@@ -99,28 +114,43 @@ func (r *Router) fnGetJwtCerts() error {
 	if !strings.HasSuffix(cfg.AASApiUrl, "/") {
 		cfg.AASApiUrl = cfg.AASApiUrl + "/"
 	}
-	url := cfg.AASApiUrl + "jwt-certificates"
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	httpClient, err := r.aasHttpClient()
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not create http request")
+		return err
 	}
-	req.Header.Add("accept", "application/x-pem-file")
+
+	aasClient := aas.Client{BaseURL: cfg.AASApiUrl, HTTPClient: httpClient}
+	jwtCerts, err := aasClient.GetJwtSigningCertificates()
+	if err != nil {
+		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not retrieve jwt certificates")
+	}
+	for _, jwtCert := range jwtCerts {
+		err = crypt.SavePemCertWithShortSha1FileName(jwtCert, constants.TrustedJWTSigningCertsDir)
+		if err != nil {
+			return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+		}
+	}
+	return nil
+}
+
+// aasHttpClient creates the HTTP client of the requests to AAS
+func (r *Router) aasHttpClient() (*http.Client, error) {
 	rootCaCertPems, err := cos.GetDirFileContents(constants.TrustedRootCACertsDir, "*.pem")
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not read root CA certificate")
+		return nil, errors.Wrap(err, "router/router:aasHttpClient() Could not read root CA certificate")
 	}
 
 	// Get the SystemCertPool, continue with an empty pool on error
 	rootCAs, err := x509.SystemCertPool()
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Failed defining certificate pool")
+		return nil, errors.Wrap(err, "router/router:aasHttpClient() Failed defining certificate pool")
 	}
 	if rootCAs == nil {
 		rootCAs = x509.NewCertPool()
 	}
 	for _, rootCACert := range rootCaCertPems {
 		if ok := rootCAs.AppendCertsFromPEM(rootCACert); !ok {
-			return err
+			return nil, err
 		}
 	}
 	httpClient := &http.Client{
@@ -132,25 +162,26 @@ func (r *Router) fnGetJwtCerts() error {
 			},
 		},
 	}
+	return httpClient, nil
+}
 
-	res, err := httpClient.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not retrieve jwt certificate")
-	}
-	defer func() {
-		derr := res.Body.Close()
-		if derr != nil {
-			defaultLog.WithError(derr).Error("Error closing response body")
-		}
-	}()
+// Fetch the tokens revoked in AAS with the token of the HVS service user
+func (r *Router) fnGetTokenRevocations(since time.Time) (*jwtauth.TokenRevocations, error) {
+	defaultLog.Trace("router/router:fnGetTokenRevocations() Entering")
+	defer defaultLog.Trace("router/router:fnGetTokenRevocations() Leaving")
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Failed read response")
+	if r.fetchTokenRevocations == nil {
+		httpClient, err := r.aasHttpClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "router/router:fnGetTokenRevocations() Could not create AAS client")
+		}
+		aasClient := &aas.Client{BaseURL: r.cfg.AASApiUrl, HTTPClient: httpClient}
+		r.fetchTokenRevocations = aas.NewTokenRevocationsFetcher(aasClient, aas.NewServiceUserTokenFetcher(r.cfg.AASApiUrl,
+			httpClient, r.cfg.HVS.Username, r.cfg.HVS.Password))
 	}
-	err = crypt.SavePemCertWithShortSha1FileName(body, constants.TrustedJWTSigningCertsDir)
+	revocations, err := r.fetchTokenRevocations(since)
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+		return nil, errors.Wrap(err, "router/router:fnGetTokenRevocations() Error retrieving token revocations from AAS")
 	}
-	return nil
+	return revocations, nil
 }
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/postgres"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
+)
+
+// SetSubscriptionRoutes registers routes for event subscription APIs
+func SetSubscriptionRoutes(router *mux.Router, store *postgres.DataStore, dek []byte) *mux.Router {
+	defaultLog.Trace("router/subscriptions:SetSubscriptionRoutes() Entering")
+	defer defaultLog.Trace("router/subscriptions:SetSubscriptionRoutes() Leaving")
+
+	subscriptionStore := postgres.NewSubscriptionStore(store)
+	deadLetterStore := postgres.NewEventDeadLetterStore(store)
+	subscriptionController := controllers.NewSubscriptionController(subscriptionStore, deadLetterStore, dek)
+
+	subscriptionIdExpr := fmt.Sprintf("%s%s", "/subscriptions/", validation.IdReg)
+
+	router.Handle("/subscriptions", ErrorHandler(PermissionsHandler(JsonResponseHandler(subscriptionController.Create),
+		[]string{constants.SubscriptionCreate}))).Methods(http.MethodPost)
+
+	router.Handle("/subscriptions", ErrorHandler(PermissionsHandler(JsonResponseHandler(subscriptionController.Search),
+		[]string{constants.SubscriptionSearch}))).Methods(http.MethodGet)
+
+	router.Handle(subscriptionIdExpr, ErrorHandler(PermissionsHandler(JsonResponseHandler(subscriptionController.Retrieve),
+		[]string{constants.SubscriptionRetrieve}))).Methods(http.MethodGet)
+
+	router.Handle(subscriptionIdExpr, ErrorHandler(PermissionsHandler(ResponseHandler(subscriptionController.Delete),
+		[]string{constants.SubscriptionDelete}))).Methods(http.MethodDelete)
+
+	router.Handle(subscriptionIdExpr+"/dead-letters", ErrorHandler(PermissionsHandler(JsonResponseHandler(subscriptionController.SearchDeadLetters),
+		[]string{constants.SubscriptionRetrieve}))).Methods(http.MethodGet)
+
+	return router
+}
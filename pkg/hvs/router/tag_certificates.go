@@ -28,7 +28,7 @@ const (
 )
 
 // SetTagCertificateRoutes registers routes for tag-certificates API
-func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flavorGroupStore domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, store *postgres.DataStore) *mux.Router {
+func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flavorGroupStore domain.FlavorGroupStore, certStore *crypt.CertificatesStore, hostTrustManager domain.HostTrustManager, store *postgres.DataStore, eventPublisher domain.EventPublisher) *mux.Router {
 	defaultLog.Trace("router/tag_certificates:SetTagCertificateRoutes() Entering")
 	defer defaultLog.Trace("router/tag_certificates:SetTagCertificateRoutes() Leaving")
 
@@ -45,6 +45,7 @@ func SetTagCertificateRoutes(router *mux.Router, cfg *config.Configuration, flav
 	tagCertificateStore := postgres.NewTagCertificateStore(store)
 	hostStore := postgres.NewHostStore(store)
 	flavorStore := postgres.NewFlavorStore(store)
+	flavorStore.EventPublisher = eventPublisher
 
 	// initialize the user credentials for AAS connections
 	tcConfig := domain.TagCertControllerConfig{
@@ -8,6 +8,7 @@ import (
 	"context"
 	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -31,10 +32,14 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/postgres"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/router"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/auditlog"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/events"
 	hostfetcher "github.com/intel-secl/intel-secl/v5/pkg/hvs/services/host-fetcher"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/hosttrust"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/services/hrrs"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/certrenewer"
+	commConstants "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	cos "github.com/intel-secl/intel-secl/v5/pkg/lib/common/os"
 	hostconnector "github.com/intel-secl/intel-secl/v5/pkg/lib/host-connector"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/saml"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/verifier"
@@ -70,6 +75,21 @@ func (a *App) startServer() error {
 	als := postgres.NewAuditLogEntryStore(dataStore)
 	alw, _ := auditlog.NewAuditLogDBWriter(als, c.AuditLog.BufferSize)
 
+	// Initialize event notifications
+	subscriptionStore := postgres.NewSubscriptionStore(dataStore)
+	deadLetterStore := postgres.NewEventDeadLetterStore(dataStore)
+	eventPublisher, err := events.NewWebhookDispatcher(c.Events, subscriptionStore, deadLetterStore, getDecodedDek(c))
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while initializing event notifications")
+	}
+	if len(c.NATS.Servers) > 0 {
+		natsPublisher, err := events.NewNatsPublisher(c.NATS.Servers, getNatsTLSConfig(), constants.NatsCredentials)
+		if err != nil {
+			return errors.Wrap(err, "An error occurred while initializing NATS event notifications")
+		}
+		eventPublisher = events.NewMultiPublisher(eventPublisher, natsPublisher)
+	}
+
 	// Load Certificates
 	certStore, err := crypt.LoadCertificates(a.loadCertPathStore(), models.GetUniqueCertTypes())
 	if err != nil {
@@ -78,13 +98,13 @@ func (a *App) startServer() error {
 
 	// Initialize Host trust manager
 	fgs := postgres.NewFlavorGroupStore(dataStore)
-	hostTrustManager := initHostTrustManager(c, dataStore, fgs, certStore, alw)
+	hostTrustManager := initHostTrustManager(c, dataStore, fgs, certStore, alw, eventPublisher)
 	go hostTrustManager.ProcessQueue()
 
 	// create an instance of the HRRS and start it...
 	reportStore := postgres.NewReportStore(dataStore)
 	reportStore.AuditLogWriter = alw
-	reportRefresher, err := hrrs.NewHostReportRefresher(c.HRRS, reportStore, hostTrustManager)
+	reportRefresher, err := hrrs.NewHostReportRefresher(c.HRRS, reportStore, fgs, postgres.NewAttestationPolicyStore(dataStore), hostTrustManager)
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while initializing HRRS")
 	}
@@ -109,7 +129,7 @@ func (a *App) startServer() error {
 	}
 
 	// Initialize routes
-	routes, err := router.InitRoutes(c, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig)
+	routes, err := router.InitRoutes(c, dataStore, fgs, certStore, hostTrustManager, hostControllerConfig, eventPublisher)
 	if err != nil {
 		return errors.Wrap(err, "An error occurred while initializing routes")
 	}
@@ -122,6 +142,11 @@ func (a *App) startServer() error {
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
 			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
 	}
+	certRenewer, err := startCertRenewer(c, tlsConfig)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while starting certificate renewer")
+	}
+	defer certRenewer.Stop()
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -138,11 +163,9 @@ func (a *App) startServer() error {
 		MaxHeaderBytes:    c.Server.MaxHeaderBytes,
 	}
 
-	tlsCert := c.TLS.CertFile
-	tlsKey := c.TLS.KeyFile
-	// dispatch web server go routine
+	// dispatch web server go routine, the TLS certificate is provided by the certificate renewer
 	go func() {
-		if err := h.ListenAndServeTLS(tlsCert, tlsKey); err != nil {
+		if err := h.ListenAndServeTLS("", ""); err != nil {
 			if err != http.ErrServerClosed {
 				defaultLog.WithError(err).Fatal("Failed to start HTTPS server")
 			}
@@ -166,10 +189,36 @@ func (a *App) startServer() error {
 		defaultLog.WithError(err).Info("Failed to gracefully shutdown webserver")
 		return err
 	}
+	eventPublisher.Stop()
 	secLog.Info(commLogMsg.ServiceStop)
 	return nil
 }
 
+// startCertRenewer starts the renewal of the TLS certificate issued by CMS with the credential of the HVS service user
+// and reloads it in the TLS configuration. The SAML and flavor signing certificates are not renewed: their keys are
+// loaded in the certificate store and in the SAML issuer at start, and the signatures of the stored flavors are
+// verified with the flavor signing certificate, so re-keying them would break the signing until a restart and the
+// verification of the existing flavors.
+func startCertRenewer(c *config.Configuration, tlsConfig *tls.Config) (*certrenewer.Renewer, error) {
+	defaultLog.Trace("server:startCertRenewer() Entering")
+	defer defaultLog.Trace("server:startCertRenewer() Leaving")
+
+	renewer := certrenewer.NewRenewer(c.CMSBaseURL, constants.TrustedRootCACertsDir,
+		certrenewer.NewAasTokenProvider(c.AASApiUrl, c.HVS.Username, c.HVS.Password, constants.TrustedRootCACertsDir))
+	tlsCertificate, err := renewer.Watch(certrenewer.Certificate{
+		CertFile: c.TLS.CertFile,
+		KeyFile:  c.TLS.KeyFile,
+		CertType: commConstants.CertTypeTls,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.GetCertificate = tlsCertificate.GetCertificate
+
+	renewer.Start()
+	return renewer, nil
+}
+
 func initHostControllerConfig(cfg *config.Configuration, certStore *crypt.CertificatesStore) domain.HostControllerConfig {
 	defaultLog.Trace("server:initHostControllerConfig() Entering")
 	defer defaultLog.Trace("server:initHostControllerConfig() Leaving")
@@ -199,7 +248,28 @@ func getDecodedDek(cfg *config.Configuration) []byte {
 	return dek
 }
 
-func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataStore, fgs domain.FlavorGroupStore, certStore *crypt.CertificatesStore, alw domain.AuditLogWriter) domain.HostTrustManager {
+// getNatsTLSConfig trusts the system CAs and the CAs in the HVS trusted CA directory, the same as the NATS
+// connections to the trust agents
+func getNatsTLSConfig() *tls.Config {
+	rootCAs, _ := x509.SystemCertPool()
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	certs, err := cos.GetDirFileContents(constants.TrustedCaCertsDir, "*.pem")
+	if err != nil {
+		defaultLog.WithError(err).Errorf("server:getNatsTLSConfig() Failed to read CA certificates from %s", constants.TrustedCaCertsDir)
+	}
+	for _, rootCACert := range certs {
+		if ok := rootCAs.AppendCertsFromPEM(rootCACert); !ok {
+			defaultLog.Info("server:getNatsTLSConfig() No certs appended, using system certs only")
+		}
+	}
+	return &tls.Config{
+		RootCAs: rootCAs,
+	}
+}
+
+func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataStore, fgs domain.FlavorGroupStore, certStore *crypt.CertificatesStore, alw domain.AuditLogWriter, eventPublisher domain.EventPublisher) domain.HostTrustManager {
 	defaultLog.Trace("server:InitHostTrustManager() Entering")
 	defer defaultLog.Trace("server:InitHostTrustManager() Leaving")
 
@@ -210,8 +280,10 @@ func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataSto
 	qs := postgres.NewDBQueueStore(dataStore)
 	hss := postgres.NewHostStatusStore(dataStore)
 	hss.AuditLogWriter = alw
+	hss.EventPublisher = eventPublisher
 	rs := postgres.NewReportStore(dataStore)
 	rs.AuditLogWriter = alw
+	rs.EventPublisher = eventPublisher
 
 	//Load certificates
 	rootCAs := (*certStore)[models.CaCertTypesRootCa.String()]
@@ -254,6 +326,7 @@ func initHostTrustManager(cfg *config.Configuration, dataStore *postgres.DataSto
 		SamlIssuerConfig:                samlIssuerConfig,
 		SkipFlavorSignatureVerification: cfg.FVS.SkipFlavorSignatureVerification,
 		HostTrustCache:                  hostQuoteTrustCache,
+		AttestationPolicyStore:          postgres.NewAttestationPolicyStore(dataStore),
 	}
 
 	// Initialize Host Fetcher service
@@ -54,6 +54,19 @@ func (me *mockEntryStore) Retrieve(e *models.AuditLogEntry) ([]models.AuditLogEn
 	return ret, nil
 }
 
+func (me *mockEntryStore) Search(c *models.AuditLogFilterCriteria) ([]models.AuditLogEntry, error) {
+	var ret []models.AuditLogEntry
+	for _, v := range me.data {
+		if (c.EntityID == uuid.Nil || v.EntityID == c.EntityID) &&
+			(c.Action == "" || v.Action == c.Action) &&
+			(c.EntityType == "" || v.EntityType == c.EntityType) {
+			ret = append(ret, *v)
+		}
+	}
+	me.t.Log("Search", c)
+	return ret, nil
+}
+
 func (me *mockEntryStore) Update(e *models.AuditLogEntry) (*models.AuditLogEntry, error) {
 	if e.ID == uuid.Nil {
 		return nil, errors.New("id can not be nil")
@@ -0,0 +1,26 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package events
+
+import "time"
+
+const (
+	DefaultBufferSize    = 1000
+	DefaultMaxRetries    = 5
+	DefaultRetryInterval = 30 * time.Second
+	DefaultTimeout       = 10 * time.Second
+)
+
+type EventsConfig struct {
+	// BufferSize is the number of events that can be queued for delivery before new events are dropped
+	BufferSize int `yaml:"buffer-size" mapstructure:"buffer-size"`
+	// MaxRetries is the number of times delivery to a subscriber is retried before the event is
+	// recorded as a dead letter
+	MaxRetries int `yaml:"max-retries" mapstructure:"max-retries"`
+	// RetryInterval is the delay before the first retry, each following retry waits one interval longer
+	RetryInterval time.Duration `yaml:"retry-interval" mapstructure:"retry-interval"`
+	// Timeout bounds each POST to a subscriber
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
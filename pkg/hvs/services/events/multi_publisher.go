@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+)
+
+// multiPublisher hands every event to each of its publishers in order
+type multiPublisher []domain.EventPublisher
+
+// NewMultiPublisher returns an EventPublisher that publishes to all the non nil publishers
+func NewMultiPublisher(publishers ...domain.EventPublisher) domain.EventPublisher {
+	var mp multiPublisher
+	for _, p := range publishers {
+		if p != nil {
+			mp = append(mp, p)
+		}
+	}
+	return mp
+}
+
+func (mp multiPublisher) Publish(e *hvs.Event) {
+	if e == nil {
+		return
+	}
+	// assign the identity once so that every publisher sees the same event
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.Created.IsZero() {
+		e.Created = time.Now().UTC()
+	}
+	for _, p := range mp {
+		p.Publish(e)
+	}
+}
+
+func (mp multiPublisher) Stop() {
+	for _, p := range mp {
+		p.Stop()
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package events
+
+import (
+	"crypto/tls"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+)
+
+const natsFlushTimeout = 5 * time.Second
+
+// natsPublisher publishes report and host trust events to the NATS servers that are already used to reach
+// the trust agents. Reports are published on hvs.report.created and changes of a host's trust status on
+// hvs.host.<host-id>.trust-changed, both with the TrustReportSummary as payload. Other event types are
+// only delivered to webhook subscribers.
+type natsPublisher struct {
+	conn      *nats.EncodedConn
+	closeOnce sync.Once
+}
+
+// NewNatsPublisher connects to the given NATS servers. The tlsConfig and credentials file are optional so
+// that unsecured servers can be used during local testing; they are always provided by HVS.
+func NewNatsPublisher(natsServers []string, tlsConfig *tls.Config, natsCredentials string) (domain.EventPublisher, error) {
+	if len(natsServers) == 0 {
+		return nil, errors.New("services/events/nats_publisher:NewNatsPublisher() At least one nats-server must be provided")
+	}
+
+	options := []nats.Option{
+		nats.Name("hvs-event-publisher"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(5 * time.Second),
+		nats.Timeout(10 * time.Second),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			defaultLog.WithError(err).Error("services/events/nats_publisher:NewNatsPublisher() NATS: Error while publishing events")
+		}),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			defaultLog.Debug("services/events/nats_publisher:NewNatsPublisher() NATS: Client disconnected")
+		}),
+		nats.ReconnectHandler(func(_ *nats.Conn) {
+			defaultLog.Debug("services/events/nats_publisher:NewNatsPublisher() NATS: Client reconnected")
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			defaultLog.Debug("services/events/nats_publisher:NewNatsPublisher() NATS: Client closed")
+		}),
+	}
+	if tlsConfig != nil {
+		options = append(options, nats.Secure(tlsConfig))
+	}
+	if natsCredentials != "" {
+		options = append(options, nats.UserCredentials(natsCredentials))
+	}
+
+	conn, err := nats.Connect(strings.Join(natsServers, ","), options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "services/events/nats_publisher:NewNatsPublisher() Failed to create nats connection")
+	}
+
+	encodedConn, err := nats.NewEncodedConn(conn, nats.JSON_ENCODER)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "services/events/nats_publisher:NewNatsPublisher() Failed to create encoded connection")
+	}
+	return &natsPublisher{conn: encodedConn}, nil
+}
+
+// Publish sends the event's report summary without waiting for the servers. Messages are buffered by the
+// client while it reconnects.
+func (np *natsPublisher) Publish(e *hvs.Event) {
+	if e == nil || e.Report == nil {
+		return
+	}
+
+	var subject string
+	switch e.Type {
+	case hvs.EventTypeReportCreated:
+		subject = hvs.NatsReportCreatedSubject
+	case hvs.EventTypeHostTrusted, hvs.EventTypeHostUntrusted:
+		subject = hvs.CreateHostSubject(e.Report.HostID.String(), hvs.NatsHostTrustChanged)
+	default:
+		return
+	}
+
+	if err := np.conn.Publish(subject, e.Report); err != nil {
+		defaultLog.WithError(err).Errorf("services/events/nats_publisher:Publish() Failed to publish report %s on %s", e.Report.ReportID, subject)
+	}
+}
+
+// Stop flushes the buffered messages and closes the connection
+func (np *natsPublisher) Stop() {
+	np.closeOnce.Do(func() {
+		if err := np.conn.FlushTimeout(natsFlushTimeout); err != nil {
+			defaultLog.WithError(err).Warn("services/events/nats_publisher:Stop() Failed to flush pending events")
+		}
+		np.conn.Close()
+	})
+}
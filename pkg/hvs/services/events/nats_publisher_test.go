@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+)
+
+func runTestNatsServer(t *testing.T) *server.Server {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	assert.NoError(t, err)
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server did not start")
+	}
+	return ns
+}
+
+func nextMessage(t *testing.T, sub *nats.Subscription) *nats.Msg {
+	msg, err := sub.NextMsg(5 * time.Second)
+	assert.NoError(t, err)
+	return msg
+}
+
+func TestNatsPublisherPublishesReportEvents(t *testing.T) {
+	ns := runTestNatsServer(t)
+	defer ns.Shutdown()
+
+	nc, err := nats.Connect(ns.ClientURL())
+	assert.NoError(t, err)
+	defer nc.Close()
+	reports, err := nc.SubscribeSync(hvs.NatsReportCreatedSubject)
+	assert.NoError(t, err)
+	trustChanges, err := nc.SubscribeSync(hvs.CreateHostSubject("*", hvs.NatsHostTrustChanged))
+	assert.NoError(t, err)
+	assert.NoError(t, nc.Flush())
+
+	publisher, err := NewNatsPublisher([]string{ns.ClientURL()}, nil, "")
+	assert.NoError(t, err)
+
+	summary := &hvs.TrustReportSummary{
+		ReportID:     uuid.New(),
+		HostID:       uuid.New(),
+		HostName:     "host1",
+		Trusted:      false,
+		FlavorsTrust: map[hvs.FlavorPartName]bool{hvs.FlavorPartPlatform: true, hvs.FlavorPartOs: false},
+	}
+	publisher.Publish(&hvs.Event{Type: hvs.EventTypeReportCreated, EntityID: summary.ReportID, EntityType: hvs.EventEntityTypeReport, Report: summary})
+	publisher.Publish(&hvs.Event{Type: hvs.EventTypeHostUntrusted, EntityID: summary.HostID, EntityType: hvs.EventEntityTypeHost, Report: summary})
+	publisher.Publish(&hvs.Event{Type: hvs.EventTypeFlavorCreated, EntityID: uuid.New(), EntityType: hvs.EventEntityTypeFlavor})
+	publisher.Stop()
+
+	var received hvs.TrustReportSummary
+	msg := nextMessage(t, reports)
+	assert.NoError(t, json.Unmarshal(msg.Data, &received))
+	assert.Equal(t, summary.ReportID, received.ReportID)
+	assert.Equal(t, summary.FlavorsTrust, received.FlavorsTrust)
+
+	msg = nextMessage(t, trustChanges)
+	assert.Equal(t, "hvs.host."+summary.HostID.String()+".trust-changed", msg.Subject)
+	assert.NoError(t, json.Unmarshal(msg.Data, &received))
+	assert.Equal(t, summary.HostID, received.HostID)
+	assert.False(t, received.Trusted)
+
+	_, err = trustChanges.NextMsg(100 * time.Millisecond)
+	assert.Equal(t, nats.ErrTimeout, err)
+}
+
+func TestNatsPublisherWithoutServers(t *testing.T) {
+	_, err := NewNatsPublisher(nil, nil, "")
+	assert.Error(t, err)
+}
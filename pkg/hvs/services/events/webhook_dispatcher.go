@@ -0,0 +1,253 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/utils"
+	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// HeaderSignature carries the hex encoded HMAC-SHA256 of the request body, keyed with the subscription secret
+	HeaderSignature = "X-Hvs-Signature"
+	HeaderEventType = "X-Hvs-Event-Type"
+	HeaderEventId   = "X-Hvs-Event-Id"
+
+	signaturePrefix = "sha256="
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+// webhookDispatcher delivers published events to the subscribers registered for their type. Every
+// subscriber is posted to independently so that a slow or unreachable endpoint only delays its own events.
+type webhookDispatcher struct {
+	cfg               EventsConfig
+	subscriptionStore domain.SubscriptionStore
+	deadLetterStore   domain.EventDeadLetterStore
+	dek               []byte
+	client            *http.Client
+
+	queue     chan *hvs.Event
+	ctx       context.Context
+	cancel    context.CancelFunc
+	doneChan  chan struct{}
+	inFlight  sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func NewWebhookDispatcher(cfg EventsConfig, ss domain.SubscriptionStore, dls domain.EventDeadLetterStore, dek []byte) (domain.EventPublisher, error) {
+	if ss == nil || dls == nil {
+		return nil, errors.New("NewWebhookDispatcher: invalid datastore")
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	ret := &webhookDispatcher{
+		cfg:               cfg,
+		subscriptionStore: ss,
+		deadLetterStore:   dls,
+		dek:               dek,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+		},
+		queue:    make(chan *hvs.Event, cfg.BufferSize),
+		doneChan: make(chan struct{}),
+	}
+	ret.ctx, ret.cancel = context.WithCancel(context.Background())
+	go ret.run()
+	return ret, nil
+}
+
+// Publish queues the event without blocking the caller. Events are dropped when the queue is full so that
+// attestation never stalls on slow subscribers.
+func (wd *webhookDispatcher) Publish(e *hvs.Event) {
+	if e == nil {
+		return
+	}
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.Created.IsZero() {
+		e.Created = time.Now().UTC()
+	}
+	select {
+	case wd.queue <- e:
+	default:
+		defaultLog.Warnf("services/events/webhook_dispatcher:Publish() Event queue is full, dropping %s event %s for %s %s",
+			e.Type, e.ID, e.EntityType, e.EntityID)
+	}
+}
+
+// Stop dispatches the events that are still queued, waits for in-flight deliveries and returns. Deliveries
+// that are waiting for a retry are recorded as dead letters.
+func (wd *webhookDispatcher) Stop() {
+	wd.closeOnce.Do(func() {
+		wd.cancel()
+		<-wd.doneChan
+		wd.inFlight.Wait()
+	})
+}
+
+func (wd *webhookDispatcher) run() {
+	defer func() {
+		if err := recover(); err != nil {
+			defaultLog.Errorf("Panic occurred: %+v", err)
+			defaultLog.Error(string(debug.Stack()))
+		}
+		close(wd.doneChan)
+	}()
+	for {
+		select {
+		case e := <-wd.queue:
+			wd.dispatch(e)
+		case <-wd.ctx.Done():
+			for len(wd.queue) > 0 {
+				wd.dispatch(<-wd.queue)
+			}
+			return
+		}
+	}
+}
+
+func (wd *webhookDispatcher) dispatch(e *hvs.Event) {
+	defaultLog.Trace("services/events/webhook_dispatcher:dispatch() Entering")
+	defer defaultLog.Trace("services/events/webhook_dispatcher:dispatch() Leaving")
+
+	subscriptions, err := wd.subscriptionStore.Search(&models.SubscriptionFilterCriteria{EventType: e.Type})
+	if err != nil {
+		defaultLog.WithError(err).Errorf("services/events/webhook_dispatcher:dispatch() Failed to search subscriptions for %s event %s", e.Type, e.ID)
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		defaultLog.WithError(err).Errorf("services/events/webhook_dispatcher:dispatch() Failed to marshal %s event %s", e.Type, e.ID)
+		return
+	}
+	for _, sub := range subscriptions {
+		wd.inFlight.Add(1)
+		go wd.deliver(sub, e, body)
+	}
+}
+
+func (wd *webhookDispatcher) deliver(sub hvs.Subscription, e *hvs.Event, body []byte) {
+	defer wd.inFlight.Done()
+	defer func() {
+		if err := recover(); err != nil {
+			defaultLog.Errorf("Panic occurred: %+v", err)
+			defaultLog.Error(string(debug.Stack()))
+		}
+	}()
+
+	secret, err := utils.DecryptString(sub.Secret, wd.dek)
+	if err != nil {
+		wd.recordDeadLetter(sub, e, 0, errors.Wrap(err, "failed to decrypt subscription secret"))
+		return
+	}
+	signature := ComputeSignature([]byte(secret), body)
+
+	attempts := 0
+	for {
+		attempts++
+		err = wd.post(sub.URL, e, body, signature)
+		if err == nil {
+			return
+		}
+		defaultLog.WithError(err).Debugf("services/events/webhook_dispatcher:deliver() Attempt %d to deliver event %s to subscription %s failed", attempts, e.ID, sub.ID)
+		if attempts > wd.cfg.MaxRetries {
+			break
+		}
+		select {
+		case <-time.After(time.Duration(attempts) * wd.cfg.RetryInterval):
+		case <-wd.ctx.Done():
+			wd.recordDeadLetter(sub, e, attempts, errors.Wrap(err, "event dispatcher stopped before delivery succeeded"))
+			return
+		}
+	}
+	wd.recordDeadLetter(sub, e, attempts, err)
+}
+
+func (wd *webhookDispatcher) post(url string, e *hvs.Event, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, signature)
+	req.Header.Set(HeaderEventType, string(e.Type))
+	req.Header.Set(HeaderEventId, e.ID.String())
+
+	resp, err := wd.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post event")
+	}
+	defer func() {
+		derr := resp.Body.Close()
+		if derr != nil {
+			defaultLog.WithError(derr).Error("Error closing response body")
+		}
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (wd *webhookDispatcher) recordDeadLetter(sub hvs.Subscription, e *hvs.Event, attempts int, cause error) {
+	defaultLog.WithError(cause).Warnf("services/events/webhook_dispatcher:recordDeadLetter() Giving up delivery of event %s to subscription %s after %d attempts", e.ID, sub.ID, attempts)
+	_, err := wd.deadLetterStore.Create(&hvs.EventDeadLetter{
+		SubscriptionID: sub.ID,
+		Event:          *e,
+		Attempts:       attempts,
+		LastError:      cause.Error(),
+	})
+	if err != nil {
+		defaultLog.WithError(err).Errorf("services/events/webhook_dispatcher:recordDeadLetter() Failed to record dead letter for event %s", e.ID)
+	}
+}
+
+// ComputeSignature returns the value of the signature header sent with an event body. Subscribers compute
+// the same value with their copy of the secret to authenticate the request.
+func ComputeSignature(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package events
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "subscriber-shared-secret"
+
+func newTestSubscription(t *testing.T, ss *mocks.MockSubscriptionStore, dek []byte, url string, eventTypes ...hvs.EventType) *hvs.Subscription {
+	secret, err := utils.EncryptString(testSecret, dek)
+	assert.NoError(t, err)
+	sub, err := ss.Create(&hvs.Subscription{URL: url, EventTypes: eventTypes, Secret: secret})
+	assert.NoError(t, err)
+	return sub
+}
+
+func newTestDek(t *testing.T) []byte {
+	dek := make([]byte, 32)
+	_, err := rand.Read(dek)
+	assert.NoError(t, err)
+	return dek
+}
+
+func TestWebhookDispatcherDeliversSignedEvent(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		received <- r
+	}))
+	defer server.Close()
+
+	dek := newTestDek(t)
+	ss := &mocks.MockSubscriptionStore{}
+	dls := &mocks.MockEventDeadLetterStore{}
+	newTestSubscription(t, ss, dek, server.URL, hvs.EventTypeHostUntrusted)
+	newTestSubscription(t, ss, dek, server.URL+"/flavors", hvs.EventTypeFlavorCreated)
+
+	publisher, err := NewWebhookDispatcher(EventsConfig{MaxRetries: 1, RetryInterval: time.Millisecond}, ss, dls, dek)
+	assert.NoError(t, err)
+
+	hostId := uuid.New()
+	publisher.Publish(&hvs.Event{Type: hvs.EventTypeHostUntrusted, EntityID: hostId, EntityType: hvs.EventEntityTypeHost})
+
+	select {
+	case r := <-received:
+		assert.Equal(t, "/", r.URL.Path)
+		assert.Equal(t, string(hvs.EventTypeHostUntrusted), r.Header.Get(HeaderEventType))
+		assert.Equal(t, ComputeSignature([]byte(testSecret), body), r.Header.Get(HeaderSignature))
+
+		var e hvs.Event
+		assert.NoError(t, json.Unmarshal(body, &e))
+		assert.Equal(t, hostId, e.EntityID)
+		assert.NotEqual(t, uuid.Nil, e.ID)
+		assert.Equal(t, e.ID.String(), r.Header.Get(HeaderEventId))
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+	publisher.Stop()
+
+	deadLetters, _ := dls.Search(nil)
+	assert.Empty(t, deadLetters)
+}
+
+func TestWebhookDispatcherRetriesUntilDelivered(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	dek := newTestDek(t)
+	ss := &mocks.MockSubscriptionStore{}
+	dls := &mocks.MockEventDeadLetterStore{}
+	newTestSubscription(t, ss, dek, server.URL, hvs.EventTypeFlavorDeleted)
+
+	publisher, err := NewWebhookDispatcher(EventsConfig{MaxRetries: 3, RetryInterval: time.Millisecond}, ss, dls, dek)
+	assert.NoError(t, err)
+	publisher.Publish(&hvs.Event{Type: hvs.EventTypeFlavorDeleted, EntityID: uuid.New(), EntityType: hvs.EventEntityTypeFlavor})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 3 }, 5*time.Second, 10*time.Millisecond)
+	publisher.Stop()
+
+	deadLetters, _ := dls.Search(nil)
+	assert.Empty(t, deadLetters)
+}
+
+func TestWebhookDispatcherRecordsDeadLetter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dek := newTestDek(t)
+	ss := &mocks.MockSubscriptionStore{}
+	dls := &mocks.MockEventDeadLetterStore{}
+	sub := newTestSubscription(t, ss, dek, server.URL, hvs.EventTypeHostConnectionFailure)
+
+	publisher, err := NewWebhookDispatcher(EventsConfig{MaxRetries: 2, RetryInterval: time.Millisecond}, ss, dls, dek)
+	assert.NoError(t, err)
+	publisher.Publish(&hvs.Event{Type: hvs.EventTypeHostConnectionFailure, EntityID: uuid.New(), EntityType: hvs.EventEntityTypeHost})
+
+	var deadLetters []hvs.EventDeadLetter
+	assert.Eventually(t, func() bool {
+		deadLetters, _ = dls.Search(&models.EventDeadLetterFilterCriteria{SubscriptionID: sub.ID})
+		return len(deadLetters) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	publisher.Stop()
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, hvs.EventTypeHostConnectionFailure, deadLetters[0].Event.Type)
+	assert.Contains(t, deadLetters[0].LastError, "500")
+}
+
+func TestNewWebhookDispatcherInvalidStore(t *testing.T) {
+	_, err := NewWebhookDispatcher(EventsConfig{}, nil, &mocks.MockEventDeadLetterStore{}, nil)
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models/taskstage"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/metrics"
 	"golang.org/x/sync/syncmap"
 	"reflect"
 	"runtime/debug"
@@ -155,6 +156,7 @@ func (svc *Service) startRetryChannelProcessor(retryMins int) {
 				case <-svc.quit:
 					return
 				case <-time.After(retry.retryTime.Sub(time.Now())):
+					metrics.QueueAdded(metrics.QueueHostDataFetch)
 					svc.workChan <- retry.hostId
 				}
 			}
@@ -224,6 +226,7 @@ func (svc *Service) doWork() {
 			// we have received a quit. Don't process anymore items - just return
 			return
 		case id := <-svc.workChan:
+			metrics.QueueRemoved(metrics.QueueHostDataFetch)
 			hId, ok := id.(uuid.UUID)
 			defaultLog.Debugf("hostfetcher/fetcher:doWork() host - %s", hId.String())
 			var connUrl string
@@ -276,6 +279,7 @@ func (svc *Service) Retrieve(host hvs.Host) (*hvs.HostManifest, error) {
 		hostState := utils.DetermineHostState(err)
 		defaultLog.Warnf("hostfetcher/Service:Retrieve() Could not connect to host : %s", hostState.String())
 		hostStatus.HostStatusInformation.HostState = hostState
+		metrics.SetHostState(host.Id, hostState)
 		if err := svc.hss.Persist(hostStatus); err != nil {
 			defaultLog.Error("hostfetcher/Service:Retrieve() could not update host status to store")
 		}
@@ -286,6 +290,7 @@ func (svc *Service) Retrieve(host hvs.Host) (*hvs.HostManifest, error) {
 	hostStatus.HostStatusInformation.LastTimeConnected = time.Now()
 	hostStatus.HostManifest = *hostData
 	svc.updateMissingHostDetails(host.Id, hostData)
+	metrics.SetHostState(host.Id, hvs.HostStateConnected)
 	if err := svc.hss.Persist(hostStatus); err != nil {
 		defaultLog.Error("hostfetcher/Service:Retrieve() could not update host status and manifest to store")
 	}
@@ -302,6 +307,7 @@ func (svc *Service) RetrieveAsync(ctx context.Context, host hvs.Host, preferHash
 	}
 	fr := &fetchRequest{ctx, host, rcvrs, preferHashMatch}
 	// queue up the request
+	metrics.QueueAdded(metrics.QueueHostDataFetch)
 	svc.rqstChan <- fr
 	return nil
 }
@@ -345,6 +351,7 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 		hostState := utils.DetermineHostState(err)
 		defaultLog.Warnf("hostfetcher/Service:FetchDataAndRespond() Could not connect to host : %s", hostState.String())
 
+		metrics.SetHostState(hId, hostState)
 		err = svc.hss.Persist(&hvs.HostStatus{
 			HostID: hId,
 			HostStatusInformation: hvs.HostStatusInformation{
@@ -366,6 +373,7 @@ func (svc *Service) FetchDataAndRespond(hId uuid.UUID, connUrl string, preferHas
 	}
 	svc.workMap.Delete(hId)
 	svc.updateMissingHostDetails(hId, hostData)
+	metrics.SetHostState(hId, hvs.HostStateConnected)
 	err = svc.hss.Persist(&hvs.HostStatus{
 		HostID: hId,
 		HostStatusInformation: hvs.HostStatusInformation{
@@ -432,7 +440,9 @@ func (svc *Service) GetHostData(connUrl string, pcrList []int) (*hvs.HostManifes
 		return nil, err
 	}
 
+	start := time.Now()
 	data, err := connector.GetHostManifest(pcrList)
+	metrics.ObserveHostDataFetch(connUrl, start)
 	return &data, err
 }
 
@@ -10,11 +10,13 @@ import (
 	"runtime/debug"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models/taskstage"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/metrics"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/chnlworkq"
 	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
@@ -145,6 +147,13 @@ func (svc *Service) VerifyHost(hostId uuid.UUID, fetchHostData bool, preferHashM
 	return svc.verifier.Verify(hostId, hostData, newData, preferHashMatch)
 }
 
+func (svc *Service) EvaluateFlavors(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroups []hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/manager:EvaluateFlavors() Entering")
+	defer defaultLog.Trace("hosttrust/manager:EvaluateFlavors() Leaving")
+
+	return svc.verifier.Evaluate(hostId, hostData, flavorGroups, candidateFlavors)
+}
+
 func (svc *Service) ProcessQueue() error {
 	defaultLog.Trace("hosttrust/manager:ProcessQueue() Entering")
 	defer defaultLog.Trace("hosttrust/manager:ProcessQueue() Leaving")
@@ -302,6 +311,7 @@ func (svc *Service) queueFlavorVerify(hostsLists ...map[uuid.UUID]bool) {
 			// here the map already has the information that we need to start the job. The host data
 			// is not available - but the worker thread should just retrieve it individually from the
 			// go routine. So, all we have to do is submit requests
+			metrics.QueueAdded(metrics.QueueFlavorVerify)
 			svc.rqstChan <- hId
 			// the go routine that manages the work queue will process the request. It only blocks till the
 			// request is copied to the internal queue
@@ -433,6 +443,7 @@ func (svc *Service) doWork() {
 			return
 
 		case id := <-svc.workChan:
+			metrics.QueueRemoved(metrics.QueueFlavorVerify)
 			if hId, ok := id.(uuid.UUID); !ok {
 				defaultLog.Error("hosttrust/manager:doWork() expecting uuid from channel - but got different type")
 				return
@@ -464,6 +475,7 @@ func (svc *Service) doWork() {
 			}
 
 		case data := <-svc.hfWorkChan:
+			metrics.QueueRemoved(metrics.QueueFetchedDataVerify)
 			if hData, ok := data.(newHostFetch); !ok {
 				defaultLog.Error("hosttrust/manager:doWork() expecting newHostFetch type from channel - but got different one")
 				return
@@ -504,7 +516,9 @@ func (svc *Service) verifyHostData(hostId uuid.UUID, data *hvs.HostManifest, new
 		taskstage.StoreInContext(vtj.ctx, taskstage.FlavorVerifyStarted)
 	}
 
-	_, err := svc.verifier.Verify(hostId, data, newData, preferHashMatch)
+	start := time.Now()
+	report, err := svc.verifier.Verify(hostId, data, newData, preferHashMatch)
+	metrics.ObserveFlavorVerification(start, report, err)
 	if err != nil {
 		defaultLog.WithError(err).Errorf("hosttrust/manager:verifyHostData() Error while verification: %s", hostId.String())
 	}
@@ -531,6 +545,7 @@ func (svc *Service) ProcessHostData(ctx context.Context, host hvs.Host, data *hv
 
 	// queue the new data to be processed by one of the worker threads by adding this to the queue
 	taskstage.StoreInContext(ctx, taskstage.FlavorVerifyQueued)
+	metrics.QueueAdded(metrics.QueueFetchedDataVerify)
 	svc.hfRqstChan <- newHostFetch{
 		ctx:             ctx,
 		hostId:          host.Id,
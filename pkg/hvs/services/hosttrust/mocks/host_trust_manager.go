@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/mocks"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/hvs"
 	"time"
 )
 
@@ -28,3 +29,19 @@ func (mock *MockHostTrustManager) VerifyHostsAsync(hostIds []uuid.UUID, fetchHos
 func (mock *MockHostTrustManager) ProcessQueue() error {
 	return nil
 }
+
+// EvaluateFlavors returns a report with a trusted result for each candidate flavor
+func (mock *MockHostTrustManager) EvaluateFlavors(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroups []hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	trustReport := hvs.TrustReport{HostManifest: *hostData}
+	for _, candidate := range candidateFlavors {
+		flavorId := candidate.Flavor.Meta.ID
+		markers := []hvs.FlavorPartName{hvs.FlavorPartName(candidate.Flavor.Meta.Description[hvs.FlavorPartDescription].(string))}
+		trustReport.AddResult(hvs.RuleResult{
+			Rule:     hvs.RuleInfo{Name: "com.intel.mtwilson.core.verifier.policy.rule.FlavorTrusted", FlavorID: &flavorId, Markers: markers},
+			FlavorId: &flavorId,
+			Trusted:  true,
+		})
+	}
+	trustReport.Trusted = trustReport.IsTrusted()
+	return &trustReport, nil
+}
@@ -81,8 +81,15 @@ func areRequiredFlavorsMissing(cachedTrustReport hvs.TrustReport, flavorPart hvs
 
 // FlavorVerify.java: 529
 func (v *Verifier) createTrustReport(hostId uuid.UUID, hostData *hvs.HostManifest, reqs flvGrpHostTrustReqs, trustCache hostTrustCache, latestReqAndDefFlavorTypes map[hvs.FlavorPartName]bool) (hvs.TrustReport, error) {
-	defaultLog.Trace("hosttrust/trust_report:createTrustReport() Entering")
-	defer defaultLog.Trace("hosttrust/trust_report:createTrustReport() Leaving")
+	return v.buildTrustReport(hostId, hostData, reqs, trustCache, latestReqAndDefFlavorTypes, nil, true)
+}
+
+// buildTrustReport verifies the host data against the flavors of the flavorgroup and the candidate flavors.
+// The flavors the host is trusted for are only added to the host's flavor trust cache when updateTrustCache is set.
+func (v *Verifier) buildTrustReport(hostId uuid.UUID, hostData *hvs.HostManifest, reqs flvGrpHostTrustReqs, trustCache hostTrustCache, latestReqAndDefFlavorTypes map[hvs.FlavorPartName]bool,
+	candidateFlavors []hvs.SignedFlavor, updateTrustCache bool) (hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/trust_report:buildTrustReport() Entering")
+	defer defaultLog.Trace("hosttrust/trust_report:buildTrustReport() Leaving")
 
 	flavorParts := []hvs.FlavorPartName{}
 	for flavorPart := range latestReqAndDefFlavorTypes {
@@ -91,15 +98,21 @@ func (v *Verifier) createTrustReport(hostId uuid.UUID, hostData *hvs.HostManifes
 
 	hostManifestMap, err := getHostManifestMap(hostData, flavorParts)
 	if err != nil {
-		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error while creating host manifest map")
+		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:buildTrustReport() Error while creating host manifest map")
 	}
 	flavorsToVerify, err := v.findFlavors(reqs.FlavorGroupId, latestReqAndDefFlavorTypes, hostManifestMap)
 	if err != nil {
-		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error while finding flavors")
+		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:buildTrustReport() Error while finding flavors")
 	}
-	trustReport, err := v.verifyFlavors(hostId, flavorsToVerify, hostData, reqs)
+	trustReport, trustedFlavorIds, err := v.verifyFlavors(hostId, append(flavorsToVerify, candidateFlavors...), hostData, reqs)
 	if err != nil {
-		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error while verifying flavors")
+		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:buildTrustReport() Error while verifying flavors")
+	}
+	// save the trust cache // ignore error since it is just a cache.
+	if updateTrustCache && len(trustedFlavorIds) > 0 {
+		if _, err := v.HostStore.AddTrustCacheFlavors(hostId, trustedFlavorIds); err != nil {
+			log.Error("hosttrust/trust_report:buildTrustReport() error while adding flavor trust cache to store for host id ", hostId, "error - ", err)
+		}
 	}
 	if !trustCache.isTrustCacheEmpty() {
 		trustReport.AddResults(trustCache.trustReport.Results)
@@ -114,7 +127,7 @@ func (v *Verifier) createTrustReport(hostId uuid.UUID, hostData *hvs.HostManifes
 
 	trustReport, err = ruleAllOfFlavors.AddFaults(trustReport)
 	if err != nil {
-		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:createTrustReport() Error applying ruleAllOfFlavors")
+		return hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:buildTrustReport() Error applying ruleAllOfFlavors")
 	}
 	return *trustReport, nil
 }
@@ -140,7 +153,8 @@ type flavorReport struct {
 }
 
 // FlavorVerify.java: 405
-func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, hostData *hvs.HostManifest, hostTrustReqs flvGrpHostTrustReqs) (*hvs.TrustReport, error) {
+// verifyFlavors returns the collective trust report and the IDs of the flavors that were added to it
+func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, hostData *hvs.HostManifest, hostTrustReqs flvGrpHostTrustReqs) (*hvs.TrustReport, []uuid.UUID, error) {
 	defaultLog.Trace("hosttrust/trust_report:verifyFlavors() Entering")
 	defer defaultLog.Trace("hosttrust/trust_report:verifyFlavors() Leaving")
 
@@ -166,7 +180,7 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 
 				individualTrustReport, err := v.FlavorVerifier.Verify(hostData, &signedFlavor, v.SkipFlavorSignatureVerification)
 				if err != nil {
-					return &hvs.TrustReport{}, errors.Wrap(err, "hosttrust/trust_report:verifyFlavors() Error verifying flavor")
+					return &hvs.TrustReport{}, nil, errors.Wrap(err, "hosttrust/trust_report:verifyFlavors() Error verifying flavor")
 				}
 				if individualTrustReport.Trusted {
 					if reflect.DeepEqual(collectiveTrustReport, hvs.TrustReport{}) {
@@ -234,14 +248,10 @@ func (v *Verifier) verifyFlavors(hostID uuid.UUID, flavors []hvs.SignedFlavor, h
 		//TODO - check if we return an error here
 		return &hvs.TrustReport{
 			HostManifest: *hostData,
-		}, nil
-	}
-	// save the trust cache // ignore error since it is just a cache.
-	if _, err := v.HostStore.AddTrustCacheFlavors(hostID, newTrustCaches); err != nil {
-		log.Error("hosttrust/trust_report:verifyFlavors() error while adding flavor trust cache to store for host id ", hostID, "error - ", err)
+		}, nil, nil
 	}
 
-	return &collectiveTrustReport, nil
+	return &collectiveTrustReport, newTrustCaches, nil
 }
 
 // FlavorVerify.java: 684
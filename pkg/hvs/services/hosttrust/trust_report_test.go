@@ -159,7 +159,7 @@ func TestVerifier_verifyFlavors(t *testing.T) {
 				hostQuoteReportCache:            tt.fields.hostQuoteReportCache,
 				HostTrustCache:                  tt.fields.HostTrustCache,
 			}
-			got, err := v.verifyFlavors(tt.args.hostID, tt.args.flavors, tt.args.hostData, tt.args.hostTrustReqs)
+			got, _, err := v.verifyFlavors(tt.args.hostID, tt.args.flavors, tt.args.hostData, tt.args.hostTrustReqs)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Verifier.verifyFlavors() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -34,6 +34,7 @@ type Verifier struct {
 	SkipFlavorSignatureVerification bool
 	hostQuoteReportCache            map[uuid.UUID]*models.QuoteReportCache
 	HostTrustCache                  *lru.Cache
+	AttestationPolicyStore          domain.AttestationPolicyStore
 }
 
 func NewVerifier(cfg domain.HostTrustVerifierConfig) domain.HostTrustVerifier {
@@ -48,6 +49,7 @@ func NewVerifier(cfg domain.HostTrustVerifierConfig) domain.HostTrustVerifier {
 		SkipFlavorSignatureVerification: cfg.SkipFlavorSignatureVerification,
 		HostTrustCache:                  cfg.HostTrustCache,
 		hostQuoteReportCache:            make(map[uuid.UUID]*models.QuoteReportCache),
+		AttestationPolicyStore:          cfg.AttestationPolicyStore,
 	}
 }
 
@@ -173,7 +175,7 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData
 	log.Debugf("hosttrust/verifier:Verify() Final results in report: %d", len(finalTrustReport.Results))
 	if len(finalTrustReport.Results) > 0 && (!finalReportValid || newData) {
 		log.Debugf("hosttrust/verifier:Verify() Generating new SAML for host: %s", hostId)
-		samlReportGen := NewSamlReportGenerator(&v.SamlIssuer)
+		samlReportGen := NewSamlReportGenerator(v.getSamlIssuer(flvGroupIds))
 		samlReport := samlReportGen.GenerateSamlReport(&finalTrustReport)
 		finalTrustReport.Trusted = finalTrustReport.IsTrusted()
 		log.Debugf("hosttrust/verifier:Verify() Saving new report for host: %s", hostId)
@@ -194,6 +196,74 @@ func (v *Verifier) Verify(hostId uuid.UUID, hostData *hvs.HostManifest, newData
 	return hvsReport, nil
 }
 
+// Evaluate verifies the host data against the flavors of the flavorgroups, with the candidate flavors added to
+// each flavorgroup, and returns the resulting trust report. Without flavorgroups the host has to match every
+// candidate flavor. Unlike Verify, neither the trust caches nor the stored reports are read or updated.
+func (v *Verifier) Evaluate(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroups []hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	defaultLog.Trace("hosttrust/verifier:Evaluate() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:Evaluate() Leaving")
+
+	if hostData == nil {
+		return nil, ErrInvalidHostManiFest
+	}
+	trustReport := hvs.TrustReport{HostManifest: *hostData}
+
+	if len(flavorGroups) == 0 {
+		for i := range candidateFlavors {
+			flavorReport, err := v.FlavorVerifier.Verify(hostData, &candidateFlavors[i], v.SkipFlavorSignatureVerification)
+			if err != nil {
+				return nil, errors.Wrap(err, "hosttrust/verifier:Evaluate() Error verifying flavor")
+			}
+			trustReport.AddResults(flavorReport.Results)
+		}
+		trustReport.Trusted = trustReport.IsTrusted()
+		return &trustReport, nil
+	}
+
+	hostUniqueFlavorPartsMap := make(map[hvs.FlavorPartName]bool)
+	if hostId != uuid.Nil {
+		hostUniqueFlavorParts, err := v.HostStore.RetrieveDistinctUniqueFlavorParts(hostId)
+		if err != nil {
+			return nil, errors.Wrap(err, "hosttrust/verifier:Evaluate() Error while retrieving host unique flavor parts")
+		}
+		for _, flavorPart := range hostUniqueFlavorParts {
+			hostUniqueFlavorPartsMap[hvs.FlavorPartName(flavorPart)] = true
+		}
+	}
+
+	for _, fg := range flavorGroups {
+		fgTrustReqs, err := NewFlvGrpHostTrustReqs(hostId, hostUniqueFlavorPartsMap, fg, v.FlavorStore, v.FlavorGroupStore, hostData, v.SkipFlavorSignatureVerification)
+		if err != nil {
+			return nil, errors.Wrap(err, "hosttrust/verifier:Evaluate() Error while retrieving NewFlvGrpHostTrustReqs")
+		}
+
+		// the candidate flavors are handled as if they had been added to the flavorgroup
+		var fgCandidateFlavors []hvs.SignedFlavor
+		for _, candidate := range candidateFlavors {
+			flavorPart, _ := candidate.Flavor.Meta.Description[hvs.FlavorPartDescription].(string)
+			matchPolicy, exists := fgTrustReqs.FlavorPartMatchPolicy[hvs.FlavorPartName(flavorPart)]
+			if !exists {
+				continue
+			}
+			if matchPolicy.Required == hvs.FlavorRequiredIfDefined {
+				fgTrustReqs.DefinedAndRequiredFlavorTypes[hvs.FlavorPartName(flavorPart)] = true
+			}
+			if matchPolicy.MatchType == hvs.MatchTypeAllOf {
+				fgTrustReqs.AllOfFlavors = append(fgTrustReqs.AllOfFlavors, candidate)
+			}
+			fgCandidateFlavors = append(fgCandidateFlavors, candidate)
+		}
+
+		fgTrustReport, err := v.buildTrustReport(hostId, hostData, *fgTrustReqs, hostTrustCache{}, fgTrustReqs.GetLatestFlavorTypeMap(), fgCandidateFlavors, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "hosttrust/verifier:Evaluate() Error while creating flavorgroup report")
+		}
+		trustReport.AddResults(fgTrustReport.Results)
+	}
+	trustReport.Trusted = trustReport.IsTrusted()
+	return &trustReport, nil
+}
+
 func (v *Verifier) getCachedFlavors(hostId uuid.UUID, flavGrpId uuid.UUID) ([]hvs.SignedFlavor, error) {
 	defaultLog.Trace("hosttrust/verifier:getCachedFlavors() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:getCachedFlavors() Leaving")
@@ -251,11 +321,43 @@ func (v *Verifier) refreshTrustReport(hostID uuid.UUID, cache *models.QuoteRepor
 	defer defaultLog.Trace("hosttrust/verifier:refreshTrustReport() Leaving")
 	log.Debugf("hosttrust/verifier:refreshTrustReport() Generating SAML for host: %s using existing trust report", hostID)
 
-	samlReportGen := NewSamlReportGenerator(&v.SamlIssuer)
+	flvGroupIds, err := v.HostStore.SearchFlavorgroups(hostID)
+	if err != nil {
+		return nil, errors.Wrap(err, "hosttrust/verifier:refreshTrustReport() Error while retrieving flavorgroups of host")
+	}
+	samlReportGen := NewSamlReportGenerator(v.getSamlIssuer(flvGroupIds))
 	samlReport := samlReportGen.GenerateSamlReport(cache.TrustReport)
 	return v.storeTrustReport(hostID, cache.TrustReport, &samlReport), nil
 }
 
+// getSamlIssuer returns the SAML issuer configuration with the shortest SAML validity set by the attestation
+// policies of the flavorgroups, or the configured one if none of them sets a SAML validity
+func (v *Verifier) getSamlIssuer(flvGroupIds []uuid.UUID) *saml.IssuerConfiguration {
+	defaultLog.Trace("hosttrust/verifier:getSamlIssuer() Entering")
+	defer defaultLog.Trace("hosttrust/verifier:getSamlIssuer() Leaving")
+
+	if v.AttestationPolicyStore == nil || len(flvGroupIds) == 0 {
+		return &v.SamlIssuer
+	}
+	policies, err := v.AttestationPolicyStore.Search(&models.AttestationPolicyFilterCriteria{FlavorgroupIds: flvGroupIds})
+	if err != nil {
+		log.WithError(err).Warn("hosttrust/verifier:getSamlIssuer() Error while retrieving attestation policies, using configured SAML validity")
+		return &v.SamlIssuer
+	}
+
+	samlIssuer := v.SamlIssuer
+	samlIssuer.ValiditySeconds = 0
+	for _, policy := range policies {
+		if policy.SamlValiditySeconds > 0 && (samlIssuer.ValiditySeconds == 0 || policy.SamlValiditySeconds < samlIssuer.ValiditySeconds) {
+			samlIssuer.ValiditySeconds = policy.SamlValiditySeconds
+		}
+	}
+	if samlIssuer.ValiditySeconds == 0 {
+		return &v.SamlIssuer
+	}
+	return &samlIssuer
+}
+
 func (v *Verifier) storeTrustReport(hostID uuid.UUID, trustReport *hvs.TrustReport, samlReport *saml.SamlAssertion) *models.HVSReport {
 	defaultLog.Trace("hosttrust/verifier:storeTrustReport() Entering")
 	defer defaultLog.Trace("hosttrust/verifier:storeTrustReport() Leaving")
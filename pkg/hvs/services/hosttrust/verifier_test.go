@@ -95,3 +95,108 @@ func TestVerifier_validateCachedFlavors(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifier_Evaluate(t *testing.T) {
+	var platform hvs.SignedFlavor
+	json.Unmarshal([]byte(platformFlavor), &platform)
+	var software hvs.SignedFlavor
+	json.Unmarshal([]byte(softwareFlavor), &software)
+	type args struct {
+		hostData         *hvs.HostManifest
+		candidateFlavors []hvs.SignedFlavor
+	}
+	tests := []struct {
+		name        string
+		verifier    flavorVerifier.Verifier
+		args        args
+		wantTrusted bool
+		wantErr     bool
+	}{
+		{
+			name:     "Candidate flavors are verified without a flavorgroup",
+			verifier: &verify{errorStatus: "Contains faults"},
+			args: args{
+				hostData:         &hvs.HostManifest{},
+				candidateFlavors: []hvs.SignedFlavor{platform, software},
+			},
+			wantTrusted: false,
+		},
+		{
+			name:     "Missing host manifest",
+			verifier: &verify{errorStatus: "No error"},
+			args: args{
+				candidateFlavors: []hvs.SignedFlavor{platform},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostTrustCache, _ := lru.New(10)
+			// the report, host and flavor stores are left unset: a dry run must not use them
+			v := &Verifier{
+				FlavorVerifier: tt.verifier,
+				HostTrustCache: hostTrustCache,
+			}
+			got, err := v.Evaluate(uuid.New(), tt.args.hostData, nil, tt.args.candidateFlavors)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Trusted != tt.wantTrusted {
+				t.Errorf("Verifier.Evaluate() trusted = %v, want %v", got.Trusted, tt.wantTrusted)
+			}
+			if hostTrustCache.Len() != 0 {
+				t.Errorf("Verifier.Evaluate() added %d entries to the host trust cache", hostTrustCache.Len())
+			}
+		})
+	}
+}
+
+func TestVerifier_getSamlIssuer(t *testing.T) {
+	criticalFgId := uuid.New()
+	labFgId := uuid.New()
+	unscheduledFgId := uuid.New()
+	v := &Verifier{
+		SamlIssuer: saml.IssuerConfiguration{ValiditySeconds: 86400},
+		AttestationPolicyStore: mocks.NewMockAttestationPolicyStore(
+			hvs.AttestationPolicy{FlavorgroupId: criticalFgId, SamlValiditySeconds: 300},
+			hvs.AttestationPolicy{FlavorgroupId: labFgId, SamlValiditySeconds: 3600},
+			hvs.AttestationPolicy{FlavorgroupId: unscheduledFgId, RefreshPeriodSeconds: 600},
+		),
+	}
+	tests := []struct {
+		name         string
+		flvGroupIds  []uuid.UUID
+		wantValidity int
+	}{
+		{
+			name:         "Shortest SAML validity of the flavorgroups",
+			flvGroupIds:  []uuid.UUID{labFgId, criticalFgId},
+			wantValidity: 300,
+		},
+		{
+			name:         "Flavorgroup policy without SAML validity",
+			flvGroupIds:  []uuid.UUID{unscheduledFgId},
+			wantValidity: 86400,
+		},
+		{
+			name:         "Flavorgroups without policy",
+			flvGroupIds:  []uuid.UUID{uuid.New()},
+			wantValidity: 86400,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.getSamlIssuer(tt.flvGroupIds).ValiditySeconds; got != tt.wantValidity {
+				t.Errorf("Verifier.getSamlIssuer() validity = %v, want %v", got, tt.wantValidity)
+			}
+		})
+	}
+	if v.SamlIssuer.ValiditySeconds != 86400 {
+		t.Errorf("Verifier.getSamlIssuer() changed the configured SAML validity")
+	}
+}
@@ -7,10 +7,13 @@ package hrrs
 
 import (
 	"context"
+	"math/rand"
 	"runtime/debug"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/hvs/domain/models"
 	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 
 	"github.com/pkg/errors"
@@ -18,7 +21,9 @@ import (
 
 // HostReportRefresher runs in the background and periodically queries HVS'
 // reports to see if they have been expired.  If so, they are passed to
-// the HostTrustManager queue to be updated.
+// the HostTrustManager queue to be updated.  The hosts of flavorgroups with
+// a scheduled attestation policy are also queued at the refresh period of
+// the policy.
 type HostReportRefresher interface {
 	Run() error
 	Stop() error
@@ -29,22 +34,36 @@ var (
 	defaultLog       = commLog.GetDefaultLogger()
 )
 
-func NewHostReportRefresher(cfg HRRSConfig, reportStore domain.ReportStore, hostTrustManager domain.HostTrustManager) (HostReportRefresher, error) {
+func NewHostReportRefresher(cfg HRRSConfig, reportStore domain.ReportStore, flavorGroupStore domain.FlavorGroupStore,
+	attestationPolicyStore domain.AttestationPolicyStore, hostTrustManager domain.HostTrustManager) (HostReportRefresher, error) {
 
 	return &hostReportRefresherImpl{
-		reportStore:      reportStore,
-		hostTrustManager: hostTrustManager,
-		cfg:              cfg,
-		fromTime:         firstFromTime,
+		reportStore:            reportStore,
+		flavorGroupStore:       flavorGroupStore,
+		attestationPolicyStore: attestationPolicyStore,
+		hostTrustManager:       hostTrustManager,
+		cfg:                    cfg,
+		fromTime:               firstFromTime,
+		nextAttestations:       make(map[uuid.UUID]scheduledAttestation),
 	}, nil
 }
 
 type hostReportRefresherImpl struct {
-	reportStore      domain.ReportStore
-	hostTrustManager domain.HostTrustManager
-	cfg              HRRSConfig
-	ctx              context.Context
-	fromTime         time.Time
+	reportStore            domain.ReportStore
+	flavorGroupStore       domain.FlavorGroupStore
+	attestationPolicyStore domain.AttestationPolicyStore
+	hostTrustManager       domain.HostTrustManager
+	cfg                    HRRSConfig
+	ctx                    context.Context
+	cancel                 context.CancelFunc
+	fromTime               time.Time
+	// nextAttestations holds the next scheduled attestation of each flavorgroup with a scheduled policy
+	nextAttestations map[uuid.UUID]scheduledAttestation
+}
+
+type scheduledAttestation struct {
+	refreshPeriod time.Duration
+	at            time.Time
 }
 
 func (refresher *hostReportRefresherImpl) Run() error {
@@ -56,7 +75,11 @@ func (refresher *hostReportRefresherImpl) Run() error {
 		return nil
 	}
 
-	refresher.ctx = context.Background()
+	refresher.ctx, refresher.cancel = context.WithCancel(context.Background())
+
+	if refresher.attestationPolicyStore != nil {
+		go refresher.runScheduledAttestations()
+	}
 
 	go func() {
 		defer func() {
@@ -77,6 +100,7 @@ func (refresher *hostReportRefresherImpl) Run() error {
 				// continue with the loop and refresh reports again
 			case <-refresher.ctx.Done():
 				defaultLog.Info("The HRRS has been stopped and will now exit")
+				return
 			}
 		}
 	}()
@@ -86,7 +110,7 @@ func (refresher *hostReportRefresherImpl) Run() error {
 
 func (refresher *hostReportRefresherImpl) Stop() error {
 	if refresher.ctx != nil {
-		refresher.ctx.Done()
+		refresher.cancel()
 	} else {
 		defaultLog.Debug("The HRRS is not running")
 	}
@@ -128,3 +152,98 @@ func (refresher *hostReportRefresherImpl) refreshReports() error {
 
 	return nil
 }
+
+// runScheduledAttestations queues the hosts of the flavorgroups with a scheduled attestation policy, until the HRRS
+// is stopped. The policies are reloaded at least every SchedulePollPeriod to take API changes into account.
+func (refresher *hostReportRefresherImpl) runScheduledAttestations() {
+	defer func() {
+		if err := recover(); err != nil {
+			defaultLog.Errorf("Panic occurred: %+v", err)
+			defaultLog.Error(string(debug.Stack()))
+		}
+	}()
+	for {
+		wait, err := refresher.attestScheduledFlavorgroups(time.Now())
+		if err != nil {
+			// log any errors, but do not stop the scheduled attestations
+			defaultLog.Errorf("HRRS encountered an error while attesting scheduled flavorgroups...\n%+v\n", err)
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-refresher.ctx.Done():
+			return
+		}
+	}
+}
+
+// attestScheduledFlavorgroups queues the hosts of the flavorgroups whose scheduled attestation is due and returns
+// how long to wait before the next one.
+//
+// The first attestation of a flavorgroup is scheduled at a random time within its refresh period, so that the
+// flavorgroups are not all attested when HVS starts. The following ones are scheduled one refresh period later,
+// delayed by a random jitter.
+func (refresher *hostReportRefresherImpl) attestScheduledFlavorgroups(now time.Time) (time.Duration, error) {
+
+	wait := refresher.schedulePollPeriod()
+	policies, err := refresher.attestationPolicyStore.Search(&models.AttestationPolicyFilterCriteria{Scheduled: true})
+	if err != nil {
+		return wait, errors.Wrap(err, "An error occurred while HRRS searched for attestation policies")
+	}
+
+	scheduledFlavorgroups := make(map[uuid.UUID]bool, len(policies))
+	for _, policy := range policies {
+		scheduledFlavorgroups[policy.FlavorgroupId] = true
+
+		next, ok := refresher.nextAttestations[policy.FlavorgroupId]
+		if !ok || next.refreshPeriod != policy.RefreshPeriod() {
+			next = scheduledAttestation{
+				refreshPeriod: policy.RefreshPeriod(),
+				at:            now.Add(randomDuration(policy.RefreshPeriod())),
+			}
+		}
+
+		if !next.at.After(now) {
+			hostIDs, err := refresher.flavorGroupStore.SearchHostsByFlavorGroup(policy.FlavorgroupId)
+			if err != nil {
+				return wait, errors.Wrapf(err, "An error occurred while HRRS searched for the hosts of flavorgroup %s", policy.FlavorgroupId)
+			}
+			if len(hostIDs) > 0 {
+				err = refresher.hostTrustManager.VerifyHostsAsync(hostIDs, true, true)
+				if err != nil {
+					return wait, errors.Wrap(err, "HRRS encountered an error calling the host trust manager")
+				}
+			}
+			defaultLog.Infof("HRRS queued %d hosts of flavorgroup %s for scheduled attestation", len(hostIDs), policy.FlavorgroupId)
+			next.at = now.Add(policy.RefreshPeriod() + randomDuration(policy.Jitter()))
+		}
+		refresher.nextAttestations[policy.FlavorgroupId] = next
+
+		if until := next.at.Sub(now); until < wait {
+			wait = until
+		}
+	}
+
+	// forget the flavorgroups whose policy has been removed
+	for flavorgroupId := range refresher.nextAttestations {
+		if !scheduledFlavorgroups[flavorgroupId] {
+			delete(refresher.nextAttestations, flavorgroupId)
+		}
+	}
+	return wait, nil
+}
+
+func (refresher *hostReportRefresherImpl) schedulePollPeriod() time.Duration {
+	if refresher.cfg.SchedulePollPeriod == 0 {
+		return DefaultSchedulePollPeriod
+	}
+	return refresher.cfg.SchedulePollPeriod
+}
+
+// randomDuration returns a random duration in [0, max)
+func randomDuration(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
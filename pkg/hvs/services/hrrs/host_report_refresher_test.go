@@ -73,7 +73,7 @@ func TestHostReportRefresher(t *testing.T) {
 	// create a new HostReportRefresher, 'run' the backgound thread and then
 	// sleep for ten seconds.  We expect the expired report to be updated
 	// in the report store.
-	refresher, err := NewHostReportRefresher(cfg, reportStore, hostTrustManager)
+	refresher, err := NewHostReportRefresher(cfg, reportStore, mocks.NewFakeFlavorgroupStore(), mocks.NewMockAttestationPolicyStore(), hostTrustManager)
 	assert.NoError(t, err)
 	err = refresher.Run()
 	assert.NoError(t, err)
@@ -100,6 +100,55 @@ func TestHostReportRefresher(t *testing.T) {
 	}
 }
 
+func TestHostReportRefresherScheduledAttestation(t *testing.T) {
+
+	scheduledFlavorgroupID := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+	unscheduledFlavorgroupID := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e3")
+	hostID := uuid.New()
+	flavorGroupStore := mocks.NewFakeFlavorgroupStore()
+	flavorGroupStore.HostFlavorgroupStore = []*hvs.HostFlavorgroup{
+		{HostId: hostID, FlavorgroupId: scheduledFlavorgroupID},
+		{HostId: uuid.New(), FlavorgroupId: unscheduledFlavorgroupID},
+	}
+	attestationPolicyStore := mocks.NewMockAttestationPolicyStore(
+		hvs.AttestationPolicy{FlavorgroupId: scheduledFlavorgroupID, RefreshPeriodSeconds: 300, JitterSeconds: 30},
+		hvs.AttestationPolicy{FlavorgroupId: unscheduledFlavorgroupID, SamlValiditySeconds: 600},
+	)
+	reportStore := mocks.NewEmptyMockReportStore()
+
+	refresher, err := NewHostReportRefresher(HRRSConfig{}, reportStore, flavorGroupStore, attestationPolicyStore,
+		MockHostTrustManager{reportStore: reportStore})
+	assert.NoError(t, err)
+	impl := refresher.(*hostReportRefresherImpl)
+
+	// the first attestation is scheduled within the refresh period
+	now := time.Now()
+	wait, err := impl.attestScheduledFlavorgroups(now)
+	assert.NoError(t, err)
+	assert.True(t, wait <= DefaultSchedulePollPeriod)
+	next := impl.nextAttestations[scheduledFlavorgroupID]
+	assert.False(t, next.at.Before(now))
+	assert.True(t, next.at.Before(now.Add(5*time.Minute)))
+	assert.NotContains(t, impl.nextAttestations, unscheduledFlavorgroupID)
+
+	// once due, the hosts of the flavorgroup are queued and the next attestation is one period plus jitter later
+	due := next.at
+	_, err = impl.attestScheduledFlavorgroups(due)
+	assert.NoError(t, err)
+	reports, err := reportStore.Search(&models.ReportFilterCriteria{HostID: hostID})
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	next = impl.nextAttestations[scheduledFlavorgroupID]
+	assert.False(t, next.at.Before(due.Add(5*time.Minute)))
+	assert.True(t, next.at.Before(due.Add(5*time.Minute+30*time.Second)))
+
+	// the schedule is dropped with the policy
+	assert.NoError(t, attestationPolicyStore.Delete(scheduledFlavorgroupID))
+	_, err = impl.attestScheduledFlavorgroups(due)
+	assert.NoError(t, err)
+	assert.Empty(t, impl.nextAttestations)
+}
+
 //-------------------------------------------------------------------------------------------------
 // M O C K   H O S T   T R U S T   M A N A G E R
 //-------------------------------------------------------------------------------------------------
@@ -115,6 +164,10 @@ func (htm MockHostTrustManager) ProcessQueue() error {
 	return errors.New("ProcessQueue is not implemented")
 }
 
+func (htm MockHostTrustManager) EvaluateFlavors(hostId uuid.UUID, hostData *hvs.HostManifest, flavorGroups []hvs.FlavorGroup, candidateFlavors []hvs.SignedFlavor) (*hvs.TrustReport, error) {
+	return nil, errors.New("EvaluateFlavors is not implemented")
+}
+
 func (htm MockHostTrustManager) VerifyHostsAsync(hostIDs []uuid.UUID, fetchHostData, preferHashMatch bool) error {
 
 	for _, hostID := range hostIDs {
@@ -9,10 +9,16 @@ import "time"
 var (
 	// DefaultRefreshPeriod by default check for expired reports every five minutes
 	DefaultRefreshPeriod, _ = time.ParseDuration("5m")
+
+	// DefaultSchedulePollPeriod by default reload the attestation policies of flavorgroups every minute
+	DefaultSchedulePollPeriod, _ = time.ParseDuration("1m")
 )
 
 type HRRSConfig struct {
 	// RefreshPeriod determines how frequently the HRRS checks for expired reports (defaults to
 	// DefaultRefreshPeriod).
 	RefreshPeriod time.Duration `yaml:"refresh-period" mapstructure:"refresh-period"`
+	// SchedulePollPeriod is the maximum delay before changes to the attestation policies of flavorgroups are taken
+	// into account (defaults to DefaultSchedulePollPeriod).
+	SchedulePollPeriod time.Duration `yaml:"schedule-poll-period" mapstructure:"schedule-poll-period"`
 }
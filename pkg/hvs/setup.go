@@ -196,6 +196,7 @@ func (a *App) setupTaskRunner() (*setup.Runner, error) {
 			WriteTimeout:      viper.GetDuration(commConfig.ServerWriteTimeout),
 			IdleTimeout:       viper.GetDuration(commConfig.ServerIdleTimeout),
 			MaxHeaderBytes:    viper.GetInt(commConfig.ServerMaxHeaderBytes),
+			MetricsEnabled:    viper.GetBool(commConfig.ServerMetricsEnabled),
 		},
 		DefaultPort:   constants.DefaultHVSListenerPort,
 		AppConfig:     &a.Config,
@@ -301,7 +302,7 @@ func (a *App) selfSignTask(name string) setup.Task {
 	}
 }
 
-// The HRRS does not require setup, just one configuration parameter.  This function
+// The HRRS does not require setup, just a few configuration parameters.  This function
 // populates the HRRS config during 'hvs setup'.
 //
 // The function needs to handle...
@@ -321,4 +322,9 @@ func (a *App) setupHRRSConfig() {
 	if refreshPeriod != hrrs.DefaultRefreshPeriod {
 		a.Config.HRRS.RefreshPeriod = refreshPeriod
 	}
+
+	schedulePollPeriod := viper.GetDuration(constants.HrrsSchedulePollPeriod)
+	if schedulePollPeriod != hrrs.DefaultSchedulePollPeriod {
+		a.Config.HRRS.SchedulePollPeriod = schedulePollPeriod
+	}
 }
@@ -40,6 +40,7 @@ var envHelp = map[string]string{
 	"LOG_ENABLE_STDOUT":                      "Enable console log",
 	"AAS_BASE_URL":                           "AAS Base URL",
 	"HRRS_REFRESH_PERIOD":                    "Host report refresh service period",
+	"HRRS_SCHEDULE_POLL_PERIOD":              "Period at which the flavorgroup attestation policies are reloaded",
 	"VCSS_REFRESH_PERIOD":                    "VCenter refresh service period",
 	"FVS_NUMBER_OF_VERIFIERS":                "Number of Flavor verification verifier threads",
 	"FVS_NUMBER_OF_DATA_FETCHERS":            "Number of Flavor verification data fetcher threads",
@@ -51,6 +52,7 @@ var envHelp = map[string]string{
 	"SERVER_WRITE_TIMEOUT":                   "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":                    "Request Idle Timeout in Seconds",
 	"SERVER_MAX_HEADER_BYTES":                "Max Length of Request Header in Bytes",
+	"SERVER_METRICS_ENABLED":                 "Expose the /metrics endpoint in Prometheus text format when set to true",
 	"NAT_SERVERS":                            "List of NATs servers to establish connection with outbound TAs",
 	"ENABLE_EKCERT_REVOKE_CHECK":             "If enabled, revocation checks will be performed for EK certs at the time of AIK provisioning",
 	"IMA_MEASURE_ENABLED":                    "To enable Ima-Measure support in hvs",
@@ -83,7 +85,8 @@ func (uc UpdateServiceConfig) Run() error {
 	(*uc.AppConfig).HVS = uc.ServiceConfig
 	(*uc.AppConfig).IMAMeasureEnabled = viper.GetBool(constants.IMAMeasureEnabled)
 	(*uc.AppConfig).HRRS = hrrs.HRRSConfig{
-		RefreshPeriod: viper.GetDuration(constants.HrrsRefreshPeriod),
+		RefreshPeriod:      viper.GetDuration(constants.HrrsRefreshPeriod),
+		SchedulePollPeriod: viper.GetDuration(constants.HrrsSchedulePollPeriod),
 	}
 	(*uc.AppConfig).VCSS = config.VCSSConfig{
 		RefreshPeriod: viper.GetDuration(constants.VcssRefreshPeriod),
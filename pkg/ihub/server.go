@@ -7,6 +7,8 @@ package ihub
 import (
 	"encoding/pem"
 	"github.com/intel-secl/intel-secl/v5/pkg/clients/k8s"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/certrenewer"
+	commConstants "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
 	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
 	"io/ioutil"
@@ -102,6 +104,23 @@ func (app *App) startDaemon() error {
 		return errors.Errorf("startService:startDaemon() Endpoint type '%s' is not supported", configuration.Endpoint.Type)
 	}
 
+	// iHub serves no TLS connection, so its TLS certificate is only renewed on disk
+	trustedCAsStoreDir := app.configDir() + constants.TrustedCAsStoreDir
+	certRenewer := certrenewer.NewRenewer(configuration.CMSBaseURL, trustedCAsStoreDir,
+		certrenewer.NewAasTokenProvider(configuration.AASBaseUrl, configuration.IHUB.Username,
+			configuration.IHUB.Password, trustedCAsStoreDir))
+	_, err := certRenewer.Watch(certrenewer.Certificate{
+		CertFile: app.configDir() + constants.DefaultTLSCertFile,
+		KeyFile:  app.configDir() + constants.DefaultTLSKeyFile,
+		CertType: commConstants.CertTypeTls,
+	})
+	if err != nil {
+		log.WithError(err).Warn("startService:startDaemon() The ihub TLS certificate is not renewed")
+	} else {
+		certRenewer.Start()
+		defer certRenewer.Stop()
+	}
+
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -20,8 +20,9 @@ var defaultLog = log.GetDefaultLogger()
 // Constants for viper variable names. Will be used to set
 // default values as well as to get each value
 const (
-	EndpointUrl = "endpoint-url"
-	KeyManager  = "key-manager"
+	EndpointUrl   = "endpoint-url"
+	KeyManager    = "key-manager"
+	MetadataStore = "metadata-store"
 
 	KmipVersion        = "kmip.version"
 	KmipServerIP       = "kmip.server-ip"
@@ -32,6 +33,11 @@ const (
 	KmipClientKeyPath  = "kmip.client-key-path"
 	KmipClientCertPath = "kmip.client-cert-path"
 	KmipRootCertPath   = "kmip.root-cert-path"
+	DirectoryKeysDir   = "directory.keys-dir"
+	DirectoryMasterKey = "directory.master-key-file"
+	Pkcs11ModulePath   = "pkcs11.module-path"
+	Pkcs11TokenLabel   = "pkcs11.token-label"
+	Pkcs11Pin          = "pkcs11.pin"
 	KBSServiceUsername = "kbs.service-username"
 	KBSServicePassword = "kbs.service-password"
 )
@@ -45,12 +51,19 @@ type Configuration struct {
 	KeyManager  string                   `yaml:"key-manager" mapstructure:"key-manager"`
 	KBS         commConfig.ServiceConfig `yaml:"kbs"`
 
+	// MetadataStore selects the backend of the keys, key transfer policies and key transfer events, either directory
+	// or postgres
+	MetadataStore string              `yaml:"metadata-store" mapstructure:"metadata-store"`
+	DB            commConfig.DBConfig `yaml:"db" mapstructure:"db"`
+
 	TLS    commConfig.TLSCertConfig `yaml:"tls"`
 	Log    commConfig.LogConfig     `yaml:"log"`
 	Server commConfig.ServerConfig  `yaml:"server"`
 
-	Kmip KmipConfig `yaml:"kmip" mapstructure:"kmip"`
-	Skc  SKCConfig  `yaml:"skc" mapstructure:"skc"`
+	Kmip      KmipConfig      `yaml:"kmip" mapstructure:"kmip"`
+	Directory DirectoryConfig `yaml:"directory" mapstructure:"directory"`
+	Pkcs11    Pkcs11Config    `yaml:"pkcs11" mapstructure:"pkcs11"`
+	Skc       SKCConfig       `yaml:"skc" mapstructure:"skc"`
 }
 
 type KBSConfig struct {
@@ -70,6 +83,20 @@ type KmipConfig struct {
 	RootCertificateFilePath   string `yaml:"root-cert-path" mapstructure:"root-cert-path"`
 }
 
+// DirectoryConfig holds the locations used by the directory key manager, which stores the key material on the local
+// file system encrypted with a master key
+type DirectoryConfig struct {
+	KeysDir       string `yaml:"keys-dir" mapstructure:"keys-dir"`
+	MasterKeyFile string `yaml:"master-key-file" mapstructure:"master-key-file"`
+}
+
+// Pkcs11Config holds the PKCS#11 module and the token used by the PKCS#11 key manager
+type Pkcs11Config struct {
+	ModulePath string `yaml:"module-path" mapstructure:"module-path"`
+	TokenLabel string `yaml:"token-label" mapstructure:"token-label"`
+	Pin        string `yaml:"pin" mapstructure:"pin"`
+}
+
 type SKCConfig struct {
 	StmLabel          string `yaml:"challenge-type" mapstructure:"challenge-type"`
 	SQVSUrl           string `yaml:"sqvs-url" mapstructure:"sqvs-url"`
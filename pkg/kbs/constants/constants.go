@@ -23,6 +23,7 @@ const (
 
 	KeysDir               = HomeDir + "keys/"
 	KeysTransferPolicyDir = HomeDir + "keys-transfer-policy/"
+	KeyTransferEventsDir  = HomeDir + "key-transfer-events/"
 
 	// certificates' path
 	TrustedJWTSigningCertsDir = ConfigDir + "certs/trustedjwt/"
@@ -41,6 +42,10 @@ const (
 	DefaultTLSCertPath = ConfigDir + "tls-cert.pem"
 	DefaultTLSKeyPath  = ConfigDir + "tls-key.pem"
 
+	// default locations for the key material and the master key of the directory key manager
+	DefaultDirectoryKeysDir       = ConfigDir + "key-store/"
+	DefaultDirectoryMasterKeyFile = ConfigDir + "master-key"
+
 	// service remove command
 	ServiceRemoveCmd = "systemctl disable kbs"
 
@@ -52,6 +57,8 @@ const (
 
 	// jwt constants
 	JWTCertsCacheTime = "1m"
+	// TokenRevocationsRefreshTime is how often the token revocation list is retrieved from AAS
+	TokenRevocationsRefreshTime = "1m"
 
 	// log constants
 	DefaultLogLevel     = "info"
@@ -69,7 +76,18 @@ const (
 	DefaultSessionExpiryTime = 60 //in minutes
 
 	// keymanager constants
-	KmipKeyManager = "kmip"
+	KmipKeyManager      = "kmip"
+	DirectoryKeyManager = "directory"
+	Pkcs11KeyManager    = "pkcs11"
+
+	// metadata store constants
+	DirectoryMetadataStore = "directory"
+	PostgresMetadataStore  = "postgres"
+	DefaultMetadataStore   = DirectoryMetadataStore
+
+	// certificate types of the certificate stores
+	SamlCertType        = "saml"
+	TpmIdentityCertType = "tpm-identity"
 
 	// algorithm constants
 	CRYPTOALG_AES = "AES"
@@ -97,6 +115,29 @@ const (
 	TransferRoleType        = "KeyTransfer"
 	ContextPermissionsRegex = "^(permissions=)(.*)$"
 	TCBLevelOutOfDate       = "OutOfDate"
+	SamlTransferType        = "saml"
+	SKCTransferType         = "skc"
+	TransferGranted         = "granted"
+	TransferDenied          = "denied"
+	TransferChallenged      = "challenged"
+	TransferFailed          = "failed"
 	DefaultTLSCertFile      = "tls-cert.pem"
 	DefaultTLSKeyFile       = "tls-key.pem"
 )
+
+// db constants
+const (
+	DBTypePostgres = "postgres"
+
+	DefaultDbName              = "kbs_db"
+	DefaultDbSSLCertFile       = ConfigDir + "kbsdbsslcert.pem"
+	DefaultDbConnRetryAttempts = 4
+	DefaultDbConnRetryTime     = 1
+
+	//Postgres connection SslModes
+	SslModeAllow      = "allow"
+	SslModePrefer     = "prefer"
+	SslModeVerifyCa   = "verify-ca"
+	SslModeRequire    = "require"
+	SslModeVerifyFull = "verify-full"
+)
@@ -14,6 +14,7 @@ const (
 	KeySearch   = "keys:search"
 	KeyRegister = "keys:register"
 	KeyTransfer = "keys:transfer"
+	KeyRotate   = "keys:rotate"
 
 	SamlCertCreate   = "saml_certificates:create"
 	SamlCertRetrieve = "saml_certificates:retrieve"
@@ -30,4 +31,6 @@ const (
 	KeyTransferPolicyUpdate   = "key_transfer_policies:update"
 	KeyTransferPolicyDelete   = "key_transfer_policies:delete"
 	KeyTransferPolicySearch   = "key_transfer_policies:search"
+
+	KeyTransferEventSearch = "key_transfer_events:search"
 )
@@ -14,6 +14,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -52,6 +53,8 @@ func NewKeyController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyS
 
 var keySearchParams = map[string]bool{"algorithm": true, "keyLength": true, "curveType": true, "transferPolicyId": true}
 var allowedAlgorithms = map[string]bool{"AES": true, "RSA": true, "EC": true, "aes": true, "rsa": true, "ec": true}
+var trustAttributeRegex = regexp.MustCompile(`^TRUST_[A-Z_]+$`)
+
 var allowedCurveTypes = map[string]bool{"secp256r1": true, "secp384r1": true, "secp521r1": true, "prime256v1": true}
 var allowedKeyLengths = map[int]bool{128: true, 192: true, 256: true, 2048: true, 3072: true, 4096: true, 7680: true}
 
@@ -189,6 +192,27 @@ func (kc *KeyController) Delete(responseWriter http.ResponseWriter, request *htt
 	return nil, http.StatusNoContent, nil
 }
 
+//Rotate : Function to create a new version of the key material of a key
+func (kc *KeyController) Rotate(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_controller:Rotate() Entering")
+	defer defaultLog.Trace("controllers/key_controller:Rotate() Leaving")
+
+	id := uuid.MustParse(mux.Vars(request)["id"])
+	rotatedKey, err := kc.remoteManager.RotateKey(id)
+	if err != nil {
+		if err.Error() == commErr.RecordNotFound {
+			defaultLog.Error("controllers/key_controller:Rotate() Key with specified id could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+		} else {
+			defaultLog.WithError(err).Error("controllers/key_controller:Rotate() Key rotate failed")
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to rotate key"}
+		}
+	}
+
+	secLog.WithField("Id", id).Infof("controllers/key_controller:Rotate() %s: Key rotated to version %d by: %s", commLogMsg.PrivilegeModified, rotatedKey.Version, request.RemoteAddr)
+	return rotatedKey, http.StatusOK, nil
+}
+
 //Search : Function to search keys
 func (kc *KeyController) Search(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
 	defaultLog.Trace("controllers/key_controller:Search() Entering")
@@ -246,9 +270,15 @@ func (kc *KeyController) Transfer(responseWriter http.ResponseWriter, request *h
 	}
 	envelopeKey := key.(*rsa.PublicKey)
 
+	version, err := getKeyVersion(request.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_controller:Transfer() %s : Invalid key version", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
 	// Wrap key with public key
 	id := uuid.MustParse(mux.Vars(request)["id"])
-	secretKey, status, err := getSecretKey(kc.remoteManager, id)
+	secretKey, status, err := getSecretKey(kc.remoteManager, id, version)
 	if err != nil {
 		return nil, status, err
 	}
@@ -267,15 +297,27 @@ func (kc *KeyController) Transfer(responseWriter http.ResponseWriter, request *h
 	return transferKeyResponse, http.StatusOK, nil
 }
 
-func getSecretKey(remoteManager *keymanager.RemoteManager, id uuid.UUID) (interface{}, int, error) {
+// getKeyVersion returns the key version requested with the version query parameter, or 0 for the current version
+func getKeyVersion(params url.Values) (int, error) {
+	if params.Get("version") == "" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(params.Get("version"))
+	if err != nil || version < 1 {
+		return 0, errors.New("version must be a positive integer")
+	}
+	return version, nil
+}
+
+func getSecretKey(remoteManager *keymanager.RemoteManager, id uuid.UUID, version int) (interface{}, int, error) {
 	defaultLog.Trace("controllers/key_controller:getSecretKey() Entering")
 	defer defaultLog.Trace("controllers/key_controller:getSecretKey() Leaving")
 
-	secretKey, err := remoteManager.TransferKey(id)
+	secretKey, err := remoteManager.TransferKeyVersion(id, version)
 	if err != nil {
 		if err.Error() == commErr.RecordNotFound {
-			defaultLog.Error("controllers/key_controller:getSecretKey() Key with specified id could not be located")
-			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
+			defaultLog.Error("controllers/key_controller:getSecretKey() Key with specified id or version could not be located")
+			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id or version does not exist"}
 		} else {
 			defaultLog.WithError(err).Error("controllers/key_controller:getSecretKey() Key transfer failed")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to transfer Key"}
@@ -347,6 +389,51 @@ func validateKeyCreateRequest(requestKey kbs.KeyRequest) error {
 			return errors.New("valid contents for usage must be specified")
 		}
 	}
+
+	if requestKey.UsagePolicy != nil {
+		if err := validateUsagePolicy(requestKey.UsagePolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateUsagePolicy checks the clauses of the usage policy in the Key Create request
+func validateUsagePolicy(policy *kbs.KeyUsagePolicy) error {
+	defaultLog.Trace("controllers/key_controller:validateUsagePolicy() Entering")
+	defer defaultLog.Trace("controllers/key_controller:validateUsagePolicy() Leaving")
+
+	if policy.AssetTags != nil {
+		for _, tags := range [][]kbs.AssetTag{policy.AssetTags.AllOf, policy.AssetTags.AnyOf} {
+			for _, tag := range tags {
+				if tag.Key == "" || validation.ValidateTextString(tag.Key) != nil || validation.ValidateTextString(tag.Value) != nil {
+					return errors.New("valid key and value must be specified for asset tags of usage_policy")
+				}
+			}
+		}
+	}
+
+	for _, trust := range policy.RequiredTrust {
+		if !trustAttributeRegex.MatchString(trust) {
+			return errors.New("required_trust of usage_policy must be SAML trust attributes such as TRUST_PLATFORM")
+		}
+	}
+
+	if policy.MaxSamlAgeSeconds < 0 {
+		return errors.New("max_saml_age_seconds of usage_policy must not be negative")
+	}
+
+	for _, issuer := range policy.AllowedAikIssuers {
+		if issuer == "" || validation.ValidateTextString(issuer) != nil {
+			return errors.New("valid common names must be specified for allowed_aik_issuers of usage_policy")
+		}
+	}
+
+	for _, feature := range policy.RequiredHardwareFeatures {
+		if validation.ValidateNameString(feature) != nil {
+			return errors.New("valid names must be specified for required_hardware_features of usage_policy")
+		}
+	}
 	return nil
 }
 
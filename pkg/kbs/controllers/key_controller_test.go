@@ -5,9 +5,13 @@
 package controllers_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha512"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"io/ioutil"
@@ -47,6 +51,7 @@ var _ = Describe("KeyController", func() {
 	var w *httptest.ResponseRecorder
 	var keyStore *mocks.MockKeyStore
 	var policyStore *mocks.MockKeyTransferPolicyStore
+	var eventStore *mocks.MockKeyTransferEventStore
 	var remoteManager *keymanager.RemoteManager
 	var keyController *controllers.KeyController
 	var keyTransferController *controllers.KeyTransferController
@@ -64,26 +69,33 @@ var _ = Describe("KeyController", func() {
 	validSamlReport, _ := ioutil.ReadFile(validSamlReportPath)
 	invalidSamlReport, _ := ioutil.ReadFile(invalidSamlReportPath)
 
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecKeyBytes, _ := x509.MarshalECPrivateKey(ecKey)
+
 	mockClient := kmipclient.NewMockKmipClient()
 	mockClient.On("CreateSymmetricKey", mock.Anything, mock.Anything).Return("1", nil)
+	mockClient.On("CreateAsymmetricKeyPair", mock.Anything).Return("2", nil)
 	mockClient.On("DeleteKey", mock.Anything).Return(nil)
+	mockClient.On("GetKey", "2").Return(ecKeyBytes, nil)
 	mockClient.On("GetKey", mock.Anything).Return([]byte(""), nil)
 	keyManager := keymanager.NewKmipManager(mockClient)
 
 	newId, _ := uuid.NewRandom()
+	certStore := mocks.NewFakeCertificateStore()
 	kcc := domain.KeyTransferControllerConfig{
-		SamlCertsDir:        samlCertsDir,
-		TrustedCaCertsDir:   trustedCaCertsDir,
-		TpmIdentityCertsDir: tpmIdentityCertsDir,
+		SamlCertStore:        certStore,
+		TrustedCaCertsDir:    trustedCaCertsDir,
+		TpmIdentityCertStore: certStore,
 	}
 
 	BeforeEach(func() {
 		router = mux.NewRouter()
 		keyStore = mocks.NewFakeKeyStore()
 		policyStore = mocks.NewFakeKeyTransferPolicyStore()
+		eventStore = mocks.NewFakeKeyTransferEventStore()
 		remoteManager = keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
 		keyController = controllers.NewKeyController(remoteManager, policyStore, newId)
-		keyTransferController = controllers.NewKeyTransferController(remoteManager, policyStore, kcc)
+		keyTransferController = controllers.NewKeyTransferController(remoteManager, policyStore, eventStore, kcc)
 	})
 
 	// Specs for HTTP Post to "/keys"
@@ -118,6 +130,54 @@ var _ = Describe("KeyController", func() {
 				Expect(w.Code).To(Equal(http.StatusCreated))
 			})
 		})
+		Context("Provide a valid Create request for an EC key", func() {
+			It("Should create a new EC Key", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods(http.MethodPost)
+				keyJson := `{
+								 "key_information": {
+									 "algorithm": "EC",
+									 "curve_type": "secp384r1"
+								 }
+							 }`
+
+				req, err := http.NewRequest(
+					http.MethodPost,
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+
+				permissions := aas.PermissionInfo{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.KeyCreate},
+				}
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{permissions})
+
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusCreated))
+
+				var keyResponse kbs.KeyResponse
+				_ = json.Unmarshal(w.Body.Bytes(), &keyResponse)
+				Expect(keyResponse.KeyInformation.CurveType).To(Equal("secp384r1"))
+
+				// Search the new key by curve type
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Search))).Methods(http.MethodGet)
+				req, err = http.NewRequest(http.MethodGet, "/keys?curveType=secp384r1", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var keyResponses []kbs.KeyResponse
+				_ = json.Unmarshal(w.Body.Bytes(), &keyResponses)
+				Expect(len(keyResponses)).To(Equal(1))
+				Expect(keyResponses[0].KeyInformation.ID).To(Equal(keyResponse.KeyInformation.ID))
+			})
+		})
 		Context("Provide a Create request that contains non-existent key-transfer-policy", func() {
 			It("Should fail to create a new Key with bad request error", func() {
 				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods(http.MethodPost)
@@ -317,6 +377,40 @@ var _ = Describe("KeyController", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+		Context("Provide a Create request that contains invalid usage policy", func() {
+			It("Should fail to create a new Key with bad request error", func() {
+				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods(http.MethodPost)
+				keyJson := `{
+								 "key_information": {
+									 "algorithm": "AES",
+									 "key_length": 256
+								 },
+								 "usage_policy": {
+									 "required_trust": ["OVERALL"],
+									 "max_saml_age_seconds": -1
+								 }
+							 }`
+
+				req, err := http.NewRequest(
+					http.MethodPost,
+					"/keys",
+					strings.NewReader(keyJson),
+				)
+
+				permissions := aas.PermissionInfo{
+					Service: constants.ServiceName,
+					Rules:   []string{constants.KeyCreate},
+				}
+				req = context.SetUserPermissions(req, []aas.PermissionInfo{permissions})
+
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
 		Context("Provide a Create request with no content", func() {
 			It("Should fail to create a new Key with bad request error", func() {
 				router.Handle("/keys", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Create))).Methods(http.MethodPost)
@@ -531,6 +625,34 @@ var _ = Describe("KeyController", func() {
 				Expect(w.Code).To(Equal(http.StatusOK))
 			})
 		})
+		Context("Provide a valid public key", func() {
+			It("Should transfer an existing EC Key", func() {
+				router.Handle("/keys/{id}/transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Transfer))).Methods(http.MethodPost)
+				envelopeKey := string(validEnvelopeKey)
+
+				req, err := http.NewRequest(
+					http.MethodPost,
+					"/keys/e57e5ea0-d465-461e-882d-1600090caa0d/transfer",
+					strings.NewReader(envelopeKey),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				req.Header.Set("Content-Type", consts.HTTPMediaTypePlain)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var transferResponse kbs.KeyTransferResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &transferResponse)).To(Succeed())
+				wrappedKey, err := base64.StdEncoding.DecodeString(transferResponse.WrappedKey)
+				Expect(err).NotTo(HaveOccurred())
+				keyBytes, err := rsa.DecryptOAEP(sha512.New384(), rand.Reader, keyPair, wrappedKey, nil)
+				Expect(err).NotTo(HaveOccurred())
+				transferredKey, err := x509.ParseECPrivateKey(keyBytes)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(transferredKey.Equal(ecKey)).To(BeTrue())
+			})
+		})
 		Context("Provide a public key without PUBLIC KEY headers", func() {
 			It("Should fail to transfer Key with bad request error", func() {
 				router.Handle("/keys/{id}/transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Transfer))).Methods(http.MethodPost)
@@ -641,6 +763,11 @@ var _ = Describe("KeyController", func() {
 				w = httptest.NewRecorder()
 				router.ServeHTTP(w, req)
 				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+
+				events, _ := eventStore.Search(nil)
+				Expect(len(events)).To(Equal(4))
+				Expect(events[3].KeyID.String()).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+				Expect(events[3].Decision).To(Equal("denied"))
 			})
 		})
 		Context("Provide an invalid saml report", func() {
@@ -749,6 +876,52 @@ var _ = Describe("KeyController", func() {
 		})
 	})
 
+	// Specs for HTTP Post to "/keys/{id}/rotate"
+	Describe("Rotate an existing Key", func() {
+		Context("Rotate Key by ID", func() {
+			It("Should create a new version of the Key and transfer the requested version", func() {
+				router.Handle("/keys/{id}/rotate", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Rotate))).Methods(http.MethodPost)
+				router.Handle("/keys/{id}/transfer", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Transfer))).Methods(http.MethodPost)
+				req, err := http.NewRequest(http.MethodPost, "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/rotate", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var keyResponse kbs.KeyResponse
+				Expect(json.Unmarshal(w.Body.Bytes(), &keyResponse)).NotTo(HaveOccurred())
+				Expect(keyResponse.Version).To(Equal(2))
+				Expect(keyResponse.KeyInformation.ID.String()).To(Equal("ee37c360-7eae-4250-a677-6ee12adce8e2"))
+
+				for version, status := range map[string]int{"1": http.StatusOK, "2": http.StatusOK, "3": http.StatusNotFound, "latest": http.StatusBadRequest} {
+					req, err = http.NewRequest(
+						http.MethodPost,
+						"/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/transfer?version="+version,
+						strings.NewReader(string(validEnvelopeKey)),
+					)
+					Expect(err).NotTo(HaveOccurred())
+					req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+					req.Header.Set("Content-Type", consts.HTTPMediaTypePlain)
+					w = httptest.NewRecorder()
+					router.ServeHTTP(w, req)
+					Expect(w.Code).To(Equal(status))
+				}
+			})
+		})
+		Context("Rotate Key by non-existent ID", func() {
+			It("Should fail to rotate Key with not found error", func() {
+				router.Handle("/keys/{id}/rotate", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyController.Rotate))).Methods(http.MethodPost)
+				req, err := http.NewRequest(http.MethodPost, "/keys/73755fda-c910-46be-821f-e8ddeab189e9/rotate", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	// Specs for HTTP Get to "/keys"
 	Describe("Search for all the Keys", func() {
 		Context("Get all the Keys", func() {
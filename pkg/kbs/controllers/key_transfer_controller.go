@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/keytransfer"
@@ -20,18 +21,22 @@ import (
 	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
 	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/saml"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
 )
 
 type KeyTransferController struct {
 	remoteManager *keymanager.RemoteManager
 	policyStore   domain.KeyTransferPolicyStore
+	eventStore    domain.KeyTransferEventStore
 	keyConfig     domain.KeyTransferControllerConfig
 }
 
-func NewKeyTransferController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyStore, kc domain.KeyTransferControllerConfig) *KeyTransferController {
+func NewKeyTransferController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyStore, es domain.KeyTransferEventStore, kc domain.KeyTransferControllerConfig) *KeyTransferController {
 	return &KeyTransferController{
 		remoteManager: rm,
 		policyStore:   ps,
+		eventStore:    es,
 		keyConfig:     kc,
 	}
 }
@@ -64,26 +69,72 @@ func (kc *KeyTransferController) TransferWithSaml(responseWriter http.ResponseWr
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Failed to unmarshal SAML report"}
 	}
 
+	version, err := getKeyVersion(request.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_transfer_controller:TransferWithSaml() %s : Invalid key version", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
 	// Validate saml report in request
 	keyId := uuid.MustParse(mux.Vars(request)["id"])
-	trusted, bindingCert := keytransfer.IsTrustedByHvs(string(bytes), samlReport, keyId, kc.keyConfig, kc.remoteManager)
+	event := &kbs.KeyTransferEvent{
+		KeyID:        keyId,
+		Requester:    getSamlHardwareUUID(samlReport),
+		TransferType: consts.SamlTransferType,
+	}
+	trusted, bindingCert, err := keytransfer.IsTrustedByHvs(string(bytes), samlReport, keyId, kc.keyConfig, kc.remoteManager)
 	if !trusted {
-		secLog.Error("controllers/key_transfer_controller:TransferWithSaml() Client not trusted by HVS")
+		event.Decision = consts.TransferDenied
+		if err != nil {
+			event.Reason = err.Error()
+		}
+		var denial *keytransfer.UsagePolicyDenial
+		if errors.As(err, &denial) {
+			event.FailedClause = denial.Clause
+			event.Reason = denial.Reason
+			_ = recordKeyTransferEvent(kc.eventStore, event)
+			secLog.WithError(err).Errorf("controllers/key_transfer_controller:TransferWithSaml() %s : Key usage policy denied the transfer to %s", commLogMsg.UnauthorizedAccess, request.RemoteAddr)
+			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Key usage policy not satisfied: " + denial.Clause}
+		}
+		_ = recordKeyTransferEvent(kc.eventStore, event)
+		secLog.WithError(err).Error("controllers/key_transfer_controller:TransferWithSaml() Client not trusted by HVS")
 		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Client not trusted by HVS"}
 	}
 	envelopeKey := bindingCert.PublicKey.(*rsa.PublicKey)
 
-	secretKey, status, err := getSecretKey(kc.remoteManager, keyId)
+	secretKey, status, err := getSecretKey(kc.remoteManager, keyId, version)
 	if err != nil {
+		event.Decision = consts.TransferFailed
+		event.Reason = "key could not be retrieved"
+		_ = recordKeyTransferEvent(kc.eventStore, event)
 		return nil, status, err
 	}
 
 	// Wrap secret key with binding key
 	wrappedKey, status, err := wrapKey(envelopeKey, secretKey.([]byte), sha256.New(), []byte("TPM2\000"))
 	if err != nil {
+		event.Decision = consts.TransferFailed
+		event.Reason = "key could not be wrapped"
+		_ = recordKeyTransferEvent(kc.eventStore, event)
 		return nil, status, err
 	}
 
+	// The key is not handed out unless the transfer is recorded
+	event.Decision = consts.TransferGranted
+	if err = recordKeyTransferEvent(kc.eventStore, event); err != nil {
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to record key transfer"}
+	}
+
 	secLog.WithField("Id", keyId).Infof("controllers/key_transfer_controller:TransferWithSaml() %s: Key transferred using SAML report by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
 	return wrappedKey, http.StatusOK, nil
 }
+
+// getSamlHardwareUUID returns the hardware UUID of the host of the SAML report
+func getSamlHardwareUUID(samlReport *saml.Saml) string {
+	for _, attribute := range samlReport.Attribute {
+		if attribute.Name == "HardwareUUID" {
+			return attribute.AttributeValue
+		}
+	}
+	return ""
+}
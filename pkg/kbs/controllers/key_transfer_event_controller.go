@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/utils"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+type KeyTransferEventController struct {
+	eventStore domain.KeyTransferEventStore
+}
+
+func NewKeyTransferEventController(es domain.KeyTransferEventStore) *KeyTransferEventController {
+	return &KeyTransferEventController{
+		eventStore: es,
+	}
+}
+
+var keyTransferEventSearchParams = map[string]bool{"keyId": true, "requester": true, "decision": true, "fromDate": true, "toDate": true}
+var keyTransferHistoryParams = map[string]bool{"requester": true, "decision": true, "fromDate": true, "toDate": true}
+var allowedTransferDecisions = map[string]bool{constants.TransferGranted: true, constants.TransferDenied: true, constants.TransferChallenged: true, constants.TransferFailed: true}
+
+// Search : Function to search key transfer events
+func (tec *KeyTransferEventController) Search(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_transfer_event_controller:Search() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_event_controller:Search() Leaving")
+
+	// check for query parameters
+	if err := utils.ValidateQueryParams(request.URL.Query(), keyTransferEventSearchParams); err != nil {
+		secLog.Errorf("controllers/key_transfer_event_controller:Search() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	criteria, err := getKeyTransferEventFilterCriteria(request.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_transfer_event_controller:Search() %s Invalid filter criteria", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid filter criteria"}
+	}
+
+	events, err := tec.eventStore.Search(criteria)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_transfer_event_controller:Search() Key transfer event search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search key transfer events"}
+	}
+
+	secLog.Infof("controllers/key_transfer_event_controller:Search() %s: Key transfer events searched by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	return events, http.StatusOK, nil
+}
+
+// SearchByKey : Function to retrieve the transfer history of a key. The history is kept after the key is deleted.
+func (tec *KeyTransferEventController) SearchByKey(responseWriter http.ResponseWriter, request *http.Request) (interface{}, int, error) {
+	defaultLog.Trace("controllers/key_transfer_event_controller:SearchByKey() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_event_controller:SearchByKey() Leaving")
+
+	// check for query parameters
+	if err := utils.ValidateQueryParams(request.URL.Query(), keyTransferHistoryParams); err != nil {
+		secLog.Errorf("controllers/key_transfer_event_controller:SearchByKey() %s", err.Error())
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: err.Error()}
+	}
+
+	criteria, err := getKeyTransferEventFilterCriteria(request.URL.Query())
+	if err != nil {
+		secLog.WithError(err).Errorf("controllers/key_transfer_event_controller:SearchByKey() %s Invalid filter criteria", commLogMsg.InvalidInputBadParam)
+		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Invalid filter criteria"}
+	}
+	criteria.KeyId = uuid.MustParse(mux.Vars(request)["id"])
+
+	events, err := tec.eventStore.Search(criteria)
+	if err != nil {
+		defaultLog.WithError(err).Error("controllers/key_transfer_event_controller:SearchByKey() Key transfer event search failed")
+		return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to search key transfer events"}
+	}
+
+	secLog.WithField("Id", criteria.KeyId).Infof("controllers/key_transfer_event_controller:SearchByKey() %s: Key transfer history retrieved by: %s", commLogMsg.AuthorizedAccess, request.RemoteAddr)
+	return events, http.StatusOK, nil
+}
+
+// recordKeyTransferEvent persists the decision taken on a key transfer request
+func recordKeyTransferEvent(eventStore domain.KeyTransferEventStore, event *kbs.KeyTransferEvent) error {
+	defaultLog.Trace("controllers/key_transfer_event_controller:recordKeyTransferEvent() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_event_controller:recordKeyTransferEvent() Leaving")
+
+	event.CreatedAt = time.Now().UTC()
+	if _, err := eventStore.Create(event); err != nil {
+		defaultLog.WithError(err).WithField("Id", event.KeyID).Errorf("controllers/key_transfer_event_controller:recordKeyTransferEvent() Failed to record %s key transfer to %s", event.Decision, event.Requester)
+		return err
+	}
+	return nil
+}
+
+func getKeyTransferEventFilterCriteria(params url.Values) (*models.KeyTransferEventFilterCriteria, error) {
+	defaultLog.Trace("controllers/key_transfer_event_controller:getKeyTransferEventFilterCriteria() Entering")
+	defer defaultLog.Trace("controllers/key_transfer_event_controller:getKeyTransferEventFilterCriteria() Leaving")
+
+	criteria := models.KeyTransferEventFilterCriteria{}
+
+	// keyId
+	if param := strings.TrimSpace(params.Get("keyId")); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid keyId query param value, must be UUID")
+		}
+		criteria.KeyId = id
+	}
+
+	// requester
+	if param := strings.TrimSpace(params.Get("requester")); param != "" {
+		if err := validation.ValidateTextString(param); err != nil {
+			return nil, errors.Wrap(err, "Valid contents for requester must be specified")
+		}
+		criteria.Requester = param
+	}
+
+	// decision
+	if param := strings.TrimSpace(params.Get("decision")); param != "" {
+		if !allowedTransferDecisions[param] {
+			return nil, errors.New("Valid decision must be specified")
+		}
+		criteria.Decision = param
+	}
+
+	// fromDate
+	if param := strings.TrimSpace(params.Get("fromDate")); param != "" {
+		fromDate, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid fromDate query param value, must be RFC3339 timestamp")
+		}
+		criteria.FromDate = fromDate
+	}
+
+	// toDate
+	if param := strings.TrimSpace(params.Get("toDate")); param != "" {
+		toDate, err := time.Parse(time.RFC3339, param)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid toDate query param value, must be RFC3339 timestamp")
+		}
+		criteria.ToDate = toDate
+	}
+
+	if !criteria.FromDate.IsZero() && !criteria.ToDate.IsZero() && criteria.FromDate.After(criteria.ToDate) {
+		return nil, errors.New("fromDate must not be after toDate")
+	}
+	return &criteria, nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/mocks"
+	kbsRoutes "github.com/intel-secl/intel-secl/v5/pkg/kbs/router"
+	consts "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KeyTransferEventController", func() {
+	var router *mux.Router
+	var w *httptest.ResponseRecorder
+	var eventStore *mocks.MockKeyTransferEventStore
+	var keyTransferEventController *controllers.KeyTransferEventController
+	BeforeEach(func() {
+		router = mux.NewRouter()
+		eventStore = mocks.NewFakeKeyTransferEventStore()
+
+		keyTransferEventController = controllers.NewKeyTransferEventController(eventStore)
+	})
+
+	// Specs for HTTP Get to "/transfer-events"
+	Describe("Search for Key Transfer Events", func() {
+		Context("Get all the Key Transfer Events", func() {
+			It("Should get list of all the Key Transfer Events", func() {
+				router.Handle("/transfer-events", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferEventController.Search))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/transfer-events", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var events []kbs.KeyTransferEvent
+				_ = json.Unmarshal(w.Body.Bytes(), &events)
+				// Verifying mocked data of 3 key transfer events
+				Expect(len(events)).To(Equal(3))
+			})
+		})
+		Context("Get the denied Key Transfer Events", func() {
+			It("Should get list of denied Key Transfer Events", func() {
+				router.Handle("/transfer-events", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferEventController.Search))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/transfer-events?decision=denied", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var events []kbs.KeyTransferEvent
+				_ = json.Unmarshal(w.Body.Bytes(), &events)
+				Expect(len(events)).To(Equal(1))
+				Expect(events[0].FailedClause).To(Equal("required_trust[TRUST_OS]"))
+			})
+		})
+		Context("Get the Key Transfer Events with invalid decision", func() {
+			It("Should fail to get Key Transfer Events with bad request error", func() {
+				router.Handle("/transfer-events", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferEventController.Search))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/transfer-events?decision=allowed", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Get the Key Transfer Events with fromDate after toDate", func() {
+			It("Should fail to get Key Transfer Events with bad request error", func() {
+				router.Handle("/transfer-events", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferEventController.Search))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/transfer-events?fromDate=2022-02-01T00:00:00Z&toDate=2022-01-01T00:00:00Z", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+		Context("Get the Key Transfer Events with invalid query parameter", func() {
+			It("Should fail to get Key Transfer Events with bad request error", func() {
+				router.Handle("/transfer-events", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferEventController.Search))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/transfer-events?badParam=true", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	// Specs for HTTP Get to "/keys/{id}/transfers"
+	Describe("Retrieve the transfer history of a Key", func() {
+		Context("Get the Key Transfer Events of a Key", func() {
+			It("Should get list of the Key Transfer Events of the Key", func() {
+				router.Handle("/keys/{id}/transfers", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferEventController.SearchByKey))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/transfers", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var events []kbs.KeyTransferEvent
+				_ = json.Unmarshal(w.Body.Bytes(), &events)
+				Expect(len(events)).To(Equal(2))
+				Expect(events[0].Decision).To(Equal("granted"))
+				Expect(events[1].Decision).To(Equal("denied"))
+			})
+		})
+		Context("Get the Key Transfer Events of a Key with keyId query parameter", func() {
+			It("Should fail to get Key Transfer Events with bad request error", func() {
+				router.Handle("/keys/{id}/transfers", kbsRoutes.ErrorHandler(kbsRoutes.JsonResponseHandler(keyTransferEventController.SearchByKey))).Methods(http.MethodGet)
+				req, err := http.NewRequest(http.MethodGet, "/keys/ee37c360-7eae-4250-a677-6ee12adce8e2/transfers?keyId=87d59b82-33b7-47e7-8fcb-6f7f12c82719", nil)
+				Expect(err).NotTo(HaveOccurred())
+				w = httptest.NewRecorder()
+				req.Header.Set("Accept", consts.HTTPMediaTypeJson)
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
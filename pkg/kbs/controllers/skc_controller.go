@@ -28,14 +28,16 @@ import (
 type SKCController struct {
 	remoteManager    *keymanager.RemoteManager
 	policyStore      domain.KeyTransferPolicyStore
+	eventStore       domain.KeyTransferEventStore
 	config           *config.Configuration
 	trustedCaCertDir string
 }
 
-func NewSKCController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyStore, kc *config.Configuration, caCertDir string) *SKCController {
+func NewSKCController(rm *keymanager.RemoteManager, ps domain.KeyTransferPolicyStore, es domain.KeyTransferEventStore, kc *config.Configuration, caCertDir string) *SKCController {
 	return &SKCController{
 		remoteManager:    rm,
 		policyStore:      ps,
+		eventStore:       es,
 		config:           kc,
 		trustedCaCertDir: caCertDir,
 	}
@@ -68,15 +70,26 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 
 	keyInfo.IssuerCommonName = request.TLS.PeerCertificates[0].Issuer.CommonName
 	userCommonName := request.TLS.PeerCertificates[0].Subject.CommonName
+	event := &kbs.KeyTransferEvent{
+		KeyID:        keyID,
+		Requester:    userCommonName,
+		TransferType: constants.SKCTransferType,
+	}
 
 	err = keyInfo.SetUserContext(userCommonName, kc.config, kc.trustedCaCertDir)
 	if err != nil {
+		event.Decision = constants.TransferDenied
+		event.Reason = "user context could not be set"
+		_ = recordKeyTransferEvent(kc.eventStore, event)
 		secLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() error while getting common name")
 		return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Couldn't fetch common name for specified user"}
 	}
 
 	key, err := kc.remoteManager.RetrieveKey(keyID)
 	if err != nil {
+		event.Decision = constants.TransferFailed
+		event.Reason = "key could not be retrieved"
+		_ = recordKeyTransferEvent(kc.eventStore, event)
 		if err.Error() == commErr.RecordNotFound {
 			defaultLog.Error("controllers/skc_controller:TransferApplicationKey() Key with specified id could not be located")
 			return nil, http.StatusNotFound, &commErr.ResourceError{Message: "Key with specified id does not exist"}
@@ -87,6 +100,9 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 	}
 	transferPolicy, err := kc.policyStore.Retrieve(key.TransferPolicyID)
 	if err != nil {
+		event.Decision = constants.TransferFailed
+		event.Reason = "key transfer policy could not be retrieved"
+		_ = recordKeyTransferEvent(kc.eventStore, event)
 		if err.Error() == commErr.RecordNotFound {
 			defaultLog.Error("controllers/skc_controller:TransferApplicationKey() specified transfer policy id could not be located")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "specified transfer policy id does not exist"}
@@ -99,6 +115,9 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 
 	isValidClient := keyInfo.IsValidClient()
 	if !isValidClient {
+		event.Decision = constants.TransferDenied
+		event.Reason = "client is not valid"
+		_ = recordKeyTransferEvent(kc.eventStore, event)
 		secLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() client is not valid")
 		return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "client is not valid"}
 	}
@@ -106,6 +125,9 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 	if len(sessionId) == 0 {
 		challenge, err := keyInfo.BuildChallengeJsonRequest(kc.config)
 		if err != nil {
+			event.Decision = constants.TransferFailed
+			event.Reason = "challenge could not be built"
+			_ = recordKeyTransferEvent(kc.eventStore, event)
 			secLog.WithError(err).Errorf("controllers/skc_controller:TransferApplicationKey() Failed to generate challenge")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in building the challenge request"}
 		} else if !(reflect.DeepEqual(challenge, kbs.ChallengeRequest{})) {
@@ -115,6 +137,9 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 			challenge.Operation = constants.KeyTransferOpertaion
 			challenge.Status = constants.FailureStatus
 
+			event.Decision = constants.TransferChallenged
+			event.Reason = "no session is established"
+			_ = recordKeyTransferEvent(kc.eventStore, event)
 			secLog.Info("controllers/skc_controller:TransferApplicationKey() Unauthorized: Generated Challenge")
 			return challenge, http.StatusUnauthorized, nil
 		}
@@ -124,6 +149,9 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 	isValidSession, isValidSGXAttributes, isSessionActive := keyInfo.IsValidSession(stmChallenge)
 	if isValidSession {
 		if !isSessionActive {
+			event.Decision = constants.TransferDenied
+			event.Reason = "session is expired"
+			_ = recordKeyTransferEvent(kc.eventStore, event)
 			secLog.Info("controllers/skc_controller:TransferApplicationKey() SessionExpired: Session is expired.Hence key transfer unsuccessful.")
 			return nil, http.StatusUnauthorized, &commErr.ResourceError{Message: "Session is expired. Create new."}
 		}
@@ -136,6 +164,9 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 			challenge.Operation = constants.KeyTransferOpertaion
 			challenge.Status = constants.FailureStatus
 
+			event.Decision = constants.TransferDenied
+			event.Reason = "sgx attributes verification failed"
+			_ = recordKeyTransferEvent(kc.eventStore, event)
 			secLog.Info("controllers/skc_controller:TransferApplicationKey() NotFound: sgx attributes verification failed")
 			return challenge, http.StatusNotFound, nil
 		}
@@ -143,11 +174,17 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 		defaultLog.Debug("Session is valid. Hence directly transfer the key")
 		keyData, err := kc.remoteManager.TransferKey(keyID)
 		if err != nil {
+			event.Decision = constants.TransferFailed
+			event.Reason = "key could not be retrieved"
+			_ = recordKeyTransferEvent(kc.eventStore, event)
 			defaultLog.WithError(err).Error("controllers/skc_controller:TransferApplicationKey() Key retrieve failed")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to retrieve key"}
 		}
 		applicationKey, err := keyInfo.FetchApplicationKey(keyData, key.KeyInformation.Algorithm)
 		if err != nil {
+			event.Decision = constants.TransferFailed
+			event.Reason = "application key could not be fetched"
+			_ = recordKeyTransferEvent(kc.eventStore, event)
 			secLog.WithError(err).WithField("id", keyID).Error(
 				"controllers/skc_controller:TransferApplicationKey() Failed to fetch the application key")
 			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in fetching the application key"}
@@ -167,15 +204,27 @@ func (kc *SKCController) TransferApplicationKey(responseWriter http.ResponseWrit
 
 		sessionID, err := base64.StdEncoding.DecodeString(keyInfo.ActiveSessionID)
 		if err != nil {
+			event.Decision = constants.TransferFailed
+			event.Reason = "active session id could not be decoded"
+			_ = recordKeyTransferEvent(kc.eventStore, event)
 			secLog.WithError(err).Errorf("controllers/skc_controller:TransferApplicationKey() Failed to decode the active session id")
 			return nil, http.StatusBadRequest, &commErr.ResourceError{Message: "Error in decoding the active session id"}
 		}
 		sessionIDStr := fmt.Sprintf("%s:%s", keyInfo.ActiveStmLabel, sessionID)
+
+		// The key is not handed out unless the transfer is recorded
+		event.Decision = constants.TransferGranted
+		if err = recordKeyTransferEvent(kc.eventStore, event); err != nil {
+			return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Failed to record key transfer"}
+		}
 		responseWriter.Header().Add("Session-Id", sessionIDStr)
 		secLog.WithField("Key", keyID).Infof("controllers/skc_controller:TransferApplicationKey(): Successfully transferred the key: %s", request.RemoteAddr)
 		delete(keyInfo.SessionIDMap, keyInfo.ActiveStmLabel+keyInfo.ActiveSessionID)
 		return outputKeyData, http.StatusOK, nil
 	}
+	event.Decision = constants.TransferDenied
+	event.Reason = "session is not valid"
+	_ = recordKeyTransferEvent(kc.eventStore, event)
 	return nil, http.StatusInternalServerError, &commErr.ResourceError{Message: "Error in transferring the application key"}
 }
 
@@ -63,7 +63,7 @@ var _ = Describe("SKCKeyTransferController", func() {
 		}
 
 		remoteManager = keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
-		skcController = controllers.NewSKCController(remoteManager, policyStore, kbsConfig, trustedCaCertsDir)
+		skcController = controllers.NewSKCController(remoteManager, policyStore, mocks.NewFakeKeyTransferEventStore(), kbsConfig, trustedCaCertsDir)
 		setupServer(server)
 	})
 
@@ -17,6 +17,7 @@ import (
 func init() {
 	viper.SetDefault(config.EndpointUrl, constants.DefaultEndpointUrl)
 	viper.SetDefault(config.KeyManager, constants.DefaultKeyManager)
+	viper.SetDefault(config.MetadataStore, constants.DefaultMetadataStore)
 
 	// Set default values for tls
 	viper.SetDefault(commConfig.TlsCertFile, constants.DefaultTLSCertPath)
@@ -32,6 +33,20 @@ func init() {
 	// Set default value for kmip version
 	viper.SetDefault(config.KmipVersion, constants.KMIP_2_0)
 
+	// Set default values for directory key manager
+	viper.SetDefault(config.DirectoryKeysDir, constants.DefaultDirectoryKeysDir)
+	viper.SetDefault(config.DirectoryMasterKey, constants.DefaultDirectoryMasterKeyFile)
+
+	// Set default values for the database of the postgres metadata store
+	viper.SetDefault(commConfig.DbVendor, constants.DBTypePostgres)
+	viper.SetDefault(commConfig.DbHost, "localhost")
+	viper.SetDefault(commConfig.DbPort, "5432")
+	viper.SetDefault(commConfig.DbName, constants.DefaultDbName)
+	viper.SetDefault(commConfig.DbSslMode, constants.SslModeVerifyFull)
+	viper.SetDefault(commConfig.DbSslCert, constants.DefaultDbSSLCertFile)
+	viper.SetDefault(commConfig.DbConnRetryAttempts, constants.DefaultDbConnRetryAttempts)
+	viper.SetDefault(commConfig.DbConnRetryTime, constants.DefaultDbConnRetryTime)
+
 	// Set default values for server
 	viper.SetDefault(commConfig.ServerPort, constants.DefaultKBSListenerPort)
 	viper.SetDefault(commConfig.ServerReadTimeout, constants.DefaultReadTimeout)
@@ -55,6 +70,19 @@ func defaultConfig() *config.Configuration {
 			Username: viper.GetString(config.KBSServiceUsername),
 			Password: viper.GetString(config.KBSServicePassword),
 		},
+		MetadataStore: viper.GetString(config.MetadataStore),
+		DB: commConfig.DBConfig{
+			Vendor:                  viper.GetString(commConfig.DbVendor),
+			Host:                    viper.GetString(commConfig.DbHost),
+			Port:                    viper.GetInt(commConfig.DbPort),
+			DBName:                  viper.GetString(commConfig.DbName),
+			Username:                viper.GetString(commConfig.DbUsername),
+			Password:                viper.GetString(commConfig.DbPassword),
+			SSLMode:                 viper.GetString(commConfig.DbSslMode),
+			SSLCert:                 viper.GetString(commConfig.DbSslCert),
+			ConnectionRetryAttempts: viper.GetInt(commConfig.DbConnRetryAttempts),
+			ConnectionRetryTime:     viper.GetInt(commConfig.DbConnRetryTime),
+		},
 		TLS: commConfig.TLSCertConfig{
 			CertFile:   viper.GetString(commConfig.TlsCertFile),
 			KeyFile:    viper.GetString(commConfig.TlsKeyFile),
@@ -85,6 +113,15 @@ func defaultConfig() *config.Configuration {
 			ClientCertificateFilePath: viper.GetString("kmip-client-cert-path"),
 			RootCertificateFilePath:   viper.GetString("kmip-root-cert-path"),
 		},
+		Directory: config.DirectoryConfig{
+			KeysDir:       viper.GetString(config.DirectoryKeysDir),
+			MasterKeyFile: viper.GetString(config.DirectoryMasterKey),
+		},
+		Pkcs11: config.Pkcs11Config{
+			ModulePath: viper.GetString(config.Pkcs11ModulePath),
+			TokenLabel: viper.GetString(config.Pkcs11TokenLabel),
+			Pin:        viper.GetString(config.Pkcs11Pin),
+		},
 		Skc: config.SKCConfig{
 			StmLabel:          viper.GetString("skc-challenge-type"),
 			SQVSUrl:           viper.GetString("sqvs-url"),
@@ -95,8 +132,17 @@ func defaultConfig() *config.Configuration {
 
 func loadAlias() {
 	alias := map[string]string{
-		commConfig.TlsSanList: "SAN_LIST",
-		commConfig.AasBaseUrl: "AAS_API_URL",
+		commConfig.TlsSanList:      "SAN_LIST",
+		commConfig.AasBaseUrl:      "AAS_API_URL",
+		commConfig.DbHost:          "KBS_DB_HOSTNAME",
+		commConfig.DbVendor:        "KBS_DB_VENDOR",
+		commConfig.DbPort:          "KBS_DB_PORT",
+		commConfig.DbName:          "KBS_DB_NAME",
+		commConfig.DbUsername:      "KBS_DB_USERNAME",
+		commConfig.DbPassword:      "KBS_DB_PASSWORD",
+		commConfig.DbSslCert:       "KBS_DB_SSLCERT",
+		commConfig.DbSslCertSource: "KBS_DB_SSLCERTSRC",
+		commConfig.DbSslMode:       "KBS_DB_SSL_MODE",
 	}
 	for k, v := range alias {
 		if env := os.Getenv(v); env != "" {
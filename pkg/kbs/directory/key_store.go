@@ -64,6 +64,27 @@ func (ks *KeyStore) Retrieve(id uuid.UUID) (*models.KeyAttributes, error) {
 	return &key, nil
 }
 
+func (ks *KeyStore) Update(key *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("directory/key_store:Update() Entering")
+	defer defaultLog.Trace("directory/key_store:Update() Leaving")
+
+	if _, err := ks.Retrieve(key.ID); err != nil {
+		return nil, err
+	}
+
+	bytes, err := json.Marshal(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_store:Update() Failed to marshal key attributes")
+	}
+
+	err = ioutil.WriteFile(filepath.Join(ks.dir, key.ID.String()), bytes, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_store:Update() Failed to store key attributes in file")
+	}
+
+	return key, nil
+}
+
 func (ks *KeyStore) Delete(id uuid.UUID) error {
 	defaultLog.Trace("directory/key_store:Delete() Entering")
 	defer defaultLog.Trace("directory/key_store:Delete() Leaving")
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package directory
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+type KeyTransferEventStore struct {
+	dir string
+}
+
+func NewKeyTransferEventStore(dir string) *KeyTransferEventStore {
+	return &KeyTransferEventStore{dir}
+}
+
+func (ktes *KeyTransferEventStore) Create(event *kbs.KeyTransferEvent) (*kbs.KeyTransferEvent, error) {
+	defaultLog.Trace("directory/key_transfer_event_store:Create() Entering")
+	defer defaultLog.Trace("directory/key_transfer_event_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_transfer_event_store:Create() failed to create new UUID")
+	}
+	event.ID = newUuid
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_transfer_event_store:Create() Failed to marshal key transfer event")
+	}
+
+	// the directory is only created by the setup of the new deployments
+	err = os.MkdirAll(ktes.dir, 0700)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_transfer_event_store:Create() Error in creating the key transfer events directory")
+	}
+	err = ioutil.WriteFile(filepath.Join(ktes.dir, event.ID.String()), bytes, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "directory/key_transfer_event_store:Create() Error in saving key transfer event")
+	}
+
+	return event, nil
+}
+
+func (ktes *KeyTransferEventStore) Search(criteria *models.KeyTransferEventFilterCriteria) ([]kbs.KeyTransferEvent, error) {
+	defaultLog.Trace("directory/key_transfer_event_store:Search() Entering")
+	defer defaultLog.Trace("directory/key_transfer_event_store:Search() Leaving")
+
+	var events = []kbs.KeyTransferEvent{}
+	eventFiles, err := ioutil.ReadDir(ktes.dir)
+	if os.IsNotExist(err) {
+		return events, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "directory/key_transfer_event_store:Search() Error in reading the key transfer events directory : %s", ktes.dir)
+	}
+
+	for _, eventFile := range eventFiles {
+		bytes, err := ioutil.ReadFile(filepath.Join(ktes.dir, eventFile.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "directory/key_transfer_event_store:Search() Unable to read key transfer event file : %s", eventFile.Name())
+		}
+
+		var event kbs.KeyTransferEvent
+		err = json.Unmarshal(bytes, &event)
+		if err != nil {
+			return nil, errors.Wrapf(err, "directory/key_transfer_event_store:Search() Failed to unmarshal key transfer event from file : %s", eventFile.Name())
+		}
+
+		if matchesKeyTransferEvent(&event, criteria) {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	return events, nil
+}
+
+// helper function to check if the key transfer event matches the given filter criteria.
+func matchesKeyTransferEvent(event *kbs.KeyTransferEvent, criteria *models.KeyTransferEventFilterCriteria) bool {
+	if criteria == nil || reflect.DeepEqual(*criteria, models.KeyTransferEventFilterCriteria{}) {
+		return true
+	}
+
+	if criteria.KeyId != uuid.Nil && event.KeyID != criteria.KeyId {
+		return false
+	}
+	if criteria.Requester != "" && event.Requester != criteria.Requester {
+		return false
+	}
+	if criteria.Decision != "" && event.Decision != criteria.Decision {
+		return false
+	}
+	if !criteria.FromDate.IsZero() && event.CreatedAt.Before(criteria.FromDate) {
+		return false
+	}
+	if !criteria.ToDate.IsZero() && event.CreatedAt.After(criteria.ToDate) {
+		return false
+	}
+	return true
+}
@@ -9,8 +9,8 @@ import "github.com/google/uuid"
 type KeyTransferControllerConfig struct {
 	AasBaseUrl              string
 	AasJwtSigningCertsDir   string
-	SamlCertsDir            string
+	SamlCertStore           CertificateStore
 	TrustedCaCertsDir       string
-	TpmIdentityCertsDir     string
+	TpmIdentityCertStore    CertificateStore
 	DefaultTransferPolicyId uuid.UUID
 }
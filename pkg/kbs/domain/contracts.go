@@ -14,6 +14,7 @@ type (
 	KeyStore interface {
 		Create(*models.KeyAttributes) (*models.KeyAttributes, error)
 		Retrieve(uuid.UUID) (*models.KeyAttributes, error)
+		Update(*models.KeyAttributes) (*models.KeyAttributes, error)
 		Delete(uuid.UUID) error
 		Search(criteria *models.KeyFilterCriteria) ([]models.KeyAttributes, error)
 	}
@@ -32,4 +33,9 @@ type (
 		Delete(uuid.UUID) error
 		Search(criteria *models.CertificateFilterCriteria) ([]kbs.Certificate, error)
 	}
+
+	KeyTransferEventStore interface {
+		Create(event *kbs.KeyTransferEvent) (*kbs.KeyTransferEvent, error)
+		Search(criteria *models.KeyTransferEventFilterCriteria) ([]kbs.KeyTransferEvent, error)
+	}
 )
@@ -41,6 +41,15 @@ func (store *MockKeyStore) Retrieve(id uuid.UUID) (*models.KeyAttributes, error)
 	return nil, errors.New(commErr.RecordNotFound)
 }
 
+// Update replaces a Key in the store
+func (store *MockKeyStore) Update(k *models.KeyAttributes) (*models.KeyAttributes, error) {
+	if _, ok := store.KeyStore[k.ID]; ok {
+		store.KeyStore[k.ID] = k
+		return k, nil
+	}
+	return nil, errors.New(commErr.RecordNotFound)
+}
+
 // Delete deletes Key from the store
 func (store *MockKeyStore) Delete(id uuid.UUID) error {
 	if _, ok := store.KeyStore[id]; ok {
@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package mocks
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	log "github.com/sirupsen/logrus"
+)
+
+// MockKeyTransferEventStore provides a mocked implementation of interface domain.KeyTransferEventStore
+type MockKeyTransferEventStore struct {
+	KeyTransferEventStore map[uuid.UUID]*kbs.KeyTransferEvent
+}
+
+// Create inserts a KeyTransferEvent into the store
+func (store *MockKeyTransferEventStore) Create(e *kbs.KeyTransferEvent) (*kbs.KeyTransferEvent, error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	store.KeyTransferEventStore[e.ID] = e
+	return e, nil
+}
+
+// Search returns a filtered list of KeyTransferEvents per the provided KeyTransferEventFilterCriteria
+func (store *MockKeyTransferEventStore) Search(criteria *models.KeyTransferEventFilterCriteria) ([]kbs.KeyTransferEvent, error) {
+
+	events := []kbs.KeyTransferEvent{}
+	for _, e := range store.KeyTransferEventStore {
+		if criteria != nil {
+			if criteria.KeyId != uuid.Nil && e.KeyID != criteria.KeyId {
+				continue
+			}
+			if criteria.Requester != "" && e.Requester != criteria.Requester {
+				continue
+			}
+			if criteria.Decision != "" && e.Decision != criteria.Decision {
+				continue
+			}
+			if !criteria.FromDate.IsZero() && e.CreatedAt.Before(criteria.FromDate) {
+				continue
+			}
+			if !criteria.ToDate.IsZero() && e.CreatedAt.After(criteria.ToDate) {
+				continue
+			}
+		}
+		events = append(events, *e)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
+// NewFakeKeyTransferEventStore loads dummy data into MockKeyTransferEventStore
+func NewFakeKeyTransferEventStore() *MockKeyTransferEventStore {
+	store := &MockKeyTransferEventStore{}
+	store.KeyTransferEventStore = make(map[uuid.UUID]*kbs.KeyTransferEvent)
+
+	_, err := store.Create(&kbs.KeyTransferEvent{
+		ID:           uuid.MustParse("0b4bd1b4-1e1e-4a4f-b43b-e7bb4a0a3bd1"),
+		KeyID:        uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"),
+		Requester:    "00ecd3ab-9af4-e711-906e-001560a04062",
+		TransferType: "saml",
+		Decision:     "granted",
+		CreatedAt:    time.Now().UTC().Add(-2 * time.Hour),
+	})
+	if err != nil {
+		log.WithError(err).Errorf("Error creating key transfer event")
+	}
+
+	_, err = store.Create(&kbs.KeyTransferEvent{
+		ID:           uuid.MustParse("6a1b7a43-e1b5-4e7b-9f0d-3b7e8c4b3c52"),
+		KeyID:        uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2"),
+		Requester:    "7a569dad-2d82-49e4-9156-069b0065b262",
+		TransferType: "saml",
+		Decision:     "denied",
+		FailedClause: "required_trust[TRUST_OS]",
+		Reason:       "trust attribute of the host is false",
+		CreatedAt:    time.Now().UTC().Add(-time.Hour),
+	})
+	if err != nil {
+		log.WithError(err).Errorf("Error creating key transfer event")
+	}
+
+	_, err = store.Create(&kbs.KeyTransferEvent{
+		ID:           uuid.MustParse("c9d4dc1a-3bf6-4a3e-9a3c-6c2c5f0e4f5d"),
+		KeyID:        uuid.MustParse("87d59b82-33b7-47e7-8fcb-6f7f12c82719"),
+		Requester:    "skcuser",
+		TransferType: "skc",
+		Decision:     "granted",
+		CreatedAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		log.WithError(err).Errorf("Error creating key transfer event")
+	}
+	return store
+}
@@ -8,24 +8,80 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
 	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
 )
 
 // KeyAttributes - Contains all possible key attributes.
 type KeyAttributes struct {
-	ID               uuid.UUID `json:"id"`
-	Algorithm        string    `json:"algorithm"`
-	KeyLength        int       `json:"key_length,omitempty"`
-	KeyData          string    `json:"key,omitempty"`
-	CurveType        string    `json:"curve_type,omitempty"`
-	PublicKey        string    `json:"public_key,omitempty"`
-	PrivateKey       string    `json:"private_key,omitempty"`
-	KmipKeyID        string    `json:"kmip_key_id,omitempty"`
-	TransferPolicyId uuid.UUID `json:"transfer_policy_id,omitempty"`
-	TransferLink     string    `json:"transfer_link,omitempty"`
-	CreatedAt        time.Time `json:"created_at,omitempty"`
-	Label            string    `json:"label,omitempty"`
-	Usage            string    `json:"usage,omitempty"`
+	ID               uuid.UUID           `json:"id"`
+	Algorithm        string              `json:"algorithm"`
+	KeyLength        int                 `json:"key_length,omitempty"`
+	KeyData          string              `json:"key,omitempty"`
+	CurveType        string              `json:"curve_type,omitempty"`
+	PublicKey        string              `json:"public_key,omitempty"`
+	PrivateKey       string              `json:"private_key,omitempty"`
+	KmipKeyID        string              `json:"kmip_key_id,omitempty"`
+	Pkcs11KeyID      string              `json:"pkcs11_key_id,omitempty"`
+	TransferPolicyId uuid.UUID           `json:"transfer_policy_id,omitempty"`
+	TransferLink     string              `json:"transfer_link,omitempty"`
+	CreatedAt        time.Time           `json:"created_at,omitempty"`
+	Label            string              `json:"label,omitempty"`
+	Usage            string              `json:"usage,omitempty"`
+	UsagePolicy      *kbs.KeyUsagePolicy `json:"usage_policy,omitempty"`
+	// Version is the version of the current key material, incremented each time the key is rotated
+	Version          int          `json:"version,omitempty"`
+	RotatedAt        *time.Time   `json:"rotated_at,omitempty"`
+	PreviousVersions []KeyVersion `json:"previous_versions,omitempty"`
+}
+
+// KeyVersion - Contains the references to the key material of a previous version of a rotated key.
+type KeyVersion struct {
+	Version     int       `json:"version"`
+	KmipKeyID   string    `json:"kmip_key_id,omitempty"`
+	Pkcs11KeyID string    `json:"pkcs11_key_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CurrentVersion returns the version of the current key material. Keys that were never rotated are at version 1.
+func (ka *KeyAttributes) CurrentVersion() int {
+	if ka.Version == 0 {
+		return 1
+	}
+	return ka.Version
+}
+
+// CurrentVersionCreatedAt returns the time at which the current key material was created
+func (ka *KeyAttributes) CurrentVersionCreatedAt() time.Time {
+	if ka.RotatedAt != nil {
+		return *ka.RotatedAt
+	}
+	return ka.CreatedAt
+}
+
+// AtVersion returns the key attributes referencing the key material of the version, or a RecordNotFound error when
+// the key has no such version
+func (ka *KeyAttributes) AtVersion(version int) (*KeyAttributes, error) {
+	if version == ka.CurrentVersion() {
+		return ka, nil
+	}
+	for _, previous := range ka.PreviousVersions {
+		if previous.Version == version {
+			keyAttributes := *ka
+			keyAttributes.Version = previous.Version
+			keyAttributes.KmipKeyID = previous.KmipKeyID
+			keyAttributes.Pkcs11KeyID = previous.Pkcs11KeyID
+			keyAttributes.RotatedAt = nil
+			keyAttributes.PreviousVersions = nil
+			if previous.Version > 1 {
+				createdAt := previous.CreatedAt
+				keyAttributes.RotatedAt = &createdAt
+			}
+			return &keyAttributes, nil
+		}
+	}
+	return nil, errors.New(commErr.RecordNotFound)
 }
 
 func (ka *KeyAttributes) ToKeyResponse() *kbs.KeyResponse {
@@ -45,6 +101,9 @@ func (ka *KeyAttributes) ToKeyResponse() *kbs.KeyResponse {
 		CreatedAt:        ka.CreatedAt,
 		Label:            ka.Label,
 		Usage:            ka.Usage,
+		UsagePolicy:      ka.UsagePolicy,
+		Version:          ka.CurrentVersion(),
+		RotatedAt:        ka.RotatedAt,
 	}
 
 	return &keyResponse
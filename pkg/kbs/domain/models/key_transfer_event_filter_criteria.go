@@ -0,0 +1,20 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyTransferEventFilterCriteria stores the parameters for filtering the key transfer events
+type KeyTransferEventFilterCriteria struct {
+	KeyId     uuid.UUID
+	Requester string
+	Decision  string
+	FromDate  time.Time
+	ToDate    time.Time
+}
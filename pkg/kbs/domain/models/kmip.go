@@ -37,8 +37,14 @@ type CreateKeyPairRequestPayload struct {
 
 // CommonAttributes payload required in CreateKeyPair request.
 type CommonAttributes struct {
-	CryptographicAlgorithm kmip14.CryptographicAlgorithm
-	CryptographicLength    int32
+	CryptographicAlgorithm        kmip14.CryptographicAlgorithm
+	CryptographicLength           int32
+	CryptographicDomainParameters *CryptographicDomainParameters `ttlv:",omitempty"`
+}
+
+// CryptographicDomainParameters payload represents the curve of EC key pairs
+type CryptographicDomainParameters struct {
+	RecommendedCurve kmip14.RecommendedCurve
 }
 
 // PrivateKeyAttributes payload represents usage mask for private key
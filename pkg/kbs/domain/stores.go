@@ -0,0 +1,16 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package domain
+
+// MetadataStores holds the stores of the keys, key transfer policies, key transfer events and of the SAML and TPM
+// identity certificates, which are backed either by the directory or by the database depending on the configured
+// metadata store
+type MetadataStores struct {
+	KeyStore                    KeyStore
+	KeyTransferPolicyStore      KeyTransferPolicyStore
+	KeyTransferEventStore       KeyTransferEventStore
+	SamlCertificateStore        CertificateStore
+	TpmIdentityCertificateStore CertificateStore
+}
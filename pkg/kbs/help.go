@@ -34,6 +34,10 @@ Available Tasks for setup:
 	download-ca-cert                    Download CMS root CA certificate
 	download-cert-tls                   Download CA certificate from CMS for tls
 	create-default-key-transfer-policy  Create default key transfer policy for KBS
+	database                            Setup database for the postgres metadata store, only when METADATA_STORE is postgres
+	migrate-directory-store             Import the keys, key transfer policies, key transfer events and the SAML and TPM
+	                                    identity certificates of the directory metadata store into the database, only
+	                                    when METADATA_STORE is postgres
 	update-service-config               Sets or Updates the Service configuration `
 
 func (app *App) printUsage() {
@@ -0,0 +1,391 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// masterKeyLength is the length in bytes of the AES-256 master key encrypting the key material
+const masterKeyLength = 32
+
+var ellipticCurves = map[string]elliptic.Curve{
+	"secp256r1":  elliptic.P256(),
+	"prime256v1": elliptic.P256(),
+	"secp384r1":  elliptic.P384(),
+	"secp521r1":  elliptic.P521(),
+}
+
+// DirectoryManager is a software key manager storing the key material in a local directory, one file per key,
+// encrypted with AES-GCM under a master key. AES keys are stored as raw bytes, RSA keys as PKCS#1 DER and EC keys
+// as SEC 1 DER, which is also the format returned by TransferKey, without the public key for EC keys.
+type DirectoryManager struct {
+	keysDir   string
+	masterKey []byte
+}
+
+// NewDirectoryManager returns a DirectoryManager storing the keys in keysDir. The master key is read from
+// masterKeyFile, and generated in it when the file does not exist.
+func NewDirectoryManager(keysDir, masterKeyFile string) (*DirectoryManager, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:NewDirectoryManager() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:NewDirectoryManager() Leaving")
+
+	if err := os.MkdirAll(keysDir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create keys directory")
+	}
+
+	masterKey, err := ioutil.ReadFile(masterKeyFile)
+	if os.IsNotExist(err) {
+		defaultLog.Infof("keymanager/directory_key_manager:NewDirectoryManager() Generating master key in %s", masterKeyFile)
+		masterKey = make([]byte, masterKeyLength)
+		if _, err = rand.Read(masterKey); err != nil {
+			return nil, errors.Wrap(err, "failed to generate master key")
+		}
+		if err = ioutil.WriteFile(masterKeyFile, masterKey, 0600); err != nil {
+			return nil, errors.Wrap(err, "failed to write master key file")
+		}
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read master key file")
+	}
+
+	if len(masterKey) != masterKeyLength {
+		return nil, errors.Errorf("master key must be %d bytes long", masterKeyLength)
+	}
+
+	return &DirectoryManager{
+		keysDir:   keysDir,
+		masterKey: masterKey,
+	}, nil
+}
+
+func (dm *DirectoryManager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:CreateKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:CreateKey() Leaving")
+
+	keyAttributes, err := newKeyAttributes(request)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes, err := generateKey(keyAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = dm.storeKey(keyAttributes, keyBytes); err != nil {
+		return nil, err
+	}
+	return keyAttributes, nil
+}
+
+func (dm *DirectoryManager) DeleteKey(attributes *models.KeyAttributes) error {
+	defaultLog.Trace("keymanager/directory_key_manager:DeleteKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:DeleteKey() Leaving")
+
+	if attributes.KmipKeyID != "" || attributes.Pkcs11KeyID != "" {
+		return errors.New("key is not created with directory key manager")
+	}
+
+	if err := os.Remove(filepath.Join(dm.keysDir, keyFileName(attributes))); err != nil {
+		return errors.Wrap(err, "failed to delete key file")
+	}
+	return nil
+}
+
+func (dm *DirectoryManager) RegisterKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:RegisterKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:RegisterKey() Leaving")
+
+	if request.KeyInformation.KeyString == "" {
+		return nil, errors.New("key_string cannot be empty for register operation in directory mode")
+	}
+
+	keyAttributes, err := newKeyAttributes(request)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode([]byte(request.KeyInformation.KeyString))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM formatted key_string")
+	}
+
+	var keyBytes []byte
+	switch keyAttributes.Algorithm {
+	case constants.CRYPTOALG_AES:
+		if err = validateAESKeyLength(len(block.Bytes) * 8); err != nil {
+			return nil, err
+		}
+		keyAttributes.KeyLength = len(block.Bytes) * 8
+		keyBytes = block.Bytes
+	case constants.CRYPTOALG_RSA:
+		rsaKey, err := parseRSAPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keyAttributes.KeyLength = rsaKey.N.BitLen()
+		keyBytes = x509.MarshalPKCS1PrivateKey(rsaKey)
+	case constants.CRYPTOALG_EC:
+		ecKey, err := parseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if curve, ok := ellipticCurves[keyAttributes.CurveType]; !ok || curve != ecKey.Curve {
+			return nil, errors.Errorf("key_string is not a %s key", keyAttributes.CurveType)
+		}
+		keyBytes, err = x509.MarshalECPrivateKey(ecKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal EC private key")
+		}
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
+	}
+
+	if err = dm.storeKey(keyAttributes, keyBytes); err != nil {
+		return nil, err
+	}
+	return keyAttributes, nil
+}
+
+func (dm *DirectoryManager) TransferKey(attributes *models.KeyAttributes) ([]byte, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:TransferKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:TransferKey() Leaving")
+
+	if attributes.KmipKeyID != "" || attributes.Pkcs11KeyID != "" {
+		return nil, errors.New("key is not created with directory key manager")
+	}
+
+	fileName := keyFileName(attributes)
+	encryptedKey, err := ioutil.ReadFile(filepath.Join(dm.keysDir, fileName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read key file")
+	}
+
+	gcm, err := dm.newGCM()
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedKey) < gcm.NonceSize() {
+		return nil, errors.New("key file is too short")
+	}
+
+	nonce, cipherText := encryptedKey[:gcm.NonceSize()], encryptedKey[gcm.NonceSize():]
+	keyBytes, err := gcm.Open(nil, nonce, cipherText, []byte(fileName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt key")
+	}
+	if attributes.Algorithm == constants.CRYPTOALG_EC {
+		return transferECPrivateKey(keyBytes)
+	}
+	return keyBytes, nil
+}
+
+func (dm *DirectoryManager) RotateKey(attributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/directory_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/directory_key_manager:RotateKey() Leaving")
+
+	rotatedAttributes := *attributes
+	keyBytes, err := generateKey(&rotatedAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = dm.storeKey(&rotatedAttributes, keyBytes); err != nil {
+		return nil, err
+	}
+	return &rotatedAttributes, nil
+}
+
+// storeKey encrypts the key material under the master key and writes it in the key file. The name of the key file is
+// used as additional data, so that a key file cannot be swapped for the file of another key or version.
+func (dm *DirectoryManager) storeKey(attributes *models.KeyAttributes, keyBytes []byte) error {
+	gcm, err := dm.newGCM()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.Wrap(err, "failed to generate nonce")
+	}
+
+	fileName := keyFileName(attributes)
+	encryptedKey := gcm.Seal(nonce, nonce, keyBytes, []byte(fileName))
+	if err = ioutil.WriteFile(filepath.Join(dm.keysDir, fileName), encryptedKey, 0600); err != nil {
+		return errors.Wrap(err, "failed to write key file")
+	}
+	return nil
+}
+
+func (dm *DirectoryManager) newGCM() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dm.masterKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher from master key")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GCM cipher")
+	}
+	return gcm, nil
+}
+
+// keyFileName returns the name of the file holding the key material of the version of the key. The first version is
+// stored in a file named after the key ID.
+func keyFileName(attributes *models.KeyAttributes) string {
+	if attributes.CurrentVersion() == 1 {
+		return attributes.ID.String()
+	}
+	return fmt.Sprintf("%s.v%d", attributes.ID.String(), attributes.CurrentVersion())
+}
+
+// generateKey generates the key material for the algorithm and the length or curve of the key attributes
+func generateKey(keyAttributes *models.KeyAttributes) ([]byte, error) {
+	switch keyAttributes.Algorithm {
+	case constants.CRYPTOALG_AES:
+		if err := validateAESKeyLength(keyAttributes.KeyLength); err != nil {
+			return nil, err
+		}
+		keyBytes := make([]byte, keyAttributes.KeyLength/8)
+		if _, err := rand.Read(keyBytes); err != nil {
+			return nil, errors.Wrap(err, "failed to create AES key")
+		}
+		return keyBytes, nil
+	case constants.CRYPTOALG_RSA:
+		rsaKey, err := rsa.GenerateKey(rand.Reader, keyAttributes.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create RSA key pair")
+		}
+		return x509.MarshalPKCS1PrivateKey(rsaKey), nil
+	case constants.CRYPTOALG_EC:
+		curve, ok := ellipticCurves[keyAttributes.CurveType]
+		if !ok {
+			return nil, errors.Errorf("%s curve type is not supported", keyAttributes.CurveType)
+		}
+		ecKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create EC key pair")
+		}
+		keyBytes, err := x509.MarshalECPrivateKey(ecKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal EC private key")
+		}
+		return keyBytes, nil
+	}
+	return nil, errors.Errorf("%s algorithm is not supported", keyAttributes.Algorithm)
+}
+
+func newKeyAttributes(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new UUID")
+	}
+
+	keyAttributes := &models.KeyAttributes{
+		ID:               newUuid,
+		Algorithm:        strings.ToUpper(request.KeyInformation.Algorithm),
+		TransferPolicyId: request.TransferPolicyID,
+		CreatedAt:        time.Now().UTC(),
+		Label:            request.Label,
+		Usage:            request.Usage,
+	}
+	if keyAttributes.Algorithm == constants.CRYPTOALG_EC {
+		keyAttributes.CurveType = request.KeyInformation.CurveType
+	} else {
+		keyAttributes.KeyLength = request.KeyInformation.KeyLength
+	}
+	return keyAttributes, nil
+}
+
+func validateAESKeyLength(keyLength int) error {
+	switch keyLength {
+	case 128, 192, 256:
+		return nil
+	}
+	return errors.Errorf("%d bits is not a valid AES key length", keyLength)
+}
+
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if rsaKey, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return rsaKey, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse RSA private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key_string is not an RSA private key")
+	}
+	return rsaKey, nil
+}
+
+func parseECPrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	if ecKey, err := x509.ParseECPrivateKey(der); err == nil {
+		return ecKey, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse EC private key")
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key_string is not an EC private key")
+	}
+	return ecKey, nil
+}
+
+// ecPrivateKey is the SEC 1 ECPrivateKey structure
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// transferECPrivateKey converts a SEC 1 or PKCS#8 EC private key to the SEC 1 DER format transferred by the key
+// managers. The optional public key is left out so that P-384 keys fit in the RSA-OAEP wrapping of 2048 bits
+// envelope keys, and is computed again from the private key by the workloads.
+func transferECPrivateKey(der []byte) ([]byte, error) {
+	ecKey, err := parseECPrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	der, err = x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal EC private key")
+	}
+
+	var key ecPrivateKey
+	if _, err = asn1.Unmarshal(der, &key); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal EC private key")
+	}
+	key.PublicKey = asn1.BitString{}
+	der, err = asn1.Marshal(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal EC private key")
+	}
+	return der, nil
+}
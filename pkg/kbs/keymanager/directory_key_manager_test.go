@@ -0,0 +1,350 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/mocks"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+)
+
+func newTestDirectoryManager(t *testing.T) (*DirectoryManager, string) {
+	dir, err := ioutil.TempDir("", "directory-key-manager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	dm, err := NewDirectoryManager(filepath.Join(dir, "keys"), filepath.Join(dir, "master-key"))
+	if err != nil {
+		t.Fatalf("NewDirectoryManager() error = %v", err)
+	}
+	return dm, dir
+}
+
+func TestNewDirectoryManager(t *testing.T) {
+	dm, dir := newTestDirectoryManager(t)
+
+	masterKeyFile := filepath.Join(dir, "master-key")
+	info, err := os.Stat(masterKeyFile)
+	if err != nil {
+		t.Fatalf("master key file was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("master key file permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	reloaded, err := NewDirectoryManager(filepath.Join(dir, "keys"), masterKeyFile)
+	if err != nil {
+		t.Fatalf("NewDirectoryManager() error = %v", err)
+	}
+	if !bytes.Equal(dm.masterKey, reloaded.masterKey) {
+		t.Error("NewDirectoryManager() did not load the existing master key")
+	}
+
+	if err = ioutil.WriteFile(masterKeyFile, []byte("short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewDirectoryManager(filepath.Join(dir, "keys"), masterKeyFile); err == nil {
+		t.Error("NewDirectoryManager() should fail with a master key of invalid length")
+	}
+}
+
+func TestDirectoryManagerCreateKey(t *testing.T) {
+	dm, _ := newTestDirectoryManager(t)
+
+	tests := []struct {
+		name       string
+		keyInfo    kbs.KeyInformation
+		wantLength int
+		wantErr    bool
+	}{
+		{
+			name:       "create AES key",
+			keyInfo:    kbs.KeyInformation{Algorithm: "AES", KeyLength: 256},
+			wantLength: 32,
+		},
+		{
+			name:    "create RSA key",
+			keyInfo: kbs.KeyInformation{Algorithm: "rsa", KeyLength: 2048},
+		},
+		{
+			name:    "create EC key",
+			keyInfo: kbs.KeyInformation{Algorithm: "EC", CurveType: "prime256v1"},
+		},
+		{
+			name:    "negative test - invalid AES key length",
+			keyInfo: kbs.KeyInformation{Algorithm: "AES", KeyLength: 2048},
+			wantErr: true,
+		},
+		{
+			name:    "negative test - curve type not supported",
+			keyInfo: kbs.KeyInformation{Algorithm: "EC", CurveType: "secp224r1"},
+			wantErr: true,
+		},
+		{
+			name:    "negative test - algorithm not supported",
+			keyInfo: kbs.KeyInformation{Algorithm: "ECB", KeyLength: 2048},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyInfo := tt.keyInfo
+			keyAttributes, err := dm.CreateKey(&kbs.KeyRequest{KeyInformation: &keyInfo})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			keyBytes, err := dm.TransferKey(keyAttributes)
+			if err != nil {
+				t.Fatalf("TransferKey() error = %v", err)
+			}
+			switch keyAttributes.Algorithm {
+			case "AES":
+				if len(keyBytes) != tt.wantLength {
+					t.Errorf("TransferKey() returned %d bytes, want %d", len(keyBytes), tt.wantLength)
+				}
+			case "RSA":
+				if _, err = x509.ParsePKCS1PrivateKey(keyBytes); err != nil {
+					t.Errorf("TransferKey() did not return a PKCS#1 key: %v", err)
+				}
+			case "EC":
+				if _, err = x509.ParseECPrivateKey(keyBytes); err != nil {
+					t.Errorf("TransferKey() did not return a SEC 1 key: %v", err)
+				}
+			}
+
+			if err = dm.DeleteKey(keyAttributes); err != nil {
+				t.Fatalf("DeleteKey() error = %v", err)
+			}
+			if _, err = dm.TransferKey(keyAttributes); err == nil {
+				t.Error("TransferKey() should fail after the key is deleted")
+			}
+		})
+	}
+}
+
+func TestDirectoryManagerRegisterKey(t *testing.T) {
+	dm, _ := newTestDirectoryManager(t)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aesKey := make([]byte, 16)
+	if _, err = rand.Read(aesKey); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		keyInfo kbs.KeyInformation
+		wantErr bool
+	}{
+		{
+			name:    "register AES key",
+			keyInfo: kbs.KeyInformation{Algorithm: "AES", KeyLength: 128, KeyString: string(pem.EncodeToMemory(&pem.Block{Type: "AES KEY", Bytes: aesKey}))},
+		},
+		{
+			name:    "register RSA key",
+			keyInfo: kbs.KeyInformation{Algorithm: "RSA", KeyLength: 2048, KeyString: privateKey},
+		},
+		{
+			name:    "register EC key",
+			keyInfo: kbs.KeyInformation{Algorithm: "EC", CurveType: "secp384r1", KeyString: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDer}))},
+		},
+		{
+			name:    "negative test - EC key of another curve",
+			keyInfo: kbs.KeyInformation{Algorithm: "EC", CurveType: "secp256r1", KeyString: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDer}))},
+			wantErr: true,
+		},
+		{
+			name:    "negative test - RSA key string is not an RSA key",
+			keyInfo: kbs.KeyInformation{Algorithm: "RSA", KeyLength: 2048, KeyString: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDer}))},
+			wantErr: true,
+		},
+		{
+			name:    "negative test - kmip key id only",
+			keyInfo: kbs.KeyInformation{Algorithm: "AES", KeyLength: 256, KmipKeyID: "1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyInfo := tt.keyInfo
+			keyAttributes, err := dm.RegisterKey(&kbs.KeyRequest{KeyInformation: &keyInfo})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RegisterKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			keyBytes, err := dm.TransferKey(keyAttributes)
+			if err != nil {
+				t.Fatalf("TransferKey() error = %v", err)
+			}
+			if keyAttributes.Algorithm == "AES" && !bytes.Equal(keyBytes, aesKey) {
+				t.Error("TransferKey() did not return the registered AES key")
+			}
+		})
+	}
+}
+
+func TestDirectoryManagerTransferKeyTampered(t *testing.T) {
+	dm, _ := newTestDirectoryManager(t)
+
+	first, err := dm.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := dm.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the key file of a key must not be usable as the key file of another key
+	if err = os.Rename(filepath.Join(dm.keysDir, keyFileName(first)), filepath.Join(dm.keysDir, keyFileName(second))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = dm.TransferKey(second); err == nil {
+		t.Error("TransferKey() should fail when the key file belongs to another key")
+	}
+
+	second.KmipKeyID = "1"
+	if _, err = dm.TransferKey(second); err == nil {
+		t.Error("TransferKey() should fail for a key created with the KMIP key manager")
+	}
+}
+
+func TestNewKeyManagerDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "directory-key-manager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	km, err := NewKeyManager(&config.Configuration{
+		KeyManager: "Directory",
+		Directory: config.DirectoryConfig{
+			KeysDir:       filepath.Join(dir, "keys"),
+			MasterKeyFile: filepath.Join(dir, "master-key"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyManager() error = %v", err)
+	}
+	if _, ok := km.(*DirectoryManager); !ok {
+		t.Errorf("NewKeyManager() returned %T, want *DirectoryManager", km)
+	}
+}
+
+func TestDirectoryManagerRotateKey(t *testing.T) {
+	dm, _ := newTestDirectoryManager(t)
+	keyStore := mocks.NewFakeKeyStore()
+	rm := NewRemoteManager(keyStore, dm, "https://localhost:9443/kbs/v1")
+
+	created, err := rm.CreateKey(&kbs.KeyRequest{KeyInformation: &kbs.KeyInformation{Algorithm: "AES", KeyLength: 256}})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+	keyId := created.KeyInformation.ID
+	firstVersion, err := rm.TransferKey(keyId)
+	if err != nil {
+		t.Fatalf("TransferKey() error = %v", err)
+	}
+
+	rotated, err := rm.RotateKey(keyId)
+	if err != nil {
+		t.Fatalf("RotateKey() error = %v", err)
+	}
+	if rotated.Version != 2 || rotated.KeyInformation.ID != keyId {
+		t.Fatalf("RotateKey() returned version %d of key %s, want version 2 of key %s", rotated.Version, rotated.KeyInformation.ID, keyId)
+	}
+
+	currentVersion, err := rm.TransferKey(keyId)
+	if err != nil {
+		t.Fatalf("TransferKey() error = %v", err)
+	}
+	if bytes.Equal(currentVersion, firstVersion) {
+		t.Error("TransferKey() returned the key material of the first version after rotation")
+	}
+	previousVersion, err := rm.TransferKeyVersion(keyId, 1)
+	if err != nil {
+		t.Fatalf("TransferKeyVersion() error = %v", err)
+	}
+	if !bytes.Equal(previousVersion, firstVersion) {
+		t.Error("TransferKeyVersion() did not return the key material of the first version")
+	}
+	if _, err = rm.TransferKeyVersion(keyId, 3); err == nil || err.Error() != commErr.RecordNotFound {
+		t.Errorf("TransferKeyVersion() error = %v, want %s", err, commErr.RecordNotFound)
+	}
+
+	if err = rm.DeleteKey(keyId); err != nil {
+		t.Fatalf("DeleteKey() error = %v", err)
+	}
+	keyFiles, err := ioutil.ReadDir(dm.keysDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keyFiles) != 0 {
+		t.Errorf("DeleteKey() left %d key files", len(keyFiles))
+	}
+}
+
+func TestTransferECPrivateKey(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			ecKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pkcs8Der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			keyBytes, err := transferECPrivateKey(pkcs8Der)
+			if err != nil {
+				t.Fatalf("transferECPrivateKey() error = %v", err)
+			}
+			// RSA-OAEP with SHA-384 and a 2048 bits envelope key wraps at most 158 bytes
+			if len(keyBytes) > 158 {
+				t.Errorf("transferECPrivateKey() returned %d bytes, too long to be wrapped", len(keyBytes))
+			}
+			transferredKey, err := x509.ParseECPrivateKey(keyBytes)
+			if err != nil {
+				t.Fatalf("transferECPrivateKey() did not return a SEC 1 key: %v", err)
+			}
+			if !transferredKey.Equal(ecKey) {
+				t.Error("transferECPrivateKey() did not return the same key")
+			}
+		})
+	}
+
+	if _, err := transferECPrivateKey([]byte("not a key")); err == nil {
+		t.Error("transferECPrivateKey() should fail for an invalid key")
+	}
+}
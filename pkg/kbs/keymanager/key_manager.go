@@ -11,6 +11,7 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/kmipclient"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/pkcs11client"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
 	"github.com/pkg/errors"
@@ -30,6 +31,21 @@ func NewKeyManager(cfg *config.Configuration) (KeyManager, error) {
 			return nil, errors.New("Failed to initialize KeyManager")
 		}
 		return NewKmipManager(kmipClient), nil
+	} else if strings.ToLower(cfg.KeyManager) == constants.DirectoryKeyManager {
+		directoryManager, err := NewDirectoryManager(cfg.Directory.KeysDir, cfg.Directory.MasterKeyFile)
+		if err != nil {
+			defaultLog.WithError(err).Error("keymanager/key_manager:NewKeyManager() Failed to initialize directory key manager")
+			return nil, errors.New("Failed to initialize KeyManager")
+		}
+		return directoryManager, nil
+	} else if strings.ToLower(cfg.KeyManager) == constants.Pkcs11KeyManager {
+		pkcs11Client := pkcs11client.NewPkcs11Client()
+		err := pkcs11Client.InitializeClient(cfg.Pkcs11.ModulePath, cfg.Pkcs11.TokenLabel, cfg.Pkcs11.Pin)
+		if err != nil {
+			defaultLog.WithError(err).Error("keymanager/key_manager:NewKeyManager() Failed to initialize PKCS#11 client")
+			return nil, errors.New("Failed to initialize KeyManager")
+		}
+		return NewPkcs11Manager(pkcs11Client), nil
 	} else {
 		defaultLog.Errorf("keymanager/key_manager:NewKeyManager() No Key Manager supported for provider: %s", cfg.KeyManager)
 		return nil, errors.Errorf("No Key Manager supported for provider: %s", cfg.KeyManager)
@@ -41,4 +57,7 @@ type KeyManager interface {
 	DeleteKey(*models.KeyAttributes) error
 	RegisterKey(*kbs.KeyRequest) (*models.KeyAttributes, error)
 	TransferKey(*models.KeyAttributes) ([]byte, error)
+	// RotateKey creates new key material of the same algorithm and length for the key attributes, which keep the key
+	// ID and are set to the new version by the caller
+	RotateKey(*models.KeyAttributes) (*models.KeyAttributes, error)
 }
@@ -5,6 +5,8 @@
 package keymanager
 
 import (
+	"crypto/elliptic"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +17,13 @@ import (
 	"github.com/pkg/errors"
 )
 
+// kmipECCurves are the curve types of the EC keys managed with kmip
+var kmipECCurves = map[string]elliptic.Curve{
+	"secp256r1":  elliptic.P256(),
+	"prime256v1": elliptic.P256(),
+	"secp384r1":  elliptic.P384(),
+}
+
 type KmipManager struct {
 	client kmipclient.KmipClient
 }
@@ -28,13 +37,13 @@ func (km *KmipManager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttributes
 	defer defaultLog.Trace("keymanager/kmip_key_manager:CreateKey() Leaving")
 
 	keyAttributes := &models.KeyAttributes{
-		Algorithm:        request.KeyInformation.Algorithm,
+		Algorithm:        strings.ToUpper(request.KeyInformation.Algorithm),
 		TransferPolicyId: request.TransferPolicyID,
 		Label:            request.Label,
 		Usage:            request.Usage,
 	}
 
-	switch request.KeyInformation.Algorithm {
+	switch keyAttributes.Algorithm {
 	case constants.CRYPTOALG_AES:
 		kmipId, err := km.client.CreateSymmetricKey(request.KeyInformation.KeyLength)
 		if err != nil {
@@ -49,6 +58,13 @@ func (km *KmipManager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttributes
 		}
 		keyAttributes.KeyLength = request.KeyInformation.KeyLength
 		keyAttributes.KmipKeyID = kmipId
+	case constants.CRYPTOALG_EC:
+		kmipId, err := km.createECKeyPair(request.KeyInformation.CurveType)
+		if err != nil {
+			return nil, err
+		}
+		keyAttributes.CurveType = request.KeyInformation.CurveType
+		keyAttributes.KmipKeyID = kmipId
 	default:
 		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
 	}
@@ -88,14 +104,21 @@ func (km *KmipManager) RegisterKey(request *kbs.KeyRequest) (*models.KeyAttribut
 	}
 	keyAttributes := &models.KeyAttributes{
 		ID:               newUuid,
-		Algorithm:        request.KeyInformation.Algorithm,
-		KeyLength:        request.KeyInformation.KeyLength,
+		Algorithm:        strings.ToUpper(request.KeyInformation.Algorithm),
 		KmipKeyID:        request.KeyInformation.KmipKeyID,
 		TransferPolicyId: request.TransferPolicyID,
 		CreatedAt:        time.Now().UTC(),
 		Label:            request.Label,
 		Usage:            request.Usage,
 	}
+	if keyAttributes.Algorithm == constants.CRYPTOALG_EC {
+		if _, ok := kmipECCurves[request.KeyInformation.CurveType]; !ok {
+			return nil, errors.Errorf("%s curve type is not supported", request.KeyInformation.CurveType)
+		}
+		keyAttributes.CurveType = request.KeyInformation.CurveType
+	} else {
+		keyAttributes.KeyLength = request.KeyInformation.KeyLength
+	}
 
 	return keyAttributes, nil
 }
@@ -108,9 +131,59 @@ func (km *KmipManager) TransferKey(attributes *models.KeyAttributes) ([]byte, er
 		return nil, errors.New("key is not created with KMIP key manager")
 	}
 
-	if attributes.Algorithm == constants.CRYPTOALG_AES || attributes.Algorithm == constants.CRYPTOALG_RSA {
+	switch attributes.Algorithm {
+	case constants.CRYPTOALG_AES, constants.CRYPTOALG_RSA:
 		return km.client.GetKey(attributes.KmipKeyID, attributes.Algorithm)
-	} else {
+	case constants.CRYPTOALG_EC:
+		keyBytes, err := km.client.GetKey(attributes.KmipKeyID, attributes.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return transferECPrivateKey(keyBytes)
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", attributes.Algorithm)
+	}
+}
+
+func (km *KmipManager) RotateKey(attributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/kmip_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/kmip_key_manager:RotateKey() Leaving")
+
+	rotatedAttributes := *attributes
+	var err error
+	switch attributes.Algorithm {
+	case constants.CRYPTOALG_AES:
+		rotatedAttributes.KmipKeyID, err = km.client.CreateSymmetricKey(attributes.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create AES key")
+		}
+	case constants.CRYPTOALG_RSA:
+		rotatedAttributes.KmipKeyID, err = km.client.CreateAsymmetricKeyPair(constants.CRYPTOALG_RSA, "", attributes.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create RSA key pair")
+		}
+	case constants.CRYPTOALG_EC:
+		rotatedAttributes.KmipKeyID, err = km.createECKeyPair(attributes.CurveType)
+		if err != nil {
+			return nil, err
+		}
+	default:
 		return nil, errors.Errorf("%s algorithm is not supported", attributes.Algorithm)
 	}
+
+	return &rotatedAttributes, nil
+}
+
+// createECKeyPair creates an EC key pair on one of the curves supported with kmip and returns its kmip key ID
+func (km *KmipManager) createECKeyPair(curveType string) (string, error) {
+	curve, ok := kmipECCurves[curveType]
+	if !ok {
+		return "", errors.Errorf("%s curve type is not supported", curveType)
+	}
+
+	kmipId, err := km.client.CreateAsymmetricKeyPair(constants.CRYPTOALG_EC, curveType, curve.Params().BitSize)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create EC key pair")
+	}
+	return kmipId, nil
 }
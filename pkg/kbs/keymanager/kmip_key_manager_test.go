@@ -5,6 +5,10 @@
 package keymanager
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"testing"
 
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
@@ -55,12 +59,21 @@ func TestKmipManagerCreateKey(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Create EC key pair - algorithm not supported",
+			name: "create EC key pair",
 			args: args{
 				algorithm: "EC",
 				curveType: "prime256v1",
 				funcName:  "CreateAsymmetricKeyPair",
 			},
+			wantErr: false,
+		},
+		{
+			name: "negative test - curve type not supported",
+			args: args{
+				algorithm: "EC",
+				curveType: "secp521r1",
+				funcName:  "CreateAsymmetricKeyPair",
+			},
 			wantErr: true,
 		},
 	}
@@ -136,6 +149,7 @@ func TestKmipManagerRegisterKey(t *testing.T) {
 
 	type args struct {
 		algorithm string
+		curveType string
 		kmipKeyID string
 	}
 	tests := []struct {
@@ -151,6 +165,24 @@ func TestKmipManagerRegisterKey(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "register EC key",
+			args: args{
+				algorithm: "EC",
+				curveType: "secp384r1",
+				kmipKeyID: "1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative testing - curve type not supported",
+			args: args{
+				algorithm: "EC",
+				curveType: "secp521r1",
+				kmipKeyID: "1",
+			},
+			wantErr: true,
+		},
 		{
 			name: "negative testing - kmipKeyID is empty",
 			args: args{
@@ -165,6 +197,7 @@ func TestKmipManagerRegisterKey(t *testing.T) {
 
 			keyInfo := &kbs.KeyInformation{
 				Algorithm: tt.args.algorithm,
+				CurveType: tt.args.curveType,
 				KmipKeyID: tt.args.kmipKeyID,
 			}
 
@@ -185,9 +218,19 @@ func TestKmipManagerRegisterKey(t *testing.T) {
 
 func TestKmipManagerTransferKey(t *testing.T) {
 
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	type args struct {
 		algorithm string
 		kmipKeyID string
+		keyBytes  []byte
 	}
 	tests := []struct {
 		name    string
@@ -210,6 +253,24 @@ func TestKmipManagerTransferKey(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "get EC key",
+			args: args{
+				algorithm: "EC",
+				kmipKeyID: "3",
+				keyBytes:  ecDer,
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative testing - EC key is not in a supported format",
+			args: args{
+				algorithm: "EC",
+				kmipKeyID: "3",
+				keyBytes:  []byte(""),
+			},
+			wantErr: true,
+		},
 		{
 			name: "negative testing - algorithm not supported",
 			args: args{
@@ -228,13 +289,18 @@ func TestKmipManagerTransferKey(t *testing.T) {
 			}
 
 			mockClient := kmipclient.NewMockKmipClient()
-			mockClient.On("GetKey", mock.Anything).Return([]byte(""), nil)
+			mockClient.On("GetKey", mock.Anything).Return(tt.args.keyBytes, nil)
 			keyManager := &KmipManager{mockClient}
-			_, err := keyManager.TransferKey(keyAttributes)
+			keyBytes, err := keyManager.TransferKey(keyAttributes)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TransferKey() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.args.algorithm == "EC" && !tt.wantErr {
+				if _, err = x509.ParseECPrivateKey(keyBytes); err != nil {
+					t.Errorf("TransferKey() did not return a SEC 1 key: %v", err)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/pkcs11client"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+type Pkcs11Manager struct {
+	client pkcs11client.Pkcs11Client
+}
+
+func NewPkcs11Manager(c pkcs11client.Pkcs11Client) *Pkcs11Manager {
+	return &Pkcs11Manager{c}
+}
+
+func (pm *Pkcs11Manager) CreateKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:CreateKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:CreateKey() Leaving")
+
+	keyAttributes := &models.KeyAttributes{
+		Algorithm:        strings.ToUpper(request.KeyInformation.Algorithm),
+		KeyLength:        request.KeyInformation.KeyLength,
+		TransferPolicyId: request.TransferPolicyID,
+		Label:            request.Label,
+		Usage:            request.Usage,
+	}
+
+	var err error
+	switch keyAttributes.Algorithm {
+	case constants.CRYPTOALG_AES:
+		if err = validateAESKeyLength(keyAttributes.KeyLength); err != nil {
+			return nil, err
+		}
+		keyAttributes.Pkcs11KeyID, err = pm.client.CreateSymmetricKey(keyAttributes.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create AES key")
+		}
+	case constants.CRYPTOALG_RSA:
+		keyAttributes.Pkcs11KeyID, err = pm.client.CreateRSAKeyPair(keyAttributes.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create RSA key pair")
+		}
+	case constants.CRYPTOALG_EC:
+		keyAttributes.KeyLength = 0
+		keyAttributes.CurveType = request.KeyInformation.CurveType
+		keyAttributes.Pkcs11KeyID, err = pm.createECKeyPair(keyAttributes.CurveType)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
+	}
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new UUID")
+	}
+	keyAttributes.ID = newUuid
+	keyAttributes.CreatedAt = time.Now().UTC()
+
+	return keyAttributes, nil
+}
+
+func (pm *Pkcs11Manager) DeleteKey(attributes *models.KeyAttributes) error {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:DeleteKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:DeleteKey() Leaving")
+
+	if attributes.Pkcs11KeyID == "" {
+		return errors.New("key is not created with PKCS#11 key manager")
+	}
+
+	return pm.client.DeleteKey(attributes.Pkcs11KeyID)
+}
+
+func (pm *Pkcs11Manager) RegisterKey(request *kbs.KeyRequest) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:RegisterKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:RegisterKey() Leaving")
+
+	if request.KeyInformation.KeyString == "" {
+		return nil, errors.New("key_string cannot be empty for register operation in pkcs11 mode")
+	}
+
+	block, _ := pem.Decode([]byte(request.KeyInformation.KeyString))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM formatted key_string")
+	}
+
+	keyAttributes := &models.KeyAttributes{
+		Algorithm:        strings.ToUpper(request.KeyInformation.Algorithm),
+		TransferPolicyId: request.TransferPolicyID,
+		Label:            request.Label,
+		Usage:            request.Usage,
+	}
+
+	var err error
+	switch keyAttributes.Algorithm {
+	case constants.CRYPTOALG_AES:
+		keyAttributes.KeyLength = len(block.Bytes) * 8
+		if err = validateAESKeyLength(keyAttributes.KeyLength); err != nil {
+			return nil, err
+		}
+		keyAttributes.Pkcs11KeyID, err = pm.client.ImportSymmetricKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to register AES key")
+		}
+	case constants.CRYPTOALG_RSA:
+		rsaKey, err := parseRSAPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keyAttributes.KeyLength = rsaKey.N.BitLen()
+		keyAttributes.Pkcs11KeyID, err = pm.client.ImportRSAPrivateKey(rsaKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to register RSA key")
+		}
+	case constants.CRYPTOALG_EC:
+		ecKey, err := parseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keyAttributes.CurveType = request.KeyInformation.CurveType
+		if curve, ok := ellipticCurves[keyAttributes.CurveType]; !ok || curve != ecKey.Curve {
+			return nil, errors.New("curve of key_string does not match curve_type")
+		}
+		keyAttributes.Pkcs11KeyID, err = pm.client.ImportECPrivateKey(ecKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to register EC key")
+		}
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", request.KeyInformation.Algorithm)
+	}
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new UUID")
+	}
+	keyAttributes.ID = newUuid
+	keyAttributes.CreatedAt = time.Now().UTC()
+
+	return keyAttributes, nil
+}
+
+func (pm *Pkcs11Manager) TransferKey(attributes *models.KeyAttributes) ([]byte, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:TransferKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:TransferKey() Leaving")
+
+	if attributes.Pkcs11KeyID == "" {
+		return nil, errors.New("key is not created with PKCS#11 key manager")
+	}
+
+	switch attributes.Algorithm {
+	case constants.CRYPTOALG_AES, constants.CRYPTOALG_RSA:
+		return pm.client.GetKey(attributes.Pkcs11KeyID, attributes.Algorithm)
+	case constants.CRYPTOALG_EC:
+		keyBytes, err := pm.client.GetKey(attributes.Pkcs11KeyID, attributes.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return transferECPrivateKey(keyBytes)
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", attributes.Algorithm)
+	}
+}
+
+func (pm *Pkcs11Manager) RotateKey(attributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("keymanager/pkcs11_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/pkcs11_key_manager:RotateKey() Leaving")
+
+	rotatedAttributes := *attributes
+	var err error
+	switch attributes.Algorithm {
+	case constants.CRYPTOALG_AES:
+		rotatedAttributes.Pkcs11KeyID, err = pm.client.CreateSymmetricKey(attributes.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create AES key")
+		}
+	case constants.CRYPTOALG_RSA:
+		rotatedAttributes.Pkcs11KeyID, err = pm.client.CreateRSAKeyPair(attributes.KeyLength)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create RSA key pair")
+		}
+	case constants.CRYPTOALG_EC:
+		rotatedAttributes.Pkcs11KeyID, err = pm.createECKeyPair(attributes.CurveType)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("%s algorithm is not supported", attributes.Algorithm)
+	}
+
+	return &rotatedAttributes, nil
+}
+
+// createECKeyPair creates an EC key pair on the curve of the curve type and returns its PKCS#11 key ID
+func (pm *Pkcs11Manager) createECKeyPair(curveType string) (string, error) {
+	curve, ok := ellipticCurves[curveType]
+	if !ok {
+		return "", errors.Errorf("%s curve type is not supported", curveType)
+	}
+
+	pkcs11Id, err := pm.client.CreateECKeyPair(curve)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create EC key pair")
+	}
+	return pkcs11Id, nil
+}
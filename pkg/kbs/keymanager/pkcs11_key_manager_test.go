@@ -0,0 +1,212 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keymanager
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/pkcs11client"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPkcs11ManagerCreateKey(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		keyInfo  kbs.KeyInformation
+		funcName string
+		wantErr  bool
+	}{
+		{
+			name:     "create symmetric key",
+			keyInfo:  kbs.KeyInformation{Algorithm: "AES", KeyLength: 256},
+			funcName: "CreateSymmetricKey",
+		},
+		{
+			name:     "create asymmetric key",
+			keyInfo:  kbs.KeyInformation{Algorithm: "rsa", KeyLength: 3072},
+			funcName: "CreateRSAKeyPair",
+		},
+		{
+			name:     "negative test - invalid AES key length",
+			keyInfo:  kbs.KeyInformation{Algorithm: "AES", KeyLength: 3072},
+			funcName: "CreateSymmetricKey",
+			wantErr:  true,
+		},
+		{
+			name:     "create EC key",
+			keyInfo:  kbs.KeyInformation{Algorithm: "EC", CurveType: "secp384r1"},
+			funcName: "CreateECKeyPair",
+		},
+		{
+			name:     "negative test - curve type not supported",
+			keyInfo:  kbs.KeyInformation{Algorithm: "EC", CurveType: "secp224r1"},
+			funcName: "CreateECKeyPair",
+			wantErr:  true,
+		},
+		{
+			name:     "negative test - algorithm not supported",
+			keyInfo:  kbs.KeyInformation{Algorithm: "ECB", KeyLength: 2048},
+			funcName: "CreateRSAKeyPair",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyInfo := tt.keyInfo
+			mockClient := pkcs11client.NewMockPkcs11Client()
+			mockClient.On(tt.funcName, mock.Anything).Return("0a1b", nil)
+			keyManager := NewPkcs11Manager(mockClient)
+
+			keyAttributes, err := keyManager.CreateKey(&kbs.KeyRequest{KeyInformation: &keyInfo})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && keyAttributes.Pkcs11KeyID != "0a1b" {
+				t.Errorf("CreateKey() Pkcs11KeyID = %s, want 0a1b", keyAttributes.Pkcs11KeyID)
+			}
+		})
+	}
+}
+
+func TestPkcs11ManagerRegisterKey(t *testing.T) {
+
+	aesKeyString := string(pem.EncodeToMemory(&pem.Block{Type: "AES KEY", Bytes: make([]byte, 32)}))
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKeyString := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDer}))
+	tests := []struct {
+		name     string
+		keyInfo  kbs.KeyInformation
+		funcName string
+		wantErr  bool
+	}{
+		{
+			name:     "register symmetric key",
+			keyInfo:  kbs.KeyInformation{Algorithm: "AES", KeyLength: 256, KeyString: aesKeyString},
+			funcName: "ImportSymmetricKey",
+		},
+		{
+			name:     "register asymmetric key",
+			keyInfo:  kbs.KeyInformation{Algorithm: "RSA", KeyLength: 2048, KeyString: privateKey},
+			funcName: "ImportRSAPrivateKey",
+		},
+		{
+			name:     "register EC key",
+			keyInfo:  kbs.KeyInformation{Algorithm: "EC", CurveType: "prime256v1", KeyString: ecKeyString},
+			funcName: "ImportECPrivateKey",
+		},
+		{
+			name:     "negative test - EC key of another curve",
+			keyInfo:  kbs.KeyInformation{Algorithm: "EC", CurveType: "secp384r1", KeyString: ecKeyString},
+			funcName: "ImportECPrivateKey",
+			wantErr:  true,
+		},
+		{
+			name:     "negative test - key string not provided",
+			keyInfo:  kbs.KeyInformation{Algorithm: "AES", KeyLength: 256, KmipKeyID: "1"},
+			funcName: "ImportSymmetricKey",
+			wantErr:  true,
+		},
+		{
+			name:     "negative test - invalid RSA key string",
+			keyInfo:  kbs.KeyInformation{Algorithm: "RSA", KeyLength: 2048, KeyString: aesKeyString},
+			funcName: "ImportRSAPrivateKey",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyInfo := tt.keyInfo
+			mockClient := pkcs11client.NewMockPkcs11Client()
+			mockClient.On(tt.funcName, mock.Anything).Return("0a1b", nil)
+			keyManager := NewPkcs11Manager(mockClient)
+
+			_, err := keyManager.RegisterKey(&kbs.KeyRequest{KeyInformation: &keyInfo})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPkcs11ManagerDeleteAndTransferKey(t *testing.T) {
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		attributes models.KeyAttributes
+		keyBytes   []byte
+		clientErr  error
+		wantErr    bool
+	}{
+		{
+			name:       "transfer and delete key",
+			attributes: models.KeyAttributes{Algorithm: "AES", Pkcs11KeyID: "0a1b"},
+		},
+		{
+			name:       "transfer and delete EC key",
+			attributes: models.KeyAttributes{Algorithm: "EC", CurveType: "secp384r1", Pkcs11KeyID: "0a1b"},
+			keyBytes:   ecDer,
+		},
+		{
+			name:       "negative test - key created with KMIP key manager",
+			attributes: models.KeyAttributes{Algorithm: "AES", KmipKeyID: "1"},
+			wantErr:    true,
+		},
+		{
+			name:       "negative test - algorithm not supported",
+			attributes: models.KeyAttributes{Algorithm: "ECB", Pkcs11KeyID: "0a1b"},
+			wantErr:    true,
+		},
+		{
+			name:       "negative test - token error",
+			attributes: models.KeyAttributes{Algorithm: "RSA", Pkcs11KeyID: "0a1b"},
+			clientErr:  errors.New("CKR_DEVICE_ERROR"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := pkcs11client.NewMockPkcs11Client()
+			mockClient.On("GetKey", mock.Anything, mock.Anything).Return(tt.keyBytes, tt.clientErr)
+			mockClient.On("DeleteKey", mock.Anything).Return(tt.clientErr)
+			keyManager := NewPkcs11Manager(mockClient)
+
+			_, err := keyManager.TransferKey(&tt.attributes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransferKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.attributes.Algorithm == "ECB" {
+				return
+			}
+			err = keyManager.DeleteKey(&tt.attributes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
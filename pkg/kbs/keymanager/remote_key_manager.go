@@ -7,6 +7,7 @@ package keymanager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
@@ -37,6 +38,7 @@ func (rm *RemoteManager) CreateKey(request *kbs.KeyRequest) (*kbs.KeyResponse, e
 		return nil, err
 	}
 
+	keyAttributes.UsagePolicy = request.UsagePolicy
 	keyAttributes.TransferLink = rm.getTransferLink(keyAttributes.ID)
 	storedKey, err := rm.store.Create(keyAttributes)
 	if err != nil {
@@ -67,6 +69,16 @@ func (rm *RemoteManager) DeleteKey(keyId uuid.UUID) error {
 		return err
 	}
 
+	// delete the key material of all the versions of the key, starting with the oldest
+	for _, previous := range keyAttributes.PreviousVersions {
+		versionAttributes, err := keyAttributes.AtVersion(previous.Version)
+		if err != nil {
+			return err
+		}
+		if err = rm.manager.DeleteKey(versionAttributes); err != nil {
+			return err
+		}
+	}
 	if err := rm.manager.DeleteKey(keyAttributes); err != nil {
 		return err
 	}
@@ -100,6 +112,7 @@ func (rm *RemoteManager) RegisterKey(request *kbs.KeyRequest) (*kbs.KeyResponse,
 		return nil, err
 	}
 
+	keyAttributes.UsagePolicy = request.UsagePolicy
 	keyAttributes.TransferLink = rm.getTransferLink(keyAttributes.ID)
 	storedKey, err := rm.store.Create(keyAttributes)
 	if err != nil {
@@ -113,14 +126,67 @@ func (rm *RemoteManager) TransferKey(keyId uuid.UUID) ([]byte, error) {
 	defaultLog.Trace("keymanager/remote_key_manager:TransferKey() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:TransferKey() Leaving")
 
+	return rm.TransferKeyVersion(keyId, 0)
+}
+
+// TransferKeyVersion returns the key material of the version of the key, or of its current version when version is 0
+func (rm *RemoteManager) TransferKeyVersion(keyId uuid.UUID, version int) ([]byte, error) {
+	defaultLog.Trace("keymanager/remote_key_manager:TransferKeyVersion() Entering")
+	defer defaultLog.Trace("keymanager/remote_key_manager:TransferKeyVersion() Leaving")
+
 	keyAttributes, err := rm.store.Retrieve(keyId)
 	if err != nil {
 		return nil, err
 	}
 
+	if version != 0 {
+		keyAttributes, err = keyAttributes.AtVersion(version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return rm.manager.TransferKey(keyAttributes)
 }
 
+// RotateKey creates a new version of the key material behind the key ID. The previous versions are kept so that data
+// encrypted with them can still be decrypted.
+func (rm *RemoteManager) RotateKey(keyId uuid.UUID) (*kbs.KeyResponse, error) {
+	defaultLog.Trace("keymanager/remote_key_manager:RotateKey() Entering")
+	defer defaultLog.Trace("keymanager/remote_key_manager:RotateKey() Leaving")
+
+	keyAttributes, err := rm.store.Retrieve(keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	rotatedAt := time.Now().UTC()
+	nextVersion := *keyAttributes
+	nextVersion.Version = keyAttributes.CurrentVersion() + 1
+	nextVersion.RotatedAt = &rotatedAt
+	nextVersion.PreviousVersions = append(append([]models.KeyVersion{}, keyAttributes.PreviousVersions...), models.KeyVersion{
+		Version:     keyAttributes.CurrentVersion(),
+		KmipKeyID:   keyAttributes.KmipKeyID,
+		Pkcs11KeyID: keyAttributes.Pkcs11KeyID,
+		CreatedAt:   keyAttributes.CurrentVersionCreatedAt(),
+	})
+
+	rotatedKey, err := rm.manager.RotateKey(&nextVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	storedKey, err := rm.store.Update(rotatedKey)
+	if err != nil {
+		if derr := rm.manager.DeleteKey(rotatedKey); derr != nil {
+			defaultLog.WithError(derr).Errorf("keymanager/remote_key_manager:RotateKey() Failed to delete version %d of key %s", rotatedKey.Version, keyId)
+		}
+		return nil, err
+	}
+
+	return storedKey.ToKeyResponse(), nil
+}
+
 func (rm *RemoteManager) getTransferLink(keyId uuid.UUID) string {
 	defaultLog.Trace("keymanager/remote_key_manager:getTransferLink() Entering")
 	defer defaultLog.Trace("keymanager/remote_key_manager:getTransferLink() Leaving")
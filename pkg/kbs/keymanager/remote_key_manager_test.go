@@ -513,3 +513,59 @@ func TestNewRemoteManager(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoteManagerRotateKey(t *testing.T) {
+
+	mockClient := kmipclient.NewMockKmipClient()
+	mockClient.On("CreateSymmetricKey", mock.Anything).Return("3", nil)
+	mockClient.On("GetKey", "1").Return([]byte("version 1"), nil)
+	mockClient.On("GetKey", "3").Return([]byte("version 2"), nil)
+	keyManager := NewKmipManager(mockClient)
+	rm := NewRemoteManager(mocks.NewFakeKeyStore(), keyManager, "https://localhost:9443/kbs/v1")
+
+	keyId := uuid.MustParse("ee37c360-7eae-4250-a677-6ee12adce8e2")
+	rotated, err := rm.RotateKey(keyId)
+	if err != nil {
+		t.Fatalf("RemoteManager.RotateKey() error = %v", err)
+	}
+	if rotated.Version != 2 || rotated.KeyInformation.KmipKeyID != "3" || rotated.RotatedAt == nil {
+		t.Errorf("RemoteManager.RotateKey() = version %d with kmip key %s, want version 2 with kmip key 3", rotated.Version, rotated.KeyInformation.KmipKeyID)
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "transfer current version by default",
+			want: "version 2",
+		},
+		{
+			name:    "transfer previous version",
+			version: 1,
+			want:    "version 1",
+		},
+		{
+			name:    "negative test - version does not exist",
+			version: 3,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rm.TransferKeyVersion(keyId, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RemoteManager.TransferKeyVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("RemoteManager.TransferKeyVersion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err = rm.RotateKey(uuid.MustParse("73755fda-c910-46be-821f-e8ddeab189e9")); err == nil {
+		t.Error("RemoteManager.RotateKey() should fail for a key that does not exist")
+	}
+}
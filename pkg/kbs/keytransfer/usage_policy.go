@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keytransfer
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	samlLib "github.com/intel-secl/intel-secl/v5/pkg/lib/saml"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+)
+
+// UsagePolicyDenial is returned when a host does not satisfy a clause of the usage policy of a key
+type UsagePolicyDenial struct {
+	// Clause identifies the clause of the usage policy, e.g. required_trust[TRUST_OS]
+	Clause string
+	Reason string
+}
+
+func (d *UsagePolicyDenial) Error() string {
+	return fmt.Sprintf("usage policy clause %s is not satisfied: %s", d.Clause, d.Reason)
+}
+
+// samlAttributes holds the attributes of a SAML report used by the usage policy clauses
+type samlAttributes struct {
+	attributes       map[string]string
+	assetTagDeployed bool
+	tags             map[string]string
+}
+
+func newSamlAttributes(samlReport *samlLib.Saml) *samlAttributes {
+	sa := &samlAttributes{
+		attributes: make(map[string]string),
+		tags:       make(map[string]string),
+	}
+	for _, as := range samlReport.Attribute {
+		sa.attributes[as.Name] = as.AttributeValue
+		if as.Name == "TRUST_ASSET_TAG" && as.AttributeValue == "true" {
+			sa.assetTagDeployed = true
+		} else if strings.HasPrefix(as.Name, "TAG_") {
+			sa.tags[strings.ToLower(strings.TrimPrefix(as.Name, "TAG_"))] = as.AttributeValue
+		}
+	}
+	return sa
+}
+
+// hasTag checks if the asset tag is deployed on the host
+func (sa *samlAttributes) hasTag(tag kbs.AssetTag) bool {
+	value, ok := sa.tags[strings.ToLower(tag.Key)]
+	return ok && strings.EqualFold(value, tag.Value)
+}
+
+// hasFeature checks if the hardware feature is enabled on the host, the feature name being case insensitive
+func (sa *samlAttributes) hasFeature(feature string) bool {
+	for name, value := range sa.attributes {
+		if strings.EqualFold(name, "FEATURE_"+feature) {
+			return value == "true"
+		}
+	}
+	return false
+}
+
+// GetUsagePolicy returns the usage policy of the key. Keys without usage policy have their usage parsed as a list of
+// tag:value asset tags which must all be deployed on the host.
+func GetUsagePolicy(key *kbs.KeyResponse) *kbs.KeyUsagePolicy {
+	if key.UsagePolicy != nil {
+		return key.UsagePolicy
+	}
+	if key.Usage == "" {
+		return nil
+	}
+
+	assetTags := &kbs.AssetTagPolicy{}
+	for _, usagePolicy := range strings.Split(key.Usage, ",") {
+		tagKeyValuePair := strings.SplitN(usagePolicy, ":", 2)
+		tag := kbs.AssetTag{Key: tagKeyValuePair[0]}
+		if len(tagKeyValuePair) == 2 {
+			tag.Value = tagKeyValuePair[1]
+		}
+		assetTags.AllOf = append(assetTags.AllOf, tag)
+	}
+	return &kbs.KeyUsagePolicy{AssetTags: assetTags}
+}
+
+// EvaluateUsagePolicy checks the SAML report and AIK certificate of a host against the usage policy of a key. It
+// returns a UsagePolicyDenial for the first clause which is not satisfied.
+func EvaluateUsagePolicy(policy *kbs.KeyUsagePolicy, samlReport *samlLib.Saml, aikCert *x509.Certificate, now time.Time) error {
+	defaultLog.Trace("keytransfer/usage_policy:EvaluateUsagePolicy() Entering")
+	defer defaultLog.Trace("keytransfer/usage_policy:EvaluateUsagePolicy() Leaving")
+
+	if policy == nil {
+		return nil
+	}
+	sa := newSamlAttributes(samlReport)
+
+	if policy.AssetTags != nil && (len(policy.AssetTags.AllOf) != 0 || len(policy.AssetTags.AnyOf) != 0) {
+		if !sa.assetTagDeployed {
+			return &UsagePolicyDenial{Clause: "asset_tags", Reason: "asset tags are not deployed on the host"}
+		}
+		for _, tag := range policy.AssetTags.AllOf {
+			if !sa.hasTag(tag) {
+				return &UsagePolicyDenial{
+					Clause: fmt.Sprintf("asset_tags.all_of[%s:%s]", tag.Key, tag.Value),
+					Reason: "asset tag is not deployed on the host",
+				}
+			}
+		}
+		if len(policy.AssetTags.AnyOf) != 0 {
+			var deployed bool
+			for _, tag := range policy.AssetTags.AnyOf {
+				if sa.hasTag(tag) {
+					deployed = true
+					break
+				}
+			}
+			if !deployed {
+				return &UsagePolicyDenial{Clause: "asset_tags.any_of", Reason: "none of the asset tags is deployed on the host"}
+			}
+		}
+	}
+
+	for _, trust := range policy.RequiredTrust {
+		if value := sa.attributes[trust]; value != "true" {
+			if value == "" {
+				value = "missing"
+			}
+			return &UsagePolicyDenial{
+				Clause: fmt.Sprintf("required_trust[%s]", trust),
+				Reason: fmt.Sprintf("trust attribute of the host is %s", value),
+			}
+		}
+	}
+
+	if policy.MaxSamlAgeSeconds > 0 {
+		issuedAt := samlReport.Subject.NotBefore
+		maxAge := time.Duration(policy.MaxSamlAgeSeconds) * time.Second
+		if issuedAt.IsZero() || now.Sub(issuedAt) > maxAge {
+			return &UsagePolicyDenial{
+				Clause: "max_saml_age_seconds",
+				Reason: fmt.Sprintf("SAML report issued at %s is older than %s", issuedAt.Format(time.RFC3339), maxAge),
+			}
+		}
+	}
+
+	if len(policy.AllowedAikIssuers) != 0 {
+		var allowed bool
+		for _, issuer := range policy.AllowedAikIssuers {
+			if aikCert.Issuer.CommonName == issuer {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return &UsagePolicyDenial{
+				Clause: "allowed_aik_issuers",
+				Reason: fmt.Sprintf("AIK certificate is issued by %s", aikCert.Issuer.CommonName),
+			}
+		}
+	}
+
+	for _, feature := range policy.RequiredHardwareFeatures {
+		if !sa.hasFeature(feature) {
+			return &UsagePolicyDenial{
+				Clause: fmt.Sprintf("required_hardware_features[%s]", feature),
+				Reason: "hardware feature is not enabled on the host",
+			}
+		}
+	}
+
+	return nil
+}
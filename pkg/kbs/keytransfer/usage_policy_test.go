@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package keytransfer
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	samlLib "github.com/intel-secl/intel-secl/v5/pkg/lib/saml"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+)
+
+func TestEvaluateUsagePolicy(t *testing.T) {
+
+	now := time.Now()
+	samlReport := &samlLib.Saml{
+		Subject: samlLib.Subject{NotBefore: now.Add(-10 * time.Minute)},
+		Attribute: []samlLib.Attribute{
+			{Name: "TRUST_OVERALL", AttributeValue: "true"},
+			{Name: "TRUST_PLATFORM", AttributeValue: "true"},
+			{Name: "TRUST_OS", AttributeValue: "false"},
+			{Name: "TRUST_SOFTWARE", AttributeValue: "NA"},
+			{Name: "TRUST_ASSET_TAG", AttributeValue: "true"},
+			{Name: "TAG_Country", AttributeValue: "US"},
+			{Name: "TAG_State", AttributeValue: "CA"},
+			{Name: "FEATURE_TXT", AttributeValue: "true"},
+			{Name: "FEATURE_SecureBootEnabled", AttributeValue: "false"},
+		},
+	}
+	aikCert := &x509.Certificate{Issuer: pkix.Name{CommonName: "HVS Privacy Certificate"}}
+
+	tests := []struct {
+		name       string
+		policy     *kbs.KeyUsagePolicy
+		wantClause string
+	}{
+		{
+			name: "no usage policy",
+		},
+		{
+			name: "all clauses satisfied",
+			policy: &kbs.KeyUsagePolicy{
+				AssetTags: &kbs.AssetTagPolicy{
+					AllOf: []kbs.AssetTag{{Key: "country", Value: "us"}},
+					AnyOf: []kbs.AssetTag{{Key: "State", Value: "NY"}, {Key: "State", Value: "CA"}},
+				},
+				RequiredTrust:            []string{"TRUST_OVERALL", "TRUST_PLATFORM"},
+				MaxSamlAgeSeconds:        3600,
+				AllowedAikIssuers:        []string{"HVS Privacy Certificate"},
+				RequiredHardwareFeatures: []string{"txt"},
+			},
+		},
+		{
+			name:       "negative test - all_of asset tag not deployed",
+			policy:     &kbs.KeyUsagePolicy{AssetTags: &kbs.AssetTagPolicy{AllOf: []kbs.AssetTag{{Key: "Country", Value: "US"}, {Key: "State", Value: "NY"}}}},
+			wantClause: "asset_tags.all_of[State:NY]",
+		},
+		{
+			name:       "negative test - none of any_of asset tags deployed",
+			policy:     &kbs.KeyUsagePolicy{AssetTags: &kbs.AssetTagPolicy{AnyOf: []kbs.AssetTag{{Key: "State", Value: "NY"}, {Key: "City", Value: "SF"}}}},
+			wantClause: "asset_tags.any_of",
+		},
+		{
+			name:       "negative test - required trust is false",
+			policy:     &kbs.KeyUsagePolicy{RequiredTrust: []string{"TRUST_PLATFORM", "TRUST_OS"}},
+			wantClause: "required_trust[TRUST_OS]",
+		},
+		{
+			name:       "negative test - required trust is not applicable",
+			policy:     &kbs.KeyUsagePolicy{RequiredTrust: []string{"TRUST_SOFTWARE"}},
+			wantClause: "required_trust[TRUST_SOFTWARE]",
+		},
+		{
+			name:       "negative test - SAML report too old",
+			policy:     &kbs.KeyUsagePolicy{MaxSamlAgeSeconds: 300},
+			wantClause: "max_saml_age_seconds",
+		},
+		{
+			name:       "negative test - AIK issuer not allowed",
+			policy:     &kbs.KeyUsagePolicy{AllowedAikIssuers: []string{"Other Privacy CA"}},
+			wantClause: "allowed_aik_issuers",
+		},
+		{
+			name:       "negative test - hardware feature not enabled",
+			policy:     &kbs.KeyUsagePolicy{RequiredHardwareFeatures: []string{"TXT", "SecureBootEnabled"}},
+			wantClause: "required_hardware_features[SecureBootEnabled]",
+		},
+		{
+			name:       "negative test - hardware feature not reported",
+			policy:     &kbs.KeyUsagePolicy{RequiredHardwareFeatures: []string{"CBNT"}},
+			wantClause: "required_hardware_features[CBNT]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EvaluateUsagePolicy(tt.policy, samlReport, aikCert, now)
+			if tt.wantClause == "" {
+				if err != nil {
+					t.Errorf("EvaluateUsagePolicy() error = %v", err)
+				}
+				return
+			}
+			denial, ok := err.(*UsagePolicyDenial)
+			if !ok {
+				t.Fatalf("EvaluateUsagePolicy() error = %v, want a UsagePolicyDenial", err)
+			}
+			if denial.Clause != tt.wantClause {
+				t.Errorf("EvaluateUsagePolicy() denied clause %s, want %s", denial.Clause, tt.wantClause)
+			}
+		})
+	}
+}
+
+func TestEvaluateUsagePolicyAssetTagsNotDeployed(t *testing.T) {
+	samlReport := &samlLib.Saml{
+		Attribute: []samlLib.Attribute{
+			{Name: "TRUST_ASSET_TAG", AttributeValue: "NA"},
+		},
+	}
+	policy := &kbs.KeyUsagePolicy{AssetTags: &kbs.AssetTagPolicy{AnyOf: []kbs.AssetTag{{Key: "Country", Value: "US"}}}}
+
+	err := EvaluateUsagePolicy(policy, samlReport, &x509.Certificate{}, time.Now())
+	if denial, ok := err.(*UsagePolicyDenial); !ok || denial.Clause != "asset_tags" {
+		t.Errorf("EvaluateUsagePolicy() error = %v, want a denial of the asset_tags clause", err)
+	}
+}
+
+func TestGetUsagePolicy(t *testing.T) {
+	usagePolicy := &kbs.KeyUsagePolicy{RequiredTrust: []string{"TRUST_OS"}}
+
+	if policy := GetUsagePolicy(&kbs.KeyResponse{Usage: "Country:US", UsagePolicy: usagePolicy}); policy != usagePolicy {
+		t.Error("GetUsagePolicy() should return the usage policy of the key")
+	}
+	if policy := GetUsagePolicy(&kbs.KeyResponse{}); policy != nil {
+		t.Error("GetUsagePolicy() should return no usage policy for a key without usage")
+	}
+
+	policy := GetUsagePolicy(&kbs.KeyResponse{Usage: "Country:US,State:CA"})
+	if policy == nil || policy.AssetTags == nil || len(policy.AssetTags.AllOf) != 2 ||
+		policy.AssetTags.AllOf[1] != (kbs.AssetTag{Key: "State", Value: "CA"}) {
+		t.Errorf("GetUsagePolicy() = %+v, want the asset tags of the usage", policy)
+	}
+}
@@ -36,6 +36,13 @@ func NewKmipClient() KmipClient {
 
 var cipherSuites = []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_RSA_WITH_AES_128_CBC_SHA256}
 
+// recommendedCurves maps the supported curve types of EC keys to the kmip recommended curves
+var recommendedCurves = map[string]kmip14.RecommendedCurve{
+	"secp256r1":  kmip14.RecommendedCurveP_256,
+	"prime256v1": kmip14.RecommendedCurveP_256,
+	"secp384r1":  kmip14.RecommendedCurveP_384,
+}
+
 // InitializeClient initializes all the values required for establishing connection to kmip server
 func (kc *kmipClient) InitializeClient(version, serverIP, serverPort, hostname, username, password, clientKeyFilePath, clientCertificateFilePath, rootCertificateFilePath string) error {
 	defaultLog.Trace("kmipclient/kmipclient:InitializeClient() Entering")
@@ -179,44 +186,73 @@ func (kc *kmipClient) CreateSymmetricKey(length int) (string, error) {
 	return respPayload.UniqueIdentifier, nil
 }
 
-// CreateAsymmetricKeyPair creates a asymmetric key on kmip server
+// CreateAsymmetricKeyPair creates a asymmetric key on kmip server. The curve type is only used for EC key pairs.
 func (kc *kmipClient) CreateAsymmetricKeyPair(algorithm, curveType string, length int) (string, error) {
 	defaultLog.Trace("kmipclient/kmipclient:CreateAsymmetricKeyPair() Entering")
 	defer defaultLog.Trace("kmipclient/kmipclient:CreateAsymmetricKeyPair() Leaving")
 
+	cryptographicAlgorithm := kmip14.CryptographicAlgorithmRSA
+	privateKeyUsageMask := kmip14.CryptographicUsageMaskDecrypt
+	publicKeyUsageMask := kmip14.CryptographicUsageMaskEncrypt
+	var domainParameters *models.CryptographicDomainParameters
+	switch algorithm {
+	case constants.CRYPTOALG_RSA:
+	case constants.CRYPTOALG_EC:
+		curve, ok := recommendedCurves[curveType]
+		if !ok {
+			return "", errors.Errorf("%s curve type is not supported", curveType)
+		}
+		cryptographicAlgorithm = kmip14.CryptographicAlgorithmECDSA
+		privateKeyUsageMask = kmip14.CryptographicUsageMaskSign
+		publicKeyUsageMask = kmip14.CryptographicUsageMaskVerify
+		domainParameters = &models.CryptographicDomainParameters{
+			RecommendedCurve: curve,
+		}
+	default:
+		return "", errors.Errorf("%s algorithm is not supported", algorithm)
+	}
+
 	var createKeyPairRequestPayLoad interface{}
 	if kc.KMIPVersion == constants.KMIP_2_0 {
 		createKeyPairRequestPayLoad = models.CreateKeyPairRequestPayload{
 			CommonAttributes: models.CommonAttributes{
-				CryptographicAlgorithm: kmip14.CryptographicAlgorithmRSA,
-				CryptographicLength:    int32(length),
+				CryptographicAlgorithm:        cryptographicAlgorithm,
+				CryptographicLength:           int32(length),
+				CryptographicDomainParameters: domainParameters,
 			},
 			PrivateKeyAttributes: models.PrivateKeyAttributes{
-				CryptographicUsageMask: kmip14.CryptographicUsageMaskDecrypt,
+				CryptographicUsageMask: privateKeyUsageMask,
 			},
 			PublicKeyAttributes: models.PublicKeyAttributes{
-				CryptographicUsageMask: kmip14.CryptographicUsageMaskEncrypt,
+				CryptographicUsageMask: publicKeyUsageMask,
 			},
 		}
 	} else {
+		commonAttributes := []kmip.Attribute{
+			{
+				AttributeName:  "Cryptographic Algorithm",
+				AttributeValue: cryptographicAlgorithm,
+			},
+			{
+				AttributeName:  "Cryptographic Length",
+				AttributeValue: int32(length),
+			},
+		}
+		if domainParameters != nil {
+			commonAttributes = append(commonAttributes, kmip.Attribute{
+				AttributeName:  "Cryptographic Domain Parameters",
+				AttributeValue: *domainParameters,
+			})
+		}
 		createKeyPairRequestPayLoad = kmip.CreateKeyPairRequestPayload{
 			CommonTemplateAttribute: &kmip.TemplateAttribute{
-				Attribute: []kmip.Attribute{
-					{
-						AttributeName:  "Cryptographic Algorithm",
-						AttributeValue: kmip14.CryptographicAlgorithmRSA,
-					},
-					{
-						AttributeName:  "Cryptographic Length",
-						AttributeValue: int32(length),
-					},
-				},
+				Attribute: commonAttributes,
 			},
 			PrivateKeyTemplateAttribute: &kmip.TemplateAttribute{
 				Attribute: []kmip.Attribute{
 					{
 						AttributeName:  "Cryptographic Usage Mask",
-						AttributeValue: kmip14.CryptographicUsageMaskDecrypt,
+						AttributeValue: privateKeyUsageMask,
 					},
 				},
 			},
@@ -224,7 +260,7 @@ func (kc *kmipClient) CreateAsymmetricKeyPair(algorithm, curveType string, lengt
 				Attribute: []kmip.Attribute{
 					{
 						AttributeName:  "Cryptographic Usage Mask",
-						AttributeValue: kmip14.CryptographicUsageMaskEncrypt,
+						AttributeValue: publicKeyUsageMask,
 					},
 				},
 			},
@@ -245,7 +281,8 @@ func (kc *kmipClient) CreateAsymmetricKeyPair(algorithm, curveType string, lengt
 	return respPayload.PrivateKeyUniqueIdentifier, nil
 }
 
-// GetKey retrieves a key from kmip server
+// GetKey retrieves a key from kmip server. EC private keys are returned in the key format of the kmip server, either
+// SEC 1 (ECPrivateKey) or PKCS#8 DER.
 func (kc *kmipClient) GetKey(keyID, algorithm string) ([]byte, error) {
 	defaultLog.Trace("kmipclient/kmipclient:GetKey() Entering")
 	defer defaultLog.Trace("kmipclient/kmipclient:GetKey() Leaving")
@@ -275,7 +312,7 @@ func (kc *kmipClient) GetKey(keyID, algorithm string) ([]byte, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to decode symmetric keyblock")
 		}
-	case constants.CRYPTOALG_RSA:
+	case constants.CRYPTOALG_RSA, constants.CRYPTOALG_EC:
 		if respPayload.ObjectType == kmip14.ObjectTypePrivateKey {
 			err = decoder.DecodeValue(&keyValue, respPayload.PrivateKey.KeyBlock.KeyValue.(ttlv.TTLV))
 			if err != nil {
@@ -0,0 +1,23 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+)
+
+type Pkcs11Client interface {
+	InitializeClient(string, string, string) error
+	CreateSymmetricKey(int) (string, error)
+	CreateRSAKeyPair(int) (string, error)
+	CreateECKeyPair(elliptic.Curve) (string, error)
+	ImportSymmetricKey([]byte) (string, error)
+	ImportRSAPrivateKey(*rsa.PrivateKey) (string, error)
+	ImportECPrivateKey(*ecdsa.PrivateKey) (string, error)
+	DeleteKey(string) error
+	GetKey(string, string) ([]byte, error)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// alternativeIV is the high half of the integrity check register defined by RFC 5649
+const alternativeIV = 0xA65959A6
+
+// unwrapKeyWithPadding unwraps a key wrapped with the AES Key Wrap with Padding algorithm of RFC 5649, which is the
+// CKM_AES_KEY_WRAP_PAD mechanism used by the HSM to export the keys
+func unwrapKeyWithPadding(kek, wrapped []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher from key encryption key")
+	}
+	if len(wrapped) < 16 || len(wrapped)%8 != 0 {
+		return nil, errors.New("wrapped key length is invalid")
+	}
+
+	n := len(wrapped)/8 - 1
+	a := make([]byte, 8)
+	r := make([]byte, n*8)
+	b := make([]byte, 16)
+
+	if n == 1 {
+		block.Decrypt(b, wrapped)
+		copy(a, b[:8])
+		copy(r, b[8:])
+	} else {
+		copy(a, wrapped[:8])
+		copy(r, wrapped[8:])
+		for j := 5; j >= 0; j-- {
+			for i := n; i >= 1; i-- {
+				t := uint64(n*j + i)
+				binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(a)^t)
+				copy(b[:8], a)
+				copy(b[8:], r[(i-1)*8:i*8])
+				block.Decrypt(b, b)
+				copy(a, b[:8])
+				copy(r[(i-1)*8:i*8], b[8:])
+			}
+		}
+	}
+
+	if binary.BigEndian.Uint32(a[:4]) != alternativeIV {
+		return nil, errors.New("wrapped key integrity check failed")
+	}
+	keyLength := int(binary.BigEndian.Uint32(a[4:]))
+	if keyLength <= 8*(n-1) || keyLength > 8*n {
+		return nil, errors.New("wrapped key integrity check failed")
+	}
+	padding := r[keyLength:]
+	if subtle.ConstantTimeCompare(padding, make([]byte, len(padding))) != 1 {
+		return nil, errors.New("wrapped key integrity check failed")
+	}
+	return r[:keyLength], nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPkcs11Client is a mock of Pkcs11Client interface
+type MockPkcs11Client struct {
+	mock.Mock
+}
+
+// NewMockPkcs11Client creates a new mock instance
+func NewMockPkcs11Client() *MockPkcs11Client {
+	return &MockPkcs11Client{}
+}
+
+// InitializeClient mocks base method
+func (m *MockPkcs11Client) InitializeClient(modulePath, tokenLabel, pin string) error {
+	args := m.Called(modulePath, tokenLabel, pin)
+	return args.Error(0)
+}
+
+// CreateSymmetricKey mocks base method
+func (m *MockPkcs11Client) CreateSymmetricKey(length int) (string, error) {
+	args := m.Called(length)
+	return args.Get(0).(string), args.Error(1)
+}
+
+// CreateRSAKeyPair mocks base method
+func (m *MockPkcs11Client) CreateRSAKeyPair(length int) (string, error) {
+	args := m.Called(length)
+	return args.Get(0).(string), args.Error(1)
+}
+
+// CreateECKeyPair mocks base method
+func (m *MockPkcs11Client) CreateECKeyPair(curve elliptic.Curve) (string, error) {
+	args := m.Called(curve)
+	return args.Get(0).(string), args.Error(1)
+}
+
+// ImportSymmetricKey mocks base method
+func (m *MockPkcs11Client) ImportSymmetricKey(key []byte) (string, error) {
+	args := m.Called(key)
+	return args.Get(0).(string), args.Error(1)
+}
+
+// ImportRSAPrivateKey mocks base method
+func (m *MockPkcs11Client) ImportRSAPrivateKey(key *rsa.PrivateKey) (string, error) {
+	args := m.Called(key)
+	return args.Get(0).(string), args.Error(1)
+}
+
+// ImportECPrivateKey mocks base method
+func (m *MockPkcs11Client) ImportECPrivateKey(key *ecdsa.PrivateKey) (string, error) {
+	args := m.Called(key)
+	return args.Get(0).(string), args.Error(1)
+}
+
+// DeleteKey mocks base method
+func (m *MockPkcs11Client) DeleteKey(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// GetKey mocks base method
+func (m *MockPkcs11Client) GetKey(id string, algorithm string) ([]byte, error) {
+	args := m.Called(id, algorithm)
+	return args.Get(0).([]byte), args.Error(1)
+}
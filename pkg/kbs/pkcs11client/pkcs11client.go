@@ -0,0 +1,485 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+
+const (
+	// keyIdLength is the length in bytes of the CKA_ID given to the keys created in the token
+	keyIdLength = 16
+	// wrappingKeyLength is the length in bytes of the ephemeral AES key used to export the keys from the token
+	wrappingKeyLength = 32
+)
+
+// namedCurveOIDs are the object identifiers of the curves supported for EC keys, given in CKA_EC_PARAMS
+var namedCurveOIDs = map[elliptic.Curve]asn1.ObjectIdentifier{
+	elliptic.P256(): {1, 2, 840, 10045, 3, 1, 7},
+	elliptic.P384(): {1, 3, 132, 0, 34},
+	elliptic.P521(): {1, 3, 132, 0, 35},
+}
+
+// pkcs11Client manages the keys of a PKCS#11 token. The keys are sensitive in the token and are only exported
+// wrapped, with an ephemeral AES key created for each export.
+type pkcs11Client struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	// PKCS#11 sessions must not be used concurrently
+	mtx sync.Mutex
+}
+
+func NewPkcs11Client() Pkcs11Client {
+	return &pkcs11Client{}
+}
+
+// InitializeClient loads the PKCS#11 module, opens a session on the token with the label and logs in as user
+func (pc *pkcs11Client) InitializeClient(modulePath, tokenLabel, pin string) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:InitializeClient() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:InitializeClient() Leaving")
+
+	if modulePath == "" {
+		return errors.New("pkcs11client/pkcs11client:InitializeClient() PKCS#11 module path is not provided")
+	}
+	if tokenLabel == "" {
+		return errors.New("pkcs11client/pkcs11client:InitializeClient() PKCS#11 token label is not provided")
+	}
+
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return errors.Errorf("pkcs11client/pkcs11client:InitializeClient() Failed to load PKCS#11 module %s", modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to initialize PKCS#11 module")
+	}
+
+	slot, err := findTokenSlot(ctx, tokenLabel)
+	if err == nil {
+		pc.session, err = ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+		if err != nil {
+			err = errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to open session")
+		}
+	}
+	if err == nil {
+		err = ctx.Login(pc.session, pkcs11.CKU_USER, pin)
+		if err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+			err = errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to login to token")
+		} else {
+			err = nil
+		}
+	}
+	if err != nil {
+		if ferr := ctx.Finalize(); ferr != nil {
+			defaultLog.WithError(ferr).Warn("pkcs11client/pkcs11client:InitializeClient() Failed to finalize PKCS#11 module")
+		}
+		ctx.Destroy()
+		return err
+	}
+
+	pc.ctx = ctx
+	defaultLog.Infof("pkcs11client/pkcs11client:InitializeClient() Logged in to PKCS#11 token %s", tokenLabel)
+	return nil
+}
+
+// CreateSymmetricKey generates an AES key of the length in bits in the token and returns its ID
+func (pc *pkcs11Client) CreateSymmetricKey(length int) (string, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:CreateSymmetricKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:CreateSymmetricKey() Leaving")
+
+	id, err := newKeyId()
+	if err != nil {
+		return "", err
+	}
+
+	template := append(keyTemplate(id, pkcs11.CKO_SECRET_KEY, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, length/8),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+	)
+
+	pc.mtx.Lock()
+	defer pc.mtx.Unlock()
+	_, err = pc.ctx.GenerateKey(pc.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)}, template)
+	if err != nil {
+		return "", errors.Wrap(err, "pkcs11client/pkcs11client:CreateSymmetricKey() Failed to generate AES key")
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// CreateRSAKeyPair generates an RSA key pair of the length in bits in the token and returns its ID
+func (pc *pkcs11Client) CreateRSAKeyPair(length int) (string, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:CreateRSAKeyPair() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:CreateRSAKeyPair() Leaving")
+
+	id, err := newKeyId()
+	if err != nil {
+		return "", err
+	}
+
+	publicTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(id)),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, length),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, []byte{1, 0, 1}),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+	}
+	privateTemplate := append(keyTemplate(id, pkcs11.CKO_PRIVATE_KEY, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+	)
+
+	pc.mtx.Lock()
+	defer pc.mtx.Unlock()
+	_, _, err = pc.ctx.GenerateKeyPair(pc.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, nil)}, publicTemplate, privateTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "pkcs11client/pkcs11client:CreateRSAKeyPair() Failed to generate RSA key pair")
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// CreateECKeyPair generates an EC key pair on the curve in the token and returns its ID
+func (pc *pkcs11Client) CreateECKeyPair(curve elliptic.Curve) (string, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:CreateECKeyPair() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:CreateECKeyPair() Leaving")
+
+	ecParams, err := marshalECParams(curve)
+	if err != nil {
+		return "", err
+	}
+	id, err := newKeyId()
+	if err != nil {
+		return "", err
+	}
+
+	publicTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(id)),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+	}
+	privateTemplate := append(keyTemplate(id, pkcs11.CKO_PRIVATE_KEY, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+	)
+
+	pc.mtx.Lock()
+	defer pc.mtx.Unlock()
+	_, _, err = pc.ctx.GenerateKeyPair(pc.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)}, publicTemplate, privateTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "pkcs11client/pkcs11client:CreateECKeyPair() Failed to generate EC key pair")
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// ImportSymmetricKey creates an AES key with the value in the token and returns its ID
+func (pc *pkcs11Client) ImportSymmetricKey(key []byte) (string, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:ImportSymmetricKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:ImportSymmetricKey() Leaving")
+
+	id, err := newKeyId()
+	if err != nil {
+		return "", err
+	}
+
+	template := append(keyTemplate(id, pkcs11.CKO_SECRET_KEY, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, key),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+	)
+
+	pc.mtx.Lock()
+	defer pc.mtx.Unlock()
+	if _, err = pc.ctx.CreateObject(pc.session, template); err != nil {
+		return "", errors.Wrap(err, "pkcs11client/pkcs11client:ImportSymmetricKey() Failed to import AES key")
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// ImportRSAPrivateKey creates an RSA private key with the value in the token and returns its ID
+func (pc *pkcs11Client) ImportRSAPrivateKey(key *rsa.PrivateKey) (string, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:ImportRSAPrivateKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:ImportRSAPrivateKey() Leaving")
+
+	if len(key.Primes) != 2 {
+		return "", errors.New("pkcs11client/pkcs11client:ImportRSAPrivateKey() Multi-prime RSA keys are not supported")
+	}
+	id, err := newKeyId()
+	if err != nil {
+		return "", err
+	}
+
+	key.Precompute()
+	template := append(keyTemplate(id, pkcs11.CKO_PRIVATE_KEY, pkcs11.CKK_RSA),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS, key.N.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, big.NewInt(int64(key.E)).Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE_EXPONENT, key.D.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIME_1, key.Primes[0].Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIME_2, key.Primes[1].Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_EXPONENT_1, key.Precomputed.Dp.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_EXPONENT_2, key.Precomputed.Dq.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_COEFFICIENT, key.Precomputed.Qinv.Bytes()),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+	)
+
+	pc.mtx.Lock()
+	defer pc.mtx.Unlock()
+	if _, err = pc.ctx.CreateObject(pc.session, template); err != nil {
+		return "", errors.Wrap(err, "pkcs11client/pkcs11client:ImportRSAPrivateKey() Failed to import RSA private key")
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// ImportECPrivateKey creates an EC private key with the value in the token and returns its ID
+func (pc *pkcs11Client) ImportECPrivateKey(key *ecdsa.PrivateKey) (string, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:ImportECPrivateKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:ImportECPrivateKey() Leaving")
+
+	ecParams, err := marshalECParams(key.Curve)
+	if err != nil {
+		return "", err
+	}
+	id, err := newKeyId()
+	if err != nil {
+		return "", err
+	}
+
+	template := append(keyTemplate(id, pkcs11.CKO_PRIVATE_KEY, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, key.D.FillBytes(make([]byte, (key.Curve.Params().BitSize+7)/8))),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+	)
+
+	pc.mtx.Lock()
+	defer pc.mtx.Unlock()
+	if _, err = pc.ctx.CreateObject(pc.session, template); err != nil {
+		return "", errors.Wrap(err, "pkcs11client/pkcs11client:ImportECPrivateKey() Failed to import EC private key")
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// DeleteKey destroys all the objects of the token with the key ID
+func (pc *pkcs11Client) DeleteKey(keyID string) error {
+	defaultLog.Trace("pkcs11client/pkcs11client:DeleteKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:DeleteKey() Leaving")
+
+	id, err := hex.DecodeString(keyID)
+	if err != nil {
+		return errors.Wrap(err, "pkcs11client/pkcs11client:DeleteKey() Invalid key ID")
+	}
+
+	pc.mtx.Lock()
+	defer pc.mtx.Unlock()
+	objects, err := pc.findObjects([]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, id)})
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return errors.Errorf("pkcs11client/pkcs11client:DeleteKey() Key %s was not found in the token", keyID)
+	}
+	for _, object := range objects {
+		if err = pc.ctx.DestroyObject(pc.session, object); err != nil {
+			return errors.Wrap(err, "pkcs11client/pkcs11client:DeleteKey() Failed to destroy key")
+		}
+	}
+	return nil
+}
+
+// GetKey exports the key with the ID from the token, wrapped with an ephemeral AES key and unwrapped here
+func (pc *pkcs11Client) GetKey(keyID, algorithm string) ([]byte, error) {
+	defaultLog.Trace("pkcs11client/pkcs11client:GetKey() Entering")
+	defer defaultLog.Trace("pkcs11client/pkcs11client:GetKey() Leaving")
+
+	id, err := hex.DecodeString(keyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:GetKey() Invalid key ID")
+	}
+
+	var class uint
+	switch algorithm {
+	case constants.CRYPTOALG_AES:
+		class = pkcs11.CKO_SECRET_KEY
+	case constants.CRYPTOALG_RSA, constants.CRYPTOALG_EC:
+		class = pkcs11.CKO_PRIVATE_KEY
+	default:
+		return nil, errors.Errorf("pkcs11client/pkcs11client:GetKey() %s algorithm is not supported", algorithm)
+	}
+
+	wrappingKey := make([]byte, wrappingKeyLength)
+	if _, err = rand.Read(wrappingKey); err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:GetKey() Failed to generate wrapping key")
+	}
+
+	pc.mtx.Lock()
+	wrappedKey, err := pc.wrapKey(id, class, wrappingKey)
+	pc.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := unwrapKeyWithPadding(wrappingKey, wrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:GetKey() Failed to unwrap key")
+	}
+	if class == pkcs11.CKO_SECRET_KEY {
+		return key, nil
+	}
+
+	// private keys are wrapped as PKCS#8 PrivateKeyInfo
+	privateKey, err := x509.ParsePKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:GetKey() Failed to parse unwrapped private key")
+	}
+	switch privateKey := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == constants.CRYPTOALG_RSA {
+			return x509.MarshalPKCS1PrivateKey(privateKey), nil
+		}
+	case *ecdsa.PrivateKey:
+		if algorithm == constants.CRYPTOALG_EC {
+			key, err = x509.MarshalECPrivateKey(privateKey)
+			if err != nil {
+				return nil, errors.Wrap(err, "pkcs11client/pkcs11client:GetKey() Failed to marshal EC private key")
+			}
+			return key, nil
+		}
+	}
+	return nil, errors.Errorf("pkcs11client/pkcs11client:GetKey() Unwrapped private key is not an %s key", algorithm)
+}
+
+// wrapKey wraps the key with the ID and class with the wrapping key, which is created as a session object of the
+// token for the time of the export
+func (pc *pkcs11Client) wrapKey(id []byte, class uint, wrappingKey []byte) ([]byte, error) {
+	objects, err := pc.findObjects([]*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, errors.Errorf("pkcs11client/pkcs11client:GetKey() Key %s was not found in the token", hex.EncodeToString(id))
+	}
+
+	wrappingKeyHandle, err := pc.ctx.CreateObject(pc.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+		pkcs11.NewAttribute(pkcs11.CKA_WRAP, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, wrappingKey),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:GetKey() Failed to create wrapping key")
+	}
+	defer func() {
+		if derr := pc.ctx.DestroyObject(pc.session, wrappingKeyHandle); derr != nil {
+			defaultLog.WithError(derr).Error("pkcs11client/pkcs11client:GetKey() Failed to destroy wrapping key")
+		}
+	}()
+
+	wrappedKey, err := pc.ctx.WrapKey(pc.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_WRAP_PAD, nil)}, wrappingKeyHandle, objects[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:GetKey() Failed to wrap key")
+	}
+	return wrappedKey, nil
+}
+
+func (pc *pkcs11Client) findObjects(template []*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+	if err := pc.ctx.FindObjectsInit(pc.session, template); err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:findObjects() Failed to initialize object search")
+	}
+	defer func() {
+		if err := pc.ctx.FindObjectsFinal(pc.session); err != nil {
+			defaultLog.WithError(err).Error("pkcs11client/pkcs11client:findObjects() Failed to finalize object search")
+		}
+	}()
+
+	var objects []pkcs11.ObjectHandle
+	for {
+		found, _, err := pc.ctx.FindObjects(pc.session, 10)
+		if err != nil {
+			return nil, errors.Wrap(err, "pkcs11client/pkcs11client:findObjects() Failed to search objects")
+		}
+		if len(found) == 0 {
+			return objects, nil
+		}
+		objects = append(objects, found...)
+	}
+}
+
+func findTokenSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to list slots")
+	}
+	for _, slot := range slots {
+		tokenInfo, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, errors.Wrap(err, "pkcs11client/pkcs11client:InitializeClient() Failed to get token information")
+		}
+		if tokenInfo.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, errors.Errorf("pkcs11client/pkcs11client:InitializeClient() Token %s was not found", tokenLabel)
+}
+
+// keyTemplate returns the attributes shared by the keys stored in the token: they are persistent, private and can
+// only leave the token wrapped
+func keyTemplate(id []byte, class, keyType uint) []*pkcs11.Attribute {
+	return []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, keyType),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(id)),
+	}
+}
+
+// marshalECParams returns the DER encoded named curve of CKA_EC_PARAMS
+func marshalECParams(curve elliptic.Curve) ([]byte, error) {
+	oid, ok := namedCurveOIDs[curve]
+	if !ok {
+		return nil, errors.Errorf("pkcs11client/pkcs11client:marshalECParams() Curve %s is not supported", curve.Params().Name)
+	}
+	ecParams, err := asn1.Marshal(oid)
+	if err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:marshalECParams() Failed to marshal curve OID")
+	}
+	return ecParams, nil
+}
+
+func newKeyId() ([]byte, error) {
+	id := make([]byte, keyIdLength)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Wrap(err, "pkcs11client/pkcs11client:newKeyId() Failed to generate key ID")
+	}
+	return id, nil
+}
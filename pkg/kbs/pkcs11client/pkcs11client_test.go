@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package pkcs11client
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"os"
+	"testing"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Test vectors of RFC 5649 section 6
+func TestUnwrapKeyWithPadding(t *testing.T) {
+	kek := mustDecodeHex(t, "5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8")
+
+	tests := []struct {
+		name    string
+		wrapped string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "unwrap 20 octets key",
+			wrapped: "138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6a",
+			want:    "c37b7e6492584340bed12207808941155068f738",
+		},
+		{
+			name:    "unwrap 7 octets key",
+			wrapped: "afbeb0f07dfbf5419200f2ccb50bb24f",
+			want:    "466f7250617369",
+		},
+		{
+			name:    "negative test - tampered wrapped key",
+			wrapped: "138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6b",
+			wantErr: true,
+		},
+		{
+			name:    "negative test - invalid length",
+			wrapped: "afbeb0f07dfbf5419200f2ccb50bb2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unwrapKeyWithPadding(kek, mustDecodeHex(t, tt.wrapped))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unwrapKeyWithPadding() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && hex.EncodeToString(got) != tt.want {
+				t.Errorf("unwrapKeyWithPadding() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPkcs11ClientSoftHSM needs a SoftHSMv2 token set in KBS_TEST_PKCS11_MODULE, _TOKEN_LABEL and _PIN
+func TestPkcs11ClientSoftHSM(t *testing.T) {
+	modulePath := os.Getenv("KBS_TEST_PKCS11_MODULE")
+	if modulePath == "" {
+		t.Skip("KBS_TEST_PKCS11_MODULE is not set")
+	}
+
+	client := NewPkcs11Client()
+	if err := client.InitializeClient(modulePath, os.Getenv("KBS_TEST_PKCS11_TOKEN_LABEL"), os.Getenv("KBS_TEST_PKCS11_PIN")); err != nil {
+		t.Fatalf("InitializeClient() error = %v", err)
+	}
+
+	t.Run("create and export AES key", func(t *testing.T) {
+		id, err := client.CreateSymmetricKey(256)
+		if err != nil {
+			t.Fatalf("CreateSymmetricKey() error = %v", err)
+		}
+		defer client.DeleteKey(id)
+
+		key, err := client.GetKey(id, "AES")
+		if err != nil {
+			t.Fatalf("GetKey() error = %v", err)
+		}
+		if len(key) != 32 {
+			t.Errorf("GetKey() returned %d bytes, want 32", len(key))
+		}
+	})
+
+	t.Run("create and export RSA key", func(t *testing.T) {
+		id, err := client.CreateRSAKeyPair(2048)
+		if err != nil {
+			t.Fatalf("CreateRSAKeyPair() error = %v", err)
+		}
+		defer client.DeleteKey(id)
+
+		key, err := client.GetKey(id, "RSA")
+		if err != nil {
+			t.Fatalf("GetKey() error = %v", err)
+		}
+		if _, err = x509.ParsePKCS1PrivateKey(key); err != nil {
+			t.Errorf("GetKey() did not return a PKCS#1 key: %v", err)
+		}
+	})
+
+	t.Run("create and export EC key", func(t *testing.T) {
+		id, err := client.CreateECKeyPair(elliptic.P384())
+		if err != nil {
+			t.Fatalf("CreateECKeyPair() error = %v", err)
+		}
+		defer client.DeleteKey(id)
+
+		key, err := client.GetKey(id, "EC")
+		if err != nil {
+			t.Fatalf("GetKey() error = %v", err)
+		}
+		if _, err = x509.ParseECPrivateKey(key); err != nil {
+			t.Errorf("GetKey() did not return a SEC 1 key: %v", err)
+		}
+	})
+
+	t.Run("import and export keys", func(t *testing.T) {
+		aesKey := make([]byte, 16)
+		if _, err := rand.Read(aesKey); err != nil {
+			t.Fatal(err)
+		}
+		id, err := client.ImportSymmetricKey(aesKey)
+		if err != nil {
+			t.Fatalf("ImportSymmetricKey() error = %v", err)
+		}
+		key, err := client.GetKey(id, "AES")
+		if err != nil {
+			t.Fatalf("GetKey() error = %v", err)
+		}
+		if !bytes.Equal(key, aesKey) {
+			t.Error("GetKey() did not return the imported AES key")
+		}
+		if err = client.DeleteKey(id); err != nil {
+			t.Fatalf("DeleteKey() error = %v", err)
+		}
+		if _, err = client.GetKey(id, "AES"); err == nil {
+			t.Error("GetKey() should fail after the key is deleted")
+		}
+
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err = client.ImportRSAPrivateKey(rsaKey)
+		if err != nil {
+			t.Fatalf("ImportRSAPrivateKey() error = %v", err)
+		}
+		defer client.DeleteKey(id)
+		key, err = client.GetKey(id, "RSA")
+		if err != nil {
+			t.Fatalf("GetKey() error = %v", err)
+		}
+		if !bytes.Equal(key, x509.MarshalPKCS1PrivateKey(rsaKey)) {
+			t.Error("GetKey() did not return the imported RSA key")
+		}
+
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err = client.ImportECPrivateKey(ecKey)
+		if err != nil {
+			t.Fatalf("ImportECPrivateKey() error = %v", err)
+		}
+		defer client.DeleteKey(id)
+		key, err = client.GetKey(id, "EC")
+		if err != nil {
+			t.Fatalf("GetKey() error = %v", err)
+		}
+		exportedKey, err := x509.ParseECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("GetKey() did not return a SEC 1 key: %v", err)
+		}
+		if !exportedKey.Equal(ecKey) {
+			t.Error("GetKey() did not return the imported EC key")
+		}
+	})
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"crypto/sha512"
+	"database/sql"
+	"encoding/hex"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// CertificateStore holds the reference to the backend store for the certificates of one type, the SAML and the TPM
+// identity certificates sharing the same table
+type CertificateStore struct {
+	Store    *DataStore
+	CertType string
+}
+
+// NewCertificateStore is a constructor method that initializes a Certificate store for the certificates of the type
+func NewCertificateStore(store *DataStore, certType string) *CertificateStore {
+	return &CertificateStore{store, certType}
+}
+
+// Create creates a new certificate record in the backend store
+func (cs *CertificateStore) Create(certificate *kbs.Certificate) (*kbs.Certificate, error) {
+	defaultLog.Trace("postgres/certificate_store:Create() Entering")
+	defer defaultLog.Trace("postgres/certificate_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/certificate_store:Create() failed to create new UUID")
+	}
+	certificate.ID = newUuid
+
+	dbCert, err := cs.toDbCertificate(certificate)
+	if err != nil {
+		return nil, err
+	}
+	if err = cs.Store.Db.Create(dbCert).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/certificate_store:Create() failed to create certificate")
+	}
+	return fromDbCertificate(dbCert), nil
+}
+
+// Import inserts a certificate record keeping its ID. It returns false when a record with the same ID is already
+// present.
+func (cs *CertificateStore) Import(certificate *kbs.Certificate) (bool, error) {
+	defaultLog.Trace("postgres/certificate_store:Import() Entering")
+	defer defaultLog.Trace("postgres/certificate_store:Import() Leaving")
+
+	dbCert, err := cs.toDbCertificate(certificate)
+	if err != nil {
+		return false, err
+	}
+	db := cs.Store.Db.Set("gorm:insert_option", "ON CONFLICT (id) DO NOTHING").Create(dbCert)
+	// the insert of a record already present does not return the ID
+	if db.Error == sql.ErrNoRows {
+		return false, nil
+	}
+	if db.Error != nil {
+		return false, errors.Wrap(db.Error, "postgres/certificate_store:Import() failed to import certificate")
+	}
+	return db.RowsAffected == 1, nil
+}
+
+// Retrieve returns a single certificate record by unique ID
+func (cs *CertificateStore) Retrieve(id uuid.UUID) (*kbs.Certificate, error) {
+	defaultLog.Trace("postgres/certificate_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/certificate_store:Retrieve() Leaving")
+
+	dbCert := certificate{}
+	if err := cs.Store.Db.Where("id = ? AND type = ?", id, cs.CertType).First(&dbCert).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrap(err, "postgres/certificate_store:Retrieve() failed to retrieve certificate")
+	}
+	return fromDbCertificate(&dbCert), nil
+}
+
+// Delete deletes a certificate record by unique ID
+func (cs *CertificateStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("postgres/certificate_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/certificate_store:Delete() Leaving")
+
+	db := cs.Store.Db.Where("id = ? AND type = ?", id, cs.CertType).Delete(&certificate{})
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "postgres/certificate_store:Delete() failed to delete certificate")
+	}
+	if db.RowsAffected != 1 {
+		return errors.New(commErr.RecordNotFound)
+	}
+	return nil
+}
+
+// Search returns a list of certificate records per requested CertificateFilterCriteria
+func (cs *CertificateStore) Search(criteria *models.CertificateFilterCriteria) ([]kbs.Certificate, error) {
+	defaultLog.Trace("postgres/certificate_store:Search() Entering")
+	defer defaultLog.Trace("postgres/certificate_store:Search() Leaving")
+
+	var dbCerts []certificate
+	if err := buildCertificateSearchQuery(cs.Store.Db, cs.CertType, criteria).Find(&dbCerts).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/certificate_store:Search() failed to retrieve records from db")
+	}
+
+	var certificates = []kbs.Certificate{}
+	for i := range dbCerts {
+		certificates = append(certificates, *fromDbCertificate(&dbCerts[i]))
+	}
+	return certificates, nil
+}
+
+// buildCertificateSearchQuery helper function to build the query object for a certificate search. The issuer filters
+// are case insensitive as in the directory store.
+func buildCertificateSearchQuery(tx *gorm.DB, certType string, criteria *models.CertificateFilterCriteria) *gorm.DB {
+	defaultLog.Trace("postgres/certificate_store:buildCertificateSearchQuery() Entering")
+	defer defaultLog.Trace("postgres/certificate_store:buildCertificateSearchQuery() Leaving")
+
+	tx = tx.Model(&certificate{}).Where("type = ?", certType)
+	if criteria == nil {
+		return tx
+	}
+
+	if criteria.SubjectEqualTo != "" {
+		tx = tx.Where("subject = ?", criteria.SubjectEqualTo)
+	}
+	if criteria.SubjectContains != "" {
+		tx = tx.Where("subject like ?", "%"+criteria.SubjectContains+"%")
+	}
+	if criteria.IssuerEqualTo != "" {
+		tx = tx.Where("lower(issuer) = lower(?)", criteria.IssuerEqualTo)
+	}
+	if criteria.IssuerContains != "" {
+		tx = tx.Where("lower(issuer) like lower(?)", "%"+criteria.IssuerContains+"%")
+	}
+	if !criteria.ValidBefore.IsZero() {
+		tx = tx.Where("not_after < ?", criteria.ValidBefore)
+	}
+	if !criteria.ValidAfter.IsZero() {
+		tx = tx.Where("not_before > ?", criteria.ValidAfter)
+	}
+	if !criteria.ValidOn.IsZero() {
+		tx = tx.Where("not_before < ? AND not_after > ?", criteria.ValidOn, criteria.ValidOn)
+	}
+	return tx
+}
+
+// toDbCertificate decodes the PEM certificate to fill the filterable columns
+func (cs *CertificateStore) toDbCertificate(cert *kbs.Certificate) (*certificate, error) {
+	x509Cert, err := crypt.GetCertFromPem(cert.Certificate)
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/certificate_store:toDbCertificate() Error in decoding the certificate")
+	}
+
+	fingerprint := sha512.Sum384(x509Cert.Raw)
+	return &certificate{
+		ID:          cert.ID,
+		Type:        cs.CertType,
+		Certificate: cert.Certificate,
+		Subject:     x509Cert.Subject.CommonName,
+		Issuer:      x509Cert.Issuer.CommonName,
+		NotBefore:   x509Cert.NotBefore.UTC(),
+		NotAfter:    x509Cert.NotAfter.UTC(),
+		Digest:      hex.EncodeToString(fingerprint[:]),
+	}, nil
+}
+
+func fromDbCertificate(dbCert *certificate) *kbs.Certificate {
+	notBefore := dbCert.NotBefore.UTC()
+	notAfter := dbCert.NotAfter.UTC()
+	return &kbs.Certificate{
+		ID:          dbCert.ID,
+		Certificate: dbCert.Certificate,
+		Subject:     dbCert.Subject,
+		Issuer:      dbCert.Issuer,
+		NotBefore:   &notBefore,
+		NotAfter:    &notAfter,
+		Revoked:     false,
+		Digest:      dbCert.Digest,
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+const samlCertPath = "../controllers/resources/saml/saml_cert.pem"
+
+var certificateColumns = []string{"id", "type", "certificate", "subject", "issuer", "not_before", "not_after", "digest"}
+
+func TestCertificateStoreCreate(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	certPem, err := ioutil.ReadFile(samlCertPath)
+	assert.NoError(t, err)
+	cert, err := crypt.GetCertFromPem(certPem)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "certificate"`)).
+		WithArgs(sqlmock.AnyArg(), "saml", certPem, cert.Subject.CommonName, cert.Issuer.CommonName,
+			cert.NotBefore.UTC(), cert.NotAfter.UTC(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	created, err := NewCertificateStore(dataStore, "saml").Create(&kbs.Certificate{Certificate: certPem})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.ID)
+	assert.Equal(t, cert.Subject.CommonName, created.Subject)
+	assert.Len(t, created.Digest, 96)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCertificateStoreCreateInvalidCertificate(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	_, err = NewCertificateStore(dataStore, "saml").Create(&kbs.Certificate{Certificate: []byte("invalid")})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCertificateStoreRetrieve(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	id := uuid.New()
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(time.Hour)
+
+	// the certificates of another type are not visible to the store
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "certificate"  WHERE (id = $1 AND type = $2)`)).
+		WithArgs(id, "tpm-identity").
+		WillReturnRows(sqlmock.NewRows(certificateColumns).
+			AddRow(id, "tpm-identity", []byte("pem"), "subject", "issuer", notBefore, notAfter, "digest"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "certificate"  WHERE (id = $1 AND type = $2)`)).
+		WithArgs(id, "saml").
+		WillReturnRows(sqlmock.NewRows(certificateColumns))
+
+	certificate, err := NewCertificateStore(dataStore, "tpm-identity").Retrieve(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "subject", certificate.Subject)
+	assert.Equal(t, notAfter.UTC(), *certificate.NotAfter)
+
+	_, err = NewCertificateStore(dataStore, "saml").Retrieve(id)
+	assert.EqualError(t, err, commErr.RecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCertificateStoreDelete(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "certificate"  WHERE (id = $1 AND type = $2)`)).
+		WithArgs(id, "saml").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "certificate"  WHERE (id = $1 AND type = $2)`)).
+		WithArgs(id, "saml").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	certStore := NewCertificateStore(dataStore, "saml")
+	assert.NoError(t, certStore.Delete(id))
+	assert.EqualError(t, certStore.Delete(id), commErr.RecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCertificateStoreSearch(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	validOn := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "certificate"  WHERE (type = $1) AND (subject like $2) AND `+
+		`(lower(issuer) = lower($3)) AND (not_before < $4 AND not_after > $5)`)).
+		WithArgs("saml", "%HVS%", "CMSCA", validOn, validOn).
+		WillReturnRows(sqlmock.NewRows(certificateColumns).
+			AddRow(uuid.New(), "saml", []byte("pem"), "HVS SAML", "cmsca", validOn, validOn, "digest"))
+
+	certificates, err := NewCertificateStore(dataStore, "saml").Search(&models.CertificateFilterCriteria{
+		SubjectContains: "HVS",
+		IssuerEqualTo:   "CMSCA",
+		ValidOn:         validOn,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 1)
+	assert.Equal(t, "HVS SAML", certificates[0].Subject)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
+	"github.com/pkg/errors"
+)
+
+// InitDatabase connects to the database configured for the postgres metadata store and migrates its tables
+func InitDatabase(cfg *commConfig.DBConfig) (*DataStore, error) {
+	defaultLog.Trace("postgres/database:InitDatabase() Entering")
+	defer defaultLog.Trace("postgres/database:InitDatabase() Leaving")
+
+	dataStore, err := NewDataStore(NewDatabaseConfig(constants.DBTypePostgres, cfg))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error instantiating Database")
+	}
+	defaultLog.Info("Migrating Database")
+	if err = dataStore.Migrate(); err != nil {
+		dataStore.Close()
+		return nil, err
+	}
+
+	return dataStore, nil
+}
+
+func NewDataStore(config *Config) (*DataStore, error) {
+	if config.Vendor == constants.DBTypePostgres {
+		return New(config)
+	}
+	return nil, errors.Errorf("Unsupported database vendor")
+}
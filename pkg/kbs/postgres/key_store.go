@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// KeyStore holds the reference to the backend store for the key attributes
+type KeyStore struct {
+	Store *DataStore
+}
+
+// NewKeyStore is a constructor method that initializes a Key store
+func NewKeyStore(store *DataStore) *KeyStore {
+	return &KeyStore{store}
+}
+
+// Create creates a new key record in the backend store, the key ID being assigned by the key manager
+func (ks *KeyStore) Create(keyAttributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("postgres/key_store:Create() Entering")
+	defer defaultLog.Trace("postgres/key_store:Create() Leaving")
+
+	if err := ks.Store.Db.Create(toDbKey(keyAttributes)).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/key_store:Create() failed to create key")
+	}
+	return keyAttributes, nil
+}
+
+// Import inserts a key record as it is. It returns false when a record with the same ID is already present.
+func (ks *KeyStore) Import(keyAttributes *models.KeyAttributes) (bool, error) {
+	defaultLog.Trace("postgres/key_store:Import() Entering")
+	defer defaultLog.Trace("postgres/key_store:Import() Leaving")
+
+	db := ks.Store.Db.Set("gorm:insert_option", "ON CONFLICT (id) DO NOTHING").Create(toDbKey(keyAttributes))
+	// the insert of a record already present does not return the ID
+	if db.Error == sql.ErrNoRows {
+		return false, nil
+	}
+	if db.Error != nil {
+		return false, errors.Wrap(db.Error, "postgres/key_store:Import() failed to import key")
+	}
+	return db.RowsAffected == 1, nil
+}
+
+// Retrieve returns a single key record by unique ID
+func (ks *KeyStore) Retrieve(id uuid.UUID) (*models.KeyAttributes, error) {
+	defaultLog.Trace("postgres/key_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/key_store:Retrieve() Leaving")
+
+	dbKey := key{}
+	if err := ks.Store.Db.Where("id = ?", id).First(&dbKey).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrap(err, "postgres/key_store:Retrieve() failed to retrieve key")
+	}
+
+	keyAttributes := models.KeyAttributes(dbKey.Attributes)
+	return &keyAttributes, nil
+}
+
+// Update updates the attributes of an existing key record
+func (ks *KeyStore) Update(keyAttributes *models.KeyAttributes) (*models.KeyAttributes, error) {
+	defaultLog.Trace("postgres/key_store:Update() Entering")
+	defer defaultLog.Trace("postgres/key_store:Update() Leaving")
+
+	dbKey := toDbKey(keyAttributes)
+	db := ks.Store.Db.Model(&key{}).Where("id = ?", dbKey.ID).Updates(map[string]interface{}{
+		"algorithm":          dbKey.Algorithm,
+		"key_length":         dbKey.KeyLength,
+		"curve_type":         dbKey.CurveType,
+		"transfer_policy_id": dbKey.TransferPolicyId,
+		"created_at":         dbKey.CreatedAt,
+		"attributes":         dbKey.Attributes,
+	})
+	if db.Error != nil {
+		return nil, errors.Wrap(db.Error, "postgres/key_store:Update() failed to update key")
+	}
+	if db.RowsAffected != 1 {
+		return nil, errors.New(commErr.RecordNotFound)
+	}
+	return keyAttributes, nil
+}
+
+// Delete deletes a key record by unique ID
+func (ks *KeyStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("postgres/key_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/key_store:Delete() Leaving")
+
+	db := ks.Store.Db.Where("id = ?", id).Delete(&key{})
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "postgres/key_store:Delete() failed to delete key")
+	}
+	if db.RowsAffected != 1 {
+		return errors.New(commErr.RecordNotFound)
+	}
+	return nil
+}
+
+// Search returns a list of key records per requested KeyFilterCriteria
+func (ks *KeyStore) Search(criteria *models.KeyFilterCriteria) ([]models.KeyAttributes, error) {
+	defaultLog.Trace("postgres/key_store:Search() Entering")
+	defer defaultLog.Trace("postgres/key_store:Search() Leaving")
+
+	var dbKeys []key
+	if err := buildKeySearchQuery(ks.Store.Db, criteria).Find(&dbKeys).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/key_store:Search() failed to retrieve records from db")
+	}
+
+	var keys = []models.KeyAttributes{}
+	for _, dbKey := range dbKeys {
+		keys = append(keys, models.KeyAttributes(dbKey.Attributes))
+	}
+	return keys, nil
+}
+
+// buildKeySearchQuery helper function to build the query object for a key search.
+func buildKeySearchQuery(tx *gorm.DB, criteria *models.KeyFilterCriteria) *gorm.DB {
+	defaultLog.Trace("postgres/key_store:buildKeySearchQuery() Entering")
+	defer defaultLog.Trace("postgres/key_store:buildKeySearchQuery() Leaving")
+
+	tx = tx.Model(&key{}).Order("created_at")
+	if criteria == nil {
+		return tx
+	}
+
+	if criteria.Algorithm != "" {
+		tx = tx.Where("algorithm = ?", criteria.Algorithm)
+	}
+	if criteria.KeyLength != 0 {
+		tx = tx.Where("key_length = ?", criteria.KeyLength)
+	}
+	if criteria.CurveType != "" {
+		tx = tx.Where("curve_type = ?", criteria.CurveType)
+	}
+	if criteria.TransferPolicyId != uuid.Nil {
+		tx = tx.Where("transfer_policy_id = ?", criteria.TransferPolicyId)
+	}
+	return tx
+}
+
+func toDbKey(keyAttributes *models.KeyAttributes) *key {
+	return &key{
+		ID:               keyAttributes.ID,
+		Algorithm:        keyAttributes.Algorithm,
+		KeyLength:        keyAttributes.KeyLength,
+		CurveType:        keyAttributes.CurveType,
+		TransferPolicyId: keyAttributes.TransferPolicyId,
+		CreatedAt:        keyAttributes.CreatedAt,
+		Attributes:       PGKeyAttributes(*keyAttributes),
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/stretchr/testify/assert"
+)
+
+var keyColumns = []string{"id", "algorithm", "key_length", "curve_type", "transfer_policy_id", "created_at", "attributes"}
+
+func newKeyAttributes() *models.KeyAttributes {
+	return &models.KeyAttributes{
+		ID:               uuid.New(),
+		Algorithm:        "AES",
+		KeyLength:        256,
+		TransferPolicyId: uuid.New(),
+		CreatedAt:        time.Now().UTC(),
+		Label:            "label",
+	}
+}
+
+func keyRow(keyAttributes *models.KeyAttributes) []driver.Value {
+	attributes, _ := json.Marshal(keyAttributes)
+	return []driver.Value{keyAttributes.ID, keyAttributes.Algorithm, keyAttributes.KeyLength, keyAttributes.CurveType,
+		keyAttributes.TransferPolicyId, keyAttributes.CreatedAt, attributes}
+}
+
+func TestKeyStoreCreate(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	keyAttributes := newKeyAttributes()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "key"`)).
+		WithArgs(keyAttributes.ID, "AES", 256, "", keyAttributes.TransferPolicyId, keyAttributes.CreatedAt, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(keyAttributes.ID))
+	mock.ExpectCommit()
+
+	created, err := NewKeyStore(dataStore).Create(keyAttributes)
+	assert.NoError(t, err)
+	assert.Equal(t, keyAttributes.ID, created.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyStoreImport(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	keyAttributes := newKeyAttributes()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "key" .* ON CONFLICT \(id\) DO NOTHING`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(keyAttributes.ID))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	// a conflicting insert returns no row
+	mock.ExpectQuery(`INSERT INTO "key" .* ON CONFLICT \(id\) DO NOTHING`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	keyStore := NewKeyStore(dataStore)
+	inserted, err := keyStore.Import(keyAttributes)
+	assert.NoError(t, err)
+	assert.True(t, inserted)
+	inserted, err = keyStore.Import(keyAttributes)
+	assert.NoError(t, err)
+	assert.False(t, inserted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyStoreRetrieve(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	keyAttributes := newKeyAttributes()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key"  WHERE (id = $1)`)).
+		WithArgs(keyAttributes.ID).
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow(keyRow(keyAttributes)...))
+
+	retrieved, err := NewKeyStore(dataStore).Retrieve(keyAttributes.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, keyAttributes.ID, retrieved.ID)
+	assert.Equal(t, "label", retrieved.Label)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyStoreRetrieveNotFound(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key"`)).WillReturnRows(sqlmock.NewRows(keyColumns))
+
+	_, err = NewKeyStore(dataStore).Retrieve(uuid.New())
+	assert.EqualError(t, err, commErr.RecordNotFound)
+}
+
+func TestKeyStoreUpdateNotFound(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "key" SET`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	_, err = NewKeyStore(dataStore).Update(newKeyAttributes())
+	assert.EqualError(t, err, commErr.RecordNotFound)
+}
+
+func TestKeyStoreDelete(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "key"  WHERE (id = $1)`)).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "key"  WHERE (id = $1)`)).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	keyStore := NewKeyStore(dataStore)
+	assert.NoError(t, keyStore.Delete(id))
+	assert.EqualError(t, keyStore.Delete(id), commErr.RecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyStoreSearch(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	keyAttributes := newKeyAttributes()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key"  WHERE (algorithm = $1) AND (key_length = $2) ORDER BY created_at`)).
+		WithArgs("AES", 256).
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow(keyRow(keyAttributes)...))
+
+	keys, err := NewKeyStore(dataStore).Search(&models.KeyFilterCriteria{Algorithm: "AES", KeyLength: 256})
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, keyAttributes.ID, keys[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// KeyTransferEventStore holds the reference to the backend store for the key transfer events
+type KeyTransferEventStore struct {
+	Store *DataStore
+}
+
+// NewKeyTransferEventStore is a constructor method that initializes a KeyTransferEvent store
+func NewKeyTransferEventStore(store *DataStore) *KeyTransferEventStore {
+	return &KeyTransferEventStore{store}
+}
+
+// Create creates a new key transfer event record in the backend store
+func (ktes *KeyTransferEventStore) Create(event *kbs.KeyTransferEvent) (*kbs.KeyTransferEvent, error) {
+	defaultLog.Trace("postgres/key_transfer_event_store:Create() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_event_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/key_transfer_event_store:Create() failed to create new UUID")
+	}
+	event.ID = newUuid
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	if err = ktes.Store.Db.Create(toDbKeyTransferEvent(event)).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/key_transfer_event_store:Create() failed to create key transfer event")
+	}
+	return event, nil
+}
+
+// Import inserts a key transfer event record keeping its ID and creation time. It returns false when a record with the
+// same ID is already present.
+func (ktes *KeyTransferEventStore) Import(event *kbs.KeyTransferEvent) (bool, error) {
+	defaultLog.Trace("postgres/key_transfer_event_store:Import() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_event_store:Import() Leaving")
+
+	db := ktes.Store.Db.Set("gorm:insert_option", "ON CONFLICT (id) DO NOTHING").Create(toDbKeyTransferEvent(event))
+	// the insert of a record already present does not return the ID
+	if db.Error == sql.ErrNoRows {
+		return false, nil
+	}
+	if db.Error != nil {
+		return false, errors.Wrap(db.Error, "postgres/key_transfer_event_store:Import() failed to import key transfer event")
+	}
+	return db.RowsAffected == 1, nil
+}
+
+// Retrieve returns a single key transfer event record by unique ID
+func (ktes *KeyTransferEventStore) Retrieve(id uuid.UUID) (*kbs.KeyTransferEvent, error) {
+	defaultLog.Trace("postgres/key_transfer_event_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_event_store:Retrieve() Leaving")
+
+	dbEvent := keyTransferEvent{}
+	if err := ktes.Store.Db.Where("id = ?", id).First(&dbEvent).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrap(err, "postgres/key_transfer_event_store:Retrieve() failed to retrieve key transfer event")
+	}
+	return fromDbKeyTransferEvent(&dbEvent), nil
+}
+
+// Search returns a list of key transfer event records per requested KeyTransferEventFilterCriteria, oldest first
+func (ktes *KeyTransferEventStore) Search(criteria *models.KeyTransferEventFilterCriteria) ([]kbs.KeyTransferEvent, error) {
+	defaultLog.Trace("postgres/key_transfer_event_store:Search() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_event_store:Search() Leaving")
+
+	var dbEvents []keyTransferEvent
+	if err := buildKeyTransferEventSearchQuery(ktes.Store.Db, criteria).Find(&dbEvents).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/key_transfer_event_store:Search() failed to retrieve records from db")
+	}
+
+	var events = []kbs.KeyTransferEvent{}
+	for i := range dbEvents {
+		events = append(events, *fromDbKeyTransferEvent(&dbEvents[i]))
+	}
+	return events, nil
+}
+
+// buildKeyTransferEventSearchQuery helper function to build the query object for a key transfer event search.
+func buildKeyTransferEventSearchQuery(tx *gorm.DB, criteria *models.KeyTransferEventFilterCriteria) *gorm.DB {
+	defaultLog.Trace("postgres/key_transfer_event_store:buildKeyTransferEventSearchQuery() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_event_store:buildKeyTransferEventSearchQuery() Leaving")
+
+	tx = tx.Model(&keyTransferEvent{}).Order("created_at")
+	if criteria == nil {
+		return tx
+	}
+
+	if criteria.KeyId != uuid.Nil {
+		tx = tx.Where("key_id = ?", criteria.KeyId)
+	}
+	if criteria.Requester != "" {
+		tx = tx.Where("requester = ?", criteria.Requester)
+	}
+	if criteria.Decision != "" {
+		tx = tx.Where("decision = ?", criteria.Decision)
+	}
+	if !criteria.FromDate.IsZero() {
+		tx = tx.Where("created_at >= ?", criteria.FromDate)
+	}
+	if !criteria.ToDate.IsZero() {
+		tx = tx.Where("created_at <= ?", criteria.ToDate)
+	}
+	return tx
+}
+
+func toDbKeyTransferEvent(event *kbs.KeyTransferEvent) *keyTransferEvent {
+	return &keyTransferEvent{
+		ID:           event.ID,
+		KeyID:        event.KeyID,
+		Requester:    event.Requester,
+		TransferType: event.TransferType,
+		Decision:     event.Decision,
+		FailedClause: event.FailedClause,
+		Reason:       event.Reason,
+		CreatedAt:    event.CreatedAt,
+	}
+}
+
+func fromDbKeyTransferEvent(dbEvent *keyTransferEvent) *kbs.KeyTransferEvent {
+	return &kbs.KeyTransferEvent{
+		ID:           dbEvent.ID,
+		KeyID:        dbEvent.KeyID,
+		Requester:    dbEvent.Requester,
+		TransferType: dbEvent.TransferType,
+		Decision:     dbEvent.Decision,
+		FailedClause: dbEvent.FailedClause,
+		Reason:       dbEvent.Reason,
+		CreatedAt:    dbEvent.CreatedAt.UTC(),
+	}
+}
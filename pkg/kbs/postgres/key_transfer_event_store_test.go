@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+var keyTransferEventColumns = []string{"id", "key_id", "requester", "transfer_type", "decision", "failed_clause", "reason", "created_at"}
+
+func TestKeyTransferEventStoreCreate(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	keyId := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "key_transfer_event"`)).
+		WithArgs(sqlmock.AnyArg(), keyId, "host", "saml", "denied", "tpm", "reason", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	event, err := NewKeyTransferEventStore(dataStore).Create(&kbs.KeyTransferEvent{
+		KeyID:        keyId,
+		Requester:    "host",
+		TransferType: "saml",
+		Decision:     "denied",
+		FailedClause: "tpm",
+		Reason:       "reason",
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, event.ID)
+	assert.False(t, event.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyTransferEventStoreImport(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	event := &kbs.KeyTransferEvent{ID: uuid.New(), KeyID: uuid.New(), Decision: "granted", CreatedAt: time.Now().UTC()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "key_transfer_event" .* ON CONFLICT \(id\) DO NOTHING`).
+		WithArgs(event.ID, event.KeyID, "", "", "granted", "", "", event.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	inserted, err := NewKeyTransferEventStore(dataStore).Import(event)
+	assert.NoError(t, err)
+	assert.False(t, inserted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyTransferEventStoreRetrieveNotFound(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key_transfer_event"  WHERE (id = $1)`)).
+		WillReturnRows(sqlmock.NewRows(keyTransferEventColumns))
+
+	_, err = NewKeyTransferEventStore(dataStore).Retrieve(uuid.New())
+	assert.EqualError(t, err, commErr.RecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyTransferEventStoreSearch(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	keyId := uuid.New()
+	fromDate := time.Now().Add(-time.Hour)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key_transfer_event"  WHERE (key_id = $1) AND (decision = $2) AND (created_at >= $3) ORDER BY created_at`)).
+		WithArgs(keyId, "granted", fromDate).
+		WillReturnRows(sqlmock.NewRows(keyTransferEventColumns).
+			AddRow(uuid.New(), keyId, "host", "saml", "granted", "", "", createdAt))
+
+	events, err := NewKeyTransferEventStore(dataStore).Search(&models.KeyTransferEventFilterCriteria{
+		KeyId:    keyId,
+		Decision: "granted",
+		FromDate: fromDate,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "host", events[0].Requester)
+	assert.Equal(t, time.UTC, events[0].CreatedAt.Location())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
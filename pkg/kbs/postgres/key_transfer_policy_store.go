@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// KeyTransferPolicyStore holds the reference to the backend store for the key transfer policies
+type KeyTransferPolicyStore struct {
+	Store *DataStore
+}
+
+// NewKeyTransferPolicyStore is a constructor method that initializes a KeyTransferPolicy store
+func NewKeyTransferPolicyStore(store *DataStore) *KeyTransferPolicyStore {
+	return &KeyTransferPolicyStore{store}
+}
+
+// Create creates a new key transfer policy record in the backend store
+func (ktps *KeyTransferPolicyStore) Create(policy *kbs.KeyTransferPolicy) (*kbs.KeyTransferPolicy, error) {
+	defaultLog.Trace("postgres/key_transfer_policy_store:Create() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_policy_store:Create() Leaving")
+
+	newUuid, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Wrap(err, "postgres/key_transfer_policy_store:Create() failed to create new UUID")
+	}
+	policy.ID = newUuid
+	policy.CreatedAt = time.Now().UTC()
+	policy.UpdatedAt = policy.CreatedAt
+
+	if err = ktps.Store.Db.Create(toDbKeyTransferPolicy(policy)).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/key_transfer_policy_store:Create() failed to create key transfer policy")
+	}
+	return policy, nil
+}
+
+// Import inserts a key transfer policy record keeping its ID and timestamps. It returns false when a record with the
+// same ID is already present.
+func (ktps *KeyTransferPolicyStore) Import(policy *kbs.KeyTransferPolicy) (bool, error) {
+	defaultLog.Trace("postgres/key_transfer_policy_store:Import() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_policy_store:Import() Leaving")
+
+	db := ktps.Store.Db.Set("gorm:insert_option", "ON CONFLICT (id) DO NOTHING").Create(toDbKeyTransferPolicy(policy))
+	// the insert of a record already present does not return the ID
+	if db.Error == sql.ErrNoRows {
+		return false, nil
+	}
+	if db.Error != nil {
+		return false, errors.Wrap(db.Error, "postgres/key_transfer_policy_store:Import() failed to import key transfer policy")
+	}
+	return db.RowsAffected == 1, nil
+}
+
+// Retrieve returns a single key transfer policy record by unique ID
+func (ktps *KeyTransferPolicyStore) Retrieve(id uuid.UUID) (*kbs.KeyTransferPolicy, error) {
+	defaultLog.Trace("postgres/key_transfer_policy_store:Retrieve() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_policy_store:Retrieve() Leaving")
+
+	dbPolicy := keyTransferPolicy{}
+	if err := ktps.Store.Db.Where("id = ?", id).First(&dbPolicy).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.New(commErr.RecordNotFound)
+		}
+		return nil, errors.Wrap(err, "postgres/key_transfer_policy_store:Retrieve() failed to retrieve key transfer policy")
+	}
+
+	policy := kbs.KeyTransferPolicy(dbPolicy.Content)
+	return &policy, nil
+}
+
+// Update updates an existing key transfer policy record
+func (ktps *KeyTransferPolicyStore) Update(policy *kbs.KeyTransferPolicy) (*kbs.KeyTransferPolicy, error) {
+	defaultLog.Trace("postgres/key_transfer_policy_store:Update() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_policy_store:Update() Leaving")
+
+	policy.UpdatedAt = time.Now().UTC()
+	dbPolicy := toDbKeyTransferPolicy(policy)
+	db := ktps.Store.Db.Model(&keyTransferPolicy{}).Where("id = ?", dbPolicy.ID).Updates(map[string]interface{}{
+		"updated_at": dbPolicy.UpdatedAt,
+		"content":    dbPolicy.Content,
+	})
+	if db.Error != nil {
+		return nil, errors.Wrap(db.Error, "postgres/key_transfer_policy_store:Update() failed to update key transfer policy")
+	}
+	if db.RowsAffected != 1 {
+		return nil, errors.New(commErr.RecordNotFound)
+	}
+	return policy, nil
+}
+
+// Delete deletes a key transfer policy record by unique ID
+func (ktps *KeyTransferPolicyStore) Delete(id uuid.UUID) error {
+	defaultLog.Trace("postgres/key_transfer_policy_store:Delete() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_policy_store:Delete() Leaving")
+
+	db := ktps.Store.Db.Where("id = ?", id).Delete(&keyTransferPolicy{})
+	if db.Error != nil {
+		return errors.Wrap(db.Error, "postgres/key_transfer_policy_store:Delete() failed to delete key transfer policy")
+	}
+	if db.RowsAffected != 1 {
+		return errors.New(commErr.RecordNotFound)
+	}
+	return nil
+}
+
+// Search returns the list of key transfer policy records. The KeyTransferPolicyFilterCriteria defines no filter yet.
+func (ktps *KeyTransferPolicyStore) Search(criteria *models.KeyTransferPolicyFilterCriteria) ([]kbs.KeyTransferPolicy, error) {
+	defaultLog.Trace("postgres/key_transfer_policy_store:Search() Entering")
+	defer defaultLog.Trace("postgres/key_transfer_policy_store:Search() Leaving")
+
+	var dbPolicies []keyTransferPolicy
+	if err := ktps.Store.Db.Model(&keyTransferPolicy{}).Order("created_at").Find(&dbPolicies).Error; err != nil {
+		return nil, errors.Wrap(err, "postgres/key_transfer_policy_store:Search() failed to retrieve records from db")
+	}
+
+	var policies = []kbs.KeyTransferPolicy{}
+	for _, dbPolicy := range dbPolicies {
+		policies = append(policies, kbs.KeyTransferPolicy(dbPolicy.Content))
+	}
+	return policies, nil
+}
+
+func toDbKeyTransferPolicy(policy *kbs.KeyTransferPolicy) *keyTransferPolicy {
+	return &keyTransferPolicy{
+		ID:        policy.ID,
+		CreatedAt: policy.CreatedAt,
+		UpdatedAt: policy.UpdatedAt,
+		Content:   PGKeyTransferPolicy(*policy),
+	}
+}
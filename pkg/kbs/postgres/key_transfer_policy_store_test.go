@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aps"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+var keyTransferPolicyColumns = []string{"id", "created_at", "updated_at", "content"}
+
+func TestKeyTransferPolicyStoreCreate(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "key_transfer_policy" ("id","created_at","updated_at","content")`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectCommit()
+
+	policy, err := NewKeyTransferPolicyStore(dataStore).Create(&kbs.KeyTransferPolicy{
+		AttestationType: []aps.AttestationType{aps.SGX},
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, policy.ID)
+	assert.Equal(t, policy.CreatedAt, policy.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyTransferPolicyStoreRetrieve(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	policy := kbs.KeyTransferPolicy{
+		ID:              uuid.New(),
+		CreatedAt:       time.Now().UTC(),
+		AttestationType: []aps.AttestationType{aps.TDX},
+	}
+	content, _ := json.Marshal(policy)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key_transfer_policy"  WHERE (id = $1)`)).
+		WithArgs(policy.ID).
+		WillReturnRows(sqlmock.NewRows(keyTransferPolicyColumns).AddRow(policy.ID, policy.CreatedAt, policy.CreatedAt, content))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key_transfer_policy"  WHERE (id = $1)`)).
+		WillReturnRows(sqlmock.NewRows(keyTransferPolicyColumns))
+
+	policyStore := NewKeyTransferPolicyStore(dataStore)
+	retrieved, err := policyStore.Retrieve(policy.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.ID, retrieved.ID)
+	assert.Equal(t, policy.AttestationType, retrieved.AttestationType)
+
+	_, err = policyStore.Retrieve(uuid.New())
+	assert.EqualError(t, err, commErr.RecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyTransferPolicyStoreUpdate(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	policy := &kbs.KeyTransferPolicy{ID: uuid.New(), AttestationType: []aps.AttestationType{aps.SGX}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "key_transfer_policy" SET "content" = $1, "updated_at" = $2  WHERE (id = $3)`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), policy.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	updated, err := NewKeyTransferPolicyStore(dataStore).Update(policy)
+	assert.NoError(t, err)
+	assert.False(t, updated.UpdatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyTransferPolicyStoreDeleteNotFound(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "key_transfer_policy"  WHERE (id = $1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = NewKeyTransferPolicyStore(dataStore).Delete(uuid.New())
+	assert.EqualError(t, err, commErr.RecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyTransferPolicyStoreSearch(t *testing.T) {
+	dataStore, mock, err := NewSQLMockDataStore()
+	assert.NoError(t, err)
+	policy := kbs.KeyTransferPolicy{ID: uuid.New(), AttestationType: []aps.AttestationType{aps.SGX}}
+	content, _ := json.Marshal(policy)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key_transfer_policy"   ORDER BY created_at`)).
+		WillReturnRows(sqlmock.NewRows(keyTransferPolicyColumns).AddRow(policy.ID, time.Now(), time.Now(), content))
+
+	policies, err := NewKeyTransferPolicyStore(dataStore).Search(nil)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.Equal(t, policy.ID, policies[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
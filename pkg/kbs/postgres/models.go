@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// Define all struct types here
+type (
+	PGKeyAttributes     models.KeyAttributes
+	PGKeyTransferPolicy kbs.KeyTransferPolicy
+
+	// key holds the attributes of a key, the filterable attributes being duplicated in their own columns
+	key struct {
+		ID               uuid.UUID       `gorm:"primary_key;type:uuid"`
+		Algorithm        string          `gorm:"not null;index:idx_key_algorithm"`
+		KeyLength        int             `gorm:"column:key_length"`
+		CurveType        string          `gorm:"column:curve_type"`
+		TransferPolicyId uuid.UUID       `gorm:"type:uuid;not null;index:idx_key_transfer_policy_id"`
+		CreatedAt        time.Time       `gorm:"column:created_at;not null"`
+		Attributes       PGKeyAttributes `gorm:"column:attributes;not null" sql:"type:JSONB"`
+	}
+
+	keyTransferPolicy struct {
+		ID        uuid.UUID           `gorm:"primary_key;type:uuid"`
+		CreatedAt time.Time           `gorm:"column:created_at;not null"`
+		UpdatedAt time.Time           `gorm:"column:updated_at;not null"`
+		Content   PGKeyTransferPolicy `gorm:"column:content;not null" sql:"type:JSONB"`
+	}
+
+	keyTransferEvent struct {
+		ID           uuid.UUID `gorm:"primary_key;type:uuid"`
+		KeyID        uuid.UUID `gorm:"column:key_id;type:uuid;not null;index:idx_key_transfer_event_key_id"`
+		Requester    string    `gorm:"column:requester;not null"`
+		TransferType string    `gorm:"column:transfer_type;not null"`
+		Decision     string    `gorm:"column:decision;not null"`
+		FailedClause string    `gorm:"column:failed_clause"`
+		Reason       string    `gorm:"column:reason"`
+		CreatedAt    time.Time `gorm:"column:created_at;not null;index:idx_key_transfer_event_created_at"`
+	}
+
+	// certificate holds a SAML or TPM identity certificate, the decoded values being kept for the searches
+	certificate struct {
+		ID          uuid.UUID `gorm:"primary_key;type:uuid"`
+		Type        string    `gorm:"column:type;not null;index:idx_certificate_type"`
+		Certificate []byte    `gorm:"column:certificate;not null"`
+		Subject     string    `gorm:"column:subject;not null"`
+		Issuer      string    `gorm:"column:issuer;not null"`
+		NotBefore   time.Time `gorm:"column:not_before;not null"`
+		NotAfter    time.Time `gorm:"column:not_after;not null"`
+		Digest      string    `gorm:"column:digest;not null"`
+	}
+)
+
+func (ka PGKeyAttributes) Value() (driver.Value, error) {
+	return json.Marshal(ka)
+}
+
+func (ka *PGKeyAttributes) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGKeyAttributes_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &ka)
+}
+
+func (ktp PGKeyTransferPolicy) Value() (driver.Value, error) {
+	return json.Marshal(ktp)
+}
+
+func (ktp *PGKeyTransferPolicy) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("postgres/models:PGKeyTransferPolicy_Scan() - type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &ktp)
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
+	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	// Import driver for GORM
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+)
+
+var defaultLog = commLog.GetDefaultLogger()
+var secLog = commLog.GetSecurityLogger()
+
+type Config struct {
+	Vendor, Host, Dbname, User, Password, SslMode, SslCert string
+	Port, ConnRetryAttempts, ConnRetryTime                 int
+}
+
+func NewDatabaseConfig(vendor string, dbConfig *commConfig.DBConfig) *Config {
+	return &Config{
+		Vendor:            vendor,
+		Host:              dbConfig.Host,
+		Port:              dbConfig.Port,
+		User:              dbConfig.Username,
+		Password:          dbConfig.Password,
+		Dbname:            dbConfig.DBName,
+		SslMode:           dbConfig.SSLMode,
+		SslCert:           dbConfig.SSLCert,
+		ConnRetryAttempts: dbConfig.ConnectionRetryAttempts,
+		ConnRetryTime:     dbConfig.ConnectionRetryTime,
+	}
+}
+
+type DataStore struct {
+	Db *gorm.DB
+}
+
+// New returns a DataStore instance with the gorm.DB set with the postgres
+func New(cfg *Config) (*DataStore, error) {
+	defaultLog.Trace("postgres/postgres:New() Entering")
+	defer defaultLog.Trace("postgres/postgres:New() Leaving")
+
+	if cfg.Host == "" || cfg.Port == 0 || cfg.User == "" ||
+		cfg.Password == "" || cfg.Dbname == "" {
+		err := errors.Errorf("postgres/postgres:New() All fields must be set (%s)", spew.Sdump(cfg))
+		defaultLog.Error(err)
+		secLog.Warningf("%s: Failed to connect to db, missing configuration - %s", commLogMsg.BadConnection, err)
+		return nil, err
+	}
+
+	if cfg.Port > 65535 || cfg.Port <= 1024 {
+		return nil, errors.New("Invalid or reserved port")
+	}
+
+	cfg.SslMode = strings.TrimSpace(strings.ToLower(cfg.SslMode))
+	if cfg.SslMode != constants.SslModeAllow && cfg.SslMode != constants.SslModePrefer &&
+		cfg.SslMode != constants.SslModeVerifyCa && cfg.SslMode != constants.SslModeRequire {
+		cfg.SslMode = constants.SslModeVerifyFull
+	}
+
+	var sslCertParams string
+	if cfg.SslMode == constants.SslModeVerifyCa || cfg.SslMode == constants.SslModeVerifyFull {
+		sslCertParams = " sslrootcert=" + cfg.SslCert
+	}
+
+	var db *gorm.DB
+	var dbErr error
+	numAttempts := cfg.ConnRetryAttempts
+	if numAttempts <= 0 || numAttempts > 100 {
+		numAttempts = constants.DefaultDbConnRetryAttempts
+	}
+	for i := 0; i < numAttempts; i = i + 1 {
+		retryTime := time.Duration(cfg.ConnRetryTime)
+		db, dbErr = gorm.Open("postgres", fmt.Sprintf("host=%s port=%d user=%s dbname=%s password=%s sslmode=%s%s",
+			cfg.Host, cfg.Port, cfg.User, cfg.Dbname, cfg.Password, cfg.SslMode, sslCertParams))
+		if dbErr != nil {
+			defaultLog.WithError(dbErr).Infof("postgres/postgres:New() Failed to connect to DB, retrying attempt %d/%d", i, numAttempts)
+		} else {
+			break
+		}
+		if retryTime < 0 || retryTime > 100 {
+			retryTime = constants.DefaultDbConnRetryTime
+		}
+		time.Sleep(retryTime * time.Second)
+	}
+	if dbErr != nil {
+		defaultLog.WithError(dbErr).Infof("postgres/postgres:New() Failed to connect to db after %d attempts\n", numAttempts)
+		secLog.Warningf("%s: Failed to connect to db after %d attempts", commLogMsg.BadConnection, numAttempts)
+		return nil, errors.Wrapf(dbErr, "Failed to connect to db after %d attempts", numAttempts)
+	}
+	db.SingularTable(true)
+	return &DataStore{Db: db}, nil
+}
+
+// Migrate creates or updates the tables of the KBS metadata stores
+func (ds *DataStore) Migrate() error {
+	defaultLog.Trace("postgres/postgres:Migrate() Entering")
+	defer defaultLog.Trace("postgres/postgres:Migrate() Leaving")
+
+	if err := ds.Db.AutoMigrate(keyTransferPolicy{}, key{}, keyTransferEvent{}, certificate{}).Error; err != nil {
+		return errors.Wrap(err, "postgres/postgres:Migrate() Failed to migrate database tables")
+	}
+	return nil
+}
+
+func (ds *DataStore) Close() {
+	defaultLog.Trace("postgres/postgres:Close() Entering")
+	defer defaultLog.Trace("postgres/postgres:Close() Leaving")
+
+	if ds.Db != nil {
+		err := ds.Db.Close()
+		if err != nil {
+			defaultLog.WithError(err).Errorf("Error closing DB connection")
+		}
+	}
+}
@@ -152,13 +152,21 @@ func permissionsHandlerUsingTLSMAuth(eh middleware.EndpointHandler, aasAPIUrl st
 			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 		}
 
-		if _, err := request.TLS.PeerCertificates[0].Verify(verifyRootCAOpts); err != nil {
+		verifiedChains, err := request.TLS.PeerCertificates[0].Verify(verifyRootCAOpts)
+		if err != nil {
 			secLog.WithError(err).Error("router/handlers:permissionsHandlerUsingTLSMAuth() Error verifying certificate chain for TLS certificate. No " +
 				"valid certificate chain could be found")
 			return errors.New("Error verifying certificate chain for TLS certificate. No " +
 				"valid certificate chain could be found")
 		}
 
+		// the client certificates revoked in CMS are rejected
+		if err = clients.NewCrlChecker(caCerts).CheckChains(verifiedChains); err != nil {
+			secLog.WithError(err).Errorf("router/handlers:permissionsHandlerUsingTLSMAuth() %s TLS certificate is revoked or its revocation cannot be checked", commLogMsg.UnauthorizedAccess)
+			responseWriter.WriteHeader(http.StatusUnauthorized)
+			return &privilegeError{Message: "TLS certificate is revoked or its revocation cannot be checked", StatusCode: http.StatusUnauthorized}
+		}
+
 		secLog.Debug("router/handlers:permissionsHandlerUsingTLSMAuth() TLS certificate chain verification successful")
 
 		client, err := clients.HTTPClientWithCA(caCerts)
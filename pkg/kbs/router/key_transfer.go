@@ -6,9 +6,7 @@ package router
 
 import (
 	"github.com/gorilla/mux"
-	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/controllers"
-	"github.com/intel-secl/intel-secl/v5/pkg/kbs/directory"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
@@ -16,14 +14,12 @@ import (
 )
 
 //setKeyTransferRoutes registers routes to perform Key transfer operation
-func setKeyTransferRoutes(router *mux.Router, endpointUrl string, config domain.KeyTransferControllerConfig, keyManager keymanager.KeyManager) *mux.Router {
+func setKeyTransferRoutes(router *mux.Router, endpointUrl string, config domain.KeyTransferControllerConfig, keyManager keymanager.KeyManager, stores domain.MetadataStores) *mux.Router {
 	defaultLog.Trace("router/key_transfer:setKeyTransferRoutes() Entering")
 	defer defaultLog.Trace("router/key_transfer:setKeyTransferRoutes() Leaving")
 
-	keyStore := directory.NewKeyStore(constants.KeysDir)
-	policyStore := directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir)
-	remoteManager := keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
-	keyTransferController := controllers.NewKeyTransferController(remoteManager, policyStore, config)
+	remoteManager := keymanager.NewRemoteManager(stores.KeyStore, keyManager, endpointUrl)
+	keyTransferController := controllers.NewKeyTransferController(remoteManager, stores.KeyTransferPolicyStore, stores.KeyTransferEventStore, config)
 	keyIdExpr := "/keys/" + validation.IdReg
 
 	router.Handle(keyIdExpr+"/transfer",
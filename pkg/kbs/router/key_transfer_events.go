@@ -0,0 +1,34 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/controllers"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
+)
+
+// setKeyTransferEventRoutes registers routes to search the key transfer audit trail
+func setKeyTransferEventRoutes(router *mux.Router, eventStore domain.KeyTransferEventStore) *mux.Router {
+	defaultLog.Trace("router/key_transfer_events:setKeyTransferEventRoutes() Entering")
+	defer defaultLog.Trace("router/key_transfer_events:setKeyTransferEventRoutes() Leaving")
+
+	eventController := controllers.NewKeyTransferEventController(eventStore)
+	keyIdExpr := "/keys/" + validation.IdReg
+
+	router.Handle("/transfer-events",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(eventController.Search),
+			[]string{constants.KeyTransferEventSearch}))).Methods(http.MethodGet)
+
+	router.Handle(keyIdExpr+"/transfers",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(eventController.SearchByKey),
+			[]string{constants.KeyTransferEventSearch}))).Methods(http.MethodGet)
+
+	return router
+}
@@ -10,21 +10,19 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/controllers"
-	"github.com/intel-secl/intel-secl/v5/pkg/kbs/directory"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/validation"
 	"net/http"
 )
 
 //setKeyRoutes registers routes to perform Key CRUD operations
-func setKeyRoutes(router *mux.Router, endpointUrl string, defaultPolicyId uuid.UUID, keyManager keymanager.KeyManager) *mux.Router {
+func setKeyRoutes(router *mux.Router, endpointUrl string, defaultPolicyId uuid.UUID, keyManager keymanager.KeyManager, stores domain.MetadataStores) *mux.Router {
 	defaultLog.Trace("router/keys:setKeyRoutes() Entering")
 	defer defaultLog.Trace("router/keys:setKeyRoutes() Leaving")
 
-	keyStore := directory.NewKeyStore(constants.KeysDir)
-	policyStore := directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir)
-	remoteManager := keymanager.NewRemoteManager(keyStore, keyManager, endpointUrl)
-	keyController := controllers.NewKeyController(remoteManager, policyStore, defaultPolicyId)
+	remoteManager := keymanager.NewRemoteManager(stores.KeyStore, keyManager, endpointUrl)
+	keyController := controllers.NewKeyController(remoteManager, stores.KeyTransferPolicyStore, defaultPolicyId)
 	keyIdExpr := "/keys/" + validation.IdReg
 
 	router.Handle("/keys",
@@ -47,17 +45,19 @@ func setKeyRoutes(router *mux.Router, endpointUrl string, defaultPolicyId uuid.U
 		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.Transfer),
 			[]string{constants.KeyTransfer}))).Methods(http.MethodPost)
 
+	router.Handle(keyIdExpr+"/rotate",
+		ErrorHandler(permissionsHandler(JsonResponseHandler(keyController.Rotate),
+			[]string{constants.KeyRotate}))).Methods(http.MethodPost)
+
 	return router
 }
 
-func setSKCKeyTransferRoutes(router *mux.Router, kbsConfig *config.Configuration, keyManager keymanager.KeyManager) *mux.Router {
+func setSKCKeyTransferRoutes(router *mux.Router, kbsConfig *config.Configuration, keyManager keymanager.KeyManager, stores domain.MetadataStores) *mux.Router {
 	defaultLog.Trace("router/keys:setSKCKeyTransferRoutes() Entering")
 	defer defaultLog.Trace("router/keys:setSKCKeyTransferRoutes() Leaving")
 
-	keyStore := directory.NewKeyStore(constants.KeysDir)
-	policyStore := directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir)
-	remoteManager := keymanager.NewRemoteManager(keyStore, keyManager, kbsConfig.EndpointURL)
-	skcController := controllers.NewSKCController(remoteManager, policyStore, kbsConfig, constants.TrustedCaCertsDir)
+	remoteManager := keymanager.NewRemoteManager(stores.KeyStore, keyManager, kbsConfig.EndpointURL)
+	skcController := controllers.NewSKCController(remoteManager, stores.KeyTransferPolicyStore, stores.KeyTransferEventStore, kbsConfig, constants.TrustedCaCertsDir)
 	keyIdExpr := "/keys/" + validation.IdReg
 
 	router.Handle(keyIdExpr+"/dhsm2-transfer",
@@ -15,7 +15,9 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/keymanager"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	commMetrics "github.com/intel-secl/intel-secl/v5/pkg/lib/common/metrics"
 	cmw "github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
 	"github.com/pkg/errors"
 )
@@ -24,11 +26,12 @@ var defaultLog = log.GetDefaultLogger()
 var secLog = log.GetSecurityLogger()
 
 type Router struct {
-	aasClient *aas.Client
+	aasClient             *aas.Client
+	fetchTokenRevocations func(since time.Time) (*jwtauth.TokenRevocations, error)
 }
 
 // InitRoutes registers all routes for the application.
-func InitRoutes(cfg *config.Configuration, keyTransferConfig domain.KeyTransferControllerConfig, keyManager keymanager.KeyManager, aasClient *aas.Client) *mux.Router {
+func InitRoutes(cfg *config.Configuration, keyTransferConfig domain.KeyTransferControllerConfig, keyManager keymanager.KeyManager, stores domain.MetadataStores, aasClient *aas.Client) *mux.Router {
 	defaultLog.Trace("router/router:InitRoutes() Entering")
 	defer defaultLog.Trace("router/router:InitRoutes() Leaving")
 
@@ -37,33 +40,39 @@ func InitRoutes(cfg *config.Configuration, keyTransferConfig domain.KeyTransferC
 
 	// ISECL-8715 - Prevent potential open redirects to external URLs
 	router.SkipClean(true)
+	router = commMetrics.SetMetricsRoutes(router, cfg.Server)
 
 	// Define sub routes for path /kbs/v1
-	defineSubRoutes(router, "/"+strings.ToLower(constants.ServiceName)+constants.ApiVersion, cfg, keyTransferConfig, keyManager, aasClient)
+	defineSubRoutes(router, "/"+strings.ToLower(constants.ServiceName)+constants.ApiVersion, cfg, keyTransferConfig, keyManager, stores, aasClient)
 
 	return router
 }
 
-func defineSubRoutes(router *mux.Router, serviceApi string, cfg *config.Configuration, keyTransferConfig domain.KeyTransferControllerConfig, keyManager keymanager.KeyManager, aasClient *aas.Client) {
+func defineSubRoutes(router *mux.Router, serviceApi string, cfg *config.Configuration, keyTransferConfig domain.KeyTransferControllerConfig, keyManager keymanager.KeyManager, stores domain.MetadataStores, aasClient *aas.Client) {
 	defaultLog.Trace("router/router:defineSubRoutes() Entering")
 	defer defaultLog.Trace("router/router:defineSubRoutes() Leaving")
 
 	subRouter := router.PathPrefix(serviceApi).Subrouter()
 	subRouter = setVersionRoutes(subRouter)
-	subRouter = setKeyTransferRoutes(subRouter, cfg.EndpointURL, keyTransferConfig, keyManager)
-	subRouter = setSKCKeyTransferRoutes(subRouter, cfg, keyManager)
+	subRouter = setKeyTransferRoutes(subRouter, cfg.EndpointURL, keyTransferConfig, keyManager, stores)
+	subRouter = setSKCKeyTransferRoutes(subRouter, cfg, keyManager, stores)
 	subRouter = setSessionRoutes(subRouter, cfg)
 	subRouter = router.PathPrefix(serviceApi).Subrouter()
-	cfgRouter := Router{aasClient: aasClient}
+	// the token revocations are retrieved with the token of the KBS service user
+	fetchTokenRevocations := aas.NewTokenRevocationsFetcher(aasClient, aas.NewServiceUserTokenFetcher(cfg.AASBaseUrl,
+		aasClient.HTTPClient, cfg.KBS.Username, cfg.KBS.Password))
+	cfgRouter := Router{aasClient: aasClient, fetchTokenRevocations: fetchTokenRevocations}
 	var cacheTime, _ = time.ParseDuration(constants.JWTCertsCacheTime)
+	var revocationsRefreshTime, _ = time.ParseDuration(constants.TokenRevocationsRefreshTime)
 
-	subRouter.Use(cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
+	subRouter.Use(cmw.NewTokenAuthWithRevocationList(constants.TrustedJWTSigningCertsDir,
 		constants.TrustedCaCertsDir, cfgRouter.fnGetJwtCerts,
-		cacheTime))
-	subRouter = setKeyRoutes(subRouter, cfg.EndpointURL, keyTransferConfig.DefaultTransferPolicyId, keyManager)
-	subRouter = setKeyTransferPolicyRoutes(subRouter)
-	subRouter = setSamlCertRoutes(subRouter)
-	subRouter = setTpmIdentityCertRoutes(subRouter)
+		cacheTime, jwtauth.NewRevocationList(cfgRouter.fnGetTokenRevocations, revocationsRefreshTime)))
+	subRouter = setKeyRoutes(subRouter, cfg.EndpointURL, keyTransferConfig.DefaultTransferPolicyId, keyManager, stores)
+	subRouter = setKeyTransferPolicyRoutes(subRouter, stores)
+	subRouter = setKeyTransferEventRoutes(subRouter, stores.KeyTransferEventStore)
+	subRouter = setSamlCertRoutes(subRouter, stores.SamlCertificateStore)
+	subRouter = setTpmIdentityCertRoutes(subRouter, stores.TpmIdentityCertificateStore)
 }
 
 // Fetch JWT certificate from AAS
@@ -71,14 +80,28 @@ func (router *Router) fnGetJwtCerts() error {
 	defaultLog.Trace("router/router:fnGetJwtCerts() Entering")
 	defer defaultLog.Trace("router/router:fnGetJwtCerts() Leaving")
 
-	jwtCert, err := router.aasClient.GetJwtSigningCertificate()
+	jwtCerts, err := router.aasClient.GetJwtSigningCertificates()
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Error retrieving JWT signing certificate from AAS")
+		return errors.Wrap(err, "router/router:fnGetJwtCerts() Error retrieving JWT signing certificates from AAS")
 	}
 
-	err = crypt.SavePemCertWithShortSha1FileName(jwtCert, constants.TrustedJWTSigningCertsDir)
-	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+	for _, jwtCert := range jwtCerts {
+		err = crypt.SavePemCertWithShortSha1FileName(jwtCert, constants.TrustedJWTSigningCertsDir)
+		if err != nil {
+			return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+		}
 	}
 	return nil
 }
+
+// Fetch the tokens revoked in AAS
+func (router *Router) fnGetTokenRevocations(since time.Time) (*jwtauth.TokenRevocations, error) {
+	defaultLog.Trace("router/router:fnGetTokenRevocations() Entering")
+	defer defaultLog.Trace("router/router:fnGetTokenRevocations() Leaving")
+
+	revocations, err := router.fetchTokenRevocations(since)
+	if err != nil {
+		return nil, errors.Wrap(err, "router/router:fnGetTokenRevocations() Error retrieving token revocations from AAS")
+	}
+	return revocations, nil
+}
@@ -12,17 +12,23 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/intel-secl/intel-secl/v5/pkg/clients"
 	"github.com/intel-secl/intel-secl/v5/pkg/clients/aas"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/directory"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/keymanager"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/postgres"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/router"
 	"github.com/intel-secl/intel-secl/v5/pkg/kbs/utils"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/certrenewer"
+	commConstants "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
 	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
@@ -46,14 +52,23 @@ func (app *App) startServer() error {
 		return err
 	}
 
-	// Initialize KeyTransferControllerConfig
-	kcc, err := initKeyTransferControllerConfig()
+	// Initialize KeyManager
+	km, err := keymanager.NewKeyManager(configuration)
 	if err != nil {
 		return err
 	}
 
-	// Initialize KeyManager
-	km, err := keymanager.NewKeyManager(configuration)
+	// Initialize the stores of the KBS metadata
+	stores, dataStore, err := initMetadataStores(configuration)
+	if err != nil {
+		return err
+	}
+	if dataStore != nil {
+		defer dataStore.Close()
+	}
+
+	// Initialize KeyTransferControllerConfig
+	kcc, err := initKeyTransferControllerConfig(stores)
 	if err != nil {
 		return err
 	}
@@ -78,7 +93,7 @@ func (app *App) startServer() error {
 	}
 
 	// Initialize routes
-	routes := router.InitRoutes(configuration, kcc, km, aasClient)
+	routes := router.InitRoutes(configuration, kcc, km, stores, aasClient)
 	loggerMiddleware := middleware.LogWriterMiddleware{app.logWriter()}
 	routes.Use(loggerMiddleware.WriteDurationLog())
 	defaultLog.Info("kbs/server:startServer() Starting server")
@@ -90,6 +105,12 @@ func (app *App) startServer() error {
 			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
 		ClientAuth: tls.RequestClientCert,
 	}
+	certRenewer, err := startCertRenewer(configuration, tlsConfig)
+	if err != nil {
+		defaultLog.WithError(err).Error("kbs/server:startServer() Error while starting certificate renewer")
+		return err
+	}
+	defer certRenewer.Stop()
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -106,14 +127,11 @@ func (app *App) startServer() error {
 		MaxHeaderBytes:    configuration.Server.MaxHeaderBytes,
 	}
 
-	tlsCert := configuration.TLS.CertFile
-	tlsKey := configuration.TLS.KeyFile
-
-	defaultLog.Info(tlsCert)
-	defaultLog.Info(tlsKey)
-	// Dispatch web server go routine
+	defaultLog.Info(configuration.TLS.CertFile)
+	defaultLog.Info(configuration.TLS.KeyFile)
+	// Dispatch web server go routine, the TLS certificate is provided by the certificate renewer
 	go func() {
-		if err := httpServer.ListenAndServeTLS(tlsCert, tlsKey); err != nil {
+		if err := httpServer.ListenAndServeTLS("", ""); err != nil {
 			if err != http.ErrServerClosed {
 				defaultLog.WithError(err).Fatal("Failed to start HTTPS server")
 			}
@@ -135,7 +153,28 @@ func (app *App) startServer() error {
 	return nil
 }
 
-func initKeyTransferControllerConfig() (domain.KeyTransferControllerConfig, error) {
+// startCertRenewer starts the renewal of the TLS certificate issued by CMS with the credential of the KBS service user
+func startCertRenewer(configuration *config.Configuration, tlsConfig *tls.Config) (*certrenewer.Renewer, error) {
+	defaultLog.Trace("kbs/server:startCertRenewer() Entering")
+	defer defaultLog.Trace("kbs/server:startCertRenewer() Leaving")
+
+	renewer := certrenewer.NewRenewer(configuration.CMSBaseURL, constants.TrustedCaCertsDir,
+		certrenewer.NewAasTokenProvider(configuration.AASBaseUrl, configuration.KBS.Username,
+			configuration.KBS.Password, constants.TrustedCaCertsDir))
+	tlsCertificate, err := renewer.Watch(certrenewer.Certificate{
+		CertFile: configuration.TLS.CertFile,
+		KeyFile:  configuration.TLS.KeyFile,
+		CertType: commConstants.CertTypeTls,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.GetCertificate = tlsCertificate.GetCertificate
+	renewer.Start()
+	return renewer, nil
+}
+
+func initKeyTransferControllerConfig(stores domain.MetadataStores) (domain.KeyTransferControllerConfig, error) {
 	defaultLog.Trace("kbs/server:initKeyTransferControllerConfig() Entering")
 	defer defaultLog.Trace("kbs/server:initKeyTransferControllerConfig() Leaving")
 
@@ -146,10 +185,44 @@ func initKeyTransferControllerConfig() (domain.KeyTransferControllerConfig, erro
 
 	kcc := domain.KeyTransferControllerConfig{
 		AasJwtSigningCertsDir:   constants.TrustedJWTSigningCertsDir,
-		SamlCertsDir:            constants.SamlCertsDir,
+		SamlCertStore:           stores.SamlCertificateStore,
 		TrustedCaCertsDir:       constants.TrustedCaCertsDir,
-		TpmIdentityCertsDir:     constants.TpmIdentityCertsDir,
+		TpmIdentityCertStore:    stores.TpmIdentityCertificateStore,
 		DefaultTransferPolicyId: id,
 	}
 	return kcc, nil
 }
+
+// initMetadataStores creates the stores of the keys, key transfer policies, key transfer events and certificates for
+// the configured metadata store. The returned DataStore is only set for the postgres metadata store and must be closed
+// by the caller.
+func initMetadataStores(configuration *config.Configuration) (domain.MetadataStores, *postgres.DataStore, error) {
+	defaultLog.Trace("kbs/server:initMetadataStores() Entering")
+	defer defaultLog.Trace("kbs/server:initMetadataStores() Leaving")
+
+	switch strings.ToLower(configuration.MetadataStore) {
+	case "", constants.DirectoryMetadataStore:
+		return domain.MetadataStores{
+			KeyStore:                    directory.NewKeyStore(constants.KeysDir),
+			KeyTransferPolicyStore:      directory.NewKeyTransferPolicyStore(constants.KeysTransferPolicyDir),
+			KeyTransferEventStore:       directory.NewKeyTransferEventStore(constants.KeyTransferEventsDir),
+			SamlCertificateStore:        directory.NewCertificateStore(constants.SamlCertsDir),
+			TpmIdentityCertificateStore: directory.NewCertificateStore(constants.TpmIdentityCertsDir),
+		}, nil, nil
+	case constants.PostgresMetadataStore:
+		dataStore, err := postgres.InitDatabase(&configuration.DB)
+		if err != nil {
+			defaultLog.WithError(err).Error("kbs/server:initMetadataStores() Error initializing the database")
+			return domain.MetadataStores{}, nil, errors.Wrap(err, "Failed to initialize the postgres metadata store")
+		}
+		return domain.MetadataStores{
+			KeyStore:                    postgres.NewKeyStore(dataStore),
+			KeyTransferPolicyStore:      postgres.NewKeyTransferPolicyStore(dataStore),
+			KeyTransferEventStore:       postgres.NewKeyTransferEventStore(dataStore),
+			SamlCertificateStore:        postgres.NewCertificateStore(dataStore, constants.SamlCertType),
+			TpmIdentityCertificateStore: postgres.NewCertificateStore(dataStore, constants.TpmIdentityCertType),
+		}, dataStore, nil
+	default:
+		return domain.MetadataStores{}, nil, errors.Errorf("kbs/server:initMetadataStores() %s metadata store is not supported", configuration.MetadataStore)
+	}
+}
@@ -141,6 +141,37 @@ func (app *App) setupTaskRunner() (*setup.Runner, error) {
 		DefaultTransferPolicyFile: constants.DefaultTransferPolicyFile,
 		ConsoleWriter:             app.consoleWriter(),
 	})
+	// the database is only set up when the keys, key transfer policies and key transfer events are stored in postgres
+	if strings.ToLower(viper.GetString(config.MetadataStore)) == constants.PostgresMetadataStore {
+		dbConf := commConfig.DBConfig{
+			Vendor:   viper.GetString(commConfig.DbVendor),
+			Host:     viper.GetString(commConfig.DbHost),
+			Port:     viper.GetInt(commConfig.DbPort),
+			DBName:   viper.GetString(commConfig.DbName),
+			Username: viper.GetString(commConfig.DbUsername),
+			Password: viper.GetString(commConfig.DbPassword),
+			SSLMode:  viper.GetString(commConfig.DbSslMode),
+			SSLCert:  viper.GetString(commConfig.DbSslCert),
+
+			ConnectionRetryAttempts: viper.GetInt(commConfig.DbConnRetryAttempts),
+			ConnectionRetryTime:     viper.GetInt(commConfig.DbConnRetryTime),
+		}
+		runner.AddTask("database", "", &tasks.DBSetup{
+			DBConfigPtr:   &app.Config.DB,
+			DBConfig:      dbConf,
+			SSLCertSource: viper.GetString(commConfig.DbSslCertSource),
+			ConsoleWriter: app.consoleWriter(),
+		})
+		runner.AddTask("migrate-directory-store", "", &tasks.MigrateDirectoryStore{
+			ConsoleWriter:        app.consoleWriter(),
+			DBConfigPtr:          &app.Config.DB,
+			KeysDir:              constants.KeysDir,
+			KeyTransferPolicyDir: constants.KeysTransferPolicyDir,
+			KeyTransferEventsDir: constants.KeyTransferEventsDir,
+			SamlCertsDir:         constants.SamlCertsDir,
+			TpmIdentityCertsDir:  constants.TpmIdentityCertsDir,
+		})
+	}
 	runner.AddTask("update-service-config", "", &tasks.UpdateServiceConfig{
 		ConsoleWriter: app.consoleWriter(),
 		AASBaseUrl:    viper.GetString(commConfig.AasBaseUrl),
@@ -155,6 +186,7 @@ func (app *App) setupTaskRunner() (*setup.Runner, error) {
 			WriteTimeout:      viper.GetDuration(commConfig.ServerWriteTimeout),
 			IdleTimeout:       viper.GetDuration(commConfig.ServerIdleTimeout),
 			MaxHeaderBytes:    viper.GetInt(commConfig.ServerMaxHeaderBytes),
+			MetricsEnabled:    viper.GetBool(commConfig.ServerMetricsEnabled),
 		},
 		DefaultPort: constants.DefaultKBSListenerPort,
 		AppConfig:   &app.Config,
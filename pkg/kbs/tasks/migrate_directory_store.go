@@ -0,0 +1,276 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/constants"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/directory"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/postgres"
+	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
+	commErr "github.com/intel-secl/intel-secl/v5/pkg/lib/common/err"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/setup"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/pkg/errors"
+)
+
+// MigrateDirectoryStore imports the keys, key transfer policies, key transfer events and the SAML and TPM identity
+// certificates of the directory metadata store into the postgres metadata store. Records keep their IDs and timestamps,
+// and records which are already present in the database are skipped so that the task can be run again. The directory
+// store is left untouched.
+type MigrateDirectoryStore struct {
+	ConsoleWriter        io.Writer
+	DBConfigPtr          *commConfig.DBConfig
+	KeysDir              string
+	KeyTransferPolicyDir string
+	KeyTransferEventsDir string
+	SamlCertsDir         string
+	TpmIdentityCertsDir  string
+	commandName          string
+}
+
+const migrateDirectoryStoreHelpPrompt = "Following environment variables are required for migrate-directory-store setup:"
+
+var migrateDirectoryStoreEnvHelp = map[string]string{
+	"METADATA_STORE": "Must be set to postgres, the database being configured by the database setup task",
+}
+
+func (t *MigrateDirectoryStore) Run() error {
+	if t.DBConfigPtr == nil {
+		return errors.New("Pointer to database configuration structure can not be nil")
+	}
+	fmt.Fprintln(t.ConsoleWriter, "Migrating the directory metadata store to the database")
+
+	dataStore, err := postgres.InitDatabase(t.DBConfigPtr)
+	if err != nil {
+		return errors.Wrap(err, "tasks/migrate_directory_store:Run() Failed to connect database")
+	}
+	defer dataStore.Close()
+
+	return t.migrate(dataStore)
+}
+
+// migrate imports the records of the directory store into the database of the data store
+func (t *MigrateDirectoryStore) migrate(dataStore *postgres.DataStore) error {
+	// policies are migrated before the keys which refer to them
+	if exists(t.KeyTransferPolicyDir) {
+		policies, err := directory.NewKeyTransferPolicyStore(t.KeyTransferPolicyDir).Search(nil)
+		if err != nil {
+			return errors.Wrap(err, "tasks/migrate_directory_store:Run() Failed to read key transfer policies")
+		}
+		policyStore := postgres.NewKeyTransferPolicyStore(dataStore)
+		var imported int
+		for i := range policies {
+			inserted, err := policyStore.Import(&policies[i])
+			if err != nil {
+				return errors.Wrapf(err, "tasks/migrate_directory_store:Run() Failed to import key transfer policy %s", policies[i].ID)
+			}
+			if inserted {
+				imported++
+			}
+		}
+		fmt.Fprintf(t.ConsoleWriter, "Imported %d of %d key transfer policies\n", imported, len(policies))
+	}
+
+	if exists(t.KeysDir) {
+		keys, err := directory.NewKeyStore(t.KeysDir).Search(nil)
+		if err != nil {
+			return errors.Wrap(err, "tasks/migrate_directory_store:Run() Failed to read keys")
+		}
+		keyStore := postgres.NewKeyStore(dataStore)
+		var imported int
+		for i := range keys {
+			inserted, err := keyStore.Import(&keys[i])
+			if err != nil {
+				return errors.Wrapf(err, "tasks/migrate_directory_store:Run() Failed to import key %s", keys[i].ID)
+			}
+			if inserted {
+				imported++
+			}
+		}
+		fmt.Fprintf(t.ConsoleWriter, "Imported %d of %d keys\n", imported, len(keys))
+	}
+
+	if exists(t.KeyTransferEventsDir) {
+		events, err := directory.NewKeyTransferEventStore(t.KeyTransferEventsDir).Search(nil)
+		if err != nil {
+			return errors.Wrap(err, "tasks/migrate_directory_store:Run() Failed to read key transfer events")
+		}
+		eventStore := postgres.NewKeyTransferEventStore(dataStore)
+		var imported int
+		for i := range events {
+			inserted, err := eventStore.Import(&events[i])
+			if err != nil {
+				return errors.Wrapf(err, "tasks/migrate_directory_store:Run() Failed to import key transfer event %s", events[i].ID)
+			}
+			if inserted {
+				imported++
+			}
+		}
+		fmt.Fprintf(t.ConsoleWriter, "Imported %d of %d key transfer events\n", imported, len(events))
+	}
+
+	if err := t.migrateCertificates(dataStore, t.SamlCertsDir, constants.SamlCertType); err != nil {
+		return err
+	}
+	return t.migrateCertificates(dataStore, t.TpmIdentityCertsDir, constants.TpmIdentityCertType)
+}
+
+// migrateCertificates imports the certificates of the directory of the certificate type
+func (t *MigrateDirectoryStore) migrateCertificates(dataStore *postgres.DataStore, dir, certType string) error {
+	if !exists(dir) {
+		return nil
+	}
+	certificates, err := readCertificates(dir)
+	if err != nil {
+		return errors.Wrapf(err, "tasks/migrate_directory_store:migrateCertificates() Failed to read %s certificates", certType)
+	}
+	certStore := postgres.NewCertificateStore(dataStore, certType)
+	var imported int
+	for i := range certificates {
+		inserted, err := certStore.Import(&certificates[i])
+		if err != nil {
+			return errors.Wrapf(err, "tasks/migrate_directory_store:migrateCertificates() Failed to import %s certificate %s", certType, certificates[i].ID)
+		}
+		if inserted {
+			imported++
+		}
+	}
+	fmt.Fprintf(t.ConsoleWriter, "Imported %d of %d %s certificates\n", imported, len(certificates), certType)
+	return nil
+}
+
+// Validate checks that every record of the directory store is present in the database
+func (t *MigrateDirectoryStore) Validate() error {
+	if t.DBConfigPtr == nil {
+		return errors.New("Pointer to database configuration structure can not be nil")
+	}
+
+	dataStore, err := postgres.New(pgConfig(t.DBConfigPtr))
+	if err != nil {
+		return errors.Wrap(err, "tasks/migrate_directory_store:Validate() Failed to connect database")
+	}
+	defer dataStore.Close()
+
+	return t.validate(dataStore)
+}
+
+// validate checks that the records of the directory store are present in the database of the data store
+func (t *MigrateDirectoryStore) validate(dataStore *postgres.DataStore) error {
+	if exists(t.KeyTransferPolicyDir) {
+		policies, err := directory.NewKeyTransferPolicyStore(t.KeyTransferPolicyDir).Search(nil)
+		if err != nil {
+			return errors.Wrap(err, "tasks/migrate_directory_store:Validate() Failed to read key transfer policies")
+		}
+		policyStore := postgres.NewKeyTransferPolicyStore(dataStore)
+		for _, policy := range policies {
+			if _, err = policyStore.Retrieve(policy.ID); err != nil {
+				return notMigratedError(err, "key transfer policy", policy.ID.String())
+			}
+		}
+	}
+
+	if exists(t.KeysDir) {
+		keys, err := directory.NewKeyStore(t.KeysDir).Search(nil)
+		if err != nil {
+			return errors.Wrap(err, "tasks/migrate_directory_store:Validate() Failed to read keys")
+		}
+		keyStore := postgres.NewKeyStore(dataStore)
+		for _, key := range keys {
+			if _, err = keyStore.Retrieve(key.ID); err != nil {
+				return notMigratedError(err, "key", key.ID.String())
+			}
+		}
+	}
+
+	if exists(t.KeyTransferEventsDir) {
+		events, err := directory.NewKeyTransferEventStore(t.KeyTransferEventsDir).Search(nil)
+		if err != nil {
+			return errors.Wrap(err, "tasks/migrate_directory_store:Validate() Failed to read key transfer events")
+		}
+		eventStore := postgres.NewKeyTransferEventStore(dataStore)
+		for _, event := range events {
+			if _, err = eventStore.Retrieve(event.ID); err != nil {
+				return notMigratedError(err, "key transfer event", event.ID.String())
+			}
+		}
+	}
+
+	if err := validateCertificates(dataStore, t.SamlCertsDir, constants.SamlCertType); err != nil {
+		return err
+	}
+	return validateCertificates(dataStore, t.TpmIdentityCertsDir, constants.TpmIdentityCertType)
+}
+
+// validateCertificates checks that the certificates of the directory of the certificate type are in the database
+func validateCertificates(dataStore *postgres.DataStore, dir, certType string) error {
+	if !exists(dir) {
+		return nil
+	}
+	certificates, err := readCertificates(dir)
+	if err != nil {
+		return errors.Wrapf(err, "tasks/migrate_directory_store:Validate() Failed to read %s certificates", certType)
+	}
+	certStore := postgres.NewCertificateStore(dataStore, certType)
+	for _, certificate := range certificates {
+		if _, err = certStore.Retrieve(certificate.ID); err != nil {
+			return notMigratedError(err, certType+" certificate", certificate.ID.String())
+		}
+	}
+	return nil
+}
+
+func (t *MigrateDirectoryStore) PrintHelp(w io.Writer) {
+	setup.PrintEnvHelp(w, migrateDirectoryStoreHelpPrompt, "", migrateDirectoryStoreEnvHelp)
+	fmt.Fprintln(w, "")
+}
+
+func (t *MigrateDirectoryStore) SetName(n, e string) {
+	t.commandName = n
+}
+
+func notMigratedError(err error, record, id string) error {
+	if err.Error() == commErr.RecordNotFound {
+		return errors.Errorf("tasks/migrate_directory_store:Validate() %s %s is not migrated to the database", record, id)
+	}
+	return errors.Wrapf(err, "tasks/migrate_directory_store:Validate() Failed to retrieve %s %s", record, id)
+}
+
+// readCertificates reads the certificates of a certificate directory. The directory store names the files by the
+// certificate IDs, the files copied by hand get an ID derived from their name so that the task can be run again.
+func readCertificates(dir string) ([]kbs.Certificate, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var certificates []kbs.Certificate
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		certPem, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		id, err := uuid.Parse(file.Name())
+		if err != nil {
+			id = uuid.NewSHA1(uuid.NameSpaceOID, []byte(file.Name()))
+		}
+		certificates = append(certificates, kbs.Certificate{ID: id, Certificate: certPem})
+	}
+	return certificates, nil
+}
+
+// exists checks if the directory of a store exists
+func exists(dir string) bool {
+	_, err := os.Stat(dir)
+	return err == nil
+}
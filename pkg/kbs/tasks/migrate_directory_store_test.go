@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tasks
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/directory"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/domain/models"
+	"github.com/intel-secl/intel-secl/v5/pkg/kbs/postgres"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/aps"
+	"github.com/intel-secl/intel-secl/v5/pkg/model/kbs"
+	"github.com/stretchr/testify/assert"
+)
+
+const samlCertPath = "../controllers/resources/saml/saml_cert.pem"
+
+// newDirectoryStore creates a directory store holding a key transfer policy, a key, a key transfer event and a SAML
+// certificate copied by hand, the TPM identity certificates directory being absent
+func newDirectoryStore(t *testing.T) (*MigrateDirectoryStore, *models.KeyAttributes) {
+	dir, err := ioutil.TempDir("", "kbs-directory-store")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	task := &MigrateDirectoryStore{
+		ConsoleWriter:        &bytes.Buffer{},
+		KeysDir:              filepath.Join(dir, "keys"),
+		KeyTransferPolicyDir: filepath.Join(dir, "keys-transfer-policy"),
+		KeyTransferEventsDir: filepath.Join(dir, "key-transfer-events"),
+		SamlCertsDir:         filepath.Join(dir, "saml"),
+		TpmIdentityCertsDir:  filepath.Join(dir, "tpm-identity"),
+	}
+	for _, storeDir := range []string{task.KeysDir, task.KeyTransferPolicyDir, task.SamlCertsDir} {
+		assert.NoError(t, os.Mkdir(storeDir, 0700))
+	}
+
+	policy, err := directory.NewKeyTransferPolicyStore(task.KeyTransferPolicyDir).Create(&kbs.KeyTransferPolicy{
+		AttestationType: []aps.AttestationType{aps.SGX},
+	})
+	assert.NoError(t, err)
+	key, err := directory.NewKeyStore(task.KeysDir).Create(&models.KeyAttributes{
+		ID:               uuid.New(),
+		Algorithm:        "AES",
+		KeyLength:        256,
+		TransferPolicyId: policy.ID,
+		CreatedAt:        time.Now().UTC(),
+	})
+	assert.NoError(t, err)
+	_, err = directory.NewKeyTransferEventStore(task.KeyTransferEventsDir).Create(&kbs.KeyTransferEvent{
+		KeyID:    key.ID,
+		Decision: "granted",
+	})
+	assert.NoError(t, err)
+	certPem, err := ioutil.ReadFile(samlCertPath)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(task.SamlCertsDir, "saml.pem"), certPem, 0600))
+	return task, key
+}
+
+func expectImport(mock sqlmock.Sqlmock, table string, inserted bool) {
+	rows := sqlmock.NewRows([]string{"id"})
+	mock.ExpectBegin()
+	if inserted {
+		mock.ExpectQuery(`INSERT INTO "` + table + `" .* ON CONFLICT \(id\) DO NOTHING`).WillReturnRows(rows.AddRow(uuid.New()))
+		mock.ExpectCommit()
+	} else {
+		mock.ExpectQuery(`INSERT INTO "` + table + `" .* ON CONFLICT \(id\) DO NOTHING`).WillReturnRows(rows)
+		mock.ExpectRollback()
+	}
+}
+
+func TestMigrateDirectoryStore(t *testing.T) {
+	task, _ := newDirectoryStore(t)
+	dataStore, mock, err := postgres.NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	expectImport(mock, "key_transfer_policy", true)
+	expectImport(mock, "key", true)
+	expectImport(mock, "key_transfer_event", true)
+	expectImport(mock, "certificate", true)
+
+	assert.NoError(t, task.migrate(dataStore))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	output := task.ConsoleWriter.(*bytes.Buffer).String()
+	assert.Contains(t, output, "Imported 1 of 1 keys")
+	assert.Contains(t, output, "Imported 1 of 1 saml certificates")
+	assert.NotContains(t, output, "tpm-identity")
+}
+
+func TestMigrateDirectoryStoreAgain(t *testing.T) {
+	task, _ := newDirectoryStore(t)
+	dataStore, mock, err := postgres.NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	expectImport(mock, "key_transfer_policy", false)
+	expectImport(mock, "key", false)
+	expectImport(mock, "key_transfer_event", false)
+	expectImport(mock, "certificate", false)
+
+	assert.NoError(t, task.migrate(dataStore))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	output := task.ConsoleWriter.(*bytes.Buffer).String()
+	assert.Contains(t, output, "Imported 0 of 1 key transfer policies")
+	assert.Contains(t, output, "Imported 0 of 1 saml certificates")
+}
+
+func TestMigrateDirectoryStoreValidate(t *testing.T) {
+	task, key := newDirectoryStore(t)
+	dataStore, mock, err := postgres.NewSQLMockDataStore()
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key_transfer_policy"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content"}).AddRow(uuid.New(), []byte("{}")))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "key"  WHERE (id = $1)`)).
+		WithArgs(key.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "attributes"}))
+
+	err = task.validate(dataStore)
+	assert.EqualError(t, err, "tasks/migrate_directory_store:Validate() key "+key.ID.String()+" is not migrated to the database")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadCertificates(t *testing.T) {
+	task, _ := newDirectoryStore(t)
+	id := uuid.New()
+	certPem, err := ioutil.ReadFile(samlCertPath)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(task.SamlCertsDir, id.String()), certPem, 0600))
+
+	certificates, err := readCertificates(task.SamlCertsDir)
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 2)
+	// the files named by the directory store keep their ID, the others get the same ID on every run
+	assert.Equal(t, id, certificates[0].ID)
+	assert.Equal(t, uuid.NewSHA1(uuid.NameSpaceOID, []byte("saml.pem")), certificates[1].ID)
+}
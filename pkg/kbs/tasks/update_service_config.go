@@ -32,7 +32,8 @@ type UpdateServiceConfig struct {
 const envHelpPrompt = "Following environment variables are required for update-service-config setup:"
 
 var allowedSKCChallengeTypes = map[string]bool{"sgx": true}
-var allowedKeyManagers = map[string]bool{"kmip": true}
+var allowedKeyManagers = map[string]bool{"kmip": true, "directory": true, "pkcs11": true}
+var allowedMetadataStores = map[string]bool{constants.DirectoryMetadataStore: true, constants.PostgresMetadataStore: true}
 
 var envHelp = map[string]string{
 	"SERVICE_USERNAME":           "The service username as configured in AAS",
@@ -49,6 +50,13 @@ var envHelp = map[string]string{
 	"KMIP_CLIENT_CERT_PATH":      "KMIP Client certificate path",
 	"KMIP_CLIENT_KEY_PATH":       "KMIP Client key path",
 	"KMIP_ROOT_CERT_PATH":        "KMIP Root Certificate path",
+	"KEY_MANAGER":                "Key manager used to store the keys, kmip, directory or pkcs11",
+	"DIRECTORY_KEYS_DIR":         "Directory in which the directory key manager stores the encrypted key material",
+	"DIRECTORY_MASTER_KEY_FILE":  "Master key file of the directory key manager, generated when it does not exist",
+	"PKCS11_MODULE_PATH":         "Path of the PKCS#11 module of the HSM",
+	"PKCS11_TOKEN_LABEL":         "Label of the PKCS#11 token in which the keys are stored",
+	"PKCS11_PIN":                 "User PIN of the PKCS#11 token",
+	"METADATA_STORE":             "Store of the keys, key transfer policies and key transfer events, directory or postgres",
 	"SKC_CHALLENGE_TYPE":         "SKC challenge type",
 	"SQVS_URL":                   "SQVS URL",
 	"SESSION_EXPIRY_TIME":        "Session Expiry Time",
@@ -58,6 +66,7 @@ var envHelp = map[string]string{
 	"SERVER_WRITE_TIMEOUT":       "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":        "Request Idle Timeout in Seconds",
 	"SERVER_MAX_HEADER_BYTES":    "Max Length Of Request Header in Bytes ",
+	"SERVER_METRICS_ENABLED":     "Expose the /metrics endpoint in Prometheus text format when set to true",
 }
 
 func (uc UpdateServiceConfig) Run() error {
@@ -94,7 +103,17 @@ func (uc UpdateServiceConfig) Run() error {
 		ClientCertificateFilePath: viper.GetString(config.KmipClientCertPath),
 		RootCertificateFilePath:   viper.GetString(config.KmipRootCertPath),
 	}
+	(*uc.AppConfig).Directory = config.DirectoryConfig{
+		KeysDir:       viper.GetString(config.DirectoryKeysDir),
+		MasterKeyFile: viper.GetString(config.DirectoryMasterKey),
+	}
+	(*uc.AppConfig).Pkcs11 = config.Pkcs11Config{
+		ModulePath: viper.GetString(config.Pkcs11ModulePath),
+		TokenLabel: viper.GetString(config.Pkcs11TokenLabel),
+		Pin:        viper.GetString(config.Pkcs11Pin),
+	}
 	(*uc.AppConfig).KeyManager = viper.GetString(config.KeyManager)
+	(*uc.AppConfig).MetadataStore = strings.ToLower(viper.GetString(config.MetadataStore))
 
 	(*uc.AppConfig).Skc = config.SKCConfig{
 		StmLabel:          viper.GetString("skc-challenge-type"),
@@ -122,7 +141,10 @@ func (uc UpdateServiceConfig) Validate() error {
 		return errors.New("tasks/update_service_config:Validate() Configured Log Length not valid. Please specify value within " + strconv.Itoa(constants.MinLogLengthLimit) + " and " + strconv.Itoa(constants.MaxLogLengthLimit))
 	}
 	if _, validInput := allowedKeyManagers[strings.ToLower((*uc.AppConfig).KeyManager)]; !validInput {
-		return errors.New("Invalid value provided for KEY_MANAGER. Value should be kmip")
+		return errors.New("Invalid value provided for KEY_MANAGER. Value should be kmip, directory or pkcs11")
+	}
+	if _, validInput := allowedMetadataStores[strings.ToLower((*uc.AppConfig).MetadataStore)]; !validInput {
+		return errors.New("Invalid value provided for METADATA_STORE. Value should be directory or postgres")
 	}
 	if (*uc.AppConfig).Skc.StmLabel != "" {
 		if _, validInput := allowedSKCChallengeTypes[strings.ToLower((*uc.AppConfig).Skc.StmLabel)]; !validInput {
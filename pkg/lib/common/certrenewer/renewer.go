@@ -0,0 +1,299 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// Package certrenewer renews the certificates issued by CMS to the services before they expire. The renewer re-keys
+// and requests a new certificate from CMS with the same subject and SAN list as the current certificate, saves them
+// in place of the current certificate and key, and serves the new certificate to the TLS connections without restart.
+package certrenewer
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	clog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/setup"
+	"github.com/pkg/errors"
+)
+
+var log = clog.GetDefaultLogger()
+var slog = clog.GetSecurityLogger()
+
+const (
+	// DefaultRenewBefore is the fraction of the lifetime of the certificates remaining when they are renewed
+	DefaultRenewBefore = 1.0 / 3
+	// DefaultCheckInterval is the interval at which the certificates are checked
+	DefaultCheckInterval = time.Hour
+)
+
+// Certificate is a certificate issued by CMS watched by the renewer
+type Certificate struct {
+	CertFile string
+	KeyFile  string
+	// CertType is the type of certificate requested from CMS, TLS or Signing
+	CertType string
+	// OnRenew is called with the new key pair after the renewed certificate has been saved
+	OnRenew func(keyPair *tls.Certificate)
+}
+
+// WatchedCertificate holds the current key pair of a certificate watched by the renewer
+type WatchedCertificate struct {
+	Certificate
+
+	lock    sync.RWMutex
+	keyPair *tls.Certificate
+	modTime time.Time
+}
+
+// KeyPair returns the current key pair of the certificate
+func (wc *WatchedCertificate) KeyPair() *tls.Certificate {
+	wc.lock.RLock()
+	defer wc.lock.RUnlock()
+	return wc.keyPair
+}
+
+// GetCertificate returns the current key pair of the certificate, it is set as tls.Config.GetCertificate so that the
+// servers use the renewed certificate for the new connections
+func (wc *WatchedCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return wc.KeyPair(), nil
+}
+
+// load reads the key pair from the certificate and key files when they have been modified since last read
+func (wc *WatchedCertificate) load() (bool, error) {
+	fi, err := os.Stat(wc.CertFile)
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not read certificate file %s", wc.CertFile)
+	}
+	wc.lock.RLock()
+	modified := wc.keyPair == nil || !fi.ModTime().Equal(wc.modTime)
+	wc.lock.RUnlock()
+	if !modified {
+		return false, nil
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(wc.CertFile, wc.KeyFile)
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not load key pair of certificate %s", wc.CertFile)
+	}
+	keyPair.Leaf, err = x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not parse certificate %s", wc.CertFile)
+	}
+	wc.lock.Lock()
+	wc.keyPair = &keyPair
+	wc.modTime = fi.ModTime()
+	wc.lock.Unlock()
+	return true, nil
+}
+
+// Renewer renews the watched certificates when the remaining part of their lifetime is less than RenewBefore
+type Renewer struct {
+	CmsBaseURL    string
+	CaCertDirPath string
+	// TokenProvider provides the bearer tokens of the requests to CMS from the bootstrap credential of the service
+	TokenProvider TokenProvider
+	RenewBefore   float64
+	CheckInterval time.Duration
+	Client        setup.HttpClient
+
+	certificates []*WatchedCertificate
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+// NewRenewer creates a renewer of the certificates issued by CMS with the default renewal settings
+func NewRenewer(cmsBaseUrl, caCertDirPath string, tokenProvider TokenProvider) *Renewer {
+	return &Renewer{
+		CmsBaseURL:    cmsBaseUrl,
+		CaCertDirPath: caCertDirPath,
+		TokenProvider: tokenProvider,
+		RenewBefore:   DefaultRenewBefore,
+		CheckInterval: DefaultCheckInterval,
+	}
+}
+
+// Watch loads the current key pair of the certificate and adds the certificate to the certificates renewed, the
+// certificates are added before the renewer is started
+func (r *Renewer) Watch(certificate Certificate) (*WatchedCertificate, error) {
+	log.Trace("certrenewer/renewer:Watch() Entering")
+	defer log.Trace("certrenewer/renewer:Watch() Leaving")
+
+	wc := &WatchedCertificate{Certificate: certificate}
+	if _, err := wc.load(); err != nil {
+		return nil, err
+	}
+	r.certificates = append(r.certificates, wc)
+	return wc, nil
+}
+
+// Start checks the watched certificates at once, then at each check interval until the renewer is stopped
+func (r *Renewer) Start() {
+	log.Trace("certrenewer/renewer:Start() Entering")
+	defer log.Trace("certrenewer/renewer:Start() Leaving")
+
+	checkInterval := r.CheckInterval
+	if checkInterval <= 0 {
+		checkInterval = DefaultCheckInterval
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			r.CheckCertificates()
+			select {
+			case <-ticker.C:
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the renewer and waits for the renewal in progress
+func (r *Renewer) Stop() {
+	log.Trace("certrenewer/renewer:Stop() Entering")
+	defer log.Trace("certrenewer/renewer:Stop() Leaving")
+
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+}
+
+// CheckCertificates reloads the watched certificates modified on disk and renews the certificates due for renewal.
+// The failures are logged and the renewals are retried at the next check.
+func (r *Renewer) CheckCertificates() {
+	log.Trace("certrenewer/renewer:CheckCertificates() Entering")
+	defer log.Trace("certrenewer/renewer:CheckCertificates() Leaving")
+
+	for _, wc := range r.certificates {
+		// the certificate may have been replaced by running the setup tasks again
+		reloaded, err := wc.load()
+		if err != nil {
+			log.WithError(err).Errorf("certrenewer/renewer:CheckCertificates() Could not reload certificate %s", wc.CertFile)
+			continue
+		}
+		if reloaded {
+			log.Infof("certrenewer/renewer:CheckCertificates() Reloaded certificate %s", wc.CertFile)
+		}
+		if !r.renewalDue(wc.KeyPair().Leaf, time.Now()) {
+			continue
+		}
+		if err = r.renew(wc); err != nil {
+			log.WithError(err).Errorf("certrenewer/renewer:CheckCertificates() Could not renew certificate %s", wc.CertFile)
+		}
+	}
+}
+
+// renewalDue returns true when the remaining part of the lifetime of the certificate is less than RenewBefore
+func (r *Renewer) renewalDue(cert *x509.Certificate, now time.Time) bool {
+	renewBefore := r.RenewBefore
+	if renewBefore <= 0 || renewBefore >= 1 {
+		renewBefore = DefaultRenewBefore
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotAfter.Add(-time.Duration(float64(lifetime) * renewBefore)))
+}
+
+func (r *Renewer) renew(wc *WatchedCertificate) error {
+	current := wc.KeyPair()
+	log.Infof("certrenewer/renewer:renew() Renewing certificate %s expiring at %s", wc.CertFile, current.Leaf.NotAfter)
+
+	keyAlgorithm, keyLength, err := keyParameters(current)
+	if err != nil {
+		return err
+	}
+	// the new certificate has the same subject and SAN list as the current one, so that it is approved by the same
+	// CertApprover role as the certificate downloaded during setup
+	var hosts []string
+	hosts = append(hosts, current.Leaf.DNSNames...)
+	for _, ip := range current.Leaf.IPAddresses {
+		hosts = append(hosts, ip.String())
+	}
+	token, err := r.TokenProvider()
+	if err != nil {
+		return errors.Wrap(err, "Could not get token to access CMS")
+	}
+	key, cert, err := setup.GetCertificateFromCMS(wc.CertType, keyAlgorithm, keyLength, r.CmsBaseURL,
+		pkix.Name{CommonName: current.Leaf.Subject.CommonName}, strings.Join(hosts, ","), r.CaCertDirPath, token, r.Client)
+	if err != nil {
+		return err
+	}
+	// make sure the certificate issued is for the new key before replacing the current certificate
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})
+	if _, err = tls.X509KeyPair(cert, keyPem); err != nil {
+		return errors.Wrap(err, "Invalid certificate received from CMS")
+	}
+
+	if err = saveKeyPair(wc.KeyFile, keyPem, wc.CertFile, cert); err != nil {
+		return err
+	}
+	if _, err = wc.load(); err != nil {
+		return err
+	}
+	slog.Infof("%s: Renewed certificate %s valid until %s", commLogMsg.ConfigChanged, wc.CertFile, wc.KeyPair().Leaf.NotAfter)
+	if wc.OnRenew != nil {
+		wc.OnRenew(wc.KeyPair())
+	}
+	return nil
+}
+
+// keyParameters returns the algorithm and length of the key of the certificate, used for the new key
+func keyParameters(keyPair *tls.Certificate) (string, int, error) {
+	switch key := keyPair.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		return "rsa", key.N.BitLen(), nil
+	case *ecdsa.PrivateKey:
+		return "ecdsa", key.Curve.Params().BitSize, nil
+	}
+	return "", 0, errors.New("Unsupported private key type")
+}
+
+// saveKeyPair replaces the key and the certificate. Both are written to temporary files before any of them is
+// replaced, and the previous key is restored when the certificate cannot be replaced, so that the files are never left
+// partially written nor holding a key which does not match the certificate.
+func saveKeyPair(keyFile string, keyPem []byte, certFile string, certPem []byte) error {
+	previousKeyPem, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return errors.Wrapf(err, "Could not read %s", keyFile)
+	}
+	if err = ioutil.WriteFile(keyFile+".tmp", keyPem, 0600); err != nil {
+		_ = os.Remove(keyFile + ".tmp")
+		return errors.Wrapf(err, "Could not write %s", keyFile)
+	}
+	if err = ioutil.WriteFile(certFile+".tmp", certPem, 0600); err != nil {
+		_ = os.Remove(keyFile + ".tmp")
+		_ = os.Remove(certFile + ".tmp")
+		return errors.Wrapf(err, "Could not write %s", certFile)
+	}
+
+	if err = os.Rename(keyFile+".tmp", keyFile); err != nil {
+		_ = os.Remove(keyFile + ".tmp")
+		_ = os.Remove(certFile + ".tmp")
+		return errors.Wrapf(err, "Could not replace %s", keyFile)
+	}
+	if err = os.Rename(certFile+".tmp", certFile); err != nil {
+		_ = os.Remove(certFile + ".tmp")
+		if restoreErr := ioutil.WriteFile(keyFile, previousKeyPem, 0600); restoreErr != nil {
+			log.WithError(restoreErr).Errorf("certrenewer/renewer:saveKeyPair() Could not restore %s", keyFile)
+		}
+		return errors.Wrapf(err, "Could not replace %s", certFile)
+	}
+	return nil
+}
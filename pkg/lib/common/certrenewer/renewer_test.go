@@ -0,0 +1,259 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package certrenewer
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mockToken = "bootstrap-token"
+
+type mockCms struct {
+	caCert   *x509.Certificate
+	caKey    *rsa.PrivateKey
+	server   *httptest.Server
+	requests int
+	fail     bool
+}
+
+func newMockCms(t *testing.T) *mockCms {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDer)
+	assert.NoError(t, err)
+
+	cms := &mockCms{caCert: caCert, caKey: caKey}
+	cms.server = httptest.NewTLSServer(http.HandlerFunc(cms.certificates))
+	return cms
+}
+
+// certificates signs the CSR for ten days, as CMS would do for the CertApprover roles of the token
+func (cms *mockCms) certificates(w http.ResponseWriter, r *http.Request) {
+	cms.requests++
+	if cms.fail || r.Header.Get("Authorization") != "Bearer "+mockToken || r.URL.Query().Get("certType") != "TLS" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	block, _ := pem.Decode(body)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	cert := cms.issue(csr.PublicKey, csr.Subject, csr.DNSNames, csr.IPAddresses, time.Now(), 10*24*time.Hour)
+	_, _ = w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}))
+}
+
+func (cms *mockCms) issue(publicKey interface{}, subject pkix.Name, dnsNames []string, ips []net.IP, notBefore time.Time, validity time.Duration) []byte {
+	serialNumber, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      subject,
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	cert, _ := x509.CreateCertificate(rand.Reader, template, cms.caCert, publicKey, cms.caKey)
+	return cert
+}
+
+// writeKeyPair saves a certificate issued at notBefore for ten days and its key in the directory
+func (cms *mockCms) writeKeyPair(t *testing.T, dir string, notBefore time.Time) Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	cert := cms.issue(&key.PublicKey, pkix.Name{CommonName: "Test TLS Certificate"}, []string{"test.example.com"},
+		[]net.IP{net.ParseIP("127.0.0.1")}, notBefore, 10*24*time.Hour)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	certificate := Certificate{CertFile: filepath.Join(dir, "tls-cert.pem"), KeyFile: filepath.Join(dir, "tls.key"), CertType: "TLS"}
+	assert.NoError(t, ioutil.WriteFile(certificate.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	assert.NoError(t, ioutil.WriteFile(certificate.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certificate
+}
+
+func setupRenewer(t *testing.T) (*mockCms, *Renewer, string, func()) {
+	cms := newMockCms(t)
+	dir, err := ioutil.TempDir("", "certrenewer")
+	assert.NoError(t, err)
+	renewer := NewRenewer(cms.server.URL+"/cms/v1/", dir, NewStaticTokenProvider(mockToken))
+	renewer.Client = cms.server.Client()
+	return cms, renewer, dir, func() {
+		cms.server.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestRenewerRenewsCertificateBeforeExpiry(t *testing.T) {
+	cms, renewer, dir, teardown := setupRenewer(t)
+	defer teardown()
+
+	// issued eight days ago, two days left of the ten days of validity
+	certificate := cms.writeKeyPair(t, dir, time.Now().Add(-8*24*time.Hour))
+	var renewed *tls.Certificate
+	certificate.OnRenew = func(keyPair *tls.Certificate) {
+		renewed = keyPair
+	}
+	watched, err := renewer.Watch(certificate)
+	assert.NoError(t, err)
+	current := watched.KeyPair()
+
+	renewer.CheckCertificates()
+	assert.Equal(t, 1, cms.requests)
+	newKeyPair, err := watched.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, current.Leaf.SerialNumber, newKeyPair.Leaf.SerialNumber)
+	assert.Equal(t, newKeyPair, renewed)
+	assert.Equal(t, current.Leaf.Subject.CommonName, newKeyPair.Leaf.Subject.CommonName)
+	assert.Equal(t, current.Leaf.DNSNames, newKeyPair.Leaf.DNSNames)
+	assert.True(t, current.Leaf.IPAddresses[0].Equal(newKeyPair.Leaf.IPAddresses[0]))
+	assert.NotEqual(t, current.PrivateKey, newKeyPair.PrivateKey)
+
+	// the renewed certificate and key are saved
+	saved, err := tls.LoadX509KeyPair(certificate.CertFile, certificate.KeyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, newKeyPair.Certificate, saved.Certificate)
+
+	// the renewed certificate is not due for renewal
+	renewer.CheckCertificates()
+	assert.Equal(t, 1, cms.requests)
+}
+
+func TestRenewerKeepsCertificateNotDue(t *testing.T) {
+	cms, renewer, dir, teardown := setupRenewer(t)
+	defer teardown()
+
+	watched, err := renewer.Watch(cms.writeKeyPair(t, dir, time.Now().Add(-24*time.Hour)))
+	assert.NoError(t, err)
+	current := watched.KeyPair()
+	renewer.CheckCertificates()
+	assert.Equal(t, 0, cms.requests)
+	assert.Equal(t, current, watched.KeyPair())
+}
+
+func TestRenewerKeepsCertificateWhenRenewalFails(t *testing.T) {
+	cms, renewer, dir, teardown := setupRenewer(t)
+	defer teardown()
+
+	certificate := cms.writeKeyPair(t, dir, time.Now().Add(-8*24*time.Hour))
+	certPem, err := ioutil.ReadFile(certificate.CertFile)
+	assert.NoError(t, err)
+	watched, err := renewer.Watch(certificate)
+	assert.NoError(t, err)
+	current := watched.KeyPair()
+
+	cms.fail = true
+	renewer.CheckCertificates()
+	assert.Equal(t, 1, cms.requests)
+	assert.Equal(t, current, watched.KeyPair())
+	savedPem, err := ioutil.ReadFile(certificate.CertFile)
+	assert.NoError(t, err)
+	assert.Equal(t, certPem, savedPem)
+
+	// the renewal is retried at the next check
+	cms.fail = false
+	renewer.CheckCertificates()
+	assert.Equal(t, 2, cms.requests)
+	assert.NotEqual(t, current, watched.KeyPair())
+}
+
+func TestRenewerKeepsKeyWhenCertificateCannotBeSaved(t *testing.T) {
+	cms, renewer, dir, teardown := setupRenewer(t)
+	defer teardown()
+
+	certificate := cms.writeKeyPair(t, dir, time.Now().Add(-8*24*time.Hour))
+	keyPem, err := ioutil.ReadFile(certificate.KeyFile)
+	assert.NoError(t, err)
+	watched, err := renewer.Watch(certificate)
+	assert.NoError(t, err)
+	current := watched.KeyPair()
+
+	// the temporary certificate file cannot be written
+	assert.NoError(t, os.Mkdir(certificate.CertFile+".tmp", 0700))
+	renewer.CheckCertificates()
+	assert.Equal(t, 1, cms.requests)
+	assert.Equal(t, current, watched.KeyPair())
+	savedKeyPem, err := ioutil.ReadFile(certificate.KeyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, keyPem, savedKeyPem)
+	_, err = os.Stat(certificate.KeyFile + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRenewerReloadsCertificateReplacedOnDisk(t *testing.T) {
+	cms, renewer, dir, teardown := setupRenewer(t)
+	defer teardown()
+
+	watched, err := renewer.Watch(cms.writeKeyPair(t, dir, time.Now().Add(-24*time.Hour)))
+	assert.NoError(t, err)
+	current := watched.KeyPair()
+
+	cms.writeKeyPair(t, dir, time.Now())
+	// make sure the modification time differs on the file systems with coarse timestamps
+	assert.NoError(t, os.Chtimes(watched.CertFile, time.Now(), time.Now().Add(time.Second)))
+	renewer.CheckCertificates()
+	assert.Equal(t, 0, cms.requests)
+	assert.NotEqual(t, current.Leaf.SerialNumber, watched.KeyPair().Leaf.SerialNumber)
+}
+
+func TestRenewerStartAndStop(t *testing.T) {
+	cms, renewer, dir, teardown := setupRenewer(t)
+	defer teardown()
+
+	watched, err := renewer.Watch(cms.writeKeyPair(t, dir, time.Now().Add(-8*24*time.Hour)))
+	assert.NoError(t, err)
+	current := watched.KeyPair()
+	renewer.Start()
+	renewer.Stop()
+	// the certificates are checked when the renewer starts
+	assert.Equal(t, 1, cms.requests)
+	assert.NotEqual(t, current, watched.KeyPair())
+	renewer.Stop()
+}
+
+func TestWatchMissingCertificate(t *testing.T) {
+	renewer := NewRenewer("https://cms.example.com:8445/cms/v1/", "", NewStaticTokenProvider(mockToken))
+	_, err := renewer.Watch(Certificate{CertFile: "missing-cert.pem", KeyFile: "missing.key", CertType: "TLS"})
+	assert.Error(t, err)
+}
+
+func TestStaticTokenProvider(t *testing.T) {
+	token, err := NewStaticTokenProvider(mockToken)()
+	assert.NoError(t, err)
+	assert.Equal(t, mockToken, token)
+	_, err = NewStaticTokenProvider("")()
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package certrenewer
+
+import (
+	"sync"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/clients"
+	aasClient "github.com/intel-secl/intel-secl/v5/pkg/clients/aas"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	"github.com/pkg/errors"
+)
+
+// TokenProvider returns a bearer token holding the CertApprover roles of the certificates renewed
+type TokenProvider func() (string, error)
+
+// NewAasTokenProvider returns a TokenProvider fetching a new token from AAS for the service user at each renewal, the
+// credential of the service user being the long-lived bootstrap credential of the service
+func NewAasTokenProvider(aasBaseUrl, username, password, caCertDirPath string) TokenProvider {
+	var lock sync.Mutex
+	var jwtClient *aasClient.JwtClient
+	return func() (string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if jwtClient == nil {
+			caCerts, err := crypt.GetCertsFromDir(caCertDirPath)
+			if err != nil {
+				return "", errors.Wrapf(err, "Could not read CA certificates from %s", caCertDirPath)
+			}
+			client, err := clients.HTTPClientWithCA(caCerts)
+			if err != nil {
+				return "", errors.Wrap(err, "Could not create http client")
+			}
+			jwtClient = aasClient.NewJWTClient(aasBaseUrl)
+			jwtClient.HTTPClient = client
+			jwtClient.AddUser(username, password)
+		}
+		token, err := jwtClient.FetchTokenForUser(username)
+		if err != nil {
+			return "", errors.Wrap(err, "Could not fetch token for user "+username)
+		}
+		return string(token), nil
+	}
+}
+
+// NewStaticTokenProvider returns a TokenProvider of a long-lived token obtained during setup
+func NewStaticTokenProvider(token string) TokenProvider {
+	return func() (string, error) {
+		if token == "" {
+			return "", errors.New("No token configured")
+		}
+		return token, nil
+	}
+}
@@ -27,6 +27,7 @@ const (
 	ServerWriteTimeout      = "server.write-timeout"
 	ServerIdleTimeout       = "server.idle-timeout"
 	ServerMaxHeaderBytes    = "server.max-header-bytes"
+	ServerMetricsEnabled    = "server.metrics-enabled"
 	SessionExpiryTime       = "session-expiry-time"
 
 	DbVendor            = "db.vendor"
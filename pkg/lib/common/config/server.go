@@ -17,6 +17,8 @@ type ServerConfig struct {
 	WriteTimeout      time.Duration `yaml:"write-timeout" mapstructure:"write-timeout"`
 	IdleTimeout       time.Duration `yaml:"idle-timeout" mapstructure:"idle-timeout"`
 	MaxHeaderBytes    int           `yaml:"max-header-bytes" mapstructure:"max-header-bytes"`
+	// MetricsEnabled exposes the /metrics endpoint in Prometheus text format
+	MetricsEnabled bool `yaml:"metrics-enabled" mapstructure:"metrics-enabled"`
 }
 
 type ServiceConfig struct {
@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxCrlSize is the maximum size of the CRLs retrieved from the distribution points
+const maxCrlSize = 1 << 24
+
+// crlCache holds the CRLs retrieved from the distribution points until their next update, it is shared by the
+// checkers since the HTTP clients are created for each connection by some services
+var crlCache = struct {
+	sync.Mutex
+	crls map[string]*x509.RevocationList
+}{crls: make(map[string]*x509.RevocationList)}
+
+// CrlChecker checks the certificates against the CRLs of their distribution points, such as the CRLs published by
+// CMS for the certificates it issues. The certificates without distribution point are not checked, and a certificate
+// is rejected when the CRL of its distribution point cannot be retrieved.
+type CrlChecker struct {
+	// HTTPClient retrieves the CRLs, it must not check the CRLs itself
+	HTTPClient *http.Client
+}
+
+func NewCrlChecker(httpClient *http.Client) *CrlChecker {
+	return &CrlChecker{HTTPClient: httpClient}
+}
+
+// VerifyPeerCertificate checks the certificate chains verified by the TLS handshake, to be set as VerifyPeerCertificate
+// of a TLS configuration. The peer is accepted when one of the chains has no revoked certificate.
+func (cc *CrlChecker) VerifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return cc.CheckChains(verifiedChains)
+}
+
+// CheckChains checks that one of the verified certificate chains has no revoked certificate
+func (cc *CrlChecker) CheckChains(chains [][]*x509.Certificate) error {
+	var err error
+	for _, chain := range chains {
+		if err = cc.CheckChain(chain); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// CheckChain checks that no certificate of the chain is revoked, each certificate of the chain being issued by the
+// next one
+func (cc *CrlChecker) CheckChain(chain []*x509.Certificate) error {
+	for i := 0; i+1 < len(chain); i++ {
+		if err := cc.Check(chain[i], chain[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check checks that the certificate is not listed in the CRLs of its distribution points signed by its issuer
+func (cc *CrlChecker) Check(cert, issuer *x509.Certificate) error {
+	for _, url := range cert.CRLDistributionPoints {
+		// the LDAP distribution points are not supported
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			continue
+		}
+		crl, err := cc.retrieveCrl(url, issuer)
+		if err != nil {
+			return errors.Wrapf(err, "crypt/crl:Check() Could not check revocation of certificate %s", cert.Subject)
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return errors.Errorf("crypt/crl:Check() Certificate %s with serial number %s is revoked", cert.Subject,
+					cert.SerialNumber.Text(16))
+			}
+		}
+	}
+	return nil
+}
+
+// retrieveCrl returns the CRL of the distribution point, retrieved again once its next update has passed
+func (cc *CrlChecker) retrieveCrl(url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	crlCache.Lock()
+	defer crlCache.Unlock()
+
+	crl, found := crlCache.crls[url]
+	if !found || time.Now().After(crl.NextUpdate) {
+		var err error
+		if crl, err = cc.fetchCrl(url); err != nil {
+			return nil, err
+		}
+		crlCache.crls[url] = crl
+	}
+	// the CRL of the URL is signed by the issuer of the certificates pointing to it
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return nil, errors.Wrapf(err, "CRL %s is not signed by %s", url, issuer.Subject)
+	}
+	return crl, nil
+}
+
+func (cc *CrlChecker) fetchCrl(url string) (*x509.RevocationList, error) {
+	httpClient := cc.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not retrieve CRL %s", url)
+	}
+	defer func() {
+		derr := response.Body.Close()
+		if derr != nil {
+			log.WithError(derr).Error("Error closing response body")
+		}
+	}()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Could not retrieve CRL %s, status code %d", url, response.StatusCode)
+	}
+	der, err := ioutil.ReadAll(io.LimitReader(response.Body, maxCrlSize))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read CRL %s", url)
+	}
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not parse CRL %s", url)
+	}
+	return crl, nil
+}
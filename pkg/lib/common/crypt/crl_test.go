@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package crypt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createCrlTestCert(t *testing.T, serial int64, cdp string, ca *x509.Certificate,
+	caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "crl test " + big.NewInt(serial).String()},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  ca == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	if cdp != "" {
+		template.CRLDistributionPoints = []string{cdp}
+	}
+	parent, parentKey := template, key
+	if ca != nil {
+		parent, parentKey = ca, caKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, key
+}
+
+func TestCrlChecker_Check(t *testing.T) {
+	ca, caKey := createCrlTestCert(t, 1, "", nil, nil)
+	var crlRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		crlRequests++
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:                    big.NewInt(1),
+			ThisUpdate:                time.Now().Add(-time.Minute),
+			NextUpdate:                time.Now().Add(time.Hour),
+			RevokedCertificateEntries: []x509.RevocationListEntry{{SerialNumber: big.NewInt(3), RevocationTime: time.Now()}},
+		}, ca, caKey)
+		assert.NoError(t, err)
+		_, _ = w.Write(crl)
+	}))
+	defer server.Close()
+
+	checker := NewCrlChecker(server.Client())
+	validCert, _ := createCrlTestCert(t, 2, server.URL+"/crl/valid", ca, caKey)
+	assert.NoError(t, checker.Check(validCert, ca))
+	revokedCert, _ := createCrlTestCert(t, 3, server.URL+"/crl/revoked", ca, caKey)
+	assert.Error(t, checker.Check(revokedCert, ca))
+	assert.Error(t, checker.CheckChains([][]*x509.Certificate{{revokedCert, ca}}))
+	assert.NoError(t, checker.CheckChains([][]*x509.Certificate{{revokedCert, ca}, {validCert, ca}}))
+	// the CRLs are retrieved again once their next update has passed only
+	assert.Equal(t, 2, crlRequests)
+
+	// the CRL must be signed by the issuer of the certificate
+	otherCa, otherCaKey := createCrlTestCert(t, 4, "", nil, nil)
+	otherCert, _ := createCrlTestCert(t, 5, server.URL+"/crl/valid", otherCa, otherCaKey)
+	assert.Error(t, checker.Check(otherCert, otherCa))
+
+	// the certificates without distribution point are not checked, the others are rejected without CRL
+	noCdpCert, _ := createCrlTestCert(t, 6, "", ca, caKey)
+	assert.NoError(t, checker.Check(noCdpCert, ca))
+	unreachableCert, _ := createCrlTestCert(t, 7, "http://127.0.0.1:1/crl/unreachable", ca, caKey)
+	assert.Error(t, checker.Check(unreachableCert, ca))
+}
@@ -112,6 +112,9 @@ func CreateKeyPairAndCertificateRequest(subject pkix.Name, hostList, keyType str
 	hosts := strings.Split(hostList, ",")
 	for _, h := range hosts {
 		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
 		if ip := net.ParseIP(h); ip != nil {
 			template.IPAddresses = append(template.IPAddresses, ip)
 		} else {
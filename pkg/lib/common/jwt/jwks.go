@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package jwtauth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+)
+
+// JSONWebKey is a public key used to verify the tokens, as defined in RFC 7517. The key id is the SHA1 hash of the
+// signing certificate, the same as the kid header of the tokens signed with the key.
+type JSONWebKey struct {
+	Kty string   `json:"kty"`
+	Kid string   `json:"kid"`
+	Use string   `json:"use,omitempty"`
+	Alg string   `json:"alg,omitempty"`
+	N   string   `json:"n,omitempty"`
+	E   string   `json:"e,omitempty"`
+	Crv string   `json:"crv,omitempty"`
+	X   string   `json:"x,omitempty"`
+	Y   string   `json:"y,omitempty"`
+	X5c []string `json:"x5c,omitempty"`
+}
+
+// JSONWebKeySet is the JWKS document listing the keys used to verify the tokens
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// KeyId returns the kid (key id) of the tokens signed with the private key of the certificate
+func KeyId(cert *x509.Certificate) (string, error) {
+	return crypt.GetCertHashInHex(cert, crypto.SHA1)
+}
+
+// NewJSONWebKey creates the JSON web key of a PEM encoded signing certificate, optionally followed by its chain
+func NewJSONWebKey(certChainPem []byte) (*JSONWebKey, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certChainPem); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("NewJSONWebKey: failed to parse certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("NewJSONWebKey: failed to parse signing certificate PEM")
+	}
+
+	kid, err := KeyId(certs[0])
+	if err != nil {
+		return nil, err
+	}
+	jwk := &JSONWebKey{Kid: kid, Use: "sig"}
+
+	switch key := certs[0].PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.Alg = "RS384"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		bitSize := key.Curve.Params().BitSize
+		if bitSize != 256 && bitSize != 384 {
+			return nil, fmt.Errorf("NewJSONWebKey: unsupported curve %s", key.Curve.Params().Name)
+		}
+		size := (bitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Alg = fmt.Sprintf("ES%d", bitSize)
+		jwk.Crv = fmt.Sprintf("P-%d", bitSize)
+		jwk.X = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+	default:
+		return nil, fmt.Errorf("NewJSONWebKey: unsupported key type for JWT signing. only RSA and ECDSA supported")
+	}
+
+	for _, cert := range certs {
+		jwk.X5c = append(jwk.X5c, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+	return jwk, nil
+}
+
+// CertificateChainPem returns the PEM encoded certificate chain of the key, the signing certificate first
+func (jwk JSONWebKey) CertificateChainPem() ([]byte, error) {
+	if len(jwk.X5c) == 0 {
+		return nil, fmt.Errorf("x5c (certificate chain) is missing in key %s", jwk.Kid)
+	}
+	var buf bytes.Buffer
+	for _, x5c := range jwk.X5c {
+		der, err := base64.StdEncoding.DecodeString(x5c)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode x5c of key %s: %v", jwk.Kid, err)
+		}
+		if err = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// PublicKey returns the public key of the JSON web key, read from the key parameters or from the signing
+// certificate when the key has only the x5c parameter
+func (jwk JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch {
+	case jwk.Kty == "RSA" && jwk.N != "" && jwk.E != "":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode modulus of key %s: %v", jwk.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode exponent of key %s: %v", jwk.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA parameters in key %s", jwk.Kid)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case jwk.Kty == "EC" && jwk.X != "" && jwk.Y != "":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s in key %s", jwk.Crv, jwk.Kid)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode x coordinate of key %s: %v", jwk.Kid, err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode y coordinate of key %s: %v", jwk.Kid, err)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("invalid EC point in key %s", jwk.Kid)
+		}
+		return key, nil
+	case len(jwk.X5c) > 0:
+		der, err := base64.StdEncoding.DecodeString(jwk.X5c[0])
+		if err != nil {
+			return nil, fmt.Errorf("could not decode x5c of key %s: %v", jwk.Kid, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse certificate of key %s: %v", jwk.Kid, err)
+		}
+		return cert.PublicKey, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s of key %s", jwk.Kty, jwk.Kid)
+}
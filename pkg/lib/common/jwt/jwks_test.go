@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func createSigningCertPem(t *testing.T, key crypto.Signer) []byte {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "AAS JWT Signing Certificate"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestNewJSONWebKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 3072)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		key     crypto.Signer
+		wantKty string
+		wantAlg string
+	}{
+		{
+			name:    "Validate NewJSONWebKey with RSA signing certificate",
+			key:     rsaKey,
+			wantKty: "RSA",
+			wantAlg: "RS384",
+		},
+		{
+			name:    "Validate NewJSONWebKey with EC signing certificate",
+			key:     ecKey,
+			wantKty: "EC",
+			wantAlg: "ES384",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certPem := createSigningCertPem(t, tt.key)
+			keyDer, err := x509.MarshalPKCS8PrivateKey(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			jwk, err := NewJSONWebKey(certPem)
+			if err != nil {
+				t.Fatalf("NewJSONWebKey() error = %v", err)
+			}
+			if jwk.Kty != tt.wantKty || jwk.Alg != tt.wantAlg {
+				t.Errorf("NewJSONWebKey() kty = %s alg = %s, want %s %s", jwk.Kty, jwk.Alg, tt.wantKty, tt.wantAlg)
+			}
+
+			// the key id of the JSON web key must select the key in the verifier for tokens of the factory
+			factory, err := NewTokenFactory(keyDer, true, certPem, "AAS JWT Issuer", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := factory.Create(map[string]string{"name": "admin"}, "subject", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chainPem, err := jwk.CertificateChainPem()
+			if err != nil {
+				t.Fatalf("CertificateChainPem() error = %v", err)
+			}
+			verifier, err := NewVerifier(chainPem, nil, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = verifier.ValidateTokenAndGetClaims(token, &struct{}{}); err != nil {
+				t.Errorf("token could not be validated with the JSON web key %s: %v", jwk.Kid, err)
+			}
+		})
+	}
+
+	if _, err = NewJSONWebKey([]byte("Cert")); err == nil {
+		t.Error("NewJSONWebKey() should fail for an invalid certificate")
+	}
+}
+
+func TestJSONWebKey_PublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecJWK, err := NewJSONWebKey(createSigningCertPem(t, ecKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaJWK, err := NewJSONWebKey(createSigningCertPem(t, rsaKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x5cOnlyJWK := JSONWebKey{Kty: "RSA", Kid: rsaJWK.Kid, X5c: rsaJWK.X5c}
+	offCurveJWK := *ecJWK
+	offCurveJWK.Y = offCurveJWK.X
+
+	tests := []struct {
+		name    string
+		jwk     JSONWebKey
+		want    crypto.PublicKey
+		wantErr bool
+	}{
+		{
+			name: "Validate PublicKey with RSA key parameters",
+			jwk:  *rsaJWK,
+			want: rsaKey.Public(),
+		},
+		{
+			name: "Validate PublicKey with EC key parameters",
+			jwk:  *ecJWK,
+			want: ecKey.Public(),
+		},
+		{
+			name: "Validate PublicKey with certificate only",
+			jwk:  x5cOnlyJWK,
+			want: rsaKey.Public(),
+		},
+		{
+			name:    "Validate PublicKey with point not on curve",
+			jwk:     offCurveJWK,
+			wantErr: true,
+		},
+		{
+			name:    "Validate PublicKey with unsupported key type",
+			jwk:     JSONWebKey{Kty: "oct", Kid: "secret"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.jwk.PublicKey()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PublicKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !tt.want.(interface{ Equal(crypto.PublicKey) bool }).Equal(got) {
+				t.Errorf("PublicKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
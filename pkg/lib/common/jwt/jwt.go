@@ -10,7 +10,6 @@ import (
 	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/x509"
-	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
@@ -20,6 +19,7 @@ import (
 	"time"
 
 	"github.com/Waterdrips/jwt-go"
+	"github.com/google/uuid"
 )
 
 const (
@@ -118,9 +118,10 @@ type verifierKey struct {
 }
 
 type verifierPrivate struct {
-	expiration   time.Time
-	pubKeyMapMtx sync.RWMutex
-	pubKeyMap    map[string]verifierKey
+	expiration     time.Time
+	pubKeyMapMtx   sync.RWMutex
+	pubKeyMap      map[string]verifierKey
+	revocationList *RevocationList
 }
 
 type Verifier interface {
@@ -180,8 +181,7 @@ func NewTokenFactory(pkcs8der []byte, includeKeyIdInToken bool, signingCertPem [
 		if err != nil {
 			return nil, fmt.Errorf("NewTokenFactory: failed to parse certificate: " + err.Error())
 		}
-		hash, _ := crypt.GetHashData(cert.Raw, crypto.SHA1)
-		keyId = hex.EncodeToString(hash)
+		keyId, _ = KeyId(cert)
 
 	}
 
@@ -227,6 +227,8 @@ func (f *JwtFactory) Create(clms interface{}, subject string, validity time.Dura
 	jwtclaim.StandardClaims.ExpiresAt = now.Add(validity).Unix()
 	jwtclaim.StandardClaims.Issuer = f.issuer
 	jwtclaim.StandardClaims.Subject = subject
+	// the token id allows to revoke the token before it expires
+	jwtclaim.StandardClaims.Id = uuid.New().String()
 
 	jwtclaim.customClaims = clms
 	token := jwt.NewWithClaims(f.signingMethod, jwtclaim)
@@ -288,6 +290,10 @@ func (v *verifierPrivate) ValidateTokenAndGetClaims(tokenString string, customCl
 		}
 		return nil, err
 	}
+	if v.revocationList != nil && v.revocationList.IsRevoked(token.standardClaims.Id, token.standardClaims.Subject,
+		time.Unix(token.standardClaims.IssuedAt, 0)) {
+		return nil, &TokenRevokedError{Jti: token.standardClaims.Id, Subject: token.standardClaims.Subject}
+	}
 	token.jwtToken = parsedToken
 	// so far we have only got the standardClaims parsed. We need to now fill the customClaims
 
@@ -312,8 +318,14 @@ func (v *verifierPrivate) ValidateTokenAndGetClaims(tokenString string, customCl
 }
 
 func NewVerifier(signingCertPems interface{}, rootCAPems [][]byte, cacheTime time.Duration) (Verifier, error) {
+	return NewVerifierWithRevocationList(signingCertPems, rootCAPems, cacheTime, nil)
+}
+
+// NewVerifierWithRevocationList creates a verifier which also rejects the tokens of the revocation list
+func NewVerifierWithRevocationList(signingCertPems interface{}, rootCAPems [][]byte, cacheTime time.Duration,
+	revocationList *RevocationList) (Verifier, error) {
 
-	v := verifierPrivate{expiration: time.Now().Add(cacheTime)}
+	v := verifierPrivate{expiration: time.Now().Add(cacheTime), revocationList: revocationList}
 	v.pubKeyMap = make(map[string]verifierKey)
 
 	var certPemSlice [][]byte
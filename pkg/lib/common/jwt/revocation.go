@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package jwtauth
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type TokenRevokedError struct {
+	Jti     string
+	Subject string
+}
+
+func (e TokenRevokedError) Error() string {
+	return fmt.Sprintf("token is revoked. jti (token id) : %s, sub (subject) : %s", e.Jti, e.Subject)
+}
+
+// RevokedToken is an entry of the token revocation list. An entry with a jti (token id) revokes a single token, an
+// entry with a subject revokes all the tokens issued to the subject before the revocation.
+type RevokedToken struct {
+	Jti       string    `json:"jti,omitempty"`
+	Subject   string    `json:"sub,omitempty"`
+	RevokedAt time.Time `json:"revoked_at"`
+	// ExpiresAt is the time after which the revoked tokens are expired, the entry is not needed anymore after that
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TokenRevocations lists the tokens revoked since a point in time, it may repeat revocations already retrieved
+type TokenRevocations struct {
+	RevokedTokens []RevokedToken `json:"revoked_tokens"`
+	// UpdatedAt is the time of the revocation list, the revocations after it are retrieved by passing it as since
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RetrieveRevocationsFn retrieves the tokens revoked since a point in time, all of them for the zero time
+type RetrieveRevocationsFn func(since time.Time) (*TokenRevocations, error)
+
+// RevocationList is the denylist of revoked tokens checked by the verifier. It is kept up to date by retrieving the
+// new revocations once the refresh interval has elapsed.
+type RevocationList struct {
+	mtx      sync.RWMutex
+	jtis     map[string]time.Time
+	subjects map[string]RevokedToken
+
+	refreshMtx          sync.Mutex
+	fnRetrieve          RetrieveRevocationsFn
+	refreshInterval     time.Duration
+	lastRefreshAttempt  time.Time
+	revocationsUpdateAt time.Time
+}
+
+func NewRevocationList(fnRetrieve RetrieveRevocationsFn, refreshInterval time.Duration) *RevocationList {
+	return &RevocationList{
+		jtis:            make(map[string]time.Time),
+		subjects:        make(map[string]RevokedToken),
+		fnRetrieve:      fnRetrieve,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// Add adds the revoked tokens to the list, the revoked tokens already in the list are deduped by jti and subject
+func (rl *RevocationList) Add(revokedTokens ...RevokedToken) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	for _, revokedToken := range revokedTokens {
+		if revokedToken.Jti != "" {
+			rl.jtis[revokedToken.Jti] = revokedToken.ExpiresAt
+		}
+		if revokedToken.Subject != "" {
+			if existing, ok := rl.subjects[revokedToken.Subject]; !ok || existing.RevokedAt.Before(revokedToken.RevokedAt) {
+				rl.subjects[revokedToken.Subject] = revokedToken
+			}
+		}
+	}
+}
+
+// IsRevoked checks if the token with the jti (token id), issued to the subject at issuedAt, is revoked
+func (rl *RevocationList) IsRevoked(jti, subject string, issuedAt time.Time) bool {
+	rl.mtx.RLock()
+	defer rl.mtx.RUnlock()
+
+	if _, ok := rl.jtis[jti]; ok && jti != "" {
+		return true
+	}
+	if revokedToken, ok := rl.subjects[subject]; ok && subject != "" {
+		// tokens are issued with an issued at time in the past to allow for clock skew
+		return issuedAt.Add(gracePeriodForClockSkew).Before(revokedToken.RevokedAt)
+	}
+	return false
+}
+
+// Refresh retrieves the tokens revoked since the last refresh and removes the expired entries from the list
+func (rl *RevocationList) Refresh() error {
+	rl.refreshMtx.Lock()
+	defer rl.refreshMtx.Unlock()
+
+	return rl.refresh()
+}
+
+// RefreshIfStale refreshes the list if the refresh interval has elapsed since the last refresh attempt
+func (rl *RevocationList) RefreshIfStale() error {
+	rl.refreshMtx.Lock()
+	defer rl.refreshMtx.Unlock()
+
+	if time.Since(rl.lastRefreshAttempt) < rl.refreshInterval {
+		return nil
+	}
+	return rl.refresh()
+}
+
+func (rl *RevocationList) refresh() error {
+	if rl.fnRetrieve == nil {
+		return nil
+	}
+	rl.lastRefreshAttempt = time.Now()
+	revocations, err := rl.fnRetrieve(rl.revocationsUpdateAt)
+	if err != nil {
+		return fmt.Errorf("could not retrieve token revocations: %v", err)
+	}
+	rl.Add(revocations.RevokedTokens...)
+	rl.revocationsUpdateAt = revocations.UpdatedAt
+
+	now := time.Now()
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+	for jti, expiresAt := range rl.jtis {
+		if now.After(expiresAt) {
+			delete(rl.jtis, jti)
+		}
+	}
+	for subject, revokedToken := range rl.subjects {
+		if now.After(revokedToken.ExpiresAt) {
+			delete(rl.subjects, subject)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Waterdrips/jwt-go"
+)
+
+func TestRevocationList_IsRevoked(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	rl := NewRevocationList(nil, time.Minute)
+	rl.Add(RevokedToken{Jti: "revoked-jti", RevokedAt: now, ExpiresAt: now.Add(time.Hour)},
+		RevokedToken{Subject: "revoked-user", RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+
+	tests := []struct {
+		name     string
+		jti      string
+		subject  string
+		issuedAt time.Time
+		want     bool
+	}{
+		{
+			name:     "Validate IsRevoked with revoked jti",
+			jti:      "revoked-jti",
+			subject:  "user",
+			issuedAt: now.Add(-gracePeriodForClockSkew),
+			want:     true,
+		},
+		{
+			name:     "Validate IsRevoked with token issued to revoked subject before revocation",
+			jti:      "jti",
+			subject:  "revoked-user",
+			issuedAt: now.Add(-time.Minute - gracePeriodForClockSkew),
+			want:     true,
+		},
+		{
+			name:     "Validate IsRevoked with token issued to revoked subject after revocation",
+			jti:      "jti",
+			subject:  "revoked-user",
+			issuedAt: now.Add(time.Second - gracePeriodForClockSkew),
+			want:     false,
+		},
+		{
+			name:     "Validate IsRevoked with token not revoked",
+			jti:      "jti",
+			subject:  "user",
+			issuedAt: now.Add(-gracePeriodForClockSkew),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rl.IsRevoked(tt.jti, tt.subject, tt.issuedAt); got != tt.want {
+				t.Errorf("RevocationList.IsRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevocationList_RefreshIfStale(t *testing.T) {
+	now := time.Now()
+	var retrievedSince []time.Time
+	revocations := []*TokenRevocations{
+		{
+			RevokedTokens: []RevokedToken{
+				{Jti: "revoked-jti", RevokedAt: now, ExpiresAt: now.Add(time.Hour)},
+				{Jti: "expired-jti", RevokedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)},
+			},
+			UpdatedAt: now,
+		},
+		{
+			// the revocations retrieved again are deduped
+			RevokedTokens: []RevokedToken{
+				{Jti: "revoked-jti", RevokedAt: now, ExpiresAt: now.Add(time.Hour)},
+				{Jti: "new-jti", RevokedAt: now, ExpiresAt: now.Add(time.Hour)},
+			},
+			UpdatedAt: now.Add(time.Second),
+		},
+	}
+	rl := NewRevocationList(func(since time.Time) (*TokenRevocations, error) {
+		retrievedSince = append(retrievedSince, since)
+		if len(retrievedSince) > len(revocations) {
+			return nil, errors.New("revocations not available")
+		}
+		return revocations[len(retrievedSince)-1], nil
+	}, time.Hour)
+
+	if err := rl.RefreshIfStale(); err != nil {
+		t.Fatalf("RevocationList.RefreshIfStale() error = %v", err)
+	}
+	if !rl.IsRevoked("revoked-jti", "", now) || rl.IsRevoked("expired-jti", "", now) {
+		t.Error("RevocationList.RefreshIfStale() should add the revoked tokens and remove the expired ones")
+	}
+
+	// the list is not refreshed again before the refresh interval has elapsed
+	if err := rl.RefreshIfStale(); err != nil || len(retrievedSince) != 1 {
+		t.Errorf("RevocationList.RefreshIfStale() retrieved the revocations %d times, want 1", len(retrievedSince))
+	}
+
+	if err := rl.Refresh(); err != nil {
+		t.Fatalf("RevocationList.Refresh() error = %v", err)
+	}
+	if !retrievedSince[1].Equal(now) {
+		t.Errorf("RevocationList.Refresh() retrieved revocations since %v, want %v", retrievedSince[1], now)
+	}
+	if !rl.IsRevoked("new-jti", "", now) || !rl.IsRevoked("revoked-jti", "", now) || len(rl.jtis) != 2 {
+		t.Error("RevocationList.Refresh() should add the new revoked tokens once")
+	}
+
+	if err := rl.Refresh(); err == nil {
+		t.Error("RevocationList.Refresh() should fail when the revocations could not be retrieved")
+	}
+}
+
+func TestVerifier_ValidateTokenAndGetClaims_Revoked(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPem := createSigningCertPem(t, key)
+	factory, err := NewTokenFactory(keyDer, true, certPem, "AAS JWT Issuer", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenString, err := factory.Create(map[string]string{"name": "admin"}, "subject", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rl := NewRevocationList(nil, time.Minute)
+	verifier, err := NewVerifierWithRevocationList(certPem, nil, time.Minute, rl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := verifier.ValidateTokenAndGetClaims(tokenString, &map[string]string{})
+	if err != nil {
+		t.Fatalf("ValidateTokenAndGetClaims() error = %v", err)
+	}
+	if token.GetStandardClaims().(*jwt.StandardClaims).Id == "" {
+		t.Error("token should have a jti (token id)")
+	}
+
+	rl.Add(RevokedToken{Jti: token.GetStandardClaims().(*jwt.StandardClaims).Id, ExpiresAt: time.Now().Add(time.Minute)})
+	_, err = verifier.ValidateTokenAndGetClaims(tokenString, &map[string]string{})
+	if _, ok := err.(*TokenRevokedError); !ok {
+		t.Errorf("ValidateTokenAndGetClaims() error = %v, want TokenRevokedError", err)
+	}
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+// metrics package exposes the metrics registered with the default Prometheus registry on the /metrics endpoint of
+// the services
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
+	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPath is the path on which the metrics are exposed, at the root of the service router
+const MetricsPath = "/metrics"
+
+var defaultLog = commLog.GetDefaultLogger()
+
+// SetMetricsRoutes registers the /metrics endpoint on the router when it is enabled in the server configuration. The
+// endpoint does not require authentication so that it can be scraped by Prometheus.
+func SetMetricsRoutes(router *mux.Router, cfg config.ServerConfig) *mux.Router {
+	defaultLog.Trace("metrics/metrics:SetMetricsRoutes() Entering")
+	defer defaultLog.Trace("metrics/metrics:SetMetricsRoutes() Leaving")
+
+	if !cfg.MetricsEnabled {
+		return router
+	}
+	defaultLog.Info("metrics/metrics:SetMetricsRoutes() Exposing metrics on ", MetricsPath)
+	router.Handle(MetricsPath, promhttp.Handler()).Methods(http.MethodGet)
+	return router
+}
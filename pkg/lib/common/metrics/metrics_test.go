@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
+)
+
+func TestSetMetricsRoutes(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		wantStatus int
+	}{
+		{name: "Metrics enabled", enabled: true, wantStatus: http.StatusOK},
+		{name: "Metrics disabled", enabled: false, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := SetMetricsRoutes(mux.NewRouter(), config.ServerConfig{MetricsEnabled: tt.enabled})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("SetMetricsRoutes() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.enabled && !strings.Contains(w.Body.String(), "# TYPE go_goroutines gauge") {
+				t.Errorf("SetMetricsRoutes() response is not in Prometheus text format: %s", w.Body.String())
+			}
+		})
+	}
+}
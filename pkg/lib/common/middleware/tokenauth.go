@@ -26,14 +26,23 @@ var log = clog.GetDefaultLogger()
 var slog = clog.GetSecurityLogger()
 
 func InitJwtVerifier(signingCertsDir, trustedCAsDir string, cacheTime time.Duration) (jwtauth.Verifier, error) {
+	var err error
+	jwtVerifier, err = initJwtVerifier(signingCertsDir, trustedCAsDir, cacheTime, nil)
+	return jwtVerifier, err
+}
+
+// initJwtVerifier creates a verifier with the signing certificates and the trusted CAs of the directories, which also
+// checks the revocation list if not nil
+func initJwtVerifier(signingCertsDir, trustedCAsDir string, cacheTime time.Duration,
+	revocationList *jwtauth.RevocationList) (jwtauth.Verifier, error) {
 
 	certPems, err := cos.GetDirFileContents(signingCertsDir, "*.pem")
 
 	rootPems, err := cos.GetDirFileContents(trustedCAsDir, "*.pem")
 
-	jwtVerifier, err = jwtauth.NewVerifier(certPems, rootPems, cacheTime)
+	verifier, err := jwtauth.NewVerifierWithRevocationList(certPems, rootPems, cacheTime, revocationList)
 
-	return jwtVerifier, err
+	return verifier, err
 }
 
 func retrieveAndSaveTrustedJwtSigningCerts() error {
@@ -49,7 +58,21 @@ func retrieveAndSaveTrustedJwtSigningCerts() error {
 
 type RetrieveJwtCertFn func() error
 
+// NewTokenAuthWithRevocationList is NewTokenAuth which also rejects the tokens revoked in AAS. The revocation list is
+// refreshed on the requests once its refresh interval has elapsed.
+func NewTokenAuthWithRevocationList(signingCertsDir, trustedCAsDir string, fnGetJwtCerts RetrieveJwtCertFn,
+	cacheTime time.Duration, revocationList *jwtauth.RevocationList) mux.MiddlewareFunc {
+	return newTokenAuth(signingCertsDir, trustedCAsDir, fnGetJwtCerts, cacheTime, revocationList)
+}
+
 func NewTokenAuth(signingCertsDir, trustedCAsDir string, fnGetJwtCerts RetrieveJwtCertFn, cacheTime time.Duration) mux.MiddlewareFunc {
+	return newTokenAuth(signingCertsDir, trustedCAsDir, fnGetJwtCerts, cacheTime, nil)
+}
+
+// newTokenAuth returns the token authentication middleware, each middleware having its own verifier
+func newTokenAuth(signingCertsDir, trustedCAsDir string, fnGetJwtCerts RetrieveJwtCertFn, cacheTime time.Duration,
+	revocationList *jwtauth.RevocationList) mux.MiddlewareFunc {
+	var verifier jwtauth.Verifier
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -63,6 +86,12 @@ func NewTokenAuth(signingCertsDir, trustedCAsDir string, fnGetJwtCerts RetrieveJ
 				return
 			}
 
+			if revocationList != nil {
+				if err := revocationList.RefreshIfStale(); err != nil {
+					log.WithError(err).Warn("failed to refresh token revocation list")
+				}
+			}
+
 			// the second item in the slice should be the jwtToken. let try to validate
 			claims := ct.AuthClaims{}
 			var token *jwtauth.Token
@@ -76,18 +105,20 @@ func NewTokenAuth(signingCertsDir, trustedCAsDir string, fnGetJwtCerts RetrieveJ
 			//     2. There are no valid certificates (maybe all are expired) and we need to call the function that retrieves
 			//        a new certificate. initJwtVerifier takes care of this scenario.
 
-			for needInit, retryNeeded, looped := jwtVerifier == nil, false, false; retryNeeded || !looped; looped = true {
+			for needInit, retryNeeded, looped := verifier == nil, false, false; retryNeeded || !looped; looped = true {
 
 				if needInit || retryNeeded {
-					if _, initErr := InitJwtVerifier(signingCertsDir, trustedCAsDir, cacheTime); initErr != nil {
+					newVerifier, initErr := initJwtVerifier(signingCertsDir, trustedCAsDir, cacheTime, revocationList)
+					if initErr != nil {
 						log.WithError(initErr).Error("attempt to initialize jwt verifier failed")
 						w.WriteHeader(http.StatusInternalServerError)
 						return
 					}
+					verifier = newVerifier
 					needInit = false
 				}
 				retryNeeded = false
-				token, err = jwtVerifier.ValidateTokenAndGetClaims(strings.TrimSpace(splitAuthHeader[1]), &claims)
+				token, err = verifier.ValidateTokenAndGetClaims(strings.TrimSpace(splitAuthHeader[1]), &claims)
 				if err != nil && !looped {
 					switch err.(type) {
 					case *jwtauth.MatchingCertNotFoundError, *jwtauth.MatchingCertJustExpired:
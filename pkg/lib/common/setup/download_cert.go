@@ -88,7 +88,7 @@ func (dc *DownloadCert) Run() error {
 		}
 	}
 	printToWriter(dc.ConsoleWriter, dc.commandName, "Start downloading certificate")
-	key, cert, err := getCertificateFromCMS(dc.CertType, dc.KeyAlgorithm, dc.KeyLength, dc.CmsBaseURL, dc.Subject, dc.SanList, dc.CaCertDirPath, dc.BearerToken, dc.Client)
+	key, cert, err := GetCertificateFromCMS(dc.CertType, dc.KeyAlgorithm, dc.KeyLength, dc.CmsBaseURL, dc.Subject, dc.SanList, dc.CaCertDirPath, dc.BearerToken, dc.Client)
 	if err != nil {
 		printToWriter(dc.ConsoleWriter, dc.commandName, "Failed to download certificate")
 		return err
@@ -150,7 +150,9 @@ func (t *DownloadCert) SetName(n, e string) {
 	t.envPrefix = PrefixUnderscroll(e)
 }
 
-func getCertificateFromCMS(certType string, keyAlg string, keyLen int, cmsBaseUrl string, subject pkix.Name, hosts string, CaCertDirPath string, bearerToken string, client HttpClient) (key []byte, cert []byte, err error) {
+// GetCertificateFromCMS creates a new key pair and requests the certificate of the certificate type for it from CMS,
+// it returns the PKCS8 DER private key and the PEM certificate
+func GetCertificateFromCMS(certType string, keyAlg string, keyLen int, cmsBaseUrl string, subject pkix.Name, hosts string, CaCertDirPath string, bearerToken string, client HttpClient) (key []byte, cert []byte, err error) {
 	//TODO: use CertType for TLS or Signing cert
 	csrData, key, err := crypt.CreateKeyPairAndCertificateRequest(subject, hosts, keyAlg, keyLen)
 	if err != nil {
@@ -4,9 +4,6 @@
  */
 package tpmprovider
 
-// #include "tpm.h"
-import "C"
-
 import (
 	"github.com/stretchr/testify/mock"
 )
@@ -31,9 +28,9 @@ func (mockedTpm MockedTpmProvider) Close() {
 	return
 }
 
-func (mockedTpm MockedTpmProvider) Version() C.TPM_VERSION {
+func (mockedTpm MockedTpmProvider) Version() TpmVersion {
 	args := mockedTpm.Called()
-	return args.Get(0).(C.TPM_VERSION)
+	return args.Get(0).(TpmVersion)
 }
 
 func (mockedTpm MockedTpmProvider) TakeOwnership(ownerSecretKey, endorsementSecretKey string) error {
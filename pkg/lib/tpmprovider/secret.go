@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package tpmprovider
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	INVALID_OWNER_SECRET_KEY       = "Invalid owner secret key"
+	INVALID_ENDORSEMENT_SECRET_KEY = "Invalid endorsement secret key"
+	INVALID_AIK_SECRET_KEY         = "Invalid aik secret key"
+)
+
+func validateAndConvertKey(key string) ([]byte, error) {
+
+	var keyBytes []byte
+	var err error
+
+	// See if the key is a 'legacy' trust-agent password (40 characters
+	// in hex format).  If so, convert it to bytes.  This is needed for
+	// backward compatibility and carrying forward existing secrets during
+	// an upgrade.
+	//
+	// Otherwise, use what was provided, including the definition of 'hex:'
+	// passwords.
+	if len(key) == 40 {
+		keyBytes, err = hex.DecodeString(key)
+		if err != nil {
+			// not a legacy secret
+			keyBytes = nil
+		}
+	}
+
+	if keyBytes == nil {
+		// tpm2-tools supports the use of 'hex' passwords.  Follow suit
+		// and convert passwords with a leading 'hex:' string to raw
+		// bytes.
+		if strings.HasPrefix(key, HEX_PREFIX) {
+			keyBytes, err = hex.DecodeString(strings.ReplaceAll(key, HEX_PREFIX, ""))
+			if err != nil {
+				return nil, errors.Wrap(err, "'hex:' was provided but could not be parsed")
+			}
+		} else {
+			keyBytes = []byte(key)
+		}
+	}
+
+	// The tss library uses TP2B_AUTH structure for passwords (containing a length and
+	// fixed length buffer).  The tpm-provider uses zero-copy to pass the passwords
+	// into underlying C code.  If the password wasn't provided, return an array that contains
+	// a single zero (to avoid a null pointer).  When passed to the C code, the TPM2B_AUTH
+	// will still be an empty password (null terminated).
+	if len(keyBytes) == 0 {
+		keyBytes = []byte{0}
+	} else if len(keyBytes) > 64 {
+		return nil, errors.New("The secret cannot exceed 64 bytes in length")
+	}
+
+	return keyBytes, nil
+}
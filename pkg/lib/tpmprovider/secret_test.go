@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tpmprovider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The secret key conversion does not use the TPM, these tests do not need the TPM simulator and can be run with
+// 'go test -tags gotpm -run TestValidateAndConvertKey ./pkg/lib/tpmprovider/'
+func TestValidateAndConvertKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "Validate legacy 40 characters hex secret is decoded",
+			key:  OwnerSecretKey,
+			want: []byte{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0xbe, 0xef,
+				0xde, 0xad, 0xbe, 0xef},
+		},
+		{
+			name: "Validate 40 characters non hex secret is used as is",
+			key:  strings.Repeat("z", 40),
+			want: []byte(strings.Repeat("z", 40)),
+		},
+		{
+			name: "Validate 'hex:' secret is decoded",
+			key:  HexSecretKey,
+			want: []byte{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0xbe, 0xef,
+				0xde, 0xad, 0xbe, 0xef},
+		},
+		{
+			name: "Validate short 'hex:' secret is decoded",
+			key:  "hex:00ff10",
+			want: []byte{0x00, 0xff, 0x10},
+		},
+		{
+			name:    "Validate invalid 'hex:' secret fails",
+			key:     "hex:xyz",
+			wantErr: true,
+		},
+		{
+			name: "Validate password is used as is",
+			key:  SimpleSecretKey,
+			want: []byte("mypassword"),
+		},
+		{
+			name: "Validate empty secret is a single zero",
+			key:  "",
+			want: []byte{0},
+		},
+		{
+			name: "Validate empty 'hex:' secret is a single zero",
+			key:  HEX_PREFIX,
+			want: []byte{0},
+		},
+		{
+			name: "Validate 64 bytes secret is accepted",
+			key:  HEX_PREFIX + strings.Repeat("ab", 64),
+			want: []byte(strings.Repeat("\xab", 64)),
+		},
+		{
+			name:    "Validate secret longer than 64 bytes fails",
+			key:     strings.Repeat("a", 65),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateAndConvertKey(tt.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,1245 @@
+//go:build gotpm || !cgo
+// +build gotpm !cgo
+
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package tpmprovider
+
+//
+// tpm20Go implements the TpmProvider with the go TPM 2.0 stack (github.com/google/go-tpm) in place
+// of TSS2, so that the go-trust-agent and workload-agent can be built without cgo and the native tpm2-tss
+// libraries (ex. when cross-compiling).  It is selected with the 'gotpm' build tag and is the only
+// implementation available when cgo is disabled.  The TPM commands and their parameters are the same as
+// the ones of the c code (tpm20linux.c, aik.c, quote.c...), so the TPMs provisioned by both implementations
+// are compatible.
+//
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+	"github.com/google/go-tpm/tpm2/transport/linuxtpm"
+	"github.com/google/go-tpm/tpm2/transport/tcp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// see 'TCG EK Credential Profile', the EK template and nonce are provisioned by some TPM vendors
+	nvIdxRsaEkNonce    = 0x1c00003
+	nvIdxRsaEkTemplate = 0x1c00004
+
+	// the handles of the nv indexes in 'TCG EK Credential Profile' that are looked up for the EK template
+	capabilityHandleStart = 0x01C00000
+	capabilityHandleCount = 5
+
+	// maximum size of the nv data read/written by a single command
+	nvBufferSize = 512
+
+	// pcrs 0-23 are selected in the quotes (tss2 does not support 32 bits of pcrs)
+	pcrSelectSize = 3
+	maxPcrCount   = 24
+
+	defaultMsSimHost = "localhost"
+	defaultMsSimPort = 2321
+)
+
+var (
+	// qualifying data of the certification of the signing/binding keys by the AIK
+	certifiedKeyQualifyingData = []byte{0x00, 0xff, 0x55, 0xaa}
+
+	// label used by WLS when encrypting data with the binding key
+	unbindLabel = []byte("TPM2\x00")
+)
+
+type goTpmFactory struct {
+	TpmFactory
+	tctiType uint32
+	conf     string
+}
+
+// newTpmFactory creates the TpmFactory that uses the go TPM 2.0 stack with the 'tctiType' transport
+func newTpmFactory(tctiType uint32, conf string) TpmFactory {
+	return goTpmFactory{tctiType: tctiType, conf: conf}
+}
+
+func (goImpl goTpmFactory) NewTpmProvider() (TpmProvider, error) {
+	var tpm transport.TPMCloser
+	var err error
+
+	switch goImpl.tctiType {
+	case TCTI_DEVICE:
+		tpm, err = linuxtpm.Open(goImpl.conf)
+	case TCTI_MSSIM:
+		var config *tcp.Config
+		config, err = parseMsSimConf(goImpl.conf)
+		if err != nil {
+			return nil, err
+		}
+		tpm, err = tcp.Open(*config)
+	default:
+		return nil, errors.Errorf("Incorrect tcti type: %d", goImpl.tctiType)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not create tpm context")
+	}
+
+	return &tpm20Go{tpm: tpm}, nil
+}
+
+// parseMsSimConf converts the tss2 mssim tcti configuration ('host=localhost,port=2321') to the
+// addresses of the command and platform ports of the MS simulator.
+func parseMsSimConf(conf string) (*tcp.Config, error) {
+	host := defaultMsSimHost
+	port := defaultMsSimPort
+
+	for _, param := range strings.Split(conf, ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		switch keyValue[0] {
+		case "host":
+			host = keyValue[1]
+		case "port":
+			var err error
+			port, err = strconv.Atoi(keyValue[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "Invalid mssim port '%s'", keyValue[1])
+			}
+		}
+	}
+
+	return &tcp.Config{
+		CommandAddress:  net.JoinHostPort(host, strconv.Itoa(port)),
+		PlatformAddress: net.JoinHostPort(host, strconv.Itoa(port+1)),
+	}, nil
+}
+
+type tpm20Go struct {
+	tpm transport.TPMCloser
+}
+
+func (t *tpm20Go) Close() {
+	if t.tpm != nil {
+		t.tpm.Close()
+		t.tpm = nil
+	}
+}
+
+func (t *tpm20Go) Version() TpmVersion {
+	return V20
+}
+
+func (t *tpm20Go) TakeOwnership(ownerSecretKey, endorsementSecretKey string) error {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	endorsementSecretKeyBytes, err := validateAndConvertKey(endorsementSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_ENDORSEMENT_SECRET_KEY)
+	}
+
+	// the TPM must not be owned, the lockout hierarchy uses the owner secret
+	hierarchies := []struct {
+		handle    tpm2.TPMHandle
+		secretKey []byte
+	}{
+		{tpm2.TPMRHOwner, ownerSecretKeyBytes},
+		{tpm2.TPMRHEndorsement, endorsementSecretKeyBytes},
+		{tpm2.TPMRHLockout, ownerSecretKeyBytes},
+	}
+
+	for _, hierarchy := range hierarchies {
+		err = t.changeAuth(hierarchy.handle, nil, hierarchy.secretKey)
+		if err != nil {
+			return errors.Wrapf(err, "TakeOwnership failed to change the auth of hierarchy 0x%X", uint32(hierarchy.handle))
+		}
+	}
+
+	return nil
+}
+
+func (t *tpm20Go) IsOwnedWithAuth(ownerSecretKey string) (bool, error) {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return false, errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	// changing the owner auth to the same value succeeds only with the owner secret
+	err = t.changeAuth(tpm2.TPMRHOwner, ownerSecretKeyBytes, ownerSecretKeyBytes)
+	if err == nil {
+		return true, nil
+	} else if errors.Is(err, tpm2.TPMRCBadAuth) || errors.Is(err, tpm2.TPMRCAuthFail) {
+		return false, nil
+	}
+
+	return false, errors.Wrap(err, "IsOwnedWithAuth failed to change the owner auth")
+}
+
+func (t *tpm20Go) changeAuth(hierarchy tpm2.TPMHandle, oldSecretKey, newSecretKey []byte) error {
+	_, err := tpm2.HierarchyChangeAuth{
+		AuthHandle: tpm2.AuthHandle{Handle: hierarchy, Auth: tpm2.PasswordAuth(oldSecretKey)},
+		NewAuth:    tpm2.TPM2BAuth{Buffer: newSecretKey},
+	}.Execute(t.tpm)
+	return err
+}
+
+func (t *tpm20Go) GetAikBytes() ([]byte, error) {
+
+	aikPublic, err := t.readPublic(TPM_HANDLE_AIK)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetAikBytes failed to read the AIK")
+	}
+
+	return rsaModulus(&aikPublic.OutPublic)
+}
+
+func (t *tpm20Go) GetAikName() ([]byte, error) {
+
+	aikPublic, err := t.readPublic(TPM_HANDLE_AIK)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetAikName failed to read the AIK")
+	}
+
+	if len(aikPublic.Name.Buffer) == 0 {
+		return nil, errors.New("The buffer size is incorrect")
+	}
+
+	return aikPublic.Name.Buffer, nil
+}
+
+func (t *tpm20Go) CreateAik(ownerSecretKey, endorsementSecretKey string) error {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	endorsementSecretKeyBytes, err := validateAndConvertKey(endorsementSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_ENDORSEMENT_SECRET_KEY)
+	}
+
+	err = t.evictIfExists(ownerSecretKeyBytes, TPM_HANDLE_AIK)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateAik")
+	}
+
+	ek, err := t.namedHandle(TPM_HANDLE_EK)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateAik: Could not read the EK")
+	}
+
+	aikTemplate := tpm2.TPMTPublic{
+		Type:    tpm2.TPMAlgRSA,
+		NameAlg: tpm2.TPMAlgSHA256,
+		ObjectAttributes: tpm2.TPMAObject{
+			Restricted:          true,
+			UserWithAuth:        true,
+			SignEncrypt:         true,
+			FixedTPM:            true,
+			FixedParent:         true,
+			SensitiveDataOrigin: true,
+		},
+		Parameters: tpm2.NewTPMUPublicParms(tpm2.TPMAlgRSA, &tpm2.TPMSRSAParms{
+			Symmetric: tpm2.TPMTSymDefObject{Algorithm: tpm2.TPMAlgNull},
+			Scheme: tpm2.TPMTRSAScheme{
+				Scheme:  tpm2.TPMAlgRSASSA,
+				Details: tpm2.NewTPMUAsymScheme(tpm2.TPMAlgRSASSA, &tpm2.TPMSSigSchemeRSASSA{HashAlg: tpm2.TPMAlgSHA256}),
+			},
+			KeyBits: 2048,
+		}),
+		Unique: tpm2.NewTPMUPublicID(tpm2.TPMAlgRSA, &tpm2.TPM2BPublicKeyRSA{}),
+	}
+
+	aik, err := tpm2.Create{
+		ParentHandle: tpm2.AuthHandle{Handle: ek.Handle, Name: ek.Name, Auth: ekPolicy(endorsementSecretKeyBytes)},
+		InPublic:     tpm2.New2B(aikTemplate),
+	}.Execute(t.tpm)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateAik: Could not create the AIK")
+	}
+
+	loadedAik, err := tpm2.Load{
+		ParentHandle: tpm2.AuthHandle{Handle: ek.Handle, Name: ek.Name, Auth: ekPolicy(endorsementSecretKeyBytes)},
+		InPrivate:    aik.OutPrivate,
+		InPublic:     aik.OutPublic,
+	}.Execute(t.tpm)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateAik: Could not load the AIK")
+	}
+
+	defer t.flush(loadedAik.ObjectHandle)
+
+	err = t.evictControl(ownerSecretKeyBytes, tpm2.NamedHandle{Handle: loadedAik.ObjectHandle, Name: loadedAik.Name}, TPM_HANDLE_AIK)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateAik: Could not persist the AIK")
+	}
+
+	return nil
+}
+
+// getPcrSelection converts the pcr banks and pcrs to the TPML_PCR_SELECTION of the quotes
+// (see getPcrSelectionBytes in tpm20linux.go).
+func getPcrSelection(pcrBanks []string, pcrs []int) (*tpm2.TPMLPCRSelection, error) {
+
+	pcrSelection := tpm2.TPMLPCRSelection{}
+
+	for _, bank := range pcrBanks {
+		var hash tpm2.TPMIAlgHash
+
+		switch bank {
+		case "SHA1":
+			hash = tpm2.TPMAlgSHA1
+		case "SHA256":
+			hash = tpm2.TPMAlgSHA256
+		case "SHA384":
+			hash = tpm2.TPMAlgSHA384
+		default:
+			return nil, fmt.Errorf("Invalid pcr bank type: %s", bank)
+		}
+
+		pcrSelect := make([]byte, pcrSelectSize)
+		for _, pcr := range pcrs {
+			if pcr < 0 || pcr > 31 {
+				return nil, fmt.Errorf("Invalid pcr value: %d", pcr)
+			}
+
+			if pcr < maxPcrCount {
+				pcrSelect[pcr/8] |= 1 << uint(pcr%8)
+			}
+		}
+
+		pcrSelection.PCRSelections = append(pcrSelection.PCRSelections, tpm2.TPMSPCRSelection{
+			Hash:      hash,
+			PCRSelect: pcrSelect,
+		})
+	}
+
+	return &pcrSelection, nil
+}
+
+// readPcrs collects the measurements of the selected pcrs, the TPM returns up to 8 pcrs
+// per PCR_Read.
+func (t *tpm20Go) readPcrs(pcrSelection *tpm2.TPMLPCRSelection) ([][]byte, error) {
+
+	var measurements [][]byte
+
+	remaining := tpm2.TPMLPCRSelection{}
+	for _, selection := range pcrSelection.PCRSelections {
+		remaining.PCRSelections = append(remaining.PCRSelections, tpm2.TPMSPCRSelection{
+			Hash:      selection.Hash,
+			PCRSelect: append([]byte{}, selection.PCRSelect...),
+		})
+	}
+
+	for count := 0; count < maxPcrCount && !pcrSelectionEmpty(&remaining); count++ {
+		pcrRead, err := tpm2.PCRRead{PCRSelectionIn: remaining}.Execute(t.tpm)
+		if err != nil {
+			return nil, err
+		}
+
+		// an inactive bank is not returned by the TPM
+		if pcrSelectionEmpty(&pcrRead.PCRSelectionOut) {
+			break
+		}
+
+		for _, digest := range pcrRead.PCRValues.Digests {
+			measurements = append(measurements, digest.Buffer)
+		}
+
+		for _, selectionOut := range pcrRead.PCRSelectionOut.PCRSelections {
+			for _, selection := range remaining.PCRSelections {
+				if selection.Hash != selectionOut.Hash {
+					continue
+				}
+
+				for i := 0; i < len(selection.PCRSelect) && i < len(selectionOut.PCRSelect); i++ {
+					selection.PCRSelect[i] &^= selectionOut.PCRSelect[i]
+				}
+			}
+		}
+	}
+
+	return measurements, nil
+}
+
+func pcrSelectionEmpty(pcrSelection *tpm2.TPMLPCRSelection) bool {
+	for _, selection := range pcrSelection.PCRSelections {
+		for _, pcrSelect := range selection.PCRSelect {
+			if pcrSelect != 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// GetTpmQuote returns the quote in the format expected by HVS (see CreateQuoteBuffer in quote.c)...
+//   - 2 byte (big endian) size of the TPMS_ATTEST followed by the TPMS_ATTEST
+//   - 2 byte signature algorithm, 2 byte hash algorithm and 2 byte size of the signature
+//     followed by the signature
+//   - the pcr measurements of the selected banks/pcrs
+func (t *tpm20Go) GetTpmQuote(nonce []byte, pcrBanks []string, pcrs []int) ([]byte, error) {
+
+	pcrSelection, err := getPcrSelection(pcrBanks, pcrs)
+	if err != nil {
+		return nil, err
+	}
+
+	aik, err := t.namedHandle(TPM_HANDLE_AIK)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetTpmQuote failed to read the AIK")
+	}
+
+	quote, err := tpm2.Quote{
+		SignHandle:     tpm2.AuthHandle{Handle: aik.Handle, Name: aik.Name, Auth: tpm2.PasswordAuth(nil)},
+		QualifyingData: tpm2.TPM2BData{Buffer: nonce},
+		InScheme:       tpm2.TPMTSigScheme{Scheme: tpm2.TPMAlgNull},
+		PCRSelect:      *pcrSelection,
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetTpmQuote failed to quote the pcrs")
+	}
+
+	signature, err := quote.Signature.Signature.RSASSA()
+	if err != nil {
+		return nil, errors.Wrap(err, "GetTpmQuote received an unexpected signature")
+	}
+
+	measurements, err := t.readPcrs(pcrSelection)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetTpmQuote failed to read the pcrs")
+	}
+
+	quoted := quote.Quoted.Bytes()
+	if len(quoted) == 0 || len(signature.Sig.Buffer) == 0 {
+		return nil, errors.New("The quote buffer size is incorrect")
+	}
+
+	// the pcr measurements are allocated from the selection (missing measurements are zeros)
+	pcrsSize := 0
+	for _, selection := range pcrSelection.PCRSelections {
+		hash, err := selection.Hash.Hash()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pcrSelect := range selection.PCRSelect {
+			for ; pcrSelect != 0; pcrSelect &= pcrSelect - 1 {
+				pcrsSize += hash.Size()
+			}
+		}
+	}
+
+	buffer := bytes.Buffer{}
+	binary.Write(&buffer, binary.BigEndian, uint16(len(quoted)))
+	buffer.Write(quoted)
+	binary.Write(&buffer, binary.BigEndian, uint16(quote.Signature.SigAlg))
+	binary.Write(&buffer, binary.BigEndian, uint16(signature.Hash))
+	binary.Write(&buffer, binary.BigEndian, uint16(len(signature.Sig.Buffer)))
+	buffer.Write(signature.Sig.Buffer)
+
+	pcrsBuffer := make([]byte, pcrsSize)
+	offset := 0
+	for _, measurement := range measurements {
+		if offset+len(measurement) > len(pcrsBuffer) {
+			return nil, errors.Errorf("Invalid pcr measurement size 0x%x", len(measurement))
+		}
+
+		offset += copy(pcrsBuffer[offset:], measurement)
+	}
+	buffer.Write(pcrsBuffer)
+
+	return buffer.Bytes(), nil
+}
+
+func (t *tpm20Go) ActivateCredential(endorsementSecretKey string, credentialBytes []byte, secretBytes []byte) ([]byte, error) {
+
+	endorsementSecretKeyBytes, err := validateAndConvertKey(endorsementSecretKey)
+	if err != nil {
+		return nil, errors.Wrap(err, INVALID_ENDORSEMENT_SECRET_KEY)
+	}
+
+	if len(credentialBytes) == 0 {
+		return nil, errors.New("Invalid size of credential bytes")
+	}
+
+	if len(secretBytes) == 0 {
+		return nil, errors.New("Invalid secret bytes")
+	}
+
+	aik, err := t.namedHandle(TPM_HANDLE_AIK)
+	if err != nil {
+		return nil, errors.Wrap(err, "ActivateCredential failed to read the AIK")
+	}
+
+	ek, err := t.namedHandle(TPM_HANDLE_EK)
+	if err != nil {
+		return nil, errors.Wrap(err, "ActivateCredential failed to read the EK")
+	}
+
+	// The aik password remains empty as it was provisioned with owner permissions
+	// during setup (no password is needed).
+	activateCredential, err := tpm2.ActivateCredential{
+		ActivateHandle: tpm2.AuthHandle{Handle: aik.Handle, Name: aik.Name, Auth: tpm2.PasswordAuth(nil)},
+		KeyHandle:      tpm2.AuthHandle{Handle: ek.Handle, Name: ek.Name, Auth: ekPolicy(endorsementSecretKeyBytes)},
+		CredentialBlob: tpm2.TPM2BIDObject{Buffer: credentialBytes},
+		Secret:         tpm2.TPM2BEncryptedSecret{Buffer: secretBytes},
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "ActivateCredential failed")
+	}
+
+	if len(activateCredential.CertInfo.Buffer) == 0 {
+		return nil, errors.New("The buffer size is incorrect")
+	}
+
+	return activateCredential.CertInfo.Buffer, nil
+}
+
+func (t *tpm20Go) NvDefine(ownerSecretKey string, indexSecretKey string, nvIndex uint32, nvSize uint16) error {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	indexSecretKeyBytes, err := validateAndConvertKey(indexSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	_, err = tpm2.NVDefineSpace{
+		AuthHandle: tpm2.AuthHandle{Handle: tpm2.TPMRHOwner, Auth: tpm2.PasswordAuth(ownerSecretKeyBytes)},
+		Auth:       tpm2.TPM2BAuth{Buffer: indexSecretKeyBytes},
+		PublicInfo: tpm2.New2B(tpm2.TPMSNVPublic{
+			NVIndex: tpm2.TPMHandle(nvIndex),
+			NameAlg: tpm2.TPMAlgSHA256,
+			Attributes: tpm2.TPMANV{
+				AuthWrite: true,
+				AuthRead:  true,
+				OwnerRead: true,
+			},
+			DataSize: nvSize,
+		}),
+	}.Execute(t.tpm)
+	if err != nil {
+		return errors.Wrapf(err, "NvDefine failed to define nv index 0x%X", nvIndex)
+	}
+
+	return nil
+}
+
+func (t *tpm20Go) NvRelease(ownerSecretKey string, nvIndex uint32) error {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	nv, _, err := t.nvNamedHandle(nvIndex)
+	if err != nil {
+		return errors.Wrapf(err, "NvRelease failed to read nv index 0x%X", nvIndex)
+	}
+
+	_, err = tpm2.NVUndefineSpace{
+		AuthHandle: tpm2.AuthHandle{Handle: tpm2.TPMRHOwner, Auth: tpm2.PasswordAuth(ownerSecretKeyBytes)},
+		NVIndex:    nv,
+	}.Execute(t.tpm)
+	if err != nil {
+		return errors.Wrapf(err, "NvRelease failed to release nv index 0x%X", nvIndex)
+	}
+
+	return nil
+}
+
+func (t *tpm20Go) NvRead(indexSecretKey string, authHandle uint32, nvIndex uint32) ([]byte, error) {
+
+	indexSecretKeyBytes, err := validateAndConvertKey(indexSecretKey)
+	if err != nil {
+		return nil, errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	return t.nvRead(indexSecretKeyBytes, authHandle, nvIndex)
+}
+
+func (t *tpm20Go) nvRead(indexSecretKey []byte, authHandle uint32, nvIndex uint32) ([]byte, error) {
+
+	nv, nvPublic, err := t.nvNamedHandle(nvIndex)
+	if err != nil {
+		return nil, errors.Wrapf(err, "NvRead failed to read nv index 0x%X", nvIndex)
+	}
+
+	if nvPublic.DataSize == 0 {
+		return nil, errors.New("The nv data size is incorrect")
+	}
+
+	auth, err := t.nvAuthHandle(indexSecretKey, authHandle, nv)
+	if err != nil {
+		return nil, err
+	}
+
+	nvData := make([]byte, 0, nvPublic.DataSize)
+	for offset := uint16(0); offset < nvPublic.DataSize; {
+		size := nvPublic.DataSize - offset
+		if size > nvBufferSize {
+			size = nvBufferSize
+		}
+
+		nvRead, err := tpm2.NVRead{
+			AuthHandle: auth,
+			NVIndex:    nv,
+			Size:       size,
+			Offset:     offset,
+		}.Execute(t.tpm)
+		if err != nil {
+			return nil, errors.Wrapf(err, "NvRead failed to read nv index 0x%X at offset %d", nvIndex, offset)
+		}
+
+		nvData = append(nvData, nvRead.Data.Buffer...)
+		offset += size
+	}
+
+	return nvData, nil
+}
+
+func (t *tpm20Go) NvWrite(indexSecretKey string, authHandle uint32, nvIndex uint32, data []byte) error {
+
+	indexSecretKeyBytes, err := validateAndConvertKey(indexSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	if data == nil || len(data) == 0 {
+		return errors.New("The data parameter cannot be null or empty")
+	}
+
+	nv, _, err := t.nvNamedHandle(nvIndex)
+	if err != nil {
+		return errors.Wrapf(err, "NvWrite failed to read nv index 0x%X", nvIndex)
+	}
+
+	auth, err := t.nvAuthHandle(indexSecretKeyBytes, authHandle, nv)
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; offset < len(data); offset += nvBufferSize {
+		end := offset + nvBufferSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		_, err = tpm2.NVWrite{
+			AuthHandle: auth,
+			NVIndex:    nv,
+			Data:       tpm2.TPM2BMaxNVBuffer{Buffer: data[offset:end]},
+			Offset:     uint16(offset),
+		}.Execute(t.tpm)
+		if err != nil {
+			return errors.Wrapf(err, "NvWrite failed to write nv index 0x%X at offset %d", nvIndex, offset)
+		}
+	}
+
+	return nil
+}
+
+// nvAuthHandle returns the handle authorizing the access to the nv index, either the index
+// itself or a hierarchy (ex. TPM2_RH_OWNER).
+func (t *tpm20Go) nvAuthHandle(secretKey []byte, authHandle uint32, nv tpm2.NamedHandle) (tpm2.AuthHandle, error) {
+	if authHandle == uint32(nv.Handle) {
+		return tpm2.AuthHandle{Handle: nv.Handle, Name: nv.Name, Auth: tpm2.PasswordAuth(secretKey)}, nil
+	}
+
+	name := tpm2.TPMHandle(authHandle).KnownName()
+	if name == nil {
+		return tpm2.AuthHandle{}, errors.Errorf("Invalid nv auth handle 0x%X", authHandle)
+	}
+
+	return tpm2.AuthHandle{Handle: tpm2.TPMHandle(authHandle), Name: *name, Auth: tpm2.PasswordAuth(secretKey)}, nil
+}
+
+func (t *tpm20Go) NvIndexExists(nvIndex uint32) (bool, error) {
+
+	_, _, err := t.nvNamedHandle(nvIndex)
+	if err == nil {
+		return true, nil
+	} else if errors.Is(err, tpm2.TPMRCHandle) {
+		return false, nil
+	}
+
+	return false, errors.Wrapf(err, "NvIndexExists failed to read nv index 0x%X", nvIndex)
+}
+
+func (t *tpm20Go) CreatePrimaryHandle(ownerSecretKey string, handle uint32) error {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	primaryTemplate := tpm2.TPMTPublic{
+		Type:    tpm2.TPMAlgRSA,
+		NameAlg: tpm2.TPMAlgSHA256,
+		ObjectAttributes: tpm2.TPMAObject{
+			Restricted:          true,
+			Decrypt:             true,
+			FixedTPM:            true,
+			FixedParent:         true,
+			SensitiveDataOrigin: true,
+			UserWithAuth:        true,
+		},
+		Parameters: tpm2.NewTPMUPublicParms(tpm2.TPMAlgRSA, &tpm2.TPMSRSAParms{
+			Symmetric: tpm2.TPMTSymDefObject{
+				Algorithm: tpm2.TPMAlgAES,
+				KeyBits:   tpm2.NewTPMUSymKeyBits(tpm2.TPMAlgAES, tpm2.TPMKeyBits(128)),
+				Mode:      tpm2.NewTPMUSymMode(tpm2.TPMAlgAES, tpm2.TPMAlgCFB),
+			},
+			Scheme:  tpm2.TPMTRSAScheme{Scheme: tpm2.TPMAlgNull},
+			KeyBits: 2048,
+		}),
+		Unique: tpm2.NewTPMUPublicID(tpm2.TPMAlgRSA, &tpm2.TPM2BPublicKeyRSA{}),
+	}
+
+	err = t.createPersistentPrimary(tpm2.TPMRHOwner, ownerSecretKeyBytes, ownerSecretKeyBytes, primaryTemplate, handle)
+	if err != nil {
+		return errors.Wrap(err, "CreatePrimaryHandle failed")
+	}
+
+	return nil
+}
+
+func (t *tpm20Go) CreateEk(ownerSecretKey, endorsementSecretKey string, handle uint32) error {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	endorsementSecretKeyBytes, err := validateAndConvertKey(endorsementSecretKey)
+	if err != nil {
+		return errors.Wrap(err, INVALID_ENDORSEMENT_SECRET_KEY)
+	}
+
+	err = t.evictIfExists(ownerSecretKeyBytes, handle)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateEk")
+	}
+
+	ekTemplate, err := t.getEkTemplate(ownerSecretKeyBytes)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateEk")
+	}
+
+	err = t.createPersistentPrimary(tpm2.TPMRHEndorsement, endorsementSecretKeyBytes, ownerSecretKeyBytes, *ekTemplate, handle)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred in CreateEk")
+	}
+
+	return nil
+}
+
+// getEkTemplate returns the EK template provisioned in nv ram by the TPM vendor (see 'TCG EK Credential
+// Profile') or the default RSA template (see 'B.3.3 Template L-1: RSA 2048 (Storage)').
+func (t *tpm20Go) getEkTemplate(ownerSecretKey []byte) (*tpm2.TPMTPublic, error) {
+
+	capability, err := tpm2.GetCapability{
+		Capability:    tpm2.TPMCapHandles,
+		Property:      capabilityHandleStart,
+		PropertyCount: capabilityHandleCount,
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not get the nv indexes")
+	}
+
+	handles, err := capability.CapabilityData.Data.Handles()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not get the nv indexes")
+	}
+
+	ekTemplatePresent, ekNoncePresent := false, false
+	for _, handle := range handles.Handle {
+		switch handle {
+		case nvIdxRsaEkTemplate:
+			ekTemplatePresent = true
+		case nvIdxRsaEkNonce:
+			ekNoncePresent = true
+		}
+	}
+
+	if !ekTemplatePresent {
+		if ekNoncePresent {
+			return nil, errors.Errorf("The EK nonce is present at nv index 0x%X without the EK template", nvIdxRsaEkNonce)
+		}
+
+		ekTemplate := tpm2.RSAEKTemplate
+		return &ekTemplate, nil
+	}
+
+	ekTemplateBytes, err := t.nvRead(ownerSecretKey, TPM2_RH_OWNER, nvIdxRsaEkTemplate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read EK template at index 0x%X", nvIdxRsaEkTemplate)
+	}
+
+	ekTemplate, err := tpm2.Unmarshal[tpm2.TPMTPublic](ekTemplateBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not unmarshal EK template")
+	}
+
+	if ekNoncePresent {
+		ekNonce, err := t.nvRead(ownerSecretKey, TPM2_RH_OWNER, nvIdxRsaEkNonce)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not read EK nonce at index 0x%X", nvIdxRsaEkNonce)
+		}
+
+		unique := make([]byte, 256)
+		copy(unique, ekNonce)
+		ekTemplate.Unique = tpm2.NewTPMUPublicID(tpm2.TPMAlgRSA, &tpm2.TPM2BPublicKeyRSA{Buffer: unique})
+	}
+
+	return ekTemplate, nil
+}
+
+// createPersistentPrimary creates a primary key in the 'hierarchy' and persists it at 'handle'
+func (t *tpm20Go) createPersistentPrimary(hierarchy tpm2.TPMHandle, hierarchySecretKey []byte, ownerSecretKey []byte,
+	template tpm2.TPMTPublic, handle uint32) error {
+
+	primary, err := tpm2.CreatePrimary{
+		PrimaryHandle: tpm2.AuthHandle{Handle: hierarchy, Auth: tpm2.PasswordAuth(hierarchySecretKey)},
+		InPublic:      tpm2.New2B(template),
+	}.Execute(t.tpm)
+	if err != nil {
+		return errors.Wrap(err, "Could not create the primary key")
+	}
+
+	defer t.flush(primary.ObjectHandle)
+
+	err = t.evictControl(ownerSecretKey, tpm2.NamedHandle{Handle: primary.ObjectHandle, Name: primary.Name}, handle)
+	if err != nil {
+		return errors.Wrapf(err, "Could not persist the primary key at handle 0x%X", handle)
+	}
+
+	return nil
+}
+
+func (t *tpm20Go) CreateSigningKey(signingSecretKey string) (*CertifiedKey, error) {
+	return t.createCertifiedKey(signingSecretKey, Signing)
+}
+
+func (t *tpm20Go) CreateBindingKey(bindingSecretKey string) (*CertifiedKey, error) {
+	return t.createCertifiedKey(bindingSecretKey, Binding)
+}
+
+func (t *tpm20Go) createCertifiedKey(keySecret string, keyUsage int) (*CertifiedKey, error) {
+
+	keySecretBytes, err := validateAndConvertKey(keySecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid secret key")
+	}
+
+	keyTemplate := tpm2.TPMTPublic{
+		Type:    tpm2.TPMAlgRSA,
+		NameAlg: tpm2.TPMAlgSHA256,
+		ObjectAttributes: tpm2.TPMAObject{
+			FixedTPM:            true,
+			FixedParent:         true,
+			SensitiveDataOrigin: true,
+			UserWithAuth:        true,
+		},
+		Parameters: tpm2.NewTPMUPublicParms(tpm2.TPMAlgRSA, &tpm2.TPMSRSAParms{
+			Symmetric: tpm2.TPMTSymDefObject{Algorithm: tpm2.TPMAlgNull},
+			Scheme:    tpm2.TPMTRSAScheme{Scheme: tpm2.TPMAlgNull},
+			KeyBits:   2048,
+		}),
+		Unique: tpm2.NewTPMUPublicID(tpm2.TPMAlgRSA, &tpm2.TPM2BPublicKeyRSA{}),
+	}
+
+	switch keyUsage {
+	case Binding:
+		keyTemplate.ObjectAttributes.Decrypt = true
+	case Signing:
+		keyTemplate.ObjectAttributes.SignEncrypt = true
+	default:
+		return nil, errors.Errorf("Invalid key usage: %d", keyUsage)
+	}
+
+	primary, err := t.namedHandle(TPM_HANDLE_PRIMARY)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateCertifiedKey failed to read the primary key")
+	}
+
+	key, err := tpm2.Create{
+		ParentHandle: tpm2.AuthHandle{Handle: primary.Handle, Name: primary.Name, Auth: tpm2.PasswordAuth(nil)},
+		InSensitive: tpm2.TPM2BSensitiveCreate{
+			Sensitive: &tpm2.TPMSSensitiveCreate{
+				UserAuth: tpm2.TPM2BAuth{Buffer: keySecretBytes},
+			},
+		},
+		InPublic: tpm2.New2B(keyTemplate),
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateCertifiedKey failed to create the key")
+	}
+
+	loadedKey, err := tpm2.Load{
+		ParentHandle: tpm2.AuthHandle{Handle: primary.Handle, Name: primary.Name, Auth: tpm2.PasswordAuth(nil)},
+		InPrivate:    key.OutPrivate,
+		InPublic:     key.OutPublic,
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateCertifiedKey failed to load the key")
+	}
+
+	defer t.flush(loadedKey.ObjectHandle)
+
+	aik, err := t.namedHandle(TPM_HANDLE_AIK)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateCertifiedKey failed to read the AIK")
+	}
+
+	// assume the aik password is empty as performed by trust-agent provisioning
+	certify, err := tpm2.Certify{
+		ObjectHandle:   tpm2.AuthHandle{Handle: loadedKey.ObjectHandle, Name: loadedKey.Name, Auth: tpm2.PasswordAuth(keySecretBytes)},
+		SignHandle:     tpm2.AuthHandle{Handle: aik.Handle, Name: aik.Name, Auth: tpm2.PasswordAuth(nil)},
+		QualifyingData: tpm2.TPM2BData{Buffer: certifiedKeyQualifyingData},
+		InScheme: tpm2.TPMTSigScheme{
+			Scheme:  tpm2.TPMAlgRSASSA,
+			Details: tpm2.NewTPMUSigScheme(tpm2.TPMAlgRSASSA, &tpm2.TPMSSchemeHash{HashAlg: tpm2.TPMAlgSHA256}),
+		},
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateCertifiedKey failed to certify the key")
+	}
+
+	return &CertifiedKey{
+		Version:        V20,
+		Usage:          keyUsage,
+		PublicKey:      tpm2.Marshal(key.OutPublic),
+		PrivateKey:     tpm2.Marshal(key.OutPrivate),
+		KeySignature:   tpm2.Marshal(certify.Signature),
+		KeyAttestation: tpm2.Marshal(certify.CertifyInfo),
+		KeyName:        tpm2.Marshal(loadedKey.Name),
+	}, nil
+}
+
+// loadCertifiedKey loads the signing/binding key created by createCertifiedKey under the primary key,
+// the returned handle must be flushed.
+func (t *tpm20Go) loadCertifiedKey(certifiedKey *CertifiedKey) (*tpm2.LoadResponse, error) {
+
+	public, err := tpm2.Unmarshal[tpm2.TPM2BPublic](certifiedKey.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not unmarshal the certified key's PublicKey")
+	}
+
+	private, err := tpm2.Unmarshal[tpm2.TPM2BPrivate](certifiedKey.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not unmarshal the certified key's PrivateKey")
+	}
+
+	primary, err := t.namedHandle(TPM_HANDLE_PRIMARY)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read the primary key")
+	}
+
+	loadedKey, err := tpm2.Load{
+		ParentHandle: tpm2.AuthHandle{Handle: primary.Handle, Name: primary.Name, Auth: tpm2.PasswordAuth(nil)},
+		InPrivate:    *private,
+		InPublic:     *public,
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load the certified key")
+	}
+
+	return loadedKey, nil
+}
+
+func (t *tpm20Go) Unbind(certifiedKey *CertifiedKey, bindingSecretKey string, encryptedData []byte) ([]byte, error) {
+
+	bindingSecretKeyBytes, err := validateAndConvertKey(bindingSecretKey)
+	if err != nil {
+		return nil, errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	if certifiedKey == nil {
+		return nil, errors.New("The certifiedKey parameter must be provided")
+	}
+
+	if len(encryptedData) == 0 {
+		return nil, errors.New("No data was provided for the 'encryptedData' parameter")
+	}
+
+	loadedKey, err := t.loadCertifiedKey(certifiedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unbind failed")
+	}
+
+	defer t.flush(loadedKey.ObjectHandle)
+
+	decrypted, err := tpm2.RSADecrypt{
+		KeyHandle:  tpm2.AuthHandle{Handle: loadedKey.ObjectHandle, Name: loadedKey.Name, Auth: tpm2.PasswordAuth(bindingSecretKeyBytes)},
+		CipherText: tpm2.TPM2BPublicKeyRSA{Buffer: encryptedData},
+		InScheme: tpm2.TPMTRSADecrypt{
+			Scheme:  tpm2.TPMAlgOAEP,
+			Details: tpm2.NewTPMUAsymScheme(tpm2.TPMAlgOAEP, &tpm2.TPMSEncSchemeOAEP{HashAlg: tpm2.TPMAlgSHA256}),
+		},
+		Label: tpm2.TPM2BData{Buffer: unbindLabel},
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "Unbind failed to decrypt the data")
+	}
+
+	return decrypted.Message.Buffer, nil
+}
+
+func (t *tpm20Go) Sign(certifiedKey *CertifiedKey, signingSecretKey string, hashed []byte) ([]byte, error) {
+
+	signingSecretKeyBytes, err := validateAndConvertKey(signingSecretKey)
+	if err != nil {
+		return nil, errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	if certifiedKey == nil {
+		return nil, errors.New("The certifiedKey parameter must be provided")
+	}
+
+	if len(certifiedKey.PublicKey) == 0 {
+		return nil, errors.New("No data was provided in the certified key's PublicKey")
+	}
+
+	if len(certifiedKey.PrivateKey) == 0 {
+		return nil, errors.New("No data was provided in the certified key's PrivateKey")
+	}
+
+	if len(hashed) == 0 {
+		return nil, errors.New("No data was provided for the 'hashed' parameter")
+	}
+
+	loadedKey, err := t.loadCertifiedKey(certifiedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Sign failed")
+	}
+
+	defer t.flush(loadedKey.ObjectHandle)
+
+	sign, err := tpm2.Sign{
+		KeyHandle: tpm2.AuthHandle{Handle: loadedKey.ObjectHandle, Name: loadedKey.Name, Auth: tpm2.PasswordAuth(signingSecretKeyBytes)},
+		Digest:    tpm2.TPM2BDigest{Buffer: hashed},
+		InScheme: tpm2.TPMTSigScheme{
+			Scheme:  tpm2.TPMAlgRSASSA,
+			Details: tpm2.NewTPMUSigScheme(tpm2.TPMAlgRSASSA, &tpm2.TPMSSchemeHash{HashAlg: tpm2.TPMAlgSHA256}),
+		},
+		Validation: tpm2.TPMTTKHashCheck{Tag: tpm2.TPMSTHashCheck, Hierarchy: tpm2.TPMRHNull},
+	}.Execute(t.tpm)
+	if err != nil {
+		return nil, errors.Wrap(err, "Sign failed to sign the hash")
+	}
+
+	signature, err := sign.Signature.Signature.RSASSA()
+	if err != nil {
+		return nil, errors.Wrap(err, "Sign received an unexpected signature")
+	}
+
+	return signature.Sig.Buffer, nil
+}
+
+func (t *tpm20Go) PublicKeyExists(handle uint32) (bool, error) {
+
+	_, err := t.readPublic(handle)
+	if err != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (t *tpm20Go) ReadPublic(handle uint32) ([]byte, error) {
+
+	public, err := t.readPublic(handle)
+	if err != nil {
+		return nil, errors.Wrapf(err, "ReadPublic failed to read handle 0x%X", handle)
+	}
+
+	return rsaModulus(&public.OutPublic)
+}
+
+func (t *tpm20Go) IsValidEk(ownerSecretKey string, handle uint32, nvIndex uint32) (bool, error) {
+
+	ownerSecretKeyBytes, err := validateAndConvertKey(ownerSecretKey)
+	if err != nil {
+		return false, errors.Wrap(err, INVALID_OWNER_SECRET_KEY)
+	}
+
+	ekCertificateBytes, err := t.nvRead(ownerSecretKeyBytes, TPM2_RH_OWNER, nvIndex)
+	if err != nil {
+		return false, NewTpmProviderError(TPM_PROVIDER_ERROR_NO_EK_CERT)
+	}
+
+	// go's x509 package does not parse the rsa exponent '0' of the EK template (see
+	// 'B.3.3 Template L-1: RSA 2048 (Storage)' in the 'TCG EK Credential Profile'),
+	// only the public key of the EK Certificate is parsed
+	var ekCertificate struct {
+		TBSCertificate struct {
+			Version            int `asn1:"optional,explicit,default:0,tag:0"`
+			SerialNumber       *big.Int
+			SignatureAlgorithm pkix.AlgorithmIdentifier
+			Issuer             asn1.RawValue
+			Validity           asn1.RawValue
+			Subject            asn1.RawValue
+			PublicKey          struct {
+				Algorithm pkix.AlgorithmIdentifier
+				PublicKey asn1.BitString
+			}
+		}
+	}
+
+	_, err = asn1.Unmarshal(ekCertificateBytes, &ekCertificate)
+	if err != nil {
+		return false, errors.Wrap(err, "Could not parse the EK Certificate")
+	}
+
+	var ekCertificatePublicKey struct {
+		N *big.Int
+		E int
+	}
+
+	_, err = asn1.Unmarshal(ekCertificate.TBSCertificate.PublicKey.PublicKey.Bytes, &ekCertificatePublicKey)
+	if err != nil {
+		return false, errors.Wrap(err, "Could not parse the public key of the EK Certificate")
+	}
+
+	ekModulus, err := t.ReadPublic(handle)
+	if err != nil {
+		return false, err
+	}
+
+	if new(big.Int).SetBytes(ekModulus).Cmp(ekCertificatePublicKey.N) != 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// IsPcrBankActive is used to determine if a PCR bank for the specified hash algo is enabled in the TPM
+func (t *tpm20Go) IsPcrBankActive(pcrBank string) (bool, error) {
+	pcrSelection, err := getPcrSelection([]string{pcrBank}, []int{0})
+	if err != nil {
+		return false, errors.Wrap(err, "Unable to initialize PCR selection bytes")
+	}
+
+	// the TPM does not return the measurements of inactive banks
+	pcrRead, err := tpm2.PCRRead{PCRSelectionIn: *pcrSelection}.Execute(t.tpm)
+	if err != nil {
+		return false, errors.Wrap(err, "Unable to read PCR0")
+	}
+
+	return len(pcrRead.PCRValues.Digests) == 1, nil
+}
+
+func (t *tpm20Go) readPublic(handle uint32) (*tpm2.ReadPublicResponse, error) {
+	return tpm2.ReadPublic{ObjectHandle: tpm2.TPMHandle(handle)}.Execute(t.tpm)
+}
+
+// namedHandle returns the name of the persistent object at 'handle' needed by the commands with
+// authorization sessions
+func (t *tpm20Go) namedHandle(handle uint32) (*tpm2.NamedHandle, error) {
+	public, err := t.readPublic(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tpm2.NamedHandle{Handle: tpm2.TPMHandle(handle), Name: public.Name}, nil
+}
+
+func (t *tpm20Go) nvNamedHandle(nvIndex uint32) (tpm2.NamedHandle, *tpm2.TPMSNVPublic, error) {
+	nvReadPublic, err := tpm2.NVReadPublic{NVIndex: tpm2.TPMHandle(nvIndex)}.Execute(t.tpm)
+	if err != nil {
+		return tpm2.NamedHandle{}, nil, err
+	}
+
+	nvPublic, err := nvReadPublic.NVPublic.Contents()
+	if err != nil {
+		return tpm2.NamedHandle{}, nil, err
+	}
+
+	return tpm2.NamedHandle{Handle: tpm2.TPMHandle(nvIndex), Name: nvReadPublic.NVName}, nvPublic, nil
+}
+
+// evictIfExists removes the persistent object at 'handle' if present
+func (t *tpm20Go) evictIfExists(ownerSecretKey []byte, handle uint32) error {
+	persistent, err := t.namedHandle(handle)
+	if errors.Is(err, tpm2.TPMRCHandle) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "Could not read handle 0x%X", handle)
+	}
+
+	err = t.evictControl(ownerSecretKey, *persistent, handle)
+	if err != nil {
+		return errors.Wrapf(err, "Could not evict handle 0x%X", handle)
+	}
+
+	return nil
+}
+
+func (t *tpm20Go) evictControl(ownerSecretKey []byte, object tpm2.NamedHandle, persistentHandle uint32) error {
+	_, err := tpm2.EvictControl{
+		Auth:             tpm2.AuthHandle{Handle: tpm2.TPMRHOwner, Auth: tpm2.PasswordAuth(ownerSecretKey)},
+		ObjectHandle:     object,
+		PersistentHandle: tpm2.TPMHandle(persistentHandle),
+	}.Execute(t.tpm)
+	return err
+}
+
+func (t *tpm20Go) flush(handle tpm2.TPMHandle) {
+	_, _ = tpm2.FlushContext{FlushHandle: handle}.Execute(t.tpm)
+}
+
+// ekPolicy returns the policy session of the EK (TPM2_PolicySecret(RH_ENDORSEMENT))
+func ekPolicy(endorsementSecretKey []byte) tpm2.Session {
+	return tpm2.Policy(tpm2.TPMAlgSHA256, 16, func(tpm transport.TPM, handle tpm2.TPMISHPolicy, nonceTPM tpm2.TPM2BNonce) error {
+		_, err := tpm2.PolicySecret{
+			AuthHandle:    tpm2.AuthHandle{Handle: tpm2.TPMRHEndorsement, Auth: tpm2.PasswordAuth(endorsementSecretKey)},
+			PolicySession: handle,
+			NonceTPM:      nonceTPM,
+		}.Execute(tpm)
+		return err
+	})
+}
+
+func rsaModulus(public *tpm2.TPM2BPublic) ([]byte, error) {
+	publicArea, err := public.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	rsaPublic, err := publicArea.Unique.RSA()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rsaPublic.Buffer) == 0 {
+		return nil, errors.New("The buffer size is incorrect")
+	}
+
+	return rsaPublic.Buffer, nil
+}
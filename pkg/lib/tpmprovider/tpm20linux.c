@@ -1,3 +1,5 @@
+//go:build linux && cgo && !gotpm
+
 /*
  * Copyright (C) 2020 Intel Corporation
  * SPDX-License-Identifier: BSD-3-Clause
@@ -1,5 +1,5 @@
-//go:build linux
-// +build linux
+//go:build linux && cgo && !gotpm
+// +build linux,cgo,!gotpm
 
 /*
  * Copyright (C) 2020 Intel Corporation
@@ -17,9 +17,7 @@ import "C"
 
 import (
 	"encoding/binary"
-	"encoding/hex"
 	"fmt"
-	"strings"
 	"unsafe"
 
 	"github.com/pkg/errors"
@@ -32,12 +30,14 @@ type linuxTpmFactory struct {
 }
 
 const (
-	INVALID_OWNER_SECRET_KEY       = "Invalid owner secret key"
-	INVALID_ENDORSEMENT_SECRET_KEY = "Invalid endorsement secret key"
-	INVALID_AIK_SECRET_KEY         = "Invalid aik secret key"
-	Tss2RcSuccess                  = 0
+	Tss2RcSuccess = 0
 )
 
+// newTpmFactory creates the TpmFactory that uses TSS2 with the 'tctiType' tcti
+func newTpmFactory(tctiType uint32, conf string) TpmFactory {
+	return linuxTpmFactory{tctiType: tctiType, conf: conf}
+}
+
 func (linuxImpl linuxTpmFactory) NewTpmProvider() (TpmProvider, error) {
 	var ctx *C.tpmCtx
 
@@ -67,8 +67,8 @@ func (t *tpm20Linux) Close() {
 	t.tpmCtx = nil
 }
 
-func (t *tpm20Linux) Version() C.TPM_VERSION {
-	return C.Version(t.tpmCtx)
+func (t *tpm20Linux) Version() TpmVersion {
+	return TpmVersion(C.Version(t.tpmCtx))
 }
 
 func (t *tpm20Linux) TakeOwnership(ownerSecretKey, endorsementSecretKey string) error {
@@ -676,54 +676,6 @@ func (t *tpm20Linux) IsValidEk(ownerSecretKey string, handle uint32, nvIndex uin
 	}
 }
 
-func validateAndConvertKey(key string) ([]byte, error) {
-
-	var keyBytes []byte
-	var err error
-
-	// See if the key is a 'legacy' trust-agent password (40 characters
-	// in hex format).  If so, convert it to bytes.  This is needed for
-	// backward compatibility and carrying forward existing secrets during
-	// an upgrade.
-	//
-	// Otherwise, use what was provided, including the definition of 'hex:'
-	// passwords.
-	if len(key) == 40 {
-		keyBytes, err = hex.DecodeString(key)
-		if err != nil {
-			// not a legacy secret
-			keyBytes = nil
-		}
-	}
-
-	if keyBytes == nil {
-		// tpm2-tools supports the use of 'hex' passwords.  Follow suit
-		// and convert passwords with a leading 'hex:' string to raw
-		// bytes.
-		if strings.HasPrefix(key, HEX_PREFIX) {
-			keyBytes, err = hex.DecodeString(strings.ReplaceAll(key, HEX_PREFIX, ""))
-			if err != nil {
-				return nil, errors.Wrap(err, "'hex:' was provided but could not be parsed")
-			}
-		} else {
-			keyBytes = []byte(key)
-		}
-	}
-
-	// The tss library uses TP2B_AUTH structure for passwords (containing a length and
-	// fixed length buffer).  The tpm-provider uses zero-copy to pass the passwords
-	// into underlying C code.  If the password wasn't provided, return an array that contains
-	// a single zero (to avoid a null pointer).  When passed to the C code, the TPM2B_AUTH
-	// will still be an empty password (null terminated).
-	if len(keyBytes) == 0 {
-		keyBytes = []byte{0}
-	} else if len(keyBytes) > 64 {
-		return nil, errors.New("The secret cannot exceed 64 bytes in length")
-	}
-
-	return keyBytes, nil
-}
-
 // IsPcrBankActive is used to determine if a PCR bank for the specified hash algo is enabled in the TPM
 func (t *tpm20Linux) IsPcrBankActive(pcrBank string) (bool, error) {
 	// create a buffer that describes the pcr selection that can be used by tss2
@@ -13,12 +13,13 @@ import (
 type LinuxTpmFactoryProvider struct{}
 
 //
-// Creates the default TpmFactory that currently uses TSS2 and /dev/tpmrm0
+// Creates the default TpmFactory that uses /dev/tpmrm0, with TSS2 or with the go TPM 2.0
+// stack when built with the 'gotpm' tag (or without cgo).
 //
 func (ltfp LinuxTpmFactoryProvider) NewTpmFactory() (TpmFactory, error) {
 
 	if runtime.GOOS == "linux" {
-		return linuxTpmFactory{tctiType: TCTI_DEVICE, conf: "/dev/tpmrm0"}, nil
+		return newTpmFactory(TCTI_DEVICE, "/dev/tpmrm0"), nil
 	} else {
 		return nil, errors.New("Unsupported tpm factory platform " + runtime.GOOS)
 	}
@@ -30,7 +30,7 @@ func (simtpmf MsSimTpmFactoryProvider) NewTpmFactory() (TpmFactory, error) {
 	}
 
 	if runtime.GOOS == "linux" {
-		return linuxTpmFactory{tctiType: TCTI_MSSIM, conf: conf}, nil
+		return newTpmFactory(TCTI_MSSIM, conf), nil
 	} else {
 		return nil, errors.New("Unsupported tpm factory platform " + runtime.GOOS)
 	}
@@ -4,9 +4,6 @@
  */
 package tpmprovider
 
-// #include "tpm.h"
-import "C"
-
 type CertifiedKey struct {
 	Version        int
 	Usage          int
@@ -17,31 +14,35 @@ type CertifiedKey struct {
 	KeyName        []byte
 }
 
-// provides go visibility to values defined in tpm.h (shared with c code)
+// TpmVersion is the version of the TPM reported by TpmProvider.Version()
+type TpmVersion uint32
+
+// provides go visibility to values defined in tpm.h (shared with c code), the values
+// are repeated here so that the go implementation of the TpmProvider builds without cgo
 const (
-	None = C.TPM_VERSION_UNKNOWN
-	V12  = C.TPM_VERSION_10
-	V20  = C.TPM_VERSION_20
-
-	NV_IDX_RSA_ENDORSEMENT_CERTIFICATE = C.NV_IDX_RSA_ENDORSEMENT_CERTIFICATE
-	NV_IDX_ECC_ENDORSEMENT_CERTIFICATE = C.NV_IDX_ECC_ENDORSEMENT_CERTIFICATE
-	NV_IDX_X509_P384_EK_CERTCHAIN      = C.NV_IDX_X509_P384_EK_CERTCHAIN
-	NV_IDX_ASSET_TAG                   = C.NV_IDX_ASSET_TAG
-	TPM_HANDLE_AIK                     = C.TPM_HANDLE_AIK
-	TPM_HANDLE_EK                      = C.TPM_HANDLE_EK
-	TPM_HANDLE_PRIMARY                 = C.TPM_HANDLE_PRIMARY
+	None = 0
+	V12  = 1
+	V20  = 2
+
+	NV_IDX_RSA_ENDORSEMENT_CERTIFICATE = 0x1c00002
+	NV_IDX_ECC_ENDORSEMENT_CERTIFICATE = 0x1c0000a
+	NV_IDX_X509_P384_EK_CERTCHAIN      = 0x01c00100
+	NV_IDX_ASSET_TAG                   = 0x1c10110
+	TPM_HANDLE_AIK                     = 0x81018000
+	TPM_HANDLE_EK                      = 0x81010000
+	TPM_HANDLE_PRIMARY                 = 0x81000000
 	TPM2_RH_OWNER                      = 0x40000001
 
-	Binding = C.TPM_CERTIFIED_KEY_USAGE_BINDING
-	Signing = C.TPM_CERTIFIED_KEY_USAGE_SIGNING
+	Binding = 0
+	Signing = 1
 
-	TCTI_DEVICE = C.TCTI_DEVICE
-	TCTI_MSSIM  = C.TCTI_MSSIM
+	TCTI_DEVICE = 0
+	TCTI_MSSIM  = 1
 
-	TPM_PROVIDER_ERROR_NO_EK_CERT     = C.TPM_PROVIDER_ERROR_NO_EK_CERT
-	TPM_PROVIDER_EK_PUBLIC_MISMATCH   = C.TPM_PROVIDER_EK_PUBLIC_MISMATCH
-	TPM_PROVIDER_INVALID_PCRSELECTION = C.TPM_PROVIDER_INVALID_PCRSELECTION
-	TPM_PROVIDER_INVALID_PCRCOUNT     = C.TPM_PROVIDER_INVALID_PCRCOUNT
+	TPM_PROVIDER_ERROR_NO_EK_CERT     = 0x100000
+	TPM_PROVIDER_EK_PUBLIC_MISMATCH   = 0x100001
+	TPM_PROVIDER_INVALID_PCRSELECTION = 0x100002
+	TPM_PROVIDER_INVALID_PCRCOUNT     = 0x100003
 
 	// used to indicate that a TPM secret password is passed in hex
 	// format:  ex. 'hex:decafbad'.
@@ -58,7 +59,7 @@ type TpmProvider interface {
 	//
 	// Reports the version of the TPM (assumes TPM 2.0).
 	//
-	Version() C.TPM_VERSION
+	Version() TpmVersion
 
 	//
 	// Provided hex string passwords, takes ownership of the TPM.
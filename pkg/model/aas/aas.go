@@ -40,11 +40,15 @@ type RoleSlice struct {
 type UserCreate struct {
 	Name     string `json:"username"`
 	Password string `json:"password"`
+	// ServiceAccount exempts the user from the password expiry and the account lockout, it is left unchanged by an
+	// update when not set
+	ServiceAccount *bool `json:"service_account,omitempty"`
 }
 
 type UserCreateResponse struct {
-	ID   string `json:"user_id"`
-	Name string `json:"username"`
+	ID             string `json:"user_id"`
+	Name           string `json:"username"`
+	ServiceAccount bool   `json:"service_account,omitempty"`
 }
 
 type UserRoleCreate struct {
@@ -57,6 +61,44 @@ type UserCred struct {
 	Password string `json:"password"`
 }
 
+// TokenResponse is returned by the token API when the request accepts application/json
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenRevokeRequest revokes a bearer token or a refresh token
+type TokenRevokeRequest struct {
+	Token string `json:"token"`
+}
+
+// OIDCTokenRequest exchanges the ID token of the OpenID Connect provider for an AAS token
+type OIDCTokenRequest struct {
+	IDToken string `json:"id_token"`
+}
+
+// LDAPGroupMappingCreate maps an LDAP group to the AAS role, the LDAP users that are members of the group are granted
+// the role
+type LDAPGroupMappingCreate struct {
+	Group string `json:"group"`
+	RoleInfo
+}
+
+type LDAPGroupMappingInfo struct {
+	ID     string `json:"mapping_id"`
+	Group  string `json:"group"`
+	RoleID string `json:"role_id"`
+	RoleInfo
+}
+
+type LDAPGroupMappingInfos []LDAPGroupMappingInfo
+
 type PasswordChange struct {
 	UserName        string `json:"username"`
 	OldPassword     string `json:"old_password"`
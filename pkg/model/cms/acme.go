@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cms
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status of the ACME objects, as defined by RFC 8555
+const (
+	AcmeStatusPending     = "pending"
+	AcmeStatusReady       = "ready"
+	AcmeStatusProcessing  = "processing"
+	AcmeStatusValid       = "valid"
+	AcmeStatusInvalid     = "invalid"
+	AcmeStatusExpired     = "expired"
+	AcmeStatusDeactivated = "deactivated"
+)
+
+// Types of the identifiers of the ACME orders, CMS issues certificates for DNS names and IP addresses
+const (
+	AcmeIdentifierDns = "dns"
+	AcmeIdentifierIp  = "ip"
+)
+
+// AcmeDirectory is the directory of the ACME endpoints of CMS
+type AcmeDirectory struct {
+	NewNonce   string            `json:"newNonce"`
+	NewAccount string            `json:"newAccount"`
+	NewOrder   string            `json:"newOrder"`
+	RevokeCert string            `json:"revokeCert"`
+	Meta       AcmeDirectoryMeta `json:"meta"`
+}
+
+// AcmeDirectoryMeta tells the ACME clients that the accounts must be bound to an external account key
+type AcmeDirectoryMeta struct {
+	ExternalAccountRequired bool `json:"externalAccountRequired"`
+}
+
+// AcmeAccount is the ACME account object, also used as new-account and account update request
+type AcmeAccount struct {
+	Status                 string          `json:"status,omitempty"`
+	Contact                []string        `json:"contact,omitempty"`
+	TermsOfServiceAgreed   bool            `json:"termsOfServiceAgreed,omitempty"`
+	OnlyReturnExisting     bool            `json:"onlyReturnExisting,omitempty"`
+	ExternalAccountBinding json.RawMessage `json:"externalAccountBinding,omitempty"`
+	Orders                 string          `json:"orders,omitempty"`
+}
+
+// AcmeOrderList lists the URLs of the orders of an ACME account
+type AcmeOrderList struct {
+	Orders []string `json:"orders"`
+}
+
+// AcmeIdentifier is an identifier of an ACME order, a DNS name or an IP address
+type AcmeIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// AcmeOrder is the ACME order object, also used as new-order request
+type AcmeOrder struct {
+	Status         string           `json:"status,omitempty"`
+	Expires        *time.Time       `json:"expires,omitempty"`
+	Identifiers    []AcmeIdentifier `json:"identifiers"`
+	NotBefore      string           `json:"notBefore,omitempty"`
+	NotAfter       string           `json:"notAfter,omitempty"`
+	Error          *AcmeProblem     `json:"error,omitempty"`
+	Authorizations []string         `json:"authorizations,omitempty"`
+	Finalize       string           `json:"finalize,omitempty"`
+	Certificate    string           `json:"certificate,omitempty"`
+}
+
+// AcmeAuthorization is the ACME authorization object. The identifiers are authorized by the CertApprover roles bound
+// to the account, so the authorizations have no challenge.
+type AcmeAuthorization struct {
+	Identifier AcmeIdentifier  `json:"identifier"`
+	Status     string          `json:"status"`
+	Expires    *time.Time      `json:"expires,omitempty"`
+	Challenges []AcmeChallenge `json:"challenges"`
+	Wildcard   bool            `json:"wildcard,omitempty"`
+}
+
+// AcmeChallenge is an ACME challenge object
+type AcmeChallenge struct {
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Status string `json:"status"`
+	Token  string `json:"token,omitempty"`
+}
+
+// AcmeFinalize is the request to finalize an ACME order, holding the base64url encoded DER CSR
+type AcmeFinalize struct {
+	Csr string `json:"csr"`
+}
+
+// AcmeRevokeCert is the ACME request to revoke a certificate, holding the base64url encoded DER certificate and the
+// RFC 5280 reason code
+type AcmeRevokeCert struct {
+	Certificate string `json:"certificate"`
+	Reason      *int   `json:"reason,omitempty"`
+}
+
+// AcmeProblem is the RFC 7807 problem document of the ACME errors
+type AcmeProblem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+// AcmeExternalAccountKey is the key the ACME clients use to bind their account to the CertApprover roles of the
+// AAS token used to create the key
+type AcmeExternalAccountKey struct {
+	KeyID     string `json:"key_id"`
+	HmacKey   string `json:"hmac_key"`
+	Directory string `json:"directory"`
+}
@@ -0,0 +1,19 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package cms
+
+// Reasons for revoking a certificate, as defined by RFC 5280
+const (
+	RevocationReasonUnspecified          = "unspecified"
+	RevocationReasonKeyCompromise        = "keyCompromise"
+	RevocationReasonAffiliationChanged   = "affiliationChanged"
+	RevocationReasonSuperseded           = "superseded"
+	RevocationReasonCessationOfOperation = "cessationOfOperation"
+)
+
+// RevokeCertificate is the request to revoke a certificate issued by CMS, the reason defaults to unspecified
+type RevokeCertificate struct {
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttestationPolicy defines how often the hosts of a flavorgroup are attested again and how long their SAML
+// reports are valid, overriding the global HRRS refresh period and SAML validity for the flavorgroup
+type AttestationPolicy struct {
+	// swagger:strfmt uuid
+	FlavorgroupId uuid.UUID `json:"flavorgroup_id"`
+	// RefreshPeriodSeconds is the period at which the hosts of the flavorgroup are attested, whether their
+	// reports have expired or not. Zero disables the schedule.
+	RefreshPeriodSeconds int `json:"refresh_period_seconds,omitempty"`
+	// JitterSeconds is the maximum random delay added to each scheduled attestation, so that flavorgroups with the
+	// same refresh period are not attested at the same time. Defaults to a tenth of the refresh period.
+	JitterSeconds int `json:"jitter_seconds,omitempty"`
+	// SamlValiditySeconds is the validity of the SAML reports of the hosts of the flavorgroup. Zero uses the
+	// configured SAML validity.
+	SamlValiditySeconds int       `json:"saml_validity_seconds,omitempty"`
+	Updated             time.Time `json:"updated"`
+}
+
+// RefreshPeriod returns the refresh period of the policy as a duration
+func (ap AttestationPolicy) RefreshPeriod() time.Duration {
+	return time.Duration(ap.RefreshPeriodSeconds) * time.Second
+}
+
+// Jitter returns the maximum delay added to scheduled attestations as a duration
+func (ap AttestationPolicy) Jitter() time.Duration {
+	if ap.JitterSeconds == 0 {
+		return ap.RefreshPeriod() / 10
+	}
+	return time.Duration(ap.JitterSeconds) * time.Second
+}
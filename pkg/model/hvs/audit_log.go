@@ -0,0 +1,39 @@
+/*
+ * Copyright (C) 2020 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogEntryCollection holds a collection of AuditLogEntry in response to the Search AuditLogs API
+type AuditLogEntryCollection struct {
+	AuditLogEntries []AuditLogEntry `json:"audit_logs" xml:"audit_logs"`
+	Next            string          `json:"next,omitempty" xml:"next"`
+	Previous        string          `json:"prev,omitempty" xml:"prev"`
+}
+
+// AuditLogEntry records a single create, update or delete of an HVS entity
+type AuditLogEntry struct {
+	RowId int `json:"-"`
+	// swagger:strfmt uuid
+	ID uuid.UUID `json:"id"`
+	// swagger:strfmt uuid
+	EntityID   uuid.UUID         `json:"entity_id"`
+	EntityType string            `json:"entity_type"`
+	Action     string            `json:"action"`
+	Created    time.Time         `json:"created"`
+	Data       []AuditColumnData `json:"data"`
+}
+
+// AuditColumnData holds the value of an entity field at the time of the audit log entry
+type AuditColumnData struct {
+	Name      string      `json:"name"`
+	Value     interface{} `json:"value"`
+	IsUpdated bool        `json:"is_updated"`
+}
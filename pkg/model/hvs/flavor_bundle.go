@@ -0,0 +1,24 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/google/uuid"
+
+// FlavorBundle holds signed flavors exported from an HVS instance along with the flavorgroups they belong to
+// and the flavor templates of these flavorgroups, so that the flavors can be imported in another HVS instance.
+// The FlavorIds and FlavorTemplateIds of each flavorgroup describe its associations.
+type FlavorBundle struct {
+	SignedFlavors   []SignedFlavor   `json:"signed_flavors"`
+	FlavorGroups    []FlavorGroup    `json:"flavorgroups"`
+	FlavorTemplates []FlavorTemplate `json:"flavor_templates,omitempty"`
+}
+
+// FlavorBundleImportResult lists the flavors that were created by an import and the ones that already existed
+type FlavorBundleImportResult struct {
+	// swagger:strfmt uuid
+	ImportedFlavorIds []uuid.UUID `json:"imported_flavor_ids"`
+	// swagger:strfmt uuid
+	ExistingFlavorIds []uuid.UUID `json:"existing_flavor_ids"`
+}
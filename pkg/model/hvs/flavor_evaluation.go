@@ -0,0 +1,19 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "github.com/google/uuid"
+
+// FlavorEvaluationRequest describes a dry run of the flavor verification. The host data is either the
+// latest host manifest stored for HostID or the given HostManifest. It is verified against the flavors of
+// the flavorgroup with the candidate flavors added to it, or only against the candidate flavors when no
+// flavorgroup is given.
+type FlavorEvaluationRequest struct {
+	// swagger:strfmt uuid
+	HostID           uuid.UUID        `json:"host_id,omitempty"`
+	HostManifest     *HostManifest    `json:"host_manifest,omitempty"`
+	FlavorgroupName  string           `json:"flavorgroup_name,omitempty"`
+	FlavorCollection FlavorCollection `json:"flavor_collection,omitempty"`
+}
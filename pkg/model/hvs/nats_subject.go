@@ -0,0 +1,17 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import "fmt"
+
+const (
+	NatsHostTrustChanged     = "trust-changed"
+	NatsReportCreatedSubject = "hvs.report.created"
+)
+
+// CreateHostSubject returns the subject HVS publishes host events on, in the form hvs.host.<host-id>.<event>
+func CreateHostSubject(hostId, event string) string {
+	return fmt.Sprintf("hvs.host.%s.%s", hostId, event)
+}
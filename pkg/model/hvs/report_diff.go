@@ -0,0 +1,286 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ReportDiff lists what changed in a host's report compared to an earlier (or later) report of the same host.
+// Entries in "added" are present in the report but not in the report it is compared against, entries in
+// "removed" are only present in the report it is compared against.
+type ReportDiff struct {
+	// swagger:strfmt uuid
+	ReportID uuid.UUID `json:"report_id"`
+	// swagger:strfmt uuid
+	AgainstReportID uuid.UUID `json:"against_report_id"`
+	// swagger:strfmt uuid
+	HostID          uuid.UUID           `json:"host_id"`
+	Trusted         bool                `json:"trusted"`
+	AgainstTrusted  bool                `json:"against_trusted"`
+	Rules           []RuleResultDiff    `json:"rules"`
+	Pcrs            []PcrValueDiff      `json:"pcrs"`
+	EventLogs       []EventLogDiff      `json:"event_logs"`
+	ImaMeasurements *ImaMeasurementDiff `json:"ima_measurements,omitempty"`
+}
+
+// RuleResultDiff describes a rule whose outcome differs between the two reports. Trusted or AgainstTrusted is
+// null when the rule was only evaluated in one of the reports.
+type RuleResultDiff struct {
+	Rule           RuleInfo `json:"rule"`
+	Trusted        *bool    `json:"trusted"`
+	AgainstTrusted *bool    `json:"against_trusted"`
+	Faults         []Fault  `json:"faults,omitempty"`
+}
+
+// PcrValueDiff describes a PCR whose value differs between the two host manifests
+type PcrValueDiff struct {
+	Index        PcrIndex     `json:"index"`
+	PcrBank      SHAAlgorithm `json:"pcr_bank"`
+	Value        string       `json:"value,omitempty"`
+	AgainstValue string       `json:"against_value,omitempty"`
+}
+
+// EventLogDiff lists the event log entries of a PCR that were added or removed
+type EventLogDiff struct {
+	Pcr     Pcr        `json:"pcr"`
+	Added   []EventLog `json:"added,omitempty"`
+	Removed []EventLog `json:"removed,omitempty"`
+}
+
+// ImaMeasurementDiff lists the IMA log entries that were added or removed
+type ImaMeasurementDiff struct {
+	Added   []Measurements `json:"added,omitempty"`
+	Removed []Measurements `json:"removed,omitempty"`
+}
+
+// DiffTrustReports compares the rule results and host manifest of report with those of against. The IDs of
+// the returned ReportDiff are left for the caller to fill in.
+func DiffTrustReports(report, against *TrustReport) *ReportDiff {
+	return &ReportDiff{
+		Trusted:         report.Trusted,
+		AgainstTrusted:  against.Trusted,
+		Rules:           diffRuleResults(report.Results, against.Results),
+		Pcrs:            diffPcrValues(&report.HostManifest.PcrManifest, &against.HostManifest.PcrManifest),
+		EventLogs:       diffEventLogs(&report.HostManifest.PcrManifest.PcrEventLogMap, &against.HostManifest.PcrManifest.PcrEventLogMap),
+		ImaMeasurements: diffImaLogs(report.HostManifest.ImaLogs, against.HostManifest.ImaLogs),
+	}
+}
+
+// ruleResultKeys identifies each rule result by its rule, flavor and PCR. Results with the same identity
+// are told apart by the order in which they were evaluated.
+func ruleResultKeys(results []RuleResult) []string {
+	occurrences := make(map[string]int)
+	keys := make([]string, len(results))
+	for i, result := range results {
+		rule := result.Rule
+		var markers []string
+		for _, marker := range rule.Markers {
+			markers = append(markers, marker.String())
+		}
+		var flavorId string
+		if result.FlavorId != nil {
+			flavorId = result.FlavorId.String()
+		} else if rule.FlavorID != nil {
+			flavorId = rule.FlavorID.String()
+		}
+		var pcr *Pcr
+		if rule.PCR != nil {
+			pcr = rule.PCR
+		} else if rule.ExpectedPcr != nil {
+			pcr = &rule.ExpectedPcr.Pcr
+		} else if rule.ExpectedPcrEventLogEntry != nil {
+			pcr = &rule.ExpectedPcrEventLogEntry.Pcr
+		}
+		key := fmt.Sprintf("%s|%s|%s", rule.Name, strings.Join(markers, ","), flavorId)
+		if pcr != nil {
+			key = fmt.Sprintf("%s|%s:%d", key, pcr.Bank, pcr.Index)
+		}
+		keys[i] = fmt.Sprintf("%s|%d", key, occurrences[key])
+		occurrences[key]++
+	}
+	return keys
+}
+
+func diffRuleResults(results, againstResults []RuleResult) []RuleResultDiff {
+	againstKeys := ruleResultKeys(againstResults)
+	againstByKey := make(map[string]RuleResult, len(againstResults))
+	for i, result := range againstResults {
+		againstByKey[againstKeys[i]] = result
+	}
+
+	diffs := []RuleResultDiff{}
+	for i, key := range ruleResultKeys(results) {
+		trusted := results[i].Trusted
+		againstResult, found := againstByKey[key]
+		if found {
+			delete(againstByKey, key)
+			if againstResult.Trusted == trusted {
+				continue
+			}
+		}
+		diff := RuleResultDiff{
+			Rule:    results[i].Rule,
+			Trusted: &trusted,
+			Faults:  results[i].Faults,
+		}
+		if found {
+			againstTrusted := againstResult.Trusted
+			diff.AgainstTrusted = &againstTrusted
+		}
+		diffs = append(diffs, diff)
+	}
+
+	// rules that were only evaluated for the report compared against, in their original order
+	for i, key := range againstKeys {
+		if againstResult, found := againstByKey[key]; found {
+			againstTrusted := againstResults[i].Trusted
+			diffs = append(diffs, RuleResultDiff{
+				Rule:           againstResult.Rule,
+				AgainstTrusted: &againstTrusted,
+			})
+		}
+	}
+	return diffs
+}
+
+func diffPcrValues(pcrManifest, againstPcrManifest *PcrManifest) []PcrValueDiff {
+	diffs := []PcrValueDiff{}
+	for _, banks := range [][2][]HostManifestPcrs{
+		{pcrManifest.Sha1Pcrs, againstPcrManifest.Sha1Pcrs},
+		{pcrManifest.Sha256Pcrs, againstPcrManifest.Sha256Pcrs},
+		{pcrManifest.Sha384Pcrs, againstPcrManifest.Sha384Pcrs},
+	} {
+		againstValues := make(map[PcrIndex]HostManifestPcrs, len(banks[1]))
+		for _, pcr := range banks[1] {
+			againstValues[pcr.Index] = pcr
+		}
+		for _, pcr := range banks[0] {
+			againstPcr, found := againstValues[pcr.Index]
+			delete(againstValues, pcr.Index)
+			if found && strings.EqualFold(againstPcr.Value, pcr.Value) {
+				continue
+			}
+			diffs = append(diffs, PcrValueDiff{
+				Index:        pcr.Index,
+				PcrBank:      pcr.PcrBank,
+				Value:        pcr.Value,
+				AgainstValue: againstPcr.Value,
+			})
+		}
+		for _, againstPcr := range banks[1] {
+			if _, found := againstValues[againstPcr.Index]; found {
+				diffs = append(diffs, PcrValueDiff{
+					Index:        againstPcr.Index,
+					PcrBank:      againstPcr.PcrBank,
+					AgainstValue: againstPcr.Value,
+				})
+			}
+		}
+	}
+	return diffs
+}
+
+func diffEventLogs(eventLogMap, againstEventLogMap *PcrEventLogMap) []EventLogDiff {
+	diffs := []EventLogDiff{}
+	for _, banks := range [][2][]TpmEventLog{
+		{eventLogMap.Sha1EventLogs, againstEventLogMap.Sha1EventLogs},
+		{eventLogMap.Sha256EventLogs, againstEventLogMap.Sha256EventLogs},
+		{eventLogMap.Sha384EventLogs, againstEventLogMap.Sha384EventLogs},
+	} {
+		events := make(map[Pcr][]EventLog)
+		againstEvents := make(map[Pcr][]EventLog)
+		var pcrs []Pcr
+		for _, eventLog := range banks[0] {
+			if _, found := events[eventLog.Pcr]; !found {
+				pcrs = append(pcrs, eventLog.Pcr)
+			}
+			events[eventLog.Pcr] = append(events[eventLog.Pcr], eventLog.TpmEvent...)
+		}
+		for _, eventLog := range banks[1] {
+			if _, found := events[eventLog.Pcr]; !found {
+				if _, found := againstEvents[eventLog.Pcr]; !found {
+					pcrs = append(pcrs, eventLog.Pcr)
+				}
+			}
+			againstEvents[eventLog.Pcr] = append(againstEvents[eventLog.Pcr], eventLog.TpmEvent...)
+		}
+		sort.SliceStable(pcrs, func(i, j int) bool {
+			return pcrs[i].Index < pcrs[j].Index
+		})
+
+		for _, pcr := range pcrs {
+			added := subtractEventLogs(events[pcr], againstEvents[pcr])
+			removed := subtractEventLogs(againstEvents[pcr], events[pcr])
+			if len(added) > 0 || len(removed) > 0 {
+				diffs = append(diffs, EventLogDiff{Pcr: pcr, Added: added, Removed: removed})
+			}
+		}
+	}
+	return diffs
+}
+
+func eventLogKey(event EventLog) string {
+	return fmt.Sprintf("%s|%s|%s", event.TypeID, event.TypeName, strings.ToLower(event.Measurement))
+}
+
+// subtractEventLogs returns the entries of events that are not in other. Entries that are logged more than
+// once are compared by their number of occurrences.
+func subtractEventLogs(events, other []EventLog) []EventLog {
+	counts := make(map[string]int)
+	for _, event := range other {
+		counts[eventLogKey(event)]++
+	}
+	var remaining []EventLog
+	for _, event := range events {
+		key := eventLogKey(event)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+		remaining = append(remaining, event)
+	}
+	return remaining
+}
+
+func diffImaLogs(imaLogs, againstImaLogs *ImaLogs) *ImaMeasurementDiff {
+	if imaLogs == nil && againstImaLogs == nil {
+		return nil
+	}
+	var measurements, againstMeasurements []Measurements
+	if imaLogs != nil {
+		measurements = imaLogs.Measurements
+	}
+	if againstImaLogs != nil {
+		againstMeasurements = againstImaLogs.Measurements
+	}
+
+	key := func(m Measurements) string {
+		return m.File + "|" + strings.ToLower(m.Measurement)
+	}
+	subtract := func(from, other []Measurements) []Measurements {
+		counts := make(map[string]int)
+		for _, m := range other {
+			counts[key(m)]++
+		}
+		var remaining []Measurements
+		for _, m := range from {
+			if counts[key(m)] > 0 {
+				counts[key(m)]--
+				continue
+			}
+			remaining = append(remaining, m)
+		}
+		return remaining
+	}
+	return &ImaMeasurementDiff{
+		Added:   subtract(measurements, againstMeasurements),
+		Removed: subtract(againstMeasurements, measurements),
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+
+package hvs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTrustReports(t *testing.T) {
+	pcr := Pcr{Index: 17, Bank: "SHA256"}
+	against := TrustReport{
+		Trusted: true,
+		Results: []RuleResult{
+			{Rule: RuleInfo{Name: "PcrMatchesConstant", Markers: []FlavorPartName{FlavorPartPlatform}, PCR: &pcr}, Trusted: true},
+			{Rule: RuleInfo{Name: "AikCertificateTrusted", Markers: []FlavorPartName{FlavorPartPlatform}}, Trusted: true},
+			{Rule: RuleInfo{Name: "AssetTagMatches", Markers: []FlavorPartName{FlavorPartAssetTag}}, Trusted: true},
+		},
+		HostManifest: HostManifest{
+			PcrManifest: PcrManifest{
+				Sha256Pcrs: []HostManifestPcrs{
+					{Index: PCR0, Value: "aa", PcrBank: SHA256},
+					{Index: PCR17, Value: "bb", PcrBank: SHA256},
+				},
+				PcrEventLogMap: PcrEventLogMap{
+					Sha256EventLogs: []TpmEventLog{{Pcr: pcr, TpmEvent: []EventLog{
+						{TypeName: "tb_policy", Measurement: "01"},
+						{TypeName: "vmlinuz", Measurement: "02"},
+						{TypeName: "vmlinuz", Measurement: "02"},
+					}}},
+				},
+			},
+			ImaLogs: &ImaLogs{Measurements: []Measurements{
+				{File: "/usr/bin/ls", Measurement: "a1"},
+				{File: "/usr/bin/cat", Measurement: "b1"},
+			}},
+		},
+	}
+	report := TrustReport{
+		Trusted: false,
+		Results: []RuleResult{
+			{Rule: RuleInfo{Name: "PcrMatchesConstant", Markers: []FlavorPartName{FlavorPartPlatform}, PCR: &pcr}, Trusted: false,
+				Faults: []Fault{{Name: "PcrValueMismatchSHA256"}}},
+			{Rule: RuleInfo{Name: "AikCertificateTrusted", Markers: []FlavorPartName{FlavorPartPlatform}}, Trusted: true},
+			{Rule: RuleInfo{Name: "XmlMeasurementLogEquals", Markers: []FlavorPartName{FlavorPartSoftware}}, Trusted: false},
+		},
+		HostManifest: HostManifest{
+			PcrManifest: PcrManifest{
+				Sha256Pcrs: []HostManifestPcrs{
+					{Index: PCR0, Value: "AA", PcrBank: SHA256},
+					{Index: PCR17, Value: "cc", PcrBank: SHA256},
+				},
+				PcrEventLogMap: PcrEventLogMap{
+					Sha256EventLogs: []TpmEventLog{{Pcr: pcr, TpmEvent: []EventLog{
+						{TypeName: "tb_policy", Measurement: "01"},
+						{TypeName: "vmlinuz", Measurement: "02"},
+						{TypeName: "initrd", Measurement: "03"},
+					}}},
+				},
+			},
+			ImaLogs: &ImaLogs{Measurements: []Measurements{
+				{File: "/usr/bin/ls", Measurement: "a2"},
+				{File: "/usr/bin/cat", Measurement: "b1"},
+			}},
+		},
+	}
+
+	diff := DiffTrustReports(&report, &against)
+	assert.False(t, diff.Trusted)
+	assert.True(t, diff.AgainstTrusted)
+
+	// the changed rule, the rule only in report and the rule only in against
+	assert.Len(t, diff.Rules, 3)
+	assert.Equal(t, "PcrMatchesConstant", diff.Rules[0].Rule.Name)
+	assert.False(t, *diff.Rules[0].Trusted)
+	assert.True(t, *diff.Rules[0].AgainstTrusted)
+	assert.Len(t, diff.Rules[0].Faults, 1)
+	assert.Equal(t, "XmlMeasurementLogEquals", diff.Rules[1].Rule.Name)
+	assert.Nil(t, diff.Rules[1].AgainstTrusted)
+	assert.Equal(t, "AssetTagMatches", diff.Rules[2].Rule.Name)
+	assert.Nil(t, diff.Rules[2].Trusted)
+
+	// PCR values are compared case insensitively
+	assert.Equal(t, []PcrValueDiff{{Index: PCR17, PcrBank: SHA256, Value: "cc", AgainstValue: "bb"}}, diff.Pcrs)
+
+	assert.Len(t, diff.EventLogs, 1)
+	assert.Equal(t, []EventLog{{TypeName: "initrd", Measurement: "03"}}, diff.EventLogs[0].Added)
+	assert.Equal(t, []EventLog{{TypeName: "vmlinuz", Measurement: "02"}}, diff.EventLogs[0].Removed)
+
+	assert.Equal(t, []Measurements{{File: "/usr/bin/ls", Measurement: "a2"}}, diff.ImaMeasurements.Added)
+	assert.Equal(t, []Measurements{{File: "/usr/bin/ls", Measurement: "a1"}}, diff.ImaMeasurements.Removed)
+}
+
+func TestDiffTrustReportsIdentical(t *testing.T) {
+	report := TrustReport{
+		Trusted: true,
+		Results: []RuleResult{{Rule: RuleInfo{Name: "AikCertificateTrusted"}, Trusted: true}},
+		HostManifest: HostManifest{
+			PcrManifest: PcrManifest{Sha1Pcrs: []HostManifestPcrs{{Index: PCR0, Value: "aa", PcrBank: SHA1}}},
+		},
+	}
+
+	diff := DiffTrustReports(&report, &report)
+	assert.Empty(t, diff.Rules)
+	assert.Empty(t, diff.Pcrs)
+	assert.Empty(t, diff.EventLogs)
+	assert.Nil(t, diff.ImaMeasurements)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2021 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package hvs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifies the kind of change an Event notifies subscribers about
+type EventType string
+
+const (
+	EventTypeHostTrusted           EventType = "host.trusted"
+	EventTypeHostUntrusted         EventType = "host.untrusted"
+	EventTypeHostConnectionFailure EventType = "host.connection-failure"
+	EventTypeFlavorCreated         EventType = "flavor.created"
+	EventTypeFlavorDeleted         EventType = "flavor.deleted"
+	EventTypeReportCreated         EventType = "report.created"
+)
+
+// EventTypes lists all the event types HVS can publish
+var EventTypes = []EventType{
+	EventTypeHostTrusted,
+	EventTypeHostUntrusted,
+	EventTypeHostConnectionFailure,
+	EventTypeFlavorCreated,
+	EventTypeFlavorDeleted,
+	EventTypeReportCreated,
+}
+
+// IsValid returns true if the event type is one that HVS publishes
+func (et EventType) IsValid() bool {
+	for _, t := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	EventEntityTypeHost   = "host"
+	EventEntityTypeFlavor = "flavor"
+	EventEntityTypeReport = "report"
+)
+
+// Subscription registers a webhook URL that is sent events of the given types
+type Subscription struct {
+	RowId int `json:"-"`
+	// swagger:strfmt uuid
+	ID         uuid.UUID   `json:"id,omitempty"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	// Secret is the key used to compute the signature of every event posted to URL. It is only
+	// returned in the response of the create request
+	Secret  string    `json:"secret,omitempty"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+// SubscriptionCollection holds a collection of Subscription in response to an API query
+type SubscriptionCollection struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Event is the JSON payload posted to subscribers
+type Event struct {
+	// swagger:strfmt uuid
+	ID      uuid.UUID `json:"id"`
+	Type    EventType `json:"type"`
+	Created time.Time `json:"created"`
+	// swagger:strfmt uuid
+	EntityID   uuid.UUID              `json:"entity_id"`
+	EntityType string                 `json:"entity_type"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	// Report summarizes the trust report for host trust and report events
+	Report *TrustReportSummary `json:"report,omitempty"`
+}
+
+// TrustReportSummary is the condensed view of a host's trust report that is sent with report and trust events
+type TrustReportSummary struct {
+	// swagger:strfmt uuid
+	ReportID uuid.UUID `json:"report_id"`
+	// swagger:strfmt uuid
+	HostID       uuid.UUID               `json:"host_id"`
+	HostName     string                  `json:"host_name,omitempty"`
+	HardwareUUID string                  `json:"hardware_uuid,omitempty"`
+	Trusted      bool                    `json:"trusted"`
+	FlavorsTrust map[FlavorPartName]bool `json:"flavors_trust,omitempty"`
+	Created      time.Time               `json:"created"`
+	Expiration   time.Time               `json:"expiration"`
+}
+
+// EventDeadLetter records an event that could not be delivered to a subscriber after all retries
+type EventDeadLetter struct {
+	// swagger:strfmt uuid
+	ID uuid.UUID `json:"id"`
+	// swagger:strfmt uuid
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	Event          Event     `json:"event"`
+	Attempts       int       `json:"attempts"`
+	LastError      string    `json:"last_error"`
+	Created        time.Time `json:"created"`
+}
+
+// EventDeadLetterCollection holds a collection of EventDeadLetter in response to an API query
+type EventDeadLetterCollection struct {
+	DeadLetters []EventDeadLetter `json:"dead_letters"`
+}
@@ -28,17 +28,23 @@ type KeyRequest struct {
 	TransferPolicyID uuid.UUID `json:"transfer_policy_id,omitempty"`
 	Label            string    `json:"label,omitempty"`
 	Usage            string    `json:"usage,omitempty"`
+	// UsagePolicy takes precedence over the asset tags of Usage for the key transfer with SAML report
+	UsagePolicy *KeyUsagePolicy `json:"usage_policy,omitempty"`
 }
 
 // KeyResponse - key attributes from key create or register response.
 type KeyResponse struct {
 	KeyInformation *KeyInformation `json:"key_information"`
 	// swagger:strfmt uuid
-	TransferPolicyID uuid.UUID `json:"transfer_policy_id"`
-	TransferLink     string    `json:"transfer_link"`
-	CreatedAt        time.Time `json:"created_at"`
-	Label            string    `json:"label,omitempty"`
-	Usage            string    `json:"usage,omitempty"`
+	TransferPolicyID uuid.UUID       `json:"transfer_policy_id"`
+	TransferLink     string          `json:"transfer_link"`
+	CreatedAt        time.Time       `json:"created_at"`
+	Label            string          `json:"label,omitempty"`
+	Usage            string          `json:"usage,omitempty"`
+	UsagePolicy      *KeyUsagePolicy `json:"usage_policy,omitempty"`
+	// Version is the current version of the key material, transferred by default
+	Version   int        `json:"version,omitempty"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
 }
 
 // KeyTransferAttributes - Contains all possible key transfer attributes.
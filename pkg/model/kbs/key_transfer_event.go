@@ -0,0 +1,29 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyTransferEvent - record of a decision on a key transfer request.
+type KeyTransferEvent struct {
+	// swagger:strfmt uuid
+	ID uuid.UUID `json:"id"`
+	// swagger:strfmt uuid
+	KeyID uuid.UUID `json:"key_id"`
+	// Requester is the hardware UUID of the host for SAML transfers and the common name of the client certificate
+	// for SKC transfers
+	Requester    string `json:"requester"`
+	TransferType string `json:"transfer_type"`
+	// Decision is one of granted, denied, challenged or failed
+	Decision string `json:"decision"`
+	// FailedClause is the clause of the key usage policy which denied the transfer
+	FailedClause string    `json:"failed_clause,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package kbs
+
+// KeyUsagePolicy - requirements on the SAML report of a host for a key to be transferred to it. All the clauses that
+// are set must be satisfied.
+type KeyUsagePolicy struct {
+	AssetTags *AssetTagPolicy `json:"asset_tags,omitempty"`
+	// RequiredTrust lists the trust attributes of the SAML report that must be true, e.g. TRUST_PLATFORM or TRUST_OS
+	RequiredTrust []string `json:"required_trust,omitempty"`
+	// MaxSamlAgeSeconds is the maximum time elapsed since the SAML report was issued
+	MaxSamlAgeSeconds int `json:"max_saml_age_seconds,omitempty"`
+	// AllowedAikIssuers lists the common names of the CAs allowed to issue the AIK certificate of the host
+	AllowedAikIssuers []string `json:"allowed_aik_issuers,omitempty"`
+	// RequiredHardwareFeatures lists the hardware features of the SAML report that must be enabled, e.g. TXT or
+	// SecureBootEnabled
+	RequiredHardwareFeatures []string `json:"required_hardware_features,omitempty"`
+}
+
+// AssetTagPolicy - asset tags that must be deployed on the host. Asset tag names and values are case insensitive.
+type AssetTagPolicy struct {
+	// AllOf lists the asset tags that must all be deployed on the host
+	AllOf []AssetTag `json:"all_of,omitempty"`
+	// AnyOf lists the asset tags of which at least one must be deployed on the host
+	AnyOf []AssetTag `json:"any_of,omitempty"`
+}
+
+type AssetTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2022 Intel Corporation
+ * SPDX-License-Identifier: BSD-3-Clause
+ */
+package tagent
+
+import (
+	"sync"
+	"time"
+
+	jwtgo "github.com/Waterdrips/jwt-go"
+	"github.com/intel-secl/intel-secl/v5/pkg/clients"
+	"github.com/intel-secl/intel-secl/v5/pkg/clients/aas"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
+	"github.com/intel-secl/intel-secl/v5/pkg/tagent/config"
+	"github.com/intel-secl/intel-secl/v5/pkg/tagent/constants"
+	"github.com/pkg/errors"
+)
+
+const (
+	// apiTokenCheckInterval is the interval at which the expiry of the API token is checked
+	apiTokenCheckInterval = 24 * time.Hour
+	// apiTokenRenewBefore is the fraction of the validity of the API token remaining when it is renewed
+	apiTokenRenewBefore = 1.0 / 3
+)
+
+// apiTokenRenewer renews the API token of the trust agent through AAS before it expires and saves the renewed token
+// in the configuration, so that the API token remains a valid credential to renew the TLS certificate. The API
+// tokens downloaded without the custom_claims:renew permission of AAS are not renewed, download-api-token must be
+// run again before they expire.
+type apiTokenRenewer struct {
+	lock sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Token returns the API token of the configuration renewed if it expires soon, it is the token provider of the
+// certificate renewer
+func (r *apiTokenRenewer) Token() (string, error) {
+	token, err := r.renewIfExpiring()
+	if err != nil {
+		log.WithError(err).Error("api_token:Token() Could not renew API token")
+	}
+	if token == "" {
+		return "", errors.New("No API token configured")
+	}
+	return token, nil
+}
+
+// renewIfExpiring renews the API token when less than apiTokenRenewBefore of its validity remains and returns the
+// current API token, the API token of the configuration when it could not be renewed
+func (r *apiTokenRenewer) renewIfExpiring() (string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cfg, err := config.LoadConfiguration()
+	if err != nil {
+		return "", errors.Wrap(err, "Could not load configuration")
+	}
+	// the token is only read to get its validity, AAS verifies it when renewing it
+	claims := jwtgo.StandardClaims{}
+	if _, _, err = new(jwtgo.Parser).ParseUnverified(cfg.ApiToken, &claims); err != nil {
+		return cfg.ApiToken, errors.Wrap(err, "Could not parse API token")
+	}
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if time.Until(expiresAt) > time.Duration(float64(expiresAt.Sub(issuedAt))*apiTokenRenewBefore) {
+		return cfg.ApiToken, nil
+	}
+
+	caCerts, err := crypt.GetCertsFromDir(constants.TrustedCaCertsDir)
+	if err != nil {
+		return cfg.ApiToken, errors.Wrapf(err, "Could not read CA certificates from %s", constants.TrustedCaCertsDir)
+	}
+	httpClient, err := clients.HTTPClientWithCA(caCerts)
+	if err != nil {
+		return cfg.ApiToken, errors.Wrap(err, "Could not create http client")
+	}
+	aasClient := &aas.Client{BaseURL: cfg.Aas.BaseURL, JWTToken: []byte(cfg.ApiToken), HTTPClient: httpClient}
+	token, err := aasClient.RenewCustomClaimsToken()
+	if err != nil {
+		return cfg.ApiToken, errors.Wrapf(err, "Could not renew API token expiring at %s, run %s again", expiresAt,
+			constants.DownloadApiTokenCommand)
+	}
+
+	oldToken := cfg.ApiToken
+	cfg.ApiToken = string(token)
+	if err = cfg.SaveConfiguration(constants.ConfigFilePath); err != nil {
+		return oldToken, errors.Wrap(err, "Could not save renewed API token")
+	}
+	secLog.Infof("%s: API token expiring at %s renewed", commLogMsg.TokenIssued, expiresAt)
+	return cfg.ApiToken, nil
+}
+
+// Start checks the expiry of the API token at apiTokenCheckInterval until Stop is called
+func (r *apiTokenRenewer) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(apiTokenCheckInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := r.renewIfExpiring(); err != nil {
+				log.WithError(err).Error("api_token:Start() Could not renew API token")
+			}
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the checks of the API token expiry
+func (r *apiTokenRenewer) Stop() {
+	close(r.stop)
+	<-r.done
+}
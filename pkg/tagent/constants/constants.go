@@ -59,6 +59,7 @@ const (
 	DefaultKeyAlgorithm             = "rsa"
 	DefaultKeyAlgorithmLength       = 3072
 	JWTCertsCacheTime               = "1m"
+	TokenRevocationsRefreshTime     = "1m"
 	DefaultTaTlsCn                  = "Trust Agent TLS Certificate"
 	DefaultTaTlsSan                 = "127.0.0.1,localhost"
 	DefaultTaTlsSanSeparator        = ","
@@ -75,6 +76,9 @@ const (
 	DefaultApiTokenExpiration       = 31536000
 	DefaultAsyncReportRetryInterval = 5
 	VerificationServiceName         = "HVS"
+	CmsServiceName                  = "CMS"
+	AasServiceName                  = "AAS"
+	CertApproverGroupName           = "CertApprover"
 )
 
 // Env Variables
@@ -7,9 +7,11 @@ package router
 import (
 	"fmt"
 	"github.com/intel-secl/intel-secl/v5/pkg/clients"
+	"github.com/intel-secl/intel-secl/v5/pkg/clients/aas"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/auth"
 	commContext "github.com/intel-secl/intel-secl/v5/pkg/lib/common/context"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
 	ct "github.com/intel-secl/intel-secl/v5/pkg/model/aas"
@@ -17,10 +19,10 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/tagent/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/tagent/constants"
 	"github.com/pkg/errors"
-	"io/ioutil"
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"time"
 )
 
 func ErrorHandler(eh middleware.EndpointHandler) http.HandlerFunc {
@@ -72,10 +74,7 @@ func fnGetJwtCerts() error {
 		return nil
 	}
 
-	jwtUrl := clients.ResolvePath(cfg.Aas.BaseURL, "jwt-certificates")
-	req, _ := http.NewRequest(http.MethodGet, jwtUrl, nil)
-	req.Header.Add("accept", "application/x-pem-file")
-	secLog.Debugf("router/handlers::fnGetJwtCerts() Connecting to AAS Endpoint %s", jwtUrl)
+	secLog.Debugf("router/handlers::fnGetJwtCerts() Connecting to AAS Endpoint %s", cfg.Aas.BaseURL)
 
 	caCerts, err := crypt.GetCertsFromDir(constants.TrustedCaCertsDir)
 	if err != nil {
@@ -88,25 +87,61 @@ func fnGetJwtCerts() error {
 		return errors.Wrap(err, "router/handlers:fnGetJwtCerts() Error setting up HTTP client")
 	}
 
-	res, err := hc.Do(req)
+	aasClient := aas.Client{BaseURL: cfg.Aas.BaseURL, HTTPClient: hc}
+	jwtCerts, err := aasClient.GetJwtSigningCertificates()
 	if err != nil {
-		return errors.Wrap(err, "router/handlers:fnGetJwtCerts() Could not retrieve jwt certificate")
+		return errors.Wrap(err, "router/handlers:fnGetJwtCerts() Could not retrieve jwt certificates")
 	}
-	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return errors.Wrap(err, "router/handlers:fnGetJwtCerts() Error while reading response body")
-	}
-
-	err = crypt.SavePemCertWithShortSha1FileName(body, constants.TrustedJWTSigningCertsDir)
-	if err != nil {
-		return errors.Wrap(err, "router/handlers:fnGetJwtCerts() Error while saving certificate")
+	for _, jwtCert := range jwtCerts {
+		err = crypt.SavePemCertWithShortSha1FileName(jwtCert, constants.TrustedJWTSigningCertsDir)
+		if err != nil {
+			return errors.Wrap(err, "router/handlers:fnGetJwtCerts() Error while saving certificate")
+		}
 	}
 
 	return nil
 }
 
+// newTokenRevocationsFetcher returns the function fetching the tokens revoked in AAS with the API token of the trust
+// agent
+func newTokenRevocationsFetcher() jwtauth.RetrieveRevocationsFn {
+	var fetchTokenRevocations func(since time.Time) (*jwtauth.TokenRevocations, error)
+	return func(since time.Time) (*jwtauth.TokenRevocations, error) {
+		log.Trace("router/handlers:fetchTokenRevocations() Entering")
+		defer log.Trace("router/handlers:fetchTokenRevocations() Leaving")
+
+		if fetchTokenRevocations == nil {
+			cfg, err := config.LoadConfiguration()
+			if err != nil {
+				return nil, errors.Wrap(err, "router/handlers:fetchTokenRevocations() Error loading configuration")
+			}
+			caCerts, err := crypt.GetCertsFromDir(constants.TrustedCaCertsDir)
+			if err != nil {
+				return nil, errors.Wrapf(err, "router/handlers:fetchTokenRevocations() Error while getting certs from %s", constants.TrustedCaCertsDir)
+			}
+			hc, err := clients.HTTPClientWithCA(caCerts)
+			if err != nil {
+				return nil, errors.Wrap(err, "router/handlers:fetchTokenRevocations() Error setting up HTTP client")
+			}
+			aasClient := &aas.Client{BaseURL: cfg.Aas.BaseURL, HTTPClient: hc}
+			// the API token is reloaded when AAS rejects it, after it is downloaded again
+			fetchTokenRevocations = aas.NewTokenRevocationsFetcher(aasClient, func() ([]byte, error) {
+				cfg, err := config.LoadConfiguration()
+				if err != nil {
+					return nil, err
+				}
+				return []byte(cfg.ApiToken), nil
+			})
+		}
+		revocations, err := fetchTokenRevocations(since)
+		if err != nil {
+			return nil, errors.Wrap(err, "router/handlers:fetchTokenRevocations() Error retrieving token revocations from AAS")
+		}
+		return revocations, nil
+	}
+}
+
 // RequiresPermission checks the JWT in the request for the required access permissions
 func RequiresPermission(eh middleware.EndpointHandler, permissionNames []string) middleware.EndpointHandler {
 	log.Trace("router/handlers:requiresPermission() Entering")
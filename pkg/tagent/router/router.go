@@ -14,6 +14,7 @@ import (
 	"github.com/intel-secl/intel-secl/v5/pkg/tagent/constants"
 
 	"github.com/gorilla/mux"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
 )
@@ -36,6 +37,7 @@ var (
 )
 
 var cacheTime, _ = time.ParseDuration(constants.JWTCertsCacheTime)
+var revocationsRefreshTime, _ = time.ParseDuration(constants.TokenRevocationsRefreshTime)
 var seclog = commLog.GetSecurityLogger()
 
 func InitRoutes(trustedJWTSigningCertsDir, trustedCaCertsDir string, requestHandler common.RequestHandler) *mux.Router {
@@ -56,7 +58,9 @@ func defineSubRoutes(router *mux.Router, trustedJWTSigningCertsDir, trustedCaCer
 	subRouter = setVersionRoutes(subRouter)
 
 	subRouter = router.PathPrefix(serviceApi).Subrouter()
-	subRouter.Use(middleware.NewTokenAuth(trustedJWTSigningCertsDir, trustedCaCertsDir, fnGetJwtCerts, cacheTime))
+	revocationList := jwtauth.NewRevocationList(newTokenRevocationsFetcher(), revocationsRefreshTime)
+	subRouter.Use(middleware.NewTokenAuthWithRevocationList(trustedJWTSigningCertsDir, trustedCaCertsDir, fnGetJwtCerts,
+		cacheTime, revocationList))
 	subRouter.HandleFunc("/aik", ErrorHandler(RequiresPermission(controllers.GetAik(requestHandler), []string{getAIKPerm}))).Methods(http.MethodGet)
 	subRouter.HandleFunc("/host", ErrorHandler(RequiresPermission(controllers.GetPlatformInfo(requestHandler, constants.PlatformInfoFilePath), []string{getHostInfoPerm}))).Methods(http.MethodGet)
 	subRouter.HandleFunc("/tpm/quote", ErrorHandler(RequiresPermission(controllers.GetTpmQuote(requestHandler), []string{postQuotePerm}))).Methods(http.MethodPost)
@@ -6,6 +6,7 @@ package tagent
 
 import (
 	"fmt"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/certrenewer"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/utils"
 	"github.com/intel-secl/intel-secl/v5/pkg/tagent/common"
 	"github.com/intel-secl/intel-secl/v5/pkg/tagent/constants"
@@ -14,6 +15,7 @@ import (
 	"os"
 	"os/signal"
 	"os/user"
+	"strings"
 	"syscall"
 )
 
@@ -56,6 +58,28 @@ func (a *App) startServer() error {
 		RequestHandler: common.NewRequestHandler(c),
 	}
 
+	// the API token is renewed before it expires since it is the credential of the trust agent to renew its TLS
+	// certificate
+	tokenRenewer := &apiTokenRenewer{}
+	tokenRenewer.Start()
+	defer tokenRenewer.Stop()
+
+	// the TLS certificate is only served by the web service, it is renewed with the API token of the trust agent
+	if strings.ToLower(c.Mode) != constants.CommunicationModeOutbound {
+		certRenewer := certrenewer.NewRenewer(c.Cms.BaseURL, constants.TrustedCaCertsDir, tokenRenewer.Token)
+		tlsCertificate, err := certRenewer.Watch(certrenewer.Certificate{
+			CertFile: constants.TLSCertFilePath,
+			KeyFile:  constants.TLSKeyFilePath,
+			CertType: constants.TlsKey,
+		})
+		if err != nil {
+			return errors.Wrap(err, "Failed to load TLS certificate")
+		}
+		serviceParameters.Web.GetCertificate = tlsCertificate.GetCertificate
+		certRenewer.Start()
+		defer certRenewer.Stop()
+	}
+
 	trustAgentService, err := service.NewTrustAgentService(&serviceParameters)
 	if err != nil {
 		log.WithError(err).Info("Failed to create service")
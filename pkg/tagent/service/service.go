@@ -5,6 +5,8 @@
 package service
 
 import (
+	"crypto/tls"
+
 	commConfig "github.com/intel-secl/intel-secl/v5/pkg/lib/common/config"
 	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	"github.com/intel-secl/intel-secl/v5/pkg/tagent/common"
@@ -31,6 +33,9 @@ type WebParameters struct {
 	TLSKeyFilePath            string
 	TrustedJWTSigningCertsDir string
 	TrustedCaCertsDir         string
+	// GetCertificate provides the TLS certificate renewed by the certificate renewer, the TLS certificate and key
+	// files are used when it is not set
+	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
 }
 
 type ServiceParameters struct {
@@ -37,6 +37,12 @@ func (service *trustAgentWebService) Start() error {
 			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
 			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		GetCertificate: service.webParameters.GetCertificate,
+	}
+	tlsCertFilePath := service.webParameters.TLSCertFilePath
+	tlsKeyFilePath := service.webParameters.TLSKeyFilePath
+	if tlsconfig.GetCertificate != nil {
+		tlsCertFilePath, tlsKeyFilePath = "", ""
 	}
 
 	httpWriter := os.Stderr
@@ -68,7 +74,7 @@ func (service *trustAgentWebService) Start() error {
 
 	// dispatch web server go routine
 	go func() {
-		if err := service.server.ListenAndServeTLS(service.webParameters.TLSCertFilePath, service.webParameters.TLSKeyFilePath); err != nil {
+		if err := service.server.ListenAndServeTLS(tlsCertFilePath, tlsKeyFilePath); err != nil {
 			secLog.Errorf("tasks/service:Start() %s", message.TLSConnectFailed)
 			secLog.WithError(err).Fatalf("server:startServer() Failed to start HTTPS server: %s\n", err.Error())
 			log.Tracef("%+v", err)
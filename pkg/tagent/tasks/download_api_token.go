@@ -71,7 +71,21 @@ func (task *DownloadApiToken) Run() error {
 		Service: constants.VerificationServiceName,
 		Rules:   []string{"reports:create:*", "hosts:search:*"},
 	})
+	// the trust agent retrieves the tokens revoked in AAS with the API token, and renews the API token before it
+	// expires
+	perms = append(perms, types.PermissionInfo{
+		Service: constants.AasServiceName,
+		Rules:   []string{"token_revocations:retrieve:*", "custom_claims:renew:*"},
+	})
 	permission["permissions"] = perms
+	// the API token is also the bootstrap credential used to renew the TLS certificate of the trust agent
+	if task.Config.Tls.CommonName != "" {
+		permission["roles"] = []types.RoleInfo{{
+			Service: constants.CmsServiceName,
+			Name:    constants.CertApproverGroupName,
+			Context: "CN=" + task.Config.Tls.CommonName + ";SAN=" + task.Config.Tls.SANList + ";certType=" + constants.TlsKey,
+		}}
+	}
 
 	createCustomerClaimsReq := types.CustomClaims{
 		Subject:      hwuuid.String(),
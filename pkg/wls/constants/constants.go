@@ -50,6 +50,8 @@ var (
 // jwt constants
 const (
 	JWTCertsCacheTime = "1m"
+	// TokenRevocationsRefreshTime is how often the token revocation list is retrieved from AAS
+	TokenRevocationsRefreshTime = "1m"
 )
 
 const (
@@ -7,14 +7,16 @@ package router
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/intel-secl/intel-secl/v5/pkg/clients/aas"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
+	jwtauth "github.com/intel-secl/intel-secl/v5/pkg/lib/common/jwt"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
+	commMetrics "github.com/intel-secl/intel-secl/v5/pkg/lib/common/metrics"
 	cmw "github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
 	cos "github.com/intel-secl/intel-secl/v5/pkg/lib/common/os"
 	"github.com/intel-secl/intel-secl/v5/pkg/wls/config"
@@ -26,7 +28,8 @@ var defaultLog = log.GetDefaultLogger()
 var secLog = log.GetSecurityLogger()
 
 type Router struct {
-	cfg *config.Configuration
+	cfg                   *config.Configuration
+	fetchTokenRevocations func(since time.Time) (*jwtauth.TokenRevocations, error)
 }
 
 // InitRoutes registers all routes for the application.
@@ -38,6 +41,7 @@ func InitRoutes(cfg *config.Configuration, certStore *crypt.CertificatesStore) (
 	router := mux.NewRouter()
 
 	router.SkipClean(true)
+	router = commMetrics.SetMetricsRoutes(router, cfg.Server)
 	err := defineSubRoutes(router, strings.ToLower(constants.ServiceName), cfg, certStore)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not define sub routes")
@@ -59,10 +63,14 @@ func defineSubRoutes(router *mux.Router, service string, cfg *config.Configurati
 	if err != nil {
 		return errors.Wrap(err, "Could not parse JWT Certificate cache time")
 	}
+	revocationsRefreshTime, err := time.ParseDuration(constants.TokenRevocationsRefreshTime)
+	if err != nil {
+		return errors.Wrap(err, "Could not parse token revocations refresh time")
+	}
 	subRouter = router.PathPrefix(serviceApi).Subrouter()
-	subRouter.Use(cmw.NewTokenAuth(constants.TrustedJWTSigningCertsDir,
+	subRouter.Use(cmw.NewTokenAuthWithRevocationList(constants.TrustedJWTSigningCertsDir,
 		constants.TrustedCaCertsDir, cfgRouter.fnGetJwtCerts,
-		cacheTime))
+		cacheTime, jwtauth.NewRevocationList(cfgRouter.fnGetTokenRevocations, revocationsRefreshTime)))
 	subRouter = SetKeyRoutes(subRouter, cfg, certStore)
 	return nil
 }
@@ -77,28 +85,43 @@ func (r *Router) fnGetJwtCerts() error {
 	if !strings.HasSuffix(cfg.AASApiUrl, "/") {
 		cfg.AASApiUrl = cfg.AASApiUrl + "/"
 	}
-	url := cfg.AASApiUrl + "jwt-certificates"
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	httpClient, err := r.aasHttpClient()
+	if err != nil {
+		return err
+	}
+
+	aasClient := aas.Client{BaseURL: cfg.AASApiUrl, HTTPClient: httpClient}
+	jwtCerts, err := aasClient.GetJwtSigningCertificates()
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not create http request")
+		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not retrieve jwt certificates")
 	}
-	req.Header.Add("accept", "application/x-pem-file")
+	for _, jwtCert := range jwtCerts {
+		err = crypt.SavePemCertWithShortSha1FileName(jwtCert, constants.TrustedJWTSigningCertsDir)
+		if err != nil {
+			return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+		}
+	}
+	return nil
+}
+
+// aasHttpClient creates the HTTP client of the requests to AAS
+func (r *Router) aasHttpClient() (*http.Client, error) {
 	rootCaCertPems, err := cos.GetDirFileContents(constants.TrustedCaCertsDir, "*.pem")
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not read root CA certificate")
+		return nil, errors.Wrap(err, "router/router:aasHttpClient() Could not read root CA certificate")
 	}
 
 	// Get the SystemCertPool, continue with an empty pool on error
 	rootCAs, err := x509.SystemCertPool()
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Failed defining certificate pool")
+		return nil, errors.Wrap(err, "router/router:aasHttpClient() Failed defining certificate pool")
 	}
 	if rootCAs == nil {
 		rootCAs = x509.NewCertPool()
 	}
 	for _, rootCACert := range rootCaCertPems {
 		if ok := rootCAs.AppendCertsFromPEM(rootCACert); !ok {
-			return err
+			return nil, err
 		}
 	}
 	httpClient := &http.Client{
@@ -110,25 +133,26 @@ func (r *Router) fnGetJwtCerts() error {
 			},
 		},
 	}
+	return httpClient, nil
+}
 
-	res, err := httpClient.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not retrieve jwt certificate")
-	}
-	defer func() {
-		derr := res.Body.Close()
-		if derr != nil {
-			defaultLog.WithError(derr).Error("Error closing response body")
-		}
-	}()
+// Fetch the tokens revoked in AAS with the token of the WLS service user
+func (r *Router) fnGetTokenRevocations(since time.Time) (*jwtauth.TokenRevocations, error) {
+	defaultLog.Trace("router/router:fnGetTokenRevocations() Entering")
+	defer defaultLog.Trace("router/router:fnGetTokenRevocations() Leaving")
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Failed read response")
+	if r.fetchTokenRevocations == nil {
+		httpClient, err := r.aasHttpClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "router/router:fnGetTokenRevocations() Could not create AAS client")
+		}
+		aasClient := &aas.Client{BaseURL: r.cfg.AASApiUrl, HTTPClient: httpClient}
+		r.fetchTokenRevocations = aas.NewTokenRevocationsFetcher(aasClient, aas.NewServiceUserTokenFetcher(r.cfg.AASApiUrl,
+			httpClient, r.cfg.WLS.Username, r.cfg.WLS.Password))
 	}
-	err = crypt.SavePemCertWithShortSha1FileName(body, constants.TrustedJWTSigningCertsDir)
+	revocations, err := r.fetchTokenRevocations(since)
 	if err != nil {
-		return errors.Wrap(err, "router/router:fnGetJwtCerts() Could not store Certificate")
+		return nil, errors.Wrap(err, "router/router:fnGetTokenRevocations() Error retrieving token revocations from AAS")
 	}
-	return nil
+	return revocations, nil
 }
@@ -9,10 +9,13 @@ import (
 	"crypto/tls"
 	"fmt"
 	"github.com/gorilla/handlers"
+	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/certrenewer"
+	commConstants "github.com/intel-secl/intel-secl/v5/pkg/lib/common/constants"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/crypt"
 	commLog "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log"
 	commLogMsg "github.com/intel-secl/intel-secl/v5/pkg/lib/common/log/message"
 	"github.com/intel-secl/intel-secl/v5/pkg/lib/common/middleware"
+	"github.com/intel-secl/intel-secl/v5/pkg/wls/config"
 	"github.com/intel-secl/intel-secl/v5/pkg/wls/constants"
 	wlsModel "github.com/intel-secl/intel-secl/v5/pkg/wls/domain/model"
 	"github.com/intel-secl/intel-secl/v5/pkg/wls/router"
@@ -61,6 +64,11 @@ func (a *App) startServer() error {
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
 			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
 	}
+	certRenewer, err := startCertRenewer(c, tlsConfig)
+	if err != nil {
+		return errors.Wrap(err, "An error occurred while starting certificate renewer")
+	}
+	defer certRenewer.Stop()
 	// Setup signal handlers to gracefully handle termination
 	stop := make(chan os.Signal)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -77,11 +85,9 @@ func (a *App) startServer() error {
 		MaxHeaderBytes:    c.Server.MaxHeaderBytes,
 	}
 
-	tlsCert := c.TLS.CertFile
-	tlsKey := c.TLS.KeyFile
-	// dispatch web server go routine
+	// dispatch web server go routine, the TLS certificate is provided by the certificate renewer
 	go func() {
-		if err := h.ListenAndServeTLS(tlsCert, tlsKey); err != nil {
+		if err := h.ListenAndServeTLS("", ""); err != nil {
 			defaultLog.WithError(err).Info("Failed to start HTTPS server")
 			stop <- syscall.SIGTERM
 		}
@@ -102,6 +108,26 @@ func (a *App) startServer() error {
 	return nil
 }
 
+// startCertRenewer starts the renewal of the TLS certificate issued by CMS with the credential of the WLS service user
+func startCertRenewer(c *config.Configuration, tlsConfig *tls.Config) (*certrenewer.Renewer, error) {
+	defaultLog.Trace("app:startCertRenewer() Entering")
+	defer defaultLog.Trace("app:startCertRenewer() Leaving")
+
+	renewer := certrenewer.NewRenewer(c.CMSBaseURL, constants.TrustedCaCertsDir,
+		certrenewer.NewAasTokenProvider(c.AASApiUrl, c.WLS.Username, c.WLS.Password, constants.TrustedCaCertsDir))
+	tlsCertificate, err := renewer.Watch(certrenewer.Certificate{
+		CertFile: c.TLS.CertFile,
+		KeyFile:  c.TLS.KeyFile,
+		CertType: commConstants.CertTypeTls,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.GetCertificate = tlsCertificate.GetCertificate
+	renewer.Start()
+	return renewer, nil
+}
+
 func (a *App) loadCertPathStore() *crypt.CertificatesPathStore {
 	return &crypt.CertificatesPathStore{
 		wlsModel.CaCertTypesRootCa.String(): crypt.CertLocation{
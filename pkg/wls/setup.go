@@ -153,6 +153,7 @@ func (a *App) setupTaskRunner() (*setup.Runner, error) {
 			WriteTimeout:      viper.GetDuration(commConfig.ServerWriteTimeout),
 			IdleTimeout:       viper.GetDuration(commConfig.ServerIdleTimeout),
 			MaxHeaderBytes:    viper.GetInt(commConfig.ServerMaxHeaderBytes),
+			MetricsEnabled:    viper.GetBool(commConfig.ServerMetricsEnabled),
 		},
 		DefaultPort:   constants.DefaultWLSListenerPort,
 		AppConfig:     &a.Config,
@@ -41,6 +41,7 @@ var optionalEnvHelp = map[string]string{
 	"SERVER_WRITE_TIMEOUT":       "Request Write Timeout Duration in Seconds",
 	"SERVER_IDLE_TIMEOUT":        "Request Idle Timeout in Seconds",
 	"SERVER_MAX_HEADER_BYTES":    "Max Length Of Request Header in Bytes ",
+	"SERVER_METRICS_ENABLED":     "Expose the /metrics endpoint in Prometheus text format when set to true",
 }
 
 var requiredEnvHelp = map[string]string{
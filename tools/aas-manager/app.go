@@ -33,6 +33,7 @@ const (
 	HELP_GENPASSWORD                  = "Generate passwords if not specified"
 	HELP_REGEN_TOKEN_ONLY             = "Generate token only"
 	HELP_GEN_CUSTOM_CLAIMS_TOKEN_ONLY = "Generate custom claims token only"
+	HELP_GRANT_RENEWAL_ROLES_ONLY     = "Grant the roles renewing the service certificates to the existing users only and mark the service users as service accounts, used on upgrade"
 	HELP_HELP                         = "Show Usage - if specified, all other options will be ignored"
 
 	PASSWORD_SIZE = 20
@@ -105,11 +106,14 @@ type App struct {
 	SKCLibRoleContext       string
 	ApsServiceUserName      string
 	ApsServiceUserPassword  string
+	CmsServiceUserName      string
+	CmsServiceUserPassword  string
 
 	Components                    map[string]bool
 	GenPassword                   bool
 	RegenTokenOnly                bool
 	GenerateCustomClaimsTokenOnly bool
+	GrantRenewalRolesOnly         bool
 	CustomClaimsComponents        map[string]bool
 	CustomClaimsTokenValiditySecs string
 	CredentialCreatorRoleContext  string
@@ -185,19 +189,27 @@ func (a *App) GetServiceUsers() []UserAndRolesCreate {
 			urc.Name = a.HvsServiceUserName
 			urc.Password = a.HvsServiceUserPassword
 			urc.Roles = append(urc.Roles, NewRole("TA", "Administrator", "", []string{"*:*:*"}))
+			// the service users renew the certificates of the services
+			urc.Roles = append(urc.Roles, MakeTlsCertificateRole(a.HvsCN, a.HvsSanList))
+			urc.Roles = append(urc.Roles, NewRole("CMS", "CertApprover", "CN=HVS Flavor Signing Certificate;certType=Signing", nil))
+			urc.Roles = append(urc.Roles, NewRole("CMS", "CertApprover", "CN=HVS SAML Certificate;certType=Signing", nil))
+			urc.Roles = append(urc.Roles, NewRole("AAS", "TokenRevocationReader", "", []string{"token_revocations:retrieve:*"}))
 		case "IHUB":
 			urc.Name = a.IhubServiceUserName
 			urc.Password = a.IhubServiceUserPassword
+			urc.Roles = append(urc.Roles, MakeTlsCertificateRole(a.IhubCN, a.IhubSanList))
 			urc.Roles = append(urc.Roles, NewRole("HVS", "ReportSearcher", "", []string{"reports:search:*"}))
 			urc.Roles = append(urc.Roles, NewRole("FDS", "HostSearcher", "", []string{"hosts:search:*"}))
 		case "WPM":
 			urc.Name = a.WpmServiceUserName
 			urc.Password = a.WpmServiceUserPassword
-			urc.Roles = append(urc.Roles, NewRole("KBS", "KeyManager", "", []string{"keys:create:*", "keys:transfer:*"}))
+			urc.Roles = append(urc.Roles, NewRole("KBS", "KeyManager", "", []string{"keys:create:*", "keys:transfer:*", "keys:rotate:*"}))
 		case "WLS":
 			urc.Name = a.WlsServiceUserName
 			urc.Password = a.WlsServiceUserPassword
 			urc.Roles = append(urc.Roles, NewRole("HVS", "ReportCreator", "", []string{"reports:create:*"}))
+			urc.Roles = append(urc.Roles, MakeTlsCertificateRole(a.WlsCN, a.WlsSanList))
+			urc.Roles = append(urc.Roles, NewRole("AAS", "TokenRevocationReader", "", []string{"token_revocations:retrieve:*"}))
 		case "WLA":
 			urc.Name = a.WlaServiceUserName
 			urc.Password = a.WlaServiceUserPassword
@@ -218,9 +230,21 @@ func (a *App) GetServiceUsers() []UserAndRolesCreate {
 			urc.Password = a.KbsServiceUserPassword
 			urc.Roles = append(urc.Roles, NewRole("APS", "TokenCreator", "", []string{"attestation_token:create:*"}))
 			urc.Roles = append(urc.Roles, NewRole("AAS", "UserReader", "", []string{"users:search:*", "user_roles:search:*"}))
+			urc.Roles = append(urc.Roles, NewRole("AAS", "TokenRevocationReader", "", []string{"token_revocations:retrieve:*"}))
+			urc.Roles = append(urc.Roles, MakeTlsCertificateRole(a.KbsCN, a.KbsSanList))
+		case "CMS":
+			// the CMS service user is optional, without it CMS accepts the revoked tokens until they expire and cannot
+			// check the roles of the ACME accounts when the orders are finalized
+			urc.Name = a.CmsServiceUserName
+			urc.Password = a.CmsServiceUserPassword
+			urc.Roles = append(urc.Roles, NewRole("AAS", "UserReader", "", []string{"users:search:*", "user_roles:search:*"}))
+			urc.Roles = append(urc.Roles, NewRole("AAS", "TokenRevocationReader", "", []string{"token_revocations:retrieve:*"}))
 		}
 
 		if urc.Name != "" {
+			// the services authenticate with the password of their configuration, which is not rotated
+			serviceAccount := true
+			urc.ServiceAccount = &serviceAccount
 			urs = append(urs, urc)
 		}
 
@@ -229,6 +253,24 @@ func (a *App) GetServiceUsers() []UserAndRolesCreate {
 
 }
 
+// GetRenewalRoles keeps the CertApprover roles of the users, which the services need to renew their certificates, and
+// the service users to be marked as service accounts
+func GetRenewalRoles(usersAndRoles []UserAndRolesCreate) []UserAndRolesCreate {
+	renewalUsersAndRoles := []UserAndRolesCreate{}
+	for _, urc := range usersAndRoles {
+		renewalUrc := UserAndRolesCreate{UserCreate: aas.UserCreate{Name: urc.Name, ServiceAccount: urc.ServiceAccount}}
+		for _, role := range urc.Roles {
+			if role.Service == "CMS" && role.Name == "CertApprover" {
+				renewalUrc.Roles = append(renewalUrc.Roles, role)
+			}
+		}
+		if len(renewalUrc.Roles) != 0 || renewalUrc.ServiceAccount != nil {
+			renewalUsersAndRoles = append(renewalUsersAndRoles, renewalUrc)
+		}
+	}
+	return renewalUsersAndRoles
+}
+
 func (a *App) GetCCCAdminUser() *UserAndRolesCreate {
 
 	if a.CCCAdminUsername == "" {
@@ -269,6 +311,7 @@ func (a *App) GetGlobalAdminUser() *UserAndRolesCreate {
 			urc.Roles = append(urc.Roles, NewRole("WLS", "Administrator", "", []string{"*:*:*"}))
 		case "AAS":
 			urc.Roles = append(urc.Roles, NewRole("AAS", "Administrator", "", []string{"*:*:*"}))
+			urc.Roles = append(urc.Roles, NewRole("CMS", "CertRevoker", "", nil))
 		case "APS":
 			urc.Roles = append(urc.Roles, NewRole("APS", "Administrator", "", []string{"*:*:*"}))
 		case "FDS":
@@ -496,6 +539,9 @@ func (a *App) LoadAllVariables(envFile string) error {
 
 		{&a.ApsServiceUserName, "APS_SERVICE_USERNAME", "", "Attestation Policy Service User Name", false, false},
 		{&a.ApsServiceUserPassword, "APS_SERVICE_PASSWORD", "", "Attestation Policy Service User Password", false, true},
+
+		{&a.CmsServiceUserName, "CMS_SERVICE_USERNAME", "", "Certificate Management Service User Name", false, false},
+		{&a.CmsServiceUserPassword, "CMS_SERVICE_PASSWORD", "", "Certificate Management Service User Password", false, true},
 	}
 
 	hasError := false
@@ -563,7 +609,7 @@ func (a *App) LoadUserAndRolesJson(file string) (*AasUsersAndRolesSetup, error)
 	return &urc, nil
 }
 
-func (a *App) GetNewOrExistingUserID(name, password string, forceUpdatePassword bool, aascl *claas.Client) (string, error) {
+func (a *App) GetNewOrExistingUserID(name, password string, serviceAccount *bool, forceUpdatePassword bool, aascl *claas.Client) (string, error) {
 
 	users, err := aascl.GetUsers(name)
 	if err != nil {
@@ -577,7 +623,7 @@ func (a *App) GetNewOrExistingUserID(name, password string, forceUpdatePassword
 		if password == "" {
 			return "", fmt.Errorf("Password not supplied and no flag to generate password. Use --genpassword flag to generate password")
 		}
-		newUser, err := aascl.CreateUser(aas.UserCreate{Name: name, Password: password})
+		newUser, err := aascl.CreateUser(aas.UserCreate{Name: name, Password: password, ServiceAccount: serviceAccount})
 		if err != nil {
 			return "", err
 		}
@@ -592,12 +638,27 @@ func (a *App) GetNewOrExistingUserID(name, password string, forceUpdatePassword
 				return "", fmt.Errorf("Could not update the user : %s's password", name)
 			}
 		}
+		if err := a.MarkServiceAccount(users[0], serviceAccount, aascl); err != nil {
+			return "", err
+		}
 		return users[0].ID, nil
 	}
 	// we should not really be here.. we have multiple users with matched name
 	return "", fmt.Errorf("Multiple records found when searching for user %s - record - %v", name, users)
 }
 
+// MarkServiceAccount marks the service users created before the service accounts were exempt from the password
+// expiry and the account lockout
+func (a *App) MarkServiceAccount(user aas.UserCreateResponse, serviceAccount *bool, aascl *claas.Client) error {
+	if serviceAccount == nil || *serviceAccount == user.ServiceAccount {
+		return nil
+	}
+	if err := aascl.UpdateUser(user.ID, aas.UserCreate{ServiceAccount: serviceAccount}); err != nil {
+		return fmt.Errorf("Could not mark the user : %s as service account", user.Name)
+	}
+	return nil
+}
+
 func (a *App) GetNewOrExistingRoleID(role aas.RoleCreate, aascl *claas.Client) (string, error) {
 	roles, err := aascl.GetRoles(role.Service, role.Name, role.Context, "", false)
 	if err != nil {
@@ -713,18 +774,36 @@ func (a *App) AddUsersAndRoles(asr *AasUsersAndRolesSetup) error {
 			continue
 		}
 
-		forcePasswordUpdate := false
-		if asr.UsersAndRoles[idx].Password == "" && (a.GenPassword || asr.UsersAndRoles[idx].PrintBearerToken) {
-			asr.UsersAndRoles[idx].Password = RandomString(PASSWORD_SIZE)
-			forcePasswordUpdate = true
-		}
-		if userid, err = a.GetNewOrExistingUserID(asr.UsersAndRoles[idx].Name, asr.UsersAndRoles[idx].Password, forcePasswordUpdate, aascl); err == nil {
+		if a.GrantRenewalRolesOnly {
+			// the roles are only granted to the users created by a previous setup
+			users, err := aascl.GetUsers(asr.UsersAndRoles[idx].Name)
+			if err != nil {
+				return fmt.Errorf("Error while attempting to retrieve user %s - error %v ", asr.UsersAndRoles[idx].Name, err)
+			}
+			if len(users) != 1 || users[0].Name != asr.UsersAndRoles[idx].Name {
+				fmt.Println("\nuser:", asr.UsersAndRoles[idx].Name, "not found, skipping")
+				continue
+			}
+			if err = a.MarkServiceAccount(users[0], asr.UsersAndRoles[idx].ServiceAccount, aascl); err != nil {
+				return err
+			}
+			userid = users[0].ID
 			fmt.Println("\nuser:", asr.UsersAndRoles[idx].Name, "userid:", userid)
 		} else {
-			return fmt.Errorf("Error while attempting to create/ retrieve user %s - error %v ", asr.UsersAndRoles[idx].Name, err)
+			forcePasswordUpdate := false
+			if asr.UsersAndRoles[idx].Password == "" && (a.GenPassword || asr.UsersAndRoles[idx].PrintBearerToken) {
+				asr.UsersAndRoles[idx].Password = RandomString(PASSWORD_SIZE)
+				forcePasswordUpdate = true
+			}
+			if userid, err = a.GetNewOrExistingUserID(asr.UsersAndRoles[idx].Name, asr.UsersAndRoles[idx].Password,
+				asr.UsersAndRoles[idx].ServiceAccount, forcePasswordUpdate, aascl); err == nil {
+				fmt.Println("\nuser:", asr.UsersAndRoles[idx].Name, "userid:", userid)
+			} else {
+				return fmt.Errorf("Error while attempting to create/ retrieve user %s - error %v ", asr.UsersAndRoles[idx].Name, err)
 
+			}
 		}
-		if a.RegenTokenOnly {
+		if a.RegenTokenOnly || len(asr.UsersAndRoles[idx].Roles) == 0 {
 			continue
 		}
 		// we might have the same role appear more than one in the list of roles to be added for a user
@@ -773,6 +852,7 @@ func (a *App) Setup(args []string) error {
 	fs.BoolVar(&a.GenPassword, "genpassword", false, HELP_GENPASSWORD)
 	fs.BoolVar(&a.RegenTokenOnly, "regen_token_only", false, HELP_REGEN_TOKEN_ONLY)
 	fs.BoolVar(&a.GenerateCustomClaimsTokenOnly, "gen_custom_claims_token_only", false, HELP_GEN_CUSTOM_CLAIMS_TOKEN_ONLY)
+	fs.BoolVar(&a.GrantRenewalRolesOnly, "grant_renewal_roles_only", false, HELP_GRANT_RENEWAL_ROLES_ONLY)
 	fs.BoolVar(&printHelp, "help", false, HELP_HELP)
 
 	err = fs.Parse(args[1:])
@@ -782,7 +862,7 @@ func (a *App) Setup(args []string) error {
 	}
 
 	if printHelp || (len(args) == 2 && args[1] == "help") {
-		fmt.Println("Usage:\n\n ", args[0], "[--answerfile] [--nosetup] [--genpassword] [--use_json] [--in_json_file] [--output_json] [--out_json_file] [--gen_custom_claims_token_only] [--help]")
+		fmt.Println("Usage:\n\n ", args[0], "[--answerfile] [--nosetup] [--genpassword] [--use_json] [--in_json_file] [--output_json] [--out_json_file] [--gen_custom_claims_token_only] [--grant_renewal_roles_only] [--help]")
 
 		fs.PrintDefaults()
 		return nil
@@ -821,6 +901,11 @@ func (a *App) Setup(args []string) error {
 		}
 	}
 
+	if a.GrantRenewalRolesOnly {
+		// the services installed before the certificate renewal was added lack the roles renewing their certificates
+		as.UsersAndRoles = GetRenewalRoles(as.UsersAndRoles)
+	}
+
 	if noSetup {
 		setup = false
 	}
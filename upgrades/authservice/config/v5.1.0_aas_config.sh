@@ -0,0 +1,15 @@
+#!/bin/bash
+
+SERVICE_NAME=authservice
+COMPONENT_NAME=authservice
+echo "Starting $COMPONENT_NAME config upgrade to v5.1.0"
+
+# the admin setup is run again with the admin user of the configuration to mark it as service account, exempt from
+# the password expiry and the account lockout
+echo "Marking AAS admin user as service account"
+./$SERVICE_NAME setup admin
+if [ $? -ne 0 ]; then
+  echo "Failed to mark AAS admin user as service account, run the admin setup again before enabling the password expiry"
+fi
+
+echo "Completed $COMPONENT_NAME config upgrade to v5.1.0"
@@ -0,0 +1,14 @@
+#!/bin/bash
+
+SERVICE_NAME=tagent
+COMPONENT_NAME=trustagent
+echo "Starting $COMPONENT_NAME config upgrade to v5.1.0"
+
+# the API token is downloaded again to hold the CertApprover role renewing the TLS certificate of the trust agent
+echo "Downloading API token from AAS"
+./$SERVICE_NAME setup download-api-token
+if [ $? -ne 0 ]; then
+  echo "Failed to download API token, the TLS certificate will not be renewed until download-api-token is run again"
+fi
+
+echo "Completed $COMPONENT_NAME config upgrade to v5.1.0"